@@ -16,7 +16,9 @@
 package backend
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
 	"hash"
 
 	"github.com/consensys/gnark/constraint/solver"
@@ -60,6 +62,15 @@ type ProverConfig struct {
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
 	Accelerator    string
+	ProofCache     ProofCache
+	CheckpointDir  string
+	KeepCheckpoint bool
+	Context        context.Context
+
+	BatchParallelism int
+
+	UnsafeNoBlinding     bool
+	SideChannelHardening bool
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -70,6 +81,7 @@ func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 		// separation tags for PLONK and Groth16
 		ChallengeHash:  sha256.New(),
 		KZGFoldingHash: sha256.New(),
+		Context:        context.Background(),
 	}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
@@ -133,6 +145,65 @@ func WithIcicleAcceleration() ProverOption {
 	}
 }
 
+// WithUnsafeNoBlinding disables the blinding of the polynomials in the PLONK
+// prover. The proofs are still sound and succinct and are verified as usual,
+// but they are NOT zero-knowledge: they leak information about the secret
+// inputs. It is meant for validity proofs where the witness is not secret, for
+// example rollups publishing their state transitions. The proofs are then
+// deterministic. The option is ignored by the Groth16 prover.
+//
+// The degrees of the blinding polynomials aren't configurable, they are part
+// of the proof format expected by the verifiers. The SRS size required by the
+// prover, see plonk.SRSSize, is hence the same with or without blinding.
+func WithUnsafeNoBlinding() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.UnsafeNoBlinding = true
+		return nil
+	}
+}
+
+// WithContext makes the prover stop when ctx is done, in which case Prove
+// returns the error of ctx. The constraint system is solved with
+// [solver.WithContext], and the context is checked between the stages of the
+// prover: a multi-scalar multiplication or an FFT which is started is not
+// interrupted, but the prover returns without waiting for it. The option is
+// ignored by the GPU accelerated provers.
+func WithContext(ctx context.Context) ProverOption {
+	return func(opt *ProverConfig) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		opt.Context = ctx
+		return nil
+	}
+}
+
+// WithSideChannelHardening reduces the leakage of the witness through the
+// timing and the memory accesses of the prover:
+//   - the constraint system is solved with [solver.WithConstantTime];
+//   - the scalars sᵢ of the multi-scalar multiplications of the witness are
+//     split as (sᵢ-ρᵢ) + ρᵢ for random ρᵢ, and the two multiplications are
+//     added, so that the buckets accessed by each multiplication are
+//     independent of the witness.
+//
+// The splitting only protects against first-order leakage, that is an
+// attacker observing the accesses of one of the two multiplications, or a
+// noisy mix of both. The bucket selection is not constant time: an attacker
+// recovering the bucket accesses of both multiplications, for example through
+// the cache of a machine shared with the prover, recovers sᵢ-ρᵢ and ρᵢ and so
+// the witness by adding them. On such machines, the option raises the cost of
+// an attack but doesn't prevent it.
+//
+// The hints and the openings of the PLONK polynomials, computed by
+// gnark-crypto, are not hardened. The proofs are verified as usual, and
+// proving takes about twice as long.
+func WithSideChannelHardening() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.SideChannelHardening = true
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -0,0 +1,122 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+	"sync"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// WithBatchParallelism sets the maximum number of proofs computed concurrently
+// by the ProveBatch functions of the backends. Each proof is already computed
+// in parallel, so that proving concurrently mostly hides the sequential parts
+// of the prover, at the cost of the memory of n provers. If not set, the proofs
+// are computed one after the other. It can't be used with a checkpoint
+// directory, which holds the checkpoint of a single proof.
+func WithBatchParallelism(n int) ProverOption {
+	return func(pc *ProverConfig) error {
+		if n <= 0 {
+			return errors.New("batch parallelism must be positive")
+		}
+		pc.BatchParallelism = n
+		return nil
+	}
+}
+
+// BatchError is the error returned by the ProveBatch functions of the backends
+// when some of the witnesses are not proved. Errors[i] is the error of the i-th
+// witness, or nil if it is proved. The errors of the witnesses can be matched
+// with errors.Is and errors.As.
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	nbFailed, first := 0, -1
+	for i := range e.Errors {
+		if e.Errors[i] != nil {
+			if first == -1 {
+				first = i
+			}
+			nbFailed++
+		}
+	}
+	if first == -1 {
+		return "no failed proof"
+	}
+	return fmt.Sprintf("%d of %d proofs failed: %v", nbFailed, len(e.Errors), e.Errors[first])
+}
+
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// ProveBatch proves the witnesses with prove, which is called with at most
+// the number of concurrent calls set by [WithBatchParallelism]. It returns the
+// proofs in the order of the witnesses. If some witnesses are not proved, it
+// returns the other proofs, with a [*BatchError].
+//
+// It implements the ProveBatch functions of the backends, which share the
+// proving key and the constraint system between the proofs.
+func ProveBatch[P any](witnesses []witness.Witness, prove func(fullWitness witness.Witness) (P, error), opts ...ProverOption) ([]P, error) {
+	cfg, err := NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	n := max(cfg.BatchParallelism, 1)
+	if n > 1 && cfg.CheckpointDir != "" {
+		// the checkpoint directory holds the checkpoint of a single proof
+		return nil, errors.New("batch parallelism is not supported with a checkpoint directory")
+	}
+	if n > 1 {
+		// the hash functions set by options are shared by the proofs, and
+		// can't be used concurrently.
+		other, err := NewProverConfig(opts...)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.HashToFieldFn != nil || sameHash(cfg.ChallengeHash, other.ChallengeHash) || sameHash(cfg.KZGFoldingHash, other.KZGFoldingHash) {
+			return nil, errors.New("batch parallelism is not supported with custom hash functions")
+		}
+	}
+
+	proofs := make([]P, len(witnesses))
+	errs := make([]error, len(witnesses))
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < min(n, len(witnesses)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				proof, err := prove(witnesses[i])
+				if err != nil {
+					errs[i] = fmt.Errorf("witness %d: %w", i, err)
+					continue
+				}
+				proofs[i] = proof
+			}
+		}()
+	}
+	for i := range witnesses {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i := range errs {
+		if errs[i] != nil {
+			return proofs, &BatchError{Errors: errs}
+		}
+	}
+	return proofs, nil
+}
+
+// sameHash returns true if a and b are the same hash instance.
+func sameHash(a, b hash.Hash) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Pointer && vb.Kind() == reflect.Pointer && va.Pointer() == vb.Pointer()
+}
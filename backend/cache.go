@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"reflect"
+	"sync"
+
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// ProofCache stores serialized proofs, see WithProofCache. Implementations
+// must be safe for concurrent use.
+type ProofCache interface {
+	// Get returns the proof stored under the key, if any.
+	Get(key []byte) (proof []byte, ok bool)
+	// Put stores the proof under the key.
+	Put(key, proof []byte)
+}
+
+// WithProofCache sets a cache of proofs: Prove returns the proof stored in the
+// cache when proving the same witness with the same key again (for example on
+// retries of an idempotent request), and stores the computed proofs otherwise.
+//
+// The cache key is computed from the proof system, a fingerprint of the
+// proving key, the full witness and the prover options changing the proof
+// (WithUnsafeNoBlinding and WithSideChannelHardening).
+// Prove returns an error if the cache is used with custom hash functions,
+// which can't be fingerprinted. Returning the same proof twice for the same
+// witness is only acceptable if linking the requests is.
+func WithProofCache(cache ProofCache) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.ProofCache = cache
+		return nil
+	}
+}
+
+// ProofCacheKey returns the cache key of the proof of the full witness with
+// the given proof system, key and prover configuration. The key is only
+// serialized to compute its fingerprint: for PLONK, the verifying key is used
+// as it commits to the circuit, and for Groth16 the hash of the proving key,
+// computed once per key (see groth16.ProvingKey.Fingerprint).
+func ProofCacheKey(id ID, cfg *ProverConfig, key gnarkio.WriterRawTo, fullWitness encoding.BinaryMarshaler) ([]byte, error) {
+	if cfg.HashToFieldFn != nil || !isSHA256(cfg.ChallengeHash) || !isSHA256(cfg.KZGFoldingHash) {
+		return nil, errors.New("the proof cache doesn't support custom hash functions")
+	}
+	w, err := fullWitness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte("gnark-proof-cache"))
+	_ = binary.Write(h, binary.BigEndian, id)
+	_ = binary.Write(h, binary.BigEndian, []bool{cfg.UnsafeNoBlinding, cfg.SideChannelHardening})
+	if _, err := key.WriteRawTo(h); err != nil {
+		return nil, err
+	}
+	_ = binary.Write(h, binary.BigEndian, uint64(len(w)))
+	h.Write(w)
+	return h.Sum(nil), nil
+}
+
+// isSHA256 returns true if h is SHA2-256, the default hash function of the
+// challenges and of the KZG folding.
+func isSHA256(h hash.Hash) bool {
+	return reflect.TypeOf(h) == reflect.TypeOf(sha256.New()) && h.Size() == sha256.Size
+}
+
+// NewMemoryProofCache returns a ProofCache storing the proofs in memory,
+// without eviction.
+func NewMemoryProofCache() ProofCache {
+	return &memoryProofCache{proofs: make(map[string][]byte)}
+}
+
+type memoryProofCache struct {
+	lock   sync.RWMutex
+	proofs map[string][]byte
+}
+
+func (c *memoryProofCache) Get(key []byte) ([]byte, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	proof, ok := c.proofs[string(key)]
+	return proof, ok
+}
+
+func (c *memoryProofCache) Put(key, proof []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.proofs[string(key)] = proof
+}
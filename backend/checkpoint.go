@@ -0,0 +1,109 @@
+package backend
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoCheckpoint is returned when resuming a proof from a checkpoint directory
+// which doesn't contain a checkpoint of the proof system.
+var ErrNoCheckpoint = errors.New("no checkpoint")
+
+// WithCheckpointDir makes the prover save its state in dir after its main
+// phases, so that a proof interrupted by a crash or a preemption can be
+// completed with the ResumeProve function of the proof system instead of
+// being computed again. The Groth16 prover saves its state after solving the
+// witness and after computing the quotient H, the PLONK prover after solving
+// the witness, after committing to L, R, O and Z, and after committing to the
+// quotient H.
+//
+// A directory holds the checkpoint of a single proof for each proof system,
+// which is overwritten by each phase and removed once the proof is computed,
+// unless [WithKeepCheckpoint] is set. The checkpoint contains the solution of
+// the constraint system, including the secret inputs: the directory must be
+// private. The checkpoints are not bound to the proving key beyond its sizes,
+// so the same key must be given when resuming. The option is ignored by the
+// GPU accelerated provers.
+func WithCheckpointDir(dir string) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.CheckpointDir = dir
+		return nil
+	}
+}
+
+// WithKeepCheckpoint keeps the checkpoint of the last phase in the directory
+// set with [WithCheckpointDir] once the proof is computed, for example to
+// compute the proof again with different prover options. As the checkpoint
+// contains the secret inputs, the caller is responsible for removing it.
+func WithKeepCheckpoint() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.KeepCheckpoint = true
+		return nil
+	}
+}
+
+// WriteCheckpoint writes the checkpoint name to the checkpoint directory dir.
+// The previous checkpoint is replaced atomically, so that it remains complete
+// if the write is interrupted.
+func WriteCheckpoint(dir, name string, checkpoint io.WriterTo) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create checkpoint: %w", err)
+	}
+	defer os.Remove(f.Name()) // no-op once renamed
+	bw := bufio.NewWriter(f)
+	if _, err := checkpoint.WriteTo(bw); err != nil {
+		f.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync checkpoint: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close checkpoint: %w", err)
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, name))
+}
+
+// ReadCheckpoint reads the checkpoint name from the checkpoint directory dir.
+// It returns [ErrNoCheckpoint] if there is no such checkpoint.
+func ReadCheckpoint(dir, name string, checkpoint io.ReaderFrom) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNoCheckpoint
+	}
+	if err != nil {
+		return fmt.Errorf("open checkpoint: %w", err)
+	}
+	defer f.Close()
+	if _, err := checkpoint.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("read checkpoint: %w", err)
+	}
+	return nil
+}
+
+// RemoveCheckpoint removes the checkpoint name from the checkpoint directory
+// dir once the proof is computed, unless the prover options keep it (see
+// [WithKeepCheckpoint]). It is a no-op if the directory is not set or if there
+// is no such checkpoint.
+func RemoveCheckpoint(opt *ProverConfig, name string) error {
+	if opt.CheckpointDir == "" || opt.KeepCheckpoint {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(opt.CheckpointDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove checkpoint: %w", err)
+	}
+	return nil
+}
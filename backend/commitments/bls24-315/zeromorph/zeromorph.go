@@ -0,0 +1,382 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package zeromorph
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidSize   = errors.New("number of evaluations is not 2 to the number of variables")
+	ErrNbQuotients   = errors.New("number of quotients doesn't match the number of variables")
+	ErrVerifyOpening = errors.New("can't verify multilinear opening proof")
+	ErrSRSSize       = errors.New("SRS is smaller than the number of evaluations")
+)
+
+// VerifyingKey is the KZG verifying key of the SRS along with its size, which
+// the degree check of the batched quotient depends on.
+type VerifyingKey struct {
+	kzg.VerifyingKey
+
+	// SRSSize is the number of powers of τ in G1 in the SRS, that is
+	// len(pk.G1) for the proving key pk given to [Open].
+	SRSSize uint64
+}
+
+// OpeningProof is a proof that a multilinear polynomial evaluates to
+// ClaimedValue at a point, following the Zeromorph protocol
+// (https://eprint.iacr.org/2023/917). Its size is logarithmic in the number
+// of evaluations of the polynomial.
+type OpeningProof struct {
+	// Quotients[k] is the commitment to the k-variate quotient qₖ, such that
+	// f - v = Σₖ (Xₖ-uₖ)qₖ(X₀, …, Xₖ₋₁).
+	Quotients []curve.G1Affine
+
+	// BatchedQuotient is the commitment to q̂ = Σₖ yᵏXᴺ⁻²ᵏ U(qₖ), which bounds
+	// the degrees of the quotients.
+	BatchedQuotient curve.G1Affine
+
+	// ShiftedQuotient is the commitment to Xᴰ⁻ᴺq̂, for an SRS of size D. As it
+	// can't be committed to with the SRS unless deg q̂ < N, it proves the
+	// bound on the degree of q̂ when the SRS is larger than N.
+	ShiftedQuotient curve.G1Affine
+
+	// Quotient is the KZG opening proof at x of the polynomial tying the
+	// quotients to f, which vanishes at x.
+	Quotient curve.G1Affine
+
+	ClaimedValue fr.Element
+}
+
+// Commit commits to the multilinear polynomial given by its evaluations on
+// the boolean hypercube, the evaluation at b=(b₀, …, bₙ₋₁) being at index
+// Σᵢ bᵢ2ⁱ. The commitment is the KZG commitment to the univariate polynomial
+// with the evaluations as coefficients.
+func Commit(evaluations []fr.Element, pk kzg.ProvingKey) (kzg.Digest, error) {
+	return kzg.Commit(evaluations, pk)
+}
+
+// Evaluate returns the evaluation of the multilinear polynomial given by its
+// evaluations on the boolean hypercube at point.
+func Evaluate(evaluations, point []fr.Element) (fr.Element, error) {
+	if len(evaluations) != 1<<len(point) {
+		return fr.Element{}, ErrInvalidSize
+	}
+	f := make([]fr.Element, len(evaluations))
+	copy(f, evaluations)
+	for k := len(point) - 1; k >= 0; k-- {
+		f, _ = fold(f, point[k])
+	}
+	return f[0], nil
+}
+
+// Open computes an opening proof of the multilinear polynomial given by its
+// evaluations on the boolean hypercube at point. digest is the commitment to
+// the polynomial, see [Commit].
+//
+// pk must hold the whole SRS, as the degree check depends on its size. The
+// cost of the last commitment grows with this size rather than with the
+// number of evaluations.
+//
+// dataTranscript is bound to the Fiat-Shamir transcript.
+func Open(evaluations, point []fr.Element, digest kzg.Digest, hf hash.Hash, pk kzg.ProvingKey, dataTranscript ...[]byte) (OpeningProof, error) {
+	var proof OpeningProof
+	n := len(point)
+	if len(evaluations) != 1<<n {
+		return proof, ErrInvalidSize
+	}
+	if len(pk.G1) < len(evaluations) {
+		return proof, ErrSRSSize
+	}
+
+	// fix the variables from the last one: f⁽ᵏ⁺¹⁾ = f⁽ᵏ⁾ + (Xₖ-uₖ)qₖ where
+	// f⁽ⁿ⁾ = f and f⁽⁰⁾ = v.
+	quotients := make([][]fr.Element, n)
+	f := make([]fr.Element, len(evaluations))
+	copy(f, evaluations)
+	for k := n - 1; k >= 0; k-- {
+		f, quotients[k] = fold(f, point[k])
+	}
+	proof.ClaimedValue = f[0]
+
+	var err error
+	proof.Quotients = make([]curve.G1Affine, n)
+	for k := range quotients {
+		if proof.Quotients[k], err = kzg.Commit(quotients[k], pk); err != nil {
+			return proof, err
+		}
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "y", "x", "z")
+	y, err := deriveY(fs, &proof, digest, point, dataTranscript)
+	if err != nil {
+		return proof, err
+	}
+
+	// q̂ = Σₖ yᵏXᴺ⁻²ᵏU(qₖ)
+	size := len(evaluations)
+	qHat := make([]fr.Element, size)
+	var yk fr.Element
+	yk.SetOne()
+	for k := range quotients {
+		offset := size - len(quotients[k])
+		for j := range quotients[k] {
+			var t fr.Element
+			t.Mul(&quotients[k][j], &yk)
+			qHat[offset+j].Add(&qHat[offset+j], &t)
+		}
+		yk.Mul(&yk, &y)
+	}
+	if proof.BatchedQuotient, err = kzg.Commit(qHat, pk); err != nil {
+		return proof, err
+	}
+
+	// Xᴰ⁻ᴺq̂ is committed to with the last N powers of τ.
+	shift := len(pk.G1) - size
+	if proof.ShiftedQuotient, err = kzg.Commit(qHat, kzg.ProvingKey{G1: pk.G1[shift:]}); err != nil {
+		return proof, err
+	}
+
+	x, z, err := deriveXZ(fs, &proof.BatchedQuotient, &proof.ShiftedQuotient)
+	if err != nil {
+		return proof, err
+	}
+
+	// ζₓ + zZₓ + z²(Xᴰ⁻ᴺq̂ - xᴰ⁻ᴺq̂) = q̂ + zf - zvΦₙ(x) + Σₖ cₖU(qₖ) +
+	// z²(Xᴰ⁻ᴺq̂ - xᴰ⁻ᴺq̂) vanishes at x.
+	c, constant := coefficients(point, &proof.ClaimedValue, x, y, z)
+	zSquare, hatCoeff := shiftCoefficients(x, z, uint64(shift))
+	r := make([]fr.Element, len(pk.G1))
+	for j := range qHat {
+		var t fr.Element
+		t.Mul(&qHat[j], &hatCoeff)
+		r[j].Mul(&evaluations[j], &z).Add(&r[j], &t)
+	}
+	for j := range qHat {
+		var t fr.Element
+		t.Mul(&qHat[j], &zSquare)
+		r[shift+j].Add(&r[shift+j], &t)
+	}
+	r[0].Add(&r[0], &constant)
+	for k := range quotients {
+		for j := range quotients[k] {
+			var t fr.Element
+			t.Mul(&quotients[k][j], &c[k])
+			r[j].Add(&r[j], &t)
+		}
+	}
+	if proof.Quotient, err = kzg.Commit(divideByXMinusA(r, x), pk); err != nil {
+		return proof, err
+	}
+	return proof, nil
+}
+
+// Verify verifies a multilinear opening proof of the polynomial committed to
+// in digest at point, as computed by Open. It costs a multi-exponentiation of
+// size n+5, for n variables, and a pairing check.
+func Verify(proof *OpeningProof, digest kzg.Digest, point []fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+	n := len(point)
+	if len(proof.Quotients) != n {
+		return ErrNbQuotients
+	}
+	if vk.SRSSize < 1<<n {
+		return ErrSRSSize
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "y", "x", "z")
+	y, err := deriveY(fs, proof, digest, point, dataTranscript)
+	if err != nil {
+		return err
+	}
+	x, z, err := deriveXZ(fs, &proof.BatchedQuotient, &proof.ShiftedQuotient)
+	if err != nil {
+		return err
+	}
+
+	// [ζₓ + zZₓ + z²(Xᴰ⁻ᴺq̂ - xᴰ⁻ᴺq̂)] + x[π] =
+	// (1-z²xᴰ⁻ᴺ)[q̂] + z²[Xᴰ⁻ᴺq̂] + z[f] - zvΦₙ(x)[1] + Σₖ cₖ[qₖ] + x[π]
+	c, constant := coefficients(point, &proof.ClaimedValue, x, y, z)
+	zSquare, hatCoeff := shiftCoefficients(x, z, vk.SRSSize-1<<n)
+	bases := make([]curve.G1Affine, 0, n+5)
+	scalars := make([]fr.Element, 0, n+5)
+	bases = append(bases, proof.BatchedQuotient, proof.ShiftedQuotient, digest, vk.G1, proof.Quotient)
+	scalars = append(scalars, hatCoeff, zSquare, z, constant, x)
+	bases = append(bases, proof.Quotients...)
+	scalars = append(scalars, c...)
+	var lhs curve.G1Affine
+	if _, err := lhs.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e([ζₓ + zZₓ] + x[π], [1]) = e([π], [τ])
+	var negQuotient curve.G1Affine
+	negQuotient.Neg(&proof.Quotient)
+	ok, err := curve.PairingCheck([]curve.G1Affine{lhs, negQuotient}, []curve.G2Affine{vk.G2[0], vk.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyOpening
+	}
+	return nil
+}
+
+// coefficients returns the coefficients cₖ of the commitments to the
+// quotients and the constant term in the linear combination checked by the
+// verifier:
+//
+//	cₖ = -yᵏxᴺ⁻²ᵏ - z(x²ᵏΦₙ₋ₖ₋₁(x²ᵏ⁺¹) - uₖΦₙ₋ₖ(x²ᵏ))
+//	constant = -zvΦₙ(x)
+//
+// where Φₘ(X) = Σᵢ₌₀²ᵐ⁻¹Xⁱ = Πᵢ₌₀ᵐ⁻¹(1+X²ⁱ), so that no inversion is needed.
+func coefficients(point []fr.Element, claimedValue *fr.Element, x, y, z fr.Element) (c []fr.Element, constant fr.Element) {
+	n := len(point)
+
+	// x²ᵏ
+	powers := make([]fr.Element, n+1)
+	powers[0] = x
+	for k := 1; k <= n; k++ {
+		powers[k].Square(&powers[k-1])
+	}
+
+	// phi[k] = Φₙ₋ₖ(x²ᵏ) = Πᵢ₌ₖⁿ⁻¹(1+x²ⁱ) and shift[k] = xᴺ⁻²ᵏ = Πᵢ₌ₖⁿ⁻¹x²ⁱ
+	phi := make([]fr.Element, n+1)
+	shift := make([]fr.Element, n+1)
+	phi[n].SetOne()
+	shift[n].SetOne()
+	one := fr.One()
+	for k := n - 1; k >= 0; k-- {
+		var t fr.Element
+		t.Add(&powers[k], &one)
+		phi[k].Mul(&phi[k+1], &t)
+		shift[k].Mul(&shift[k+1], &powers[k])
+	}
+
+	c = make([]fr.Element, n)
+	var yk fr.Element
+	yk.SetOne()
+	for k := range c {
+		var t, u fr.Element
+		t.Mul(&powers[k], &phi[k+1])
+		u.Mul(&point[k], &phi[k])
+		t.Sub(&t, &u).Mul(&t, &z)
+		u.Mul(&yk, &shift[k])
+		c[k].Add(&t, &u).Neg(&c[k])
+		yk.Mul(&yk, &y)
+	}
+	constant.Mul(&z, claimedValue).Mul(&constant, &phi[0]).Neg(&constant)
+	return c, constant
+}
+
+// shiftCoefficients returns the coefficients z² and 1-z²xᴰ⁻ᴺ of the
+// commitments to Xᴰ⁻ᴺq̂ and q̂ in the linear combination checked by the
+// verifier, shift being D-N.
+func shiftCoefficients(x, z fr.Element, shift uint64) (zSquare, hatCoeff fr.Element) {
+	var xShift fr.Element
+	xShift.Exp(x, new(big.Int).SetUint64(shift))
+	zSquare.Square(&z)
+	hatCoeff.Mul(&zSquare, &xShift)
+	one := fr.One()
+	hatCoeff.Sub(&one, &hatCoeff)
+	return zSquare, hatCoeff
+}
+
+// fold fixes the last variable of the multilinear polynomial f to u. With
+// f = (1-X)A + XB it returns A + u(B-A) and the quotient B-A.
+func fold(f []fr.Element, u fr.Element) (folded, quotient []fr.Element) {
+	half := len(f) / 2
+	quotient = make([]fr.Element, half)
+	for j := range quotient {
+		quotient[j].Sub(&f[half+j], &f[j])
+		var t fr.Element
+		t.Mul(&quotient[j], &u)
+		f[j].Add(&f[j], &t)
+	}
+	return f[:half], quotient
+}
+
+func deriveY(fs *fiatshamir.Transcript, proof *OpeningProof, digest kzg.Digest, point []fr.Element, dataTranscript [][]byte) (fr.Element, error) {
+	var y fr.Element
+	if err := fs.Bind("y", digest.Marshal()); err != nil {
+		return y, err
+	}
+	for i := range point {
+		if err := fs.Bind("y", point[i].Marshal()); err != nil {
+			return y, err
+		}
+	}
+	if err := fs.Bind("y", proof.ClaimedValue.Marshal()); err != nil {
+		return y, err
+	}
+	for _, data := range dataTranscript {
+		if err := fs.Bind("y", data); err != nil {
+			return y, err
+		}
+	}
+	for i := range proof.Quotients {
+		if err := fs.Bind("y", proof.Quotients[i].Marshal()); err != nil {
+			return y, err
+		}
+	}
+	b, err := fs.ComputeChallenge("y")
+	if err != nil {
+		return y, err
+	}
+	y.SetBytes(b)
+	return y, nil
+}
+
+func deriveXZ(fs *fiatshamir.Transcript, batchedQuotient, shiftedQuotient *curve.G1Affine) (x, z fr.Element, err error) {
+	if err = fs.Bind("x", batchedQuotient.Marshal()); err != nil {
+		return
+	}
+	if err = fs.Bind("x", shiftedQuotient.Marshal()); err != nil {
+		return
+	}
+	b, err := fs.ComputeChallenge("x")
+	if err != nil {
+		return
+	}
+	x.SetBytes(b)
+	if b, err = fs.ComputeChallenge("z"); err != nil {
+		return
+	}
+	z.SetBytes(b)
+	return
+}
+
+// divideByXMinusA returns the quotient of p by (X-a), dropping the remainder.
+func divideByXMinusA(p []fr.Element, a fr.Element) []fr.Element {
+	res := make([]fr.Element, len(p)-1)
+	var carry fr.Element
+	for i := len(p) - 1; i >= 1; i-- {
+		carry.Mul(&carry, &a).Add(&carry, &p[i])
+		res[i-1] = carry
+	}
+	return res
+}
@@ -0,0 +1,357 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package ligero
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrPolynomialTooLarge = errors.New("polynomial is larger than the committed matrix")
+	ErrProofSize          = errors.New("opening proof doesn't match the parameters")
+	ErrMerklePath         = errors.New("invalid Merkle path of an opened column")
+	ErrColumnMismatch     = errors.New("opened column doesn't match the encoded rows")
+	ErrClaimedValue       = errors.New("claimed value doesn't match the combined row")
+)
+
+// Params are the public parameters of the commitment scheme. The polynomial
+// is arranged in a NbRows×NbColumns matrix, whose rows are encoded with a
+// Reed-Solomon code of rate 1/Rho; the openings reveal NbQueries columns of
+// the encoded matrix.
+//
+// In the unique decoding regime, each query catches a cheating prover with
+// probability about (1-1/Rho)/2: for instance, Rho=4 and NbQueries=128 give
+// a soundness error of about 2⁻⁸⁷.
+type Params struct {
+	NbRows, NbColumns int
+	Rho               int
+	NbQueries         int
+}
+
+// Check returns an error if the parameters are invalid.
+func (p *Params) Check() error {
+	if p.NbRows <= 0 || p.NbColumns <= 0 || p.NbQueries <= 0 {
+		return errors.New("number of rows, columns and queries must be positive")
+	}
+	if bits.OnesCount(uint(p.NbColumns)) != 1 || bits.OnesCount(uint(p.Rho)) != 1 || p.Rho < 2 {
+		return errors.New("number of columns and Rho must be powers of 2, with Rho ≥ 2")
+	}
+	return nil
+}
+
+// Generator returns the generator of the evaluation domain of the code, of
+// size NbColumns×Rho.
+func (p *Params) Generator() (fr.Element, error) {
+	return fft.Generator(uint64(p.NbColumns * p.Rho))
+}
+
+// OpeningProof is a proof that the committed polynomial evaluates to
+// ClaimedValue at a point z. With the polynomial arranged in a matrix M and
+// a = (1, zᵐ, z²ᵐ, …), b = (1, z, z², …) for m columns, the evaluation is
+// aᵀMb.
+type OpeningProof struct {
+	ClaimedValue fr.Element
+
+	// CombinedRow is aᵀM and ProximityRow is rᵀM for a random r.
+	CombinedRow, ProximityRow []fr.Element
+
+	// Columns[i] is the i-th queried column of the encoded matrix and
+	// Paths[i] is its Merkle path, from the leaf to the root.
+	Columns [][]fr.Element
+	Paths   [][][]byte
+}
+
+// Commitment is the commitment to a polynomial, with the encoded matrix kept
+// by the prover to compute opening proofs.
+type Commitment struct {
+	// Root is the root of the Merkle tree over the columns of the encoded
+	// matrix, and the commitment sent to the verifier.
+	Root []byte
+
+	params  Params
+	rows    [][]fr.Element
+	encoded [][]fr.Element
+	tree    [][][]byte // tree[0] are the hashes of the columns
+}
+
+// Commit commits to the polynomial p, given in canonical basis. hf is used
+// for the Merkle tree and must be the same as the one used to verify.
+func Commit(p []fr.Element, params Params, hf hash.Hash) (*Commitment, error) {
+	if err := params.Check(); err != nil {
+		return nil, err
+	}
+	if len(p) > params.NbRows*params.NbColumns {
+		return nil, ErrPolynomialTooLarge
+	}
+	c := &Commitment{params: params}
+	size := params.NbColumns * params.Rho
+	domain := fft.NewDomain(uint64(size))
+	c.rows = make([][]fr.Element, params.NbRows)
+	c.encoded = make([][]fr.Element, params.NbRows)
+	for i := range c.rows {
+		c.rows[i] = make([]fr.Element, params.NbColumns)
+		if start := i * params.NbColumns; start < len(p) {
+			copy(c.rows[i], p[start:])
+		}
+		c.encoded[i] = encode(c.rows[i], domain)
+	}
+
+	leaves := make([][]byte, size)
+	column := make([]fr.Element, params.NbRows)
+	for j := range leaves {
+		for i := range column {
+			column[i] = c.encoded[i][j]
+		}
+		leaves[j] = hashColumn(hf, column)
+	}
+	c.tree = [][][]byte{leaves}
+	for layer := leaves; len(layer) > 1; {
+		next := make([][]byte, len(layer)/2)
+		for j := range next {
+			next[j] = hashNode(hf, layer[2*j], layer[2*j+1])
+		}
+		c.tree = append(c.tree, next)
+		layer = next
+	}
+	c.Root = c.tree[len(c.tree)-1][0]
+	return c, nil
+}
+
+// Open computes an opening proof of the committed polynomial at z.
+//
+// dataTranscript is bound to the Fiat-Shamir transcript.
+func (c *Commitment) Open(z fr.Element, hf hash.Hash, dataTranscript ...[]byte) (OpeningProof, error) {
+	var proof OpeningProof
+	fs := fiatshamir.NewTranscript(hf, challengeNames(c.params)...)
+	r, err := deriveR(fs, c.Root, z, dataTranscript)
+	if err != nil {
+		return proof, err
+	}
+
+	a, b := evaluationVectors(c.params, z)
+	proof.CombinedRow = combineRows(c.rows, a)
+	proof.ProximityRow = combineRows(c.rows, powers(r, c.params.NbRows))
+	proof.ClaimedValue = innerProduct(proof.CombinedRow, b)
+
+	indexes, err := deriveIndexes(fs, c.params, &proof)
+	if err != nil {
+		return proof, err
+	}
+	proof.Columns = make([][]fr.Element, len(indexes))
+	proof.Paths = make([][][]byte, len(indexes))
+	for q, index := range indexes {
+		proof.Columns[q] = make([]fr.Element, c.params.NbRows)
+		for i := range proof.Columns[q] {
+			proof.Columns[q][i] = c.encoded[i][index]
+		}
+		proof.Paths[q] = make([][]byte, len(c.tree)-1)
+		for level := range proof.Paths[q] {
+			proof.Paths[q][level] = c.tree[level][index^1]
+			index >>= 1
+		}
+	}
+	return proof, nil
+}
+
+// Verify verifies an opening proof at z of the polynomial committed to in
+// root.
+func Verify(root []byte, proof *OpeningProof, z fr.Element, params Params, hf hash.Hash, dataTranscript ...[]byte) error {
+	if err := params.Check(); err != nil {
+		return err
+	}
+	size := params.NbColumns * params.Rho
+	depth := bits.TrailingZeros(uint(size))
+	if len(proof.CombinedRow) != params.NbColumns || len(proof.ProximityRow) != params.NbColumns ||
+		len(proof.Columns) != params.NbQueries || len(proof.Paths) != params.NbQueries {
+		return ErrProofSize
+	}
+	for q := range proof.Columns {
+		if len(proof.Columns[q]) != params.NbRows || len(proof.Paths[q]) != depth {
+			return ErrProofSize
+		}
+	}
+
+	fs := fiatshamir.NewTranscript(hf, challengeNames(params)...)
+	r, err := deriveR(fs, root, z, dataTranscript)
+	if err != nil {
+		return err
+	}
+	a, b := evaluationVectors(params, z)
+	if v := innerProduct(proof.CombinedRow, b); !v.Equal(&proof.ClaimedValue) {
+		return ErrClaimedValue
+	}
+	indexes, err := deriveIndexes(fs, params, proof)
+	if err != nil {
+		return err
+	}
+
+	domain := fft.NewDomain(uint64(size))
+	encodedCombined := encode(proof.CombinedRow, domain)
+	encodedProximity := encode(proof.ProximityRow, domain)
+	rPowers := powers(r, params.NbRows)
+	for q, index := range indexes {
+		node := hashColumn(hf, proof.Columns[q])
+		for level, sibling := range proof.Paths[q] {
+			if index>>level&1 == 0 {
+				node = hashNode(hf, node, sibling)
+			} else {
+				node = hashNode(hf, sibling, node)
+			}
+		}
+		if !bytes.Equal(node, root) {
+			return ErrMerklePath
+		}
+		u, w := innerProduct(a, proof.Columns[q]), innerProduct(rPowers, proof.Columns[q])
+		if !u.Equal(&encodedCombined[index]) || !w.Equal(&encodedProximity[index]) {
+			return ErrColumnMismatch
+		}
+	}
+	return nil
+}
+
+// encode returns the evaluations of the row, in canonical basis, on the
+// domain.
+func encode(row []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, domain.Cardinality)
+	copy(res, row)
+	domain.FFT(res, fft.DIF)
+	fft.BitReverse(res)
+	return res
+}
+
+// evaluationVectors returns a = (1, zᵐ, z²ᵐ, …) and b = (1, z, …, zᵐ⁻¹) for m
+// columns.
+func evaluationVectors(params Params, z fr.Element) (a, b []fr.Element) {
+	b = powers(z, params.NbColumns)
+	var zm fr.Element
+	zm.Mul(&b[params.NbColumns-1], &z)
+	return powers(zm, params.NbRows), b
+}
+
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
+
+func combineRows(rows [][]fr.Element, coefficients []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(rows[0]))
+	for i := range rows {
+		for j := range res {
+			var t fr.Element
+			t.Mul(&rows[i][j], &coefficients[i])
+			res[j].Add(&res[j], &t)
+		}
+	}
+	return res
+}
+
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+func hashColumn(hf hash.Hash, column []fr.Element) []byte {
+	hf.Reset()
+	for i := range column {
+		b := column[i].Bytes()
+		hf.Write(b[:])
+	}
+	return hf.Sum(nil)
+}
+
+func hashNode(hf hash.Hash, left, right []byte) []byte {
+	hf.Reset()
+	hf.Write(left)
+	hf.Write(right)
+	return hf.Sum(nil)
+}
+
+// challengeNames returns the names of the Fiat-Shamir challenges: the
+// coefficient of the proximity test, then one challenge per query.
+func challengeNames(params Params) []string {
+	res := make([]string, params.NbQueries+1)
+	res[0] = "r"
+	for q := 1; q < len(res); q++ {
+		res[q] = "query" + strconv.Itoa(q-1)
+	}
+	return res
+}
+
+func deriveR(fs *fiatshamir.Transcript, root []byte, z fr.Element, dataTranscript [][]byte) (fr.Element, error) {
+	var r fr.Element
+	if err := fs.Bind("r", root); err != nil {
+		return r, err
+	}
+	if err := fs.Bind("r", z.Marshal()); err != nil {
+		return r, err
+	}
+	for _, data := range dataTranscript {
+		if err := fs.Bind("r", data); err != nil {
+			return r, err
+		}
+	}
+	b, err := fs.ComputeChallenge("r")
+	if err != nil {
+		return r, err
+	}
+	r.SetBytes(b)
+	return r, nil
+}
+
+// deriveIndexes returns the indexes of the queried columns, bound to the
+// combined and proximity rows.
+func deriveIndexes(fs *fiatshamir.Transcript, params Params, proof *OpeningProof) ([]int, error) {
+	for _, row := range [][]fr.Element{proof.CombinedRow, proof.ProximityRow} {
+		for i := range row {
+			if err := fs.Bind("query0", row[i].Marshal()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	size := params.NbColumns * params.Rho
+	res := make([]int, params.NbQueries)
+	var e fr.Element
+	var bi big.Int
+	for q := range res {
+		b, err := fs.ComputeChallenge("query" + strconv.Itoa(q))
+		if err != nil {
+			return nil, err
+		}
+		e.SetBytes(b)
+		e.BigInt(&bi)
+		res[q] = int(bi.Uint64() & uint64(size-1))
+	}
+	return res, nil
+}
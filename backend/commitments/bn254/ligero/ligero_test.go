@@ -0,0 +1,73 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package ligero
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	params := Params{NbRows: 8, NbColumns: 16, Rho: 4, NbQueries: 20}
+	p := make([]fr.Element, 100)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	commitment, err := Commit(p, params, mimc.NewMiMC())
+	assert.NoError(err)
+
+	var z fr.Element
+	z.SetRandom()
+	proof, err := commitment.Open(z, mimc.NewMiMC())
+	assert.NoError(err)
+
+	// Horner evaluation of p at z
+	var expected fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		expected.Mul(&expected, &z).Add(&expected, &p[i])
+	}
+	assert.Equal(expected, proof.ClaimedValue)
+	assert.NoError(Verify(commitment.Root, &proof, z, params, mimc.NewMiMC()))
+
+	// wrong point
+	var other fr.Element
+	other.SetRandom()
+	assert.Error(Verify(commitment.Root, &proof, other, params, mimc.NewMiMC()))
+
+	// wrong claimed value
+	proof.ClaimedValue.SetOne()
+	assert.ErrorIs(Verify(commitment.Root, &proof, z, params, mimc.NewMiMC()), ErrClaimedValue)
+	proof.ClaimedValue = expected
+
+	// combined row not consistent with the columns, which also changes the
+	// queried columns
+	old := proof.CombinedRow[0]
+	proof.CombinedRow[0].SetOne()
+	proof.ClaimedValue.Add(&expected, &proof.CombinedRow[0]).Sub(&proof.ClaimedValue, &old)
+	assert.Error(Verify(commitment.Root, &proof, z, params, mimc.NewMiMC()))
+
+	// invalid sizes
+	_, err = Commit(make([]fr.Element, 200), params, mimc.NewMiMC())
+	assert.ErrorIs(err, ErrPolynomialTooLarge)
+	proof.Columns = proof.Columns[1:]
+	assert.ErrorIs(Verify(commitment.Root, &proof, z, params, mimc.NewMiMC()), ErrProofSize)
+}
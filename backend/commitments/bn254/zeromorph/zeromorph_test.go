@@ -0,0 +1,254 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package zeromorph
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	const nbVariables = 5
+	srs, err := kzg.NewSRS(1<<nbVariables, big.NewInt(42))
+	assert.NoError(err)
+	vk := VerifyingKey{VerifyingKey: srs.Vk, SRSSize: uint64(len(srs.Pk.G1))}
+
+	evaluations := randomElements(1 << nbVariables)
+	point := randomElements(nbVariables)
+	digest, err := Commit(evaluations, srs.Pk)
+	assert.NoError(err)
+
+	proof, err := Open(evaluations, point, digest, sha256.New(), srs.Pk, []byte("data"))
+	assert.NoError(err)
+	expected, err := Evaluate(evaluations, point)
+	assert.NoError(err)
+	assert.Equal(expected, proof.ClaimedValue)
+	assert.NoError(Verify(&proof, digest, point, sha256.New(), vk, []byte("data")))
+
+	// wrong transcript data
+	assert.Error(Verify(&proof, digest, point, sha256.New(), vk, []byte("other")))
+
+	// wrong claimed value
+	proof.ClaimedValue.SetOne()
+	assert.Error(Verify(&proof, digest, point, sha256.New(), vk, []byte("data")))
+	proof.ClaimedValue = expected
+
+	// wrong point
+	point[2].SetOne()
+	assert.Error(Verify(&proof, digest, point, sha256.New(), vk, []byte("data")))
+
+	// invalid sizes
+	_, err = Open(evaluations[:10], point, digest, sha256.New(), srs.Pk)
+	assert.ErrorIs(err, ErrInvalidSize)
+	assert.ErrorIs(Verify(&proof, digest, point[:3], sha256.New(), vk), ErrNbQuotients)
+	_, err = Open(evaluations, point, digest, sha256.New(), kzg.ProvingKey{G1: srs.Pk.G1[:10]})
+	assert.ErrorIs(err, ErrSRSSize)
+}
+
+func TestOpenLargerSRS(t *testing.T) {
+	assert := require.New(t)
+
+	const nbVariables = 3
+	srs, err := kzg.NewSRS(100, big.NewInt(42))
+	assert.NoError(err)
+	vk := VerifyingKey{VerifyingKey: srs.Vk, SRSSize: uint64(len(srs.Pk.G1))}
+
+	evaluations := randomElements(1 << nbVariables)
+	point := randomElements(nbVariables)
+	digest, err := Commit(evaluations, srs.Pk)
+	assert.NoError(err)
+	proof, err := Open(evaluations, point, digest, sha256.New(), srs.Pk)
+	assert.NoError(err)
+	assert.NoError(Verify(&proof, digest, point, sha256.New(), vk))
+
+	// the size of the SRS is part of the statement
+	vk.SRSSize--
+	assert.Error(Verify(&proof, digest, point, sha256.New(), vk))
+}
+
+// TestForgeryLargerSRS checks that the verifier rejects an opening to a wrong
+// value, forged with quotients whose degrees are too high to be committed to
+// with an SRS of size N but not with a larger SRS.
+func TestForgeryLargerSRS(t *testing.T) {
+	assert := require.New(t)
+
+	const (
+		nbVariables = 2
+		size        = 1 << nbVariables
+	)
+	srs, err := kzg.NewSRS(2*size, big.NewInt(42))
+	assert.NoError(err)
+	vk := VerifyingKey{VerifyingKey: srs.Vk, SRSSize: uint64(len(srs.Pk.G1))}
+
+	evaluations := randomElements(size)
+	point := randomElements(nbVariables)
+	digest, err := Commit(evaluations, srs.Pk)
+	assert.NoError(err)
+
+	// f - vΦ₂ = A₀q₀ + A₁q₁ with A₀ = (1+X²)((1-u₀)X - u₀) and
+	// A₁ = (1-u₁)X² - u₁. Since Φ₂ = (1+X)(1+X²) = A₀(eX + g) + A₁d(1+X²) for
+	// d = (1-u₀)/(u₀²(1-u₁) - (1-u₀)²u₁), e = -d(1-u₁)/(1-u₀) and
+	// g = -(1 + du₁)/u₀, the quotients q₀ + eX + g and q₁ + d(1+X²) open f
+	// to v - 1, with degrees 1 and 2 instead of 0 and 1.
+	f := make([]fr.Element, size)
+	copy(f, evaluations)
+	quotients := make([][]fr.Element, nbVariables)
+	for k := nbVariables - 1; k >= 0; k-- {
+		f, quotients[k] = fold(f, point[k])
+	}
+	var proof OpeningProof
+	one := fr.One()
+	proof.ClaimedValue.Sub(&f[0], &one)
+	var alpha, beta, d, e, g, tmp fr.Element
+	alpha.Sub(&one, &point[0])
+	beta.Sub(&one, &point[1])
+	d.Square(&point[0]).Mul(&d, &beta)
+	tmp.Square(&alpha).Mul(&tmp, &point[1])
+	d.Sub(&d, &tmp).Inverse(&d).Mul(&d, &alpha)
+	e.Div(&beta, &alpha).Mul(&e, &d).Neg(&e)
+	g.Mul(&d, &point[1]).Add(&g, &one).Div(&g, &point[0]).Neg(&g)
+	quotients[0] = []fr.Element{quotients[0][0], e}
+	quotients[0][0].Add(&quotients[0][0], &g)
+	quotients[1] = append(quotients[1], d)
+	quotients[1][0].Add(&quotients[1][0], &d)
+
+	proof.Quotients = make([]curve.G1Affine, nbVariables)
+	for k := range quotients {
+		proof.Quotients[k], err = kzg.Commit(quotients[k], srs.Pk)
+		assert.NoError(err)
+	}
+	fs := fiatshamir.NewTranscript(sha256.New(), "y", "x", "z")
+	y, err := deriveY(fs, &proof, digest, point, nil)
+	assert.NoError(err)
+
+	// q̂ = X³q₀ + yX²q₁ has degree N, so it can be committed to but Xᴰ⁻ᴺq̂
+	// can't, the best the prover can do is to drop its leading term.
+	qHat := make([]fr.Element, size+1)
+	for k := range quotients {
+		var yk fr.Element
+		yk.Exp(y, big.NewInt(int64(k)))
+		offset := size - 1<<k
+		for j := range quotients[k] {
+			tmp.Mul(&quotients[k][j], &yk)
+			qHat[offset+j].Add(&qHat[offset+j], &tmp)
+		}
+	}
+	proof.BatchedQuotient, err = kzg.Commit(qHat, srs.Pk)
+	assert.NoError(err)
+	shift := len(srs.Pk.G1) - size
+	proof.ShiftedQuotient, err = kzg.Commit(qHat[:size], kzg.ProvingKey{G1: srs.Pk.G1[shift:]})
+	assert.NoError(err)
+	x, z, err := deriveXZ(fs, &proof.BatchedQuotient, &proof.ShiftedQuotient)
+	assert.NoError(err)
+
+	// without the degree check, q̂ + zf - zvΦₙ(x) + Σₖ cₖqₖ would vanish at x
+	c, constant := coefficients(point, &proof.ClaimedValue, x, y, z)
+	r := make([]fr.Element, len(srs.Pk.G1))
+	for j := range qHat {
+		r[j] = qHat[j]
+	}
+	for j := range evaluations {
+		tmp.Mul(&evaluations[j], &z)
+		r[j].Add(&r[j], &tmp)
+	}
+	r[0].Add(&r[0], &constant)
+	for k := range quotients {
+		for j := range quotients[k] {
+			tmp.Mul(&quotients[k][j], &c[k])
+			r[j].Add(&r[j], &tmp)
+		}
+	}
+	remainder := evaluate(r, x)
+	assert.True(remainder.IsZero())
+
+	zSquare, hatCoeff := shiftCoefficients(x, z, uint64(shift))
+	var hatCoeffMinusOne fr.Element
+	hatCoeffMinusOne.Sub(&hatCoeff, &one)
+	for j := range qHat {
+		tmp.Mul(&qHat[j], &hatCoeffMinusOne)
+		r[j].Add(&r[j], &tmp)
+	}
+	for j := range qHat[:size] {
+		tmp.Mul(&qHat[j], &zSquare)
+		r[shift+j].Add(&r[shift+j], &tmp)
+	}
+	proof.Quotient, err = kzg.Commit(divideByXMinusA(r, x), srs.Pk)
+	assert.NoError(err)
+	assert.ErrorIs(Verify(&proof, digest, point, sha256.New(), vk), ErrVerifyOpening)
+}
+
+func TestEvaluate(t *testing.T) {
+	assert := require.New(t)
+
+	// on the hypercube, the evaluation is the value at index Σᵢ bᵢ2ⁱ
+	evaluations := randomElements(8)
+	point := make([]fr.Element, 3)
+	point[0].SetOne()
+	point[2].SetOne()
+	v, err := Evaluate(evaluations, point)
+	assert.NoError(err)
+	assert.Equal(evaluations[5], v)
+
+	// f(u) = Σ_b f(b) Πᵢ (uᵢbᵢ + (1-uᵢ)(1-bᵢ))
+	point = randomElements(3)
+	var expected fr.Element
+	one := fr.One()
+	for b := range evaluations {
+		eq := one
+		for i := range point {
+			if b>>i&1 == 1 {
+				eq.Mul(&eq, &point[i])
+			} else {
+				var t fr.Element
+				t.Sub(&one, &point[i])
+				eq.Mul(&eq, &t)
+			}
+		}
+		eq.Mul(&eq, &evaluations[b])
+		expected.Add(&expected, &eq)
+	}
+	v, err = Evaluate(evaluations, point)
+	assert.NoError(err)
+	assert.Equal(expected, v)
+}
+
+func evaluate(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+func randomElements(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
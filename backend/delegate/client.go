@@ -0,0 +1,193 @@
+package delegate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonk_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonk_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315"
+	plonk_bls24317 "github.com/consensys/gnark/backend/plonk/bls24-317"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	plonk_bw6633 "github.com/consensys/gnark/backend/plonk/bw6-633"
+	plonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Client requests proofs from a [Server]. It is safe for concurrent use.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the server at the given URL. If httpClient is
+// nil, [http.DefaultClient] is used.
+func NewClient(url string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{url: strings.TrimSuffix(url, "/"), httpClient: httpClient}
+}
+
+// ProveGroth16 requests a Groth16 proof of the full witness for the circuit
+// registered under the given identifier, and verifies it with the verifying
+// key.
+func (c *Client) ProveGroth16(ctx context.Context, id string, fullWitness witness.Witness, vk groth16.VerifyingKey, opts ...backend.VerifierOption) (groth16.Proof, error) {
+	curve, proofBytes, publicWitness, err := c.prove(ctx, id, backend.GROTH16, fullWitness)
+	if err != nil {
+		return nil, err
+	}
+	if curve != vk.CurveID() {
+		return nil, fmt.Errorf("delegate: server proves on %s, verifying key on %s", curve, vk.CurveID())
+	}
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(bytes.NewReader(proofBytes)); err != nil {
+		return nil, fmt.Errorf("delegate: read proof: %w", err)
+	}
+	if err := groth16.Verify(proof, vk, publicWitness, opts...); err != nil {
+		return nil, fmt.Errorf("delegate: verify proof: %w", err)
+	}
+	return proof, nil
+}
+
+// ProvePlonk requests a PLONK proof of the full witness for the circuit
+// registered under the given identifier, and verifies it with the verifying
+// key.
+func (c *Client) ProvePlonk(ctx context.Context, id string, fullWitness witness.Witness, vk plonk.VerifyingKey, opts ...backend.VerifierOption) (plonk.Proof, error) {
+	curve, proofBytes, publicWitness, err := c.prove(ctx, id, backend.PLONK, fullWitness)
+	if err != nil {
+		return nil, err
+	}
+	if vkCurve := plonkCurveID(vk); curve != vkCurve {
+		return nil, fmt.Errorf("delegate: server proves on %s, verifying key on %s", curve, vkCurve)
+	}
+	proof := plonk.NewProof(curve)
+	if _, err := proof.ReadFrom(bytes.NewReader(proofBytes)); err != nil {
+		return nil, fmt.Errorf("delegate: read proof: %w", err)
+	}
+	if err := plonk.Verify(proof, vk, publicWitness, opts...); err != nil {
+		return nil, fmt.Errorf("delegate: verify proof: %w", err)
+	}
+	return proof, nil
+}
+
+// plonkCurveID returns the curve of the PLONK verifying key, as the interface
+// doesn't expose it.
+func plonkCurveID(vk plonk.VerifyingKey) ecc.ID {
+	switch vk.(type) {
+	case *plonk_bn254.VerifyingKey:
+		return ecc.BN254
+	case *plonk_bls12381.VerifyingKey:
+		return ecc.BLS12_381
+	case *plonk_bls12377.VerifyingKey:
+		return ecc.BLS12_377
+	case *plonk_bw6761.VerifyingKey:
+		return ecc.BW6_761
+	case *plonk_bls24317.VerifyingKey:
+		return ecc.BLS24_317
+	case *plonk_bls24315.VerifyingKey:
+		return ecc.BLS24_315
+	case *plonk_bw6633.VerifyingKey:
+		return ecc.BW6_633
+	default:
+		return ecc.UNKNOWN
+	}
+}
+
+// prove runs the protocol and returns the proof checked against the binding,
+// with the public part of the witness.
+func (c *Client) prove(ctx context.Context, id string, backendID backend.ID, fullWitness witness.Witness) (ecc.ID, []byte, witness.Witness, error) {
+	fail := func(err error) (ecc.ID, []byte, witness.Witness, error) {
+		return ecc.UNKNOWN, nil, nil, err
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return fail(fmt.Errorf("delegate: public witness: %w", err))
+	}
+	publicBytes, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return fail(fmt.Errorf("delegate: marshal public witness: %w", err))
+	}
+	plaintext, err := fullWitness.MarshalBinary()
+	if err != nil {
+		return fail(fmt.Errorf("delegate: marshal witness: %w", err))
+	}
+
+	var session sessionResponse
+	if err := c.post(ctx, sessionPath, &sessionRequest{Circuit: id}, &session); err != nil {
+		return fail(err)
+	}
+	if session.Backend != backendID {
+		return fail(fmt.Errorf("delegate: circuit %q is proved with %s, expected %s", id, session.Backend, backendID))
+	}
+	curve, err := ecc.IDFromString(session.Curve)
+	if err != nil {
+		return fail(fmt.Errorf("delegate: %w", err))
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fail(fmt.Errorf("delegate: generate key: %w", err))
+	}
+	t := transcript(id, &session, key.PublicKey().Bytes())
+	keys, err := deriveKeys(key, session.Key, t)
+	if err != nil {
+		return fail(fmt.Errorf("delegate: %w", err))
+	}
+	ciphertext, err := keys.seal(plaintext, t)
+	if err != nil {
+		return fail(fmt.Errorf("delegate: encrypt witness: %w", err))
+	}
+
+	var res proveResponse
+	req := proveRequest{Session: session.Session, Key: key.PublicKey().Bytes(), Witness: ciphertext}
+	if err := c.post(ctx, provePath, &req, &res); err != nil {
+		return fail(err)
+	}
+	if !hmac.Equal(res.Binding, keys.bind(t, res.Proof, publicBytes)) {
+		return fail(ErrBinding)
+	}
+	return curve, res.Proof, publicWitness, nil
+}
+
+// post sends the request in JSON and decodes the response.
+func (c *Client) post(ctx context.Context, path string, req, res interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("delegate: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("delegate: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpRes, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("delegate: %w", err)
+	}
+	defer httpRes.Body.Close()
+	if httpRes.StatusCode != http.StatusOK {
+		var e errorResponse
+		msg, _ := io.ReadAll(io.LimitReader(httpRes.Body, 1<<10))
+		if json.Unmarshal(msg, &e) == nil && e.Error != "" {
+			msg = []byte(e.Error)
+		}
+		return fmt.Errorf("delegate: %s: %s", httpRes.Status, msg)
+	}
+	if err := json.NewDecoder(httpRes.Body).Decode(res); err != nil {
+		return fmt.Errorf("delegate: decode response: %w", err)
+	}
+	return nil
+}
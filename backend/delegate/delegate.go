@@ -0,0 +1,158 @@
+// Package delegate implements a protocol to delegate the computation of proofs
+// to a proving service, without sending the witness in clear.
+//
+// The [Server] holds the constraint systems and proving keys of the circuits
+// it serves, and the [Client] the verifying keys. A proof is computed in two
+// requests:
+//
+//  1. the client opens a session for a circuit, and the server replies with
+//     the public key of an X25519 key pair generated for the session;
+//  2. the client encrypts its full witness with a key derived from the
+//     Diffie-Hellman secret of the server key and of its own ephemeral key,
+//     and sends the ciphertext with its public key. The server decrypts the
+//     witness, solves the constraint system and computes the proof, and
+//     replies with the proof and a MAC, keyed by the session secret, of the
+//     transcript, the proof and the public witness.
+//
+// The client checks the MAC, which binds the proof to the witness it sent, and
+// verifies the proof. A session can be used once: the keys of the session are
+// dropped by the server after the proving request, or after a timeout.
+//
+// The witness is only protected in transit: it is decrypted by the server,
+// which must be trusted with it. The protocol doesn't authenticate the server,
+// which must be done by the transport (e.g. TLS), as an active attacker could
+// otherwise open the session in place of the server.
+package delegate
+
+import (
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// protocolLabel separates the transcripts of the protocol from other uses
+	// of the keys, and is changed with incompatible versions of the protocol.
+	protocolLabel = "gnark/delegate/v1"
+
+	sessionPath = "/session"
+	provePath   = "/prove"
+)
+
+// ErrBinding is returned by the client when the response of the server is not
+// bound to the session, the proof or the public witness.
+var ErrBinding = errors.New("delegate: invalid transcript binding")
+
+type sessionRequest struct {
+	Circuit string `json:"circuit"`
+}
+
+type sessionResponse struct {
+	Session string     `json:"session"`
+	Backend backend.ID `json:"backend"`
+	Curve   string     `json:"curve"`
+	Key     []byte     `json:"key"`
+}
+
+type proveRequest struct {
+	Session string `json:"session"`
+	Key     []byte `json:"key"`
+	Witness []byte `json:"witness"`
+}
+
+type proveResponse struct {
+	Proof   []byte `json:"proof"`
+	Binding []byte `json:"binding"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// transcript returns the hash of the public data of the session, to which the
+// encryption of the witness and the binding of the proof are tied.
+func transcript(circuit string, session *sessionResponse, clientKey []byte) []byte {
+	h := sha256.New()
+	for _, b := range [][]byte{
+		[]byte(protocolLabel),
+		[]byte(circuit),
+		[]byte(session.Session),
+		[]byte(session.Backend.String()),
+		[]byte(session.Curve),
+		session.Key,
+		clientKey,
+	} {
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// sessionKeys are the keys derived from the Diffie-Hellman secret.
+type sessionKeys struct {
+	encryption []byte
+	binding    []byte
+}
+
+func deriveKeys(private *ecdh.PrivateKey, peer []byte, transcript []byte) (sessionKeys, error) {
+	peerKey, err := ecdh.X25519().NewPublicKey(peer)
+	if err != nil {
+		return sessionKeys{}, fmt.Errorf("invalid public key: %w", err)
+	}
+	secret, err := private.ECDH(peerKey)
+	if err != nil {
+		return sessionKeys{}, err
+	}
+	kdf := hkdf.New(sha256.New, secret, transcript, []byte(protocolLabel))
+	keys := sessionKeys{
+		encryption: make([]byte, chacha20poly1305.KeySize),
+		binding:    make([]byte, sha256.Size),
+	}
+	if _, err := io.ReadFull(kdf, keys.encryption); err != nil {
+		return sessionKeys{}, err
+	}
+	if _, err := io.ReadFull(kdf, keys.binding); err != nil {
+		return sessionKeys{}, err
+	}
+	return keys, nil
+}
+
+// seal encrypts the witness. Both key pairs are ephemeral, so each encryption
+// key is used once and the nonce can be fixed.
+func (k sessionKeys) seal(witness, transcript []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(k.encryption)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, make([]byte, aead.NonceSize()), witness, transcript), nil
+}
+
+func (k sessionKeys) open(ciphertext, transcript []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(k.encryption)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext, transcript)
+}
+
+// bind returns the MAC of the transcript, the proof and the public witness.
+func (k sessionKeys) bind(transcript, proof, publicWitness []byte) []byte {
+	mac := hmac.New(sha256.New, k.binding)
+	mac.Write(transcript)
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(proof)))
+	mac.Write(l[:])
+	mac.Write(proof)
+	mac.Write(publicWitness)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,147 @@
+package delegate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+func newWitness(t *testing.T, x, y int) witness.Witness {
+	w, err := frontend.NewWitness(&cubic.Circuit{X: x, Y: y}, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	return w
+}
+
+// newGroth16Server returns a server of the cubic circuit and its verifying key.
+func newGroth16Server(t *testing.T) (*Server, groth16.VerifyingKey) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	s, err := NewServer()
+	assert.NoError(err)
+	assert.NoError(s.RegisterGroth16("cubic", ccs, pk))
+	assert.Error(s.RegisterGroth16("cubic", ccs, pk))
+	return s, vk
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+	s, vk := newGroth16Server(t)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := NewClient(server.URL, server.Client())
+
+	_, err := client.ProveGroth16(context.Background(), "cubic", newWitness(t, 3, 35), vk)
+	assert.NoError(err)
+
+	_, err = client.ProveGroth16(context.Background(), "cubic", newWitness(t, 4, 35), vk)
+	assert.ErrorContains(err, "proving failed")
+
+	_, err = client.ProveGroth16(context.Background(), "unknown", newWitness(t, 3, 35), vk)
+	assert.ErrorContains(err, "unknown circuit")
+
+	_, err = client.ProvePlonk(context.Background(), "cubic", newWitness(t, 3, 35), plonk.NewVerifyingKey(ecc.BN254))
+	assert.ErrorContains(err, "is proved with groth16")
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	s, err := NewServer()
+	assert.NoError(err)
+	assert.NoError(s.RegisterPlonk("cubic", ccs, pk))
+
+	mux := http.NewServeMux()
+	mux.Handle("/delegate/", http.StripPrefix("/delegate", s))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewClient(server.URL+"/delegate/", server.Client())
+
+	_, err = client.ProvePlonk(context.Background(), "cubic", newWitness(t, 3, 35), vk)
+	assert.NoError(err)
+
+	_, err = client.ProvePlonk(context.Background(), "cubic", newWitness(t, 3, 35), plonk.NewVerifyingKey(ecc.BLS12_381))
+	assert.ErrorContains(err, "verifying key on bls12_381")
+}
+
+// recordingTransport keeps the body of the last proving request.
+type recordingTransport struct {
+	prove []byte
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == provePath {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		rt.prove = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSessionReplay(t *testing.T) {
+	assert := require.New(t)
+	s, vk := newGroth16Server(t)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	rt := &recordingTransport{}
+	client := NewClient(server.URL, &http.Client{Transport: rt})
+
+	_, err := client.ProveGroth16(context.Background(), "cubic", newWitness(t, 3, 35), vk)
+	assert.NoError(err)
+
+	res, err := http.Post(server.URL+provePath, "application/json", bytes.NewReader(rt.prove))
+	assert.NoError(err)
+	res.Body.Close()
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func TestBinding(t *testing.T) {
+	assert := require.New(t)
+	s, vk := newGroth16Server(t)
+	// the server replies with a modified proof
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != provePath {
+			s.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, r)
+		var res proveResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || len(res.Proof) == 0 {
+			t.Errorf("unexpected response %s", rec.Body.Bytes())
+			return
+		}
+		res.Proof[len(res.Proof)-1] ^= 1
+		_ = json.NewEncoder(w).Encode(&res)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, server.Client())
+
+	_, err := client.ProveGroth16(context.Background(), "cubic", newWitness(t, 3, 35), vk)
+	assert.ErrorIs(err, ErrBinding)
+}
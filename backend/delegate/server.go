@@ -0,0 +1,334 @@
+package delegate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/scheduler"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/logger"
+)
+
+// Option defines option for altering the behavior of the server. See the
+// descriptions of functions returning instances of this type for implemented
+// options.
+type Option func(*Config) error
+
+// Config is the configuration of the server with the options applied.
+type Config struct {
+	SessionTimeout time.Duration
+	MaxSessions    int
+	MaxWitnessSize int64
+	Scheduler      *scheduler.Scheduler
+	ProverOptions  []backend.ProverOption
+}
+
+// WithSessionTimeout sets the time after which an unused session expires. If
+// not set, sessions expire after a minute.
+func WithSessionTimeout(d time.Duration) Option {
+	return func(cfg *Config) error {
+		if d <= 0 {
+			return errors.New("session timeout must be positive")
+		}
+		cfg.SessionTimeout = d
+		return nil
+	}
+}
+
+// WithMaxSessions sets the maximum number of open sessions, above which new
+// sessions are rejected. If not set, at most 1024 sessions are open.
+func WithMaxSessions(n int) Option {
+	return func(cfg *Config) error {
+		if n <= 0 {
+			return errors.New("maximum number of sessions must be positive")
+		}
+		cfg.MaxSessions = n
+		return nil
+	}
+}
+
+// WithMaxWitnessSize sets the maximum size, in bytes, of an encrypted witness.
+// If not set, witnesses are limited to 64MiB.
+func WithMaxWitnessSize(bytes int64) Option {
+	return func(cfg *Config) error {
+		if bytes <= 0 {
+			return errors.New("maximum witness size must be positive")
+		}
+		cfg.MaxWitnessSize = bytes
+		return nil
+	}
+}
+
+// WithScheduler sets the scheduler admitting the proving jobs. If not set, the
+// proofs are computed as the requests arrive.
+func WithScheduler(s *scheduler.Scheduler) Option {
+	return func(cfg *Config) error {
+		cfg.Scheduler = s
+		return nil
+	}
+}
+
+// WithProverOptions sets the options of the prover.
+func WithProverOptions(opts ...backend.ProverOption) Option {
+	return func(cfg *Config) error {
+		cfg.ProverOptions = opts
+		return nil
+	}
+}
+
+type circuit struct {
+	ccs     constraint.ConstraintSystem
+	backend backend.ID
+	prove   func(ctx context.Context, fullWitness witness.Witness) (io.WriterTo, error)
+}
+
+type session struct {
+	circuitID string
+	circuit   *circuit
+	response  sessionResponse
+	key       *ecdh.PrivateKey
+	expires   time.Time
+}
+
+// Server serves the proving requests of [Client] for the registered circuits.
+// It implements [http.Handler] and is safe for concurrent use.
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+
+	lock     sync.Mutex
+	circuits map[string]*circuit
+	sessions map[string]*session
+}
+
+// NewServer returns a new server with the given options.
+func NewServer(opts ...Option) (*Server, error) {
+	cfg := Config{
+		SessionTimeout: time.Minute,
+		MaxSessions:    1024,
+		MaxWitnessSize: 64 << 20,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	s := &Server{
+		cfg:      cfg,
+		mux:      http.NewServeMux(),
+		circuits: make(map[string]*circuit),
+		sessions: make(map[string]*session),
+	}
+	s.mux.HandleFunc(sessionPath, s.handleSession)
+	s.mux.HandleFunc(provePath, s.handleProve)
+	return s, nil
+}
+
+// RegisterGroth16 serves the Groth16 proofs of the constraint system under the
+// given identifier.
+func (s *Server) RegisterGroth16(id string, ccs constraint.ConstraintSystem, pk groth16.ProvingKey) error {
+	return s.register(id, &circuit{
+		ccs:     ccs,
+		backend: backend.GROTH16,
+		prove: func(ctx context.Context, fullWitness witness.Witness) (io.WriterTo, error) {
+			if s.cfg.Scheduler != nil {
+				return s.cfg.Scheduler.ProveGroth16(ctx, ccs, pk, fullWitness, s.cfg.ProverOptions...)
+			}
+			return groth16.Prove(ccs, pk, fullWitness, s.cfg.ProverOptions...)
+		},
+	})
+}
+
+// RegisterPlonk serves the PLONK proofs of the constraint system under the
+// given identifier.
+func (s *Server) RegisterPlonk(id string, ccs constraint.ConstraintSystem, pk plonk.ProvingKey) error {
+	return s.register(id, &circuit{
+		ccs:     ccs,
+		backend: backend.PLONK,
+		prove: func(ctx context.Context, fullWitness witness.Witness) (io.WriterTo, error) {
+			if s.cfg.Scheduler != nil {
+				return s.cfg.Scheduler.ProvePlonk(ctx, ccs, pk, fullWitness, s.cfg.ProverOptions...)
+			}
+			return plonk.Prove(ccs, pk, fullWitness, s.cfg.ProverOptions...)
+		},
+	})
+}
+
+func (s *Server) register(id string, c *circuit) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.circuits[id]; ok {
+		return fmt.Errorf("circuit %q already registered", id)
+	}
+	s.circuits[id] = c
+	return nil
+}
+
+// ServeHTTP handles the requests of the protocol, at the paths /session and
+// /prove. The server can be mounted under a prefix with [http.StripPrefix].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	var req sessionRequest
+	if !decodeRequest(w, r, &req, 1<<10) {
+		return
+	}
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "generate key")
+		return
+	}
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		writeError(w, http.StatusInternalServerError, "generate session")
+		return
+	}
+
+	s.lock.Lock()
+	c, ok := s.circuits[req.Circuit]
+	if !ok {
+		s.lock.Unlock()
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown circuit %q", req.Circuit))
+		return
+	}
+	now := time.Now()
+	for k, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, k)
+		}
+	}
+	if len(s.sessions) >= s.cfg.MaxSessions {
+		s.lock.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "too many open sessions")
+		return
+	}
+	sess := &session{
+		circuitID: req.Circuit,
+		circuit:   c,
+		response: sessionResponse{
+			Session: hex.EncodeToString(id[:]),
+			Backend: c.backend,
+			Curve:   utils.FieldToCurve(c.ccs.Field()).String(),
+			Key:     key.PublicKey().Bytes(),
+		},
+		key:     key,
+		expires: now.Add(s.cfg.SessionTimeout),
+	}
+	s.sessions[sess.response.Session] = sess
+	s.lock.Unlock()
+
+	writeResponse(w, &sess.response)
+}
+
+func (s *Server) handleProve(w http.ResponseWriter, r *http.Request) {
+	var req proveRequest
+	// the ciphertext is encoded in base64
+	if !decodeRequest(w, r, &req, s.cfg.MaxWitnessSize*4/3+1<<10) {
+		return
+	}
+
+	// the session is used once, even if the request fails
+	s.lock.Lock()
+	sess, ok := s.sessions[req.Session]
+	delete(s.sessions, req.Session)
+	s.lock.Unlock()
+	if !ok || time.Now().After(sess.expires) {
+		writeError(w, http.StatusNotFound, "unknown or expired session")
+		return
+	}
+
+	t := transcript(sess.circuitID, &sess.response, req.Key)
+	keys, err := deriveKeys(sess.key, req.Key, t)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	plaintext, err := keys.open(req.Witness, t)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "decrypt witness")
+		return
+	}
+	fullWitness, err := witness.New(sess.circuit.ccs.Field())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "new witness")
+		return
+	}
+	if err := fullWitness.UnmarshalBinary(plaintext); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid witness")
+		return
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid witness")
+		return
+	}
+	publicBytes, err := publicWitness.MarshalBinary()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "marshal public witness")
+		return
+	}
+
+	proof, err := sess.circuit.prove(r.Context(), fullWitness)
+	if err != nil {
+		// the error may contain values of the witness, it is only logged
+		log := logger.Logger()
+		log.Error().Str("circuit", sess.circuitID).Err(err).Msg("delegated proving failed")
+		writeError(w, http.StatusUnprocessableEntity, "proving failed")
+		return
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		writeError(w, http.StatusInternalServerError, "marshal proof")
+		return
+	}
+	writeResponse(w, &proveResponse{
+		Proof:   buf.Bytes(),
+		Binding: keys.bind(t, buf.Bytes(), publicBytes),
+	})
+}
+
+// decodeRequest decodes the JSON body of a POST request, and replies with an
+// error if it fails.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}, maxSize int64) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSize)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("decode request: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log := logger.Logger()
+		log.Error().Err(err).Msg("write response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&errorResponse{Error: msg})
+}
@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// envelopeMagic starts the envelopes, and is changed with incompatible
+// versions of the format.
+var envelopeMagic = []byte("gnarkenv\x01")
+
+// ErrUnknownProofSystem is returned by Verify when the envelope is for a proof
+// system which is not registered, see RegisterVerifier.
+var ErrUnknownProofSystem = errors.New("unknown proof system")
+
+// EnvelopeVerifier verifies the proof of an envelope. vk, proof and
+// publicWitness are serialized with WriteTo for the keys and the proofs, and
+// MarshalBinary for the public witness.
+type EnvelopeVerifier func(curve ecc.ID, vk, proof, publicWitness []byte, opts ...VerifierOption) error
+
+var (
+	verifiers     = make(map[ID]EnvelopeVerifier)
+	verifiersLock sync.RWMutex
+)
+
+// RegisterVerifier registers the verifier of the envelopes of the proof system
+// id. The proof systems of gnark register their verifier when their package
+// is imported, so that Verify supports the proof systems imported by the
+// application.
+func RegisterVerifier(id ID, verifier EnvelopeVerifier) {
+	verifiersLock.Lock()
+	defer verifiersLock.Unlock()
+	verifiers[id] = verifier
+}
+
+// Envelope is a proof with its verifying key and its public witness, tagged
+// with its proof system and its curve, so that it is verified with Verify
+// without knowing them.
+type Envelope struct {
+	Backend       ID
+	Curve         ecc.ID
+	VerifyingKey  []byte
+	Proof         []byte
+	PublicWitness []byte
+}
+
+// NewEnvelope returns the envelope of the proof of the proof system id on the
+// curve, with its verifying key vk and its public witness, serialized with
+// MarshalBinary.
+func NewEnvelope(id ID, curve ecc.ID, vk, proof io.WriterTo, publicWitness encoding.BinaryMarshaler) ([]byte, error) {
+	var vkBuf, proofBuf bytes.Buffer
+	if _, err := vk.WriteTo(&vkBuf); err != nil {
+		return nil, fmt.Errorf("serialize verifying key: %w", err)
+	}
+	if _, err := proof.WriteTo(&proofBuf); err != nil {
+		return nil, fmt.Errorf("serialize proof: %w", err)
+	}
+	w, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("serialize public witness: %w", err)
+	}
+	e := Envelope{
+		Backend:       id,
+		Curve:         curve,
+		VerifyingKey:  vkBuf.Bytes(),
+		Proof:         proofBuf.Bytes(),
+		PublicWitness: w,
+	}
+	return e.MarshalBinary()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The envelope starts with
+// a header of the proof system and the curve, followed by the sections
+// prefixed by their length.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	res := make([]byte, 0, len(envelopeMagic)+4+3*4+len(e.VerifyingKey)+len(e.Proof)+len(e.PublicWitness))
+	res = append(res, envelopeMagic...)
+	res = binary.BigEndian.AppendUint16(res, uint16(e.Backend))
+	res = binary.BigEndian.AppendUint16(res, uint16(e.Curve))
+	for _, s := range [][]byte{e.VerifyingKey, e.Proof, e.PublicWitness} {
+		if uint64(len(s)) > 1<<32-1 {
+			return nil, errors.New("envelope section too large")
+		}
+		res = binary.BigEndian.AppendUint32(res, uint32(len(s)))
+		res = append(res, s...)
+	}
+	return res, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The sections of the
+// envelope are not copied from data.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, envelopeMagic) {
+		return errors.New("not a proof envelope")
+	}
+	data = data[len(envelopeMagic):]
+	if len(data) < 4 {
+		return io.ErrUnexpectedEOF
+	}
+	var res Envelope
+	res.Backend = ID(binary.BigEndian.Uint16(data))
+	res.Curve = ecc.ID(binary.BigEndian.Uint16(data[2:]))
+	data = data[4:]
+	for _, s := range []*[]byte{&res.VerifyingKey, &res.Proof, &res.PublicWitness} {
+		if len(data) < 4 {
+			return io.ErrUnexpectedEOF
+		}
+		n := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(n) {
+			return io.ErrUnexpectedEOF
+		}
+		*s, data = data[:n:n], data[n:]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%d trailing bytes in the envelope", len(data))
+	}
+	*e = res
+	return nil
+}
+
+// Verify verifies the proof of the envelope written by NewEnvelope, with the
+// verifier of its proof system and its curve. The proof system must be
+// registered, which is done by importing its package (for example
+// github.com/consensys/gnark/backend/groth16). It returns
+// ErrUnknownProofSystem if it isn't.
+//
+// The verifying key is read from the envelope: the caller must check that it
+// is the expected one, for example by comparing it to a trusted key, as any
+// valid proof of any circuit is accepted otherwise.
+func Verify(envelope []byte, opts ...VerifierOption) error {
+	var e Envelope
+	if err := e.UnmarshalBinary(envelope); err != nil {
+		return err
+	}
+	verifiersLock.RLock()
+	verifier, ok := verifiers[e.Backend]
+	verifiersLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownProofSystem, e.Backend)
+	}
+	if !slices.Contains(gnark.Curves(), e.Curve) {
+		return fmt.Errorf("unsupported curve %s", e.Curve)
+	}
+	return verifier(e.Curve, e.VerifyingKey, e.Proof, e.PublicWitness, opts...)
+}
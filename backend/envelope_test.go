@@ -0,0 +1,93 @@
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+type envelopeCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+}
+
+func (c *envelopeCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestEnvelope(t *testing.T) {
+	assert := require.New(t)
+
+	var envelopes [][]byte
+
+	// groth16 on BLS12-381
+	{
+		field := ecc.BLS12_381.ScalarField()
+		ccs, err := frontend.Compile(field, r1cs.NewBuilder, &envelopeCircuit{})
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&envelopeCircuit{X: 3, Y: 9}, field)
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+		public, err := w.Public()
+		assert.NoError(err)
+		envelope, err := backend.NewEnvelope(backend.GROTH16, ecc.BLS12_381, vk, proof, public)
+		assert.NoError(err)
+		envelopes = append(envelopes, envelope)
+	}
+
+	// plonk on BN254
+	{
+		field := ecc.BN254.ScalarField()
+		ccs, err := frontend.Compile(field, scs.NewBuilder, &envelopeCircuit{})
+		assert.NoError(err)
+		srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+		assert.NoError(err)
+		pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&envelopeCircuit{X: 3, Y: 9}, field)
+		assert.NoError(err)
+		proof, err := plonk.Prove(ccs, pk, w)
+		assert.NoError(err)
+		public, err := w.Public()
+		assert.NoError(err)
+		envelope, err := backend.NewEnvelope(backend.PLONK, ecc.BN254, vk, proof, public)
+		assert.NoError(err)
+		envelopes = append(envelopes, envelope)
+	}
+
+	for _, envelope := range envelopes {
+		assert.NoError(backend.Verify(envelope))
+
+		var e backend.Envelope
+		assert.NoError(e.UnmarshalBinary(envelope))
+
+		// wrong public witness
+		tampered := e
+		tampered.PublicWitness = append([]byte{}, e.PublicWitness...)
+		tampered.PublicWitness[len(tampered.PublicWitness)-1] ^= 1
+		data, err := tampered.MarshalBinary()
+		assert.NoError(err)
+		assert.Error(backend.Verify(data))
+
+		// unknown proof system
+		tampered = e
+		tampered.Backend = backend.UNKNOWN
+		data, err = tampered.MarshalBinary()
+		assert.NoError(err)
+		assert.ErrorIs(backend.Verify(data), backend.ErrUnknownProofSystem)
+
+		// truncated envelope
+		assert.Error(backend.Verify(envelope[:len(envelope)-1]))
+	}
+}
@@ -0,0 +1,71 @@
+package backend
+
+import "fmt"
+
+// VerificationCheck identifies the check of a verifier which rejected a proof.
+type VerificationCheck uint8
+
+const (
+	// CheckPublicWitness is the check that the public witness matches the
+	// verifying key. Only the size of the public witness can be checked this
+	// way: wrong values of the public inputs make the pairing equation or the
+	// quotient identity fail.
+	CheckPublicWitness VerificationCheck = iota + 1
+	// CheckProofFormat is the check that the proof is well formed: its points
+	// are in the correct subgroup and it has the number of commitments
+	// expected by the verifying key.
+	CheckProofFormat
+	// CheckCommitmentOpening is the check of the openings of the commitments
+	// of the proof (Pedersen proof of knowledge for Groth16, batched KZG
+	// opening for PLONK).
+	CheckCommitmentOpening
+	// CheckPairingEquation is the Groth16 pairing equation.
+	CheckPairingEquation
+	// CheckQuotientIdentity is the PLONK algebraic relation at the evaluation
+	// challenge, linking the quotient to the constraints.
+	CheckQuotientIdentity
+)
+
+// String returns the name of the check.
+func (c VerificationCheck) String() string {
+	switch c {
+	case CheckPublicWitness:
+		return "public witness"
+	case CheckProofFormat:
+		return "proof format"
+	case CheckCommitmentOpening:
+		return "commitment opening"
+	case CheckPairingEquation:
+		return "pairing equation"
+	case CheckQuotientIdentity:
+		return "quotient identity"
+	default:
+		return "unknown check"
+	}
+}
+
+// VerificationError is the error returned by the verifiers when a proof is
+// rejected. Check identifies the check which failed, and can be retrieved with
+// errors.As:
+//
+//	var vErr *backend.VerificationError
+//	if errors.As(err, &vErr) && vErr.Check == backend.CheckPublicWitness {
+//		// ...
+//	}
+type VerificationError struct {
+	Check VerificationCheck
+	Err   error
+}
+
+// NewVerificationError returns a [*VerificationError] for the given check.
+func NewVerificationError(check VerificationCheck, err error) *VerificationError {
+	return &VerificationError{Check: check, Err: err}
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%s check failed: %v", e.Check, e.Err)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
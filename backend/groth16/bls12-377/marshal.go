@@ -19,8 +19,13 @@ package groth16
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	"crypto/sha256"
+	"fmt"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/pedersen"
 	"github.com/consensys/gnark-crypto/utils/unsafe"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/utils"
 	"io"
 )
@@ -244,6 +249,30 @@ func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 	return pk.writeTo(w, true)
 }
 
+// resetFingerprint clears the fingerprint of the key, if it was computed,
+// before another key is read into it.
+func (pk *ProvingKey) resetFingerprint() {
+	if pk.fingerprint.Load() != nil {
+		pk.fingerprint.Store([]byte(nil))
+	}
+}
+
+// Fingerprint returns the SHA-256 hash of the raw encoding of the key. It is
+// computed on the first call and stored in the key, which must thus not be
+// modified afterwards, except by reading another key into it.
+func (pk *ProvingKey) Fingerprint() ([]byte, error) {
+	if fp, _ := pk.fingerprint.Load().([]byte); fp != nil {
+		return fp, nil
+	}
+	h := sha256.New()
+	if _, err := pk.WriteRawTo(h); err != nil {
+		return nil, err
+	}
+	fp := h.Sum(nil)
+	pk.fingerprint.Store(fp)
+	return fp, nil
+}
+
 func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	n, err := pk.Domain.WriteTo(w)
 	if err != nil {
@@ -318,6 +347,7 @@ func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 }
 
 func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	pk.resetFingerprint()
 	n, err := pk.Domain.ReadFrom(r)
 	if err != nil {
 		return n, err
@@ -449,6 +479,7 @@ func (pk *ProvingKey) WriteDump(w io.Writer) error {
 // ReadDump reads a ProvingKey from a dump written by WriteDump.
 // This is platform dependent and very unsafe (no checks, no endianness translation, etc.)
 func (pk *ProvingKey) ReadDump(r io.Reader) error {
+	pk.resetFingerprint()
 	// read the marker to fail early in case of malformed input
 	if err := unsafe.ReadMarker(r); err != nil {
 		return err
@@ -535,3 +566,92 @@ func (pk *ProvingKey) ReadDump(r io.Reader) error {
 	return nil
 
 }
+
+// checkpointName is the name of the file of the checkpoints of the prover in
+// the checkpoint directory.
+const checkpointName = "groth16-bls12-377.checkpoint"
+
+// checkpointPhase is the last phase of the prover saved in a checkpoint.
+type checkpointPhase uint8
+
+const (
+	// checkpointSolved is saved after solving the constraint system, with the
+	// commitments, the wire values and the A, B, C vectors.
+	checkpointSolved checkpointPhase = iota + 1
+	// checkpointQuotient is saved after computing the quotient H, with the
+	// commitments, the wire values and H.
+	checkpointQuotient
+)
+
+// checkpoint is the state of the prover saved with backend.WithCheckpointDir.
+type checkpoint struct {
+	phase         checkpointPhase
+	commitments   []curve.G1Affine
+	commitmentPok curve.G1Affine
+	wireValues    []fr.Element
+	a, b, c       []fr.Element
+	h             []fr.Element
+}
+
+// save writes the checkpoint to the checkpoint directory of the options, if
+// any.
+func (ck *checkpoint) save(opt *backend.ProverConfig) error {
+	if opt.CheckpointDir == "" {
+		return nil
+	}
+	return backend.WriteCheckpoint(opt.CheckpointDir, checkpointName, ck)
+}
+
+// WriteTo writes the checkpoint with the points in uncompressed form.
+func (ck *checkpoint) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	toEncode := []interface{}{
+		uint8(ck.phase),
+		ck.commitments,
+		&ck.commitmentPok,
+		ck.wireValues,
+	}
+	if ck.phase == checkpointSolved {
+		toEncode = append(toEncode, ck.a, ck.b, ck.c)
+	} else {
+		toEncode = append(toEncode, ck.h)
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads a checkpoint written by WriteTo.
+func (ck *checkpoint) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	var phase uint8
+	toDecode := []interface{}{
+		&phase,
+		&ck.commitments,
+		&ck.commitmentPok,
+		&ck.wireValues,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	ck.phase = checkpointPhase(phase)
+	switch ck.phase {
+	case checkpointSolved:
+		toDecode = []interface{}{&ck.a, &ck.b, &ck.c}
+	case checkpointQuotient:
+		toDecode = []interface{}{&ck.h}
+	default:
+		return dec.BytesRead(), fmt.Errorf("unknown checkpoint phase %d", phase)
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
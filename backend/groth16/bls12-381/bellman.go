@@ -0,0 +1,367 @@
+package groth16
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bls12-381"
+	"github.com/consensys/gnark/internal/backend/ioutils"
+)
+
+// This file converts the keys and the proofs to and from the format of
+// bellman (zcash/sapling-crypto). The points are encoded as in zcash, which is
+// also the encoding of gnark-crypto, in big-endian with the flags in the most
+// significant bits.
+//
+// The keys are interchangeable only for the same quadratic arithmetic program:
+// a bellman prover synthesizes its circuit itself, and the constraints must
+// be the ones of the gnark constraint system, in the same order. Bellman also
+// requires all the public wires to be in the A query. The Pedersen commitments
+// of gnark are not supported by bellman.
+
+// SizeOfBellmanProof is the size of a proof in the bellman format.
+const SizeOfBellmanProof = 2*curve.SizeOfG1AffineCompressed + curve.SizeOfG2AffineCompressed
+
+var errBellmanCommitments = errors.New("the bellman format doesn't support commitments")
+
+// MarshalBellman returns the proof in the bellman format: A, B and C
+// compressed.
+func (proof *Proof) MarshalBellman() ([]byte, error) {
+	if len(proof.Commitments) != 0 {
+		return nil, errBellmanCommitments
+	}
+	res := make([]byte, 0, SizeOfBellmanProof)
+	ar := proof.Ar.Bytes()
+	bs := proof.Bs.Bytes()
+	krs := proof.Krs.Bytes()
+	res = append(res, ar[:]...)
+	res = append(res, bs[:]...)
+	res = append(res, krs[:]...)
+	return res, nil
+}
+
+// UnmarshalBellman sets the proof from its bellman format.
+func (proof *Proof) UnmarshalBellman(data []byte) error {
+	if len(data) != SizeOfBellmanProof {
+		return fmt.Errorf("invalid bellman proof size %d", len(data))
+	}
+	var res Proof
+	if _, err := res.Ar.SetBytes(data[:curve.SizeOfG1AffineCompressed]); err != nil {
+		return err
+	}
+	data = data[curve.SizeOfG1AffineCompressed:]
+	if _, err := res.Bs.SetBytes(data[:curve.SizeOfG2AffineCompressed]); err != nil {
+		return err
+	}
+	data = data[curve.SizeOfG2AffineCompressed:]
+	if _, err := res.Krs.SetBytes(data); err != nil {
+		return err
+	}
+	*proof = res
+	return nil
+}
+
+// WriteBellmanTo writes the verifying key in the bellman format.
+func (vk *VerifyingKey) WriteBellmanTo(w io.Writer) (int64, error) {
+	bw := bellmanWriter{w: ioutils.WriterCounter{W: w}}
+	vk.writeBellman(&bw)
+	return bw.w.N, bw.err
+}
+
+// ReadBellmanFrom reads the verifying key in the bellman format.
+func (vk *VerifyingKey) ReadBellmanFrom(r io.Reader) (int64, error) {
+	br := bellmanReader{r: r}
+	vk.readBellman(&br)
+	if br.err != nil {
+		return br.n, br.err
+	}
+	return br.n, vk.Precompute()
+}
+
+func (vk *VerifyingKey) writeBellman(bw *bellmanWriter) {
+	if len(vk.PublicAndCommitmentCommitted) != 0 {
+		bw.err = errBellmanCommitments
+		return
+	}
+	bw.writeG1(&vk.G1.Alpha)
+	bw.writeG1(&vk.G1.Beta)
+	bw.writeG2(&vk.G2.Beta)
+	bw.writeG2(&vk.G2.Gamma)
+	bw.writeG1(&vk.G1.Delta)
+	bw.writeG2(&vk.G2.Delta)
+	bw.writeG1s(vk.G1.K)
+}
+
+func (vk *VerifyingKey) readBellman(br *bellmanReader) {
+	var res VerifyingKey
+	br.readG1(&res.G1.Alpha)
+	br.readG1(&res.G1.Beta)
+	br.readG2(&res.G2.Beta)
+	br.readG2(&res.G2.Gamma)
+	br.readG1(&res.G1.Delta)
+	br.readG2(&res.G2.Delta)
+	res.G1.K = br.readG1s()
+	if br.err == nil && len(res.G1.K) == 0 {
+		br.err = errors.New("empty IC query")
+	}
+	if br.err == nil {
+		*vk = res
+	}
+}
+
+// WriteBellmanParameters writes the proving key pk and the verifying key vk in
+// the bellman format of the parameters.
+func WriteBellmanParameters(w io.Writer, pk *ProvingKey, vk *VerifyingKey) (int64, error) {
+	if len(pk.CommitmentKeys) != 0 {
+		return 0, errBellmanCommitments
+	}
+	nbPublic := len(vk.G1.K)
+	for i := 0; i < nbPublic && i < len(pk.InfinityA); i++ {
+		if pk.InfinityA[i] {
+			return 0, fmt.Errorf("public wire %d isn't in the A query, as required by bellman", i)
+		}
+	}
+
+	// bellman's H query is in the natural order, when gnark's is bit-reversed.
+	h := make([]curve.G1Affine, len(pk.G1.Z))
+	nn := bits.UintSize - bits.TrailingZeros64(pk.Domain.Cardinality)
+	for i := range h {
+		h[i] = pk.G1.Z[bits.Reverse(uint(i))>>nn]
+	}
+
+	bw := bellmanWriter{w: ioutils.WriterCounter{W: w}}
+	vk.writeBellman(&bw)
+	bw.writeG1s(h)
+	bw.writeG1s(pk.G1.K)
+	bw.writeG1s(pk.G1.A)
+	bw.writeG1s(pk.G1.B)
+	bw.writeG2s(pk.G2.B)
+	return bw.w.N, bw.err
+}
+
+// ReadBellmanParameters reads the parameters of r1cs in the bellman format, and
+// returns the corresponding proving and verifying keys.
+func ReadBellmanParameters(r io.Reader, r1cs *cs.R1CS) (*ProvingKey, *VerifyingKey, error) {
+	if commitmentInfo, ok := r1cs.CommitmentInfo.(constraint.Groth16Commitments); ok && len(commitmentInfo) != 0 {
+		return nil, nil, errBellmanCommitments
+	}
+
+	var (
+		pk ProvingKey
+		vk VerifyingKey
+	)
+	br := bellmanReader{r: r}
+	vk.readBellman(&br)
+	h := br.readG1s()
+	pk.G1.K = br.readG1s()
+	pk.G1.A = br.readG1s()
+	pk.G1.B = br.readG1s()
+	pk.G2.B = br.readG2s()
+	if br.err != nil {
+		return nil, nil, br.err
+	}
+
+	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
+	nbPublic := r1cs.GetNbPublicVariables()
+	if len(vk.G1.K) != nbPublic {
+		return nil, nil, fmt.Errorf("IC query of %d points, the constraint system has %d public wires", len(vk.G1.K), nbPublic)
+	}
+	if len(pk.G1.K) != nbWires-nbPublic {
+		return nil, nil, fmt.Errorf("L query of %d points, the constraint system has %d private wires", len(pk.G1.K), nbWires-nbPublic)
+	}
+
+	// the points at infinity are filtered from the A and B queries; they
+	// correspond to the wires which aren't in the left or the right terms of
+	// the constraints.
+	pk.InfinityA = make([]bool, nbWires)
+	pk.InfinityB = make([]bool, nbWires)
+	for i := range pk.InfinityA {
+		pk.InfinityA[i] = true
+		pk.InfinityB[i] = true
+	}
+	it := r1cs.GetR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		for _, t := range c.L {
+			pk.InfinityA[t.WireID()] = false
+		}
+		for _, t := range c.R {
+			pk.InfinityB[t.WireID()] = false
+		}
+	}
+	for i := 0; i < nbWires; i++ {
+		if pk.InfinityA[i] {
+			pk.NbInfinityA++
+		}
+		if pk.InfinityB[i] {
+			pk.NbInfinityB++
+		}
+	}
+	if uint64(len(pk.G1.A)) != uint64(nbWires)-pk.NbInfinityA {
+		return nil, nil, fmt.Errorf("A query of %d points, expected %d", len(pk.G1.A), uint64(nbWires)-pk.NbInfinityA)
+	}
+	if uint64(len(pk.G1.B)) != uint64(nbWires)-pk.NbInfinityB || len(pk.G2.B) != len(pk.G1.B) {
+		return nil, nil, fmt.Errorf("B queries of %d and %d points, expected %d", len(pk.G1.B), len(pk.G2.B), uint64(nbWires)-pk.NbInfinityB)
+	}
+
+	// the H query has one point less than the size of the domain
+	cardinality := uint64(len(h) + 1)
+	if cardinality&(cardinality-1) != 0 || cardinality < uint64(r1cs.GetNbConstraints()) {
+		return nil, nil, fmt.Errorf("H query of %d points doesn't match the constraint system", len(h))
+	}
+	pk.Domain = *fft.NewDomain(cardinality)
+	pk.G1.Z = make([]curve.G1Affine, len(h))
+	nn := bits.UintSize - bits.TrailingZeros64(cardinality)
+	for i := range h {
+		pk.G1.Z[bits.Reverse(uint(i))>>nn] = h[i]
+	}
+
+	pk.G1.Alpha = vk.G1.Alpha
+	pk.G1.Beta = vk.G1.Beta
+	pk.G1.Delta = vk.G1.Delta
+	pk.G2.Beta = vk.G2.Beta
+	pk.G2.Delta = vk.G2.Delta
+	if err := vk.Precompute(); err != nil {
+		return nil, nil, err
+	}
+	return &pk, &vk, nil
+}
+
+// bellmanWriter writes the points in the bellman format. It records the first
+// error, and the number of bytes written.
+type bellmanWriter struct {
+	w   ioutils.WriterCounter
+	err error
+}
+
+func (bw *bellmanWriter) write(b []byte) {
+	if bw.err == nil {
+		_, bw.err = bw.w.Write(b)
+	}
+}
+
+func (bw *bellmanWriter) writeLen(n int) {
+	if n > 1<<32-1 {
+		bw.err = fmt.Errorf("%d points exceed the bellman format", n)
+		return
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n))
+	bw.write(b[:])
+}
+
+func (bw *bellmanWriter) writeG1(p *curve.G1Affine) {
+	b := p.RawBytes()
+	bw.write(b[:])
+}
+
+func (bw *bellmanWriter) writeG2(p *curve.G2Affine) {
+	b := p.RawBytes()
+	bw.write(b[:])
+}
+
+// writeG1s writes the length of points and the points, which bellman requires
+// not to be at infinity.
+func (bw *bellmanWriter) writeG1s(points []curve.G1Affine) {
+	bw.writeLen(len(points))
+	for i := range points {
+		if points[i].IsInfinity() && bw.err == nil {
+			bw.err = errors.New("point at infinity in a query")
+		}
+		bw.writeG1(&points[i])
+	}
+}
+
+func (bw *bellmanWriter) writeG2s(points []curve.G2Affine) {
+	bw.writeLen(len(points))
+	for i := range points {
+		if points[i].IsInfinity() && bw.err == nil {
+			bw.err = errors.New("point at infinity in a query")
+		}
+		bw.writeG2(&points[i])
+	}
+}
+
+// bellmanReader reads the points in the bellman format, and checks that they
+// are in the subgroups. It records the first error, and the number of bytes
+// read.
+type bellmanReader struct {
+	r   io.Reader
+	n   int64
+	err error
+	buf [curve.SizeOfG2AffineUncompressed]byte
+}
+
+func (br *bellmanReader) read(size int) []byte {
+	if br.err != nil {
+		return nil
+	}
+	n, err := io.ReadFull(br.r, br.buf[:size])
+	br.n += int64(n)
+	br.err = err
+	return br.buf[:size]
+}
+
+func (br *bellmanReader) readG1(p *curve.G1Affine) {
+	b := br.read(curve.SizeOfG1AffineUncompressed)
+	if br.err == nil && b[0]&0x80 != 0 {
+		br.err = errors.New("compressed point in the bellman parameters")
+	}
+	if br.err == nil {
+		_, br.err = p.SetBytes(b)
+	}
+}
+
+func (br *bellmanReader) readG2(p *curve.G2Affine) {
+	b := br.read(curve.SizeOfG2AffineUncompressed)
+	if br.err == nil && b[0]&0x80 != 0 {
+		br.err = errors.New("compressed point in the bellman parameters")
+	}
+	if br.err == nil {
+		_, br.err = p.SetBytes(b)
+	}
+}
+
+func (br *bellmanReader) readLen() int {
+	b := br.read(4)
+	if br.err != nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(b))
+}
+
+// readG1s reads a query written by bellmanWriter.writeG1s.
+func (br *bellmanReader) readG1s() []curve.G1Affine {
+	n := br.readLen()
+	// the points are appended, so that a corrupted length doesn't allocate
+	// more than the points read.
+	var res []curve.G1Affine
+	for i := 0; i < n && br.err == nil; i++ {
+		var p curve.G1Affine
+		br.readG1(&p)
+		if br.err == nil && p.IsInfinity() {
+			br.err = errors.New("point at infinity in a query")
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+func (br *bellmanReader) readG2s() []curve.G2Affine {
+	n := br.readLen()
+	var res []curve.G2Affine
+	for i := 0; i < n && br.err == nil; i++ {
+		var p curve.G2Affine
+		br.readG2(&p)
+		if br.err == nil && p.IsInfinity() {
+			br.err = errors.New("point at infinity in a query")
+		}
+		res = append(res, p)
+	}
+	return res
+}
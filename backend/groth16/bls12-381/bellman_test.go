@@ -0,0 +1,78 @@
+package groth16_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type bellmanCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+	W    frontend.Variable
+}
+
+func (c *bellmanCircuit) Define(api frontend.API) error {
+	// the public wires are in the A query, as bellman requires
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Mul(c.Y, c.W), api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestBellman(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &bellmanCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	// round trip of the keys through the bellman format
+	var params bytes.Buffer
+	n, err := groth16_bls12381.WriteBellmanParameters(&params, pk.(*groth16_bls12381.ProvingKey), vk.(*groth16_bls12381.VerifyingKey))
+	assert.NoError(err)
+	assert.EqualValues(params.Len(), n)
+	pkBellman, vkBellman, err := groth16_bls12381.ReadBellmanParameters(bytes.NewReader(params.Bytes()), ccs.(*cs_bls12381.R1CS))
+	assert.NoError(err)
+
+	var vkBytes bytes.Buffer
+	_, err = vk.(*groth16_bls12381.VerifyingKey).WriteBellmanTo(&vkBytes)
+	assert.NoError(err)
+	assert.True(bytes.HasPrefix(params.Bytes(), vkBytes.Bytes()))
+	assert.Equal(curve.SizeOfG1AffineUncompressed*3+curve.SizeOfG2AffineUncompressed*3+4+3*curve.SizeOfG1AffineUncompressed, vkBytes.Len())
+	var vkRead groth16_bls12381.VerifyingKey
+	_, err = vkRead.ReadBellmanFrom(&vkBytes)
+	assert.NoError(err)
+	assert.Equal(vk.(*groth16_bls12381.VerifyingKey).G1.K, vkRead.G1.K)
+
+	// the converted keys prove and verify
+	w, err := frontend.NewWitness(&bellmanCircuit{X: 2, Y: 3, W: 5}, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pkBellman, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, public))
+	assert.NoError(groth16.Verify(proof, vkBellman, public))
+
+	// round trip of the proof through the bellman format
+	data, err := proof.(*groth16_bls12381.Proof).MarshalBellman()
+	assert.NoError(err)
+	assert.Len(data, groth16_bls12381.SizeOfBellmanProof)
+	var proofRead groth16_bls12381.Proof
+	assert.NoError(proofRead.UnmarshalBellman(data))
+	assert.NoError(groth16.Verify(&proofRead, &vkRead, public))
+
+	// the parameters are bound to the constraint system
+	other, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &singleSecretCommittedCircuit{})
+	assert.NoError(err)
+	_, _, err = groth16_bls12381.ReadBellmanParameters(bytes.NewReader(params.Bytes()), other.(*cs_bls12381.R1CS))
+	assert.Error(err)
+}
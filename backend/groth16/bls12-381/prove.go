@@ -17,6 +17,7 @@
 package groth16
 
 import (
+	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
@@ -32,6 +33,7 @@ import (
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
 	"math/big"
 	"runtime"
 	"time"
@@ -76,6 +78,10 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	proof := &Proof{Commitments: make([]curve.G1Affine, len(commitmentInfo))}
 
 	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
+	if opt.SideChannelHardening {
+		solverOpts = append(solverOpts, solver.WithConstantTime())
+	}
+	solverOpts = append(solverOpts, solver.WithContext(opt.Context))
 
 	privateCommittedValues := make([][]fr.Element, len(commitmentInfo))
 
@@ -92,7 +98,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		}
 
 		var err error
-		if proof.Commitments[i], err = pk.CommitmentKeys[i].Commit(privateCommittedValues[i]); err != nil {
+		if proof.Commitments[i], err = commit(&pk.CommitmentKeys[i], privateCommittedValues[i], opt.SideChannelHardening); err != nil {
 			return err
 		}
 
@@ -117,8 +123,6 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	solution := _solution.(*cs.R1CSSolution)
 	wireValues := []fr.Element(solution.W)
 
-	start := time.Now()
-
 	commitmentsSerialized := make([]byte, fr.Bytes*len(commitmentInfo))
 	for i := range commitmentInfo {
 		copy(commitmentsSerialized[fr.Bytes*i:], wireValues[commitmentInfo[i].CommitmentIndex].Marshal())
@@ -128,15 +132,93 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		return nil, err
 	}
 
+	ck := &checkpoint{
+		phase:         checkpointSolved,
+		commitments:   proof.Commitments,
+		commitmentPok: proof.CommitmentPok,
+		wireValues:    wireValues,
+		a:             solution.A,
+		b:             solution.B,
+		c:             solution.C,
+	}
+	solution.A = nil
+	solution.B = nil
+	solution.C = nil
+	if err := ck.save(&opt); err != nil {
+		return nil, err
+	}
+
+	return proveFromCheckpoint(r1cs, pk, ck, &opt, log)
+}
+
+// ResumeProve completes the proof from the checkpoint saved by [Prove] in the
+// directory set with backend.WithCheckpointDir, after a crash or a
+// preemption. It returns backend.ErrNoCheckpoint if there is no checkpoint to
+// resume from. The constraint system and the proving key must be the ones of
+// the interrupted proof and the other prover options are applied as in Prove.
+func ResumeProve(r1cs *cs.R1CS, pk *ProvingKey, opts ...backend.ProverOption) (*Proof, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new prover config: %w", err)
+	}
+	if opt.CheckpointDir == "" {
+		return nil, errors.New("no checkpoint directory")
+	}
+
+	var ck checkpoint
+	if err := backend.ReadCheckpoint(opt.CheckpointDir, checkpointName, &ck); err != nil {
+		return nil, err
+	}
+	nbInternal, nbSecret, nbPublic := r1cs.GetNbVariables()
+	if len(ck.wireValues) != nbInternal+nbSecret+nbPublic || len(ck.commitments) != len(pk.CommitmentKeys) {
+		return nil, errors.New("checkpoint doesn't match the constraint system")
+	}
+	if ck.phase == checkpointQuotient && uint64(len(ck.h)) != pk.Domain.Cardinality {
+		return nil, errors.New("checkpoint doesn't match the proving key")
+	}
+
+	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Str("acceleration", "none").Int("nbConstraints", r1cs.GetNbConstraints()).Str("backend", "groth16").Logger()
+	log.Debug().Uint8("phase", uint8(ck.phase)).Msg("resuming from checkpoint")
+
+	return proveFromCheckpoint(r1cs, pk, &ck, &opt, log)
+}
+
+// proveFromCheckpoint computes the proof from the solution of the constraint
+// system and, from the phase checkpointQuotient, the quotient H.
+func proveFromCheckpoint(r1cs *cs.R1CS, pk *ProvingKey, ck *checkpoint, opt *backend.ProverConfig, log zerolog.Logger) (*Proof, error) {
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+	proof := &Proof{Commitments: ck.commitments, CommitmentPok: ck.commitmentPok}
+	wireValues := ck.wireValues
+
+	// the buffers of the prover are taken from the pool of the solver, if any
+	solverConfig, err := solver.NewConfig(opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	pool := solverConfig.Pool
+
+	start := time.Now()
+
 	// H (witness reduction / FFT part)
 	var h []fr.Element
-	chHDone := make(chan struct{}, 1)
+	chHDone := make(chan error, 1)
 	go func() {
-		h = computeH(solution.A, solution.B, solution.C, &pk.Domain)
-		solution.A = nil
-		solution.B = nil
-		solution.C = nil
-		chHDone <- struct{}{}
+		if ck.phase == checkpointQuotient {
+			h = ck.h
+			chHDone <- nil
+			return
+		}
+		if err := opt.Context.Err(); err != nil {
+			chHDone <- err
+			return
+		}
+		b, c := ck.b, ck.c
+		h = computeH(ck.a, b, c, &pk.Domain)
+		ck.phase, ck.h = checkpointQuotient, h
+		ck.a, ck.b, ck.c = nil, nil, nil
+		solver.PutBuffer(pool, b)
+		solver.PutBuffer(pool, c)
+		chHDone <- ck.save(opt)
 	}()
 
 	// we need to copy and filter the wireValues for each multi exp
@@ -145,7 +227,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chWireValuesA, chWireValuesB := make(chan struct{}, 1), make(chan struct{}, 1)
 
 	go func() {
-		wireValuesA = make([]fr.Element, len(wireValues)-int(pk.NbInfinityA))
+		wireValuesA = solver.GetBuffer[fr.Element](pool, len(wireValues)-int(pk.NbInfinityA), 0)
 		for i, j := 0, 0; j < len(wireValuesA); i++ {
 			if pk.InfinityA[i] {
 				continue
@@ -156,7 +238,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		close(chWireValuesA)
 	}()
 	go func() {
-		wireValuesB = make([]fr.Element, len(wireValues)-int(pk.NbInfinityB))
+		wireValuesB = solver.GetBuffer[fr.Element](pool, len(wireValues)-int(pk.NbInfinityB), 0)
 		for i, j := 0, 0; j < len(wireValuesB); i++ {
 			if pk.InfinityB[i] {
 				continue
@@ -191,7 +273,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&bs1, pk.G1.B, wireValuesB, opt.SideChannelHardening, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -204,7 +286,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&ar, pk.G1.A, wireValuesA, opt.SideChannelHardening, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -224,7 +306,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		chKrs2Done := make(chan error, 1)
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
+			err := multiExpG1(&krs2, pk.G1.Z, h[:sizeH], opt.SideChannelHardening, ecc.MultiExpConfig{NbTasks: n / 2})
 			chKrs2Done <- err
 		}()
 
@@ -234,7 +316,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
 		_wireValues := filterHeap(wireValues[r1cs.GetNbPublicVariables():], r1cs.GetNbPublicVariables(), internal.ConcatAll(toRemove...))
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&krs, pk.G1.K, _wireValues, opt.SideChannelHardening, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chKrsDone <- err
 			return
 		}
@@ -280,7 +362,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		if err := multiExpG2(&Bs, pk.G2.B, wireValuesB, opt.SideChannelHardening, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
 			return err
 		}
 
@@ -294,7 +376,14 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	// wait for FFT to end, as it uses all our CPUs
-	<-chHDone
+	select {
+	case err := <-chHDone:
+		if err != nil {
+			return nil, err
+		}
+	case <-opt.Context.Done():
+		return nil, opt.Context.Err()
+	}
 
 	// schedule our proof part computations
 	go computeKRS()
@@ -304,8 +393,22 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		return nil, err
 	}
 
-	// wait for all parts of the proof to be computed.
-	if err := <-chKrsDone; err != nil {
+	// wait for all parts of the proof to be computed. If the context is done,
+	// the buffers used by the multi-exponentiations are not reused.
+	select {
+	case err := <-chKrsDone:
+		if err != nil {
+			return nil, err
+		}
+	case <-opt.Context.Done():
+		return nil, opt.Context.Err()
+	}
+
+	for _, b := range [][]fr.Element{wireValues, wireValuesA, wireValuesB, h} {
+		solver.PutBuffer(pool, b)
+	}
+
+	if err := backend.RemoveCheckpoint(opt, checkpointName); err != nil {
 		return nil, err
 	}
 
@@ -314,6 +417,86 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	return proof, nil
 }
 
+// blindScalars returns random ρᵢ and sᵢ-ρᵢ for the scalars sᵢ. The buckets of
+// each of the multi-scalar multiplications by ρ and s-ρ are independent of s,
+// and their sum is the multiplication by s. Observing the buckets of both
+// reveals s, see [backend.WithSideChannelHardening].
+func blindScalars(scalars []fr.Element) (rho, masked []fr.Element, err error) {
+	rho = make([]fr.Element, len(scalars))
+	masked = make([]fr.Element, len(scalars))
+	for i := range scalars {
+		if _, err = rho[i].SetRandom(); err != nil {
+			return nil, nil, err
+		}
+		masked[i].Sub(&scalars[i], &rho[i])
+	}
+	return rho, masked, nil
+}
+
+// multiExpG1 sets p to the multi-scalar multiplication of the points by the
+// scalars, with blinded scalars if requested (see blindScalars).
+func multiExpG1(p *curve.G1Jac, points []curve.G1Affine, scalars []fr.Element, blinded bool, config ecc.MultiExpConfig) error {
+	if !blinded {
+		_, err := p.MultiExp(points, scalars, config)
+		return err
+	}
+	rho, masked, err := blindScalars(scalars)
+	if err != nil {
+		return err
+	}
+	var q curve.G1Jac
+	if _, err := p.MultiExp(points, masked, config); err != nil {
+		return err
+	}
+	if _, err := q.MultiExp(points, rho, config); err != nil {
+		return err
+	}
+	p.AddAssign(&q)
+	return nil
+}
+
+// multiExpG2 is multiExpG1 in G2.
+func multiExpG2(p *curve.G2Jac, points []curve.G2Affine, scalars []fr.Element, blinded bool, config ecc.MultiExpConfig) error {
+	if !blinded {
+		_, err := p.MultiExp(points, scalars, config)
+		return err
+	}
+	rho, masked, err := blindScalars(scalars)
+	if err != nil {
+		return err
+	}
+	var q curve.G2Jac
+	if _, err := p.MultiExp(points, masked, config); err != nil {
+		return err
+	}
+	if _, err := q.MultiExp(points, rho, config); err != nil {
+		return err
+	}
+	p.AddAssign(&q)
+	return nil
+}
+
+// commit returns the Pedersen commitment to the values, computed with blinded
+// values if requested (see blindScalars).
+func commit(pk *pedersen.ProvingKey, values []fr.Element, blinded bool) (curve.G1Affine, error) {
+	if !blinded {
+		return pk.Commit(values)
+	}
+	rho, masked, err := blindScalars(values)
+	if err != nil {
+		return curve.G1Affine{}, err
+	}
+	c1, err := pk.Commit(masked)
+	if err != nil {
+		return curve.G1Affine{}, err
+	}
+	c2, err := pk.Commit(rho)
+	if err != nil {
+		return curve.G1Affine{}, err
+	}
+	return *c1.Add(&c1, &c2), nil
+}
+
 // if len(toRemove) == 0, returns slice
 // else, returns a new slice without the indexes in toRemove. The first value in the slice is taken as indexes as sliceFirstIndex
 // this assumes len(slice) > len(toRemove)
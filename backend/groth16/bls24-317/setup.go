@@ -28,6 +28,7 @@ import (
 	cs "github.com/consensys/gnark/constraint/bls24-317"
 	"math/big"
 	"math/bits"
+	"sync/atomic"
 )
 
 // ProvingKey is used by a Groth16 prover to encode a proof of a statement
@@ -55,6 +56,9 @@ type ProvingKey struct {
 	NbInfinityA, NbInfinityB uint64
 
 	CommitmentKeys []pedersen.ProvingKey
+
+	// hash of the raw encoding of the key, see Fingerprint
+	fingerprint atomic.Value // []byte
 }
 
 // VerifyingKey is used by a Groth16 verifier to verify the validity of a proof and a statement
@@ -0,0 +1,95 @@
+package collaborative_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/groth16/bn254/collaborative"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	api.AssertIsDifferent(c.X, 0)
+	return nil
+}
+
+func TestProve(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := w.Public()
+	assert.NoError(err)
+
+	for _, nbParties := range []int{1, 3} {
+		nbParties := nbParties
+		assert.Run(func(assert *test.Assert) {
+			shares, err := collaborative.Share(ccs.(*cs.R1CS), w, nbParties)
+			assert.NoError(err)
+			triples, err := collaborative.GenerateTriples(pk.(*groth16_bn254.ProvingKey), nbParties)
+			assert.NoError(err)
+
+			transports := collaborative.NewLocalTransports(nbParties)
+			proofs := make([]*groth16_bn254.Proof, nbParties)
+			errs := make([]error, nbParties)
+			var wg sync.WaitGroup
+			for i := 0; i < nbParties; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					proofs[i], errs[i] = collaborative.Prove(transports[i], ccs.(*cs.R1CS), pk.(*groth16_bn254.ProvingKey), shares[i], triples[i])
+				}(i)
+			}
+			wg.Wait()
+
+			for i := range proofs {
+				assert.NoError(errs[i])
+				assert.NoError(groth16.Verify(proofs[i], vk, pubWitness))
+				assert.Equal(proofs[0].Ar, proofs[i].Ar)
+				assert.Equal(proofs[0].Krs, proofs[i].Krs)
+			}
+		}, fmt.Sprintf("parties=%d", nbParties))
+	}
+}
+
+func TestShareSerialization(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	shares, err := collaborative.Share(ccs.(*cs.R1CS), w, 2)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = shares[1].WriteTo(&buf)
+	assert.NoError(err)
+	var read collaborative.WitnessShare
+	_, err = read.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(*shares[1], read)
+
+	// the owner of the witness solves the circuit before sharing it
+	wrong, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 36}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = collaborative.Share(ccs.(*cs.R1CS), wrong, 2)
+	assert.Error(err)
+}
@@ -0,0 +1,21 @@
+// Package collaborative implements a Groth16 prover for BN254 run as a secure
+// multi-party computation, in the spirit of zk-SaaS
+// (https://eprint.iacr.org/2023/905).
+//
+// The owner of the witness solves the circuit and splits the solution into
+// additive shares with [Share], one per party. The parties then run [Prove]
+// together, exchanging messages through a [Transport], and all obtain the same
+// proof, which is a regular Groth16 proof verified by groth16.Verify. No party
+// learns more about the witness than what the proof reveals, as long as at
+// least one party is honest and the parties follow the protocol (semi-honest
+// model).
+//
+// The multi-scalar multiplications and the FFTs of the prover are linear and
+// are computed locally on the shares. The only non-linear steps, the product
+// A·B of the quotient and the product r·s of the blinding factors, use Beaver
+// triples. The triples are generated beforehand by a dealer with
+// [GenerateTriples]; the dealer must not collude with the parties and must
+// not reuse triples across proofs.
+//
+// Circuits with BSB22 commitments (api.Commit) are not supported.
+package collaborative
@@ -0,0 +1,244 @@
+package collaborative
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Prove computes a Groth16 proof of the circuit together with the other
+// parties connected by t. share is the share of the solution of the party,
+// returned by [Share], and triples its share of fresh Beaver triples, returned
+// by [GenerateTriples]. All the parties must call Prove with the same circuit
+// and proving key, and they all obtain the same proof.
+//
+// The protocol runs in three rounds of communication: the opening of the
+// masked operands of the Beaver multiplications, the opening of Ar and Bs,
+// and the opening of Krs. Each opened share is masked by a uniformly random
+// value unknown to the other parties.
+func Prove(t Transport, r1cs *cs.R1CS, pk *groth16_bn254.ProvingKey, share *WitnessShare, triples *Triples) (*groth16_bn254.Proof, error) {
+	if len(r1cs.CommitmentInfo.(constraint.Groth16Commitments)) != 0 {
+		return nil, errors.New("circuits with commitments are not supported")
+	}
+	nbPublic := r1cs.GetNbPublicVariables()
+	nbWires := nbPublic + r1cs.GetNbSecretVariables() + r1cs.GetNbInternalVariables()
+	nbConstraints := r1cs.GetNbConstraints()
+	if len(share.W) != nbWires || len(share.A) != nbConstraints || len(share.B) != nbConstraints || len(share.C) != nbConstraints {
+		return nil, errors.New("the share does not match the circuit")
+	}
+	n := int(pk.Domain.Cardinality)
+	if len(triples.A) != nbTriples(pk) || len(triples.B) != nbTriples(pk) || len(triples.C) != nbTriples(pk) {
+		return nil, fmt.Errorf("expected %d triples", nbTriples(pk))
+	}
+	first := t.Party() == 0
+
+	// shares of the blinding factors r and s
+	var r, s fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := s.SetRandom(); err != nil {
+		return nil, err
+	}
+
+	// round 1: products a·b on the coset and r·s
+	a, b, c := onCoset(share.A, &pk.Domain), onCoset(share.B, &pk.Domain), onCoset(share.C, &pk.Domain)
+	x, y := append(a, r), append(b, s)
+	xy, err := multiply(t, x, y, triples)
+	if err != nil {
+		return nil, fmt.Errorf("beaver multiplication: %w", err)
+	}
+	rs := xy[n]
+
+	// h = (a·b - c) / Z on the coset, where Z is constant
+	var den, one fr.Element
+	one.SetOne()
+	den.Exp(pk.Domain.FrMultiplicativeGen, big.NewInt(int64(n)))
+	den.Sub(&den, &one).Inverse(&den)
+	h := xy[:n]
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			h[i].Sub(&h[i], &c[i]).Mul(&h[i], &den)
+		}
+	})
+	pk.Domain.FFTInverse(h, fft.DIF, fft.OnCoset())
+
+	// the multi-scalar multiplications are linear in the shares
+	wireValuesA := filter(share.W, pk.InfinityA, pk.NbInfinityA)
+	wireValuesB := filter(share.W, pk.InfinityB, pk.NbInfinityB)
+	config := ecc.MultiExpConfig{NbTasks: runtime.NumCPU()}
+
+	var _r, _s big.Int
+	r.BigInt(&_r)
+	s.BigInt(&_s)
+
+	var ar, bs1, tmp curve.G1Affine
+	var bs, tmp2 curve.G2Affine
+	if _, err := ar.MultiExp(pk.G1.A, wireValuesA, config); err != nil {
+		return nil, err
+	}
+	ar.Add(&ar, tmp.ScalarMultiplication(&pk.G1.Delta, &_r))
+	if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, config); err != nil {
+		return nil, err
+	}
+	bs1.Add(&bs1, tmp.ScalarMultiplication(&pk.G1.Delta, &_s))
+	if _, err := bs.MultiExp(pk.G2.B, wireValuesB, config); err != nil {
+		return nil, err
+	}
+	bs.Add(&bs, tmp2.ScalarMultiplication(&pk.G2.Delta, &_s))
+	if first {
+		ar.Add(&ar, &pk.G1.Alpha)
+		bs1.Add(&bs1, &pk.G1.Beta)
+		bs.Add(&bs, &pk.G2.Beta)
+	}
+
+	var krs curve.G1Affine
+	if _, err := krs.MultiExp(pk.G1.K, share.W[nbPublic:], config); err != nil {
+		return nil, err
+	}
+	// deg(H) = (n-1)+(n-1)-n = n-2
+	if _, err := tmp.MultiExp(pk.G1.Z, h[:n-1], config); err != nil {
+		return nil, err
+	}
+	krs.Add(&krs, &tmp)
+	var _rs big.Int
+	rs.BigInt(&_rs)
+	krs.Sub(&krs, tmp.ScalarMultiplication(&pk.G1.Delta, &_rs))
+
+	// round 2: open Ar, Bs and its counterpart in G1
+	g1, g2, err := open(t, []curve.G1Affine{ar, bs1}, []curve.G2Affine{bs})
+	if err != nil {
+		return nil, fmt.Errorf("open Ar and Bs: %w", err)
+	}
+	proof := &groth16_bn254.Proof{Ar: g1[0], Bs: g2[0]}
+
+	// round 3: open Krs = K + H·Z + s·Ar + r·Bs - r·s·δ
+	krs.Add(&krs, tmp.ScalarMultiplication(&g1[0], &_s))
+	krs.Add(&krs, tmp.ScalarMultiplication(&g1[1], &_r))
+	if g1, _, err = open(t, []curve.G1Affine{krs}, nil); err != nil {
+		return nil, fmt.Errorf("open Krs: %w", err)
+	}
+	proof.Krs = g1[0]
+
+	return proof, nil
+}
+
+// onCoset returns the evaluations on the coset of the domain of the
+// polynomial interpolating the values on the domain, as in the groth16 prover.
+func onCoset(values fr.Vector, domain *fft.Domain) fr.Vector {
+	res := make(fr.Vector, domain.Cardinality)
+	copy(res, values)
+	domain.FFTInverse(res, fft.DIF)
+	domain.FFT(res, fft.DIT, fft.OnCoset())
+	return res
+}
+
+// filter returns the values for which infinity is false.
+func filter(values fr.Vector, infinity []bool, nbInfinity uint64) fr.Vector {
+	res := make(fr.Vector, 0, len(values)-int(nbInfinity))
+	for i := range values {
+		if !infinity[i] {
+			res = append(res, values[i])
+		}
+	}
+	return res
+}
+
+// multiply returns the shares of the products x[i]·y[i], consuming the Beaver
+// triples: the parties open d = x-a and e = y-b, and x·y = c + d·b + e·a + d·e.
+func multiply(t Transport, x, y fr.Vector, triples *Triples) (fr.Vector, error) {
+	n := len(x)
+	masked := make(fr.Vector, 2*n)
+	for i := 0; i < n; i++ {
+		masked[i].Sub(&x[i], &triples.A[i])
+		masked[n+i].Sub(&y[i], &triples.B[i])
+	}
+	msg, err := masked.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := t.Exchange(msg)
+	if err != nil {
+		return nil, err
+	}
+	opened := make(fr.Vector, 2*n)
+	for i, m := range msgs {
+		received := masked
+		if i != t.Party() {
+			received = nil
+			if err := received.UnmarshalBinary(m); err != nil {
+				return nil, fmt.Errorf("party %d: %w", i, err)
+			}
+			if len(received) != len(masked) {
+				return nil, fmt.Errorf("party %d: expected %d scalars, got %d", i, len(masked), len(received))
+			}
+		}
+		for j := range opened {
+			opened[j].Add(&opened[j], &received[j])
+		}
+	}
+	d, e := opened[:n], opened[n:]
+
+	res := make(fr.Vector, n)
+	first := t.Party() == 0
+	var tmp fr.Element
+	for i := 0; i < n; i++ {
+		res[i].Mul(&d[i], &triples.B[i])
+		tmp.Mul(&e[i], &triples.A[i])
+		res[i].Add(&res[i], &tmp).Add(&res[i], &triples.C[i])
+		if first {
+			tmp.Mul(&d[i], &e[i])
+			res[i].Add(&res[i], &tmp)
+		}
+	}
+	return res, nil
+}
+
+// open returns the sums over all the parties of the shares of points.
+func open(t Transport, g1 []curve.G1Affine, g2 []curve.G2Affine) ([]curve.G1Affine, []curve.G2Affine, error) {
+	msg := make([]byte, 0, len(g1)*curve.SizeOfG1AffineUncompressed+len(g2)*curve.SizeOfG2AffineUncompressed)
+	for i := range g1 {
+		msg = append(msg, g1[i].Marshal()...)
+	}
+	for i := range g2 {
+		msg = append(msg, g2[i].Marshal()...)
+	}
+	msgs, err := t.Exchange(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	res1 := make([]curve.G1Affine, len(g1))
+	res2 := make([]curve.G2Affine, len(g2))
+	for i, m := range msgs {
+		if len(m) != len(msg) {
+			return nil, nil, fmt.Errorf("party %d: expected %d bytes, got %d", i, len(msg), len(m))
+		}
+		var p1 curve.G1Affine
+		for j := range res1 {
+			if err := p1.Unmarshal(m[:curve.SizeOfG1AffineUncompressed]); err != nil {
+				return nil, nil, fmt.Errorf("party %d: %w", i, err)
+			}
+			m = m[curve.SizeOfG1AffineUncompressed:]
+			res1[j].Add(&res1[j], &p1)
+		}
+		var p2 curve.G2Affine
+		for j := range res2 {
+			if err := p2.Unmarshal(m[:curve.SizeOfG2AffineUncompressed]); err != nil {
+				return nil, nil, fmt.Errorf("party %d: %w", i, err)
+			}
+			m = m[curve.SizeOfG2AffineUncompressed:]
+			res2[j].Add(&res2[j], &p2)
+		}
+	}
+	return res1, res2, nil
+}
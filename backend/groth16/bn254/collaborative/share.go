@@ -0,0 +1,158 @@
+package collaborative
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+)
+
+// WitnessShare is the additive share of the solution of the circuit held by a
+// party: the sums over all the parties of the shares are the values of the
+// wires W and of the linear expressions A, B and C of the constraints.
+type WitnessShare struct {
+	W, A, B, C fr.Vector
+}
+
+// Share solves the circuit and splits the solution into nbParties additive
+// shares, to be sent privately to the parties.
+func Share(r1cs *cs.R1CS, fullWitness witness.Witness, nbParties int, opts ...backend.ProverOption) ([]*WitnessShare, error) {
+	if nbParties < 1 {
+		return nil, fmt.Errorf("invalid number of parties %d", nbParties)
+	}
+	if len(r1cs.CommitmentInfo.(constraint.Groth16Commitments)) != 0 {
+		return nil, errors.New("circuits with commitments are not supported")
+	}
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new prover config: %w", err)
+	}
+	_solution, err := r1cs.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.R1CSSolution)
+
+	shares, err := split(nbParties, solution.W, solution.A, solution.B, solution.C)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*WitnessShare, nbParties)
+	for i := range res {
+		res[i] = &WitnessShare{W: shares[0][i], A: shares[1][i], B: shares[2][i], C: shares[3][i]}
+	}
+	return res, nil
+}
+
+// WriteTo writes the binary encoding of the share to w.
+func (s *WitnessShare) WriteTo(w io.Writer) (int64, error) {
+	return writeVectors(w, &s.W, &s.A, &s.B, &s.C)
+}
+
+// ReadFrom reads the binary encoding of the share from r.
+func (s *WitnessShare) ReadFrom(r io.Reader) (int64, error) {
+	return readVectors(r, &s.W, &s.A, &s.B, &s.C)
+}
+
+// Triples is the share held by a party of Beaver triples (a, b, c) with
+// c = a·b.
+type Triples struct {
+	A, B, C fr.Vector
+}
+
+// GenerateTriples samples the Beaver triples needed by [Prove] with the
+// proving key pk and splits them into nbParties additive shares. The triples
+// must be generated by a dealer trusted not to collude with the parties, and
+// used for a single proof.
+func GenerateTriples(pk *groth16_bn254.ProvingKey, nbParties int) ([]*Triples, error) {
+	if nbParties < 1 {
+		return nil, fmt.Errorf("invalid number of parties %d", nbParties)
+	}
+	n := nbTriples(pk)
+	a, b, c := make(fr.Vector, n), make(fr.Vector, n), make(fr.Vector, n)
+	for i := 0; i < n; i++ {
+		if _, err := a[i].SetRandom(); err != nil {
+			return nil, err
+		}
+		if _, err := b[i].SetRandom(); err != nil {
+			return nil, err
+		}
+		c[i].Mul(&a[i], &b[i])
+	}
+	shares, err := split(nbParties, a, b, c)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Triples, nbParties)
+	for i := range res {
+		res[i] = &Triples{A: shares[0][i], B: shares[1][i], C: shares[2][i]}
+	}
+	return res, nil
+}
+
+// WriteTo writes the binary encoding of the triples to w.
+func (t *Triples) WriteTo(w io.Writer) (int64, error) {
+	return writeVectors(w, &t.A, &t.B, &t.C)
+}
+
+// ReadFrom reads the binary encoding of the triples from r.
+func (t *Triples) ReadFrom(r io.Reader) (int64, error) {
+	return readVectors(r, &t.A, &t.B, &t.C)
+}
+
+// nbTriples returns the number of triples used by a proof: one per point of
+// the coset on which A·B is evaluated, and one for r·s.
+func nbTriples(pk *groth16_bn254.ProvingKey) int {
+	return int(pk.Domain.Cardinality) + 1
+}
+
+// split returns, for each vector, nbShares random vectors summing to it.
+func split(nbShares int, vectors ...fr.Vector) ([][]fr.Vector, error) {
+	res := make([][]fr.Vector, len(vectors))
+	for k, values := range vectors {
+		res[k] = make([]fr.Vector, nbShares)
+		last := make(fr.Vector, len(values))
+		copy(last, values)
+		for i := 0; i < nbShares-1; i++ {
+			res[k][i] = make(fr.Vector, len(values))
+			for j := range res[k][i] {
+				if _, err := res[k][i][j].SetRandom(); err != nil {
+					return nil, err
+				}
+				last[j].Sub(&last[j], &res[k][i][j])
+			}
+		}
+		res[k][nbShares-1] = last
+	}
+	return res, nil
+}
+
+func writeVectors(w io.Writer, vectors ...*fr.Vector) (int64, error) {
+	var n int64
+	for _, v := range vectors {
+		m, err := v.WriteTo(w)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func readVectors(r io.Reader, vectors ...*fr.Vector) (int64, error) {
+	var n int64
+	for _, v := range vectors {
+		m, err := v.ReadFrom(r)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,126 @@
+package collaborative
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// Transport connects a party to the other parties of the computation.
+type Transport interface {
+	// Party returns the index of the party, in [0, NbParties()).
+	Party() int
+	// NbParties returns the number of parties of the computation.
+	NbParties() int
+	// Exchange sends msg to all the other parties and returns the messages
+	// sent by all the parties in the same round, indexed by party. The message
+	// of the party itself is msg.
+	Exchange(msg []byte) ([][]byte, error)
+}
+
+// maxMessageSize bounds the size of the messages read from the peers.
+const maxMessageSize = 1 << 32
+
+type connTransport struct {
+	party int
+	conns []io.ReadWriter
+}
+
+// NewConnTransport returns a [Transport] for the party exchanging
+// length-prefixed messages over the connections to its peers, for instance
+// [net.Conn]. conns[i] is the connection to the party i and conns[party] is
+// ignored. The connections must be reliable and ordered; they are not
+// authenticated nor encrypted by the transport.
+func NewConnTransport(party int, conns []io.ReadWriter) (Transport, error) {
+	if party < 0 || party >= len(conns) {
+		return nil, fmt.Errorf("party %d out of range [0, %d)", party, len(conns))
+	}
+	for i, c := range conns {
+		if i != party && c == nil {
+			return nil, fmt.Errorf("missing connection to party %d", i)
+		}
+	}
+	return &connTransport{party: party, conns: conns}, nil
+}
+
+// NewLocalTransports returns the transports of nbParties parties running in
+// the same process, connected by in-memory pipes.
+func NewLocalTransports(nbParties int) []Transport {
+	conns := make([][]io.ReadWriter, nbParties)
+	for i := range conns {
+		conns[i] = make([]io.ReadWriter, nbParties)
+	}
+	for i := range conns {
+		for j := i + 1; j < nbParties; j++ {
+			conns[i][j], conns[j][i] = net.Pipe()
+		}
+	}
+	res := make([]Transport, nbParties)
+	for i := range res {
+		res[i] = &connTransport{party: i, conns: conns[i]}
+	}
+	return res
+}
+
+func (t *connTransport) Party() int {
+	return t.party
+}
+
+func (t *connTransport) NbParties() int {
+	return len(t.conns)
+}
+
+func (t *connTransport) Exchange(msg []byte) ([][]byte, error) {
+	// the writes are concurrent with the reads, as the connections may not be
+	// buffered
+	chErr := make(chan error, len(t.conns))
+	for i := range t.conns {
+		if i == t.party {
+			continue
+		}
+		go func(w io.Writer) {
+			var header [8]byte
+			binary.BigEndian.PutUint64(header[:], uint64(len(msg)))
+			if _, err := w.Write(header[:]); err != nil {
+				chErr <- err
+				return
+			}
+			_, err := w.Write(msg)
+			chErr <- err
+		}(t.conns[i])
+	}
+
+	res := make([][]byte, len(t.conns))
+	var errs []error
+	for i := range t.conns {
+		if i == t.party {
+			res[i] = msg
+			continue
+		}
+		var header [8]byte
+		if _, err := io.ReadFull(t.conns[i], header[:]); err != nil {
+			errs = append(errs, fmt.Errorf("read from party %d: %w", i, err))
+			continue
+		}
+		size := binary.BigEndian.Uint64(header[:])
+		if size > maxMessageSize {
+			errs = append(errs, fmt.Errorf("message of %d bytes from party %d", size, i))
+			continue
+		}
+		res[i] = make([]byte, size)
+		if _, err := io.ReadFull(t.conns[i], res[i]); err != nil {
+			errs = append(errs, fmt.Errorf("read from party %d: %w", i, err))
+		}
+	}
+	for i := 0; i < len(t.conns)-1; i++ {
+		if err := <-chErr; err != nil {
+			errs = append(errs, fmt.Errorf("write: %w", err))
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
@@ -0,0 +1,193 @@
+package groth16
+
+import (
+	"errors"
+	"io"
+	"text/template"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/rust"
+)
+
+// ExportRust writes the sources of a Rust verifier of the proofs of vk to w,
+// for the target of the options (see [rust.WithTarget]): a Solana program
+// using the alt_bn128 syscalls or a CosmWasm contract. Use [rust.ExportCrate]
+// to write the complete crate.
+//
+// The proofs are serialized with [Proof.MarshalSolidity]. As for the Solidity
+// verifier, at most one commitment is supported and its hash to the field is
+// SHA-256, so the proofs must be computed and verified with the option
+// backend.WithProverHashToFieldFunction(sha256.New()).
+//
+// This is an experimental feature, the generated code has not been audited.
+func (vk *VerifyingKey) ExportRust(w io.Writer, exportOpts ...rust.ExportOption) error {
+	if len(vk.PublicAndCommitmentCommitted) > 1 {
+		return errors.New("exporting rust verifier with more than one commitment is not supported")
+	}
+	cfg, err := rust.NewExportConfig(exportOpts...)
+	if err != nil {
+		return err
+	}
+	tmpl, err := rust.NewTemplate(rustTemplate, template.FuncMap{
+		"g1": func(p curve.G1Affine) string {
+			b := p.RawBytes()
+			return rust.Bytes(b[:])
+		},
+		"g2": func(p curve.G2Affine) string {
+			b := p.RawBytes()
+			return rust.Bytes(b[:])
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// negate Beta, Gamma and Delta, to avoid negating proof elements in the
+	// verifier.
+	var betaNeg, gammaNeg, deltaNeg curve.G2Affine
+	betaNeg.Neg(&vk.G2.Beta)
+	gammaNeg.Neg(&vk.G2.Gamma)
+	deltaNeg.Neg(&vk.G2.Delta)
+
+	var committed []int
+	if len(vk.PublicAndCommitmentCommitted) == 1 {
+		committed = vk.PublicAndCommitmentCommitted[0]
+	}
+	return tmpl.ExecuteTemplate(w, "groth16", struct {
+		Cfg            rust.ExportConfig
+		Vk             *VerifyingKey
+		BetaNeg        curve.G2Affine
+		GammaNeg       curve.G2Affine
+		DeltaNeg       curve.G2Affine
+		NbPublicInputs int
+		NbCommitments  int
+		Committed      []int
+	}{
+		Cfg:            cfg,
+		Vk:             vk,
+		BetaNeg:        betaNeg,
+		GammaNeg:       gammaNeg,
+		DeltaNeg:       deltaNeg,
+		NbPublicInputs: len(vk.G1.K) - 1 - len(vk.PublicAndCommitmentCommitted),
+		NbCommitments:  len(vk.PublicAndCommitmentCommitted),
+		Committed:      committed,
+	})
+}
+
+const rustTemplate = `
+{{- define "groth16" -}}
+// Code generated by gnark DO NOT EDIT
+
+//! Groth16 verifier over BN254 for {{ .Cfg.Target }}.
+//!
+//! The proofs are serialized with gnark's MarshalSolidity and the public
+//! inputs are 32 bytes big-endian integers, reduced modulo r.
+
+#![allow(dead_code, unused_imports)]
+
+/// Number of public inputs.
+pub const NB_PUBLIC_INPUTS: usize = {{ .NbPublicInputs }};
+
+{{- if eq .NbCommitments 0 }}
+
+/// Size of the proof: A, B, C.
+const PROOF_SIZE: usize = 256;
+{{- else }}
+
+/// Size of the proof: A, B, C, the number of commitments (4 bytes), the
+/// commitment and its proof of knowledge.
+const PROOF_SIZE: usize = 388;
+
+/// Pedersen G in G2.
+const PEDERSEN_G: [u8; 128] = {{ g2 .Vk.CommitmentKey.G }};
+
+/// Pedersen GRootSigmaNeg in G2.
+const PEDERSEN_G_ROOT_SIGMA_NEG: [u8; 128] = {{ g2 .Vk.CommitmentKey.GRootSigmaNeg }};
+
+/// Indexes of the public inputs committed to, starting from 1.
+const PUBLIC_COMMITTED: [usize; {{ len .Committed }}] = [{{ range $i, $c := .Committed }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}];
+{{- end }}
+
+/// α in G1.
+const ALPHA: [u8; 64] = {{ g1 .Vk.G1.Alpha }};
+
+/// -β in G2.
+const BETA_NEG: [u8; 128] = {{ g2 .BetaNeg }};
+
+/// -γ in G2.
+const GAMMA_NEG: [u8; 128] = {{ g2 .GammaNeg }};
+
+/// -δ in G2.
+const DELTA_NEG: [u8; 128] = {{ g2 .DeltaNeg }};
+
+/// Points of the constant and of the public inputs in G1.
+const K: [[u8; 64]; {{ len .Vk.G1.K }}] = [
+{{- range $k := .Vk.G1.K }}
+    {{ g1 $k }},
+{{- end }}
+];
+
+/// Verifies the proof for the public inputs.
+pub fn verify(proof: &[u8], public_inputs: &[[u8; 32]]) -> Result<(), VerifierError> {
+    if public_inputs.len() != NB_PUBLIC_INPUTS {
+        return Err(VerifierError::InvalidPublicInputsNumber);
+    }
+    if proof.len() != PROOF_SIZE {
+        return Err(VerifierError::InvalidProofSize);
+    }
+    for input in public_inputs {
+        fr_from_be(input)?;
+    }
+
+    // L = K₀ + ∑ᵢ xᵢ Kᵢ₊₁
+    let mut l = K[0];
+{{- if gt .NbCommitments 0 }}
+    if proof[256..260] != [0, 0, 0, 1] {
+        return Err(VerifierError::InvalidProofSize);
+    }
+    let commitment = g1_at(proof, 260);
+    let pok = g1_at(proof, 324);
+
+    // the commitment is bound to the proof as the last public input, equal
+    // to the hash of the commitment and of the committed public inputs.
+    let mut h = Sha256::new();
+    h.update(commitment);
+    for i in PUBLIC_COMMITTED {
+        h.update(public_inputs[i - 1]);
+    }
+    let digest: [u8; 32] = h.finalize().into();
+    let hashed = fr_to_be(&Fr::from_be_bytes_mod_order(&digest));
+
+    // e(commitment, G) e(pok, GRootSigmaNeg) = 1
+    let mut pairing = Vec::with_capacity(384);
+    pairing.extend_from_slice(&commitment);
+    pairing.extend_from_slice(&PEDERSEN_G);
+    pairing.extend_from_slice(&pok);
+    pairing.extend_from_slice(&PEDERSEN_G_ROOT_SIGMA_NEG);
+    if !bn254::pairing_check(&pairing)? {
+        return Err(VerifierError::InvalidCommitment);
+    }
+
+    l = bn254::add(&l, &commitment)?;
+    l = bn254::add(&l, &bn254::mul(&K[NB_PUBLIC_INPUTS + 1], &hashed)?)?;
+{{- end }}
+    for (i, input) in public_inputs.iter().enumerate() {
+        l = bn254::add(&l, &bn254::mul(&K[i + 1], input)?)?;
+    }
+
+    // e(A, B) e(C, -δ) e(α, -β) e(L, -γ) = 1
+    let mut pairing = Vec::with_capacity(768);
+    pairing.extend_from_slice(&proof[..192]);
+    pairing.extend_from_slice(&proof[192..256]);
+    pairing.extend_from_slice(&DELTA_NEG);
+    pairing.extend_from_slice(&ALPHA);
+    pairing.extend_from_slice(&BETA_NEG);
+    pairing.extend_from_slice(&l);
+    pairing.extend_from_slice(&GAMMA_NEG);
+    if !bn254::pairing_check(&pairing)? {
+        return Err(VerifierError::ProofInvalid);
+    }
+    Ok(())
+}
+{{ template "runtime" .Cfg }}
+{{ end }}`
@@ -53,14 +53,14 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
 
 	if len(publicWitness) != nbPublicVars-1 {
-		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
+		return backend.NewVerificationError(backend.CheckPublicWitness, fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), nbPublicVars-1))
 	}
 	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Logger()
 	start := time.Now()
 
 	// check that the points in the proof are in the correct subgroup
 	if !proof.isValid() {
-		return errCorrectSubgroupCheckFailed
+		return backend.NewVerificationError(backend.CheckProofFormat, errCorrectSubgroupCheckFailed)
 	}
 
 	var doubleML curve.GT
@@ -104,7 +104,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return err
 	} else {
 		if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
-			return err
+			return backend.NewVerificationError(backend.CheckCommitmentOpening, err)
 		}
 	}
 
@@ -134,7 +134,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 
 	right = curve.FinalExponentiation(&right, &doubleML)
 	if !vk.e.Equal(&right) {
-		return errPairingCheckFailed
+		return backend.NewVerificationError(backend.CheckPairingEquation, errPairingCheckFailed)
 	}
 
 	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
@@ -0,0 +1,35 @@
+package groth16
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func init() {
+	backend.RegisterVerifier(backend.GROTH16, verifyEnvelope)
+}
+
+// verifyEnvelope is the backend.EnvelopeVerifier of the package, see
+// backend.Verify.
+func verifyEnvelope(curve ecc.ID, vkData, proofData, publicWitnessData []byte, opts ...backend.VerifierOption) error {
+	vk := NewVerifyingKey(curve)
+	if _, err := vk.ReadFrom(bytes.NewReader(vkData)); err != nil {
+		return fmt.Errorf("read verifying key: %w", err)
+	}
+	proof := NewProof(curve)
+	if _, err := proof.ReadFrom(bytes.NewReader(proofData)); err != nil {
+		return fmt.Errorf("read proof: %w", err)
+	}
+	publicWitness, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return err
+	}
+	if err := publicWitness.UnmarshalBinary(publicWitnessData); err != nil {
+		return fmt.Errorf("read public witness: %w", err)
+	}
+	return Verify(proof, vk, publicWitness, opts...)
+}
@@ -20,6 +20,9 @@
 package groth16
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -84,6 +87,11 @@ type ProvingKey interface {
 	NbG2() int
 
 	IsDifferent(interface{}) bool
+
+	// Fingerprint returns the hash of the encoding of the key, computed once
+	// and stored in the key. It identifies the key in the proof cache, see
+	// backend.WithProofCache.
+	Fingerprint() ([]byte, error)
 }
 
 // VerifyingKey represents a Groth16 VerifyingKey
@@ -169,7 +177,59 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 //		will execute all the prover computations, even if the witness is invalid
 //	 will produce an invalid proof
 //		internally, the solution vector to the R1CS will be filled with random values which may impact benchmarking
+//
+// if a proof cache is set with backend.WithProofCache, the proof is read from
+// the cache when available, and stored in the cache otherwise.
 func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ProofCache == nil {
+		return prove(r1cs, pk, fullWitness, opts...)
+	}
+
+	// the proving key is hashed once, instead of being serialized for every
+	// proof
+	fingerprint, err := pk.Fingerprint()
+	if err != nil {
+		return nil, fmt.Errorf("proof cache key: %w", err)
+	}
+	key, err := backend.ProofCacheKey(backend.GROTH16, &cfg, rawBytes(fingerprint), fullWitness)
+	if err != nil {
+		return nil, fmt.Errorf("proof cache key: %w", err)
+	}
+	if data, ok := cfg.ProofCache.Get(key); ok {
+		proof := NewProof(pk.CurveID())
+		if _, err := proof.ReadFrom(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("read cached proof: %w", err)
+		}
+		return proof, nil
+	}
+	proof, err := prove(r1cs, pk, fullWitness, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	cfg.ProofCache.Put(key, buf.Bytes())
+	return proof, nil
+}
+
+// ProveBatch computes the proofs of the witnesses for the same constraint
+// system and proving key, with [Prove]. The number of proofs computed
+// concurrently is set with backend.WithBatchParallelism. If some witnesses are
+// not proved, it returns the other proofs with a [*backend.BatchError] giving
+// the error of each witness.
+func ProveBatch(r1cs constraint.ConstraintSystem, pk ProvingKey, witnesses []witness.Witness, opts ...backend.ProverOption) ([]Proof, error) {
+	return backend.ProveBatch(witnesses, func(fullWitness witness.Witness) (Proof, error) {
+		return Prove(r1cs, pk, fullWitness, opts...)
+	}, opts...)
+}
+
+func prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
 	switch _r1cs := r1cs.(type) {
 	case *cs_bls12377.R1CS:
 		return groth16_bls12377.Prove(_r1cs, pk.(*groth16_bls12377.ProvingKey), fullWitness, opts...)
@@ -200,6 +260,44 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 	}
 }
 
+// ResumeProve completes the proof interrupted while proving with the option
+// backend.WithCheckpointDir, from the checkpoint saved in the directory. The
+// constraint system and the proving key must be the ones of the interrupted
+// proof. It returns backend.ErrNoCheckpoint if the prover didn't save a
+// checkpoint yet, in which case the proof must be computed again with Prove,
+// or if the checkpoint was removed once the proof was computed (see
+// backend.WithKeepCheckpoint).
+func ResumeProve(r1cs constraint.ConstraintSystem, pk ProvingKey, opts ...backend.ProverOption) (Proof, error) {
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		return groth16_bls12377.ResumeProve(_r1cs, pk.(*groth16_bls12377.ProvingKey), opts...)
+
+	case *cs_bls12381.R1CS:
+		return groth16_bls12381.ResumeProve(_r1cs, pk.(*groth16_bls12381.ProvingKey), opts...)
+
+	case *cs_bn254.R1CS:
+		if icicle_bn254.HasIcicle {
+			return nil, errors.New("resuming a proof is not supported with icicle")
+		}
+		return groth16_bn254.ResumeProve(_r1cs, pk.(*groth16_bn254.ProvingKey), opts...)
+
+	case *cs_bw6761.R1CS:
+		return groth16_bw6761.ResumeProve(_r1cs, pk.(*groth16_bw6761.ProvingKey), opts...)
+
+	case *cs_bls24317.R1CS:
+		return groth16_bls24317.ResumeProve(_r1cs, pk.(*groth16_bls24317.ProvingKey), opts...)
+
+	case *cs_bls24315.R1CS:
+		return groth16_bls24315.ResumeProve(_r1cs, pk.(*groth16_bls24315.ProvingKey), opts...)
+
+	case *cs_bw6633.R1CS:
+		return groth16_bw6633.ResumeProve(_r1cs, pk.(*groth16_bw6633.ProvingKey), opts...)
+
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in a production environment.
@@ -434,3 +532,11 @@ func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
 	}
 	return r1cs
 }
+
+// rawBytes is a fingerprint given to backend.ProofCacheKey.
+type rawBytes []byte
+
+func (b rawBytes) WriteRawTo(w io.Writer) (int64, error) {
+	n, err := w.Write(b)
+	return int64(n), err
+}
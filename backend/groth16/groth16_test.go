@@ -1,18 +1,26 @@
 package groth16_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"math/big"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/memory"
 	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCustomHashToField(t *testing.T) {
@@ -51,6 +59,280 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestProofCache(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	cache := &countingCache{ProofCache: backend.NewMemoryProofCache()}
+	serialize := func(proof groth16.Proof) []byte {
+		var buf bytes.Buffer
+		_, err := proof.WriteTo(&buf)
+		assert.NoError(err)
+		return buf.Bytes()
+	}
+
+	var proofs [][]byte
+	for _, x := range []int{3, 3, 4} {
+		witness, err := frontend.NewWitness(&squareCircuit{X: x, Y: x * x}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, witness, backend.WithProofCache(cache))
+		assert.NoError(err)
+		pubWitness, err := witness.Public()
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, pubWitness))
+		proofs = append(proofs, serialize(proof))
+	}
+	// groth16 proofs are randomized, equal proofs come from the cache.
+	assert.Equal(proofs[0], proofs[1])
+	assert.NotEqual(proofs[0], proofs[2])
+	assert.Equal(1, cache.hits)
+	assert.Equal(2, cache.puts)
+
+	// the same key read back hits the cache, another key doesn't
+	var buf bytes.Buffer
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	pk2 := groth16.NewProvingKey(ecc.BN254)
+	_, err = pk2.ReadFrom(&buf)
+	assert.NoError(err)
+	other, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	for _, k := range []groth16.ProvingKey{pk2, other} {
+		_, err = groth16.Prove(ccs, k, witness, backend.WithProofCache(cache))
+		assert.NoError(err)
+	}
+	assert.Equal(2, cache.hits)
+	assert.Equal(3, cache.puts)
+
+	// the options changing the proof are part of the key
+	_, err = groth16.Prove(ccs, pk, witness, backend.WithProofCache(cache), backend.WithSideChannelHardening())
+	assert.NoError(err)
+	assert.Equal(2, cache.hits)
+	assert.Equal(4, cache.puts)
+	_, err = groth16.Prove(ccs, pk, witness, backend.WithProofCache(cache), backend.WithProverHashToFieldFunction(constantHash{}))
+	assert.Error(err)
+
+	// reading another key into pk2 resets its fingerprint
+	fp, err := pk2.Fingerprint()
+	assert.NoError(err)
+	buf.Reset()
+	_, err = other.WriteRawTo(&buf)
+	assert.NoError(err)
+	_, err = pk2.ReadFrom(&buf)
+	assert.NoError(err)
+	fpOther, err := pk2.Fingerprint()
+	assert.NoError(err)
+	assert.NotEqual(fp, fpOther)
+}
+
+func TestResumeProve(t *testing.T) {
+	assert := test.NewAssert(t)
+	assignment := &commitmentCircuit{X: 1}
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &commitmentCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+
+			dir := t.TempDir()
+			_, err = groth16.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.ErrorIs(err, backend.ErrNoCheckpoint)
+
+			// the checkpoint is removed once the proof is computed
+			proof, err := groth16.Prove(ccs, pk, witness, backend.WithProverHashToFieldFunction(constantHash{}), backend.WithCheckpointDir(dir))
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pubWitness, backend.WithVerifierHashToFieldFunction(constantHash{})))
+			_, err = groth16.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.ErrorIs(err, backend.ErrNoCheckpoint)
+
+			// the checkpoint of the last phase is kept on demand and doesn't
+			// need the witness
+			_, err = groth16.Prove(ccs, pk, witness, backend.WithProverHashToFieldFunction(constantHash{}), backend.WithCheckpointDir(dir), backend.WithKeepCheckpoint())
+			assert.NoError(err)
+			resumed, err := groth16.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(resumed, vk, pubWitness, backend.WithVerifierHashToFieldFunction(constantHash{})))
+			entries, err := os.ReadDir(dir)
+			assert.NoError(err)
+			assert.Empty(entries)
+		}, curve.String())
+	}
+}
+
+func TestSolverPool(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &commitmentCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&commitmentCircuit{X: 1}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+
+	// the buffers given back by a proof are reused by the next ones
+	pool := solver.NewPool(solver.WithPoolInitialSize(16))
+	for i := 0; i < 3; i++ {
+		proof, err := groth16.Prove(ccs, pk, witness, backend.WithProverHashToFieldFunction(constantHash{}), backend.WithSolverOptions(solver.WithPool(pool)))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, pubWitness, backend.WithVerifierHashToFieldFunction(constantHash{})))
+	}
+}
+
+func TestVerificationError(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, witness)
+	assert.NoError(err)
+
+	check := func(err error) backend.VerificationCheck {
+		var vErr *backend.VerificationError
+		assert.ErrorAs(err, &vErr)
+		return vErr.Check
+	}
+	// full witness instead of the public one
+	assert.Equal(backend.CheckPublicWitness, check(groth16.Verify(proof, vk, witness)))
+	// wrong public input
+	wrongPublic, err := frontend.NewWitness(&squareCircuit{Y: 10}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Equal(backend.CheckPairingEquation, check(groth16.Verify(proof, vk, wrongPublic)))
+}
+
+func TestSideChannelHardening(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &hardeningCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(&hardeningCircuit{X: 5, Idx: 3, Y: 9}, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, witness, backend.WithSideChannelHardening())
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pubWitness))
+
+			invalid, err := frontend.NewWitness(&hardeningCircuit{X: 5, Idx: 3, Y: 10}, curve.ScalarField())
+			assert.NoError(err)
+			_, err = groth16.Prove(ccs, pk, invalid, backend.WithSideChannelHardening())
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+func TestProveBatch(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	var witnesses []witness.Witness
+	for _, x := range []int{2, 3, 4, 5} {
+		y := x * x
+		if x == 4 {
+			y++
+		}
+		w, err := frontend.NewWitness(&squareCircuit{X: x, Y: y}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		witnesses = append(witnesses, w)
+	}
+
+	proofs, err := groth16.ProveBatch(ccs, pk, witnesses, backend.WithBatchParallelism(2))
+	var batchErr *backend.BatchError
+	assert.ErrorAs(err, &batchErr)
+	assert.Len(batchErr.Errors, len(witnesses))
+	for i := range witnesses {
+		if i == 2 {
+			assert.Error(batchErr.Errors[i])
+			assert.Nil(proofs[i])
+			continue
+		}
+		assert.NoError(batchErr.Errors[i])
+		pubWitness, err := witnesses[i].Public()
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proofs[i], vk, pubWitness))
+	}
+
+	proofs, err = groth16.ProveBatch(ccs, pk, witnesses[:2])
+	assert.NoError(err)
+	assert.Len(proofs, 2)
+
+	// the hash functions of the options are shared by the proofs
+	_, err = groth16.ProveBatch(ccs, pk, witnesses, backend.WithBatchParallelism(2), backend.WithProverHashToFieldFunction(constantHash{}))
+	assert.Error(err)
+
+	// the checkpoint directory holds the checkpoint of a single proof
+	_, err = groth16.ProveBatch(ccs, pk, witnesses, backend.WithBatchParallelism(2), backend.WithCheckpointDir(t.TempDir()))
+	assert.Error(err)
+}
+
+func TestProveBatchStatefulBlueprints(t *testing.T) {
+	assert := require.New(t)
+
+	// the lookup tables and the memories are solved by stateful blueprints,
+	// which must not be shared by the concurrent solves
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &hardeningCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	var witnesses []witness.Witness
+	for i := 0; i < 8; i++ {
+		x, idx := i+1, i%4
+		w, err := frontend.NewWitness(&hardeningCircuit{X: x, Idx: idx, Y: idx * idx}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		witnesses = append(witnesses, w)
+	}
+
+	proofs, err := groth16.ProveBatch(ccs, pk, witnesses, backend.WithBatchParallelism(4))
+	assert.NoError(err)
+	for i := range witnesses {
+		pubWitness, err := witnesses[i].Public()
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proofs[i], vk, pubWitness))
+	}
+}
+
+type countingCache struct {
+	backend.ProofCache
+	hits, puts int
+}
+
+func (c *countingCache) Get(key []byte) ([]byte, bool) {
+	proof, ok := c.ProofCache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return proof, ok
+}
+
+func (c *countingCache) Put(key, proof []byte) {
+	c.puts++
+	c.ProofCache.Put(key, proof)
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -168,6 +450,35 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+// hardeningCircuit solves divisions, lookups and memory accesses at secret
+// indices.
+type hardeningCircuit struct {
+	X, Idx frontend.Variable
+	Y      frontend.Variable `gnark:",public"`
+}
+
+func (c *hardeningCircuit) Define(api frontend.API) error {
+	squares := logderivlookup.New(api)
+	for i := 0; i < 8; i++ {
+		squares.Insert(i * i)
+	}
+	m := memory.New(api, []frontend.Variable{1, 2, 3, 4})
+	m.Write(c.Idx, c.X)
+	v := m.Read(c.Idx)
+	api.AssertIsEqual(api.Div(api.Mul(v, squares.Lookup(c.Idx)[0]), c.X), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -182,3 +493,27 @@ func getCurves() []ecc.ID {
 	}
 	return gnark.Curves()
 }
+
+func TestContext(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.NoError(err)
+	pubWitness, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pubWitness))
+
+	cancel()
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+	_, err = ccs.Solve(w, solver.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,384 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package shplonk
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbPoints = errors.New("number of opening sets is not the same as the number of polynomials")
+	ErrEmptyPoints     = errors.New("opening set is empty")
+	ErrDuplicatePoint  = errors.New("opening set contains the same point twice")
+	ErrVerifyOpening   = errors.New("can't verify batch opening proof at multiple points")
+)
+
+// OpeningProof is a batch opening proof of several polynomials, each at its
+// own set of points. Its size doesn't depend on the number of polynomials or
+// points, besides the claimed values.
+type OpeningProof struct {
+	// W is the commitment to Σᵢ γⁱ(fᵢ-rᵢ)/Z_{Sᵢ}, and WPrime the commitment to the
+	// quotient of the linearized polynomial L by (X-z).
+	W, WPrime curve.G1Affine
+
+	// ClaimedValues[i][j] = fᵢ(points[i][j])
+	ClaimedValues [][]fr.Element
+}
+
+// BatchOpen computes a batch opening proof of the polynomials fᵢ, given in
+// canonical basis, at the sets of points Sᵢ (shplonk, see
+// https://eprint.iacr.org/2020/081, section 4). digests[i] is the KZG
+// commitment to polynomials[i]. The points of each set must be distinct.
+//
+// dataTranscript is bound to the Fiat-Shamir transcript.
+func BatchOpen(polynomials [][]fr.Element, digests []kzg.Digest, points [][]fr.Element, hf hash.Hash, pk kzg.ProvingKey, dataTranscript ...[]byte) (OpeningProof, error) {
+	var proof OpeningProof
+	if err := checkSizes(len(polynomials), digests, points); err != nil {
+		return proof, err
+	}
+
+	// claimed values and remainders rᵢ, interpolating fᵢ on Sᵢ
+	proof.ClaimedValues = make([][]fr.Element, len(polynomials))
+	remainders := make([][]fr.Element, len(polynomials))
+	for i, p := range polynomials {
+		proof.ClaimedValues[i] = make([]fr.Element, len(points[i]))
+		for j := range points[i] {
+			proof.ClaimedValues[i][j] = eval(p, points[i][j])
+		}
+		remainders[i] = interpolate(points[i], proof.ClaimedValues[i])
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGamma(fs, digests, points, proof.ClaimedValues, dataTranscript)
+	if err != nil {
+		return proof, err
+	}
+
+	// h = Σᵢ γⁱ(fᵢ-rᵢ)/Z_{Sᵢ}, the divisions are exact.
+	maxSize := 0
+	for _, p := range polynomials {
+		if len(p) > maxSize {
+			maxSize = len(p)
+		}
+	}
+	h := make([]fr.Element, maxSize)
+	quotients := make([][]fr.Element, len(polynomials))
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i, p := range polynomials {
+		q := sub(p, remainders[i])
+		for _, s := range points[i] {
+			q = divideByXMinusA(q, s)
+		}
+		quotients[i] = q
+		for j := range q {
+			var t fr.Element
+			t.Mul(&q[j], &gammaI)
+			h[j].Add(&h[j], &t)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	proof.W, err = kzg.Commit(h, pk)
+	if err != nil {
+		return proof, err
+	}
+
+	z, err := deriveZ(fs, &proof.W)
+	if err != nil {
+		return proof, err
+	}
+
+	// L = Σᵢ γⁱ Z_{T\Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)h, which vanishes at z.
+	zT := vanishingAt(allPoints(points), z)
+	l := make([]fr.Element, maxSize)
+	gammaI.SetOne()
+	for i, p := range polynomials {
+		var c, ri fr.Element
+		c.Div(&zT, ptr(vanishingAt(points[i], z)))
+		c.Mul(&c, &gammaI)
+		ri = eval(remainders[i], z)
+		for j := range p {
+			var t fr.Element
+			t.Mul(&p[j], &c)
+			l[j].Add(&l[j], &t)
+		}
+		ri.Mul(&ri, &c)
+		l[0].Sub(&l[0], &ri)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	for j := range h {
+		var t fr.Element
+		t.Mul(&h[j], &zT)
+		l[j].Sub(&l[j], &t)
+	}
+	proof.WPrime, err = kzg.Commit(divideByXMinusA(l, z), pk)
+	if err != nil {
+		return proof, err
+	}
+	return proof, nil
+}
+
+// BatchVerify verifies a batch opening proof of the polynomials committed to
+// in digests at the sets of points, as computed by BatchOpen. It costs a
+// multi-exponentiation and a pairing check.
+//
+// The proofs computed with the hash of std/recursion.NewShort are verified in
+// circuit by the KZG verifier of std/commitments/kzg.
+func BatchVerify(proof *OpeningProof, digests []kzg.Digest, points [][]fr.Element, hf hash.Hash, vk kzg.VerifyingKey, dataTranscript ...[]byte) error {
+	if err := checkSizes(len(digests), digests, points); err != nil {
+		return err
+	}
+	if len(proof.ClaimedValues) != len(digests) {
+		return ErrInvalidNbPoints
+	}
+	for i := range points {
+		if len(proof.ClaimedValues[i]) != len(points[i]) {
+			return ErrInvalidNbPoints
+		}
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGamma(fs, digests, points, proof.ClaimedValues, dataTranscript)
+	if err != nil {
+		return err
+	}
+	z, err := deriveZ(fs, &proof.W)
+	if err != nil {
+		return err
+	}
+
+	// [L] = Σᵢ γⁱ Z_{T\Sᵢ}(z)([fᵢ] - rᵢ(z)[1]) - Z_T(z)[W]
+	zT := vanishingAt(allPoints(points), z)
+	scalars := make([]fr.Element, len(digests)+2)
+	bases := make([]curve.G1Affine, len(digests)+2)
+	var gammaI, constant fr.Element
+	gammaI.SetOne()
+	for i := range digests {
+		remainder := interpolate(points[i], proof.ClaimedValues[i])
+		ri := eval(remainder, z)
+		scalars[i].Div(&zT, ptr(vanishingAt(points[i], z)))
+		scalars[i].Mul(&scalars[i], &gammaI)
+		bases[i] = digests[i]
+		ri.Mul(&ri, &scalars[i])
+		constant.Add(&constant, &ri)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	// [L] + z[W'] is checked against [τ][W'].
+	n := len(digests)
+	constant.Neg(&constant)
+	scalars[n] = constant
+	bases[n] = vk.G1
+	scalars[n+1].Neg(&zT)
+	bases[n+1] = proof.W
+	var lhs curve.G1Affine
+	if _, err := lhs.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var zWPrime curve.G1Affine
+	var bz big.Int
+	z.BigInt(&bz)
+	zWPrime.ScalarMultiplication(&proof.WPrime, &bz)
+	lhs.Add(&lhs, &zWPrime)
+
+	// e([L] + z[W'], [1]) = e([W'], [τ])
+	var negWPrime curve.G1Affine
+	negWPrime.Neg(&proof.WPrime)
+	ok, err := curve.PairingCheck([]curve.G1Affine{lhs, negWPrime}, []curve.G2Affine{vk.G2[0], vk.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyOpening
+	}
+	return nil
+}
+
+func checkSizes(nbPolynomials int, digests []kzg.Digest, points [][]fr.Element) error {
+	if nbPolynomials != len(digests) || nbPolynomials != len(points) {
+		return ErrInvalidNbPoints
+	}
+	for _, s := range points {
+		if len(s) == 0 {
+			return ErrEmptyPoints
+		}
+		for j := range s {
+			for k := j + 1; k < len(s); k++ {
+				if s[j].Equal(&s[k]) {
+					return ErrDuplicatePoint
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func deriveGamma(fs *fiatshamir.Transcript, digests []kzg.Digest, points, claimedValues [][]fr.Element, dataTranscript [][]byte) (fr.Element, error) {
+	var gamma fr.Element
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return gamma, err
+		}
+	}
+	for i := range points {
+		for j := range points[i] {
+			if err := fs.Bind("gamma", points[i][j].Marshal()); err != nil {
+				return gamma, err
+			}
+			if err := fs.Bind("gamma", claimedValues[i][j].Marshal()); err != nil {
+				return gamma, err
+			}
+		}
+	}
+	for _, data := range dataTranscript {
+		if err := fs.Bind("gamma", data); err != nil {
+			return gamma, err
+		}
+	}
+	b, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return gamma, err
+	}
+	gamma.SetBytes(b)
+	return gamma, nil
+}
+
+func deriveZ(fs *fiatshamir.Transcript, w *curve.G1Affine) (fr.Element, error) {
+	var z fr.Element
+	if err := fs.Bind("z", w.Marshal()); err != nil {
+		return z, err
+	}
+	b, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return z, err
+	}
+	z.SetBytes(b)
+	return z, nil
+}
+
+// allPoints returns the union T of the sets of points.
+func allPoints(points [][]fr.Element) []fr.Element {
+	var res []fr.Element
+	seen := make(map[fr.Element]struct{})
+	for _, s := range points {
+		for _, p := range s {
+			if _, ok := seen[p]; !ok {
+				seen[p] = struct{}{}
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// vanishingAt returns Πᵢ(x-sᵢ).
+func vanishingAt(s []fr.Element, x fr.Element) fr.Element {
+	var res, t fr.Element
+	res.SetOne()
+	for i := range s {
+		t.Sub(&x, &s[i])
+		res.Mul(&res, &t)
+	}
+	return res
+}
+
+// eval evaluates p, in canonical basis, at x.
+func eval(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+// interpolate returns the polynomial of degree < len(s) such that r(sᵢ) = vᵢ,
+// in canonical basis.
+func interpolate(s, v []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(s))
+	for i := range s {
+		// Lagrange basis polynomial: Πⱼ≠ᵢ(X-sⱼ)/(sᵢ-sⱼ)
+		basis := []fr.Element{fr.One()}
+		var denominator fr.Element
+		denominator.SetOne()
+		for j := range s {
+			if j == i {
+				continue
+			}
+			basis = mulByXMinusA(basis, s[j])
+			var t fr.Element
+			t.Sub(&s[i], &s[j])
+			denominator.Mul(&denominator, &t)
+		}
+		var c fr.Element
+		c.Div(&v[i], &denominator)
+		for k := range basis {
+			var t fr.Element
+			t.Mul(&basis[k], &c)
+			res[k].Add(&res[k], &t)
+		}
+	}
+	return res
+}
+
+// sub returns p-q.
+func sub(p, q []fr.Element) []fr.Element {
+	res := make([]fr.Element, max(len(p), len(q)))
+	copy(res, p)
+	for i := range q {
+		res[i].Sub(&res[i], &q[i])
+	}
+	return res
+}
+
+// mulByXMinusA returns p⋅(X-a).
+func mulByXMinusA(p []fr.Element, a fr.Element) []fr.Element {
+	res := make([]fr.Element, len(p)+1)
+	for i := range p {
+		var t fr.Element
+		t.Mul(&p[i], &a)
+		res[i].Sub(&res[i], &t)
+		res[i+1].Add(&res[i+1], &p[i])
+	}
+	return res
+}
+
+// divideByXMinusA returns the quotient of p by (X-a), dropping the remainder.
+func divideByXMinusA(p []fr.Element, a fr.Element) []fr.Element {
+	if len(p) <= 1 {
+		return []fr.Element{}
+	}
+	res := make([]fr.Element, len(p)-1)
+	var carry fr.Element
+	for i := len(p) - 1; i >= 1; i-- {
+		carry.Mul(&carry, &a).Add(&carry, &p[i])
+		res[i-1] = carry
+	}
+	return res
+}
+
+func ptr(e fr.Element) *fr.Element {
+	return &e
+}
@@ -19,6 +19,7 @@ package plonk
 import (
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/iop"
@@ -26,6 +27,7 @@ import (
 	"github.com/consensys/gnark/backend/plonk/internal"
 	"github.com/consensys/gnark/constraint"
 	cs "github.com/consensys/gnark/constraint/bls12-381"
+	"github.com/consensys/gnark/constraint/permutation"
 )
 
 // VerifyingKey stores the data needed to verify a proof:
@@ -40,6 +42,10 @@ type VerifyingKey struct {
 	Generator         fr.Element
 	NbPublicVariables uint64
 
+	// SRSSize is the number of G1 points of the canonical kzg SRS needed by
+	// the circuit. It isn't serialized, as it is derived from Size.
+	SRSSize uint64
+
 	// Commitment scheme that is used for an instantiation of PLONK
 	Kzg kzg.VerifyingKey
 
@@ -99,14 +105,29 @@ func Setup(spr *cs.SparseR1CS, srs, srsLagrange kzg.SRS) (*ProvingKey, *Verifyin
 		return nil, nil, fmt.Errorf("circuit has only %d constraints; unsupported by the current implementation", spr.GetNbConstraints())
 	}
 
-	// check the size of the kzg srs.
-	if len(srs.Pk.G1) < (int(domain.Cardinality) + 3) { // + 3 for the kzg.Open of blinded poly
-		return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(srs.Pk.G1), domain.Cardinality+3)
+	// check the size of the kzg srs. A larger srs is truncated to the size of
+	// the domain, after checking that the truncated points are consistent
+	// with the verifying key.
+	sizeCanonical := srsSize(domain.Cardinality)
+	if uint64(len(srs.Pk.G1)) < sizeCanonical {
+		return nil, nil, fmt.Errorf("%w: got %d points, need %d for %d constraints and %d public inputs (domain of size %d)",
+			internal.ErrSRSTooSmall, len(srs.Pk.G1), sizeCanonical, spr.GetNbConstraints(), len(spr.Public), domain.Cardinality)
+	}
+	canonical := srs.Pk.G1[:sizeCanonical]
+	if err := checkSRS(canonical, &srs.Vk); err != nil {
+		return nil, nil, err
 	}
 
-	// same for the lagrange form
-	if len(srsLagrange.Pk.G1) != int(domain.Cardinality) {
-		return nil, nil, fmt.Errorf("kzg srs lagrange is too small: got %d, need %d", len(srsLagrange.Pk.G1), domain.Cardinality)
+	// the lagrange form depends on the size of the domain, it is computed from
+	// the canonical form if its size differs, and checked against it otherwise.
+	lagrange := srsLagrange.Pk.G1
+	if uint64(len(lagrange)) != domain.Cardinality {
+		var err error
+		if lagrange, err = kzg.ToLagrangeG1(canonical[:domain.Cardinality]); err != nil {
+			return nil, nil, fmt.Errorf("kzg srs lagrange: %w", err)
+		}
+	} else if err := checkLagrangeSRS(lagrange, canonical); err != nil {
+		return nil, nil, err
 	}
 
 	// step 1: set the verifying key
@@ -115,9 +136,10 @@ func Setup(spr *cs.SparseR1CS, srs, srsLagrange kzg.SRS) (*ProvingKey, *Verifyin
 	vk.SizeInv.SetUint64(vk.Size).Inverse(&vk.SizeInv)
 	vk.Generator.Set(&domain.Generator)
 	vk.NbPublicVariables = uint64(len(spr.Public))
+	vk.SRSSize = sizeCanonical
 
-	pk.Kzg.G1 = srs.Pk.G1[:int(vk.Size)+3]
-	pk.KzgLagrange.G1 = srsLagrange.Pk.G1
+	pk.Kzg.G1 = canonical
+	pk.KzgLagrange.G1 = lagrange
 	vk.Kzg = srs.Vk
 
 	// step 2: ql, qr, qm, qo, qk, qcp in Lagrange Basis
@@ -136,6 +158,79 @@ func Setup(spr *cs.SparseR1CS, srs, srsLagrange kzg.SRS) (*ProvingKey, *Verifyin
 	return &pk, &vk, nil
 }
 
+// srsSize returns the number of points of the canonical kzg SRS needed for a
+// domain of the given size: the blinded Z, the largest committed polynomial,
+// has order_blinding_Z+1 more coefficients.
+func srsSize(cardinality uint64) uint64 {
+	return cardinality + order_blinding_Z + 1
+}
+
+// checkSRS checks that g1 is a prefix of the powers [τⁱ]G₁, where [τ]G₂ is in
+// the verifying key. For a random ρ, it checks that
+//
+//	e(∑ ρⁱ[τⁱ⁺¹]G₁, [1]G₂) == e(∑ ρⁱ[τⁱ]G₁, [τ]G₂)
+func checkSRS(g1 []curve.G1Affine, vk *kzg.VerifyingKey) error {
+	if !g1[0].Equal(&vk.G1) {
+		return fmt.Errorf("%w: first point differs from the generator of the verifying key", internal.ErrInconsistentSRS)
+	}
+	n := len(g1) - 1
+	rho := make([]fr.Element, n)
+	rho[0].SetOne()
+	if _, err := rho[1].SetRandom(); err != nil {
+		return err
+	}
+	for i := 2; i < n; i++ {
+		rho[i].Mul(&rho[i-1], &rho[1])
+	}
+	var lo, hi curve.G1Affine
+	if _, err := lo.MultiExp(g1[:n], rho, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := hi.MultiExp(g1[1:], rho, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	lo.Neg(&lo)
+	ok, err := curve.PairingCheck([]curve.G1Affine{hi, lo}, []curve.G2Affine{vk.G2[0], vk.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: points are not successive powers of the verifying key", internal.ErrInconsistentSRS)
+	}
+	return nil
+}
+
+// checkLagrangeSRS checks that lagrange is the canonical SRS in the lagrange
+// basis of the domain of the same size. For a random ρ, the polynomial p such
+// that p(ωⁱ) = ρⁱ must have the same commitment in both forms.
+func checkLagrangeSRS(lagrange, canonical []curve.G1Affine) error {
+	domain := fft.NewDomain(uint64(len(lagrange)))
+	evaluations := make([]fr.Element, domain.Cardinality)
+	evaluations[0].SetOne()
+	if _, err := evaluations[1].SetRandom(); err != nil {
+		return err
+	}
+	for i := 2; i < len(evaluations); i++ {
+		evaluations[i].Mul(&evaluations[i-1], &evaluations[1])
+	}
+	coefficients := make([]fr.Element, len(evaluations))
+	copy(coefficients, evaluations)
+	domain.FFTInverse(coefficients, fft.DIF)
+	fft.BitReverse(coefficients)
+
+	var fromLagrange, fromCanonical curve.G1Affine
+	if _, err := fromLagrange.MultiExp(lagrange, evaluations, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := fromCanonical.MultiExp(canonical[:len(coefficients)], coefficients, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if !fromLagrange.Equal(&fromCanonical) {
+		return fmt.Errorf("%w: lagrange form differs from the canonical form", internal.ErrInconsistentSRS)
+	}
+	return nil
+}
+
 // NbPublicWitness returns the expected public witness size (number of field elements)
 func (vk *VerifyingKey) NbPublicWitness() int {
 	return int(vk.NbPublicVariables)
@@ -270,23 +365,19 @@ func initFFTDomain(spr *cs.SparseR1CS) *fft.Domain {
 //
 // The permutation is encoded as a slice s of size 3*size(l), where the
 // i-th entry of l∥r∥o is sent to the s[i]-th entry, so it acts on a tab
-// like this: for i in tab: tab[i] = tab[permutation[i]]
+// like this: for i in tab: tab[i] = tab[permutation[i]], see package
+// constraint/permutation.
 func buildPermutation(spr *cs.SparseR1CS, trace *Trace, nbVariables int) {
 
-	// nbVariables := spr.NbInternalVariables + len(spr.Public) + len(spr.Secret)
 	sizeSolution := len(trace.Ql.Coefficients())
-	sizePermutation := 3 * sizeSolution
-
-	// init permutation
-	permutation := make([]int64, sizePermutation)
-	for i := 0; i < len(permutation); i++ {
-		permutation[i] = -1
-	}
 
 	// init LRO position -> variable_ID
-	lro := make([]int, sizePermutation) // position -> variable_ID
+	var lro [3][]int
+	for i := range lro {
+		lro[i] = make([]int, sizeSolution)
+	}
 	for i := 0; i < len(spr.Public); i++ {
-		lro[i] = i // IDs of LRO associated to placeholders (only L needs to be taken care of)
+		lro[0][i] = i // IDs of LRO associated to placeholders (only L needs to be taken care of)
 	}
 
 	offset := len(spr.Public)
@@ -294,37 +385,18 @@ func buildPermutation(spr *cs.SparseR1CS, trace *Trace, nbVariables int) {
 	j := 0
 	it := spr.GetSparseR1CIterator()
 	for c := it.Next(); c != nil; c = it.Next() {
-		lro[offset+j] = int(c.XA)
-		lro[sizeSolution+offset+j] = int(c.XB)
-		lro[2*sizeSolution+offset+j] = int(c.XC)
+		lro[0][offset+j] = int(c.XA)
+		lro[1][offset+j] = int(c.XB)
+		lro[2][offset+j] = int(c.XC)
 
 		j++
 	}
 
-	// init cycle:
-	// map ID -> last position the ID was seen
-	cycle := make([]int64, nbVariables)
-	for i := 0; i < len(cycle); i++ {
-		cycle[i] = -1
+	s, err := permutation.Build(lro[:], nbVariables)
+	if err != nil {
+		panic(err) // the wires of the constraints are in range by construction
 	}
-
-	for i := 0; i < len(lro); i++ {
-		if cycle[lro[i]] != -1 {
-			// if != -1, it means we already encountered this value
-			// so we need to set the corresponding permutation index.
-			permutation[i] = cycle[lro[i]]
-		}
-		cycle[lro[i]] = int64(i)
-	}
-
-	// complete the Permutation by filling the first IDs encountered
-	for i := 0; i < sizePermutation; i++ {
-		if permutation[i] == -1 {
-			permutation[i] = cycle[lro[i]]
-		}
-	}
-
-	trace.S = permutation
+	trace.S = s
 }
 
 // computePermutationPolynomials computes the LDE (Lagrange basis) of the permutation.
@@ -0,0 +1,93 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package shplonk
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchOpen(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := kzg.NewSRS(64, big.NewInt(42))
+	assert.NoError(err)
+
+	// polynomials of different degrees, opened at overlapping sets of points
+	sizes := []int{10, 23, 7}
+	points := [][]fr.Element{randomPoints(2), randomPoints(1), randomPoints(3)}
+	points[1] = append(points[1], points[0][1])
+	polynomials := make([][]fr.Element, len(sizes))
+	digests := make([]kzg.Digest, len(sizes))
+	for i, size := range sizes {
+		polynomials[i] = randomPoints(size)
+		digests[i], err = kzg.Commit(polynomials[i], srs.Pk)
+		assert.NoError(err)
+	}
+
+	proof, err := BatchOpen(polynomials, digests, points, sha256.New(), srs.Pk, []byte("data"))
+	assert.NoError(err)
+	assert.Equal(eval(polynomials[1], points[1][1]), proof.ClaimedValues[1][1])
+	assert.NoError(BatchVerify(&proof, digests, points, sha256.New(), srs.Vk, []byte("data")))
+
+	// wrong transcript data
+	assert.Error(BatchVerify(&proof, digests, points, sha256.New(), srs.Vk, []byte("other")))
+
+	// wrong claimed value
+	proof.ClaimedValues[2][1].SetOne()
+	assert.ErrorIs(BatchVerify(&proof, digests, points, sha256.New(), srs.Vk, []byte("data")), ErrVerifyOpening)
+	proof.ClaimedValues[2][1] = eval(polynomials[2], points[2][1])
+
+	// wrong digest
+	var tampered curve.G1Affine
+	tampered.Add(&digests[0], &srs.Vk.G1)
+	digests[0] = tampered
+	assert.Error(BatchVerify(&proof, digests, points, sha256.New(), srs.Vk, []byte("data")))
+
+	// invalid opening sets
+	_, err = BatchOpen(polynomials, digests, points[:2], sha256.New(), srs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbPoints)
+	points[0][1] = points[0][0]
+	_, err = BatchOpen(polynomials, digests, points, sha256.New(), srs.Pk)
+	assert.ErrorIs(err, ErrDuplicatePoint)
+}
+
+func TestInterpolate(t *testing.T) {
+	s, v := randomPoints(4), randomPoints(4)
+	r := interpolate(s, v)
+	for i := range s {
+		if got := eval(r, s[i]); !got.Equal(&v[i]) {
+			t.Fatalf("r(s[%d]) = %s, expected %s", i, got.String(), v[i].String())
+		}
+	}
+}
+
+func randomPoints(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
@@ -53,12 +53,93 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return fmt.Errorf("create backend config: %w", err)
 	}
 
+	o, err := verifyRelation(proof, vk, publicWitness, &cfg)
+	if err != nil {
+		return err
+	}
+	if err := kzg.BatchVerifyMultiPoints(o.digests, o.proofs, o.points, vk.Kzg); err != nil {
+		return backend.NewVerificationError(backend.CheckCommitmentOpening, err)
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+
+	return nil
+}
+
+// VerifyDeferred verifies the proof but the pairing check of its KZG openings,
+// which it returns: the proof is valid if [kzg.BatchVerifyMultiPoints] accepts
+// the digests opened at the points with the KZG verifying key of vk. It lets
+// the callers check the openings of several proofs later, or accumulate them.
+func VerifyDeferred(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...backend.VerifierOption) ([]kzg.Digest, []kzg.OpeningProof, []fr.Element, error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create backend config: %w", err)
+	}
+	o, err := verifyRelation(proof, vk, publicWitness, &cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return o.digests, o.proofs, o.points, nil
+}
+
+// BatchVerify verifies several proofs at once, sharing the pairing check of
+// their KZG opening proofs, which are folded with random coefficients. The
+// verifying keys must share the same KZG setup. If the batch is rejected, the
+// error doesn't tell which proof is invalid: the proofs must then be verified
+// individually with [Verify].
+func BatchVerify(proofs []*Proof, vks []*VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	log := logger.Logger().With().Str("curve", "bls24-315").Str("backend", "plonk").Logger()
+	start := time.Now()
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+	if len(proofs) != len(vks) || len(proofs) != len(publicWitnesses) {
+		return errors.New("the numbers of proofs, verifying keys and public witnesses differ")
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	var all openings
+	for i := range proofs {
+		if !vks[i].Kzg.G1.Equal(&vks[0].Kzg.G1) || !vks[i].Kzg.G2[0].Equal(&vks[0].Kzg.G2[0]) || !vks[i].Kzg.G2[1].Equal(&vks[0].Kzg.G2[1]) {
+			return fmt.Errorf("proof %d: the verifying keys don't share the same KZG setup", i)
+		}
+		o, err := verifyRelation(proofs[i], vks[i], publicWitnesses[i], &cfg)
+		if err != nil {
+			return fmt.Errorf("proof %d: %w", i, err)
+		}
+		all.digests = append(all.digests, o.digests...)
+		all.proofs = append(all.proofs, o.proofs...)
+		all.points = append(all.points, o.points...)
+	}
+	if err := kzg.BatchVerifyMultiPoints(all.digests, all.proofs, all.points, vks[0].Kzg); err != nil {
+		return backend.NewVerificationError(backend.CheckCommitmentOpening, err)
+	}
+
+	log.Debug().Int("nbProofs", len(proofs)).Dur("took", time.Since(start)).Msg("batch verifier done")
+
+	return nil
+}
+
+// openings are the KZG openings left to check once the algebraic relation of a
+// proof holds.
+type openings struct {
+	digests []kzg.Digest
+	proofs  []kzg.OpeningProof
+	points  []fr.Element
+}
+
+// verifyRelation verifies the proof but the pairing check of its KZG openings,
+// which it returns.
+func verifyRelation(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, cfg *backend.VerifierConfig) (openings, error) {
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
-		return errors.New("BSB22 Commitment number mismatch")
+		return openings{}, backend.NewVerificationError(backend.CheckProofFormat, errors.New("BSB22 Commitment number mismatch"))
 	}
 
 	if len(publicWitness) != int(vk.NbPublicVariables) {
-		return errInvalidWitness
+		return openings{}, backend.NewVerificationError(backend.CheckPublicWitness, errInvalidWitness)
 	}
 
 	// transcript to derive the challenge
@@ -68,17 +149,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
 	if err := bindPublicData(fs, "gamma", vk, publicWitness); err != nil {
-		return err
+		return openings{}, err
 	}
 	gamma, err := deriveRandomness(fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
 	if err != nil {
-		return err
+		return openings{}, err
 	}
 
 	// derive beta from Comm(l), Comm(r), Comm(o)
 	beta, err := deriveRandomness(fs, "beta")
 	if err != nil {
-		return err
+		return openings{}, err
 	}
 
 	// derive alpha from Com(Z), Bsb22Commitments
@@ -89,13 +170,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	alphaDeps[len(alphaDeps)-1] = &proof.Z
 	alpha, err := deriveRandomness(fs, "alpha", alphaDeps...)
 	if err != nil {
-		return err
+		return openings{}, err
 	}
 
 	// derive zeta, the point of evaluation
 	zeta, err := deriveRandomness(fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
 	if err != nil {
-		return err
+		return openings{}, err
 	}
 
 	// evaluation of zhZeta=ζⁿ-1
@@ -196,7 +277,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// check that the opening of the linearised polynomial is equal to -constLin
 	openingLinPol := proof.BatchedProof.ClaimedValues[0]
 	if !constLin.Equal(&openingLinPol) {
-		return errAlgebraicRelation
+		return openings{}, backend.NewVerificationError(backend.CheckQuotientIdentity, errAlgebraicRelation)
 	}
 
 	// computing the linearised polynomial digest
@@ -253,7 +334,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zh, zetaNPlusTwoZh, zetaNPlusTwoSquareZh,
 	)
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return openings{}, err
 	}
 
 	// Fold the first proof
@@ -273,30 +354,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zu.Marshal(),
 	)
 	if err != nil {
-		return err
+		return openings{}, err
 	}
 
-	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
-		foldedDigest,
-		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.Kzg,
-	)
-
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-
-	return err
+	return openings{
+		digests: []kzg.Digest{foldedDigest, proof.Z},
+		proofs:  []kzg.OpeningProof{foldedProof, proof.ZShiftedOpening},
+		points:  []fr.Element{zeta, shiftedZeta},
+	}, nil
 }
 
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKey, publicInputs []fr.Element) error {
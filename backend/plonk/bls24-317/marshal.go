@@ -19,8 +19,17 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
 	"io"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
 )
 
 // WriteRawTo writes binary encoding of Proof to w without point compression
@@ -256,6 +265,213 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	if vk.Qcp == nil {
 		vk.Qcp = []kzg.Digest{}
 	}
+	vk.SRSSize = srsSize(vk.Size)
+
+	return dec.BytesRead(), nil
+}
+
+// ReadFromV09 reads a VerifyingKey serialized by gnark v0.9. The format is the
+// same as ReadFrom, without the precomputed lines of the KZG verifying key,
+// which are computed from [τ]G₂.
+func (vk *VerifyingKey) ReadFromV09(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	toDecode := []interface{}{
+		&vk.Size,
+		&vk.SizeInv,
+		&vk.Generator,
+		&vk.NbPublicVariables,
+		&vk.CosetShift,
+		&vk.S[0],
+		&vk.S[1],
+		&vk.S[2],
+		&vk.Ql,
+		&vk.Qr,
+		&vk.Qm,
+		&vk.Qo,
+		&vk.Qk,
+		&vk.Qcp,
+		&vk.Kzg.G1,
+		&vk.Kzg.G2[0],
+		&vk.Kzg.G2[1],
+		&vk.CommitmentConstraintIndexes,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	if vk.Qcp == nil {
+		vk.Qcp = []kzg.Digest{}
+	}
+	vk.SRSSize = srsSize(vk.Size)
+	vk.Kzg.Lines[0] = curve.PrecomputeLines(vk.Kzg.G2[0])
+	vk.Kzg.Lines[1] = curve.PrecomputeLines(vk.Kzg.G2[1])
+
+	return dec.BytesRead(), nil
+}
+
+// ReadFromV09 reads a ProvingKey serialized by gnark v0.9. The v0.9 format is
+// the verifying key, the FFT domains, the KZG keys and the polynomials of the
+// trace; the domains and the trace are recomputed from the constraint system
+// by the prover since v0.10, so they are skipped and the reader is not
+// consumed after the KZG keys.
+func (pk *ProvingKey) ReadFromV09(r io.Reader) (int64, error) {
+	pk.Vk = &VerifyingKey{}
+	n, err := pk.Vk.ReadFromV09(r)
+	if err != nil {
+		return n, err
+	}
 
+	var domain fft.Domain
+	for i := 0; i < 2; i++ {
+		n2, err := domain.ReadFrom(r)
+		n += n2
+		if err != nil {
+			return n, err
+		}
+	}
+
+	n2, err := pk.Kzg.ReadFrom(r)
+	n += n2
+	if err != nil {
+		return n, err
+	}
+	n2, err = pk.KzgLagrange.ReadFrom(r)
+	n += n2
+	return n, err
+}
+
+// checkpointName is the name of the file of the checkpoint of the prover in
+// the checkpoint directory.
+const checkpointName = "plonk-bls24-317.checkpoint"
+
+// checkpointPhase is the last phase of the prover saved in a checkpoint.
+type checkpointPhase uint8
+
+const (
+	// checkpointSolved is saved after solving the constraint system, with the
+	// public inputs, the BSB22 commitments and L, R, O in Lagrange form.
+	checkpointSolved checkpointPhase = iota + 1
+	// checkpointZ is saved after committing to L, R, O and Z, with in
+	// addition the blinding polynomials, Z in Lagrange form and the
+	// commitments.
+	checkpointZ
+	// checkpointQuotient is saved after committing to the quotient H, with in
+	// addition H, and L, R, O, Z and the BSB22 committed polynomials in
+	// canonical form.
+	checkpointQuotient
+)
+
+// checkpoint is the state of the prover saved with backend.WithCheckpointDir.
+// The challenges are not saved, they are derived again from the commitments.
+type checkpoint struct {
+	phase            checkpointPhase
+	public           witness.Witness
+	l, r, o          []fr.Element
+	commitmentVal    []fr.Element
+	bsb22Commitments []kzg.Digest
+	cCommitments     [][]fr.Element
+
+	// from checkpointZ
+	blinding [][]fr.Element
+	lro      []kzg.Digest
+	z        []fr.Element
+	zDigest  kzg.Digest
+
+	// from checkpointQuotient
+	h       []fr.Element
+	hDigest []kzg.Digest
+}
+
+// save writes the checkpoint to the checkpoint directory of the options, if
+// any.
+func (ck *checkpoint) save(opt *backend.ProverConfig) error {
+	if opt.CheckpointDir == "" {
+		return nil
+	}
+	if err := backend.WriteCheckpoint(opt.CheckpointDir, checkpointName, ck); err != nil {
+		return err
+	}
+	if checkpointSaved != nil {
+		return checkpointSaved(ck.phase)
+	}
+	return nil
+}
+
+// checkpointSaved is called once a checkpoint is saved, if set. The tests set
+// it to interrupt the prover after a phase.
+var checkpointSaved func(phase checkpointPhase) error
+
+// WriteTo writes the checkpoint with the points in uncompressed form.
+func (ck *checkpoint) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	toEncode := []interface{}{
+		uint8(ck.phase),
+		ck.public,
+		ck.l,
+		ck.r,
+		ck.o,
+		ck.commitmentVal,
+		ck.bsb22Commitments,
+		ck.cCommitments,
+	}
+	if ck.phase >= checkpointZ {
+		toEncode = append(toEncode, ck.blinding, ck.lro, ck.z, &ck.zDigest)
+	}
+	if ck.phase == checkpointQuotient {
+		toEncode = append(toEncode, ck.h, ck.hDigest)
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads a checkpoint written by WriteTo.
+func (ck *checkpoint) ReadFrom(r io.Reader) (int64, error) {
+	var err error
+	if ck.public, err = witness.New(fr.Modulus()); err != nil {
+		return 0, err
+	}
+	dec := curve.NewDecoder(r)
+	var phase uint8
+	toDecode := []interface{}{
+		&phase,
+		ck.public,
+		&ck.l,
+		&ck.r,
+		&ck.o,
+		&ck.commitmentVal,
+		&ck.bsb22Commitments,
+		&ck.cCommitments,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	ck.phase = checkpointPhase(phase)
+	switch ck.phase {
+	case checkpointSolved:
+		return dec.BytesRead(), nil
+	case checkpointZ:
+		toDecode = []interface{}{&ck.blinding, &ck.lro, &ck.z, &ck.zDigest}
+	case checkpointQuotient:
+		toDecode = []interface{}{&ck.blinding, &ck.lro, &ck.z, &ck.zDigest, &ck.h, &ck.hDigest}
+	default:
+		return dec.BytesRead(), fmt.Errorf("unknown checkpoint phase %d", phase)
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	if len(ck.blinding) != nb_blinding_polynomials || len(ck.lro) != 3 || (ck.phase == checkpointQuotient && len(ck.hDigest) != 3) {
+		return dec.BytesRead(), errors.New("invalid checkpoint")
+	}
 	return dec.BytesRead(), nil
 }
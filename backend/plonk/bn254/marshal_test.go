@@ -69,6 +69,7 @@ func (pk *ProvingKey) randomize() {
 
 func (vk *VerifyingKey) randomize() {
 	vk.Size = rand.Uint64() //#nosec G404 weak rng is fine here
+	vk.SRSSize = srsSize(vk.Size)
 	vk.SizeInv.SetRandom()
 	vk.Generator.SetRandom()
 	vk.NbPublicVariables = rand.Uint64()                     //#nosec G404 weak rng is fine here
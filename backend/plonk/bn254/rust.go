@@ -0,0 +1,342 @@
+package plonk
+
+import (
+	"io"
+	"text/template"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/rust"
+)
+
+// ExportRust writes the sources of a Rust verifier of the proofs of vk to w,
+// for the target of the options (see [rust.WithTarget]): a Solana program
+// using the alt_bn128 syscalls or a CosmWasm contract. Use [rust.ExportCrate]
+// to write the complete crate.
+//
+// The proofs are serialized with [Proof.MarshalSolidity] and must be computed
+// with the default hash functions of the prover, as for the Solidity verifier.
+//
+// This is an experimental feature, the generated code has not been audited.
+func (vk *VerifyingKey) ExportRust(w io.Writer, exportOpts ...rust.ExportOption) error {
+	cfg, err := rust.NewExportConfig(exportOpts...)
+	if err != nil {
+		return err
+	}
+	tmpl, err := rust.NewTemplate(tmplRustVerifier, template.FuncMap{
+		"g1": func(p curve.G1Affine) string {
+			b := p.RawBytes()
+			return rust.Bytes(b[:])
+		},
+		"g2": func(p curve.G2Affine) string {
+			b := p.RawBytes()
+			return rust.Bytes(b[:])
+		},
+		"fr": func(x fr.Element) string {
+			b := x.Bytes()
+			return rust.Bytes(b[:])
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "plonk", struct {
+		Cfg rust.ExportConfig
+		Vk  *VerifyingKey
+	}{
+		Cfg: cfg,
+		Vk:  vk,
+	})
+}
+
+const tmplRustVerifier = `
+{{- define "plonk" -}}
+// Code generated by gnark DO NOT EDIT
+
+//! PLONK verifier over BN254 for {{ .Cfg.Target }}.
+//!
+//! The proofs are serialized with gnark's MarshalSolidity and the public
+//! inputs are 32 bytes big-endian integers, reduced modulo r.
+
+#![allow(dead_code, unused_imports)]
+
+/// Number of public inputs.
+pub const NB_PUBLIC_INPUTS: usize = {{ .Vk.NbPublicVariables }};
+
+/// Number of BSB22 commitments (custom gates).
+const NB_CUSTOM_GATES: usize = {{ len .Vk.CommitmentConstraintIndexes }};
+
+const DOMAIN_SIZE: u64 = {{ .Vk.Size }};
+const INV_DOMAIN_SIZE: [u8; 32] = {{ fr .Vk.SizeInv }};
+const OMEGA: [u8; 32] = {{ fr .Vk.Generator }};
+const COSET_SHIFT: [u8; 32] = {{ fr .Vk.CosetShift }};
+
+const G1_SRS: [u8; 64] = {{ g1 .Vk.Kzg.G1 }};
+const G2_SRS: [[u8; 128]; 2] = [{{ g2 (index .Vk.Kzg.G2 0) }}, {{ g2 (index .Vk.Kzg.G2 1) }}];
+
+const QL: [u8; 64] = {{ g1 .Vk.Ql }};
+const QR: [u8; 64] = {{ g1 .Vk.Qr }};
+const QM: [u8; 64] = {{ g1 .Vk.Qm }};
+const QO: [u8; 64] = {{ g1 .Vk.Qo }};
+const QK: [u8; 64] = {{ g1 .Vk.Qk }};
+const S: [[u8; 64]; 3] = [
+{{- range $s := .Vk.S }}
+    {{ g1 $s }},
+{{- end }}
+];
+const QCP: [[u8; 64]; NB_CUSTOM_GATES] = [
+{{- range $q := .Vk.Qcp }}
+    {{ g1 $q }},
+{{- end }}
+];
+const COMMITMENT_CONSTRAINT_INDEXES: [u64; NB_CUSTOM_GATES] = [{{ range $i, $c := .Vk.CommitmentConstraintIndexes }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}];
+
+// offsets in the proof
+const PROOF_L_COM: usize = 0x0;
+const PROOF_R_COM: usize = 0x40;
+const PROOF_O_COM: usize = 0x80;
+const PROOF_H_0: usize = 0xc0;
+const PROOF_H_1: usize = 0x100;
+const PROOF_H_2: usize = 0x140;
+const PROOF_L_AT_ZETA: usize = 0x180;
+const PROOF_R_AT_ZETA: usize = 0x1a0;
+const PROOF_O_AT_ZETA: usize = 0x1c0;
+const PROOF_S1_AT_ZETA: usize = 0x1e0;
+const PROOF_S2_AT_ZETA: usize = 0x200;
+const PROOF_GRAND_PRODUCT_COMMITMENT: usize = 0x220;
+const PROOF_GRAND_PRODUCT_AT_ZETA_OMEGA: usize = 0x260;
+const PROOF_BATCH_OPENING_AT_ZETA: usize = 0x280;
+const PROOF_OPENING_AT_ZETA_OMEGA: usize = 0x2c0;
+const PROOF_OPENING_QCP_AT_ZETA: usize = 0x300;
+const PROOF_BSB_COMMITMENTS: usize = PROOF_OPENING_QCP_AT_ZETA + 0x20 * NB_CUSTOM_GATES;
+const PROOF_SIZE: usize = PROOF_BSB_COMMITMENTS + 0x40 * NB_CUSTOM_GATES;
+
+/// Domain separation tag of the hash to the field of the BSB22 commitments.
+const HASH_FR_DST: &[u8] = b"BSB22-Plonk";
+
+/// Verifies the proof for the public inputs.
+pub fn verify(proof: &[u8], public_inputs: &[[u8; 32]]) -> Result<(), VerifierError> {
+    if public_inputs.len() != NB_PUBLIC_INPUTS {
+        return Err(VerifierError::InvalidPublicInputsNumber);
+    }
+    if proof.len() != PROOF_SIZE {
+        return Err(VerifierError::InvalidProofSize);
+    }
+    let mut inputs = Vec::with_capacity(NB_PUBLIC_INPUTS);
+    for input in public_inputs {
+        inputs.push(fr_from_be(input)?);
+    }
+    let l = fr_at(proof, PROOF_L_AT_ZETA)?;
+    let r = fr_at(proof, PROOF_R_AT_ZETA)?;
+    let o = fr_at(proof, PROOF_O_AT_ZETA)?;
+    let s1 = fr_at(proof, PROOF_S1_AT_ZETA)?;
+    let s2 = fr_at(proof, PROOF_S2_AT_ZETA)?;
+    let zu = fr_at(proof, PROOF_GRAND_PRODUCT_AT_ZETA_OMEGA)?;
+    let mut qcp = Vec::with_capacity(NB_CUSTOM_GATES);
+    for i in 0..NB_CUSTOM_GATES {
+        qcp.push(fr_at(proof, PROOF_OPENING_QCP_AT_ZETA + 0x20 * i)?);
+    }
+    let inv_n = fr_from_be(&INV_DOMAIN_SIZE)?;
+    let omega = fr_from_be(&OMEGA)?;
+    let coset_shift = fr_from_be(&COSET_SHIFT)?;
+
+    // challenges
+    let (gamma, beta, alpha, zeta) = derive_challenges(proof, public_inputs);
+    let zeta_n = zeta.pow([DOMAIN_SIZE]);
+    let zh = zeta_n - Fr::one(); // ζⁿ-1
+
+    // PI(ζ) = ∑ᵢ Lᵢ(ζ)wᵢ, Lᵢ(ζ) = ωⁱ/n (ζⁿ-1)/(ζ-ωⁱ)
+    let mut dens = Vec::with_capacity(NB_PUBLIC_INPUTS + NB_CUSTOM_GATES + 1);
+    let mut wi = Fr::one();
+    let mut ws = Vec::with_capacity(NB_PUBLIC_INPUTS + NB_CUSTOM_GATES);
+    for _ in 0..NB_PUBLIC_INPUTS {
+        ws.push(wi);
+        dens.push(zeta - wi);
+        wi *= omega;
+    }
+    for i in 0..NB_CUSTOM_GATES {
+        let w = omega.pow([NB_PUBLIC_INPUTS as u64 + COMMITMENT_CONSTRAINT_INDEXES[i]]);
+        ws.push(w);
+        dens.push(zeta - w);
+    }
+    dens.push(zeta - Fr::one());
+    batch_invert(&mut dens)?;
+    let zh_n = zh * inv_n;
+    let mut pi = Fr::from(0u64);
+    for i in 0..NB_PUBLIC_INPUTS {
+        pi += zh_n * ws[i] * dens[i] * inputs[i];
+    }
+    for i in 0..NB_CUSTOM_GATES {
+        let j = NB_PUBLIC_INPUTS + i;
+        let h = hash_fr(&proof[PROOF_BSB_COMMITMENTS + 0x40 * i..PROOF_BSB_COMMITMENTS + 0x40 * (i + 1)]);
+        pi += zh_n * ws[j] * dens[j] * h;
+    }
+
+    // α²L₁(ζ)
+    let alpha_square_lagrange_one = zh_n * dens[NB_PUBLIC_INPUTS + NB_CUSTOM_GATES] * alpha * alpha;
+
+    // opening of the linearised polynomial at ζ:
+    // -[PI(ζ) - α²L₁(ζ) + α(l(ζ)+βs1(ζ)+γ)(r(ζ)+βs2(ζ)+γ)(o(ζ)+γ)z(ωζ)]
+    let ls1 = l + beta * s1 + gamma;
+    let rs2 = r + beta * s2 + gamma;
+    let opening_lin = -(pi - alpha_square_lagrange_one + alpha * ls1 * rs2 * (o + gamma) * zu);
+
+    // [linearised polynomial] =
+    // l(ζ)[Ql] + r(ζ)[Qr] + l(ζ)r(ζ)[Qm] + o(ζ)[Qo] + [Qk] + ∑ᵢqcpᵢ(ζ)[Bsb22ᵢ] +
+    // _s1[S3] + coeff_z[Z] - (ζⁿ-1)([H₀] + ζⁿ⁺²[H₁] + ζ²⁽ⁿ⁺²⁾[H₂])
+    // where
+    // _s1 = αβz(ωζ)(l(ζ)+βs1(ζ)+γ)(r(ζ)+βs2(ζ)+γ)
+    // coeff_z = α²L₁(ζ) - α(l(ζ)+βζ+γ)(r(ζ)+βuζ+γ)(o(ζ)+βu²ζ+γ)
+    let _s1 = alpha * beta * zu * ls1 * rs2;
+    let beta_zeta = beta * zeta;
+    let coeff_z = alpha_square_lagrange_one
+        - alpha
+            * (l + beta_zeta + gamma)
+            * (r + beta_zeta * coset_shift + gamma)
+            * (o + beta_zeta * coset_shift * coset_shift + gamma);
+    let zeta_n_plus_two = zeta_n * zeta * zeta;
+
+    // folded H: -(ζⁿ-1)([H₀] + ζⁿ⁺²([H₁] + ζⁿ⁺²[H₂]))
+    let mut folded_h = bn254::mul(&g1_at(proof, PROOF_H_2), &fr_to_be(&zeta_n_plus_two))?;
+    folded_h = bn254::add(&folded_h, &g1_at(proof, PROOF_H_1))?;
+    folded_h = bn254::mul(&folded_h, &fr_to_be(&zeta_n_plus_two))?;
+    folded_h = bn254::add(&folded_h, &g1_at(proof, PROOF_H_0))?;
+    folded_h = bn254::mul(&folded_h, &fr_to_be(&-zh))?;
+
+    let mut lin = bn254::mul(&QL, &fr_to_be(&l))?;
+    lin = g1_acc_mul(&lin, &QR, &r)?;
+    lin = g1_acc_mul(&lin, &QM, &(l * r))?;
+    lin = g1_acc_mul(&lin, &QO, &o)?;
+    lin = bn254::add(&lin, &QK)?;
+    for i in 0..NB_CUSTOM_GATES {
+        lin = g1_acc_mul(&lin, &g1_at(proof, PROOF_BSB_COMMITMENTS + 0x40 * i), &qcp[i])?;
+    }
+    lin = g1_acc_mul(&lin, &S[2], &_s1)?;
+    lin = g1_acc_mul(&lin, &g1_at(proof, PROOF_GRAND_PRODUCT_COMMITMENT), &coeff_z)?;
+    lin = bn254::add(&lin, &folded_h)?;
+
+    // fold the openings at ζ of the linearised polynomial, l, r, o, s1, s2
+    // and the qcpᵢ
+    let mut digests = vec![
+        lin,
+        g1_at(proof, PROOF_L_COM),
+        g1_at(proof, PROOF_R_COM),
+        g1_at(proof, PROOF_O_COM),
+        S[0],
+        S[1],
+    ];
+    digests.extend_from_slice(&QCP);
+    let mut claimed_values = vec![opening_lin, l, r, o, s1, s2];
+    claimed_values.extend_from_slice(&qcp);
+
+    let mut transcript = Vec::with_capacity(5 + 0x20 + 0x60 * digests.len() + 0x20);
+    transcript.extend_from_slice(b"gamma");
+    transcript.extend_from_slice(&fr_to_be(&zeta));
+    for d in &digests {
+        transcript.extend_from_slice(d);
+    }
+    for v in &claimed_values {
+        transcript.extend_from_slice(&fr_to_be(v));
+    }
+    transcript.extend_from_slice(&fr_to_be(&zu));
+    let gamma_kzg = Fr::from_be_bytes_mod_order(&sha256(&[&transcript]));
+
+    let mut folded_digest = digests[0];
+    let mut folded_value = claimed_values[0];
+    let mut acc_gamma = Fr::one();
+    for i in 1..digests.len() {
+        acc_gamma *= gamma_kzg;
+        folded_digest = g1_acc_mul(&folded_digest, &digests[i], &acc_gamma)?;
+        folded_value += acc_gamma * claimed_values[i];
+    }
+
+    // batch the openings at ζ and ωζ with a random λ derived from the
+    // digests, the opening proofs and ζ.
+    let opening_zeta = g1_at(proof, PROOF_BATCH_OPENING_AT_ZETA);
+    let z = g1_at(proof, PROOF_GRAND_PRODUCT_COMMITMENT);
+    let opening_zeta_omega = g1_at(proof, PROOF_OPENING_AT_ZETA_OMEGA);
+    let lambda = Fr::from_be_bytes_mod_order(&sha256(&[
+        &folded_digest,
+        &opening_zeta,
+        &z,
+        &opening_zeta_omega,
+        &fr_to_be(&zeta),
+        &fr_to_be(&gamma_kzg),
+    ]));
+
+    // e(F + λ[Z] - [f + λz(ωζ)]G₁ + [ζ]W + [λωζ]W', [1]₂) e(-(W + λW'), [τ]₂) = 1
+    let folded_quotients = g1_acc_mul(&opening_zeta, &opening_zeta_omega, &lambda)?;
+    let mut folded = g1_acc_mul(&folded_digest, &z, &lambda)?;
+    folded = g1_acc_mul(&folded, &G1_SRS, &-(folded_value + lambda * zu))?;
+    folded = g1_acc_mul(&folded, &opening_zeta, &zeta)?;
+    folded = g1_acc_mul(&folded, &opening_zeta_omega, &(lambda * zeta * omega))?;
+
+    let mut pairing = Vec::with_capacity(384);
+    pairing.extend_from_slice(&folded);
+    pairing.extend_from_slice(&G2_SRS[0]);
+    pairing.extend_from_slice(&g1_neg(&folded_quotients));
+    pairing.extend_from_slice(&G2_SRS[1]);
+    if !bn254::pairing_check(&pairing)? {
+        return Err(VerifierError::ProofInvalid);
+    }
+    Ok(())
+}
+
+/// Returns the challenges γ, β, α and ζ. Each challenge is the SHA-256 of its
+/// name, of the previous challenge (not reduced) and of its bindings.
+fn derive_challenges(proof: &[u8], public_inputs: &[[u8; 32]]) -> (Fr, Fr, Fr, Fr) {
+    // γ binds the verifying key, the public inputs and [L], [R], [O]
+    let mut transcript = Vec::with_capacity(5 + 0x40 * (8 + NB_CUSTOM_GATES) + 0x20 * NB_PUBLIC_INPUTS + 0xc0);
+    transcript.extend_from_slice(b"gamma");
+    for p in [&S[0], &S[1], &S[2], &QL, &QR, &QM, &QO, &QK] {
+        transcript.extend_from_slice(p);
+    }
+    for p in &QCP {
+        transcript.extend_from_slice(p);
+    }
+    for input in public_inputs {
+        transcript.extend_from_slice(input);
+    }
+    transcript.extend_from_slice(&proof[PROOF_L_COM..PROOF_H_0]);
+    let gamma = sha256(&[&transcript]);
+
+    // β binds nothing more
+    let beta = sha256(&[b"beta", &gamma]);
+
+    // α binds the BSB22 commitments and [Z]
+    let alpha = sha256(&[
+        b"alpha",
+        &beta,
+        &proof[PROOF_BSB_COMMITMENTS..PROOF_SIZE],
+        &proof[PROOF_GRAND_PRODUCT_COMMITMENT..PROOF_GRAND_PRODUCT_AT_ZETA_OMEGA],
+    ]);
+
+    // ζ binds [H₀], [H₁], [H₂]
+    let zeta = sha256(&[b"zeta", &alpha, &proof[PROOF_H_0..PROOF_L_AT_ZETA]]);
+
+    (
+        Fr::from_be_bytes_mod_order(&gamma),
+        Fr::from_be_bytes_mod_order(&beta),
+        Fr::from_be_bytes_mod_order(&alpha),
+        Fr::from_be_bytes_mod_order(&zeta),
+    )
+}
+
+/// Hashes the BSB22 commitment to the field, with expand_message_xmd of
+/// RFC 9380 with SHA-256 to 48 bytes.
+fn hash_fr(commitment: &[u8]) -> Fr {
+    let dst_prime = [HASH_FR_DST, &[HASH_FR_DST.len() as u8]].concat();
+    let b0 = sha256(&[&[0u8; 64], commitment, &[0, 48, 0], &dst_prime]);
+    let b1 = sha256(&[&b0, &[1], &dst_prime]);
+    let mut b0_xor_b1 = [0u8; 32];
+    for i in 0..32 {
+        b0_xor_b1[i] = b0[i] ^ b1[i];
+    }
+    let b2 = sha256(&[&b0_xor_b1, &[2], &dst_prime]);
+    let mut uniform = [0u8; 48];
+    uniform[..32].copy_from_slice(&b1);
+    uniform[32..].copy_from_slice(&b2[..16]);
+    Fr::from_be_bytes_mod_order(&uniform)
+}
+{{ template "runtime" .Cfg }}
+{{ end }}`
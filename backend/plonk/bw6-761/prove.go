@@ -80,7 +80,10 @@ const (
 	nb_blinding_polynomials
 )
 
-// blinding orders (-1 to deactivate)
+// blinding orders (-1 to deactivate), the blinding polynomials are zero with
+// the backend.WithUnsafeNoBlinding option. They aren't configurable, as the
+// verifiers expect the quotient in pieces of n+2 coefficients, and they set
+// the size of the SRS, see srsSize.
 const (
 	order_blinding_L = 1
 	order_blinding_R = 1
@@ -109,29 +112,75 @@ type Proof struct {
 }
 
 func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
-
-	log := logger.Logger().With().
-		Str("curve", spr.CurveID().String()).
-		Int("nbConstraints", spr.GetNbConstraints()).
-		Str("backend", "plonk").Logger()
-
 	// parse the options
 	opt, err := backend.NewProverConfig(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("get prover options: %w", err)
 	}
+	return prove(spr, pk, fullWitness, &opt, nil)
+}
+
+// ResumeProve completes the proof from the checkpoint saved by [Prove] in the
+// directory set with backend.WithCheckpointDir, after a crash or a
+// preemption. Prove saves a checkpoint after solving the constraint system,
+// after committing to L, R, O and Z, and after committing to the quotient H,
+// and the proof is resumed from the last one. It returns
+// backend.ErrNoCheckpoint if there is no checkpoint to resume from. The
+// constraint system and the proving key must be the ones of the interrupted
+// proof and the other prover options are applied as in Prove, except for the
+// blinding polynomials which are restored from the last two checkpoints.
+func ResumeProve(spr *cs.SparseR1CS, pk *ProvingKey, opts ...backend.ProverOption) (*Proof, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("get prover options: %w", err)
+	}
+	if opt.CheckpointDir == "" {
+		return nil, errors.New("no checkpoint directory")
+	}
+
+	var ck checkpoint
+	if err := backend.ReadCheckpoint(opt.CheckpointDir, checkpointName, &ck); err != nil {
+		return nil, err
+	}
+	public, ok := ck.public.Vector().(fr.Vector)
+	if !ok || len(public) != len(spr.Public) || len(ck.cCommitments) != len(spr.CommitmentInfo.(constraint.PlonkCommitments)) {
+		return nil, errors.New("checkpoint doesn't match the constraint system")
+	}
+	n := int(pk.Vk.Size)
+	if len(ck.l) != n || len(ck.r) != n || len(ck.o) != n ||
+		(ck.phase >= checkpointZ && len(ck.z) != n) ||
+		(ck.phase == checkpointQuotient && len(ck.h) != 3*(n+2)) {
+		return nil, errors.New("checkpoint doesn't match the proving key")
+	}
+	return prove(spr, pk, ck.public, &opt, &ck)
+}
+
+// prove computes the proof of fullWitness or, if ck is not nil, resumes it
+// from the checkpoint, in which case fullWitness may only contain the public
+// inputs.
+func prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opt *backend.ProverConfig, ck *checkpoint) (*Proof, error) {
+
+	log := logger.Logger().With().
+		Str("curve", spr.CurveID().String()).
+		Int("nbConstraints", spr.GetNbConstraints()).
+		Str("backend", "plonk").Logger()
 
 	start := time.Now()
 
 	// init instance
-	g, ctx := errgroup.WithContext(context.Background())
-	instance, err := newInstance(ctx, spr, pk, fullWitness, &opt)
+	g, ctx := errgroup.WithContext(opt.Context)
+	instance, err := newInstance(ctx, spr, pk, fullWitness, opt)
 	if err != nil {
 		return nil, fmt.Errorf("new instance: %w", err)
 	}
+	instance.ck = ck
 
 	// solve constraints
-	g.Go(instance.solveConstraints)
+	if ck == nil {
+		g.Go(instance.solveConstraints)
+	} else {
+		g.Go(instance.restoreSolution)
+	}
 
 	// complete qk
 	g.Go(instance.completeQk)
@@ -158,6 +207,13 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	g.Go(instance.batchOpening)
 
 	if err := g.Wait(); err != nil {
+		// the steps stop with errContextDone when the context is done
+		if ctxErr := opt.Context.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if err := backend.RemoveCheckpoint(opt, checkpointName); err != nil {
 		return nil, err
 	}
 
@@ -211,6 +267,9 @@ type instance struct {
 	domain0, domain1 *fft.Domain
 
 	trace *Trace
+
+	// checkpoint the proof is resumed from, if any
+	ck *checkpoint
 }
 
 func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts *backend.ProverConfig) (*instance, error) {
@@ -239,6 +298,10 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 		chRestoreLRO:           make(chan struct{}, 1),
 	}
 	s.initBSB22Commitments()
+	if opts.SideChannelHardening {
+		s.opt.SolverOpts = append(s.opt.SolverOpts, solver.WithConstantTime())
+	}
+	s.opt.SolverOpts = append(s.opt.SolverOpts, solver.WithContext(ctx))
 	s.x = make([]*iop.Polynomial, id_Qci+2*len(s.commitmentInfo))
 
 	// init fft domains
@@ -255,6 +318,12 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 		s.domain1 = fft.NewDomain(4*sizeSystem, fft.WithoutPrecompute())
 	}
 
+	// the blinded Z has n+order_blinding_Z+1 coefficients, it is committed
+	// with the canonical SRS.
+	if sizeSRS := srsSize(s.domain0.Cardinality); uint64(len(pk.Kzg.G1)) < sizeSRS {
+		return nil, fmt.Errorf("kzg srs is too small for the blinding: got %d, need %d", len(pk.Kzg.G1), sizeSRS)
+	}
+
 	// build trace
 	s.trace = NewTrace(spr, s.domain0)
 
@@ -262,6 +331,24 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 }
 
 func (s *instance) initBlindingPolynomials() error {
+	if s.ck != nil && s.ck.phase >= checkpointZ {
+		// the commitments of the checkpoint are blinded with its polynomials
+		for i := range s.bp {
+			s.bp[i] = iop.NewPolynomial(&s.ck.blinding[i], iop.Form{Basis: iop.Canonical, Layout: iop.Regular})
+		}
+		close(s.chbp)
+		return nil
+	}
+	if s.opt.UnsafeNoBlinding {
+		// the blinding polynomials are zero, but keep their sizes as the
+		// prover expects the blinded polynomials to be of degree n+order.
+		s.bp[id_Bl] = getZeroPolynomial(order_blinding_L)
+		s.bp[id_Br] = getZeroPolynomial(order_blinding_R)
+		s.bp[id_Bo] = getZeroPolynomial(order_blinding_O)
+		s.bp[id_Bz] = getZeroPolynomial(order_blinding_Z)
+		close(s.chbp)
+		return nil
+	}
 	s.bp[id_Bl] = getRandomPolynomial(order_blinding_L)
 	s.bp[id_Br] = getRandomPolynomial(order_blinding_R)
 	s.bp[id_Bo] = getRandomPolynomial(order_blinding_O)
@@ -295,14 +382,16 @@ func (s *instance) bsb22Hint(_ *big.Int, ins, outs []*big.Int) error {
 	for i := range ins {
 		committedValues[offset+commitmentInfo.Committed[i]].SetBigInt(ins[i])
 	}
-	if _, err = committedValues[offset+commitmentInfo.CommitmentIndex].SetRandom(); err != nil { // Commitment injection constraint has qcp = 0. Safe to use for blinding.
-		return err
-	}
-	if _, err = committedValues[offset+s.spr.GetNbConstraints()-1].SetRandom(); err != nil { // Last constraint has qcp = 0. Safe to use for blinding
-		return err
+	if !s.opt.UnsafeNoBlinding {
+		if _, err = committedValues[offset+commitmentInfo.CommitmentIndex].SetRandom(); err != nil { // Commitment injection constraint has qcp = 0. Safe to use for blinding.
+			return err
+		}
+		if _, err = committedValues[offset+s.spr.GetNbConstraints()-1].SetRandom(); err != nil { // Last constraint has qcp = 0. Safe to use for blinding
+			return err
+		}
 	}
 	s.cCommitments[commDepth] = iop.NewPolynomial(&committedValues, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
-	if s.proof.Bsb22Commitments[commDepth], err = kzg.Commit(s.cCommitments[commDepth].Coefficients(), s.pk.KzgLagrange); err != nil {
+	if s.proof.Bsb22Commitments[commDepth], err = kzgCommit(s.cCommitments[commDepth].Coefficients(), s.pk.KzgLagrange, s.opt.SideChannelHardening); err != nil {
 		return err
 	}
 
@@ -320,16 +409,103 @@ func (s *instance) bsb22Hint(_ *big.Int, ins, outs []*big.Int) error {
 }
 
 // solveConstraints computes the evaluation of the polynomials L, R, O
-// and sets x[id_L], x[id_R], x[id_O] in canonical form
+// and sets x[id_L], x[id_R], x[id_O] in canonical form. The solution is saved
+// in a checkpoint if the checkpoint directory is set.
 func (s *instance) solveConstraints() error {
 	_solution, err := s.spr.Solve(s.fullWitness, s.opt.SolverOpts...)
 	if err != nil {
 		return err
 	}
 	solution := _solution.(*cs.SparseR1CSSolution)
-	evaluationLDomainSmall := []fr.Element(solution.L)
-	evaluationRDomainSmall := []fr.Element(solution.R)
-	evaluationODomainSmall := []fr.Element(solution.O)
+	if s.opt.CheckpointDir != "" {
+		public, err := s.fullWitness.Public()
+		if err != nil {
+			return err
+		}
+		ck := &checkpoint{
+			phase:            checkpointSolved,
+			public:           public,
+			l:                solution.L,
+			r:                solution.R,
+			o:                solution.O,
+			commitmentVal:    s.commitmentVal,
+			bsb22Commitments: s.proof.Bsb22Commitments,
+			cCommitments:     coefficients(s.cCommitments),
+		}
+		if err := ck.save(s.opt); err != nil {
+			return err
+		}
+	}
+	return s.setSolution(solution.L, solution.R, solution.O)
+}
+
+// restoreSolution restores the solution of the constraint system and the
+// BSB22 commitments from the checkpoint, as solveConstraints computes them.
+// From the checkpointZ phase, the commitments to L, R, O are restored instead
+// of being computed again.
+func (s *instance) restoreSolution() error {
+	ck := s.ck
+	form := ck.form()
+	copy(s.commitmentVal, ck.commitmentVal)
+	copy(s.proof.Bsb22Commitments, ck.bsb22Commitments)
+	for i := range ck.cCommitments {
+		s.cCommitments[i] = iop.NewPolynomial(&ck.cCommitments[i], form)
+	}
+	if ck.phase == checkpointSolved {
+		return s.setSolution(ck.l, ck.r, ck.o)
+	}
+	s.x[id_L] = iop.NewPolynomial(&ck.l, form)
+	s.x[id_R] = iop.NewPolynomial(&ck.r, form)
+	s.x[id_O] = iop.NewPolynomial(&ck.o, form)
+	copy(s.proof.LRO[:], ck.lro)
+	close(s.chLRO)
+	return nil
+}
+
+// form returns the form of the polynomials saved in the checkpoint: the
+// quotient is computed in place on the polynomials, which are left in
+// canonical form.
+func (ck *checkpoint) form() iop.Form {
+	if ck.phase == checkpointQuotient {
+		return iop.Form{Basis: iop.Canonical, Layout: iop.Regular}
+	}
+	return iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+}
+
+// saveCheckpoint saves the state of the prover after committing to Z or to
+// the quotient H, if the checkpoint directory is set.
+func (s *instance) saveCheckpoint(phase checkpointPhase) error {
+	if s.opt.CheckpointDir == "" {
+		return nil
+	}
+	public, err := s.fullWitness.Public()
+	if err != nil {
+		return err
+	}
+	ck := &checkpoint{
+		phase:            phase,
+		public:           public,
+		l:                s.x[id_L].Coefficients(),
+		r:                s.x[id_R].Coefficients(),
+		o:                s.x[id_O].Coefficients(),
+		commitmentVal:    s.commitmentVal,
+		bsb22Commitments: s.proof.Bsb22Commitments,
+		cCommitments:     coefficients(s.cCommitments),
+		blinding:         coefficients(s.bp),
+		lro:              s.proof.LRO[:],
+		z:                s.x[id_Z].Coefficients(),
+		zDigest:          s.proof.Z,
+	}
+	if phase == checkpointQuotient {
+		ck.h = s.h.Coefficients()[:3*(s.domain0.Cardinality+2)]
+		ck.hDigest = s.proof.H[:]
+	}
+	return ck.save(s.opt)
+}
+
+// setSolution sets x[id_L], x[id_R], x[id_O] from the evaluations of L, R, O
+// and commits to them.
+func (s *instance) setSolution(evaluationLDomainSmall, evaluationRDomainSmall, evaluationODomainSmall []fr.Element) error {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
@@ -450,7 +626,7 @@ func (s *instance) deriveGammaAndBeta() error {
 // /!\ The polynomial p is supposed to be in Lagrange form.
 func (s *instance) commitToPolyAndBlinding(p, b *iop.Polynomial) (commit curve.G1Affine, err error) {
 
-	commit, err = kzg.Commit(p.Coefficients(), s.pk.KzgLagrange)
+	commit, err = kzgCommit(p.Coefficients(), s.pk.KzgLagrange, s.opt.SideChannelHardening)
 
 	// we add in the blinding contribution
 	n := int(s.domain0.Cardinality)
@@ -477,6 +653,10 @@ func (s *instance) deriveZeta() (err error) {
 
 // computeQuotient computes H
 func (s *instance) computeQuotient() (err error) {
+	if s.ck != nil && s.ck.phase == checkpointQuotient {
+		return s.restoreQuotient()
+	}
+
 	s.x[id_Ql] = s.trace.Ql
 	s.x[id_Qr] = s.trace.Qr
 	s.x[id_Qm] = s.trace.Qm
@@ -535,7 +715,7 @@ func (s *instance) computeQuotient() (err error) {
 	}
 
 	// commit to h
-	if err := commitToQuotient(s.h1(), s.h2(), s.h3(), s.proof, s.pk.Kzg); err != nil {
+	if err := commitToQuotient(s.h1(), s.h2(), s.h3(), s.proof, s.pk.Kzg, s.opt.SideChannelHardening); err != nil {
 		return err
 	}
 
@@ -550,6 +730,46 @@ func (s *instance) computeQuotient() (err error) {
 	case <-s.chRestoreLRO:
 	}
 
+	if err := s.saveCheckpoint(checkpointQuotient); err != nil {
+		return err
+	}
+
+	close(s.chH)
+
+	return nil
+}
+
+// restoreQuotient restores H and its commitments from the checkpoint, as
+// computeQuotient computes them. The polynomials of the trace are put in
+// canonical form, as computeQuotient leaves them.
+func (s *instance) restoreQuotient() error {
+	polys := append([]*iop.Polynomial{s.trace.Ql, s.trace.Qr, s.trace.Qm, s.trace.Qo, s.trace.S1, s.trace.S2, s.trace.S3}, s.trace.Qcp...)
+	var wg sync.WaitGroup
+	wg.Add(len(polys))
+	for _, p := range polys {
+		go func(p *iop.Polynomial) {
+			p.ToCanonical(s.domain0).ToRegular()
+			wg.Done()
+		}(p)
+	}
+	wg.Wait()
+
+	// wait for Z to be restored or context done
+	select {
+	case <-s.ctx.Done():
+		return errContextDone
+	case <-s.chZ:
+	}
+
+	if err := s.deriveAlpha(); err != nil {
+		return err
+	}
+	s.h = iop.NewPolynomial(&s.ck.h, iop.Form{Basis: iop.Canonical, Layout: iop.Regular})
+	copy(s.proof.H[:], s.ck.hDigest)
+	if err := s.deriveZeta(); err != nil {
+		return err
+	}
+
 	close(s.chH)
 
 	return nil
@@ -563,6 +783,13 @@ func (s *instance) buildRatioCopyConstraint() (err error) {
 	case <-s.chGammaBeta:
 	}
 
+	if s.ck != nil && s.ck.phase >= checkpointZ {
+		s.x[id_Z] = iop.NewPolynomial(&s.ck.z, s.ck.form())
+		s.proof.Z = s.ck.zDigest
+		close(s.chZ)
+		return nil
+	}
+
 	// TODO @gbotrel having iop.BuildRatioCopyConstraint return something
 	// with capacity = len() + 4 would avoid extra alloc / copy during openZ
 	s.x[id_Z], err = iop.BuildRatioCopyConstraint(
@@ -582,11 +809,16 @@ func (s *instance) buildRatioCopyConstraint() (err error) {
 	}
 
 	// commit to the blinded version of z
-	s.proof.Z, err = s.commitToPolyAndBlinding(s.x[id_Z], s.bp[id_Bz])
+	if s.proof.Z, err = s.commitToPolyAndBlinding(s.x[id_Z], s.bp[id_Bz]); err != nil {
+		return err
+	}
+	if err = s.saveCheckpoint(checkpointZ); err != nil {
+		return err
+	}
 
 	close(s.chZ)
 
-	return
+	return nil
 }
 
 // open Z (blinded) at ωζ
@@ -686,7 +918,7 @@ func (s *instance) computeLinearizedPolynomial() error {
 	)
 
 	var err error
-	s.linearizedPolynomialDigest, err = kzg.Commit(s.linearizedPolynomial, s.pk.Kzg, runtime.NumCPU()*2)
+	s.linearizedPolynomialDigest, err = kzgCommit(s.linearizedPolynomial, s.pk.Kzg, s.opt.SideChannelHardening, runtime.NumCPU()*2)
 	if err != nil {
 		return err
 	}
@@ -1121,6 +1353,12 @@ func getRandomPolynomial(n int) *iop.Polynomial {
 	return res
 }
 
+// return the zero polynomial of degree n
+func getZeroPolynomial(n int) *iop.Polynomial {
+	a := make([]fr.Element, n+1)
+	return iop.NewPolynomial(&a, iop.Form{Basis: iop.Canonical, Layout: iop.Regular})
+}
+
 func coefficients(p []*iop.Polynomial) [][]fr.Element {
 	res := make([][]fr.Element, len(p))
 	for i, pI := range p {
@@ -1129,27 +1367,55 @@ func coefficients(p []*iop.Polynomial) [][]fr.Element {
 	return res
 }
 
-func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, kzgPk kzg.ProvingKey) error {
+func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, kzgPk kzg.ProvingKey, blinded bool) error {
 	g := new(errgroup.Group)
 
 	g.Go(func() (err error) {
-		proof.H[0], err = kzg.Commit(h1, kzgPk)
+		proof.H[0], err = kzgCommit(h1, kzgPk, blinded)
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[1], err = kzg.Commit(h2, kzgPk)
+		proof.H[1], err = kzgCommit(h2, kzgPk, blinded)
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[2], err = kzg.Commit(h3, kzgPk)
+		proof.H[2], err = kzgCommit(h3, kzgPk, blinded)
 		return
 	})
 
 	return g.Wait()
 }
 
+// kzgCommit returns the KZG commitment of p, computed with blinded coefficients
+// if requested: the commitments of p-ρ and of a random ρ are added, so that the
+// buckets of each of the multi-scalar multiplications are independent of p.
+// Observing the buckets of both reveals p, see
+// [backend.WithSideChannelHardening].
+func kzgCommit(p []fr.Element, pk kzg.ProvingKey, blinded bool, nbTasks ...int) (kzg.Digest, error) {
+	if !blinded {
+		return kzg.Commit(p, pk, nbTasks...)
+	}
+	rho := make([]fr.Element, len(p))
+	masked := make([]fr.Element, len(p))
+	for i := range p {
+		if _, err := rho[i].SetRandom(); err != nil {
+			return kzg.Digest{}, err
+		}
+		masked[i].Sub(&p[i], &rho[i])
+	}
+	c1, err := kzg.Commit(masked, pk, nbTasks...)
+	if err != nil {
+		return kzg.Digest{}, err
+	}
+	c2, err := kzg.Commit(rho, pk, nbTasks...)
+	if err != nil {
+		return kzg.Digest{}, err
+	}
+	return *c1.Add(&c1, &c2), nil
+}
+
 // divideByXMinusOne
 // The input must be in LagrangeCoset.
 // The result is in Canonical Regular. (in place using a)
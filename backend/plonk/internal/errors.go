@@ -0,0 +1,10 @@
+package internal
+
+import "errors"
+
+// The errors returned by Setup for all the curves, exported by the plonk
+// package.
+var (
+	ErrSRSTooSmall     = errors.New("kzg srs is too small")
+	ErrInconsistentSRS = errors.New("inconsistent kzg srs")
+)
@@ -20,11 +20,15 @@
 package plonk
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk/internal"
 	"github.com/consensys/gnark/constraint"
 
 	"github.com/consensys/gnark/backend/solidity"
@@ -61,9 +65,20 @@ import (
 	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/kzg"
 	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
 
+	"github.com/consensys/gnark/internal/utils"
 	gnarkio "github.com/consensys/gnark/io"
 )
 
+var (
+	// ErrSRSTooSmall is returned by Setup when the kzg SRS has less points
+	// than the circuit needs.
+	ErrSRSTooSmall = internal.ErrSRSTooSmall
+	// ErrInconsistentSRS is returned by Setup when the points of the kzg SRS
+	// are not the powers of the verifying key, or when its lagrange form
+	// doesn't match the canonical form.
+	ErrInconsistentSRS = internal.ErrInconsistentSRS
+)
+
 // Proof represents a Plonk proof generated by plonk.Prove
 //
 // it's underlying implementation is curve specific (see gnark/internal/backend)
@@ -100,36 +115,104 @@ type VerifyingKey interface {
 // The kzg SRS must be provided in canonical and lagrange form.
 // For test purposes, see test/unsafekzg package. With an existing SRS generated through MPC in canonical form,
 // gnark-crypto offers the ToLagrangeG1 method to convert it to lagrange form.
+//
+// The canonical SRS may be larger than the circuit needs (see [SRSSize]): it
+// is truncated, and the truncated points are checked against the verifying key
+// of the SRS. The lagrange form is checked against the canonical form, and is
+// computed from it if srsLagrange is nil or of another size. Setup returns an
+// error wrapping [ErrSRSTooSmall] or [ErrInconsistentSRS] if these checks fail.
 func Setup(ccs constraint.ConstraintSystem, srs, srsLagrange kzg.SRS) (ProvingKey, VerifyingKey, error) {
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs, *srs.(*kzg_bn254.SRS), *srsLagrange.(*kzg_bn254.SRS))
+		return plonk_bn254.Setup(tccs, *srs.(*kzg_bn254.SRS), lagrangeSRS[kzg_bn254.SRS](srsLagrange))
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Setup(tccs, *srs.(*kzg_bls12381.SRS), *srsLagrange.(*kzg_bls12381.SRS))
+		return plonk_bls12381.Setup(tccs, *srs.(*kzg_bls12381.SRS), lagrangeSRS[kzg_bls12381.SRS](srsLagrange))
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Setup(tccs, *srs.(*kzg_bls12377.SRS), *srsLagrange.(*kzg_bls12377.SRS))
+		return plonk_bls12377.Setup(tccs, *srs.(*kzg_bls12377.SRS), lagrangeSRS[kzg_bls12377.SRS](srsLagrange))
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Setup(tccs, *srs.(*kzg_bw6761.SRS), *srsLagrange.(*kzg_bw6761.SRS))
+		return plonk_bw6761.Setup(tccs, *srs.(*kzg_bw6761.SRS), lagrangeSRS[kzg_bw6761.SRS](srsLagrange))
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Setup(tccs, *srs.(*kzg_bls24317.SRS), *srsLagrange.(*kzg_bls24317.SRS))
+		return plonk_bls24317.Setup(tccs, *srs.(*kzg_bls24317.SRS), lagrangeSRS[kzg_bls24317.SRS](srsLagrange))
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Setup(tccs, *srs.(*kzg_bls24315.SRS), *srsLagrange.(*kzg_bls24315.SRS))
+		return plonk_bls24315.Setup(tccs, *srs.(*kzg_bls24315.SRS), lagrangeSRS[kzg_bls24315.SRS](srsLagrange))
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Setup(tccs, *srs.(*kzg_bw6633.SRS), *srsLagrange.(*kzg_bw6633.SRS))
+		return plonk_bw6633.Setup(tccs, *srs.(*kzg_bw6633.SRS), lagrangeSRS[kzg_bw6633.SRS](srsLagrange))
 	default:
 		panic("unrecognized SparseR1CS curve type")
 	}
 
 }
 
+// lagrangeSRS returns the lagrange form of the SRS, or an empty SRS if it
+// isn't provided.
+func lagrangeSRS[T any, PT interface {
+	*T
+	kzg.SRS
+}](srs kzg.SRS) (res T) {
+	if srs != nil {
+		res = *srs.(PT)
+	}
+	return
+}
+
 // Prove generates PLONK proof from a circuit, associated preprocessed public data, and the witness
 // if the force flag is set:
 //
 //		will execute all the prover computations, even if the witness is invalid
 //	 will produce an invalid proof
 //		internally, the solution vector to the SparseR1CS will be filled with random values which may impact benchmarking
+//
+// if a proof cache is set with backend.WithProofCache, the proof is read from
+// the cache when available, and stored in the cache otherwise.
 func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ProofCache == nil {
+		return prove(ccs, pk, fullWitness, opts...)
+	}
+
+	vk, ok := pk.VerifyingKey().(gnarkio.WriterRawTo)
+	if !ok {
+		return nil, errors.New("proof cache: unsupported verifying key")
+	}
+	key, err := backend.ProofCacheKey(backend.PLONK, &cfg, vk, fullWitness)
+	if err != nil {
+		return nil, fmt.Errorf("proof cache key: %w", err)
+	}
+	if data, ok := cfg.ProofCache.Get(key); ok {
+		proof := NewProof(utils.FieldToCurve(ccs.Field()))
+		if _, err := proof.ReadFrom(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("read cached proof: %w", err)
+		}
+		return proof, nil
+	}
+	proof, err := prove(ccs, pk, fullWitness, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	cfg.ProofCache.Put(key, buf.Bytes())
+	return proof, nil
+}
+
+// ProveBatch computes the proofs of the witnesses for the same constraint
+// system and proving key, with [Prove]. The number of proofs computed
+// concurrently is set with backend.WithBatchParallelism. If some witnesses are
+// not proved, it returns the other proofs with a [*backend.BatchError] giving
+// the error of each witness.
+func ProveBatch(ccs constraint.ConstraintSystem, pk ProvingKey, witnesses []witness.Witness, opts ...backend.ProverOption) ([]Proof, error) {
+	return backend.ProveBatch(witnesses, func(fullWitness witness.Witness) (Proof, error) {
+		return Prove(ccs, pk, fullWitness, opts...)
+	}, opts...)
+}
+
+func prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
@@ -158,6 +241,42 @@ func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.W
 	}
 }
 
+// ResumeProve completes the proof interrupted while proving with the option
+// backend.WithCheckpointDir, from the checkpoint saved in the directory. The
+// constraint system and the proving key must be the ones of the interrupted
+// proof. It returns backend.ErrNoCheckpoint if the prover didn't save a
+// checkpoint yet, in which case the proof must be computed again with Prove,
+// or if the checkpoint was removed once the proof was computed (see
+// backend.WithKeepCheckpoint).
+func ResumeProve(ccs constraint.ConstraintSystem, pk ProvingKey, opts ...backend.ProverOption) (Proof, error) {
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		return plonk_bn254.ResumeProve(tccs, pk.(*plonk_bn254.ProvingKey), opts...)
+
+	case *cs_bls12381.SparseR1CS:
+		return plonk_bls12381.ResumeProve(tccs, pk.(*plonk_bls12381.ProvingKey), opts...)
+
+	case *cs_bls12377.SparseR1CS:
+		return plonk_bls12377.ResumeProve(tccs, pk.(*plonk_bls12377.ProvingKey), opts...)
+
+	case *cs_bw6761.SparseR1CS:
+		return plonk_bw6761.ResumeProve(tccs, pk.(*plonk_bw6761.ProvingKey), opts...)
+
+	case *cs_bw6633.SparseR1CS:
+		return plonk_bw6633.ResumeProve(tccs, pk.(*plonk_bw6633.ProvingKey), opts...)
+
+	case *cs_bls24317.SparseR1CS:
+		return plonk_bls24317.ResumeProve(tccs, pk.(*plonk_bls24317.ProvingKey), opts...)
+
+	case *cs_bls24315.SparseR1CS:
+		return plonk_bls24315.ResumeProve(tccs, pk.(*plonk_bls24315.ProvingKey), opts...)
+
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
+
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
 func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
 
@@ -217,6 +336,61 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies several PLONK proofs at once, sharing the pairing check
+// of their KZG opening proofs. The proofs must be on the same curve and their
+// verifying keys must share the same KZG setup, but may be of different
+// circuits. It is faster than verifying the proofs one by one, but if the batch
+// is rejected the error doesn't tell which proof is invalid.
+func BatchVerify(proofs []Proof, vks []VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	if len(proofs) != len(vks) || len(proofs) != len(publicWitnesses) {
+		return errors.New("the numbers of proofs, verifying keys and public witnesses differ")
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	switch proofs[0].(type) {
+	case *plonk_bn254.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bn254.BatchVerify, opts...)
+	case *plonk_bls12381.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bls12381.BatchVerify, opts...)
+	case *plonk_bls12377.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bls12377.BatchVerify, opts...)
+	case *plonk_bw6761.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bw6761.BatchVerify, opts...)
+	case *plonk_bw6633.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bw6633.BatchVerify, opts...)
+	case *plonk_bls24317.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bls24317.BatchVerify, opts...)
+	case *plonk_bls24315.Proof:
+		return batchVerify(proofs, vks, publicWitnesses, plonk_bls24315.BatchVerify, opts...)
+
+	default:
+		panic("unrecognized proof type")
+	}
+}
+
+// batchVerify converts the proofs, verifying keys and public witnesses to the
+// types of the curve of the first proof and calls verify.
+func batchVerify[P, VK, W any](proofs []Proof, vks []VerifyingKey, publicWitnesses []witness.Witness, verify func([]*P, []*VK, []W, ...backend.VerifierOption) error, opts ...backend.VerifierOption) error {
+	tProofs := make([]*P, len(proofs))
+	tVks := make([]*VK, len(vks))
+	tWitnesses := make([]W, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if tProofs[i], ok = any(proofs[i]).(*P); !ok {
+			return fmt.Errorf("proof %d: the proofs are not on the same curve", i)
+		}
+		if tVks[i], ok = any(vks[i]).(*VK); !ok {
+			return fmt.Errorf("verifying key %d: invalid type %T", i, vks[i])
+		}
+		if tWitnesses[i], ok = publicWitnesses[i].Vector().(W); !ok {
+			return witness.ErrInvalidWitness
+		}
+	}
+	return verify(tProofs, tVks, tWitnesses, opts...)
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
@@ -322,7 +496,8 @@ func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
 
 // SRSSize returns the required size of the kzg SRS for a given constraint system
 // Note that the SRS size in Lagrange form is a power of 2,
-// and the SRS size in canonical form need few extra elements (3) to account for the blinding factors
+// and the SRS size in canonical form need few extra elements (3) to account for the blinding factors.
+// The blinding degrees are fixed, so the size is the same with backend.WithUnsafeNoBlinding.
 func SRSSize(ccs constraint.ConstraintSystem) (sizeCanonical, sizeLagrange int) {
 	nbConstraints := ccs.GetNbConstraints()
 	sizeSystem := nbConstraints + ccs.GetNbPublicVariables()
@@ -2,18 +2,25 @@ package plonk_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/memory"
 	"github.com/consensys/gnark/test"
 	"github.com/consensys/gnark/test/unsafekzg"
 	"github.com/stretchr/testify/require"
@@ -151,6 +158,311 @@ func TestCustomChallengeHash(t *testing.T) {
 	}
 }
 
+func TestUnsafeNoBlinding(t *testing.T) {
+	assert := test.NewAssert(t)
+	assignment := &blindingCircuit{X: 3, Y: 9}
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+
+			// without blinding the proofs are deterministic
+			var proofs [2]bytes.Buffer
+			for i := range proofs {
+				proof, err := plonk.Prove(ccs, pk, witness, backend.WithUnsafeNoBlinding())
+				assert.NoError(err)
+				assert.NoError(plonk.Verify(proof, vk, pubWitness))
+				_, err = proof.WriteTo(&proofs[i])
+				assert.NoError(err)
+			}
+			assert.Equal(proofs[0].Bytes(), proofs[1].Bytes())
+
+			proof, err := plonk.Prove(ccs, pk, witness)
+			assert.NoError(err)
+			var blinded bytes.Buffer
+			_, err = proof.WriteTo(&blinded)
+			assert.NoError(err)
+			assert.NotEqual(proofs[0].Bytes(), blinded.Bytes())
+		}, curve.String())
+	}
+
+	// the prover needs the SRS size of SRSSize, with or without blinding
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, _, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	sizeCanonical, _ := plonk.SRSSize(ccs)
+	tpk := pk.(*plonk_bn254.ProvingKey)
+	assert.Equal(sizeCanonical, len(tpk.Kzg.G1))
+	tpk.Kzg.G1 = tpk.Kzg.G1[:sizeCanonical-1]
+	_, err = plonk.Prove(ccs, pk, witness)
+	assert.Error(err)
+	_, err = plonk.Prove(ccs, pk, witness, backend.WithUnsafeNoBlinding())
+	assert.Error(err)
+}
+
+func TestResumeProve(t *testing.T) {
+	assert := test.NewAssert(t)
+	assignment := &blindingCircuit{X: 3, Y: 9}
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+
+			dir := t.TempDir()
+			_, err = plonk.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.ErrorIs(err, backend.ErrNoCheckpoint)
+
+			// without blinding, the resumed proof is the same as the original
+			var proofs [2]bytes.Buffer
+			proof, err := plonk.Prove(ccs, pk, witness, backend.WithUnsafeNoBlinding(), backend.WithCheckpointDir(dir), backend.WithKeepCheckpoint())
+			assert.NoError(err)
+			_, err = proof.WriteTo(&proofs[0])
+			assert.NoError(err)
+			proof, err = plonk.ResumeProve(ccs, pk, backend.WithUnsafeNoBlinding(), backend.WithCheckpointDir(dir), backend.WithKeepCheckpoint())
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+			_, err = proof.WriteTo(&proofs[1])
+			assert.NoError(err)
+			assert.Equal(proofs[0].Bytes(), proofs[1].Bytes())
+
+			// the checkpoint is removed once the proof is computed
+			proof, err = plonk.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+			_, err = plonk.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.ErrorIs(err, backend.ErrNoCheckpoint)
+			_, err = plonk.Prove(ccs, pk, witness, backend.WithCheckpointDir(dir))
+			assert.NoError(err)
+			_, err = plonk.ResumeProve(ccs, pk, backend.WithCheckpointDir(dir))
+			assert.ErrorIs(err, backend.ErrNoCheckpoint)
+		}, curve.String())
+	}
+}
+
+func TestProofCache(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &smallCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+
+	cache := &countingCache{ProofCache: backend.NewMemoryProofCache()}
+	serialize := func(proof plonk.Proof) []byte {
+		var buf bytes.Buffer
+		_, err := proof.WriteTo(&buf)
+		assert.NoError(err)
+		return buf.Bytes()
+	}
+
+	var proofs [][]byte
+	for _, x := range []int{1, 1, 0} {
+		witness, err := frontend.NewWitness(&smallCircuit{X: x}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		proof, err := plonk.Prove(ccs, pk, witness, backend.WithProofCache(cache))
+		assert.NoError(err)
+		pubWitness, err := witness.Public()
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(proof, vk, pubWitness))
+		proofs = append(proofs, serialize(proof))
+	}
+	assert.Equal(proofs[0], proofs[1])
+	assert.NotEqual(proofs[0], proofs[2])
+	assert.Equal(1, cache.hits)
+	assert.Equal(2, cache.puts)
+
+	// the options changing the proof are part of the key
+	witness, err := frontend.NewWitness(&smallCircuit{X: 1}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+	for _, opt := range []backend.ProverOption{backend.WithUnsafeNoBlinding(), backend.WithSideChannelHardening()} {
+		for i := 0; i < 2; i++ {
+			proof, err := plonk.Prove(ccs, pk, witness, backend.WithProofCache(cache), opt)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+		}
+	}
+	assert.Equal(3, cache.hits)
+	assert.Equal(4, cache.puts)
+
+	// custom hash functions can't be part of the key
+	_, err = plonk.Prove(ccs, pk, witness, backend.WithProofCache(cache), backend.WithProverChallengeHashFunction(sha256.New224()))
+	assert.Error(err)
+	_, err = plonk.Prove(ccs, pk, witness, backend.WithProofCache(cache), backend.WithProverHashToFieldFunction(sha256.New()))
+	assert.Error(err)
+}
+
+func TestVerificationError(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&blindingCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, witness)
+	assert.NoError(err)
+
+	check := func(err error) backend.VerificationCheck {
+		var vErr *backend.VerificationError
+		assert.ErrorAs(err, &vErr)
+		return vErr.Check
+	}
+	// full witness instead of the public one
+	assert.Equal(backend.CheckPublicWitness, check(plonk.Verify(proof, vk, witness)))
+	// wrong public input
+	wrongPublic, err := frontend.NewWitness(&blindingCircuit{Y: 10}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Equal(backend.CheckQuotientIdentity, check(plonk.Verify(proof, vk, wrongPublic)))
+}
+
+func TestSideChannelHardening(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &hardeningCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(&hardeningCircuit{X: 5, Idx: 3, Y: 9}, curve.ScalarField())
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, witness, backend.WithSideChannelHardening())
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+
+			invalid, err := frontend.NewWitness(&hardeningCircuit{X: 5, Idx: 3, Y: 10}, curve.ScalarField())
+			assert.NoError(err)
+			_, err = plonk.Prove(ccs, pk, invalid, backend.WithSideChannelHardening())
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+func TestSetupSRSSize(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	refCcs, _, refSrs, refSrsLagrange := referenceCircuit(ecc.BN254)
+
+	fullWitness, err := frontend.NewWitness(&blindingCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	// an oversized SRS is truncated, and its lagrange form computed again
+	for _, lagrange := range []kzg.SRS{refSrsLagrange, nil} {
+		pk, vk, err := plonk.Setup(ccs, refSrs, lagrange)
+		assert.NoError(err)
+		sizeCanonical, _ := plonk.SRSSize(ccs)
+		assert.EqualValues(sizeCanonical, vk.(*plonk_bn254.VerifyingKey).SRSSize)
+		proof, err := plonk.Prove(ccs, pk, fullWitness)
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(proof, vk, publicWitness))
+	}
+
+	// the SRS of the small circuit is too small for the reference circuit
+	_, _, err = plonk.Setup(refCcs, srs, srsLagrange)
+	assert.ErrorIs(err, plonk.ErrSRSTooSmall)
+
+	// the points of the SRS are not the powers of the same τ. The SRS are
+	// cached by unsafekzg, so they are copied before being modified.
+	tampered := *refSrs.(*kzg_bn254.SRS)
+	tampered.Pk.G1 = append(tampered.Pk.G1[:0:0], tampered.Pk.G1...)
+	tampered.Pk.G1[2], tampered.Pk.G1[3] = tampered.Pk.G1[3], tampered.Pk.G1[2]
+	_, _, err = plonk.Setup(ccs, &tampered, nil)
+	assert.ErrorIs(err, plonk.ErrInconsistentSRS)
+
+	tamperedLagrange := *srsLagrange.(*kzg_bn254.SRS)
+	tamperedLagrange.Pk.G1 = append(tamperedLagrange.Pk.G1[:0:0], tamperedLagrange.Pk.G1...)
+	tamperedLagrange.Pk.G1[0], tamperedLagrange.Pk.G1[1] = tamperedLagrange.Pk.G1[1], tamperedLagrange.Pk.G1[0]
+	_, _, err = plonk.Setup(ccs, srs, &tamperedLagrange)
+	assert.ErrorIs(err, plonk.ErrInconsistentSRS)
+}
+
+func TestSetupParallelSRS(t *testing.T) {
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+			assert.NoError(err)
+			_, sizeLagrange := plonk.SRSSize(ccs)
+
+			// Setup checks the consistency of the SRS
+			srs, srsLagrange, err := unsafekzg.NewSRSParallel(curve, uint64(sizeLagrange), []byte("seed"))
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+
+			fullWitness, err := frontend.NewWitness(&blindingCircuit{X: 3, Y: 9}, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, publicWitness))
+
+			// the SRS is derived from the seed
+			srs2, _, err := unsafekzg.NewSRSParallel(curve, uint64(sizeLagrange), []byte("seed"))
+			assert.NoError(err)
+			assert.Equal(srs, srs2)
+		})
+	}
+}
+
+type countingCache struct {
+	backend.ProofCache
+	hits, puts int
+}
+
+func (c *countingCache) Get(key []byte) ([]byte, bool) {
+	proof, ok := c.ProofCache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return proof, ok
+}
+
+func (c *countingCache) Put(key, proof []byte) {
+	c.puts++
+	c.ProofCache.Put(key, proof)
+}
+
 func TestCustomKZGFoldingHash(t *testing.T) {
 	assert := test.NewAssert(t)
 	assignment := &smallCircuit{X: 1}
@@ -194,6 +506,57 @@ func TestCustomKZGFoldingHash(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+
+			var (
+				proofs    []plonk.Proof
+				vks       []plonk.VerifyingKey
+				witnesses []witness.Witness
+			)
+			for _, x := range []int{2, 3, 4} {
+				w, err := frontend.NewWitness(&blindingCircuit{X: x, Y: x * x}, curve.ScalarField())
+				assert.NoError(err)
+				proof, err := plonk.Prove(ccs, pk, w)
+				assert.NoError(err)
+				pubWitness, err := w.Public()
+				assert.NoError(err)
+				proofs = append(proofs, proof)
+				vks = append(vks, vk)
+				witnesses = append(witnesses, pubWitness)
+			}
+			assert.NoError(plonk.BatchVerify(proofs, vks, witnesses))
+			assert.NoError(plonk.BatchVerify(nil, nil, nil))
+
+			// a proof with the wrong public input
+			wrongWitnesses := append([]witness.Witness{}, witnesses...)
+			wrongWitnesses[1] = witnesses[2]
+			assert.Error(plonk.BatchVerify(proofs, vks, wrongWitnesses))
+
+			// the proofs of the batch must have the same KZG setup. The SRS
+			// of a larger circuit is sampled independently.
+			otherCcs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: 64})
+			assert.NoError(err)
+			otherSrs, otherSrsLagrange, err := unsafekzg.NewSRS(otherCcs)
+			assert.NoError(err)
+			_, otherVk, err := plonk.Setup(otherCcs, otherSrs, otherSrsLagrange)
+			assert.NoError(err)
+			otherVks := append([]plonk.VerifyingKey{}, vks...)
+			otherVks[0] = otherVk
+			assert.Error(plonk.BatchVerify(proofs, otherVks, witnesses))
+		}, curve.String())
+	}
+}
+
 func BenchmarkSetup(b *testing.B) {
 	for _, curve := range getCurves() {
 		b.Run(curve.String(), func(b *testing.B) {
@@ -310,6 +673,21 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type blindingCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *blindingCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
 type smallCircuit struct {
 	X frontend.Variable
 }
@@ -320,6 +698,25 @@ func (c *smallCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+// hardeningCircuit solves divisions, lookups and memory accesses at secret
+// indices.
+type hardeningCircuit struct {
+	X, Idx frontend.Variable
+	Y      frontend.Variable `gnark:",public"`
+}
+
+func (c *hardeningCircuit) Define(api frontend.API) error {
+	squares := logderivlookup.New(api)
+	for i := 0; i < 8; i++ {
+		squares.Insert(i * i)
+	}
+	m := memory.New(api, []frontend.Variable{1, 2, 3, 4})
+	m.Write(c.Idx, c.X)
+	v := m.Read(c.Idx)
+	api.AssertIsEqual(api.Div(api.Mul(v, squares.Lookup(c.Idx)[0]), c.X), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -334,3 +731,27 @@ func getCurves() []ecc.ID {
 	}
 	return gnark.Curves()
 }
+
+func TestContext(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &blindingCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&blindingCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := plonk.Prove(ccs, pk, witness, backend.WithContext(ctx))
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, pubWitness))
+
+	cancel()
+	_, err = plonk.Prove(ccs, pk, witness, backend.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+}
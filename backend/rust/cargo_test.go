@@ -0,0 +1,122 @@
+package rust_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/rust"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCargo runs cargo test on the exported crates, checking that they accept
+// a gnark proof and reject it once tampered with or with other public inputs.
+// It needs cargo and is run with the rustcheck build tag.
+func TestCargo(t *testing.T) {
+	if !test.RustCheck {
+		t.Skip("rust checks are disabled, see the rustcheck build tag")
+	}
+	assignment := &mulCircuit{X: 3, Y: 6, Z: 3}
+	for name, circuit := range map[string]frontend.Circuit{"mul": &mulCircuit{}, "commit": &commitCircuit{}} {
+		var a frontend.Circuit = assignment
+		if name == "commit" {
+			a = &commitCircuit{*assignment}
+		}
+		w, err := frontend.NewWitness(a, ecc.BN254.ScalarField())
+		require.NoError(t, err)
+		pw, err := w.Public()
+		require.NoError(t, err)
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+		require.NoError(t, err)
+		pk, vk, err := groth16.Setup(ccs)
+		require.NoError(t, err)
+		proof, err := groth16.Prove(ccs, pk, w, backend.WithProverHashToFieldFunction(sha256.New()))
+		require.NoError(t, err)
+		require.NoError(t, groth16.Verify(proof, vk, pw, backend.WithVerifierHashToFieldFunction(sha256.New())))
+		checkCargo(t, "groth16/"+name, vk.(rust.Exporter), proof.(solidityProof).MarshalSolidity(), pw)
+
+		ccs, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, circuit)
+		require.NoError(t, err)
+		srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+		require.NoError(t, err)
+		ppk, pvk, err := plonk.Setup(ccs, srs, srsLagrange)
+		require.NoError(t, err)
+		pproof, err := plonk.Prove(ccs, ppk, w)
+		require.NoError(t, err)
+		require.NoError(t, plonk.Verify(pproof, pvk, pw))
+		checkCargo(t, "plonk/"+name, pvk.(rust.Exporter), pproof.(solidityProof).MarshalSolidity(), pw)
+	}
+}
+
+type solidityProof interface {
+	MarshalSolidity() []byte
+}
+
+func checkCargo(t *testing.T, name string, vk rust.Exporter, proof []byte, publicWitness witness.Witness) {
+	inputs := publicWitness.Vector().(fr_bn254.Vector)
+	var sb strings.Builder
+	sb.WriteString("use gnark_verifier::verify;\n\n")
+	fmt.Fprintf(&sb, "const PROOF: &[u8] = &%s;\n", rust.Bytes(proof))
+	fmt.Fprintf(&sb, "const PUBLIC_INPUTS: [[u8; 32]; %d] = [\n", len(inputs))
+	for i := range inputs {
+		b := inputs[i].Bytes()
+		fmt.Fprintf(&sb, "    %s,\n", rust.Bytes(b[:]))
+	}
+	sb.WriteString(`];
+
+#[test]
+fn valid_proof() {
+    assert_eq!(verify(PROOF, &PUBLIC_INPUTS), Ok(()));
+}
+
+#[test]
+fn tampered_proof() {
+    let mut proof = PROOF.to_vec();
+    proof[40] ^= 1;
+    assert!(verify(&proof, &PUBLIC_INPUTS).is_err());
+}
+
+#[test]
+fn wrong_public_inputs() {
+    let mut inputs = PUBLIC_INPUTS;
+    inputs[0][31] ^= 1;
+    assert!(verify(PROOF, &inputs).is_err());
+}
+`)
+
+	for _, target := range []rust.Target{rust.Solana, rust.CosmWasm} {
+		t.Run(name+"/"+target.String(), func(t *testing.T) {
+			assert := require.New(t)
+			dir := t.TempDir()
+			assert.NoError(rust.ExportCrate(dir, vk, rust.WithTarget(target), rust.WithCrateName("gnark_verifier")))
+			assert.NoError(os.MkdirAll(filepath.Join(dir, "tests"), 0o755))
+			assert.NoError(os.WriteFile(filepath.Join(dir, "tests", "verify.rs"), []byte(sb.String()), 0o644))
+
+			feature := "library"
+			if target == rust.Solana {
+				feature = "no-entrypoint"
+			}
+			cmd := exec.Command("cargo", "test", "--features", feature)
+			cmd.Dir = dir
+			t.Log("running ", cmd.String())
+			out, err := cmd.CombinedOutput()
+			assert.NoError(err, string(out))
+		})
+	}
+}
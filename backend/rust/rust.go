@@ -0,0 +1,541 @@
+// Package rust provides the options and the common code of the exporters of
+// BN254 verifiers as Rust crates, for the chains without an EVM but with BN254
+// support: Solana programs, which use the alt_bn128 syscalls, and CosmWasm
+// contracts.
+//
+// The verifying keys implement [Exporter], and [ExportCrate] writes a complete
+// crate (manifest and sources) to a directory. As for the Solidity verifiers,
+// the proofs are given in the format of their MarshalSolidity method and the
+// public inputs as 32 bytes big-endian integers.
+//
+// This is an experimental feature, the generated code has not been audited.
+package rust
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Target is the chain for which the verifier crate is generated.
+type Target uint8
+
+const (
+	// Solana generates a Solana program. The curve operations are done with
+	// the alt_bn128 syscalls and the instruction data is the proof followed
+	// by the public inputs.
+	Solana Target = iota
+	// CosmWasm generates a CosmWasm contract with a Verify query. The curve
+	// operations are done in Wasm with arkworks.
+	CosmWasm
+)
+
+func (t Target) String() string {
+	switch t {
+	case Solana:
+		return "solana"
+	case CosmWasm:
+		return "cosmwasm"
+	default:
+		return fmt.Sprintf("Target(%d)", uint8(t))
+	}
+}
+
+// Exporter is implemented by the verifying keys which can be exported as a
+// Rust verifier.
+type Exporter interface {
+	// ExportRust writes the sources of the verifier (src/lib.rs) to w.
+	ExportRust(w io.Writer, exportOpts ...ExportOption) error
+}
+
+// ExportOption defines option for altering the behavior of the Rust
+// exporters. See the descriptions of functions returning instances of this
+// type for implemented options.
+type ExportOption func(*ExportConfig) error
+
+// ExportConfig is the configuration for the exporters with the options
+// applied.
+type ExportConfig struct {
+	Target          Target
+	CrateName       string
+	SolanaVersion   string
+	CosmWasmVersion string
+}
+
+var crateNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// NewExportConfig returns a default ExportConfig with given export options opts
+// applied.
+func NewExportConfig(opts ...ExportOption) (ExportConfig, error) {
+	config := ExportConfig{
+		Target:          Solana,
+		CrateName:       "gnark-verifier",
+		SolanaVersion:   "2.2",
+		CosmWasmVersion: "2.1",
+	}
+	for _, option := range opts {
+		if err := option(&config); err != nil {
+			return ExportConfig{}, err
+		}
+	}
+	return config, nil
+}
+
+// WithTarget sets the chain for which the verifier is generated. The default
+// is [Solana].
+func WithTarget(target Target) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if target != Solana && target != CosmWasm {
+			return fmt.Errorf("unknown target %s", target)
+		}
+		cfg.Target = target
+		return nil
+	}
+}
+
+// WithCrateName sets the name of the generated crate. The default is
+// "gnark-verifier".
+func WithCrateName(name string) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if !crateNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid crate name %q", name)
+		}
+		cfg.CrateName = name
+		return nil
+	}
+}
+
+// WithSolanaVersion sets the version requirement of the solana-program and
+// solana-bn254 dependencies.
+func WithSolanaVersion(version string) ExportOption {
+	return func(cfg *ExportConfig) error {
+		cfg.SolanaVersion = version
+		return nil
+	}
+}
+
+// WithCosmWasmVersion sets the version requirement of the cosmwasm-std and
+// cosmwasm-schema dependencies.
+func WithCosmWasmVersion(version string) ExportOption {
+	return func(cfg *ExportConfig) error {
+		cfg.CosmWasmVersion = version
+		return nil
+	}
+}
+
+// ExportCrate writes the crate of the verifier of vk in dir: the manifest
+// Cargo.toml and the sources src/lib.rs. The directory is created if needed
+// and the existing files are overwritten.
+func ExportCrate(dir string, vk Exporter, exportOpts ...ExportOption) error {
+	cfg, err := NewExportConfig(exportOpts...)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0o755); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "Cargo.toml"), func(w io.Writer) error {
+		return WriteManifest(w, cfg)
+	}); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, "src", "lib.rs"), func(w io.Writer) error {
+		return vk.ExportRust(w, exportOpts...)
+	})
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteManifest writes the Cargo.toml of the verifier crate.
+func WriteManifest(w io.Writer, cfg ExportConfig) error {
+	t, err := template.New("manifest").Parse(tmplManifest)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, struct {
+		Cfg    ExportConfig
+		Solana bool
+	}{
+		Cfg:    cfg,
+		Solana: cfg.Target == Solana,
+	})
+}
+
+// NewTemplate parses the template of a verifier. The verifier template defines
+// the function verify and the constant NB_PUBLIC_INPUTS, and includes the
+// common code with {{ template "runtime" .Cfg }}, where .Cfg is the
+// [ExportConfig].
+func NewTemplate(verifier string, funcs template.FuncMap) (*template.Template, error) {
+	t, err := template.New("verifier").Funcs(funcs).Parse(tmplRuntime)
+	if err != nil {
+		return nil, err
+	}
+	return t.Parse(verifier)
+}
+
+// Bytes returns the Rust literal of the byte array b.
+func Bytes(b []byte) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := range b {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "0x%02x", b[i])
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+const tmplManifest = `# Code generated by gnark DO NOT EDIT
+
+[package]
+name = "{{ .Cfg.CrateName }}"
+version = "0.1.0"
+edition = "2021"
+
+[lib]
+crate-type = ["cdylib", "lib"]
+
+[features]
+{{- if .Solana }}
+no-entrypoint = []
+{{- else }}
+library = []
+{{- end }}
+
+[dependencies]
+ark-bn254 = "0.4"
+ark-ff = "0.4"
+sha2 = "0.10"
+{{- if .Solana }}
+solana-program = "{{ .Cfg.SolanaVersion }}"
+solana-bn254 = "{{ .Cfg.SolanaVersion }}"
+{{- else }}
+ark-ec = "0.4"
+cosmwasm-schema = "{{ .Cfg.CosmWasmVersion }}"
+cosmwasm-std = "{{ .Cfg.CosmWasmVersion }}"
+
+[profile.release]
+opt-level = "z"
+lto = true
+codegen-units = 1
+panic = "abort"
+{{- end }}
+`
+
+// tmplRuntime is the code shared by the verifiers: the errors, the scalar
+// field helpers, the curve operations with the EIP-196/EIP-197 encoding and
+// the entrypoint of the target.
+const tmplRuntime = `
+{{- define "runtime" }}
+use ark_bn254::Fr;
+use ark_ff::{BigInt, BigInteger, Field, One, PrimeField};
+use sha2::{Digest, Sha256};
+
+/// Errors returned by the verifier.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+#[repr(u32)]
+pub enum VerifierError {
+    /// The proof doesn't have the expected size.
+    InvalidProofSize = 1,
+    /// The number of public inputs is not the expected one.
+    InvalidPublicInputsNumber = 2,
+    /// A public input or a value of the proof is not reduced modulo r.
+    ScalarNotInField = 3,
+    /// A point is invalid or a curve operation failed.
+    CurveOperation = 4,
+    /// The commitment or its proof of knowledge is invalid.
+    InvalidCommitment = 5,
+    /// The proof is invalid.
+    ProofInvalid = 6,
+}
+
+/// Modulus of the base field of BN254, big-endian.
+const P_MOD: [u8; 32] = [
+    0x30, 0x64, 0x4e, 0x72, 0xe1, 0x31, 0xa0, 0x29, 0xb8, 0x50, 0x45, 0xb6, 0x81, 0x81, 0x58, 0x5d,
+    0x97, 0x81, 0x6a, 0x91, 0x68, 0x71, 0xca, 0x8d, 0x3c, 0x20, 0x8c, 0x16, 0xd8, 0x7c, 0xfd, 0x47,
+];
+
+/// Returns the scalar with the big-endian encoding b, which must be reduced.
+fn fr_from_be(b: &[u8]) -> Result<Fr, VerifierError> {
+    let mut limbs = [0u64; 4];
+    for (i, limb) in limbs.iter_mut().enumerate() {
+        let mut buf = [0u8; 8];
+        buf.copy_from_slice(&b[24 - 8 * i..32 - 8 * i]);
+        *limb = u64::from_be_bytes(buf);
+    }
+    Fr::from_bigint(BigInt::new(limbs)).ok_or(VerifierError::ScalarNotInField)
+}
+
+/// Returns the big-endian encoding of x.
+fn fr_to_be(x: &Fr) -> [u8; 32] {
+    let mut res = [0u8; 32];
+    res.copy_from_slice(&x.into_bigint().to_bytes_be());
+    res
+}
+
+/// Returns the SHA-256 digest of the concatenation of the parts.
+fn sha256(parts: &[&[u8]]) -> [u8; 32] {
+    let mut h = Sha256::new();
+    for part in parts {
+        h.update(part);
+    }
+    h.finalize().into()
+}
+
+/// Returns the point of 64 bytes at offset in b.
+fn g1_at(b: &[u8], offset: usize) -> [u8; 64] {
+    let mut res = [0u8; 64];
+    res.copy_from_slice(&b[offset..offset + 64]);
+    res
+}
+
+/// Returns the scalar at offset in b, which must be reduced.
+fn fr_at(b: &[u8], offset: usize) -> Result<Fr, VerifierError> {
+    fr_from_be(&b[offset..offset + 32])
+}
+
+/// Returns -p for a point p with reduced coordinates.
+fn g1_neg(p: &[u8; 64]) -> [u8; 64] {
+    let mut res = *p;
+    if p[32..].iter().all(|b| *b == 0) {
+        return res;
+    }
+    let mut borrow = 0u16;
+    for i in (0..32).rev() {
+        let d = (P_MOD[i] as u16).wrapping_sub(p[32 + i] as u16 + borrow);
+        res[32 + i] = d as u8;
+        borrow = (d >> 8) & 1;
+    }
+    res
+}
+
+/// Returns acc + [s]p.
+fn g1_acc_mul(acc: &[u8; 64], p: &[u8; 64], s: &Fr) -> Result<[u8; 64], VerifierError> {
+    bn254::add(acc, &bn254::mul(p, &fr_to_be(s))?)
+}
+
+/// Inverts all the elements of v, which must be non-zero.
+fn batch_invert(v: &mut [Fr]) -> Result<(), VerifierError> {
+    let mut prods = Vec::with_capacity(v.len());
+    let mut acc = Fr::one();
+    for x in v.iter() {
+        prods.push(acc);
+        acc *= x;
+    }
+    let mut inv = acc.inverse().ok_or(VerifierError::ProofInvalid)?;
+    for i in (0..v.len()).rev() {
+        let x = v[i];
+        v[i] = inv * prods[i];
+        inv *= x;
+    }
+    Ok(())
+}
+{{- if eq .Target.String "solana" }}
+
+/// Operations on the G1 points of BN254, encoded as in EIP-196 and EIP-197,
+/// with the alt_bn128 syscalls.
+mod bn254 {
+    use super::VerifierError;
+    use solana_bn254::prelude::{alt_bn128_addition, alt_bn128_multiplication, alt_bn128_pairing};
+
+    /// Returns p + q.
+    pub fn add(p: &[u8; 64], q: &[u8; 64]) -> Result<[u8; 64], VerifierError> {
+        let mut input = [0u8; 128];
+        input[..64].copy_from_slice(p);
+        input[64..].copy_from_slice(q);
+        to_point(alt_bn128_addition(&input).map_err(|_| VerifierError::CurveOperation)?)
+    }
+
+    /// Returns [s]p, s is big-endian.
+    pub fn mul(p: &[u8; 64], s: &[u8; 32]) -> Result<[u8; 64], VerifierError> {
+        let mut input = [0u8; 96];
+        input[..64].copy_from_slice(p);
+        input[64..].copy_from_slice(s);
+        to_point(alt_bn128_multiplication(&input).map_err(|_| VerifierError::CurveOperation)?)
+    }
+
+    /// Returns true if the product of the pairings of the pairs (G1, G2) of
+    /// the input is one.
+    pub fn pairing_check(input: &[u8]) -> Result<bool, VerifierError> {
+        let res = alt_bn128_pairing(input).map_err(|_| VerifierError::CurveOperation)?;
+        Ok(res.len() == 32 && res[31] == 1 && res[..31].iter().all(|b| *b == 0))
+    }
+
+    fn to_point(res: Vec<u8>) -> Result<[u8; 64], VerifierError> {
+        res.as_slice().try_into().map_err(|_| VerifierError::CurveOperation)
+    }
+}
+
+#[cfg(not(feature = "no-entrypoint"))]
+solana_program::entrypoint!(process_instruction);
+
+/// Verifies the proof in the instruction data, which is the proof followed by
+/// the NB_PUBLIC_INPUTS public inputs of 32 bytes. The program fails with the
+/// custom error code of the [VerifierError] if the proof is rejected.
+pub fn process_instruction(
+    _program_id: &solana_program::pubkey::Pubkey,
+    _accounts: &[solana_program::account_info::AccountInfo],
+    data: &[u8],
+) -> solana_program::entrypoint::ProgramResult {
+    let nb_bytes_inputs = 32 * NB_PUBLIC_INPUTS;
+    if data.len() < nb_bytes_inputs {
+        return Err(solana_program::program_error::ProgramError::InvalidInstructionData);
+    }
+    let (proof, inputs) = data.split_at(data.len() - nb_bytes_inputs);
+    let public_inputs: Vec<[u8; 32]> = inputs.chunks_exact(32).map(|c| c.try_into().unwrap()).collect();
+    verify(proof, &public_inputs)
+        .map_err(|e| solana_program::program_error::ProgramError::Custom(e as u32))
+}
+{{- else }}
+
+/// Operations on the G1 points of BN254, encoded as in EIP-196 and EIP-197,
+/// with arkworks.
+mod bn254 {
+    use super::VerifierError;
+    use ark_bn254::{Bn254, Fq, Fq12, Fq2, G1Affine, G2Affine};
+    use ark_ec::{pairing::Pairing, AffineRepr, CurveGroup};
+    use ark_ff::{BigInt, BigInteger, One, PrimeField, Zero};
+
+    fn fq_from_be(b: &[u8]) -> Result<Fq, VerifierError> {
+        let mut limbs = [0u64; 4];
+        for (i, limb) in limbs.iter_mut().enumerate() {
+            let mut buf = [0u8; 8];
+            buf.copy_from_slice(&b[24 - 8 * i..32 - 8 * i]);
+            *limb = u64::from_be_bytes(buf);
+        }
+        Fq::from_bigint(BigInt::new(limbs)).ok_or(VerifierError::CurveOperation)
+    }
+
+    fn g1_from_bytes(b: &[u8]) -> Result<G1Affine, VerifierError> {
+        let x = fq_from_be(&b[..32])?;
+        let y = fq_from_be(&b[32..64])?;
+        if x.is_zero() && y.is_zero() {
+            return Ok(G1Affine::zero());
+        }
+        // the cofactor of G1 is one.
+        let p = G1Affine::new_unchecked(x, y);
+        if !p.is_on_curve() {
+            return Err(VerifierError::CurveOperation);
+        }
+        Ok(p)
+    }
+
+    fn g1_to_bytes(p: &G1Affine) -> [u8; 64] {
+        let mut res = [0u8; 64];
+        if let Some((x, y)) = p.xy() {
+            res[..32].copy_from_slice(&x.into_bigint().to_bytes_be());
+            res[32..].copy_from_slice(&y.into_bigint().to_bytes_be());
+        }
+        res
+    }
+
+    fn g2_from_bytes(b: &[u8]) -> Result<G2Affine, VerifierError> {
+        // x.c1 || x.c0 || y.c1 || y.c0
+        let x = Fq2::new(fq_from_be(&b[32..64])?, fq_from_be(&b[..32])?);
+        let y = Fq2::new(fq_from_be(&b[96..128])?, fq_from_be(&b[64..96])?);
+        if x.is_zero() && y.is_zero() {
+            return Ok(G2Affine::zero());
+        }
+        let p = G2Affine::new_unchecked(x, y);
+        if !p.is_on_curve() || !p.is_in_correct_subgroup_assuming_on_curve() {
+            return Err(VerifierError::CurveOperation);
+        }
+        Ok(p)
+    }
+
+    /// Returns p + q.
+    pub fn add(p: &[u8; 64], q: &[u8; 64]) -> Result<[u8; 64], VerifierError> {
+        let r = g1_from_bytes(p)?.into_group() + g1_from_bytes(q)?.into_group();
+        Ok(g1_to_bytes(&r.into_affine()))
+    }
+
+    /// Returns [s]p, s is big-endian.
+    pub fn mul(p: &[u8; 64], s: &[u8; 32]) -> Result<[u8; 64], VerifierError> {
+        let mut limbs = [0u64; 4];
+        for (i, limb) in limbs.iter_mut().enumerate() {
+            let mut buf = [0u8; 8];
+            buf.copy_from_slice(&s[24 - 8 * i..32 - 8 * i]);
+            *limb = u64::from_be_bytes(buf);
+        }
+        Ok(g1_to_bytes(&g1_from_bytes(p)?.mul_bigint(limbs).into_affine()))
+    }
+
+    /// Returns true if the product of the pairings of the pairs (G1, G2) of
+    /// the input is one.
+    pub fn pairing_check(input: &[u8]) -> Result<bool, VerifierError> {
+        if input.len() % 192 != 0 {
+            return Err(VerifierError::CurveOperation);
+        }
+        let mut a = Vec::with_capacity(input.len() / 192);
+        let mut b = Vec::with_capacity(input.len() / 192);
+        for pair in input.chunks_exact(192) {
+            a.push(g1_from_bytes(&pair[..64])?);
+            b.push(g2_from_bytes(&pair[64..])?);
+        }
+        Ok(Bn254::multi_pairing(a, b).0 == Fq12::one())
+    }
+}
+
+#[cosmwasm_schema::cw_serde]
+pub struct InstantiateMsg {}
+
+#[cosmwasm_schema::cw_serde]
+#[derive(cosmwasm_schema::QueryResponses)]
+pub enum QueryMsg {
+    /// Returns true if the proof, serialized with MarshalSolidity, is valid
+    /// for the public inputs of 32 bytes.
+    #[returns(bool)]
+    Verify {
+        proof: cosmwasm_std::Binary,
+        public_inputs: Vec<cosmwasm_std::Binary>,
+    },
+}
+
+#[cfg_attr(not(feature = "library"), cosmwasm_std::entry_point)]
+pub fn instantiate(
+    _deps: cosmwasm_std::DepsMut,
+    _env: cosmwasm_std::Env,
+    _info: cosmwasm_std::MessageInfo,
+    _msg: InstantiateMsg,
+) -> cosmwasm_std::StdResult<cosmwasm_std::Response> {
+    Ok(cosmwasm_std::Response::default())
+}
+
+#[cfg_attr(not(feature = "library"), cosmwasm_std::entry_point)]
+pub fn query(
+    _deps: cosmwasm_std::Deps,
+    _env: cosmwasm_std::Env,
+    msg: QueryMsg,
+) -> cosmwasm_std::StdResult<cosmwasm_std::Binary> {
+    match msg {
+        QueryMsg::Verify { proof, public_inputs } => {
+            let mut inputs = Vec::with_capacity(public_inputs.len());
+            for input in &public_inputs {
+                let input: [u8; 32] = input
+                    .as_slice()
+                    .try_into()
+                    .map_err(|_| cosmwasm_std::StdError::generic_err("public inputs must be 32 bytes"))?;
+                inputs.push(input);
+            }
+            cosmwasm_std::to_json_binary(&verify(proof.as_slice(), &inputs).is_ok())
+        }
+    }
+}
+{{- end }}
+{{- end }}`
@@ -0,0 +1,105 @@
+package rust_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/rust"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+type mulCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+	Z    frontend.Variable
+}
+
+func (c *mulCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.Z, c.Z), api.Add(c.X, c.Y))
+	return nil
+}
+
+type commitCircuit struct {
+	mulCircuit
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Z)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return c.mulCircuit.Define(api)
+}
+
+func exporters(t *testing.T) map[string]rust.Exporter {
+	res := make(map[string]rust.Exporter)
+	for name, circuit := range map[string]frontend.Circuit{"mul": &mulCircuit{}, "commit": &commitCircuit{}} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+		require.NoError(t, err)
+		_, vk, err := groth16.Setup(ccs)
+		require.NoError(t, err)
+		res["groth16/"+name] = vk.(rust.Exporter)
+
+		ccs, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, circuit)
+		require.NoError(t, err)
+		srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+		require.NoError(t, err)
+		_, pvk, err := plonk.Setup(ccs, srs, srsLagrange)
+		require.NoError(t, err)
+		res["plonk/"+name] = pvk.(rust.Exporter)
+	}
+	return res
+}
+
+func TestExportRust(t *testing.T) {
+	for name, vk := range exporters(t) {
+		for _, target := range []rust.Target{rust.Solana, rust.CosmWasm} {
+			t.Run(name+"/"+target.String(), func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, vk.ExportRust(&buf, rust.WithTarget(target)))
+				src := buf.String()
+				require.Contains(t, src, "pub const NB_PUBLIC_INPUTS: usize = 2;")
+				require.Contains(t, src, "pub fn verify(")
+				switch target {
+				case rust.Solana:
+					require.Contains(t, src, "entrypoint!(process_instruction);")
+				case rust.CosmWasm:
+					require.Contains(t, src, "pub fn query(")
+				}
+			})
+		}
+	}
+}
+
+func TestExportCrate(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	for _, vk := range exporters(t) {
+		assert.NoError(rust.ExportCrate(dir, vk, rust.WithTarget(rust.CosmWasm), rust.WithCrateName("my_verifier")))
+		break
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+	assert.NoError(err)
+	assert.True(strings.Contains(string(manifest), `name = "my_verifier"`))
+	assert.True(strings.Contains(string(manifest), "cosmwasm-std"))
+	_, err = os.Stat(filepath.Join(dir, "src", "lib.rs"))
+	assert.NoError(err)
+}
+
+func TestExportConfig(t *testing.T) {
+	assert := require.New(t)
+	_, err := rust.NewExportConfig(rust.WithCrateName("not a name"))
+	assert.Error(err)
+	_, err = rust.NewExportConfig(rust.WithTarget(rust.Target(42)))
+	assert.Error(err)
+}
@@ -0,0 +1,235 @@
+// Package scheduler implements a queue of proving jobs with admission control:
+// it bounds the number of proofs computed concurrently and the total memory
+// they are estimated to use, so that a proving service under load queues the
+// requests instead of running out of memory.
+//
+// Jobs are admitted in order of submission: a job waits until all the jobs
+// submitted before it are admitted and enough memory is available. A job
+// estimated to use more than the memory limit runs alone.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Option defines option for altering the behavior of the scheduler. See the
+// descriptions of functions returning instances of this type for implemented
+// options.
+type Option func(*Config) error
+
+// Config is the configuration of the scheduler with the options applied.
+type Config struct {
+	MaxConcurrent   int
+	MemoryLimit     uint64
+	MemoryEstimator func(ccs constraint.ConstraintSystem) uint64
+}
+
+// WithMaxConcurrent sets the maximum number of jobs running concurrently. If
+// not set, jobs run one at a time. Zero means no limit.
+func WithMaxConcurrent(n int) Option {
+	return func(cfg *Config) error {
+		if n < 0 {
+			return errors.New("maximum number of concurrent jobs must be positive")
+		}
+		cfg.MaxConcurrent = n
+		return nil
+	}
+}
+
+// WithMemoryLimit sets the maximum total memory, in bytes, that running jobs
+// are estimated to use. If not set, memory is not limited.
+func WithMemoryLimit(bytes uint64) Option {
+	return func(cfg *Config) error {
+		cfg.MemoryLimit = bytes
+		return nil
+	}
+}
+
+// WithMemoryEstimator sets the function estimating the memory used to prove
+// a constraint system, in bytes. If not set, [EstimateMemory] is used.
+func WithMemoryEstimator(f func(ccs constraint.ConstraintSystem) uint64) Option {
+	return func(cfg *Config) error {
+		if f == nil {
+			return errors.New("memory estimator must not be nil")
+		}
+		cfg.MemoryEstimator = f
+		return nil
+	}
+}
+
+// memoryFactor is the approximate number of field elements (or equivalent
+// size in curve points) allocated by the provers per constraint or wire.
+const memoryFactor = 24
+
+// EstimateMemory returns a rough estimate of the peak memory, in bytes, used
+// to prove the constraint system, excluding the proving key.
+func EstimateMemory(ccs constraint.ConstraintSystem) uint64 {
+	n := uint64(ccs.GetNbConstraints()) + uint64(ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables()+ccs.GetNbInternalVariables())
+	elementSize := uint64((ccs.Field().BitLen() + 63) / 64 * 8)
+	return n * elementSize * memoryFactor
+}
+
+// Stats are the metrics of a scheduler.
+type Stats struct {
+	Queued      int           // jobs waiting to be admitted
+	Running     int           // jobs running
+	MemoryInUse uint64        // estimated memory of the running jobs
+	Completed   uint64        // jobs which returned without error
+	Failed      uint64        // jobs which returned an error
+	Canceled    uint64        // jobs canceled before being admitted
+	WaitTime    time.Duration // total time spent by the admitted jobs in the queue
+}
+
+type job struct {
+	memory   uint64
+	admitted chan struct{}
+}
+
+// Scheduler queues proving jobs. It is safe for concurrent use.
+type Scheduler struct {
+	cfg Config
+
+	lock  sync.Mutex
+	queue []*job
+	stats Stats
+}
+
+// New returns a new scheduler with the given options.
+func New(opts ...Option) (*Scheduler, error) {
+	cfg := Config{
+		MaxConcurrent:   1,
+		MemoryEstimator: EstimateMemory,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &Scheduler{cfg: cfg}, nil
+}
+
+// Stats returns the current metrics of the scheduler.
+func (s *Scheduler) Stats() Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats := s.stats
+	stats.Queued = len(s.queue)
+	return stats
+}
+
+// Do runs the proving job f for the constraint system once it is admitted,
+// and returns its error. If the context is done before the job is admitted,
+// the job is removed from the queue and Do returns the context error.
+func (s *Scheduler) Do(ctx context.Context, ccs constraint.ConstraintSystem, f func() error) error {
+	memory := s.cfg.MemoryEstimator(ccs)
+	if s.cfg.MemoryLimit != 0 && memory > s.cfg.MemoryLimit {
+		memory = s.cfg.MemoryLimit // runs alone
+	}
+	if err := s.acquire(ctx, memory); err != nil {
+		return err
+	}
+	err := errors.New("proving job panicked")
+	defer func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if err != nil {
+			s.stats.Failed++
+		} else {
+			s.stats.Completed++
+		}
+		s.release(memory)
+	}()
+	err = f()
+	return err
+}
+
+// ProveGroth16 computes a Groth16 proof with [groth16.Prove] once admitted.
+func (s *Scheduler) ProveGroth16(ctx context.Context, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (groth16.Proof, error) {
+	var proof groth16.Proof
+	err := s.Do(ctx, ccs, func() (err error) {
+		proof, err = groth16.Prove(ccs, pk, fullWitness, opts...)
+		return err
+	})
+	return proof, err
+}
+
+// ProvePlonk computes a PLONK proof with [plonk.Prove] once admitted.
+func (s *Scheduler) ProvePlonk(ctx context.Context, ccs constraint.ConstraintSystem, pk plonk.ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (plonk.Proof, error) {
+	var proof plonk.Proof
+	err := s.Do(ctx, ccs, func() (err error) {
+		proof, err = plonk.Prove(ccs, pk, fullWitness, opts...)
+		return err
+	})
+	return proof, err
+}
+
+func (s *Scheduler) acquire(ctx context.Context, memory uint64) error {
+	start := time.Now()
+	j := &job{memory: memory, admitted: make(chan struct{})}
+	s.lock.Lock()
+	s.queue = append(s.queue, j)
+	s.admit()
+	s.lock.Unlock()
+
+	select {
+	case <-j.admitted:
+	case <-ctx.Done():
+		s.lock.Lock()
+		select {
+		case <-j.admitted:
+			// admitted concurrently, give the slot back.
+			s.release(memory)
+		default:
+			for i := range s.queue {
+				if s.queue[i] == j {
+					s.queue = append(s.queue[:i], s.queue[i+1:]...)
+					break
+				}
+			}
+			s.admit() // the jobs behind may fit now
+		}
+		s.stats.Canceled++
+		s.lock.Unlock()
+		return ctx.Err()
+	}
+
+	s.lock.Lock()
+	s.stats.WaitTime += time.Since(start)
+	s.lock.Unlock()
+	return nil
+}
+
+// release frees the slot of a running job. It must be called with the lock
+// held.
+func (s *Scheduler) release(memory uint64) {
+	s.stats.Running--
+	s.stats.MemoryInUse -= memory
+	s.admit()
+}
+
+// admit admits the jobs at the head of the queue while they fit. It must be
+// called with the lock held.
+func (s *Scheduler) admit() {
+	for len(s.queue) > 0 {
+		j := s.queue[0]
+		if s.cfg.MaxConcurrent != 0 && s.stats.Running >= s.cfg.MaxConcurrent {
+			return
+		}
+		if s.cfg.MemoryLimit != 0 && s.stats.MemoryInUse+j.memory > s.cfg.MemoryLimit {
+			return
+		}
+		s.queue = s.queue[1:]
+		s.stats.Running++
+		s.stats.MemoryInUse += j.memory
+		close(j.admitted)
+	}
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+// sizedCS is a constraint system whose estimated memory is its size.
+type sizedCS struct {
+	constraint.ConstraintSystem
+	size uint64
+}
+
+func estimateSize(ccs constraint.ConstraintSystem) uint64 {
+	return ccs.(*sizedCS).size
+}
+
+// waitStats polls the stats until cond holds.
+func waitStats(t *testing.T, s *Scheduler, cond func(Stats) bool) Stats {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		stats := s.Stats()
+		if cond(stats) {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected stats %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMemoryAdmission(t *testing.T) {
+	assert := require.New(t)
+	s, err := New(WithMaxConcurrent(0), WithMemoryLimit(100), WithMemoryEstimator(estimateSize))
+	assert.NoError(err)
+
+	// jobs of size 60, 30, 20, 200: the first two run, the third waits for
+	// memory and the fourth, larger than the limit, waits to run alone.
+	var release [4]chan struct{}
+	done := make(chan int, 4)
+	for i, size := range []uint64{60, 30, 20, 200} {
+		release[i] = make(chan struct{})
+		i, size := i, size
+		go func() {
+			err := s.Do(context.Background(), &sizedCS{size: size}, func() error {
+				<-release[i]
+				return nil
+			})
+			assert.NoError(err)
+			done <- i
+		}()
+		// submit in order
+		waitStats(t, s, func(st Stats) bool { return st.Running+st.Queued == i+1 })
+	}
+	stats := waitStats(t, s, func(st Stats) bool { return st.Running == 2 && st.Queued == 2 })
+	assert.EqualValues(90, stats.MemoryInUse)
+
+	close(release[0])
+	assert.Equal(0, <-done)
+	stats = waitStats(t, s, func(st Stats) bool { return st.Running == 2 && st.Queued == 1 })
+	assert.EqualValues(50, stats.MemoryInUse)
+
+	close(release[1])
+	close(release[2])
+	<-done
+	<-done
+	stats = waitStats(t, s, func(st Stats) bool { return st.Running == 1 && st.Queued == 0 })
+	assert.EqualValues(100, stats.MemoryInUse)
+
+	close(release[3])
+	assert.Equal(3, <-done)
+	stats = s.Stats()
+	assert.EqualValues(4, stats.Completed)
+	assert.EqualValues(0, stats.MemoryInUse)
+}
+
+func TestCancel(t *testing.T) {
+	assert := require.New(t)
+	s, err := New(WithMemoryEstimator(estimateSize))
+	assert.NoError(err)
+
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- s.Do(context.Background(), &sizedCS{size: 1}, func() error {
+			<-release
+			return errors.New("failed")
+		})
+	}()
+	waitStats(t, s, func(st Stats) bool { return st.Running == 1 })
+
+	// the second job is queued behind the first one, then canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- s.Do(ctx, &sizedCS{size: 1}, func() error {
+			t.Error("canceled job should not run")
+			return nil
+		})
+	}()
+	waitStats(t, s, func(st Stats) bool { return st.Queued == 1 })
+	cancel()
+	assert.ErrorIs(<-done, context.Canceled)
+
+	close(release)
+	assert.Error(<-done)
+	stats := s.Stats()
+	assert.EqualValues(1, stats.Canceled)
+	assert.EqualValues(1, stats.Failed)
+	assert.Equal(0, stats.Running+stats.Queued)
+}
+
+func TestProveGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&cubic.Circuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+
+	s, err := New(WithMemoryLimit(EstimateMemory(ccs)))
+	assert.NoError(err)
+	proof, err := s.ProveGroth16(context.Background(), ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pw))
+	assert.EqualValues(1, s.Stats().Completed)
+}
@@ -0,0 +1,310 @@
+// Package srs loads KZG structured reference strings (SRS) for PLONK from the
+// transcripts of public trusted setup ceremonies, as a replacement of the
+// test only SRS of test/unsafekzg in production code.
+//
+// The transcripts are downloaded with [Fetch], which checks them against a
+// checksum pinned by the caller, and read with [ReadPtau], which truncates
+// them to the size needed by the circuit and checks that the points are
+// consistent powers of a secret τ with [Verify]. The supported format is the
+// snarkjs Powers of Tau format (.ptau), used by the Hermez (Polygon) ceremony
+// on BN254; the Aztec Ignition and Celo transcripts formats are not supported.
+package srs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// The transcripts of the Hermez ceremony have 2^power powers of τ in G₂ and
+// 2^(power+1)-1 in G₁, for power in [HermezMinPower, HermezMaxPower].
+const (
+	HermezMinPower = 8
+	HermezMaxPower = 28
+
+	hermezURL = "https://storage.googleapis.com/zkevm/ptau/powersOfTau28_hez_final_%02d.ptau"
+)
+
+// HermezURL returns the URL of the transcript of the Hermez ceremony with
+// 2^power powers of τ in G₂, to download with [Fetch]. The checksum is not
+// pinned here: it is computed by the caller from a trusted copy of the file.
+func HermezURL(power int) (string, error) {
+	if power < HermezMinPower || power > HermezMaxPower {
+		return "", fmt.Errorf("no Hermez transcript of power %d, expected [%d, %d]", power, HermezMinPower, HermezMaxPower)
+	}
+	return fmt.Sprintf(hermezURL, power), nil
+}
+
+// Fetch downloads the file at url into the cache directory, unless it is
+// already there, and checks that its SHA2-256 checksum is the given hex
+// encoded checksum. It returns the path of the file.
+func Fetch(ctx context.Context, url, checksum, cacheDir string) (string, error) {
+	if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != 2*sha256.Size {
+		return "", fmt.Errorf("invalid SHA2-256 checksum %q", checksum)
+	}
+	path := filepath.Join(cacheDir, checksum)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(cacheDir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name()) // no-op once renamed
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", url, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != checksum {
+		return "", fmt.Errorf("download %s: checksum mismatch, got %s", url, got)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// NewSRS returns the canonical and Lagrange SRS for PLONK proofs of the
+// constraint system, read from the Powers of Tau file at path.
+func NewSRS(ccs constraint.ConstraintSystem, path string) (canonical, lagrange kzg.SRS, err error) {
+	if curve := utils.FieldToCurve(ccs.Field()); curve != ecc.BN254 {
+		return nil, nil, fmt.Errorf("powers of tau files are only supported on BN254, not %s", curve)
+	}
+	sizeCanonical, sizeLagrange := plonk.SRSSize(ccs)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	srs, err := ReadPtau(f, sizeCanonical)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lagrangeSRS := &kzg_bn254.SRS{Vk: srs.Vk}
+	lagrangeSRS.Pk.G1, err = kzg_bn254.ToLagrangeG1(srs.Pk.G1[:sizeLagrange])
+	if err != nil {
+		return nil, nil, err
+	}
+	return srs, lagrangeSRS, nil
+}
+
+// ptau sections
+const (
+	ptauHeader = 1
+	ptauTauG1  = 2
+	ptauTauG2  = 3
+)
+
+// ReadPtau reads the first size powers of τ in G1 from a Powers of Tau file
+// in the snarkjs format and returns them as a KZG SRS, after checking them
+// with Verify.
+func ReadPtau(r io.ReadSeeker, size int) (*kzg_bn254.SRS, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if string(magic[:]) != "ptau" {
+		return nil, errors.New("not a powers of tau file")
+	}
+	var version, nbSections uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &nbSections); err != nil {
+		return nil, err
+	}
+
+	// index the sections
+	sections := make(map[uint32]int64)
+	for i := uint32(0); i < nbSections; i++ {
+		var id uint32
+		var length uint64
+		if err := binary.Read(r, binary.LittleEndian, &id); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return nil, err
+		}
+		offset, err := r.Seek(int64(length), io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		sections[id] = offset - int64(length)
+	}
+	for _, id := range []uint32{ptauHeader, ptauTauG1, ptauTauG2} {
+		if _, ok := sections[id]; !ok {
+			return nil, fmt.Errorf("missing section %d", id)
+		}
+	}
+
+	// header: field element size, modulus, power, ceremony power
+	if _, err := r.Seek(sections[ptauHeader], io.SeekStart); err != nil {
+		return nil, err
+	}
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return nil, err
+	}
+	if n8 != fp.Bytes {
+		return nil, fmt.Errorf("unsupported field element size %d", n8)
+	}
+	q := make([]byte, n8)
+	if _, err := io.ReadFull(r, q); err != nil {
+		return nil, err
+	}
+	if new(big.Int).SetBytes(reverse(q)).Cmp(fp.Modulus()) != 0 {
+		return nil, errors.New("not a BN254 powers of tau file")
+	}
+	var power uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return nil, err
+	}
+	if nbG1 := uint64(2)<<power - 1; uint64(size) > nbG1 || size < 2 {
+		return nil, fmt.Errorf("invalid SRS size %d, the file has %d powers of tau", size, nbG1)
+	}
+
+	var srs kzg_bn254.SRS
+	if _, err := r.Seek(sections[ptauTauG1], io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 2*fp.Bytes)
+	srs.Pk.G1 = make([]bn254.G1Affine, size)
+	for i := range srs.Pk.G1 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		srs.Pk.G1[i].X = readElement(buf[:fp.Bytes])
+		srs.Pk.G1[i].Y = readElement(buf[fp.Bytes:])
+	}
+
+	if _, err := r.Seek(sections[ptauTauG2], io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf = make([]byte, 4*fp.Bytes)
+	for i := range srs.Vk.G2 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		srs.Vk.G2[i].X.A0 = readElement(buf[:fp.Bytes])
+		srs.Vk.G2[i].X.A1 = readElement(buf[fp.Bytes : 2*fp.Bytes])
+		srs.Vk.G2[i].Y.A0 = readElement(buf[2*fp.Bytes : 3*fp.Bytes])
+		srs.Vk.G2[i].Y.A1 = readElement(buf[3*fp.Bytes:])
+	}
+	srs.Vk.G1 = srs.Pk.G1[0]
+
+	if err := Verify(&srs); err != nil {
+		return nil, err
+	}
+	srs.Vk.Lines[0] = bn254.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = bn254.PrecomputeLines(srs.Vk.G2[1])
+	return &srs, nil
+}
+
+// readElement reads a field element in Montgomery form, little endian, as
+// in the snarkjs files. The Montgomery form of gnark-crypto is the same.
+func readElement(b []byte) fp.Element {
+	var e fp.Element
+	for i := range e {
+		e[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return e
+}
+
+func reverse(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[len(b)-1-i] = b[i]
+	}
+	return res
+}
+
+// Verify checks that the SRS is made of the powers of a secret τ:
+//
+//	G1 = [G₁, [τ]G₁, [τ²]G₁, ...] and G2 = [G₂, [τ]G₂]
+//
+// where G₁ and G₂ are the standard generators. It checks all the powers at
+// once with a random linear combination, which costs a multi-exponentiation
+// and a pairing check. It doesn't check the contributions of the ceremony.
+func Verify(srs *kzg_bn254.SRS) error {
+	g1 := srs.Pk.G1
+	if len(g1) < 2 {
+		return kzg_bn254.ErrMinSRSSize
+	}
+	_, _, gen1, gen2 := bn254.Generators()
+	if !g1[0].Equal(&gen1) || !srs.Vk.G1.Equal(&gen1) || !srs.Vk.G2[0].Equal(&gen2) {
+		return errors.New("invalid SRS: first powers are not the generators")
+	}
+	for i := range g1 {
+		if !g1[i].IsInSubGroup() {
+			return fmt.Errorf("invalid SRS: G1 point %d is not in the subgroup", i)
+		}
+	}
+	if !srs.Vk.G2[1].IsInSubGroup() {
+		return errors.New("invalid SRS: [τ]G₂ is not in the subgroup")
+	}
+
+	// e(Σ rᵢ[τⁱ⁺¹]G₁, G₂) = e(Σ rᵢ[τⁱ]G₁, [τ]G₂)
+	r := make([]fr.Element, len(g1)-1)
+	for i := range r {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+	var shifted, unshifted bn254.G1Affine
+	if _, err := shifted.MultiExp(g1[1:], r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := unshifted.MultiExp(g1[:len(g1)-1], r, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	unshifted.Neg(&unshifted)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{shifted, unshifted}, []bn254.G2Affine{srs.Vk.G2[0], srs.Vk.G2[1]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid SRS: points are not consistent powers of tau")
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package srs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// writePtau writes the SRS in the snarkjs powers of tau format, with 2^power
+// powers of tau in G2 of which only the first two are set.
+func writePtau(t *testing.T, srs *kzg_bn254.SRS, power uint32) []byte {
+	require.Len(t, srs.Pk.G1, 2<<power-1)
+	write := func(buf *bytes.Buffer, vs ...any) {
+		for _, v := range vs {
+			require.NoError(t, binary.Write(buf, binary.LittleEndian, v))
+		}
+	}
+
+	var header, tauG1, tauG2 bytes.Buffer
+	write(&header, uint32(fp.Bytes), reverse(fp.Modulus().FillBytes(make([]byte, fp.Bytes))), power, power)
+	for _, p := range srs.Pk.G1 {
+		write(&tauG1, p.X[:], p.Y[:])
+	}
+	for i := 0; i < 1<<power; i++ {
+		if i < 2 {
+			g := srs.Vk.G2[i]
+			write(&tauG2, g.X.A0[:], g.X.A1[:], g.Y.A0[:], g.Y.A1[:])
+		} else {
+			tauG2.Write(make([]byte, 4*fp.Bytes))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("ptau")
+	write(&buf, uint32(1), uint32(3)) // version, number of sections
+	// sections may be in any order
+	for _, section := range []struct {
+		id      uint32
+		content *bytes.Buffer
+	}{{ptauTauG2, &tauG2}, {ptauHeader, &header}, {ptauTauG1, &tauG1}} {
+		write(&buf, section.id, uint64(section.content.Len()))
+		buf.Write(section.content.Bytes())
+	}
+	return buf.Bytes()
+}
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestReadPtau(t *testing.T) {
+	assert := require.New(t)
+	const power = 4
+	srs, err := kzg_bn254.NewSRS(2<<power-1, big.NewInt(42))
+	assert.NoError(err)
+	ptau := writePtau(t, srs, power)
+
+	read, err := ReadPtau(bytes.NewReader(ptau), 10)
+	assert.NoError(err)
+	assert.Equal(srs.Pk.G1[:10], read.Pk.G1)
+	assert.Equal(srs.Vk, read.Vk)
+
+	_, err = ReadPtau(bytes.NewReader(ptau), 2<<power)
+	assert.ErrorContains(err, "invalid SRS size")
+
+	// powers of different secrets
+	tampered := *srs
+	tampered.Pk.G1 = append(tampered.Pk.G1[:0:0], srs.Pk.G1...)
+	tampered.Pk.G1[3], tampered.Pk.G1[4] = tampered.Pk.G1[4], tampered.Pk.G1[3]
+	_, err = ReadPtau(bytes.NewReader(writePtau(t, &tampered, power)), 10)
+	assert.ErrorContains(err, "not consistent")
+	_, err = ReadPtau(bytes.NewReader(writePtau(t, &tampered, power)), 3)
+	assert.NoError(err, "truncated before the tampered points")
+}
+
+// testPtau is a transcript with 2^4 powers of τ = 42 in G₂, written by
+// TestGeneratePtau.
+const testPtau = "testdata/tau42_04.ptau"
+
+func TestReadPtauFile(t *testing.T) {
+	assert := require.New(t)
+	f, err := os.Open(testPtau)
+	assert.NoError(err)
+	defer f.Close()
+
+	read, err := ReadPtau(f, 2<<4-1)
+	assert.NoError(err)
+	expected, err := kzg_bn254.NewSRS(2<<4-1, big.NewInt(42))
+	assert.NoError(err)
+	assert.Equal(expected.Pk.G1, read.Pk.G1)
+	assert.Equal(expected.Vk, read.Vk)
+}
+
+func TestGeneratePtau(t *testing.T) {
+	t.Skip("test used only to generate testdata")
+	srs, err := kzg_bn254.NewSRS(2<<4-1, big.NewInt(42))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(testPtau, writePtau(t, srs, 4), 0o600))
+}
+
+func TestHermezURL(t *testing.T) {
+	assert := require.New(t)
+	url, err := HermezURL(8)
+	assert.NoError(err)
+	assert.Equal("https://storage.googleapis.com/zkevm/ptau/powersOfTau28_hez_final_08.ptau", url)
+	_, err = HermezURL(HermezMaxPower + 1)
+	assert.Error(err)
+}
+
+func TestNewSRS(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+
+	const power = 4
+	srs, err := kzg_bn254.NewSRS(2<<power-1, big.NewInt(42))
+	assert.NoError(err)
+	path := filepath.Join(t.TempDir(), "test.ptau")
+	assert.NoError(os.WriteFile(path, writePtau(t, srs, power), 0o600))
+
+	canonical, lagrange, err := NewSRS(ccs, path)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, canonical, lagrange)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, pw))
+}
+
+func TestFetch(t *testing.T) {
+	assert := require.New(t)
+	content := []byte("powers of tau")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	dir := t.TempDir()
+
+	_, err := Fetch(context.Background(), server.URL, hex.EncodeToString(make([]byte, 32)), dir)
+	assert.ErrorContains(err, "checksum mismatch")
+	path, err := Fetch(context.Background(), server.URL, checksum, dir)
+	assert.NoError(err)
+	got, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(content, got)
+
+	// cached
+	server.Close()
+	path2, err := Fetch(context.Background(), server.URL, checksum, dir)
+	assert.NoError(err)
+	assert.Equal(path, path2)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+}
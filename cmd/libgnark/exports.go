@@ -0,0 +1,241 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+// backends, as backend.ID
+enum {
+	GNARK_GROTH16 = 1,
+	GNARK_PLONK = 2,
+};
+
+// curves, as ecc.ID
+enum {
+	GNARK_BN254 = 1,
+	GNARK_BLS12_377 = 2,
+	GNARK_BLS12_381 = 4,
+	GNARK_BLS24_315 = 5,
+	GNARK_BLS24_317 = 6,
+	GNARK_BW6_761 = 7,
+	GNARK_BW6_633 = 8,
+};
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/ffi"
+)
+
+// cError returns the error message as a C string, or nil if err is nil.
+func cError(err error) *C.char {
+	if err == nil {
+		return nil
+	}
+	return C.CString(err.Error())
+}
+
+// recoverError converts a panic into an error message, so that invalid
+// inputs don't crash the host process.
+func recoverError(res **C.char) {
+	if r := recover(); r != nil {
+		*res = cError(fmt.Errorf("panic: %v", r))
+	}
+}
+
+func goBytes(buf unsafe.Pointer, n C.size_t) []byte {
+	if buf == nil || n == 0 {
+		return nil
+	}
+	// copy the buffer, as keys can exceed the size accepted by C.GoBytes.
+	return append([]byte(nil), unsafe.Slice((*byte)(buf), int(n))...)
+}
+
+func getObject(h C.uintptr_t) (o *ffi.Object, err error) {
+	defer func() {
+		if recover() != nil {
+			err = errors.New("invalid handle")
+		}
+	}()
+	o, ok := cgo.Handle(h).Value().(*ffi.Object)
+	if !ok {
+		return nil, errors.New("invalid handle")
+	}
+	return o, nil
+}
+
+func newHandle(o *ffi.Object, out *C.uintptr_t) {
+	*out = C.uintptr_t(cgo.NewHandle(o))
+}
+
+func setBytes(data []byte, out *unsafe.Pointer, outLen *C.size_t) {
+	*out = C.CBytes(data)
+	*outLen = C.size_t(len(data))
+}
+
+// gnark_read_constraint_system reads a serialized constraint system of the
+// given backend and curve.
+//
+//export gnark_read_constraint_system
+func gnark_read_constraint_system(b C.int, curve C.int, buf unsafe.Pointer, n C.size_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := ffi.ReadConstraintSystem(backend.ID(b), ecc.ID(curve), goBytes(buf, n))
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(o, out)
+	return nil
+}
+
+// gnark_read_proving_key reads a serialized proving key of the given backend
+// and curve.
+//
+//export gnark_read_proving_key
+func gnark_read_proving_key(b C.int, curve C.int, buf unsafe.Pointer, n C.size_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := ffi.ReadProvingKey(backend.ID(b), ecc.ID(curve), goBytes(buf, n))
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(o, out)
+	return nil
+}
+
+// gnark_read_verifying_key reads a serialized verifying key of the given
+// backend and curve.
+//
+//export gnark_read_verifying_key
+func gnark_read_verifying_key(b C.int, curve C.int, buf unsafe.Pointer, n C.size_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := ffi.ReadVerifyingKey(backend.ID(b), ecc.ID(curve), goBytes(buf, n))
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(o, out)
+	return nil
+}
+
+// gnark_new_witness builds the full witness of the constraint system from a
+// JSON object mapping the names of the inputs to their values.
+//
+//export gnark_new_witness
+func gnark_new_witness(ccs C.uintptr_t, json *C.char, n C.size_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := getObject(ccs)
+	if err != nil {
+		return cError(err)
+	}
+	w, err := ffi.NewWitness(o, goBytes(unsafe.Pointer(json), n))
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(w, out)
+	return nil
+}
+
+// gnark_read_witness reads a serialized (full or public) witness on the
+// scalar field of the given curve.
+//
+//export gnark_read_witness
+func gnark_read_witness(curve C.int, buf unsafe.Pointer, n C.size_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	w, err := ffi.ReadWitness(ecc.ID(curve), goBytes(buf, n))
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(w, out)
+	return nil
+}
+
+// gnark_write_witness serializes the witness.
+//
+//export gnark_write_witness
+func gnark_write_witness(w C.uintptr_t, out *unsafe.Pointer, outLen *C.size_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := getObject(w)
+	if err != nil {
+		return cError(err)
+	}
+	data, err := ffi.WriteWitness(o)
+	if err != nil {
+		return cError(err)
+	}
+	setBytes(data, out, outLen)
+	return nil
+}
+
+// gnark_public_witness extracts the public part of the full witness.
+//
+//export gnark_public_witness
+func gnark_public_witness(w C.uintptr_t, out *C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	o, err := getObject(w)
+	if err != nil {
+		return cError(err)
+	}
+	pw, err := ffi.PublicWitness(o)
+	if err != nil {
+		return cError(err)
+	}
+	newHandle(pw, out)
+	return nil
+}
+
+// gnark_prove computes a serialized proof of the full witness.
+//
+//export gnark_prove
+func gnark_prove(ccs, pk, w C.uintptr_t, out *unsafe.Pointer, outLen *C.size_t) (res *C.char) {
+	defer recoverError(&res)
+	var objects [3]*ffi.Object
+	for i, h := range []C.uintptr_t{ccs, pk, w} {
+		o, err := getObject(h)
+		if err != nil {
+			return cError(err)
+		}
+		objects[i] = o
+	}
+	proof, err := ffi.Prove(objects[0], objects[1], objects[2])
+	if err != nil {
+		return cError(err)
+	}
+	setBytes(proof, out, outLen)
+	return nil
+}
+
+// gnark_verify verifies a serialized proof against the public witness.
+//
+//export gnark_verify
+func gnark_verify(vk C.uintptr_t, proof unsafe.Pointer, n C.size_t, publicWitness C.uintptr_t) (res *C.char) {
+	defer recoverError(&res)
+	vkObject, err := getObject(vk)
+	if err != nil {
+		return cError(err)
+	}
+	pw, err := getObject(publicWitness)
+	if err != nil {
+		return cError(err)
+	}
+	return cError(ffi.Verify(vkObject, goBytes(proof, n), pw))
+}
+
+// gnark_release releases the object referenced by the handle.
+//
+//export gnark_release
+func gnark_release(h C.uintptr_t) {
+	defer func() { _ = recover() }()
+	cgo.Handle(h).Delete()
+}
+
+// gnark_free frees a buffer or an error message returned by libgnark.
+//
+//export gnark_free
+func gnark_free(p unsafe.Pointer) {
+	C.free(p)
+}
@@ -0,0 +1,27 @@
+// Package main builds libgnark, a shared library exposing the gnark provers
+// and verifiers through a C interface, for use from other languages without
+// spawning a subprocess:
+//
+//	go build -buildmode=c-shared -o libgnark.so ./cmd/libgnark
+//
+// The build also writes the C header libgnark.h. The C functions exchange
+// gnark objects (constraint systems, keys, witnesses) through opaque handles,
+// and serialized objects (proofs, witnesses) through byte buffers, in the
+// binary formats of the corresponding Go objects (WriteTo / ReadFrom):
+//
+//	uintptr_t ccs, pk, vk, w, pw;
+//	char *err;
+//	err = gnark_read_constraint_system(GNARK_GROTH16, GNARK_BN254, buf, len, &ccs);
+//	err = gnark_read_proving_key(GNARK_GROTH16, GNARK_BN254, buf, len, &pk);
+//	err = gnark_new_witness(ccs, json, json_len, &w);
+//	err = gnark_prove(ccs, pk, w, &proof, &proof_len);
+//	err = gnark_public_witness(w, &pw);
+//	err = gnark_verify(vk, proof, proof_len, pw);
+//	gnark_release(w);
+//
+// Functions which can fail return NULL on success and an error message
+// otherwise. Error messages and output buffers are allocated with malloc and
+// must be freed with gnark_free. Handles must be freed with gnark_release.
+package main
+
+func main() {}
@@ -0,0 +1,218 @@
+package constraint
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxBlameGadgets is the maximum number of call stack frames displayed by
+// Blame.String.
+const maxBlameGadgets = 10
+
+// BlameInput is a public or secret input of the circuit on which a constraint
+// depends.
+type BlameInput struct {
+	Wire  int
+	Name  string
+	Value string // set by the solver when known
+}
+
+// Blame is the result of the backward dependency analysis of a constraint. See
+// System.Blame.
+type Blame struct {
+	// Inputs are the circuit inputs the constraint transitively depends on, in
+	// wire order.
+	Inputs []BlameInput
+	// Gadgets are the call stack frames recorded with the debug information of
+	// the constraint and of the instructions it depends on, closest first and
+	// without duplicates.
+	Gadgets []StackFrame
+}
+
+// String formats the blame for error messages.
+func (b *Blame) String() string {
+	var sbb strings.Builder
+	sbb.WriteString("depends on inputs:")
+	for i, in := range b.Inputs {
+		if i > 0 {
+			sbb.WriteByte(',')
+		}
+		sbb.WriteByte(' ')
+		sbb.WriteString(in.Name)
+		if in.Value != "" {
+			sbb.WriteByte('=')
+			sbb.WriteString(in.Value)
+		}
+	}
+	if len(b.Gadgets) > 0 {
+		sbb.WriteString("\nthrough gadgets:")
+		for i, f := range b.Gadgets {
+			if i == maxBlameGadgets {
+				sbb.WriteString("\n\t... ")
+				sbb.WriteString(strconv.Itoa(len(b.Gadgets) - maxBlameGadgets))
+				sbb.WriteString(" more")
+				break
+			}
+			sbb.WriteString("\n\t")
+			sbb.WriteString(f.Function)
+			sbb.WriteString("\n\t\t")
+			sbb.WriteString(f.File)
+			sbb.WriteByte(':')
+			sbb.WriteString(strconv.Itoa(int(f.Line)))
+		}
+	}
+	return sbb.String()
+}
+
+// Blame walks the wire dependency graph backwards from the given constraint:
+// it returns the circuit inputs from which the values of the wires of the
+// constraint are computed, and the call stacks recorded with the constraints
+// on the dependency paths.
+//
+// The dependency graph is not stored in the constraint system, it is rebuilt
+// from the blueprints on each call, in time linear in the size of the system.
+// Use [System.Dependencies] to blame several constraints.
+func (system *System) Blame(constraintID int) Blame {
+	return system.Dependencies().Blame(constraintID)
+}
+
+// Dependencies are the wires read and solved by the instructions of a
+// system, from which the blame of its constraints is computed. See
+// System.Dependencies.
+type Dependencies struct {
+	system *System
+	rec    *dependencyRecorder
+	reads  [][]uint32
+}
+
+// Dependencies replays the blueprints of the system to record the wires read
+// and solved by each instruction, in time linear in the size of the system,
+// so that the blame of several constraints is computed from a single replay.
+func (system *System) Dependencies() *Dependencies {
+	rec, reads := system.replayDependencies()
+	return &Dependencies{system: system, rec: rec, reads: reads}
+}
+
+// instructionOf returns the instruction adding the given constraint, or -1.
+// The constraints are added in the order of the instructions.
+func (system *System) instructionOf(constraintID int) int {
+	i := sort.Search(len(system.Instructions), func(i int) bool {
+		return int(system.Instructions[i].ConstraintOffset) > constraintID
+	}) - 1
+	// skip the instructions without constraints at the same offset
+	for ; i >= 0; i-- {
+		pi := system.Instructions[i]
+		if nb := system.Blueprints[pi.BlueprintID].NbConstraints(); nb > 0 {
+			if constraintID < int(pi.ConstraintOffset)+nb {
+				return i
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+// Blame returns the blame of the given constraint, see [System.Blame].
+func (d *Dependencies) Blame(constraintID int) Blame {
+	system, rec, reads := d.system, d.rec, d.reads
+	failing := system.instructionOf(constraintID)
+
+	var res Blame
+	if failing == -1 {
+		return res
+	}
+
+	seenFrames := make(map[StackFrame]struct{})
+	addFrames := func(iID int) {
+		pi := system.Instructions[iID]
+		first := int(pi.ConstraintOffset)
+		for cID := first; cID < first+system.Blueprints[pi.BlueprintID].NbConstraints(); cID++ {
+			for _, f := range system.GetCallStack(cID) {
+				if _, ok := seenFrames[f]; !ok {
+					seenFrames[f] = struct{}{}
+					res.Gadgets = append(res.Gadgets, f)
+				}
+			}
+		}
+	}
+
+	// breadth first search from the failing instruction.
+	visited := map[int]struct{}{failing: {}}
+	inputs := make(map[uint32]struct{})
+	queue := []int{failing}
+	for len(queue) > 0 {
+		iID := queue[0]
+		queue = queue[1:]
+		addFrames(iID)
+		for _, w := range reads[iID] {
+			if w < rec.offset {
+				inputs[w] = struct{}{}
+				continue
+			}
+			p := rec.producer[w-rec.offset]
+			if _, ok := visited[p]; p == -1 || ok {
+				continue
+			}
+			visited[p] = struct{}{}
+			queue = append(queue, p)
+		}
+	}
+
+	for w := uint32(0); w < rec.offset; w++ {
+		if w == 0 && system.Type == SystemR1CS {
+			continue // constant wire
+		}
+		if _, ok := inputs[w]; ok {
+			res.Inputs = append(res.Inputs, BlameInput{Wire: int(w), Name: system.VariableToString(int(w))})
+		}
+	}
+	return res
+}
+
+// replayDependencies replays the instructions to find the wires read by each
+// one, and the instruction solving each internal wire.
+func (system *System) replayDependencies() (*dependencyRecorder, [][]uint32) {
+	rec := &dependencyRecorder{
+		offset:   system.internalWireOffset(),
+		producer: make([]int, system.NbInternalVariables),
+	}
+	for i := range rec.producer {
+		rec.producer[i] = -1
+	}
+	reads := make([][]uint32, len(system.Instructions))
+	for i, pi := range system.Instructions {
+		rec.current = i
+		rec.reads = nil
+		system.Blueprints[pi.BlueprintID].UpdateInstructionTree(pi.Unpack(system), rec)
+		reads[i] = rec.reads
+	}
+	return rec, reads
+}
+
+// dependencyRecorder implements InstructionTree to record the wires read and
+// solved by the instructions. Inputs are reported as solved so that the
+// blueprints report them as read.
+type dependencyRecorder struct {
+	offset   uint32
+	producer []int // internal wire -> instruction solving it, -1 if not solved yet
+	current  int
+	reads    []uint32
+}
+
+func (r *dependencyRecorder) InsertWire(wire uint32, _ Level) {
+	r.producer[wire-r.offset] = r.current
+}
+
+func (r *dependencyRecorder) HasWire(wire uint32) bool {
+	return wire != math.MaxUint32 && wire < r.offset+uint32(len(r.producer))
+}
+
+func (r *dependencyRecorder) GetWireLevel(wire uint32) Level {
+	if wire >= r.offset && r.producer[wire-r.offset] == -1 {
+		return LevelUnset
+	}
+	r.reads = append(r.reads, wire)
+	return 0
+}
@@ -17,7 +17,10 @@
 package cs
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"math/big"
 	"time"
 
 	"github.com/consensys/gnark/backend/witness"
@@ -26,6 +29,7 @@ import (
 	"github.com/consensys/gnark/logger"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/rs/zerolog"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 )
@@ -75,12 +79,23 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 		return nil, err
 	}
 
-	// reset the stateful blueprints
-	for i := range cs.Blueprints {
-		if b, ok := cs.Blueprints[i].(constraint.BlueprintStateful); ok {
-			b.Reset()
+	return cs.solve(solver, log, start)
+}
+
+// solve runs the solver on its levels and formats the solution, as Solve.
+func (cs *system) solve(solver *solver, log zerolog.Logger, start time.Time) (any, error) {
+	// give the buffers which are not part of the solution back to the pool,
+	// once the logs are printed
+	keepValues := false
+	defer func() {
+		csolver.PutBuffer(solver.pool, solver.solved)
+		if !keepValues {
+			csolver.PutBuffer(solver.pool, solver.values)
+			csolver.PutBuffer(solver.pool, solver.a)
+			csolver.PutBuffer(solver.pool, solver.b)
+			csolver.PutBuffer(solver.pool, solver.c)
 		}
-	}
+	}()
 
 	// defer log printing once all solver.values are computed
 	// (or sooner, if a constraint is not satisfied)
@@ -102,6 +117,7 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 		res.A = solver.a
 		res.B = solver.b
 		res.C = solver.c
+		keepValues = true
 		return &res, nil
 	} else {
 		// sparse R1CS
@@ -114,6 +130,147 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 
 }
 
+// SolvePartial implements constraint.ConstraintSystem. The witness has the
+// size of the full witness, the values of the missing inputs being ignored.
+// The instructions are solved sequentially, in the order of the levels, once
+// the instructions they depend on are solved and the inputs they read are
+// known; the instructions of the stateful blueprints are deferred to Finish.
+func (cs *system) SolvePartial(witness witness.Witness, missing []int, opts ...csolver.Option) (constraint.PartialSolution, error) {
+	if cs.GkrInfo.Is() {
+		// the GKR hints share a state within a solve
+		return nil, errors.New("partial solving of GKR circuits is not supported")
+	}
+	solver, err := newSolver(cs, witness.Vector().(fr.Vector), opts...)
+	if err != nil {
+		return nil, err
+	}
+	nbInputs := len(cs.Public) + len(cs.Secret)
+	for _, w := range missing {
+		if w < 0 || w >= nbInputs || (w == 0 && cs.Type == constraint.SystemR1CS) {
+			return nil, fmt.Errorf("missing input %d is not an input of the circuit", w)
+		}
+		if solver.solved[w] {
+			solver.solved[w] = false
+			solver.values[w].SetZero()
+			solver.nbSolved--
+		}
+	}
+
+	g := cs.DependencyGraph()
+	done, err := solver.runPartial(&g)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PartialSolution{
+		cs:     cs,
+		values: solver.values,
+		solved: solver.solved,
+		a:      solver.a,
+		b:      solver.b,
+		c:      solver.c,
+		done:   done,
+	}
+	for i := range g.Nodes {
+		if done[i] || !dependenciesDone(&g.Nodes[i], done) {
+			continue
+		}
+		pi := cs.Instructions[i]
+		nbConstraints := cs.Blueprints[pi.BlueprintID].NbConstraints()
+		if nbConstraints == 0 {
+			res.hints = append(res.hints, i)
+		}
+		for cID := int(pi.ConstraintOffset); cID < int(pi.ConstraintOffset)+nbConstraints; cID++ {
+			res.constraints = append(res.constraints, cID)
+		}
+	}
+	return res, nil
+}
+
+// PartialSolution implements constraint.PartialSolution.
+type PartialSolution struct {
+	cs                 *system
+	values             fr.Vector
+	solved             []bool
+	a, b, c            fr.Vector // R1CS only
+	done               []bool    // instructions solved
+	hints, constraints []int
+}
+
+// SolvedWires implements constraint.PartialSolution.
+func (p *PartialSolution) SolvedWires() []int {
+	var res []int
+	for w, ok := range p.solved {
+		if ok {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// Blocking implements constraint.PartialSolution.
+func (p *PartialSolution) Blocking() (hints, constraints []int) {
+	return p.hints, p.constraints
+}
+
+// Finish implements constraint.PartialSolution.
+func (p *PartialSolution) Finish(witness witness.Witness, opts ...csolver.Option) (any, error) {
+	cs := p.cs
+	log := logger.Logger().With().Int("nbConstraints", cs.GetNbConstraints()).Logger()
+	start := time.Now()
+
+	solver, err := newSolver(cs, witness.Vector().(fr.Vector), opts...)
+	if err != nil {
+		log.Err(err).Send()
+		return nil, err
+	}
+
+	// the known inputs must not change, and the wires solved partially are
+	// not solved again
+	nbInputs := len(cs.Public) + len(cs.Secret)
+	for w := 0; w < nbInputs; w++ {
+		if p.solved[w] && !solver.values[w].Equal(&p.values[w]) {
+			return nil, fmt.Errorf("the value of the input %s differs from the partial solving", cs.VariableToString(w))
+		}
+	}
+	for w := nbInputs; w < len(p.solved); w++ {
+		if p.solved[w] {
+			solver.values[w] = p.values[w]
+			solver.solved[w] = true
+			solver.nbSolved++
+		}
+	}
+	copy(solver.a, p.a)
+	copy(solver.b, p.b)
+	copy(solver.c, p.c)
+
+	solver.levels = make([][]uint32, 0, len(cs.Levels))
+	for _, level := range cs.Levels {
+		var remaining []uint32
+		for _, i := range level {
+			if !p.done[i] {
+				remaining = append(remaining, i)
+			}
+		}
+		if len(remaining) != 0 {
+			solver.levels = append(solver.levels, remaining)
+		}
+	}
+
+	return cs.solve(solver, log, start)
+}
+
+// dependenciesDone returns true if the instructions the node depends on are
+// solved.
+func dependenciesDone(node *constraint.DependencyNode, done []bool) bool {
+	for _, d := range node.Dependencies {
+		if !done[d] {
+			return false
+		}
+	}
+	return true
+}
+
 // IsSolved
 // Deprecated: use _, err := Solve(...) instead
 func (cs *system) IsSolved(witness witness.Witness, opts ...csolver.Option) error {
@@ -151,6 +308,16 @@ func (cs *system) GetCoefficient(i int) (r constraint.Element) {
 	return
 }
 
+// Iterate calls f on the decompressed constraints of the system, in order,
+// until f returns false.
+func (cs *system) Iterate(f func(cID int, view constraint.ConstraintView) bool) {
+	cs.System.IterateWithCoefficients(func(coeffID uint32) *big.Int {
+		var r big.Int
+		cs.Coefficients[coeffID].BigInt(&r)
+		return &r
+	}, f)
+}
+
 // GetSparseR1Cs return the list of SparseR1C
 func (cs *system) GetSparseR1Cs() []constraint.SparseR1C {
 
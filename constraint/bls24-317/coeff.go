@@ -21,6 +21,7 @@ import (
 	"errors"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/internal/utils"
+	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
@@ -84,6 +85,66 @@ func (ct *CoeffTable) fromBytes(buf []byte) error {
 	return nil
 }
 
+// coeffChunkSize is the number of coefficients written at once by
+// writeStreamTo.
+const coeffChunkSize = 1 << 16
+
+// writeStreamTo writes the coefficients as toBytes, by chunks of
+// coeffChunkSize coefficients.
+func (ct *CoeffTable) writeStreamTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, min(len(ct.Coefficients), coeffChunkSize)*fr.Bytes)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(ct.Coefficients)))
+	var written int64
+	for start := 0; start < len(ct.Coefficients); start += coeffChunkSize {
+		for _, c := range ct.Coefficients[start:min(start+coeffChunkSize, len(ct.Coefficients))] {
+			for _, limb := range c {
+				buf = binary.LittleEndian.AppendUint64(buf, limb)
+			}
+		}
+		n, err := w.Write(buf)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		buf = buf[:0]
+	}
+	if len(buf) != 0 {
+		n, err := w.Write(buf)
+		written += int64(n)
+		return written, err
+	}
+	return written, nil
+}
+
+// readStreamFrom reads the coefficients written by writeStreamTo, without
+// reading r past them.
+func (ct *CoeffTable) readStreamFrom(r io.Reader) (int64, error) {
+	var b [8]byte
+	read, err := io.ReadFull(r, b[:])
+	if err != nil {
+		return int64(read), err
+	}
+	ctLen := binary.LittleEndian.Uint64(b[:])
+	ct.Coefficients = make([]fr.Element, 0, ctLen)
+	buf := make([]byte, min(ctLen, coeffChunkSize)*fr.Bytes)
+	for uint64(len(ct.Coefficients)) < ctLen {
+		chunk := buf[:min(ctLen-uint64(len(ct.Coefficients)), coeffChunkSize)*fr.Bytes]
+		n, err := io.ReadFull(r, chunk)
+		read += n
+		if err != nil {
+			return int64(read), err
+		}
+		for k := 0; k < len(chunk); k += fr.Bytes {
+			var c fr.Element
+			for j := 0; j < fr.Limbs; j++ {
+				c[j] = binary.LittleEndian.Uint64(chunk[k+j*8 : k+(j+1)*8])
+			}
+			ct.Coefficients = append(ct.Coefficients, c)
+		}
+	}
+	return int64(read), nil
+}
+
 func (ct *CoeffTable) AddCoeff(coeff constraint.Element) uint32 {
 	c := (*fr.Element)(coeff[:])
 	var cID uint32
@@ -17,15 +17,16 @@
 package cs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark-crypto/field/pool"
 	"github.com/consensys/gnark/constraint"
 	csolver "github.com/consensys/gnark/constraint/solver"
 	"github.com/rs/zerolog"
 	"math"
 	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -43,16 +44,54 @@ type solver struct {
 	solved   []bool
 	nbSolved uint64
 
+	// blueprints of the system, with the stateful ones cloned for this solve
+	blueprints []constraint.Blueprint
+
 	// maps hintID to hint function
 	mHintsFunctions map[csolver.HintID]csolver.Hint
 
+	// if set, the hints computed by a provider, prefetched by level; see
+	// csolver.WithHintProvider
+	remote *csolver.RemoteHints
+
 	// used to out api.Println
 	logger  zerolog.Logger
 	nbTasks int
 
+	// if set, called after each instruction; see csolver.WithInstructionHook
+	hook csolver.InstructionHook
+
+	// if set, called after each level; see csolver.WithProgress
+	progress func(solved, total uint64)
+
+	// if set, solving stops when it is done; see csolver.WithContext
+	ctx context.Context
+
+	// if set, the instructions are solved in order; see csolver.WithDeterministicOrder
+	deterministic bool
+
+	// if set, the unsatisfied constraints are collected in unsatisfied
+	// instead of stopping the solving; see csolver.WithCollectAllErrors
+	collectAll  bool
+	unsatisfied []*UnsatisfiedConstraintError
+
+	// computes the blame of the unsatisfied constraints, set on the first one
+	blame     *blameContext
+	blameOnce sync.Once
+
+	// levels of the instructions to solve, the levels of the system unless
+	// the solving is finished from a partial solution
+	levels [][]uint32
+
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
+	pool *csolver.Pool // memory reused across the solves, may be nil
+
 	q *big.Int
+
+	// if set, the solver doesn't branch on the values; see csolver.WithConstantTime
+	constantTime bool
+	qMinusTwo    *big.Int // exponent of the constant-time inverse
 }
 
 func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver, error) {
@@ -87,24 +126,69 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 
 	// hintsDependencies is from compile time; it contains the list of hints the solver **needs**
 	var missing []string
+	var remote *csolver.RemoteHints
+	if opt.HintProvider != nil {
+		ctx := opt.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		remote = csolver.NewRemoteHints(ctx, opt.HintProvider)
+	}
 	for hintUUID, hintID := range cs.MHintsDependencies {
-		if _, ok := hintFunctions[hintUUID]; !ok {
+		f, ok := hintFunctions[hintUUID]
+		if opt.HintReplay != nil {
+			// the outputs are recorded from a previous solve
+			f, ok = opt.HintReplay.Hint(hintUUID, hintID), true
+			hintFunctions[hintUUID] = f
+		}
+		if !ok && remote != nil {
+			// computed out of process
+			f, ok = remote.Hint(hintUUID, hintID), true
+			hintFunctions[hintUUID] = f
+		}
+		if !ok && opt.HintSandbox != nil {
+			// the hint of an untrusted module, executed in the sandbox
+			f, ok = csolver.SandboxedHint(opt.HintSandbox, hintID), true
+			hintFunctions[hintUUID] = f
+		}
+		if !ok {
 			missing = append(missing, hintID)
+			continue
+		}
+		if err := csolver.CheckHintVersion(hintID, f); err != nil {
+			return nil, err
 		}
 	}
 
 	if len(missing) > 0 {
 		return nil, fmt.Errorf("solver missing hint(s): %v", missing)
 	}
+	hintFunctions = csolver.GuardHints(cs.MHintsDependencies, hintFunctions, opt.HintTimeout)
+	if opt.HintTrace != nil {
+		hintFunctions = csolver.TraceHints(opt.HintTrace, cs.MHintsDependencies, hintFunctions)
+	}
 
 	s := solver{
 		system:          cs,
-		values:          make([]fr.Element, nbWires),
-		solved:          make([]bool, nbWires),
+		blueprints:      constraint.SolvingBlueprints(cs.Blueprints),
+		values:          csolver.GetBuffer[fr.Element](opt.Pool, nbWires, nbWires),
+		solved:          csolver.GetBuffer[bool](opt.Pool, nbWires, nbWires),
 		mHintsFunctions: hintFunctions,
+		remote:          remote,
 		logger:          opt.Logger,
 		nbTasks:         opt.NbTasks,
+		hook:            opt.InstructionHook,
+		progress:        opt.Progress,
+		ctx:             opt.Context,
+		levels:          cs.Levels,
+		deterministic:   opt.DeterministicOrder,
+		collectAll:      opt.CollectAllErrors,
+		pool:            opt.Pool,
 		q:               cs.Field(),
+		constantTime:    opt.ConstantTime,
+	}
+	if s.constantTime {
+		s.qMinusTwo = new(big.Int).Sub(s.q, big.NewInt(2))
 	}
 
 	// set the witness indexes as solved
@@ -123,9 +207,9 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 
 	if s.Type == constraint.SystemR1CS {
 		n := ecc.NextPowerOfTwo(uint64(cs.GetNbConstraints()))
-		s.a = make(fr.Vector, cs.GetNbConstraints(), n)
-		s.b = make(fr.Vector, cs.GetNbConstraints(), n)
-		s.c = make(fr.Vector, cs.GetNbConstraints(), n)
+		s.a = csolver.GetBuffer[fr.Element](opt.Pool, cs.GetNbConstraints(), int(n))
+		s.b = csolver.GetBuffer[fr.Element](opt.Pool, cs.GetNbConstraints(), int(n))
+		s.c = csolver.GetBuffer[fr.Element](opt.Pool, cs.GetNbConstraints(), int(n))
 	}
 
 	return &s, nil
@@ -212,27 +296,19 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 	// tmp IO big int memory
 	nbInputs := len(h.Inputs)
 	nbOutputs := int(h.OutputRange.End - h.OutputRange.Start)
-	inputs := make([]*big.Int, nbInputs)
-	outputs := make([]*big.Int, nbOutputs)
+	bigInts := make([]*big.Int, nbInputs+nbOutputs+1)
+	s.pool.GetBigInts(bigInts)
+	inputs := bigInts[:nbInputs]
+	outputs := bigInts[nbInputs : nbInputs+nbOutputs]
 	for i := 0; i < nbOutputs; i++ {
-		outputs[i] = pool.BigInt.Get()
 		outputs[i].SetUint64(0)
 	}
 
-	q := pool.BigInt.Get()
+	q := bigInts[nbInputs+nbOutputs]
 	q.Set(s.q)
 
 	for i := 0; i < nbInputs; i++ {
-		var v fr.Element
-		for _, term := range h.Inputs[i] {
-			if term.IsConstant() {
-				v.Add(&v, &s.Coefficients[term.CoeffID()])
-				continue
-			}
-			s.accumulateInto(term, &v)
-		}
-		inputs[i] = pool.BigInt.Get()
-		v.BigInt(inputs[i])
+		s.hintInput(h.Inputs[i], inputs[i])
 	}
 
 	err := f(q, inputs, outputs)
@@ -241,32 +317,111 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 	for i := range outputs {
 		v.SetBigInt(outputs[i])
 		s.set(int(h.OutputRange.Start)+i, v)
-		pool.BigInt.Put(outputs[i])
 	}
 
-	for i := range inputs {
-		pool.BigInt.Put(inputs[i])
-	}
-
-	pool.BigInt.Put(q)
+	s.pool.PutBigInts(bigInts)
 
 	return err
 }
 
+// hintInput sets res to the value of the input of a hint.
+func (s *solver) hintInput(l constraint.LinearExpression, res *big.Int) {
+	var v fr.Element
+	for _, term := range l {
+		if term.IsConstant() {
+			v.Add(&v, &s.Coefficients[term.CoeffID()])
+			continue
+		}
+		s.accumulateInto(term, &v)
+	}
+	v.BigInt(res)
+}
+
+// prefetchHints computes the hints of the level computed by the provider in
+// one call of the provider, as their inputs are solved by the previous levels.
+func (s *solver) prefetchHints(level []uint32) error {
+	if s.remote == nil {
+		return nil
+	}
+	var calls []csolver.HintCall
+	var h constraint.HintMapping
+	for _, i := range level {
+		pi := s.Instructions[i]
+		bc, ok := s.blueprints[pi.BlueprintID].(constraint.BlueprintHint)
+		if !ok {
+			continue
+		}
+		bc.DecompressHint(&h, pi.Unpack(&s.System))
+		if !s.remote.IsRemote(h.HintID) {
+			continue
+		}
+		call := csolver.HintCall{
+			ID:      h.HintID,
+			Inputs:  make([]*big.Int, len(h.Inputs)),
+			Outputs: make([]*big.Int, h.OutputRange.End-h.OutputRange.Start),
+		}
+		for j := range call.Inputs {
+			call.Inputs[j] = new(big.Int)
+			s.hintInput(h.Inputs[j], call.Inputs[j])
+		}
+		for j := range call.Outputs {
+			call.Outputs[j] = new(big.Int)
+		}
+		calls = append(calls, call)
+	}
+	return s.remote.Prefetch(s.q, calls)
+}
+
 func (s *solver) printLogs(logs []constraint.LogEntry) {
 	if s.logger.GetLevel() == zerolog.Disabled {
 		return
 	}
 
 	for i := 0; i < len(logs); i++ {
-		logLine := s.logValue(logs[i])
-		s.logger.Debug().Str(zerolog.CallerFieldName, logs[i].Caller).Msg(logLine)
+		e := s.logger.WithLevel(logs[i].Level)
+		if e == nil {
+			continue // below the logger level, don't resolve the values
+		}
+		e = e.Str(zerolog.CallerFieldName, logs[i].Caller)
+		if len(logs[i].Keys) != 0 {
+			// structured log entry, values are logged as fields
+			values := logs[i].FormatValues(s.resolveLogValues(logs[i]))
+			for j, key := range logs[i].Keys {
+				e = e.Str(key, values[j])
+			}
+			e.Send()
+			continue
+		}
+		e.Msg(s.logValue(logs[i]))
 	}
 }
 
 const unsolvedVariable = "<unsolved>"
 
 func (s *solver) logValue(log constraint.LogEntry) string {
+	toResolve := s.resolveLogValues(log)
+	if len(log.Stack) > 0 {
+		var sbb strings.Builder
+		for _, lID := range log.Stack {
+			location := s.SymbolTable.Locations[lID]
+			function := s.SymbolTable.Functions[location.FunctionID]
+
+			sbb.WriteString(function.Name)
+			sbb.WriteByte('\n')
+			sbb.WriteByte('\t')
+			sbb.WriteString(function.Filename)
+			sbb.WriteByte(':')
+			sbb.WriteString(strconv.Itoa(int(location.Line)))
+			sbb.WriteByte('\n')
+		}
+		toResolve = append(toResolve, sbb.String())
+	}
+	return fmt.Sprintf(log.Format, toResolve...)
+}
+
+// resolveLogValues evaluates the linear expressions of the log entry. Expressions
+// which can't be evaluated yet are resolved as unsolvedVariable.
+func (s *solver) resolveLogValues(log constraint.LogEntry) []interface{} {
 	var toResolve []interface{}
 	var (
 		eval         fr.Element
@@ -306,23 +461,7 @@ func (s *solver) logValue(log constraint.LogEntry) string {
 		}
 
 	}
-	if len(log.Stack) > 0 {
-		var sbb strings.Builder
-		for _, lID := range log.Stack {
-			location := s.SymbolTable.Locations[lID]
-			function := s.SymbolTable.Functions[location.FunctionID]
-
-			sbb.WriteString(function.Name)
-			sbb.WriteByte('\n')
-			sbb.WriteByte('\t')
-			sbb.WriteString(function.Filename)
-			sbb.WriteByte(':')
-			sbb.WriteString(strconv.Itoa(int(location.Line)))
-			sbb.WriteByte('\n')
-		}
-		toResolve = append(toResolve, sbb.String())
-	}
-	return fmt.Sprintf(log.Format, toResolve...)
+	return toResolve
 }
 
 // divByCoeff sets res = res / t.Coeff
@@ -362,6 +501,21 @@ func (s *solver) IsSolved(vID uint32) bool {
 	return s.solved[vID]
 }
 
+func (s *solver) ConstantTime() bool {
+	return s.constantTime
+}
+
+// Inverse overrides the inverse of the field for the blueprints, to compute
+// it as a^(q-2) in constant time if requested.
+func (s *solver) Inverse(a constraint.Element) (constraint.Element, bool) {
+	if !s.constantTime {
+		return s.system.Inverse(a)
+	}
+	e := (*fr.Element)(a[:])
+	e.Exp(*e, s.qMinusTwo)
+	return a, !e.IsZero()
+}
+
 // Read interprets input calldata as either a LinearExpression (if R1CS) or a Term (if Plonkish),
 // evaluates it and return the result and the number of uint32 word read.
 func (s *solver) Read(calldata []uint32) (constraint.Element, int) {
@@ -389,7 +543,7 @@ func (s *solver) Read(calldata []uint32) (constraint.Element, int) {
 // an instruction can encode a hint, a custom constraint or a generic constraint.
 func (solver *solver) processInstruction(pi constraint.PackedInstruction, scratch *scratch) error {
 	// fetch the blueprint
-	blueprint := solver.Blueprints[pi.BlueprintID]
+	blueprint := solver.blueprints[pi.BlueprintID]
 	inst := pi.Unpack(&solver.System)
 	cID := inst.ConstraintOffset // here we have 1 constraint in the instruction only
 
@@ -424,6 +578,10 @@ func (solver *solver) processInstruction(pi constraint.PackedInstruction, scratc
 // run runs the solver. it return an error if a constraint is not satisfied or if not all wires
 // were instantiated.
 func (solver *solver) run() error {
+	if solver.hook != nil || solver.deterministic || solver.collectAll {
+		return solver.runSequential()
+	}
+
 	// minWorkPerCPU is the minimum target number of constraint a task should hold
 	// in other words, if a level has less than minWorkPerCPU, it will not be parallelized and executed
 	// sequentially without sync.
@@ -447,6 +605,11 @@ func (solver *solver) run() error {
 		go func() {
 			var scratch scratch
 			for t := range chTasks {
+				if err := solver.ctxErr(); err != nil {
+					chError <- err
+					wg.Done()
+					return
+				}
 				for _, i := range t {
 					if err := solver.processInstruction(solver.Instructions[i], &scratch); err != nil {
 						chError <- err
@@ -468,7 +631,13 @@ func (solver *solver) run() error {
 	var scratch scratch
 
 	// for each level, we push the tasks
-	for _, level := range solver.Levels {
+	for _, level := range solver.levels {
+		if err := solver.ctxErr(); err != nil {
+			return err
+		}
+		if err := solver.prefetchHints(level); err != nil {
+			return err
+		}
 
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
@@ -480,6 +649,7 @@ func (solver *solver) run() error {
 					return err
 				}
 			}
+			solver.reportProgress()
 			continue
 		}
 
@@ -522,15 +692,200 @@ func (solver *solver) run() error {
 		if len(chError) > 0 {
 			return <-chError
 		}
+		solver.reportProgress()
+	}
+
+	return solver.checkDone()
+}
+
+// runSequential runs the solver sequentially, calling the instruction hook, if
+// set, after each instruction, and collecting the unsatisfied constraints if
+// requested. The instructions are processed by level, or in
+// order by chunks if the order is deterministic.
+//
+// When collecting, an unsatisfied constraint may leave the wire it should
+// solve unsolved, for example a division by zero, so that the instructions
+// depending on it, directly or not, are skipped.
+func (solver *solver) runSequential() error {
+	levels := solver.levels
+	if solver.deterministic {
+		levels = inOrder(levels)
+	}
+	var g constraint.DependencyGraph
+	var done []bool
+	if solver.collectAll {
+		g = solver.DependencyGraph()
+		done = make([]bool, len(solver.Instructions))
+	}
+
+	var scratch scratch
+	for _, level := range levels {
+		if err := solver.ctxErr(); err != nil {
+			return err
+		}
+		// the chunks of the deterministic order are not levels: their
+		// hints are computed one by one
+		if !solver.deterministic {
+			if err := solver.prefetchHints(level); err != nil {
+				return err
+			}
+		}
+		for _, i := range level {
+			if done != nil && !dependenciesDone(&g.Nodes[i], done) {
+				continue
+			}
+			err := solver.processInstruction(solver.Instructions[i], &scratch)
+			if solver.hook != nil {
+				if hErr := solver.hook(int(i), solver, err); hErr != nil {
+					return hErr
+				}
+			}
+			if done != nil && err == nil {
+				done[i] = true
+			}
+			if err = solver.collect(err); err != nil {
+				return err
+			}
+		}
+		solver.reportProgress()
+	}
+
+	return solver.checkDone()
+}
+
+// inOrderChunkSize is the number of instructions processed between two
+// progress reports when the order is deterministic.
+const inOrderChunkSize = 1 << 12
+
+// inOrder returns the instructions of the levels in increasing order, split in
+// chunks of inOrderChunkSize instructions. Instructions only depend on
+// instructions added before them, so that this order is a valid solving
+// order.
+func inOrder(levels [][]uint32) [][]uint32 {
+	var all []uint32
+	for _, level := range levels {
+		all = append(all, level...)
+	}
+	slices.Sort(all)
+	res := make([][]uint32, 0, len(all)/inOrderChunkSize+1)
+	for len(all) > inOrderChunkSize {
+		res = append(res, all[:inOrderChunkSize])
+		all = all[inOrderChunkSize:]
+	}
+	return append(res, all)
+}
+
+// runPartial solves sequentially the instructions whose inputs are solved and
+// whose dependencies are solved, and returns the instructions solved. The
+// instructions of the stateful blueprints are not solved, as their state is
+// reset when the solving is finished.
+func (solver *solver) runPartial(g *constraint.DependencyGraph) ([]bool, error) {
+	done := make([]bool, len(solver.Instructions))
+	ready := func(i uint32) bool {
+		if _, ok := solver.blueprints[solver.Instructions[i].BlueprintID].(constraint.BlueprintStateful); ok {
+			return false
+		}
+		for _, w := range g.Nodes[i].Inputs {
+			if !solver.solved[w] {
+				return false
+			}
+		}
+		return dependenciesDone(&g.Nodes[i], done)
 	}
 
+	var scratch scratch
+	for _, level := range solver.levels {
+		if err := solver.ctxErr(); err != nil {
+			return nil, err
+		}
+		for _, i := range level {
+			if !ready(i) {
+				continue
+			}
+			// an unsatisfied constraint which is collected is not done, so
+			// that the instructions depending on it are not solved
+			err := solver.processInstruction(solver.Instructions[i], &scratch)
+			if cErr := solver.collect(err); cErr != nil {
+				return nil, cErr
+			}
+			done[i] = err == nil
+		}
+		solver.reportProgress()
+	}
+	if err := solver.unsatisfiedErr(); err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// collect records err if it is an unsatisfied constraint and the errors are
+// collected, and returns nil then. Otherwise it returns err, which stops the
+// solving. The errors are only collected when solving sequentially, as the
+// wires of the unsatisfied constraints may be solved by later instructions.
+func (solver *solver) collect(err error) error {
+	uErr, ok := err.(*UnsatisfiedConstraintError)
+	if !ok || !solver.collectAll {
+		return err
+	}
+	solver.unsatisfied = append(solver.unsatisfied, uErr)
+	return nil
+}
+
+// unsatisfiedErr returns the collected unsatisfied constraints, if any, in
+// increasing order of constraint ID.
+func (solver *solver) unsatisfiedErr() error {
+	if len(solver.unsatisfied) == 0 {
+		return nil
+	}
+	slices.SortFunc(solver.unsatisfied, func(a, b *UnsatisfiedConstraintError) int {
+		return a.CID - b.CID
+	})
+	return &UnsatisfiedConstraintsError{Errors: solver.unsatisfied}
+}
+
+// checkDone returns the error of the solving once all the instructions are
+// processed. The wires solved by the unsatisfied constraints may be missing
+// then, which is not reported.
+func (solver *solver) checkDone() error {
+	if err := solver.unsatisfiedErr(); err != nil {
+		return err
+	}
 	if int(solver.nbSolved) != len(solver.values) {
 		return errors.New("solver didn't assign a value to all wires")
 	}
-
 	return nil
 }
 
+// reportProgress calls the progress callback, if set, with the number of
+// solved wires.
+func (solver *solver) reportProgress() {
+	if solver.progress != nil {
+		solver.progress(atomic.LoadUint64(&solver.nbSolved), uint64(len(solver.values)))
+	}
+}
+
+// ctxErr returns the error of the context of the solver, if set.
+func (solver *solver) ctxErr() error {
+	if solver.ctx == nil {
+		return nil
+	}
+	return solver.ctx.Err()
+}
+
+// Value implements csolver.State; it returns the value of the wire and true
+// if the wire is solved.
+func (solver *solver) Value(wireID int) (*big.Int, bool) {
+	if wireID < 0 || wireID >= len(solver.values) || !solver.solved[wireID] {
+		return nil, false
+	}
+	return solver.values[wireID].BigInt(new(big.Int)), true
+}
+
+// NbWires implements csolver.State; it returns the number of wires.
+func (solver *solver) NbWires() int {
+	return len(solver.values)
+}
+
 // solveR1C compute unsolved wires in the constraint, if any and set the solver accordingly
 //
 // returns an error if the solver called a hint function that errored
@@ -587,7 +942,11 @@ func (solver *solver) solveR1C(cID uint32, r *constraint.R1C) error {
 
 	switch loc {
 	case 1:
-		if !b.IsZero() {
+		if solver.constantTime {
+			if !solver.divConstantTime(&wire, a, b, c) {
+				return solver.wrapErrWithDebugInfo(cID, fmt.Errorf("%s ⋅ %s != %s", a.String(), b.String(), c.String()))
+			}
+		} else if !b.IsZero() {
 			wire.Div(c, b).
 				Sub(&wire, a)
 			a.Add(a, &wire)
@@ -599,7 +958,11 @@ func (solver *solver) solveR1C(cID uint32, r *constraint.R1C) error {
 			}
 		}
 	case 2:
-		if !a.IsZero() {
+		if solver.constantTime {
+			if !solver.divConstantTime(&wire, b, a, c) {
+				return solver.wrapErrWithDebugInfo(cID, fmt.Errorf("%s ⋅ %s != %s", a.String(), b.String(), c.String()))
+			}
+		} else if !a.IsZero() {
 			wire.Div(c, a).
 				Sub(&wire, b)
 			b.Add(b, &wire)
@@ -625,18 +988,82 @@ func (solver *solver) solveR1C(cID uint32, r *constraint.R1C) error {
 	return nil
 }
 
+// divConstantTime solves acc⋅den == c for the unsolved term of acc, with the
+// same field operations whether den is zero or not: with 0⁻¹ = 0, the term is
+// wire = (c⋅den⁻¹ - acc)⋅den⋅den⁻¹. It returns false if the constraint is not
+// satisfied, that is if den is zero and c is not.
+func (solver *solver) divConstantTime(wire, acc, den, c *fr.Element) bool {
+	var inv, isNonZero, check fr.Element
+	inv.Exp(*den, solver.qMinusTwo)
+	isNonZero.Mul(den, &inv)
+	wire.Mul(c, &inv).
+		Sub(wire, acc).
+		Mul(wire, &isNonZero)
+	acc.Add(acc, wire)
+	return check.Mul(acc, den).Equal(c)
+}
+
 // UnsatisfiedConstraintError wraps an error with useful metadata on the unsatisfied constraint
 type UnsatisfiedConstraintError struct {
 	Err       error
 	CID       int     // constraint ID
 	DebugInfo *string // optional debug info
+
+	// the blame is computed on first use, see Blame
+	blameCtx  *blameContext
+	blameOnce sync.Once
+	blame     *constraint.Blame
+}
+
+// Blame returns the inputs and gadgets the constraint depends on. It is
+// computed on the first call, from the dependencies of the system which are
+// built at most once per solve.
+func (r *UnsatisfiedConstraintError) Blame() *constraint.Blame {
+	if r.blameCtx == nil {
+		return nil
+	}
+	r.blameOnce.Do(func() {
+		r.blame = r.blameCtx.blame(r.CID)
+	})
+	return r.blame
 }
 
 func (r *UnsatisfiedConstraintError) Error() string {
+	var msg string
 	if r.DebugInfo != nil {
-		return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
+		msg = fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
+	} else {
+		msg = fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
+	}
+	if blame := r.Blame(); blame != nil {
+		msg = strings.TrimRight(msg, "\n") + "\n" + blame.String()
+	}
+	return msg
+}
+
+// UnsatisfiedConstraintsError is returned by the solver when the errors are
+// collected (see csolver.WithCollectAllErrors) and constraints are not
+// satisfied.
+type UnsatisfiedConstraintsError struct {
+	Errors []*UnsatisfiedConstraintError // in increasing order of constraint ID
+}
+
+func (r *UnsatisfiedConstraintsError) Error() string {
+	msgs := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		msgs[i] = strings.TrimRight(err.Error(), "\n")
+	}
+	return fmt.Sprintf("%d constraints are not satisfied:\n%s", len(r.Errors), strings.Join(msgs, "\n"))
+}
+
+// Unwrap returns the errors of the unsatisfied constraints, so that errors.As
+// finds the first one.
+func (r *UnsatisfiedConstraintsError) Unwrap() []error {
+	res := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		res[i] = err
 	}
-	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
+	return res
 }
 
 func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedConstraintError {
@@ -645,7 +1072,36 @@ func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedCo
 		debugInfo = new(string)
 		*debugInfo = solver.logValue(solver.DebugInfo[dID])
 	}
-	return &UnsatisfiedConstraintError{CID: int(cID), Err: err, DebugInfo: debugInfo}
+	solver.blameOnce.Do(func() {
+		// the inputs are solved from the witness, and the values may be
+		// reused by the next solve before the blame is computed
+		nbInputs := len(solver.Public) + len(solver.Secret)
+		solver.blame = &blameContext{
+			system: &solver.System,
+			inputs: slices.Clone(solver.values[:nbInputs]),
+		}
+	})
+	return &UnsatisfiedConstraintError{CID: int(cID), Err: err, DebugInfo: debugInfo, blameCtx: solver.blame}
+}
+
+// blameContext computes the blame of the unsatisfied constraints of a solve.
+type blameContext struct {
+	system *constraint.System
+	inputs []fr.Element // values of the inputs of the solve
+
+	once sync.Once
+	deps *constraint.Dependencies // replayed on the first blame
+}
+
+func (b *blameContext) blame(cID int) *constraint.Blame {
+	b.once.Do(func() {
+		b.deps = b.system.Dependencies()
+	})
+	blame := b.deps.Blame(cID)
+	for i := range blame.Inputs {
+		blame.Inputs[i].Value = b.inputs[blame.Inputs[i].Wire].String()
+	}
+	return &blame
 }
 
 // temporary variables to avoid memallocs in hotloop
@@ -37,6 +37,11 @@ type Solver interface {
 	// Read interprets input calldata as a LinearExpression,
 	// evaluates it and return the result and the number of uint32 word read.
 	Read(calldata []uint32) (Element, int)
+
+	// ConstantTime returns true if the blueprints must not branch on the
+	// values of the wires nor index tables by them, see
+	// solver.WithConstantTime.
+	ConstantTime() bool
 }
 
 // BlueprintSolvable represents a blueprint that knows how to solve itself.
@@ -73,6 +78,34 @@ type BlueprintStateful interface {
 
 	// Reset is called by the solver between invocation of Solve.
 	Reset()
+
+	// Clone returns a copy of the blueprint which doesn't share its solving
+	// state, so that the system can be solved concurrently.
+	Clone() BlueprintStateful
+}
+
+// SolvingBlueprints returns the blueprints used by a solve of the system: the
+// stateful blueprints are replaced by clones in their initial state, the
+// other blueprints are shared.
+func SolvingBlueprints(blueprints []Blueprint) []Blueprint {
+	var res []Blueprint
+	for i := range blueprints {
+		b, ok := blueprints[i].(BlueprintStateful)
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = make([]Blueprint, len(blueprints))
+			copy(res, blueprints)
+		}
+		c := b.Clone()
+		c.Reset()
+		res[i] = c
+	}
+	if res == nil {
+		return blueprints
+	}
+	return res
 }
 
 // Compressible represent an object that knows how to encode itself as a []uint32.
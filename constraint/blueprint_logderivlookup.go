@@ -66,6 +66,10 @@ func (b *BlueprintLookupHint) Solve(s Solver, inst Instruction) error {
 			return fmt.Errorf("lookup query too large")
 		}
 		// we set the output wire to the value of the entry
+		if s.ConstantTime() {
+			s.SetValue(uint32(i+int(inst.WireOffset)), ctSelectElement(entries, idx))
+			continue
+		}
 		s.SetValue(uint32(i+int(inst.WireOffset)), entries[idx])
 	}
 	return nil
@@ -86,6 +90,15 @@ func (b *BlueprintLookupHint) Reset() {
 	b.cachedOffset = 0
 }
 
+func (b *BlueprintLookupHint) Clone() BlueprintStateful {
+	return &BlueprintLookupHint{
+		EntriesCalldata:  b.EntriesCalldata,
+		maxLevel:         b.maxLevel,
+		maxLevelPosition: b.maxLevelPosition,
+		maxLevelOffset:   b.maxLevelOffset,
+	}
+}
+
 func (b *BlueprintLookupHint) CalldataSize() int {
 	// variable size
 	return -1
@@ -0,0 +1,157 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of the accesses solved by [BlueprintMemory].
+const (
+	MemoryRead uint32 = iota
+	MemoryWrite
+	MemoryFinal
+)
+
+// BlueprintMemory is a blueprint which solves the accesses to a random-access
+// memory. The memory is replayed by the solver, each access returning the
+// value of the accessed cell before the access and the time of the previous
+// access to the cell.
+//
+// The calldata of an access is:
+//
+//	[len, accessIndex, kind, size, init..., addr, value, previous]
+//
+// where init are the initial values of the memory cells, only given with the
+// first access, addr is the accessed address (for reads and writes), value is
+// the written value (for writes) and previous is an output of the previous
+// access, which orders the accesses in the instruction tree. The last access
+// returns the final value and time of all the cells.
+type BlueprintMemory struct {
+	// state of the memory while solving
+	values   []Element
+	times    []uint64
+	nbSolved uint32
+}
+
+// ensures BlueprintMemory implements the BlueprintStateful interface
+var _ BlueprintStateful = (*BlueprintMemory)(nil)
+
+// MemoryAccess returns the calldata of the access of index accessIndex to the
+// memory of the given size. init is only used for the first access.
+func (b *BlueprintMemory) MemoryAccess(accessIndex int, kind uint32, size int, init []Compressible, inputs ...Compressible) []uint32 {
+	calldata := []uint32{0, uint32(accessIndex), kind, uint32(size)}
+	if accessIndex == 0 {
+		for _, v := range init {
+			v.Compress(&calldata)
+		}
+	}
+	for _, v := range inputs {
+		v.Compress(&calldata)
+	}
+	calldata[0] = uint32(len(calldata))
+	return calldata
+}
+
+func (b *BlueprintMemory) Solve(s Solver, inst Instruction) error {
+	accessIndex, kind, size := inst.Calldata[1], inst.Calldata[2], int(inst.Calldata[3])
+	if accessIndex != b.nbSolved {
+		return fmt.Errorf("memory access %d solved after %d accesses", accessIndex, b.nbSolved)
+	}
+	b.nbSolved++
+
+	offset, delta := 4, 0
+	if accessIndex == 0 {
+		b.values = make([]Element, size)
+		b.times = make([]uint64, size)
+		for i := range b.values {
+			b.values[i], delta = s.Read(inst.Calldata[offset:])
+			offset += delta
+		}
+	}
+
+	if kind == MemoryFinal {
+		for i := range b.values {
+			s.SetValue(inst.WireOffset+uint32(i), b.values[i])
+			s.SetValue(inst.WireOffset+uint32(size+i), s.FromInterface(b.times[i]))
+		}
+		return nil
+	}
+
+	addrE, delta := s.Read(inst.Calldata[offset:])
+	offset += delta
+	addr, ok := s.Uint64(addrE)
+	if !ok || addr >= uint64(size) {
+		return errors.New("memory address out of range")
+	}
+	if s.ConstantTime() {
+		// the address may be secret, all the cells are accessed
+		s.SetValue(inst.WireOffset, ctSelectElement(b.values, addr))
+		s.SetValue(inst.WireOffset+1, s.FromInterface(ctSelectUint64(b.times, addr)))
+		if kind == MemoryWrite {
+			v, _ := s.Read(inst.Calldata[offset:])
+			ctSetElement(b.values, addr, v)
+		}
+		ctSetUint64(b.times, addr, uint64(accessIndex)+1)
+		return nil
+	}
+	s.SetValue(inst.WireOffset, b.values[addr])
+	s.SetValue(inst.WireOffset+1, s.FromInterface(b.times[addr]))
+	if kind == MemoryWrite {
+		b.values[addr], _ = s.Read(inst.Calldata[offset:])
+	}
+	// the time of the access is its index, starting from 1 as 0 is the
+	// time of the initial values.
+	b.times[addr] = uint64(accessIndex) + 1
+	return nil
+}
+
+func (b *BlueprintMemory) Reset() {
+	b.values = nil
+	b.times = nil
+	b.nbSolved = 0
+}
+
+func (b *BlueprintMemory) Clone() BlueprintStateful {
+	return &BlueprintMemory{}
+}
+
+func (b *BlueprintMemory) CalldataSize() int {
+	// variable size
+	return -1
+}
+
+func (b *BlueprintMemory) NbConstraints() int {
+	return 0
+}
+
+// NbOutputs return the number of output wires this blueprint creates.
+func (b *BlueprintMemory) NbOutputs(inst Instruction) int {
+	if inst.Calldata[2] == MemoryFinal {
+		return 2 * int(inst.Calldata[3])
+	}
+	return 2
+}
+
+func (b *BlueprintMemory) UpdateInstructionTree(inst Instruction, tree InstructionTree) Level {
+	// the access depends on all the linear expressions of the calldata
+	maxLevel := LevelUnset
+	for j := 4; j < len(inst.Calldata); {
+		n := int(inst.Calldata[j])
+		j++
+		for k := 0; k < n; k++ {
+			wireID := inst.Calldata[j+1]
+			j += 2
+			if !tree.HasWire(wireID) {
+				continue
+			}
+			if level := tree.GetWireLevel(wireID); level > maxLevel {
+				maxLevel = level
+			}
+		}
+	}
+	maxLevel++
+	for i := 0; i < b.NbOutputs(inst); i++ {
+		tree.InsertWire(uint32(i+int(inst.WireOffset)), maxLevel)
+	}
+	return maxLevel
+}
@@ -0,0 +1,74 @@
+package constraint
+
+// BlueprintPrefixSum is a blueprint which solves the prefix sums of a list of
+// linear expressions in a single instruction, the i-th output being the sum
+// of the i+1 first inputs. It doesn't add constraints: the outputs must be
+// constrained by the caller.
+//
+// The calldata is:
+//
+//	[len, n, inputs...]
+type BlueprintPrefixSum struct{}
+
+// ensures BlueprintPrefixSum implements the BlueprintSolvable interface
+var _ BlueprintSolvable = (*BlueprintPrefixSum)(nil)
+
+// PrefixSum returns the calldata of the prefix sums of the inputs.
+func (b *BlueprintPrefixSum) PrefixSum(inputs ...Compressible) []uint32 {
+	calldata := []uint32{0, uint32(len(inputs))}
+	for _, v := range inputs {
+		v.Compress(&calldata)
+	}
+	calldata[0] = uint32(len(calldata))
+	return calldata
+}
+
+func (b *BlueprintPrefixSum) Solve(s Solver, inst Instruction) error {
+	n := int(inst.Calldata[1])
+	var sum Element
+	offset := 2
+	for i := 0; i < n; i++ {
+		v, delta := s.Read(inst.Calldata[offset:])
+		offset += delta
+		sum = s.Add(sum, v)
+		s.SetValue(inst.WireOffset+uint32(i), sum)
+	}
+	return nil
+}
+
+func (b *BlueprintPrefixSum) CalldataSize() int {
+	// variable size
+	return -1
+}
+
+func (b *BlueprintPrefixSum) NbConstraints() int {
+	return 0
+}
+
+// NbOutputs return the number of output wires this blueprint creates.
+func (b *BlueprintPrefixSum) NbOutputs(inst Instruction) int {
+	return int(inst.Calldata[1])
+}
+
+func (b *BlueprintPrefixSum) UpdateInstructionTree(inst Instruction, tree InstructionTree) Level {
+	maxLevel := LevelUnset
+	for j := 2; j < len(inst.Calldata); {
+		n := int(inst.Calldata[j])
+		j++
+		for k := 0; k < n; k++ {
+			wireID := inst.Calldata[j+1]
+			j += 2
+			if !tree.HasWire(wireID) {
+				continue
+			}
+			if level := tree.GetWireLevel(wireID); level > maxLevel {
+				maxLevel = level
+			}
+		}
+	}
+	maxLevel++
+	for i := 0; i < b.NbOutputs(inst); i++ {
+		tree.InsertWire(uint32(i+int(inst.WireOffset)), maxLevel)
+	}
+	return maxLevel
+}
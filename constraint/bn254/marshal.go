@@ -99,3 +99,26 @@ func (cs *system) ReadFrom(r io.Reader) (int64, error) {
 
 	return int64(totalLen) + 4*8, nil
 }
+
+// WriteStreamTo encodes the constraint system into w without materializing the
+// encoding in memory, for systems too large to be serialized with WriteTo.
+// The result is read with ReadStreamFrom.
+func (cs *system) WriteStreamTo(w io.Writer) (int64, error) {
+	n, err := cs.System.WriteStreamTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := cs.CoeffTable.writeStreamTo(w)
+	return n + m, err
+}
+
+// ReadStreamFrom decodes the constraint system written by WriteStreamTo from r,
+// reading it by chunks. r is not read past the end of the constraint system.
+func (cs *system) ReadStreamFrom(r io.Reader) (int64, error) {
+	n, err := cs.System.ReadStreamFrom(r)
+	if err != nil {
+		return n, err
+	}
+	m, err := cs.CoeffTable.readStreamFrom(r)
+	return n + m, err
+}
@@ -18,6 +18,7 @@ package cs_test
 
 import (
 	"bytes"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/frontend/cs/scs"
@@ -72,6 +73,9 @@ func TestSerialization(t *testing.T) {
 				if written != read {
 					t.Fatal("didn't read same number of bytes we wrote")
 				}
+				if err := constraint.Validate(&reconstructed); err != nil {
+					t.Fatal(err)
+				}
 
 				// compare original and reconstructed
 				if diff := cmp.Diff(r1cs1, &reconstructed,
@@ -81,12 +85,43 @@ func TestSerialization(t *testing.T) {
 						"CoeffTable.mCoeffs",
 						"System.lbWireLevel",
 						"System.genericHint",
+						"System.skipSolverData",
 						"System.SymbolTable",
 						"System.bitLen")); diff != "" {
 					t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
 				}
 			}
 
+			// streamed round trip; the reader must stop at the end of the system
+			{
+				buffer.Reset()
+				written, err := r1cs1.(*cs.R1CS).WriteStreamTo(&buffer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				buffer.WriteByte(0xff)
+				var reconstructed cs.R1CS
+				read, err := reconstructed.ReadStreamFrom(&buffer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if written != read || buffer.Len() != 1 {
+					t.Fatal("didn't read same number of bytes we wrote")
+				}
+				if diff := cmp.Diff(r1cs1, &reconstructed,
+					cmpopts.IgnoreFields(cs.R1CS{},
+						"System.q",
+						"field",
+						"CoeffTable.mCoeffs",
+						"System.lbWireLevel",
+						"System.genericHint",
+						"System.skipSolverData",
+						"System.SymbolTable",
+						"System.bitLen")); diff != "" {
+					t.Fatalf("streamed round trip mismatch (-want +got):\n%s", diff)
+				}
+			}
+
 			// ensure determinism in compilation / serialization / reconstruction
 			{
 				buffer.Reset()
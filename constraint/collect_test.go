@@ -0,0 +1,111 @@
+package constraint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type collectCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *collectCircuit) Define(api frontend.API) error {
+	m := api.(frontend.MessageAsserter)
+	m.AssertIsEqualWithMessage(api.Mul(c.X, c.Y), 6, "product")
+	m.AssertIsEqualWithMessage(c.X, 2, "x")
+	m.AssertIsEqualWithMessage(api.Add(c.X, c.Y), 5, "sum")
+	m.AssertIsDifferentWithMessage(c.Y, 4, "y")
+	return nil
+}
+
+func TestCollectAllErrors(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &collectCircuit{})
+		assert.NoError(err)
+
+		solve := func(x, y int, opts ...solver.Option) error {
+			w, err := frontend.NewWitness(&collectCircuit{X: x, Y: y}, field)
+			assert.NoError(err)
+			_, err = ccs.Solve(w, opts...)
+			return err
+		}
+		assert.NoError(solve(2, 3, solver.WithCollectAllErrors()))
+
+		// the product, x and the sum fail, y holds
+		err = solve(1, 3, solver.WithCollectAllErrors())
+		var all *cs_bn254.UnsatisfiedConstraintsError
+		assert.ErrorAs(err, &all)
+		assert.Len(all.Errors, 3)
+		for i, msg := range []string{"product", "x", "sum"} {
+			assert.NotNil(all.Errors[i].DebugInfo)
+			assert.Contains(*all.Errors[i].DebugInfo, msg)
+			if i > 0 {
+				assert.Less(all.Errors[i-1].CID, all.Errors[i].CID)
+			}
+		}
+
+		// the blames share the dependencies of the solve
+		for _, e := range all.Errors {
+			blame := e.Blame()
+			assert.NotNil(blame)
+			assert.Equal(len(ccs.(interface{ Blame(int) constraint.Blame }).Blame(e.CID).Inputs), len(blame.Inputs))
+			assert.Contains(e.Error(), blame.String())
+		}
+
+		// errors.As finds the first one
+		var first *cs_bn254.UnsatisfiedConstraintError
+		assert.ErrorAs(err, &first)
+		assert.Equal(all.Errors[0].CID, first.CID)
+
+		// without the option, the solving stops at the first one
+		err = solve(1, 3)
+		assert.ErrorAs(err, &first)
+		assert.False(errors.As(err, &all))
+	}
+}
+
+type collectDivCircuit struct {
+	X, Y, Z frontend.Variable
+}
+
+func (c *collectDivCircuit) Define(api frontend.API) error {
+	d := api.Div(c.X, c.Y)
+	api.AssertIsEqual(api.Mul(d, d), c.Z)
+	api.AssertIsEqual(c.X, c.Z)
+	return nil
+}
+
+func TestCollectAllErrorsUnsolvedWire(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &collectDivCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&collectDivCircuit{X: 1, Y: 0, Z: 2}, field)
+		assert.NoError(err)
+
+		// the division can't be solved: the constraints reading the quotient
+		// are skipped, the last one is still checked
+		for _, opts := range [][]solver.Option{{solver.WithCollectAllErrors()}, {solver.WithCollectAllErrors(), solver.WithDeterministicOrder()}} {
+			_, err = ccs.Solve(w, opts...)
+			var all *cs_bn254.UnsatisfiedConstraintsError
+			assert.ErrorAs(err, &all)
+			// the division is one constraint in R1CS and two in PLONK
+			assert.GreaterOrEqual(len(all.Errors), 2)
+			assert.Contains(all.Errors[len(all.Errors)-1].Error(), "Z=2")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package constraint
+
+// The functions below access a table at a secret index by scanning all its
+// entries, so that the accessed memory doesn't depend on the index.
+
+// ctMask returns all ones if a == b and 0 otherwise, without branching.
+func ctMask(a, b uint64) uint64 {
+	x := a ^ b
+	// the top bit of x | -x is set iff x != 0
+	return ((x | -x) >> 63) - 1
+}
+
+// ctSelectElement returns table[i].
+func ctSelectElement(table []Element, i uint64) Element {
+	var res Element
+	for j := range table {
+		m := ctMask(uint64(j), i)
+		for k := range res {
+			res[k] |= table[j][k] & m
+		}
+	}
+	return res
+}
+
+// ctSelectUint64 returns table[i].
+func ctSelectUint64(table []uint64, i uint64) uint64 {
+	var res uint64
+	for j := range table {
+		res |= table[j] & ctMask(uint64(j), i)
+	}
+	return res
+}
+
+// ctSetElement sets table[i] = v.
+func ctSetElement(table []Element, i uint64, v Element) {
+	for j := range table {
+		m := ctMask(uint64(j), i)
+		for k := range v {
+			table[j][k] ^= (table[j][k] ^ v[k]) & m
+		}
+	}
+}
+
+// ctSetUint64 sets table[i] = v.
+func ctSetUint64(table []uint64, i uint64, v uint64) {
+	for j := range table {
+		table[j] ^= (table[j] ^ v) & ctMask(uint64(j), i)
+	}
+}
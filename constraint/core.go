@@ -127,6 +127,9 @@ type System struct {
 	GkrInfo        GkrInfo
 
 	genericHint BlueprintID
+
+	// skipSolverData is set by SkipSolverData
+	skipSolverData bool `cbor:"-"`
 }
 
 // NewSystem initialize the common structure among constraint system
@@ -156,6 +159,42 @@ func (system *System) GetNbInstructions() int {
 	return len(system.Instructions)
 }
 
+// GetNbHints returns the number of instructions in the system encoding a hint call
+func (system *System) GetNbHints() int {
+	nbHints := 0
+	for _, inst := range system.Instructions {
+		if _, ok := system.Blueprints[inst.BlueprintID].(BlueprintHint); ok {
+			nbHints++
+		}
+	}
+	return nbHints
+}
+
+// StackFrame is a frame of a call stack recorded at compile time.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int64
+}
+
+// GetCallStack returns the call stack recorded in the debug information
+// attached to the given constraint, innermost frame first. It returns nil if
+// the constraint has no debug information.
+func (system *System) GetCallStack(constraintID int) []StackFrame {
+	dID, ok := system.MDebug[constraintID]
+	if !ok {
+		return nil
+	}
+	stack := system.DebugInfo[dID].Stack
+	frames := make([]StackFrame, len(stack))
+	for i, lID := range stack {
+		location := system.SymbolTable.Locations[lID]
+		function := system.SymbolTable.Functions[location.FunctionID]
+		frames[i] = StackFrame{Function: function.Name, File: function.Filename, Line: location.Line}
+	}
+	return frames
+}
+
 // GetInstruction returns the instruction at index id
 func (system *System) GetInstruction(id int) Instruction {
 	return system.Instructions[id].Unpack(system)
@@ -177,6 +216,10 @@ func (system *System) GetNbInternalVariables() int {
 	return system.NbInternalVariables
 }
 
+func (system *System) SetGnarkVersion(v semver.Version) {
+	system.GnarkVersion = v.String()
+}
+
 // CheckSerializationHeader parses the scalar field and gnark version headers
 //
 // This is meant to be use at the deserialization step, and will error for illegal values
@@ -224,6 +267,11 @@ func (system *System) FieldBitLen() int {
 	return system.bitLen
 }
 
+// SkipSolverData implements ConstraintSystem
+func (system *System) SkipSolverData() {
+	system.skipSolverData = true
+}
+
 func (system *System) AddInternalVariable() (idx int) {
 	idx = system.NbInternalVariables + system.GetNbPublicVariables() + system.GetNbSecretVariables()
 	system.NbInternalVariables++
@@ -316,10 +364,16 @@ func (system *System) AddCommitment(c Commitment) error {
 }
 
 func (system *System) AddLog(l LogEntry) {
+	if system.skipSolverData {
+		return
+	}
 	system.Logs = append(system.Logs, l)
 }
 
 func (system *System) AttachDebugInfo(debugInfo DebugInfo, constraintID []int) {
+	if system.skipSolverData {
+		return
+	}
 	system.DebugInfo = append(system.DebugInfo, LogEntry(debugInfo))
 	id := len(system.DebugInfo) - 1
 	for _, cID := range constraintID {
@@ -381,6 +435,8 @@ func (cs *System) AddSparseR1C(c SparseR1C, bID BlueprintID) int {
 }
 
 func (cs *System) AddInstruction(bID BlueprintID, calldata []uint32) []uint32 {
+	profile.RecordInstruction()
+
 	// set the offsets
 	pi := PackedInstruction{
 		StartCallData:    uint64(len(cs.CallData)),
@@ -406,6 +462,9 @@ func (cs *System) AddInstruction(bID BlueprintID, calldata []uint32) []uint32 {
 
 	// add the instruction
 	cs.Instructions = append(cs.Instructions, pi)
+	if cs.skipSolverData {
+		return wires
+	}
 
 	// update the instruction dependency tree
 	level := blueprint.UpdateInstructionTree(inst, cs)
@@ -1,6 +1,7 @@
 package constraint
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/consensys/gnark/internal/utils"
@@ -10,6 +11,9 @@ type DebugInfo LogEntry
 
 func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
 	var l LogEntry
+	if system.skipSolverData {
+		return DebugInfo(l)
+	}
 
 	const minLogSize = 500
 	var sbb strings.Builder
@@ -40,3 +44,60 @@ func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
 
 	return DebugInfo(l)
 }
+
+// NewMessageDebugInfo returns the debug info of an assertion with a
+// user-defined message, formatted as with fmt.Sprintf(format, args...). The
+// arguments of type LinearExpression or Term are evaluated by the solver and
+// formatted as strings in place of the verbs which consume them.
+func (system *System) NewMessageDebugInfo(format string, args ...interface{}) DebugInfo {
+	var l LogEntry
+	if system.skipSolverData {
+		return DebugInfo(l)
+	}
+	var sbb strings.Builder
+	sbb.Grow(len(format))
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sbb.WriteByte(format[i])
+			continue
+		}
+		// the verb extends to the first letter, after the flags, the width and
+		// the precision.
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.*", format[j]) != -1 {
+			j++
+		}
+		switch {
+		case j == len(format):
+			sbb.WriteString(strings.ReplaceAll(format[i:], "%", "%%"))
+			i = j
+			continue
+		case format[j] == '%':
+			sbb.WriteString("%%")
+			i = j
+			continue
+		case len(args) == 0:
+			sbb.WriteString(strings.ReplaceAll(fmt.Sprintf(format[i:j+1]), "%", "%%"))
+			i = j
+			continue
+		}
+		switch v := args[0].(type) {
+		case LinearExpression:
+			l.WriteVariable(v, &sbb)
+		case Term:
+			l.WriteVariable(LinearExpression{v}, &sbb)
+		default:
+			sbb.WriteString(strings.ReplaceAll(fmt.Sprintf(format[i:j+1], v), "%", "%%"))
+		}
+		args = args[1:]
+		i = j
+	}
+	sbb.WriteByte('\n')
+	sbb.WriteString("%s\n") // some space for the stack.
+	l.Format = sbb.String()
+
+	l.Stack = system.SymbolTable.CollectStack()
+
+	return DebugInfo(l)
+}
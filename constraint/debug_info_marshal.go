@@ -0,0 +1,139 @@
+package constraint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/debug"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// debugInfoVersion is the version of the format written by
+// System.WriteDebugInfo.
+const debugInfoVersion = 1
+
+// ErrDebugInfoMismatch is returned by System.ReadDebugInfo when the debug
+// information was written for another constraint system.
+var ErrDebugInfoMismatch = errors.New("debug information of another constraint system")
+
+// debugInfoFile is the content of the file written by System.WriteDebugInfo.
+type debugInfoFile struct {
+	Version     int
+	Fingerprint []byte
+	DebugInfo   []LogEntry
+	SymbolTable debug.SymbolTable
+	MDebug      map[int]int
+}
+
+// WriteDebugInfo writes the debug information of the constraint system, that
+// is the call stacks of the failing constraints and the symbol table, to w. The
+// debug information can then be removed with StripDebugInfo, so that the
+// serialized constraint system is smaller, and attached again with
+// ReadDebugInfo to report the failing constraints with their call stacks.
+func (system *System) WriteDebugInfo(w io.Writer) (int64, error) {
+	fingerprint, err := system.debugInfoFingerprint()
+	if err != nil {
+		return 0, err
+	}
+	enc, err := cbor.CoreDetEncOptions().EncModeWithTags(getTagSet())
+	if err != nil {
+		return 0, err
+	}
+	data, err := enc.Marshal(&debugInfoFile{
+		Version:     debugInfoVersion,
+		Fingerprint: fingerprint,
+		DebugInfo:   system.DebugInfo,
+		SymbolTable: system.SymbolTable,
+		MDebug:      system.MDebug,
+	})
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// StripDebugInfo removes the debug information of the constraint system. The
+// solver then reports the failing constraints without their call stacks.
+func (system *System) StripDebugInfo() {
+	system.DebugInfo = nil
+	system.SymbolTable = debug.NewSymbolTable()
+	system.MDebug = map[int]int{}
+}
+
+// ReadDebugInfo reads the debug information written by WriteDebugInfo and
+// attaches it to the constraint system, replacing its debug information. It
+// returns ErrDebugInfoMismatch if the debug information was written for
+// another constraint system.
+func (system *System) ReadDebugInfo(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	dm, err := cbor.DecOptions{
+		MaxArrayElements: 2147483647,
+		MaxMapPairs:      2147483647,
+	}.DecModeWithTags(getTagSet())
+	if err != nil {
+		return int64(len(data)), err
+	}
+	var f debugInfoFile
+	if err := dm.Unmarshal(data, &f); err != nil {
+		return int64(len(data)), fmt.Errorf("decode debug information: %w", err)
+	}
+	if f.Version != debugInfoVersion {
+		return int64(len(data)), fmt.Errorf("unsupported debug information version %d", f.Version)
+	}
+	fingerprint, err := system.debugInfoFingerprint()
+	if err != nil {
+		return int64(len(data)), err
+	}
+	if !bytes.Equal(fingerprint, f.Fingerprint) {
+		return int64(len(data)), ErrDebugInfoMismatch
+	}
+	for _, dID := range f.MDebug {
+		if dID < 0 || dID >= len(f.DebugInfo) {
+			return int64(len(data)), fmt.Errorf("invalid debug information index %d", dID)
+		}
+	}
+	for _, l := range f.DebugInfo {
+		for _, lID := range l.Stack {
+			if lID < 0 || lID >= len(f.SymbolTable.Locations) {
+				return int64(len(data)), fmt.Errorf("invalid location index %d", lID)
+			}
+			if fID := f.SymbolTable.Locations[lID].FunctionID; fID < 0 || fID >= len(f.SymbolTable.Functions) {
+				return int64(len(data)), fmt.Errorf("invalid function index %d", fID)
+			}
+		}
+	}
+
+	system.DebugInfo = f.DebugInfo
+	system.SymbolTable = debug.NewSymbolTable()
+	system.SymbolTable.Locations = f.SymbolTable.Locations
+	system.SymbolTable.Functions = f.SymbolTable.Functions
+	system.MDebug = f.MDebug
+	if system.MDebug == nil {
+		system.MDebug = map[int]int{}
+	}
+	return int64(len(data)), nil
+}
+
+// debugInfoFingerprint returns a hash of the instructions of the constraint
+// system, which the debug information refers to.
+func (system *System) debugInfoFingerprint() ([]byte, error) {
+	instructions, err := system.instructionsToBytes()
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := system.calldataToBytes()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write(instructions)
+	h.Write(calldata)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,50 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugInfoSidecar(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sourceMapCircuit{})
+	assert.NoError(err)
+
+	var full, sidecar, stripped bytes.Buffer
+	_, err = ccs.WriteTo(&full)
+	assert.NoError(err)
+	_, err = ccs.WriteDebugInfo(&sidecar)
+	assert.NoError(err)
+	ccs.StripDebugInfo()
+	_, err = ccs.WriteTo(&stripped)
+	assert.NoError(err)
+	assert.Less(stripped.Len(), full.Len())
+
+	loaded := &cs_bn254.R1CS{}
+	_, err = loaded.ReadFrom(&stripped)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&sourceMapCircuit{A: 6, B: 3, C: 3}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = loaded.Solve(w)
+	assert.Error(err)
+	assert.NotContains(err.Error(), "sourceMapCircuit")
+
+	_, err = loaded.ReadDebugInfo(bytes.NewReader(sidecar.Bytes()))
+	assert.NoError(err)
+	_, err = loaded.Solve(w)
+	assert.ErrorContains(err, "sourceMapCircuit")
+
+	// the debug information is bound to the constraint system
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &iterateCircuit{})
+	assert.NoError(err)
+	_, err = other.ReadDebugInfo(bytes.NewReader(sidecar.Bytes()))
+	assert.ErrorIs(err, constraint.ErrDebugInfoMismatch)
+}
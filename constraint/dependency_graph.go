@@ -0,0 +1,183 @@
+package constraint
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// DependencyNode is an instruction of the dependency graph of a constraint
+// system.
+type DependencyNode struct {
+	// Blueprint is the name of the type of the blueprint of the instruction.
+	Blueprint string
+	// Level is the level at which the solver solves the instruction: the
+	// instructions of the same level are solved in parallel, after the ones of
+	// the previous levels.
+	Level int
+	// Dependencies are the instructions solving the internal wires read by
+	// the instruction, in increasing order.
+	Dependencies []int
+	// Inputs are the public and secret input wires read by the instruction.
+	Inputs []int
+	// Outputs are the internal wires solved by the instruction.
+	Outputs []int
+}
+
+// DependencyGraph is the directed acyclic graph of the instructions of a
+// constraint system, an instruction depending on the ones solving the wires it
+// reads. See System.DependencyGraph.
+type DependencyGraph struct {
+	Nodes []DependencyNode
+	// NbLevels is the number of levels of the solver, that is the length of
+	// the longest path in the graph.
+	NbLevels int
+}
+
+// DependencyGraph returns the dependency graph of the instructions of the
+// system. As with Blame, the graph is rebuilt from the blueprints on each
+// call.
+func (system *System) DependencyGraph() DependencyGraph {
+	rec, reads := system.replayDependencies()
+
+	g := DependencyGraph{Nodes: make([]DependencyNode, len(system.Instructions))}
+	for i, pi := range system.Instructions {
+		node := &g.Nodes[i]
+		node.Blueprint = blueprintName(system.Blueprints[pi.BlueprintID])
+		seen := make(map[int]struct{}, len(reads[i]))
+		for _, w := range reads[i] {
+			if w < rec.offset {
+				if _, ok := seen[-1-int(w)]; !ok {
+					seen[-1-int(w)] = struct{}{}
+					node.Inputs = append(node.Inputs, int(w))
+				}
+				continue
+			}
+			p := rec.producer[w-rec.offset]
+			if _, ok := seen[p]; p == -1 || p == i || ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			node.Dependencies = append(node.Dependencies, p)
+			node.Level = max(node.Level, g.Nodes[p].Level+1)
+		}
+		sort.Ints(node.Dependencies)
+		sort.Ints(node.Inputs)
+		g.NbLevels = max(g.NbLevels, node.Level+1)
+	}
+	for w, p := range rec.producer {
+		if p != -1 {
+			g.Nodes[p].Outputs = append(g.Nodes[p].Outputs, w+int(rec.offset))
+		}
+	}
+	return g
+}
+
+// CriticalPath returns a longest path of the graph, from an instruction
+// without dependencies to one of the last level. Its length bounds the
+// concurrency of the witness generation.
+func (g *DependencyGraph) CriticalPath() []int {
+	if len(g.Nodes) == 0 {
+		return nil
+	}
+	last := 0
+	for i := range g.Nodes {
+		if g.Nodes[i].Level > g.Nodes[last].Level {
+			last = i
+		}
+	}
+	path := []int{last}
+	for g.Nodes[last].Level > 0 {
+		for _, p := range g.Nodes[last].Dependencies {
+			if g.Nodes[p].Level == g.Nodes[last].Level-1 {
+				last = p
+				break
+			}
+		}
+		path = append(path, last)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// WriteDOT writes the graph in the DOT format of Graphviz. The nodes are
+// labelled with their blueprint and level, and the nodes and edges of the
+// critical path are drawn in red.
+func (g *DependencyGraph) WriteDOT(w io.Writer) error {
+	critical := g.criticalSet()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph dependencies {")
+	for i, node := range g.Nodes {
+		fmt.Fprintf(bw, "\tn%d [label=\"%d: %s\\nlevel %d\"", i, i, node.Blueprint, node.Level)
+		if _, ok := critical[i]; ok {
+			fmt.Fprint(bw, ", color=red")
+		}
+		fmt.Fprintln(bw, "];")
+	}
+	for i, node := range g.Nodes {
+		for _, p := range node.Dependencies {
+			fmt.Fprintf(bw, "\tn%d -> n%d", p, i)
+			if q, ok := critical[i]; ok && q == p {
+				fmt.Fprint(bw, " [color=red]")
+			}
+			fmt.Fprintln(bw, ";")
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteGraphML writes the graph in the GraphML format, the nodes having the
+// blueprint and level attributes.
+func (g *DependencyGraph) WriteGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(bw, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(bw, `  <key id="blueprint" for="node" attr.name="blueprint" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="level" for="node" attr.name="level" attr.type="int"/>`)
+	fmt.Fprintln(bw, `  <graph id="dependencies" edgedefault="directed">`)
+	for i, node := range g.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"n%d\"><data key=\"blueprint\">", i)
+		if err := xml.EscapeText(bw, []byte(node.Blueprint)); err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "</data><data key=\"level\">%d</data></node>\n", node.Level)
+	}
+	for i, node := range g.Nodes {
+		for _, p := range node.Dependencies {
+			fmt.Fprintf(bw, "    <edge source=\"n%d\" target=\"n%d\"/>\n", p, i)
+		}
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
+
+// criticalSet returns the nodes of the critical path, mapped to their
+// predecessor on the path (-1 for the first one).
+func (g *DependencyGraph) criticalSet() map[int]int {
+	path := g.CriticalPath()
+	res := make(map[int]int, len(path))
+	for i, n := range path {
+		if i == 0 {
+			res[n] = -1
+		} else {
+			res[n] = path[i-1]
+		}
+	}
+	return res
+}
+
+// blueprintName returns the name of the type of the blueprint.
+func blueprintName(b Blueprint) string {
+	t := reflect.TypeOf(b)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
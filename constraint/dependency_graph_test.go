@@ -0,0 +1,52 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type chainCircuit struct {
+	X, Y, Z frontend.Variable
+}
+
+func (c *chainCircuit) Define(api frontend.API) error {
+	// a chain of 4 multiplications and an independent one
+	x := c.X
+	for i := 0; i < 4; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, api.Mul(c.Y, c.Z))
+	return nil
+}
+
+func TestDependencyGraph(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &chainCircuit{})
+	assert.NoError(err)
+	g := ccs.DependencyGraph()
+	assert.Len(g.Nodes, ccs.GetNbInstructions())
+	assert.Equal(5, g.NbLevels)
+
+	path := g.CriticalPath()
+	assert.Len(path, g.NbLevels)
+	for i, n := range path {
+		assert.Equal(i, g.Nodes[n].Level)
+		if i > 0 {
+			assert.Contains(g.Nodes[n].Dependencies, path[i-1])
+		}
+	}
+	assert.Equal([]int{0}, g.Nodes[path[0]].Inputs, "the chain starts from X")
+
+	var dot, graphML bytes.Buffer
+	assert.NoError(g.WriteDOT(&dot))
+	assert.Contains(dot.String(), "digraph dependencies {")
+	assert.Contains(dot.String(), "[color=red]")
+	assert.NoError(g.WriteGraphML(&graphML))
+	assert.Contains(graphML.String(), `<data key="blueprint">BlueprintSparseR1CMul</data>`)
+}
@@ -0,0 +1,50 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type deterministicCircuit struct {
+	X frontend.Variable
+}
+
+func (c *deterministicCircuit) Define(api frontend.API) error {
+	// the first failing constraint by index is at the second level, and
+	// the following ones at the first level
+	api.AssertIsEqual(api.Mul(c.X, c.X), 5)
+	for i := 0; i < 300; i++ {
+		api.AssertIsEqual(api.Mul(c.X, i+1), 3*(i+1))
+	}
+	return nil
+}
+
+func TestDeterministicOrder(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &deterministicCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&deterministicCircuit{X: 2}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	failing := func(opts ...solver.Option) int {
+		_, err := ccs.Solve(w, opts...)
+		var uErr *cs_bn254.UnsatisfiedConstraintError
+		assert.ErrorAs(err, &uErr)
+		return uErr.CID
+	}
+
+	// by level, a constraint of the first level fails first
+	assert.NotEqual(1, failing(solver.WithNbTasks(1)))
+	for _, nbTasks := range []int{1, 4, 16} {
+		for i := 0; i < 5; i++ {
+			assert.Equal(1, failing(solver.WithNbTasks(nbTasks), solver.WithDeterministicOrder()))
+		}
+	}
+}
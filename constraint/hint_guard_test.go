@@ -0,0 +1,85 @@
+package constraint_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseGuardedHint unblocks the calls of guardedHint which hang.
+var releaseGuardedHint = make(chan struct{})
+
+// guardedHint panics on 0, hangs on 1 and doubles its input otherwise.
+func guardedHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	switch inputs[0].Uint64() {
+	case 0:
+		var m map[int]int
+		m[0] = 1
+	case 1:
+		<-releaseGuardedHint
+	}
+	outputs[0].Lsh(inputs[0], 1)
+	return nil
+}
+
+type hintGuardCircuit struct {
+	X frontend.Variable
+}
+
+func (c *hintGuardCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(guardedHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], api.Mul(c.X, 2))
+	return nil
+}
+
+func TestHintGuard(t *testing.T) {
+	assert := require.New(t)
+	defer close(releaseGuardedHint)
+	name := solver.GetHintName(guardedHint)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &hintGuardCircuit{})
+		assert.NoError(err)
+		solve := func(x int, opts ...solver.Option) error {
+			w, err := frontend.NewWitness(&hintGuardCircuit{X: x}, ecc.BN254.ScalarField())
+			assert.NoError(err)
+			_, err = ccs.Solve(w, append(opts, solver.WithHints(guardedHint))...)
+			return err
+		}
+
+		for _, opts := range [][]solver.Option{nil, {solver.WithHintTimeout(time.Second)}} {
+			assert.NoError(solve(3, opts...))
+
+			// a panic is returned as an error naming the hint
+			err = solve(0, opts...)
+			var hErr *solver.HintError
+			assert.ErrorAs(err, &hErr)
+			assert.Equal(name, hErr.Hint)
+			assert.ErrorContains(err, "assignment to entry in nil map")
+		}
+
+		// a hint which hangs times out
+		start := time.Now()
+		err = solve(1, solver.WithHintTimeout(50*time.Millisecond))
+		assert.ErrorIs(err, solver.ErrHintTimeout)
+		assert.ErrorContains(err, name)
+		assert.Less(time.Since(start), 10*time.Second)
+	}
+
+	w, err := frontend.NewWitness(&hintGuardCircuit{X: 3}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &hintGuardCircuit{})
+	assert.NoError(err)
+	_, err = ccs.Solve(w, solver.WithHintTimeout(0))
+	assert.Error(err)
+}
@@ -0,0 +1,82 @@
+package constraint_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// doublingModule stands for a module compiled to WebAssembly, instantiated by
+// fakeRuntime as doublingSandbox.
+var doublingModule = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x2a}
+
+type fakeRuntime struct {
+	sandbox        doublingSandbox
+	nbInstantiated int
+}
+
+func (r *fakeRuntime) instantiate(module []byte) (solver.HintSandbox, error) {
+	if !bytes.Equal(module, doublingModule) {
+		return nil, errors.New("unknown module")
+	}
+	r.nbInstantiated++
+	return &r.sandbox, nil
+}
+
+func TestHintModules(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &sandboxCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&sandboxCircuit{X: 3, Y: 12}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the modules are shipped with the constraint system
+	var buf bytes.Buffer
+	_, err = ccs.WriteTo(&buf)
+	assert.NoError(err)
+	_, err = solver.HintModules{solver.GetHintID(untrustedHint): doublingModule}.WriteTo(&buf)
+	assert.NoError(err)
+
+	ccs2 := &cs_bn254.SparseR1CS{}
+	_, err = ccs2.ReadFrom(&buf)
+	assert.NoError(err)
+	modules := make(solver.HintModules)
+	_, err = modules.ReadFrom(&buf)
+	assert.NoError(err)
+
+	var runtime fakeRuntime
+	sandbox, err := solver.NewModuleSandbox(modules, runtime.instantiate)
+	assert.NoError(err)
+	for i := 0; i < 2; i++ {
+		_, err = ccs2.Solve(w, solver.WithHintSandbox(sandbox))
+		assert.NoError(err)
+	}
+	assert.Equal(1, runtime.nbInstantiated)
+	assert.Equal([]string{solver.GetHintName(untrustedHint), solver.GetHintName(untrustedHint)}, runtime.sandbox.names)
+
+	sandbox, err = solver.NewModuleSandbox(solver.HintModules{}, runtime.instantiate)
+	assert.NoError(err)
+	_, err = ccs2.Solve(w, solver.WithHintSandbox(sandbox))
+	assert.ErrorContains(err, "no module")
+	sandbox, err = solver.NewModuleSandbox(solver.HintModules{solver.GetHintID(untrustedHint): append(doublingModule[:8:8], 0)}, runtime.instantiate)
+	assert.NoError(err)
+	_, err = ccs2.Solve(w, solver.WithHintSandbox(sandbox))
+	assert.ErrorContains(err, "unknown module")
+	_, err = solver.NewModuleSandbox(solver.HintModules{solver.GetHintID(untrustedHint): doublingModule[1:]}, runtime.instantiate)
+	assert.ErrorContains(err, "not a WebAssembly module")
+
+	// truncated
+	var short bytes.Buffer
+	_, err = modules.WriteTo(&short)
+	assert.NoError(err)
+	_, err = make(solver.HintModules).ReadFrom(bytes.NewReader(short.Bytes()[:short.Len()-1]))
+	assert.Error(err)
+}
@@ -0,0 +1,127 @@
+package constraint_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/constraint/solver/remote"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteHint is not registered: the solver calls the handler.
+func remoteHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Mul(inputs[0], inputs[0])
+	outputs[0].Mod(outputs[0], field)
+	return nil
+}
+
+type providerCircuit struct {
+	X [60]frontend.Variable
+}
+
+// Define squares the inputs twice with the hint, in two levels.
+func (c *providerCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		res := c.X[i]
+		for j := 0; j < 2; j++ {
+			sq, err := api.Compiler().NewHint(remoteHint, 1, res)
+			if err != nil {
+				return err
+			}
+			api.AssertIsEqual(sq[0], api.Mul(res, res))
+			res = sq[0]
+		}
+	}
+	return nil
+}
+
+// badProvider returns unreduced outputs.
+type badProvider struct{}
+
+func (badProvider) Hint(_ context.Context, field *big.Int, calls []solver.HintCall) error {
+	for _, c := range calls {
+		c.Outputs[0].Set(field)
+	}
+	return nil
+}
+
+func TestHintProvider(t *testing.T) {
+	assert := require.New(t)
+	var nbRequests atomic.Int32
+	handler, err := remote.NewHandler([]solver.Hint{remoteHint})
+	assert.NoError(err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nbRequests.Add(1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := remote.NewClient(server.URL, server.Client())
+
+	var assignment providerCircuit
+	for i := range assignment.X {
+		assignment.X[i] = i + 2
+	}
+	w, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &providerCircuit{})
+		assert.NoError(err)
+
+		_, err = ccs.Solve(w)
+		assert.ErrorContains(err, "missing hint")
+
+		// one request per level of hints, whatever the number of tasks
+		for _, nbTasks := range []int{1, 4} {
+			nbRequests.Store(0)
+			_, err = ccs.Solve(w, solver.WithHintProvider(client), solver.WithNbTasks(nbTasks))
+			assert.NoError(err)
+			assert.EqualValues(2, nbRequests.Load())
+		}
+
+		// the calls are not batched in the deterministic order
+		nbRequests.Store(0)
+		_, err = ccs.Solve(w, solver.WithHintProvider(client), solver.WithDeterministicOrder())
+		assert.NoError(err)
+		assert.EqualValues(2*len(assignment.X), nbRequests.Load())
+
+		_, err = ccs.Solve(w, solver.WithHintProvider(badProvider{}))
+		assert.ErrorContains(err, "not reduced")
+
+		none, err := remote.NewHandler(nil)
+		assert.NoError(err)
+		empty := httptest.NewServer(none)
+		_, err = ccs.Solve(w, solver.WithHintProvider(remote.NewClient(empty.URL, nil)))
+		assert.ErrorContains(err, "unknown hint")
+		empty.Close()
+
+		// the level of 60 calls is too large for the limits
+		for _, opt := range []remote.Option{remote.WithMaxCalls(10), remote.WithMaxOutputs(10), remote.WithMaxRequestSize(1 << 10)} {
+			small, err := remote.NewHandler([]solver.Hint{remoteHint}, opt)
+			assert.NoError(err)
+			limited := httptest.NewServer(small)
+			_, err = ccs.Solve(w, solver.WithHintProvider(remote.NewClient(limited.URL, nil)))
+			assert.ErrorContains(err, "413")
+			limited.Close()
+		}
+	}
+
+	// a request asking for many outputs is rejected before allocating them
+	rec := httptest.NewRecorder()
+	body := `{"modulus": "7", "calls": [{"name": "x", "inputs": [], "nbOutputs": 1099511627776}]}`
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+
+	_, err = remote.NewHandler(nil, remote.WithMaxOutputs(0))
+	assert.Error(err)
+}
@@ -0,0 +1,68 @@
+package constraint_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// untrustedHint is not registered: the solver runs it in the sandbox.
+func untrustedHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	return errors.New("the native hint must not be called")
+}
+
+type sandboxCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *sandboxCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(untrustedHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Add(res[0], res[0]), c.Y)
+	return nil
+}
+
+// doublingSandbox implements the hint as the WASM module would, on the encoded
+// input.
+type doublingSandbox struct {
+	names []string
+}
+
+func (s *doublingSandbox) Call(name string, input []byte) ([]byte, error) {
+	s.names = append(s.names, name)
+	n := int(input[3])
+	modulus := new(big.Int).SetBytes(input[12 : 12+n])
+	x := new(big.Int).SetBytes(input[12+n : 12+2*n])
+	x.Mul(x, big.NewInt(2)).Mod(x, modulus)
+	return x.FillBytes(make([]byte, n)), nil
+}
+
+func TestHintSandbox(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &sandboxCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&sandboxCircuit{X: 3, Y: 12}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	_, err = ccs.Solve(w)
+	assert.ErrorContains(err, "missing hint")
+
+	var sandbox doublingSandbox
+	_, err = ccs.Solve(w, solver.WithHintSandbox(&sandbox))
+	assert.NoError(err)
+	assert.Equal([]string{solver.GetHintName(untrustedHint)}, sandbox.names)
+
+	w, err = frontend.NewWitness(&sandboxCircuit{X: 3, Y: 6}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w, solver.WithHintSandbox(&sandbox))
+	assert.Error(err)
+}
@@ -0,0 +1,105 @@
+package constraint_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type hintTraceCircuit struct {
+	X frontend.Variable
+}
+
+func (c *hintTraceCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(solver.InvZeroHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(res[0], c.X), 1)
+	return nil
+}
+
+func TestHintTrace(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &hintTraceCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&hintTraceCircuit{X: 2}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = ccs.Solve(w, solver.WithHintTrace(&buf))
+	assert.NoError(err)
+
+	var entry solver.HintTraceEntry
+	dec := json.NewDecoder(&buf)
+	assert.NoError(dec.Decode(&entry))
+	assert.False(dec.More(), "expected a single hint invocation")
+	assert.Equal(solver.GetHintName(solver.InvZeroHint), entry.Hint)
+	assert.Equal(solver.GetHintID(solver.InvZeroHint), entry.ID)
+	assert.Equal([]string{"2"}, entry.Inputs)
+	// 1/2 mod r
+	half := new(fr.Element).SetUint64(2)
+	half.Inverse(half)
+	assert.Equal([]string{half.String()}, entry.Outputs)
+	assert.Empty(entry.Error)
+}
+
+// randomHint is nondeterministic, and not registered.
+func randomHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	r, err := rand.Int(rand.Reader, field)
+	if err != nil {
+		return err
+	}
+	outputs[0].Set(r)
+	return nil
+}
+
+type hintReplayCircuit struct {
+	X frontend.Variable
+}
+
+func (c *hintReplayCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(randomHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(api.Mul(res[0], c.X), 0)
+	return nil
+}
+
+func TestHintReplay(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &hintReplayCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&hintReplayCircuit{X: 2}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	recorded, err := ccs.Solve(w, solver.WithHints(randomHint), solver.WithHintTrace(&buf))
+	assert.NoError(err)
+	trace, err := solver.ReadHintTrace(&buf)
+	assert.NoError(err)
+	assert.Len(trace, 1)
+
+	// the hint doesn't need to be provided
+	replayed, err := ccs.Solve(w, solver.WithHintReplay(trace))
+	assert.NoError(err)
+	assert.Equal(recorded, replayed)
+
+	// the inputs must match the recording
+	w, err = frontend.NewWitness(&hintReplayCircuit{X: 3}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w, solver.WithHintReplay(trace))
+	assert.ErrorContains(err, "no recorded call")
+}
@@ -0,0 +1,94 @@
+package constraint
+
+import "math/big"
+
+// ConstraintKind is the shape of a constraint, see ConstraintView.
+type ConstraintKind uint8
+
+const (
+	// KindOther is a constraint of a custom blueprint, which can't be
+	// decompressed.
+	KindOther ConstraintKind = iota
+	// KindR1C is a rank-1 constraint L⋅R == O.
+	KindR1C
+	// KindSparseR1C is a PLONK constraint
+	// qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa⋅xb) + qC == 0.
+	KindSparseR1C
+)
+
+// TermView is a decompressed term Coeff⋅Wire of a linear expression.
+type TermView struct {
+	Coeff *big.Int
+	Wire  int
+}
+
+// ConstraintView is a decompressed constraint yielded by Iterate. The wires
+// are indexed as in the solution: public inputs, then secret inputs, then
+// internal wires. See Resolver.VariableToString for their names.
+type ConstraintView struct {
+	Kind ConstraintKind
+	// Blueprint is the name of the type of the blueprint of the constraint,
+	// for example "BlueprintGenericR1C".
+	Blueprint string
+
+	// L, R and O are the linear expressions of a KindR1C constraint.
+	L, R, O []TermView
+
+	// XA, XB, XC are the wires and QL, QR, QO, QM, QC the coefficients of a
+	// KindSparseR1C constraint. Commitment is set if the constraint is part
+	// of a BSB22 commitment.
+	XA, XB, XC         int
+	QL, QR, QO, QM, QC *big.Int
+	Commitment         CommitmentConstraint
+
+	// Gadget is the call stack recorded with the debug information of the
+	// constraint, innermost frame first. It is nil if there is none.
+	Gadget []StackFrame
+}
+
+// IterateWithCoefficients calls f on the constraints of the system, in order,
+// until f returns false. coefficient returns the value of the coefficient of
+// the given ID; it is provided by the curve typed systems, which implement
+// ConstraintSystem.Iterate with it.
+func (system *System) IterateWithCoefficients(coefficient func(coeffID uint32) *big.Int, f func(cID int, view ConstraintView) bool) {
+	expression := func(l LinearExpression) []TermView {
+		res := make([]TermView, len(l))
+		for i, t := range l {
+			res[i] = TermView{Coeff: coefficient(t.CID), Wire: int(t.VID)}
+		}
+		return res
+	}
+
+	var (
+		r1c       R1C
+		sparseR1C SparseR1C
+	)
+	for _, pi := range system.Instructions {
+		blueprint := system.Blueprints[pi.BlueprintID]
+		nbConstraints := blueprint.NbConstraints()
+		if nbConstraints == 0 {
+			continue
+		}
+		name := blueprintName(blueprint)
+		for i := 0; i < nbConstraints; i++ {
+			cID := int(pi.ConstraintOffset) + i
+			view := ConstraintView{Blueprint: name, Gadget: system.GetCallStack(cID)}
+			switch b := blueprint.(type) {
+			case BlueprintR1C:
+				b.DecompressR1C(&r1c, pi.Unpack(system))
+				view.Kind = KindR1C
+				view.L, view.R, view.O = expression(r1c.L), expression(r1c.R), expression(r1c.O)
+			case BlueprintSparseR1C:
+				b.DecompressSparseR1C(&sparseR1C, pi.Unpack(system))
+				view.Kind = KindSparseR1C
+				view.XA, view.XB, view.XC = int(sparseR1C.XA), int(sparseR1C.XB), int(sparseR1C.XC)
+				view.QL, view.QR, view.QO = coefficient(sparseR1C.QL), coefficient(sparseR1C.QR), coefficient(sparseR1C.QO)
+				view.QM, view.QC = coefficient(sparseR1C.QM), coefficient(sparseR1C.QC)
+				view.Commitment = sparseR1C.Commitment
+			}
+			if !f(cID, view) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type iterateCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *iterateCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, 3, c.X), c.Y)
+	api.AssertIsBoolean(c.X)
+	return nil
+}
+
+func TestIterate(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &iterateCircuit{})
+	assert.NoError(err)
+	var views []constraint.ConstraintView
+	ccs.Iterate(func(cID int, view constraint.ConstraintView) bool {
+		assert.Equal(len(views), cID)
+		views = append(views, view)
+		return true
+	})
+	assert.Len(views, ccs.GetNbConstraints())
+	// 3X ⋅ X == v0
+	assert.Equal(constraint.KindR1C, views[0].Kind)
+	assert.Equal("BlueprintGenericR1C", views[0].Blueprint)
+	assert.Len(views[0].L, 1)
+	assert.Equal(int64(3), views[0].L[0].Coeff.Int64())
+	assert.Equal("X", ccs.VariableToString(views[0].L[0].Wire))
+	// 1 ⋅ v0 == Y
+	assert.Equal("Y", ccs.VariableToString(views[1].O[0].Wire))
+
+	ccs, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &iterateCircuit{})
+	assert.NoError(err)
+	nbViews := 0
+	ccs.Iterate(func(cID int, view constraint.ConstraintView) bool {
+		assert.Equal(constraint.KindSparseR1C, view.Kind)
+		nbViews++
+		return false
+	})
+	assert.Equal(1, nbViews, "iteration should stop when f returns false")
+}
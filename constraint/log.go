@@ -15,7 +15,10 @@
 package constraint
 
 import (
+	"fmt"
 	"strings"
+
+	"github.com/rs/zerolog"
 )
 
 // LogEntry is used as a shared data structure between the frontend and the backend
@@ -26,6 +29,47 @@ type LogEntry struct {
 	Format    string
 	ToResolve []LinearExpression // TODO @gbotrel we could store here a struct with a flag that says if we expand or evaluate the expression
 	Stack     []int
+
+	// Keys and Values are set for structured log entries (see frontend.API.Log),
+	// in which case Format is empty. Values[i] is the format string of the value
+	// of Keys[i]; the formats consume the resolved ToResolve expressions in order.
+	Keys   []string
+	Values []string
+
+	// Level is the level at which the solver logs the entry. The zero value
+	// is zerolog.DebugLevel.
+	Level zerolog.Level
+}
+
+// FormatValues formats the values of a structured log entry given the
+// resolved ToResolve expressions.
+func (l *LogEntry) FormatValues(resolved []interface{}) []string {
+	res := make([]string, len(l.Values))
+	j := 0
+	for i, format := range l.Values {
+		n := nbVerbs(format)
+		if j+n > len(resolved) {
+			n = len(resolved) - j
+		}
+		res[i] = fmt.Sprintf(format, resolved[j:j+n]...)
+		j += n
+	}
+	return res
+}
+
+// nbVerbs returns the number of formatting verbs in the format string.
+func nbVerbs(format string) int {
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] != '%' {
+			n++
+		}
+		i++
+	}
+	return n
 }
 
 func (l *LogEntry) WriteVariable(le LinearExpression, sbb *strings.Builder) {
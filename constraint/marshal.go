@@ -92,6 +92,20 @@ func (cs *System) FromBytes(data []byte) (int, error) {
 	})
 
 	// CBOR decoding of the constraint system (except what we do directly in binary)
+	if err := cs.bodyFromBytes(data[headerLen+h.levelsLen+h.instructionsLen+h.calldataLen : headerLen+h.levelsLen+h.instructionsLen+h.calldataLen+h.bodyLen]); err != nil {
+		return 0, err
+	}
+
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+
+	return headerLen + int(h.levelsLen) + int(h.instructionsLen) + int(h.calldataLen) + int(h.bodyLen), nil
+}
+
+// bodyFromBytes decodes the CBOR encoding of the constraint system written by
+// toBytes, and checks its serialization header.
+func (cs *System) bodyFromBytes(data []byte) error {
 	ts := getTagSet()
 	dm, err := cbor.DecOptions{
 		MaxArrayElements: 2147483647,
@@ -99,16 +113,16 @@ func (cs *System) FromBytes(data []byte) (int, error) {
 	}.DecModeWithTags(ts)
 
 	if err != nil {
-		return 0, err
+		return err
 	}
-	decoder := dm.NewDecoder(bytes.NewReader(data[headerLen+h.levelsLen+h.instructionsLen+h.calldataLen : headerLen+h.levelsLen+h.instructionsLen+h.calldataLen+h.bodyLen]))
+	decoder := dm.NewDecoder(bytes.NewReader(data))
 
 	if err := decoder.Decode(&cs); err != nil {
-		return 0, err
+		return err
 	}
 
 	if err := cs.CheckSerializationHeader(); err != nil {
-		return 0, err
+		return err
 	}
 
 	switch v := cs.CommitmentInfo.(type) {
@@ -117,12 +131,7 @@ func (cs *System) FromBytes(data []byte) (int, error) {
 	case *PlonkCommitments:
 		cs.CommitmentInfo = *v
 	}
-
-	if err := g.Wait(); err != nil {
-		return 0, err
-	}
-
-	return headerLen + int(h.levelsLen) + int(h.instructionsLen) + int(h.calldataLen) + int(h.bodyLen), nil
+	return nil
 }
 
 func (cs *System) toBytes() ([]byte, error) {
@@ -358,6 +367,8 @@ func getTagSet() cbor.TagSet {
 	addType(reflect.TypeOf(BlueprintLookupHint{}))
 	addType(reflect.TypeOf(Groth16Commitments{}))
 	addType(reflect.TypeOf(PlonkCommitments{}))
+	addType(reflect.TypeOf(BlueprintMemory{}))
+	addType(reflect.TypeOf(BlueprintPrefixSum{}))
 
 	return ts
 }
@@ -0,0 +1,311 @@
+package constraint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/internal/backend/ioutils"
+)
+
+// streamMagic starts the encoding written by System.WriteStreamTo, so that it
+// isn't mistaken for the encoding of WriteTo.
+const streamMagic = uint64(0x6d61657274736367) // "gcstream"
+
+// streamChunkSize is the number of values encoded in each chunk of the
+// sections written by System.WriteStreamTo.
+const streamChunkSize = 1 << 20
+
+// WriteStreamTo serializes the constraint system to w, without materializing
+// the serialized system in memory: the instructions, the calldata and the
+// levels are encoded and written in chunks of bounded size. The result is
+// read with ReadStreamFrom.
+//
+// This is not meant to be called directly since the constraint.System is embedded in
+// a "curve-typed" system (e.g. bls12-381.system)
+func (cs *System) WriteStreamTo(w io.Writer) (int64, error) {
+	cw := &ioutils.WriterCounter{W: w}
+	sw := &streamWriter{w: bufio.NewWriterSize(cw, 1<<20)}
+
+	sw.writeUint64(streamMagic)
+
+	// the body comes first, so that the version of the system is checked
+	// before reading the large sections.
+	body, err := cs.toBytes()
+	if err != nil {
+		return cw.N, err
+	}
+	sw.buf.Write(body)
+	sw.writeChunk()
+
+	sw.writeUint64(uint64(len(cs.Levels)))
+	for _, l := range cs.Levels {
+		sw.writeUints32(l)
+	}
+	cs.instructionsToStream(sw)
+	cs.calldataToStream(sw)
+
+	if sw.err == nil {
+		sw.err = sw.w.Flush()
+	}
+	return cw.N, sw.err
+}
+
+// ReadStreamFrom deserializes the constraint system written by WriteStreamTo
+// from r. The sections are read and decoded in chunks, so that the memory used
+// is the one of the deserialized system, plus a chunk. r is not read past the
+// end of the constraint system.
+//
+// This is not meant to be called directly since the constraint.System is embedded in
+// a "curve-typed" system (e.g. bls12-381.system)
+func (cs *System) ReadStreamFrom(r io.Reader) (int64, error) {
+	sr := &streamReader{r: r}
+
+	magic, err := sr.readUint64()
+	if err != nil {
+		return sr.n, err
+	}
+	if magic != streamMagic {
+		return sr.n, errors.New("not a streamed constraint system")
+	}
+
+	body, err := sr.readChunk()
+	if err != nil {
+		return sr.n, err
+	}
+	if err := cs.bodyFromBytes(body); err != nil {
+		return sr.n, err
+	}
+
+	nbLevels, err := sr.readUint64()
+	if err != nil {
+		return sr.n, err
+	}
+	cs.Levels = make([][]uint32, 0, min(nbLevels, streamChunkSize))
+	for i := uint64(0); i < nbLevels; i++ {
+		l, err := sr.readUints32()
+		if err != nil {
+			return sr.n, err
+		}
+		cs.Levels = append(cs.Levels, l)
+	}
+
+	if err := cs.instructionsFromStream(sr); err != nil {
+		return sr.n, err
+	}
+	if err := cs.calldataFromStream(sr); err != nil {
+		return sr.n, err
+	}
+	return sr.n, nil
+}
+
+func (cs *System) instructionsToStream(sw *streamWriter) {
+	n := min(len(cs.Instructions), streamChunkSize)
+	sBlueprintID := make([]uint32, n)
+	sConstraintOffset := make([]uint32, n)
+	sWireOffset := make([]uint32, n)
+	sStartCallData := make([]uint64, n)
+
+	sw.writeUint64(uint64(len(cs.Instructions)))
+	for start := 0; start < len(cs.Instructions) && sw.err == nil; start += streamChunkSize {
+		chunk := cs.Instructions[start:min(start+streamChunkSize, len(cs.Instructions))]
+		for i, inst := range chunk {
+			sBlueprintID[i] = uint32(inst.BlueprintID)
+			sConstraintOffset[i] = inst.ConstraintOffset
+			sWireOffset[i] = inst.WireOffset
+			sStartCallData[i] = inst.StartCallData
+		}
+		for _, s := range [][]uint32{sBlueprintID, sConstraintOffset, sWireOffset} {
+			if sw.buf32, sw.err = ioutils.CompressAndWriteUints32(&sw.buf, s[:len(chunk)], sw.buf32); sw.err != nil {
+				return
+			}
+		}
+		if sw.err = ioutils.CompressAndWriteUints64(&sw.buf, sStartCallData[:len(chunk)]); sw.err != nil {
+			return
+		}
+		sw.writeChunk()
+	}
+}
+
+func (cs *System) instructionsFromStream(sr *streamReader) error {
+	nbInstructions, err := sr.readUint64()
+	if err != nil {
+		return err
+	}
+	cs.Instructions = make([]PackedInstruction, 0, nbInstructions)
+	for uint64(len(cs.Instructions)) < nbInstructions {
+		in, err := sr.readChunk()
+		if err != nil {
+			return err
+		}
+		var columns [3][]uint32
+		for i := range columns {
+			var n int
+			if sr.buf32, n, columns[i], err = ioutils.ReadAndDecompressUints32(in, sr.buf32); err != nil {
+				return err
+			}
+			in = in[n:]
+		}
+		_, sStartCallData, err := ioutils.ReadAndDecompressUints64(in)
+		if err != nil {
+			return err
+		}
+		n := len(sStartCallData)
+		if n == 0 || len(columns[0]) != n || len(columns[1]) != n || len(columns[2]) != n ||
+			uint64(len(cs.Instructions)+n) > nbInstructions {
+			return errors.New("invalid instructions chunk")
+		}
+		for i := 0; i < n; i++ {
+			cs.Instructions = append(cs.Instructions, PackedInstruction{
+				BlueprintID:      BlueprintID(columns[0][i]),
+				ConstraintOffset: columns[1][i],
+				WireOffset:       columns[2][i],
+				StartCallData:    sStartCallData[i],
+			})
+		}
+	}
+	return nil
+}
+
+func (cs *System) calldataToStream(sw *streamWriter) {
+	sw.writeUint64(uint64(len(cs.CallData)))
+	var b []byte
+	for start := 0; start < len(cs.CallData) && sw.err == nil; start += streamChunkSize {
+		b = b[:0]
+		for _, v := range cs.CallData[start:min(start+streamChunkSize, len(cs.CallData))] {
+			b = binary.AppendUvarint(b, uint64(v))
+		}
+		sw.buf.Write(b)
+		sw.writeChunk()
+	}
+}
+
+func (cs *System) calldataFromStream(sr *streamReader) error {
+	calldataLen, err := sr.readUint64()
+	if err != nil {
+		return err
+	}
+	cs.CallData = make([]uint32, 0, calldataLen)
+	for uint64(len(cs.CallData)) < calldataLen {
+		buf, err := sr.readChunk()
+		if err != nil {
+			return err
+		}
+		if len(buf) == 0 {
+			return errors.New("invalid calldata")
+		}
+		for len(buf) > 0 {
+			v, n := binary.Uvarint(buf[:min(len(buf), binary.MaxVarintLen64)])
+			if n <= 0 || uint64(len(cs.CallData)) >= calldataLen {
+				return errors.New("invalid calldata")
+			}
+			cs.CallData = append(cs.CallData, uint32(v))
+			buf = buf[n:]
+		}
+	}
+	return nil
+}
+
+// streamWriter writes the sections of System.WriteStreamTo. It records the
+// first error, so that the sections are written without checking each write.
+type streamWriter struct {
+	w     *bufio.Writer
+	buf   bytes.Buffer // current chunk
+	buf32 []uint32
+	err   error
+}
+
+func (sw *streamWriter) writeUint64(v uint64) {
+	if sw.err != nil {
+		return
+	}
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	_, sw.err = sw.w.Write(b[:])
+}
+
+// writeChunk writes the current chunk with its length, and resets it.
+func (sw *streamWriter) writeChunk() {
+	sw.writeUint64(uint64(sw.buf.Len()))
+	if sw.err == nil {
+		_, sw.err = sw.w.Write(sw.buf.Bytes())
+	}
+	sw.buf.Reset()
+}
+
+// writeUints32 writes the length of s, and the compressed chunks of s.
+func (sw *streamWriter) writeUints32(s []uint32) {
+	sw.writeUint64(uint64(len(s)))
+	for start := 0; start < len(s) && sw.err == nil; start += streamChunkSize {
+		chunk := s[start:min(start+streamChunkSize, len(s))]
+		if sw.buf32, sw.err = ioutils.CompressAndWriteUints32(&sw.buf, chunk, sw.buf32); sw.err != nil {
+			return
+		}
+		sw.writeChunk()
+	}
+}
+
+// streamReader reads the sections of System.ReadStreamFrom, and counts the
+// bytes read.
+type streamReader struct {
+	r     io.Reader
+	n     int64
+	chunk []byte // reused for each chunk
+	buf32 []uint32
+}
+
+func (sr *streamReader) readUint64() (uint64, error) {
+	var b [8]byte
+	n, err := io.ReadFull(sr.r, b[:])
+	sr.n += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+// readChunk returns the next chunk. It is valid until the next call.
+func (sr *streamReader) readChunk() ([]byte, error) {
+	length, err := sr.readUint64()
+	if err != nil {
+		return nil, err
+	}
+	// the chunks are bounded, except for the body of the system
+	if length > 1<<40 {
+		return nil, fmt.Errorf("invalid chunk length %d", length)
+	}
+	if uint64(cap(sr.chunk)) < length {
+		sr.chunk = make([]byte, length)
+	}
+	sr.chunk = sr.chunk[:length]
+	n, err := io.ReadFull(sr.r, sr.chunk)
+	sr.n += int64(n)
+	return sr.chunk, err
+}
+
+// readUints32 reads a slice written by streamWriter.writeUints32.
+func (sr *streamReader) readUints32() ([]uint32, error) {
+	length, err := sr.readUint64()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uint32, 0, length)
+	for uint64(len(res)) < length {
+		in, err := sr.readChunk()
+		if err != nil {
+			return nil, err
+		}
+		var out []uint32
+		if sr.buf32, _, out, err = ioutils.ReadAndDecompressUints32(in, sr.buf32); err != nil {
+			return nil, err
+		}
+		if len(out) == 0 || uint64(len(res)+len(out)) > length {
+			return nil, errors.New("invalid chunk")
+		}
+		res = append(res, out...)
+	}
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package constraint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamChunks(t *testing.T) {
+	assert := require.New(t)
+
+	// the sections span several chunks
+	n := 2*streamChunkSize + 3
+	cs := NewSystem(ecc.BN254.ScalarField(), 0, SystemR1CS)
+	cs.Instructions = make([]PackedInstruction, n)
+	cs.CallData = make([]uint32, n)
+	for i := range cs.Instructions {
+		cs.Instructions[i] = PackedInstruction{
+			BlueprintID:      BlueprintID(i % 3),
+			ConstraintOffset: uint32(i),
+			WireOffset:       uint32(2 * i),
+			StartCallData:    uint64(i),
+		}
+		cs.CallData[i] = uint32(i * i)
+	}
+	cs.Levels = [][]uint32{{0, 1}, {}, make([]uint32, n)}
+	for i := range cs.Levels[2] {
+		cs.Levels[2][i] = uint32(n - i)
+	}
+
+	var buf bytes.Buffer
+	written, err := cs.WriteStreamTo(&buf)
+	assert.NoError(err)
+	assert.EqualValues(buf.Len(), written)
+
+	var reconstructed System
+	read, err := reconstructed.ReadStreamFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(cs.Instructions, reconstructed.Instructions)
+	assert.Equal(cs.CallData, reconstructed.CallData)
+	assert.Equal(cs.Levels, reconstructed.Levels)
+
+	// the encoding of WriteTo is rejected
+	data, err := cs.ToBytes()
+	assert.NoError(err)
+	_, err = new(System).ReadStreamFrom(bytes.NewReader(data))
+	assert.Error(err)
+
+	// truncated streams are rejected
+	_, err = new(System).ReadStreamFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+}
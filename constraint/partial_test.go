@@ -0,0 +1,88 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type partialCircuit struct {
+	Late   frontend.Variable `gnark:",public"`
+	Static frontend.Variable
+}
+
+func (c *partialCircuit) Define(api frontend.API) error {
+	// the static part is solved without the late input
+	inv, err := api.Compiler().NewHint(solver.InvZeroHint, 1, c.Static)
+	if err != nil {
+		return err
+	}
+	acc := api.Mul(inv[0], c.Static)
+	for i := 0; i < 5; i++ {
+		acc = api.Mul(acc, c.Static, i+2)
+	}
+	api.AssertIsDifferent(acc, 0)
+	api.AssertIsDifferent(api.Mul(acc, c.Late), 0)
+	return nil
+}
+
+func TestSolvePartial(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &partialCircuit{})
+		assert.NoError(err)
+		late := -1
+		for w := 0; w < ccs.GetNbPublicVariables(); w++ {
+			if ccs.VariableToString(w) == "Late" {
+				late = w
+			}
+		}
+		assert.NotEqual(-1, late)
+
+		placeholder, err := frontend.NewWitness(&partialCircuit{Late: 0, Static: 3}, field)
+		assert.NoError(err)
+		partial, err := ccs.SolvePartial(placeholder, []int{late})
+		assert.NoError(err)
+
+		solved := partial.SolvedWires()
+		assert.NotContains(solved, late)
+		assert.Greater(len(solved), ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables())
+		assert.Less(len(solved), ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables()+ccs.GetNbInternalVariables())
+		hints, constraints := partial.Blocking()
+		assert.Empty(hints)
+		assert.NotEmpty(constraints)
+
+		// finishing gives the solution of a full solve, for each late input
+		for _, l := range []int{5, 7} {
+			w, err := frontend.NewWitness(&partialCircuit{Late: l, Static: 3}, field)
+			assert.NoError(err)
+			expected, err := ccs.Solve(w)
+			assert.NoError(err)
+			finished, err := partial.Finish(w)
+			assert.NoError(err)
+			assert.Equal(expected, finished)
+		}
+
+		// unsatisfied with the late input
+		w, err := frontend.NewWitness(&partialCircuit{Late: 0, Static: 3}, field)
+		assert.NoError(err)
+		_, err = partial.Finish(w)
+		assert.Error(err)
+
+		// the known inputs can't change
+		w, err = frontend.NewWitness(&partialCircuit{Late: 5, Static: 4}, field)
+		assert.NoError(err)
+		_, err = partial.Finish(w)
+		assert.Error(err)
+
+		_, err = ccs.SolvePartial(placeholder, []int{ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables()})
+		assert.Error(err)
+	}
+}
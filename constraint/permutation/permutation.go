@@ -0,0 +1,242 @@
+// Package permutation builds the copy constraints of PLONK-like
+// arithmetizations, where the variables are laid out in the cells of a fixed
+// number of columns and the equality of the cells holding the same variable is
+// enforced by a permutation argument.
+//
+// The permutation σ is encoded as a slice of the positions of the cells, the
+// cells being numbered column by column: the i-th cell of the j-th column of
+// size n is at position j·n+i. The cells holding the same variable form a cycle
+// of σ, so the copy constraints hold if and only if the values of the cells are
+// invariant by σ. This is the encoding used by the PLONK backend.
+//
+// The invariance is proven by a grand-product argument: given a label for each
+// position and the challenges β, γ, the accumulator
+//
+//	Z(0) = 1
+//	Z(i+1) = Z(i)·∏ⱼ(vⱼ(i)+β·idⱼ(i)+γ) / ∏ⱼ(vⱼ(i)+β·idⱼ(σ(i))+γ)
+//
+// wraps around to 1 exactly when the products over all the positions are
+// equal, which holds, except with negligible probability over β and γ, if and
+// only if the values are invariant by σ. GrandProduct computes Z in the same
+// way as the PLONK prover (iop.BuildRatioCopyConstraint of gnark-crypto), and
+// CheckGrandProduct checks its relations.
+package permutation
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Build returns the permutation σ of the cells of the columns, where
+// columns[j][i] is the ID of the variable held by the i-th cell of the j-th
+// column, in [0, nbVariables). All the columns must have the same size.
+//
+// Each cell is sent to the previous cell holding the same variable, and the
+// first one to the last one, so that σ has one cycle per variable.
+func Build(columns [][]int, nbVariables int) ([]int64, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("no column")
+	}
+	n := len(columns[0])
+	for j := range columns {
+		if len(columns[j]) != n {
+			return nil, fmt.Errorf("column %d has size %d, expected %d", j, len(columns[j]), n)
+		}
+	}
+
+	// position -> variable ID
+	ids := make([]int, 0, len(columns)*n)
+	for j := range columns {
+		for _, id := range columns[j] {
+			if id < 0 || id >= nbVariables {
+				return nil, fmt.Errorf("variable %d out of range [0, %d)", id, nbVariables)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	permutation := make([]int64, len(ids))
+	for i := range permutation {
+		permutation[i] = -1
+	}
+
+	// variable ID -> last position the variable was seen
+	cycle := make([]int64, nbVariables)
+	for i := range cycle {
+		cycle[i] = -1
+	}
+	for i, id := range ids {
+		// if != -1, we already encountered the variable: link to the previous
+		// position.
+		permutation[i] = cycle[id]
+		cycle[id] = int64(i)
+	}
+
+	// close the cycles by sending the first positions to the last ones
+	for i, id := range ids {
+		if permutation[i] == -1 {
+			permutation[i] = cycle[id]
+		}
+	}
+	return permutation, nil
+}
+
+// Check returns an error if the values of the cells, numbered column by
+// column, are not invariant by the permutation, that is if the copy
+// constraints don't hold.
+func Check[E comparable](permutation []int64, values []E) error {
+	if len(permutation) != len(values) {
+		return fmt.Errorf("got %d values for a permutation of size %d", len(values), len(permutation))
+	}
+	for i, s := range permutation {
+		if s < 0 || s >= int64(len(values)) {
+			return fmt.Errorf("position %d is sent out of range to %d", i, s)
+		}
+		if values[i] != values[s] {
+			return fmt.Errorf("copy constraint between positions %d and %d doesn't hold", i, s)
+		}
+	}
+	return nil
+}
+
+// Element is implemented by the pointers to the field elements of gnark-crypto,
+// for instance *fr.Element.
+type Element[E any] interface {
+	*E
+	SetOne() *E
+	Add(x, y *E) *E
+	Mul(x, y *E) *E
+	Inverse(x *E) *E
+	IsZero() bool
+	Equal(x *E) bool
+}
+
+// GrandProduct returns the evaluations of the accumulator Z of the
+// grand-product argument for the permutation σ, given the values of the cells
+// and their labels, column by column as in Build. For the PLONK backend, the
+// label of the i-th cell of the j-th column is ωⁱ·uʲ, where ω generates the
+// domain of size n and u is the coset shift.
+//
+// It returns an error if a factor of the denominators is zero, which happens
+// with negligible probability over the challenges.
+func GrandProduct[E any, PE Element[E]](permutation []int64, values, labels [][]E, beta, gamma E) ([]E, error) {
+	n, err := checkSizes(permutation, values, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	// z[i+1] is first the product of the numerators of the rows up to i, and
+	// prefix[i] the product of the denominators d[0..i].
+	z := make([]E, n)
+	d := make([]E, n)
+	prefix := make([]E, n)
+	PE(&z[0]).SetOne()
+	var num E
+	for i := 0; i < n-1; i++ {
+		ratio[E, PE](&num, &d[i], permutation, values, labels, &beta, &gamma, i)
+		if i == 0 {
+			z[1], prefix[0] = num, d[0]
+		} else {
+			PE(&z[i+1]).Mul(&z[i], &num)
+			PE(&prefix[i]).Mul(&prefix[i-1], &d[i])
+		}
+	}
+	if n == 1 {
+		return z, nil
+	}
+
+	// batch inversion: the inverse of prefix[i-1] is the inverse of prefix[i]
+	// times d[i].
+	if PE(&prefix[n-2]).IsZero() {
+		return nil, errors.New("zero denominator in the grand product")
+	}
+	var inv E
+	PE(&inv).Inverse(&prefix[n-2])
+	for i := n - 2; i >= 0; i-- {
+		PE(&z[i+1]).Mul(&z[i+1], &inv)
+		PE(&inv).Mul(&inv, &d[i])
+	}
+	return z, nil
+}
+
+// CheckGrandProduct returns an error if z is not the accumulator of the
+// grand-product argument for the permutation, the values and the labels, see
+// GrandProduct. It checks that z starts at 1 and the relation between its
+// successive evaluations, including the wrap-around from the last one to the
+// first one, which fails when the values are not invariant by σ.
+func CheckGrandProduct[E any, PE Element[E]](z []E, permutation []int64, values, labels [][]E, beta, gamma E) error {
+	n, err := checkSizes(permutation, values, labels)
+	if err != nil {
+		return err
+	}
+	if len(z) != n {
+		return fmt.Errorf("got %d evaluations of the accumulator, expected %d", len(z), n)
+	}
+	var one E
+	PE(&one).SetOne()
+	if !PE(&z[0]).Equal(&one) {
+		return errors.New("the accumulator doesn't start at 1")
+	}
+	var num, den, lhs, rhs E
+	for i := 0; i < n; i++ {
+		// Z(i+1)·∏ⱼ(vⱼ(i)+β·idⱼ(σ(i))+γ) = Z(i)·∏ⱼ(vⱼ(i)+β·idⱼ(i)+γ)
+		ratio[E, PE](&num, &den, permutation, values, labels, &beta, &gamma, i)
+		PE(&lhs).Mul(&z[(i+1)%n], &den)
+		PE(&rhs).Mul(&z[i], &num)
+		if !PE(&lhs).Equal(&rhs) {
+			return fmt.Errorf("the accumulator relation doesn't hold at row %d", i)
+		}
+	}
+	return nil
+}
+
+// checkSizes returns the size of the columns, after checking that the values
+// and the labels are columns of the same size, with as many cells as the
+// permutation.
+func checkSizes[E any](permutation []int64, values, labels [][]E) (int, error) {
+	if len(values) == 0 {
+		return 0, errors.New("no column")
+	}
+	if len(labels) != len(values) {
+		return 0, fmt.Errorf("got %d columns of labels, expected %d", len(labels), len(values))
+	}
+	n := len(values[0])
+	if n == 0 {
+		return 0, errors.New("empty columns")
+	}
+	for j := range values {
+		if len(values[j]) != n || len(labels[j]) != n {
+			return 0, fmt.Errorf("column %d has size %d and %d labels, expected %d", j, len(values[j]), len(labels[j]), n)
+		}
+	}
+	if len(permutation) != len(values)*n {
+		return 0, fmt.Errorf("got %d cells for a permutation of size %d", len(values)*n, len(permutation))
+	}
+	for i, s := range permutation {
+		if s < 0 || s >= int64(len(permutation)) {
+			return 0, fmt.Errorf("position %d is sent out of range to %d", i, s)
+		}
+	}
+	return n, nil
+}
+
+// ratio sets num and den to the numerator ∏ⱼ(vⱼ(i)+β·idⱼ(i)+γ) and the
+// denominator ∏ⱼ(vⱼ(i)+β·idⱼ(σ(i))+γ) of the i-th row.
+func ratio[E any, PE Element[E]](num, den *E, permutation []int64, values, labels [][]E, beta, gamma *E, i int) {
+	n := int64(len(values[0]))
+	PE(num).SetOne()
+	PE(den).SetOne()
+	var t E
+	for j := range values {
+		PE(&t).Mul(beta, &labels[j][i])
+		PE(&t).Add(&t, gamma)
+		PE(&t).Add(&t, &values[j][i])
+		PE(num).Mul(num, &t)
+
+		s := permutation[int64(j)*n+int64(i)]
+		PE(&t).Mul(beta, &labels[s/n][s%n])
+		PE(&t).Add(&t, gamma)
+		PE(&t).Add(&t, &values[j][i])
+		PE(den).Mul(den, &t)
+	}
+}
@@ -0,0 +1,121 @@
+package permutation
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	assert := require.New(t)
+
+	// positions 0..3 | 4..7 | 8..11
+	columns := [][]int{
+		{0, 1, 2, 3},
+		{1, 1, 4, 0},
+		{2, 5, 3, 3},
+	}
+	s, err := Build(columns, 6)
+	assert.NoError(err)
+	// one cycle per variable, each position sent to the previous occurrence
+	assert.Equal([]int64{
+		7, 5, 8, 11,
+		1, 4, 6, 0,
+		2, 9, 3, 10,
+	}, s)
+
+	values := []string{"a", "b", "c", "d", "b", "b", "e", "a", "c", "f", "d", "d"}
+	assert.NoError(Check(s, values))
+	values[10] = "x"
+	assert.Error(Check(s, values))
+
+	_, err = Build([][]int{{0, 1}, {0}}, 2)
+	assert.Error(err, "columns of different sizes")
+	_, err = Build([][]int{{0, 2}}, 2)
+	assert.Error(err, "variable out of range")
+}
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestGrandProduct(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	spr := ccs.(*cs.SparseR1CS)
+	w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_solution, err := spr.Solve(w)
+	assert.NoError(err)
+	solution := _solution.(*cs.SparseR1CSSolution)
+	n := len(solution.L)
+	domain := fft.NewDomain(uint64(n))
+
+	// the wires of the constraints, after the placeholders of the public
+	// inputs, as laid out by the PLONK backend
+	columns := [][]int{make([]int, n), make([]int, n), make([]int, n)}
+	for i := range spr.Public {
+		columns[0][i] = i
+	}
+	it := spr.GetSparseR1CIterator()
+	for i, c := len(spr.Public), it.Next(); c != nil; i, c = i+1, it.Next() {
+		columns[0][i], columns[1][i], columns[2][i] = int(c.XA), int(c.XB), int(c.XC)
+	}
+	s, err := Build(columns, spr.NbInternalVariables+len(spr.Public)+len(spr.Secret))
+	assert.NoError(err)
+
+	// the labels ωⁱ·uʲ
+	labels := make([][]fr.Element, 3)
+	var shift fr.Element
+	shift.SetOne()
+	for j := range labels {
+		labels[j] = make([]fr.Element, n)
+		labels[j][0].Set(&shift)
+		for i := 1; i < n; i++ {
+			labels[j][i].Mul(&labels[j][i-1], &domain.Generator)
+		}
+		shift.Mul(&shift, &domain.FrMultiplicativeGen)
+	}
+
+	var beta, gamma fr.Element
+	_, err = beta.SetRandom()
+	assert.NoError(err)
+	_, err = gamma.SetRandom()
+	assert.NoError(err)
+	values := [][]fr.Element{solution.L, solution.R, solution.O}
+	z, err := GrandProduct(s, values, labels, beta, gamma)
+	assert.NoError(err)
+	assert.NoError(CheckGrandProduct(z, s, values, labels, beta, gamma))
+
+	// Z of the PLONK prover
+	form := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	expected, err := iop.BuildRatioCopyConstraint([]*iop.Polynomial{
+		iop.NewPolynomial(&values[0], form),
+		iop.NewPolynomial(&values[1], form),
+		iop.NewPolynomial(&values[2], form),
+	}, s, beta, gamma, form, domain)
+	assert.NoError(err)
+	assert.Equal(expected.Coefficients(), z)
+
+	// a copy constraint is broken
+	values[2][n-1].SetUint64(42)
+	z, err = GrandProduct(s, values, labels, beta, gamma)
+	assert.NoError(err)
+	assert.Error(CheckGrandProduct(z, s, values, labels, beta, gamma))
+}
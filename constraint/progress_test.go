@@ -0,0 +1,57 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type progressCircuit struct {
+	X frontend.Variable
+}
+
+func (c *progressCircuit) Define(api frontend.API) error {
+	// a wide level, solved in parallel, then a chain of small levels
+	acc := frontend.Variable(0)
+	for i := 0; i < 200; i++ {
+		acc = api.Add(acc, api.Mul(c.X, c.X, i+1))
+	}
+	for i := 0; i < 10; i++ {
+		acc = api.Mul(acc, acc)
+	}
+	api.AssertIsDifferent(acc, 0)
+	return nil
+}
+
+func TestProgress(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &progressCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&progressCircuit{X: 3}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	nbWires := uint64(ccs.GetNbInternalVariables() + ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables())
+	for _, opts := range [][]solver.Option{
+		{solver.WithNbTasks(4)},
+		{solver.WithInstructionHook(func(int, solver.State, error) error { return nil })},
+	} {
+		var solved []uint64
+		opts = append(opts, solver.WithProgress(func(s, total uint64) {
+			assert.Equal(nbWires, total)
+			solved = append(solved, s)
+		}))
+		_, err = ccs.Solve(w, opts...)
+		assert.NoError(err)
+
+		assert.Greater(len(solved), 10, "one call per level")
+		for i := 1; i < len(solved); i++ {
+			assert.LessOrEqual(solved[i-1], solved[i])
+		}
+		assert.Equal(nbWires, solved[len(solved)-1])
+	}
+}
@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"fmt"
 	"hash/fnv"
 	"math/big"
 	"reflect"
@@ -93,10 +94,17 @@ type HintID uint32
 //
 // In the init() method of the gadget, call the method [RegisterHint] function on
 // the hint function hintFn to register a hint function in the package registry.
+// The ID of a hint registered this way is derived from the name of the Go
+// function, so renaming or moving the function breaks the serialized constraint
+// systems using it. [RegisterVersionedHint] registers the hint under a stable
+// namespaced name instead, and records its version in the constraint systems.
 type Hint func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
 
 // GetHintID returns the derived hint ID from the hint function reference.
 func GetHintID(fn Hint) HintID {
+	if vh, ok := getVersionedHint(fn); ok {
+		return versionedHintID(vh)
+	}
 	hf := fnv.New32a()
 	name := GetHintName(fn)
 
@@ -110,8 +118,12 @@ func GetHintID(fn Hint) HintID {
 // GetHintName returns the derived hint name from the hint function reference.
 // By default, it is the fully qualified name of the function. If the function
 // is anonymous, then it is the fully qualified name of the package and the
-// function index.
+// function index. If the function was registered with
+// [RegisterVersionedHint], then it is its namespaced name and version.
 func GetHintName(fn Hint) string {
+	if vh, ok := getVersionedHint(fn); ok {
+		return fmt.Sprintf("%s@v%d", vh.name, vh.version)
+	}
 	fnptr := reflect.ValueOf(fn).Pointer()
 	name := runtime.FuncForPC(fnptr).Name()
 	return newToOldStyle(name)
@@ -0,0 +1,102 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrHintTimeout is the error of a hint which doesn't return within the
+// timeout set with [WithHintTimeout].
+var ErrHintTimeout = errors.New("timed out")
+
+// HintError is returned by the solver when a hint panics, or doesn't return
+// within the timeout set with [WithHintTimeout]. The errors returned by the
+// hints are returned unchanged.
+type HintError struct {
+	Hint string // name of the hint, as recorded in the constraint system
+	ID   HintID
+	Err  error // ErrHintTimeout, or the value of the panic
+}
+
+func (e *HintError) Error() string {
+	return fmt.Sprintf("hint %s: %s", e.Hint, e.Err)
+}
+
+func (e *HintError) Unwrap() error {
+	return e.Err
+}
+
+// WithHintTimeout is a solver option that makes the solving fail with a
+// [HintError] when a hint doesn't return within timeout, instead of waiting
+// for it. The hints are then called in a new go routine, on copies of their
+// inputs and outputs, and a hint which times out keeps running in the
+// background until it returns, as go routines can't be interrupted.
+func WithHintTimeout(timeout time.Duration) Option {
+	return func(opt *Config) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid hint timeout: %s", timeout)
+		}
+		opt.HintTimeout = timeout
+		return nil
+	}
+}
+
+// GuardHints returns the hint functions with the hints the constraint system
+// depends on wrapped to return a [HintError] when they panic, instead of
+// crashing the solver, and when they don't return within timeout if it isn't
+// zero, see [WithHintTimeout]. names are the names of the hints recorded in
+// the constraint system.
+func GuardHints(names map[HintID]string, hintFunctions map[HintID]Hint, timeout time.Duration) map[HintID]Hint {
+	res := cloneMap(hintFunctions)
+	for id, name := range names {
+		f, ok := hintFunctions[id]
+		if !ok {
+			continue
+		}
+		id, name := id, name
+		recovered := func(field *big.Int, inputs []*big.Int, outputs []*big.Int) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &HintError{Hint: name, ID: id, Err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+			return f(field, inputs, outputs)
+		}
+		if timeout == 0 {
+			res[id] = recovered
+			continue
+		}
+		res[id] = func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+			// the arguments are reused by the solver, while the hint may
+			// still run after the timeout
+			field, inputs, outs := new(big.Int).Set(field), copyBigInts(inputs), copyBigInts(outputs)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- recovered(field, inputs, outs)
+			}()
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case err := <-done:
+				for i := range outputs {
+					outputs[i].Set(outs[i])
+				}
+				return err
+			case <-timer.C:
+				return &HintError{Hint: name, ID: id, Err: fmt.Errorf("%w after %s", ErrHintTimeout, timeout)}
+			}
+		}
+	}
+	return res
+}
+
+func copyBigInts(values []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(values))
+	for i := range values {
+		res[i] = new(big.Int).Set(values[i])
+	}
+	return res
+}
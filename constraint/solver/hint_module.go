@@ -0,0 +1,141 @@
+package solver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"sync"
+)
+
+// HintModules are the WebAssembly modules implementing hints, keyed by the ID
+// of the hint (see [GetHintID]). They are shipped with a serialized constraint
+// system, so that its witnesses can be solved without the Go code of the
+// hints, with a [ModuleSandbox].
+//
+// The module of a hint exports a function under the name of the hint, with
+// the encoding of the inputs and outputs of [SandboxedHint].
+type HintModules map[HintID][]byte
+
+// wasmHeader is the magic number and the version of the WebAssembly binary
+// format.
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// ModuleSandbox is a [HintSandbox] executing each hint in an instance of its
+// module, to give to the solver with [WithHintSandbox].
+type ModuleSandbox struct {
+	modules     HintModules
+	instantiate func(module []byte) (HintSandbox, error)
+
+	mu        sync.Mutex
+	instances map[HintID]HintSandbox
+}
+
+// NewModuleSandbox returns a sandbox executing the hints with a module in
+// modules. The module of a hint is instantiated on its first call with
+// instantiate, which is implemented with a WebAssembly runtime with the
+// restrictions described in [HintSandbox]. Package hintwasm implements it with
+// the wazero runtime.
+func NewModuleSandbox(modules HintModules, instantiate func(module []byte) (HintSandbox, error)) (*ModuleSandbox, error) {
+	for id, module := range modules {
+		if !bytes.HasPrefix(module, wasmHeader) {
+			return nil, fmt.Errorf("module of hint %d is not a WebAssembly module", id)
+		}
+	}
+	return &ModuleSandbox{
+		modules:     modules,
+		instantiate: instantiate,
+		instances:   make(map[HintID]HintSandbox),
+	}, nil
+}
+
+// Call implements [HintSandbox], calling the hint in the instance of its
+// module.
+func (s *ModuleSandbox) Call(name string, input []byte) ([]byte, error) {
+	id := hintIDOfName(name)
+	s.mu.Lock()
+	instance, ok := s.instances[id]
+	if !ok {
+		module, ok := s.modules[id]
+		if !ok {
+			s.mu.Unlock()
+			return nil, errors.New("no module for the hint")
+		}
+		var err error
+		if instance, err = s.instantiate(module); err != nil {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("instantiate module: %w", err)
+		}
+		s.instances[id] = instance
+	}
+	s.mu.Unlock()
+	return instance.Call(name, input)
+}
+
+// hintIDOfName returns the ID of the hint with the name recorded in the
+// constraint system, as computed by [GetHintID].
+func hintIDOfName(name string) HintID {
+	if vh, ok := parseHintName(name); ok {
+		return versionedHintID(vh)
+	}
+	hf := fnv.New32a()
+	hf.Write([]byte(name)) // #nosec G104 -- does not err
+	return HintID(hf.Sum32())
+}
+
+// WriteTo writes the modules, by increasing hint ID, as the number of modules
+// followed by the ID, the byte length and the bytes of each module, with the
+// integers as 4-byte big-endian integers.
+func (m HintModules) WriteTo(w io.Writer) (int64, error) {
+	ids := make([]HintID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var buf bytes.Buffer
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(ids))))
+	for _, id := range ids {
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(id)))
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(m[id]))))
+		buf.Write(m[id])
+	}
+	return buf.WriteTo(w)
+}
+
+// ReadFrom reads modules written by [HintModules.WriteTo] and adds them to m.
+func (m HintModules) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	var b [4]byte
+	readUint32 := func() (uint32, error) {
+		read, err := io.ReadFull(r, b[:])
+		n += int64(read)
+		return binary.BigEndian.Uint32(b[:]), err
+	}
+	nbModules, err := readUint32()
+	if err != nil {
+		return n, err
+	}
+	for i := uint32(0); i < nbModules; i++ {
+		id, err := readUint32()
+		if err != nil {
+			return n, err
+		}
+		size, err := readUint32()
+		if err != nil {
+			return n, err
+		}
+		// the size is not trusted: the module is read without preallocating
+		var module bytes.Buffer
+		read, err := io.CopyN(&module, r, int64(size))
+		n += read
+		if err != nil {
+			return n, fmt.Errorf("module of hint %d: %w", id, err)
+		}
+		m[HintID(id)] = module.Bytes()
+	}
+	return n, nil
+}
@@ -0,0 +1,140 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// HintProvider computes hints out of process, for example in a service with
+// access to a database, a signing service or a large lookup table, so that the
+// hints don't need to be registered in the solving process. See
+// [WithHintProvider], and the package
+// [github.com/consensys/gnark/constraint/solver/remote] for a provider served
+// over HTTP.
+type HintProvider interface {
+	// Hint computes the outputs of the calls, which are independent. The
+	// outputs are allocated and set to 0 by the caller.
+	Hint(ctx context.Context, field *big.Int, calls []HintCall) error
+}
+
+// HintCall is a call of a hint computed by a [HintProvider].
+type HintCall struct {
+	Name    string // name of the hint, as recorded in the constraint system
+	ID      HintID
+	Inputs  []*big.Int
+	Outputs []*big.Int
+}
+
+// WithHintProvider is a solver option that computes the hints of the
+// constraint system which are not registered nor provided with [WithHints]
+// with the provider, under the name recorded in the constraint system (see
+// [GetHintName]). It takes precedence over [WithHintSandbox].
+//
+// The inputs of the hints of a level of the constraint system are solved by
+// the previous levels, so that the solver calls the provider once per level
+// with all the calls of the level, instead of once per call. The provider is
+// called with the context given with [WithContext], or with
+// [context.Background].
+func WithHintProvider(provider HintProvider) Option {
+	return func(opt *Config) error {
+		opt.HintProvider = provider
+		return nil
+	}
+}
+
+// RemoteHints computes the hints of a solve with a [HintProvider], see
+// [WithHintProvider]. The solver computes the calls of a level with
+// [RemoteHints.Prefetch] before solving it, and the hint functions returned
+// by [RemoteHints.Hint] return the prefetched outputs.
+type RemoteHints struct {
+	ctx      context.Context
+	provider HintProvider
+	names    map[HintID]string
+
+	mu      sync.Mutex
+	outputs map[string][][]*big.Int // prefetched, by hint and inputs
+}
+
+// NewRemoteHints returns the remote hints of a solve calling provider with
+// ctx.
+func NewRemoteHints(ctx context.Context, provider HintProvider) *RemoteHints {
+	return &RemoteHints{
+		ctx:      ctx,
+		provider: provider,
+		names:    make(map[HintID]string),
+		outputs:  make(map[string][][]*big.Int),
+	}
+}
+
+// Hint returns the hint function computing the hint with the given ID and
+// name with the provider. It returns the outputs prefetched for its inputs, if
+// any, and calls the provider otherwise.
+func (r *RemoteHints) Hint(id HintID, name string) Hint {
+	r.names[id] = name
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		key := replayKey(id, toDecimal(inputs))
+		r.mu.Lock()
+		prefetched := r.outputs[key]
+		if len(prefetched) > 0 {
+			r.outputs[key] = prefetched[1:]
+			if len(prefetched) == 1 {
+				delete(r.outputs, key)
+			}
+		}
+		r.mu.Unlock()
+		if len(prefetched) > 0 {
+			for i := range outputs {
+				outputs[i].Set(prefetched[0][i])
+			}
+			return nil
+		}
+		return r.call(field, []HintCall{{Name: name, ID: id, Inputs: inputs, Outputs: outputs}})
+	}
+}
+
+// IsRemote returns true if the hint with the given ID is computed by the
+// provider.
+func (r *RemoteHints) IsRemote(id HintID) bool {
+	_, ok := r.names[id]
+	return ok
+}
+
+// Prefetch computes the calls in one call of the provider, for the hint
+// functions to return their outputs. The names of the calls are set from
+// their IDs.
+func (r *RemoteHints) Prefetch(field *big.Int, calls []HintCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	for i := range calls {
+		calls[i].Name = r.names[calls[i].ID]
+	}
+	if err := r.call(field, calls); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range calls {
+		key := replayKey(c.ID, toDecimal(c.Inputs))
+		r.outputs[key] = append(r.outputs[key], c.Outputs)
+	}
+	return nil
+}
+
+// call calls the provider and checks that the outputs are reduced, as the
+// provider is not trusted to return field elements.
+func (r *RemoteHints) call(field *big.Int, calls []HintCall) error {
+	if err := r.provider.Hint(r.ctx, field, calls); err != nil {
+		return fmt.Errorf("hint provider: %w", err)
+	}
+	for _, c := range calls {
+		for i, o := range c.Outputs {
+			if o.Sign() < 0 || o.Cmp(field) >= 0 {
+				return fmt.Errorf("hint provider: hint %s: output %d not reduced", c.Name, i)
+			}
+		}
+	}
+	return nil
+}
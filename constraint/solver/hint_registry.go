@@ -9,7 +9,7 @@ import (
 )
 
 func init() {
-	RegisterHint(InvZeroHint)
+	RegisterHint(InvZeroHint, ResolveHint)
 }
 
 var (
@@ -0,0 +1,93 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// ReadHintTrace reads the trace written by a solver with [WithHintTrace].
+func ReadHintTrace(r io.Reader) ([]HintTraceEntry, error) {
+	var res []HintTraceEntry
+	dec := json.NewDecoder(r)
+	for {
+		var entry HintTraceEntry
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, fmt.Errorf("entry %d: %w", len(res), err)
+		}
+		res = append(res, entry)
+	}
+}
+
+// WithHintReplay is a solver option that replays the hint outputs of a
+// previous solve, recorded with [WithHintTrace] and read with [ReadHintTrace],
+// instead of calling the hints. It makes the solution reproducible even with
+// nondeterministic hints, and the hints don't need to be registered, so that a
+// witness can be solved on one machine and solved again for proving on another
+// one.
+//
+// A hint call is matched with a recorded call of the same hint with the same
+// inputs, and the solver fails if there is none. The recorded calls of a hint
+// with identical inputs are replayed in the order of the trace.
+func WithHintReplay(trace []HintTraceEntry) Option {
+	return func(opt *Config) error {
+		r := &HintReplay{outputs: make(map[string][][]*big.Int)}
+		for i, entry := range trace {
+			if entry.Error != "" {
+				continue
+			}
+			outputs := make([]*big.Int, len(entry.Outputs))
+			for j := range entry.Outputs {
+				var ok bool
+				if outputs[j], ok = new(big.Int).SetString(entry.Outputs[j], 10); !ok {
+					return fmt.Errorf("entry %d: invalid output %q", i, entry.Outputs[j])
+				}
+			}
+			key := replayKey(entry.ID, entry.Inputs)
+			r.outputs[key] = append(r.outputs[key], outputs)
+		}
+		opt.HintReplay = r
+		return nil
+	}
+}
+
+// HintReplay holds the recorded hint outputs of a solve, see [WithHintReplay].
+type HintReplay struct {
+	mu      sync.Mutex
+	outputs map[string][][]*big.Int
+}
+
+// Hint returns the hint function replaying the recorded calls of the hint. name
+// is the name of the hint, for the errors.
+func (r *HintReplay) Hint(id HintID, name string) Hint {
+	return func(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		key := replayKey(id, toDecimal(inputs))
+		r.mu.Lock()
+		recorded := r.outputs[key]
+		if len(recorded) == 0 {
+			r.mu.Unlock()
+			return fmt.Errorf("hint %s: no recorded call with inputs %v", name, inputs)
+		}
+		r.outputs[key] = recorded[1:]
+		r.mu.Unlock()
+
+		if len(recorded[0]) != len(outputs) {
+			return fmt.Errorf("hint %s: %d recorded outputs, expected %d", name, len(recorded[0]), len(outputs))
+		}
+		for i := range outputs {
+			outputs[i].Set(recorded[0][i])
+		}
+		return nil
+	}
+}
+
+func replayKey(id HintID, inputs []string) string {
+	return fmt.Sprintf("%d:%s", id, strings.Join(inputs, ","))
+}
@@ -0,0 +1,94 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// Resolver gives the resolver hints access to data which is not part of the
+// witness, for example a database of Merkle paths or an oracle, so that the
+// witness of a circuit can be pulled on demand during solving. It is defined
+// by the application, and the resolver hints convert it to the type they
+// expect. See [WithResolver].
+type Resolver interface{}
+
+// ResolverHint is a hint with access to the context and to the resolver given
+// to the solver with [WithResolver]. The hint should abort when the context is
+// done, for example by passing it to the requests it makes. As any hint, its
+// outputs are not constrained: the circuit must check the data it pulls.
+//
+// A resolver hint is registered with [RegisterResolverHint] and called in the
+// circuit through [ResolveHint] (see frontend.NewResolverHint).
+type ResolverHint func(ctx context.Context, resolver Resolver, field *big.Int, inputs []*big.Int, outputs []*big.Int) error
+
+var (
+	resolverHints  = make(map[HintID]ResolverHint)
+	resolverHintsM sync.RWMutex
+)
+
+// RegisterResolverHint registers resolver hints in the global registry. As for
+// [RegisterHint], the ID of a resolver hint is derived from the name of the Go
+// function.
+func RegisterResolverHint(hintFns ...ResolverHint) {
+	resolverHintsM.Lock()
+	defer resolverHintsM.Unlock()
+	for _, hintFn := range hintFns {
+		resolverHints[GetResolverHintID(hintFn)] = hintFn
+	}
+}
+
+// GetResolverHintID returns the ID of the resolver hint, which is the first
+// input of [ResolveHint].
+func GetResolverHintID(fn ResolverHint) HintID {
+	name := newToOldStyle(runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name())
+	hf := fnv.New32a()
+	hf.Write([]byte(name)) // #nosec G104 -- does not err
+	return HintID(hf.Sum32())
+}
+
+// ResolveHint calls a resolver hint. Its first input is the ID of the resolver
+// hint (see [GetResolverHintID]), and the next ones are the inputs of the
+// resolver hint. The resolver hint is called with the context and the
+// resolver given with [WithResolver], or with [context.Background] and a nil
+// resolver if the option isn't set.
+func ResolveHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	return resolve(context.Background(), nil, field, inputs, outputs)
+}
+
+// BindResolver returns a hint replacing [ResolveHint], which calls the
+// resolver hints with the given context and resolver. It is set by
+// [WithResolver], and can be given to solvers which don't take solver options.
+func BindResolver(ctx context.Context, resolver Resolver) Hint {
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		return resolve(ctx, resolver, field, inputs, outputs)
+	}
+}
+
+// WithResolver is a solver option which gives the context and the resolver to
+// the resolver hints. Solving fails with the error of the context if it is
+// done before a resolver hint is called.
+func WithResolver(ctx context.Context, resolver Resolver) Option {
+	return OverrideHint(GetHintID(ResolveHint), BindResolver(ctx, resolver))
+}
+
+func resolve(ctx context.Context, resolver Resolver, field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) == 0 || !inputs[0].IsUint64() {
+		return fmt.Errorf("resolve hint: missing resolver hint ID")
+	}
+	id := HintID(inputs[0].Uint64())
+	resolverHintsM.RLock()
+	f, ok := resolverHints[id]
+	resolverHintsM.RUnlock()
+	if !ok {
+		return fmt.Errorf("resolve hint: resolver hint %d is not registered", id)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f(ctx, resolver, field, inputs[1:], outputs)
+}
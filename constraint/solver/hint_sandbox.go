@@ -0,0 +1,74 @@
+package solver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// HintSandbox executes the hints of untrusted modules, typically WebAssembly
+// modules run by a WASM runtime, so that the solver doesn't execute arbitrary
+// native code for them. See [WithHintSandbox].
+//
+// A WASM runtime implements it by instantiating the module without imports (no
+// WASI, file system or network access), with bounded memory and execution
+// time, and by calling the function exported under the name of the hint with
+// the encoded input, see [SandboxedHint] for the encoding.
+type HintSandbox interface {
+	// Call calls the function of the module implementing the hint with the
+	// given name. It returns the encoded outputs.
+	Call(name string, input []byte) (output []byte, err error)
+}
+
+// WithHintSandbox is a solver option that executes the hints of the constraint
+// system which are not registered nor provided with [WithHints] in the sandbox,
+// under the name recorded in the constraint system (see [GetHintName]).
+func WithHintSandbox(sandbox HintSandbox) Option {
+	return func(opt *Config) error {
+		opt.HintSandbox = sandbox
+		return nil
+	}
+}
+
+// SandboxedHint returns a hint function calling the hint with the given name in
+// the sandbox.
+//
+// The input of the call is, with the integers in big-endian order:
+//   - the byte length n of the modulus, the number of inputs and the number of
+//     outputs, as 4-byte integers;
+//   - the modulus, on n bytes;
+//   - the inputs, on n bytes each.
+//
+// The output of the call must be the outputs, on n bytes each. The outputs are
+// checked to be reduced modulo the modulus, as the module is not trusted.
+func SandboxedHint(sandbox HintSandbox, name string) Hint {
+	return func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+		n := (field.BitLen() + 7) / 8
+		input := make([]byte, 12+n*(1+len(inputs)))
+		binary.BigEndian.PutUint32(input[0:4], uint32(n))
+		binary.BigEndian.PutUint32(input[4:8], uint32(len(inputs)))
+		binary.BigEndian.PutUint32(input[8:12], uint32(len(outputs)))
+		field.FillBytes(input[12 : 12+n])
+		for i, in := range inputs {
+			if in.Sign() < 0 || in.Cmp(field) >= 0 {
+				return fmt.Errorf("sandboxed hint %s: input %d not reduced", name, i)
+			}
+			in.FillBytes(input[12+n*(i+1) : 12+n*(i+2)])
+		}
+
+		output, err := sandbox.Call(name, input)
+		if err != nil {
+			return fmt.Errorf("sandboxed hint %s: %w", name, err)
+		}
+		if len(output) != n*len(outputs) {
+			return fmt.Errorf("sandboxed hint %s: output of %d bytes, expected %d", name, len(output), n*len(outputs))
+		}
+		for i := range outputs {
+			outputs[i].SetBytes(output[i*n : (i+1)*n])
+			if outputs[i].Cmp(field) >= 0 {
+				return fmt.Errorf("sandboxed hint %s: output %d not reduced", name, i)
+			}
+		}
+		return nil
+	}
+}
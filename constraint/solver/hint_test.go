@@ -1,6 +1,12 @@
 package solver
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
 
 func TestRegexpRename(t *testing.T) {
 	for i, v := range []struct{ input, expected string }{
@@ -17,3 +23,145 @@ func TestRegexpRename(t *testing.T) {
 	}
 
 }
+
+func versionedTestHintV1(_ *big.Int, inputs, outputs []*big.Int) error {
+	outputs[0].Set(inputs[0])
+	return nil
+}
+
+func versionedTestHintV2(_ *big.Int, inputs, outputs []*big.Int) error {
+	outputs[0].Neg(inputs[0])
+	return nil
+}
+
+func TestVersionedHint(t *testing.T) {
+	RegisterVersionedHint("gnark/constraint/solver/testHint", 1, versionedTestHintV1)
+	// registering twice is a no-op
+	RegisterVersionedHint("gnark/constraint/solver/testHint", 1, versionedTestHintV1)
+
+	name := GetHintName(versionedTestHintV1)
+	if name != "gnark/constraint/solver/testHint@v1" {
+		t.Fatalf("unexpected name %s", name)
+	}
+	id := GetHintID(versionedTestHintV1)
+	if GetRegisteredHint(id) == nil {
+		t.Fatal("hint not registered")
+	}
+	if err := CheckHintVersion(name, versionedTestHintV1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the other version of the hint has the same ID, but a mismatching
+	// version. It can't be registered globally along the first one.
+	versionedHintsM.Lock()
+	versionedHints[reflect.ValueOf(versionedTestHintV2).Pointer()] = versionedHint{name: "gnark/constraint/solver/testHint", version: 2}
+	versionedHintsM.Unlock()
+	if GetHintID(versionedTestHintV2) != id {
+		t.Fatal("the ID depends on the version")
+	}
+	if err := CheckHintVersion(name, versionedTestHintV2); err == nil {
+		t.Fatal("expected version mismatch")
+	}
+
+	// unversioned hints are not checked
+	if err := CheckHintVersion(name, InvZeroHint); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckHintVersion(GetHintName(InvZeroHint), versionedTestHintV2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVersionedHintInvalidName(t *testing.T) {
+	for _, name := range []string{"", "gnark/hint@v1"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for name %q", name)
+				}
+			}()
+			RegisterVersionedHint(name, 1, versionedTestHintV2)
+		}()
+	}
+}
+
+type echoSandbox struct {
+	output []byte
+}
+
+func (s echoSandbox) Call(name string, input []byte) ([]byte, error) {
+	return s.output, nil
+}
+
+func TestSandboxedHintOutput(t *testing.T) {
+	field := big.NewInt(251)
+	inputs := []*big.Int{big.NewInt(1)}
+	outputs := []*big.Int{new(big.Int), new(big.Int)}
+	for _, c := range []struct {
+		output []byte
+		valid  bool
+	}{
+		{[]byte{1, 250}, true},
+		{[]byte{1}, false},
+		{[]byte{1, 2, 3}, false},
+		{[]byte{1, 251}, false},
+	} {
+		err := SandboxedHint(echoSandbox{c.output}, "echo")(field, inputs, outputs)
+		if c.valid != (err == nil) {
+			t.Errorf("output %v: unexpected error %v", c.output, err)
+		}
+		if c.valid && (outputs[0].Int64() != 1 || outputs[1].Int64() != 250) {
+			t.Errorf("unexpected outputs %v", outputs)
+		}
+	}
+}
+
+type testResolver map[uint64]uint64
+
+func resolverTestHint(ctx context.Context, resolver Resolver, _ *big.Int, inputs, outputs []*big.Int) error {
+	r, ok := resolver.(testResolver)
+	if !ok {
+		return errors.New("unexpected resolver")
+	}
+	v, ok := r[inputs[0].Uint64()]
+	if !ok {
+		return errors.New("unknown key")
+	}
+	outputs[0].SetUint64(v)
+	return ctx.Err()
+}
+
+func TestResolverHint(t *testing.T) {
+	RegisterResolverHint(resolverTestHint)
+	id := new(big.Int).SetUint64(uint64(GetResolverHintID(resolverTestHint)))
+	field := big.NewInt(101)
+	outputs := []*big.Int{new(big.Int)}
+
+	cfg, err := NewConfig(WithResolver(context.Background(), testResolver{3: 42}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hint := cfg.HintFunctions[GetHintID(ResolveHint)]
+	if err := hint(field, []*big.Int{id, big.NewInt(3)}, outputs); err != nil {
+		t.Fatal(err)
+	}
+	if outputs[0].Uint64() != 42 {
+		t.Fatalf("expected 42, got %s", outputs[0])
+	}
+
+	// without the option, the resolver is nil
+	if err := ResolveHint(field, []*big.Int{id, big.NewInt(3)}, outputs); err == nil {
+		t.Fatal("expected error without resolver")
+	}
+	// unregistered resolver hint
+	if err := hint(field, []*big.Int{big.NewInt(1), big.NewInt(3)}, outputs); err == nil {
+		t.Fatal("expected error for unregistered hint")
+	}
+	// the context is checked before calling the hint
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = BindResolver(ctx, testResolver{3: 42})(field, []*big.Int{id, big.NewInt(3)}, outputs)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
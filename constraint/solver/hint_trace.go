@@ -0,0 +1,78 @@
+package solver
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// HintTraceEntry is the record of a hint invocation written by the solver with
+// [WithHintTrace].
+type HintTraceEntry struct {
+	Hint     string        `json:"hint"`
+	ID       HintID        `json:"id"`
+	Inputs   []string      `json:"inputs"`
+	Outputs  []string      `json:"outputs"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// WithHintTrace is a solver option that writes a record of every hint
+// invocation to w, as JSON lines: one [HintTraceEntry] per line, with the
+// inputs and outputs in decimal and the duration in nanoseconds. As the hints
+// are called concurrently, the records are not in the order of the
+// instructions of the constraint system.
+//
+// The trace is meant to diagnose the hints returning unexpected or
+// nondeterministic values and makes solving slower. It can be replayed with
+// [WithHintReplay].
+func WithHintTrace(w io.Writer) Option {
+	return func(opt *Config) error {
+		opt.HintTrace = w
+		return nil
+	}
+}
+
+// TraceHints returns the hint functions with the hints the constraint system
+// depends on wrapped to write their invocations to w, see [WithHintTrace].
+// names are the names of the hints recorded in the constraint system.
+func TraceHints(w io.Writer, names map[HintID]string, hintFunctions map[HintID]Hint) map[HintID]Hint {
+	var (
+		mu  sync.Mutex
+		enc = json.NewEncoder(w)
+	)
+	res := cloneMap(hintFunctions)
+	for id, name := range names {
+		f, ok := hintFunctions[id]
+		if !ok {
+			continue
+		}
+		id, name := id, name
+		res[id] = func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+			entry := HintTraceEntry{Hint: name, ID: id, Inputs: toDecimal(inputs)}
+			start := time.Now()
+			err := f(field, inputs, outputs)
+			entry.Duration = time.Since(start)
+			entry.Outputs = toDecimal(outputs)
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			// the trace is best effort, it doesn't fail the solver
+			_ = enc.Encode(entry)
+			return err
+		}
+	}
+	return res
+}
+
+func toDecimal(values []*big.Int) []string {
+	res := make([]string, len(values))
+	for i := range values {
+		res[i] = values[i].String()
+	}
+	return res
+}
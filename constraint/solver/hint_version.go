@@ -0,0 +1,114 @@
+package solver
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// versionedHint is the namespaced name and the version of a hint registered
+// with [RegisterVersionedHint].
+type versionedHint struct {
+	name    string
+	version uint32
+}
+
+var (
+	// versionedHints maps the function pointers of the versioned hints to
+	// their name. It has its own lock as [GetHintID] is called with registryM
+	// held.
+	versionedHints  = make(map[uintptr]versionedHint)
+	versionedHintsM sync.RWMutex
+)
+
+// RegisterVersionedHint registers a hint function in the global registry under
+// a namespaced name, for example "gnark/std/emulated/DivHint", and a version.
+//
+// The ID of the hint is derived from the name only, so that it doesn't depend
+// on the Go symbol of the function, and the constraint systems calling the hint
+// record its version: [GetHintName] returns "gnark/std/emulated/DivHint@v2".
+// Solving a constraint system with another version of the hint fails, see
+// [CheckHintVersion]. The version must be increased when the outputs of the
+// hint change.
+//
+// It panics if the name is empty or contains '@', or if its ID is already
+// taken by another hint.
+func RegisterVersionedHint(name string, version uint32, hintFn Hint) {
+	if name == "" || strings.Contains(name, "@") {
+		panic(fmt.Sprintf("invalid hint name %q", name))
+	}
+	fnptr := reflect.ValueOf(hintFn).Pointer()
+	vh := versionedHint{name: name, version: version}
+	versionedHintsM.Lock()
+	if prev, ok := versionedHints[fnptr]; ok && prev != vh {
+		versionedHintsM.Unlock()
+		panic(fmt.Sprintf("hint already registered as %s@v%d", prev.name, prev.version))
+	}
+	versionedHints[fnptr] = vh
+	versionedHintsM.Unlock()
+
+	key := GetHintID(hintFn)
+	registryM.Lock()
+	defer registryM.Unlock()
+	if registered, ok := registry[key]; ok {
+		if reflect.ValueOf(registered).Pointer() != fnptr {
+			panic(fmt.Errorf("hint id %d of %s already taken", key, name))
+		}
+		return
+	}
+	registry[key] = hintFn
+}
+
+// getVersionedHint returns the name and version of the hint if it was
+// registered with [RegisterVersionedHint].
+func getVersionedHint(fn Hint) (versionedHint, bool) {
+	versionedHintsM.RLock()
+	defer versionedHintsM.RUnlock()
+	vh, ok := versionedHints[reflect.ValueOf(fn).Pointer()]
+	return vh, ok
+}
+
+// versionedHintID returns the ID of the versioned hint, derived from its name.
+func versionedHintID(vh versionedHint) HintID {
+	hf := fnv.New32a()
+	hf.Write([]byte(vh.name)) // #nosec G104 -- does not err
+	return HintID(hf.Sum32())
+}
+
+// parseHintName splits the name returned by [GetHintName] for a versioned
+// hint into the namespaced name and the version. ok is false if the hint is
+// not versioned.
+func parseHintName(name string) (vh versionedHint, ok bool) {
+	i := strings.LastIndex(name, "@v")
+	if i < 0 {
+		return vh, false
+	}
+	version, err := strconv.ParseUint(name[i+2:], 10, 32)
+	if err != nil {
+		return vh, false
+	}
+	return versionedHint{name: name[:i], version: uint32(version)}, true
+}
+
+// CheckHintVersion returns an error if the hint function provided to the
+// solver is another version of the hint the constraint system depends on.
+// dependency is the name of the hint recorded in the constraint system. The
+// hints which are not versioned, for example the ones given with
+// [OverrideHint], are not checked.
+func CheckHintVersion(dependency string, hintFn Hint) error {
+	required, ok := parseHintName(dependency)
+	if !ok {
+		return nil
+	}
+	provided, ok := getVersionedHint(hintFn)
+	if !ok || provided.name != required.name {
+		return nil
+	}
+	if provided.version != required.version {
+		return fmt.Errorf("hint %s: the constraint system requires version %d, but the solver provides version %d", required.name, required.version, provided.version)
+	}
+	return nil
+}
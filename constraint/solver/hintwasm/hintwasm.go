@@ -0,0 +1,155 @@
+// Package hintwasm executes the WebAssembly modules of hints (see
+// [solver.HintModules]) with the wazero runtime, to give to
+// [solver.NewModuleSandbox]:
+//
+//	runtime := hintwasm.NewRuntime()
+//	defer runtime.Close()
+//	sandbox, err := solver.NewModuleSandbox(modules, runtime.Instantiate)
+//
+// It is a separate package so that the programs which don't import it don't
+// embed a WebAssembly runtime.
+//
+// A hint module has no imports and exports:
+//   - its memory, under the name "memory";
+//   - a function "alloc" (i32 size) -> (i32 address) returning the address of
+//     size bytes in the memory, where the input of a call is written;
+//   - for each hint, a function exported under the name of the hint (see
+//     [solver.GetHintName]) (i32 address, i32 size) -> (i64 output), called
+//     with the input of [solver.SandboxedHint] and returning the address of
+//     its output in the high 32 bits and the size of the output in the low 32
+//     bits.
+package hintwasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Runtime instantiates the hint modules, see [Runtime.Instantiate].
+type Runtime struct {
+	runtime wazero.Runtime
+	timeout time.Duration
+}
+
+type config struct {
+	memoryLimitPages uint32
+	timeout          time.Duration
+}
+
+// Option configures a [Runtime].
+type Option func(*config)
+
+// WithMemoryLimit sets the maximum size of the memory of a module, in pages of
+// 64 KiB. Defaults to 256 pages (16 MiB).
+func WithMemoryLimit(pages uint32) Option {
+	return func(c *config) {
+		c.memoryLimitPages = pages
+	}
+}
+
+// WithTimeout sets the maximum duration of a call to a hint. A module whose
+// call times out is closed, and the next calls to its hints fail. Defaults to
+// 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// NewRuntime returns a runtime for the hint modules, which must be closed
+// when the solving is done.
+func NewRuntime(opts ...Option) *Runtime {
+	cfg := config{
+		memoryLimitPages: 256,
+		timeout:          10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	rc := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(cfg.memoryLimitPages).
+		WithCloseOnContextDone(true)
+	return &Runtime{
+		runtime: wazero.NewRuntimeWithConfig(context.Background(), rc),
+		timeout: cfg.timeout,
+	}
+}
+
+// Close releases the modules instantiated by the runtime.
+func (r *Runtime) Close() error {
+	return r.runtime.Close(context.Background())
+}
+
+// Instantiate compiles and instantiates the module, as the instantiate
+// argument of [solver.NewModuleSandbox]. The module must not have imports.
+func (r *Runtime) Instantiate(module []byte) (solver.HintSandbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	compiled, err := r.runtime.CompileModule(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+	if len(compiled.ImportedFunctions()) != 0 || len(compiled.ImportedMemories()) != 0 {
+		return nil, errors.New("the module has imports")
+	}
+	// the module is anonymous, so that other modules can't import it
+	mod, err := r.runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		return nil, fmt.Errorf("instantiate: %w", err)
+	}
+	memory := mod.ExportedMemory("memory")
+	alloc := mod.ExportedFunction("alloc")
+	if memory == nil || alloc == nil {
+		return nil, errors.New("the module doesn't export memory and alloc")
+	}
+	return &instance{module: mod, memory: memory, alloc: alloc, timeout: r.timeout}, nil
+}
+
+// instance is the instance of a module, whose hints are called one at a time.
+type instance struct {
+	mu      sync.Mutex
+	module  api.Module
+	memory  api.Memory
+	alloc   api.Function
+	timeout time.Duration
+}
+
+// Call implements [solver.HintSandbox].
+func (i *instance) Call(name string, input []byte) ([]byte, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	f := i.module.ExportedFunction(name)
+	if f == nil {
+		return nil, errors.New("the module doesn't export the hint")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), i.timeout)
+	defer cancel()
+
+	res, err := i.alloc.Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("alloc: %w", err)
+	}
+	address := uint32(res[0])
+	if !i.memory.Write(address, input) {
+		return nil, errors.New("alloc returned an address out of the memory")
+	}
+	if res, err = f.Call(ctx, uint64(address), uint64(len(input))); err != nil {
+		return nil, err
+	}
+	output, ok := i.memory.Read(uint32(res[0]>>32), uint32(res[0]))
+	if !ok {
+		return nil, errors.New("output out of the memory")
+	}
+	// the memory is reused by the next calls
+	return bytes.Clone(output), nil
+}
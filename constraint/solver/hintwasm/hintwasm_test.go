@@ -0,0 +1,115 @@
+package hintwasm_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/constraint/solver/hintwasm"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// identityHint is not registered: the solver runs its module.
+func identityHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	return errors.New("the native hint must not be called")
+}
+
+type identityCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *identityCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(identityHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], c.Y)
+	return nil
+}
+
+// uleb appends the unsigned LEB128 encoding of v.
+func uleb(b []byte, v int) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func section(id byte, content []byte) []byte {
+	return append(uleb([]byte{id}, len(content)), content...)
+}
+
+func export(b []byte, name string, kind byte, index byte) []byte {
+	b = uleb(b, len(name))
+	return append(append(b, name...), kind, index)
+}
+
+// module returns a module exporting the hint name with the given body, of
+// type (i32, i32) -> i64, and alloc returning the address 1024. If imports is
+// set, the module imports a function.
+func module(name string, body []byte, imports bool) []byte {
+	m := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// (i32) -> i32 and (i32, i32) -> i64
+	m = append(m, section(1, []byte{0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e})...)
+	if imports {
+		m = append(m, section(2, []byte{0x01, 0x03, 'e', 'n', 'v', 0x01, 'f', 0x00, 0x00})...)
+	}
+	m = append(m, section(3, []byte{0x02, 0x00, 0x01})...)
+	m = append(m, section(5, []byte{0x01, 0x00, 0x01})...)
+	funcOffset := byte(0)
+	if imports {
+		funcOffset = 1
+	}
+	exports := []byte{0x03}
+	exports = export(exports, "memory", 0x02, 0)
+	exports = export(exports, "alloc", 0x00, funcOffset)
+	exports = export(exports, name, 0x00, funcOffset+1)
+	m = append(m, section(7, exports)...)
+	alloc := []byte{0x00, 0x41, 0x80, 0x08, 0x0b} // i32.const 1024
+	code := []byte{0x02}
+	code = append(uleb(code, len(alloc)), alloc...)
+	code = append(uleb(code, len(body)), body...)
+	return append(m, section(10, code)...)
+}
+
+// identityBody returns the input of a hint on BN254 with a single input, the
+// output being the input after the modulus: (address + 44) << 32 | size - 44.
+var identityBody = []byte{
+	0x00,
+	0x20, 0x00, 0x41, 0x2c, 0x6a, 0xad, 0x42, 0x20, 0x86,
+	0x20, 0x01, 0x41, 0x2c, 0x6b, 0xad,
+	0x84, 0x0b,
+}
+
+// loopBody loops forever.
+var loopBody = []byte{0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b}
+
+func TestRuntime(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &identityCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&identityCircuit{X: 3, Y: 3}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	name := solver.GetHintName(identityHint)
+	solve := func(runtime *hintwasm.Runtime, module []byte) error {
+		sandbox, err := solver.NewModuleSandbox(solver.HintModules{solver.GetHintID(identityHint): module}, runtime.Instantiate)
+		assert.NoError(err)
+		_, err = ccs.Solve(w, solver.WithHintSandbox(sandbox))
+		return err
+	}
+
+	runtime := hintwasm.NewRuntime(hintwasm.WithTimeout(100 * time.Millisecond))
+	defer runtime.Close()
+
+	assert.NoError(solve(runtime, module(name, identityBody, false)))
+	assert.ErrorContains(solve(runtime, module("other", identityBody, false)), "doesn't export the hint")
+	assert.ErrorContains(solve(runtime, module(name, identityBody, true)), "the module has imports")
+	assert.ErrorContains(solve(runtime, module(name, loopBody, false)), "deadline exceeded")
+}
@@ -1,8 +1,12 @@
 package solver
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/big"
 	"runtime"
+	"time"
 
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
@@ -18,8 +22,41 @@ type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
 	NbTasks       int             // defaults to runtime.NumCPU()
+
+	InstructionHook InstructionHook // defaults to nil
+	HintSandbox     HintSandbox     // defaults to nil
+	HintProvider    HintProvider    // defaults to nil
+	HintTrace       io.Writer       // defaults to nil
+	HintReplay      *HintReplay     // defaults to nil
+	HintTimeout     time.Duration   // defaults to 0, no timeout
+	Pool            *Pool           // defaults to nil
+	ConstantTime    bool            // defaults to false
+
+	Progress           func(solved, total uint64) // defaults to nil
+	Context            context.Context            // defaults to nil
+	DeterministicOrder bool                       // defaults to false
+	CollectAllErrors   bool                       // defaults to false
+}
+
+// State gives read access to the wire values of the constraint system during
+// solving.
+type State interface {
+	// Value returns the value of the wire and true if the wire is solved.
+	Value(wireID int) (*big.Int, bool)
+	// NbWires returns the number of wires of the constraint system.
+	NbWires() int
 }
 
+// InstructionHook is called by the solver after processing each instruction,
+// in solving order. err is the error returned when processing the instruction
+// (for example an unsatisfied constraint), in which case the solver stops once
+// the hook returns, unless the unsatisfied constraints are collected (see
+// WithCollectAllErrors). If the hook returns an error, the solver stops and returns
+// it.
+//
+// state is only valid during the call of the hook.
+type InstructionHook func(instructionID int, state State, err error) error
+
 // WithHints is a solver option that specifies additional hint functions to be used
 // by the constraint solver.
 func WithHints(hintFunctions ...Hint) Option {
@@ -49,7 +86,9 @@ func OverrideHint(id HintID, f Hint) Option {
 
 // WithLogger is a prover option that specifies zerolog.Logger as a destination for the
 // logs printed by api.Println(). By default, uses gnark/logger.
-// zerolog.Nop() will disable logging
+// zerolog.Nop() will disable logging. Log entries below the level of the logger
+// are skipped (api.Println() logs at debug level, see frontend.LevelLogger to
+// log at other levels).
 func WithLogger(l zerolog.Logger) Option {
 	return func(opt *Config) error {
 		opt.Logger = l
@@ -57,6 +96,16 @@ func WithLogger(l zerolog.Logger) Option {
 	}
 }
 
+// WithLogSink is a solver option that writes the logs printed by api.Println()
+// and frontend.StructuredLogger as JSON lines to w, one object per log entry.
+// Structured entries have one field per key. It overrides WithLogger.
+func WithLogSink(w io.Writer) Option {
+	return func(opt *Config) error {
+		opt.Logger = zerolog.New(w).Level(zerolog.DebugLevel).With().Timestamp().Logger()
+		return nil
+	}
+}
+
 // WithNbTasks sets the number of parallel workers to use for the solver. If not
 // set, then the number of workers is set to runtime.NumCPU().
 //
@@ -79,6 +128,87 @@ func WithNbTasks(nbTasks int) Option {
 	}
 }
 
+// WithInstructionHook sets a hook called by the solver after each instruction.
+// When set, the instructions are processed sequentially, in a single go
+// routine, so that the hook observes a consistent state. This is meant for
+// debugging tools and makes solving slower.
+func WithInstructionHook(hook InstructionHook) Option {
+	return func(opt *Config) error {
+		opt.InstructionHook = hook
+		return nil
+	}
+}
+
+// WithDeterministicOrder makes the solver process the instructions one at a
+// time, in a single go routine, in the order in which they were added to the
+// constraint system, whatever the number of tasks. The error of an
+// unsatisfied constraint is then the one of the first failing constraint by
+// index, and is the same from one run to another, which helps triaging the
+// circuits failing intermittently. In this mode, the progress callback (see
+// WithProgress) is called after each chunk of instructions instead of each
+// level. This is meant for debugging and makes solving slower.
+func WithDeterministicOrder() Option {
+	return func(opt *Config) error {
+		opt.DeterministicOrder = true
+		return nil
+	}
+}
+
+// WithCollectAllErrors makes the solver continue past the unsatisfied
+// constraints instead of stopping at the first one. Once all the instructions
+// are processed, it returns an UnsatisfiedConstraintsError (of the package of
+// the curve, for example cs_bn254) listing the unsatisfied constraints, with
+// their debug information, in increasing order of constraint ID. The other
+// errors, for example of a hint, still stop the solving.
+//
+// The instructions are then processed sequentially, in a single go routine,
+// which makes solving slower. A wire which should have been solved by an
+// unsatisfied constraint is left unsolved, so that the constraints depending
+// on it may be reported as a consequence of the first failures.
+func WithCollectAllErrors() Option {
+	return func(opt *Config) error {
+		opt.CollectAllErrors = true
+		return nil
+	}
+}
+
+// WithProgress sets a callback reporting the progress of solving: it is called
+// after each level of the constraint system (see constraint.System.Levels)
+// with the number of solved wires and the total number of wires. The levels
+// have very different sizes, so that the progress isn't linear in time. The
+// callback is called from the solving go routine, and should return quickly.
+func WithProgress(progress func(solved, total uint64)) Option {
+	return func(opt *Config) error {
+		opt.Progress = progress
+		return nil
+	}
+}
+
+// WithContext makes the solver stop when ctx is done, in which case Solve
+// returns the error of ctx. The context is checked before each level of the
+// constraint system and before each task of the workers of a level: a task
+// which is started is completed, and the hints are not interrupted.
+func WithContext(ctx context.Context) Option {
+	return func(opt *Config) error {
+		opt.Context = ctx
+		return nil
+	}
+}
+
+// WithConstantTime makes the solver avoid branching on the values of the wires
+// and indexing tables by them: the divisions of the constraints are computed
+// with the same field operations whether the divisor is zero or not, and the
+// lookup tables and memories are read and written by scanning all their
+// entries. It is meant for solving secret witnesses on shared or observable
+// machines, and makes solving slower. The hints, which compute with
+// [big.Int], are not constant-time.
+func WithConstantTime() Option {
+	return func(opt *Config) error {
+		opt.ConstantTime = true
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
@@ -0,0 +1,163 @@
+package solver
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/field/pool"
+)
+
+// Pool holds the memory reused across the calls to Solve, to reduce the
+// allocations and the GC pauses of services solving many witnesses of the
+// same constraint systems: the big.Int of the inputs and outputs of the hints,
+// and the buffers of the wire values. It is given to the solver with
+// [WithPool] and is safe for concurrent use.
+//
+// The Groth16 prover gives the buffers of the solution back to the pool once
+// the proof is computed. Other users of the solution may give them back with
+// [PutBuffer] when they don't use them anymore.
+//
+// A nil *Pool is valid: the buffers are allocated and the big.Int are taken
+// from the shared pool of gnark-crypto.
+type Pool struct {
+	lock       sync.Mutex
+	bigInts    []*big.Int
+	buffers    map[reflect.Type][]any
+	maxBigInts int
+	maxBuffers int
+}
+
+// PoolOption defines option for altering the behavior of a [Pool].
+type PoolOption func(*Pool)
+
+// WithPoolInitialSize sets the number of big.Int allocated when creating the
+// pool. The default is 0.
+func WithPoolInitialSize(n int) PoolOption {
+	return func(p *Pool) {
+		for i := len(p.bigInts); i < n; i++ {
+			p.bigInts = append(p.bigInts, new(big.Int))
+		}
+	}
+}
+
+// WithPoolMaxRetained sets the maximal number of big.Int retained by the pool,
+// the others are left to the GC. The default is 65536.
+func WithPoolMaxRetained(n int) PoolOption {
+	return func(p *Pool) {
+		p.maxBigInts = n
+	}
+}
+
+// WithPoolMaxBuffers sets the maximal number of buffers of each type retained
+// by the pool, the others are left to the GC. A solve uses up to 5 buffers:
+// the wire values, the solved flags and the A, B, C vectors of a R1CS. The
+// default is 8.
+func WithPoolMaxBuffers(n int) PoolOption {
+	return func(p *Pool) {
+		p.maxBuffers = n
+	}
+}
+
+// NewPool returns a new [Pool] with the options applied.
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{
+		buffers:    make(map[reflect.Type][]any),
+		maxBigInts: 1 << 16,
+		maxBuffers: 8,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(p.bigInts) > p.maxBigInts {
+		p.bigInts = p.bigInts[:p.maxBigInts]
+	}
+	return p
+}
+
+// GetBigInts sets the entries of dst to big.Int from the pool. Their values are
+// undefined.
+func (p *Pool) GetBigInts(dst []*big.Int) {
+	if p == nil {
+		for i := range dst {
+			dst[i] = pool.BigInt.Get()
+		}
+		return
+	}
+	i := 0
+	p.lock.Lock()
+	for ; i < len(dst) && len(p.bigInts) > 0; i++ {
+		dst[i] = p.bigInts[len(p.bigInts)-1]
+		p.bigInts = p.bigInts[:len(p.bigInts)-1]
+	}
+	p.lock.Unlock()
+	for ; i < len(dst); i++ {
+		dst[i] = new(big.Int)
+	}
+}
+
+// PutBigInts gives the big.Int of src back to the pool.
+func (p *Pool) PutBigInts(src []*big.Int) {
+	if p == nil {
+		for i := range src {
+			pool.BigInt.Put(src[i])
+		}
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for i := 0; i < len(src) && len(p.bigInts) < p.maxBigInts; i++ {
+		if src[i] != nil {
+			p.bigInts = append(p.bigInts, src[i])
+		}
+	}
+}
+
+// GetBuffer returns a zeroed slice of length n and capacity at least capacity
+// from the pool.
+func GetBuffer[T any](p *Pool, n, capacity int) []T {
+	if capacity < n {
+		capacity = n
+	}
+	if p != nil {
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		p.lock.Lock()
+		buffers := p.buffers[t]
+		for i := range buffers {
+			b := buffers[i].([]T)
+			if cap(b) < capacity {
+				continue
+			}
+			buffers[i] = buffers[len(buffers)-1]
+			p.buffers[t] = buffers[:len(buffers)-1]
+			p.lock.Unlock()
+			b = b[:n]
+			clear(b)
+			return b
+		}
+		p.lock.Unlock()
+	}
+	return make([]T, n, capacity)
+}
+
+// PutBuffer gives the buffer b back to the pool. b must not be used anymore.
+func PutBuffer[T any](p *Pool, b []T) {
+	if p == nil || cap(b) == 0 {
+		return
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.buffers[t]) < p.maxBuffers {
+		p.buffers[t] = append(p.buffers[t], b)
+	}
+}
+
+// WithPool sets the pool of memory of the solver. By default the solver
+// allocates the wire values for each call to Solve.
+func WithPool(pool *Pool) Option {
+	return func(opt *Config) error {
+		opt.Pool = pool
+		return nil
+	}
+}
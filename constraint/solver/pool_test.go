@@ -0,0 +1,69 @@
+package solver
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPoolBuffers(t *testing.T) {
+	p := NewPool(WithPoolMaxBuffers(1))
+
+	b := GetBuffer[uint64](p, 4, 8)
+	if len(b) != 4 || cap(b) < 8 {
+		t.Fatalf("unexpected buffer len %d cap %d", len(b), cap(b))
+	}
+	b[0] = 42
+	PutBuffer(p, b)
+	PutBuffer(p, make([]uint64, 16)) // dropped, the pool retains 1 buffer
+
+	// too small
+	if r := GetBuffer[uint64](p, 16, 16); &r[0] == &b[0] {
+		t.Fatal("buffer too small was reused")
+	}
+	// other type
+	if r := GetBuffer[int64](p, 4, 4); cap(r) != 4 {
+		t.Fatal("buffer of another type was reused")
+	}
+	r := GetBuffer[uint64](p, 2, 2)
+	if &r[0] != &b[0] {
+		t.Fatal("buffer was not reused")
+	}
+	if len(r) != 2 || r[0] != 0 {
+		t.Fatal("reused buffer is not zeroed")
+	}
+	if r := GetBuffer[uint64](p, 2, 2); &r[0] == &b[0] {
+		t.Fatal("buffer was reused twice")
+	}
+
+	// a nil pool allocates
+	if r := GetBuffer[uint64](nil, 3, 5); len(r) != 3 || cap(r) != 5 {
+		t.Fatal("unexpected buffer from nil pool")
+	}
+	PutBuffer[uint64](nil, r)
+}
+
+func TestPoolBigInts(t *testing.T) {
+	p := NewPool(WithPoolInitialSize(4), WithPoolMaxRetained(3))
+	if len(p.bigInts) != 3 {
+		t.Fatalf("expected 3 big.Int retained, got %d", len(p.bigInts))
+	}
+
+	ints := make([]*big.Int, 5)
+	p.GetBigInts(ints)
+	for i := range ints {
+		if ints[i] == nil {
+			t.Fatal("nil big.Int")
+		}
+	}
+	if len(p.bigInts) != 0 {
+		t.Fatal("pool not emptied")
+	}
+	p.PutBigInts(ints)
+	if len(p.bigInts) != 3 {
+		t.Fatalf("expected 3 big.Int retained, got %d", len(p.bigInts))
+	}
+
+	var nilPool *Pool
+	nilPool.GetBigInts(ints)
+	nilPool.PutBigInts(ints)
+}
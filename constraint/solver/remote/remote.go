@@ -0,0 +1,267 @@
+// Package remote computes hints in another process over HTTP, for hints which
+// need access to external data, such as databases, signing services or large
+// lookup tables.
+//
+// The [Client] is a [solver.HintProvider], given to the solver with
+// [solver.WithHintProvider], and the [Handler] serves its calls with the
+// registered hint functions. The solver posts the calls of a level of the
+// constraint system in one request, as a JSON object
+//
+//	{"modulus": "21888...", "calls": [{"name": "...", "inputs": ["1", "2"], "nbOutputs": 1}]}
+//
+// and the response is the outputs of the calls, in the same order:
+//
+//	{"outputs": [["3"]]}
+//
+// The integers are encoded as decimal strings, and a failed request is
+// answered with an error status and the error message in the body. The
+// handler bounds the size of the requests, see [WithMaxRequestSize],
+// [WithMaxCalls] and [WithMaxOutputs].
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+type request struct {
+	Modulus string `json:"modulus"`
+	Calls   []call `json:"calls"`
+}
+
+type call struct {
+	Name      string   `json:"name"`
+	Inputs    []string `json:"inputs"`
+	NbOutputs int      `json:"nbOutputs"`
+}
+
+type response struct {
+	Outputs [][]string `json:"outputs"`
+}
+
+// maxErrorSize is the number of bytes of an error response read by the
+// client.
+const maxErrorSize = 1 << 12
+
+// Client computes hints by posting them to a [Handler].
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient returns a client posting the hint calls to url with client, or
+// with [http.DefaultClient] if client is nil.
+func NewClient(url string, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{url: url, client: client}
+}
+
+// Hint implements [solver.HintProvider].
+func (c *Client) Hint(ctx context.Context, field *big.Int, calls []solver.HintCall) error {
+	req := request{Modulus: field.String(), Calls: make([]call, len(calls))}
+	for i := range calls {
+		req.Calls[i] = call{Name: calls[i].Name, Inputs: toDecimal(calls[i].Inputs), NbOutputs: len(calls[i].Outputs)}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxErrorSize))
+		return fmt.Errorf("%s: %s", httpResp.Status, bytes.TrimSpace(msg))
+	}
+
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if len(resp.Outputs) != len(calls) {
+		return fmt.Errorf("%d outputs for %d calls", len(resp.Outputs), len(calls))
+	}
+	for i := range calls {
+		if len(resp.Outputs[i]) != len(calls[i].Outputs) {
+			return fmt.Errorf("hint %s: %d outputs, expected %d", calls[i].Name, len(resp.Outputs[i]), len(calls[i].Outputs))
+		}
+		if err := fromDecimal(calls[i].Outputs, resp.Outputs[i]); err != nil {
+			return fmt.Errorf("hint %s: %w", calls[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// Option defines option for altering the behavior of the handler. See the
+// descriptions of functions returning instances of this type for implemented
+// options.
+type Option func(*Config) error
+
+// Config is the configuration of the handler with the options applied.
+type Config struct {
+	MaxRequestSize int64
+	MaxCalls       int
+	MaxOutputs     int
+}
+
+// WithMaxRequestSize sets the maximum size, in bytes, of a request body. If
+// not set, requests are limited to 16MiB.
+func WithMaxRequestSize(bytes int64) Option {
+	return func(cfg *Config) error {
+		if bytes <= 0 {
+			return errors.New("maximum request size must be positive")
+		}
+		cfg.MaxRequestSize = bytes
+		return nil
+	}
+}
+
+// WithMaxCalls sets the maximum number of calls in a request. If not set, a
+// request has at most 65536 calls.
+func WithMaxCalls(n int) Option {
+	return func(cfg *Config) error {
+		if n <= 0 {
+			return errors.New("maximum number of calls must be positive")
+		}
+		cfg.MaxCalls = n
+		return nil
+	}
+}
+
+// WithMaxOutputs sets the maximum total number of outputs of the calls of a
+// request. If not set, a request has at most 2²⁰ outputs.
+func WithMaxOutputs(n int) Option {
+	return func(cfg *Config) error {
+		if n <= 0 {
+			return errors.New("maximum number of outputs must be positive")
+		}
+		cfg.MaxOutputs = n
+		return nil
+	}
+}
+
+// Handler serves the requests of a [Client] with hint functions.
+type Handler struct {
+	cfg   Config
+	hints map[string]solver.Hint
+}
+
+// NewHandler returns a handler computing the given hints, which are called by
+// the name given by [solver.GetHintName].
+func NewHandler(hints []solver.Hint, opts ...Option) (*Handler, error) {
+	cfg := Config{
+		MaxRequestSize: 16 << 20,
+		MaxCalls:       1 << 16,
+		MaxOutputs:     1 << 20,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	h := &Handler{cfg: cfg, hints: make(map[string]solver.Hint, len(hints))}
+	for _, f := range hints {
+		h.hints[solver.GetHintName(f)] = f
+	}
+	return h, nil
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.cfg.MaxRequestSize)).Decode(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("decode request: %s", err), status)
+		return
+	}
+	if len(req.Calls) > h.cfg.MaxCalls {
+		http.Error(w, fmt.Sprintf("%d calls, at most %d", len(req.Calls), h.cfg.MaxCalls), http.StatusRequestEntityTooLarge)
+		return
+	}
+	// the outputs are allocated from the numbers of the request: they are
+	// checked before computing any call
+	nbOutputs := 0
+	for _, c := range req.Calls {
+		if c.NbOutputs < 0 || c.NbOutputs > h.cfg.MaxOutputs-nbOutputs {
+			http.Error(w, fmt.Sprintf("hint %s: invalid number of outputs %d, at most %d in a request", c.Name, c.NbOutputs, h.cfg.MaxOutputs), http.StatusRequestEntityTooLarge)
+			return
+		}
+		nbOutputs += c.NbOutputs
+	}
+	field, ok := new(big.Int).SetString(req.Modulus, 10)
+	if !ok {
+		http.Error(w, fmt.Sprintf("invalid modulus %q", req.Modulus), http.StatusBadRequest)
+		return
+	}
+
+	resp := response{Outputs: make([][]string, len(req.Calls))}
+	for i, c := range req.Calls {
+		f, ok := h.hints[c.Name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown hint %s", c.Name), http.StatusNotFound)
+			return
+		}
+		inputs := make([]*big.Int, len(c.Inputs))
+		if err := fromDecimal(inputs, c.Inputs); err != nil {
+			http.Error(w, fmt.Sprintf("hint %s: %s", c.Name, err), http.StatusBadRequest)
+			return
+		}
+		outputs := make([]*big.Int, c.NbOutputs)
+		for j := range outputs {
+			outputs[j] = new(big.Int)
+		}
+		if err := f(field, inputs, outputs); err != nil {
+			http.Error(w, fmt.Sprintf("hint %s: %s", c.Name, err), http.StatusInternalServerError)
+			return
+		}
+		resp.Outputs[i] = toDecimal(outputs)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toDecimal(values []*big.Int) []string {
+	res := make([]string, len(values))
+	for i := range values {
+		res[i] = values[i].String()
+	}
+	return res
+}
+
+// fromDecimal sets res from the decimal strings, allocating the nil entries.
+func fromDecimal(res []*big.Int, values []string) error {
+	for i := range values {
+		if res[i] == nil {
+			res[i] = new(big.Int)
+		}
+		if _, ok := res[i].SetString(values[i], 10); !ok {
+			return fmt.Errorf("invalid integer %q", values[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// squareHint is only given to the handler, the solving process calls it
+// through the client.
+func squareHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Mul(inputs[0], inputs[0])
+	return nil
+}
+
+func failingHint(_ *big.Int, _ []*big.Int, _ []*big.Int) error {
+	return errors.New("database unavailable")
+}
+
+type hintCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+	hint solver.Hint
+}
+
+func (c *hintCircuit) Define(api frontend.API) error {
+	x2, err := api.Compiler().NewHint(c.hint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	y2, err := api.Compiler().NewHint(c.hint, 1, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(c.X, c.X), x2[0])
+	api.AssertIsEqual(api.Mul(c.Y, c.Y), y2[0])
+	api.AssertIsEqual(api.Add(x2[0], y2[0]), c.Z)
+	return nil
+}
+
+func compile(t *testing.T, hint solver.Hint) (constraint.ConstraintSystem, frontend.Circuit) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &hintCircuit{hint: hint})
+	require.NoError(t, err)
+	return ccs, &hintCircuit{X: 3, Y: 4, Z: 25, hint: hint}
+}
+
+func solve(ccs constraint.ConstraintSystem, assignment frontend.Circuit, opts ...solver.Option) error {
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return err
+	}
+	_, err = ccs.Solve(w, opts...)
+	return err
+}
+
+func newServer(t *testing.T, handler http.Handler) *httptest.Server {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRoundTrip(t *testing.T) {
+	assert := require.New(t)
+	ccs, assignment := compile(t, squareHint)
+
+	h, err := NewHandler([]solver.Hint{squareHint})
+	assert.NoError(err)
+	var nbRequests atomic.Int32
+	srv := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nbRequests.Add(1)
+		h.ServeHTTP(w, r)
+	}))
+
+	// the hint isn't registered in the solving process
+	assert.Error(solve(ccs, assignment))
+
+	client := NewClient(srv.URL, srv.Client())
+	assert.NoError(solve(ccs, assignment, solver.WithHintProvider(client)))
+	// the two calls are on the same level
+	assert.EqualValues(1, nbRequests.Load())
+
+	assignment.(*hintCircuit).Z = 26
+	assert.Error(solve(ccs, assignment, solver.WithHintProvider(client)))
+}
+
+func TestRemoteHintError(t *testing.T) {
+	assert := require.New(t)
+	ccs, assignment := compile(t, failingHint)
+
+	h, err := NewHandler([]solver.Hint{failingHint})
+	assert.NoError(err)
+	srv := newServer(t, h)
+	err = solve(ccs, assignment, solver.WithHintProvider(NewClient(srv.URL, srv.Client())))
+	assert.ErrorContains(err, "500 Internal Server Error")
+	assert.ErrorContains(err, "database unavailable")
+
+	// the handler doesn't know the hint
+	h, err = NewHandler(nil)
+	assert.NoError(err)
+	srv = newServer(t, h)
+	err = solve(ccs, assignment, solver.WithHintProvider(NewClient(srv.URL, srv.Client())))
+	assert.ErrorContains(err, "404 Not Found")
+}
+
+func TestContext(t *testing.T) {
+	assert := require.New(t)
+	ccs, assignment := compile(t, squareHint)
+
+	// the handler blocks until the request is canceled or the test ends
+	done := make(chan struct{})
+	defer close(done)
+	srv := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	client := NewClient(srv.URL, srv.Client())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := solve(ccs, assignment, solver.WithHintProvider(client), solver.WithContext(ctx))
+	assert.ErrorIs(err, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = solve(ccs, assignment, solver.WithHintProvider(client), solver.WithContext(ctx))
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	// timeout of the HTTP client
+	httpClient := srv.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+	err = solve(ccs, assignment, solver.WithHintProvider(NewClient(srv.URL, httpClient)))
+	assert.ErrorContains(err, "Client.Timeout exceeded")
+}
+
+func TestHandlerLimits(t *testing.T) {
+	assert := require.New(t)
+	ccs, assignment := compile(t, squareHint)
+
+	h, err := NewHandler([]solver.Hint{squareHint}, WithMaxCalls(1))
+	assert.NoError(err)
+	srv := newServer(t, h)
+	err = solve(ccs, assignment, solver.WithHintProvider(NewClient(srv.URL, srv.Client())))
+	assert.ErrorContains(err, "413 Request Entity Too Large")
+
+	_, err = NewHandler(nil, WithMaxOutputs(0))
+	assert.Error(err)
+}
@@ -0,0 +1,161 @@
+package constraint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SourceMapVersion is the version of the source map format written by
+// System.WriteSourceMap.
+const SourceMapVersion = 1
+
+// SourceMap maps the constraints of a constraint system to the call stacks
+// recorded in its debug information. It is written by System.WriteSourceMap as
+// JSON, so that external tools can map constraints to source code without
+// deserializing the constraint system:
+//
+//	{
+//	  "version": 1,
+//	  "nbConstraints": 12,
+//	  "functions": [{"name": "main.(*Circuit).Define", "file": "main.go"}],
+//	  "locations": [[0, 21]],
+//	  "stacks": [[0]],
+//	  "constraints": [[3, 4, 0]]
+//	}
+//
+// A location is a [function index, line] pair and a stack is a list of
+// location indexes, innermost first. Each entry of "constraints" is a
+// [first, last, stack index] triple: the constraints with IDs in the
+// inclusive range [first, last] have the given stack. Constraints without
+// debug information are omitted.
+type SourceMap struct {
+	Version       int                 `json:"version"`
+	NbConstraints int                 `json:"nbConstraints"`
+	Functions     []SourceMapFunction `json:"functions"`
+	Locations     [][2]int64          `json:"locations"`
+	Stacks        [][]int             `json:"stacks"`
+	Constraints   [][3]int            `json:"constraints"`
+}
+
+// SourceMapFunction is a function referenced in a SourceMap.
+type SourceMapFunction struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// WriteSourceMap writes the SourceMap of the constraint system to w, as JSON.
+func (system *System) WriteSourceMap(w io.Writer) error {
+	m := SourceMap{
+		Version:       SourceMapVersion,
+		NbConstraints: system.GetNbConstraints(),
+		Functions:     make([]SourceMapFunction, len(system.SymbolTable.Functions)),
+		Locations:     make([][2]int64, len(system.SymbolTable.Locations)),
+		Stacks:        make([][]int, len(system.DebugInfo)),
+		Constraints:   [][3]int{},
+	}
+	for i, f := range system.SymbolTable.Functions {
+		m.Functions[i] = SourceMapFunction{Name: f.Name, File: f.Filename}
+	}
+	for i, l := range system.SymbolTable.Locations {
+		m.Locations[i] = [2]int64{int64(l.FunctionID), l.Line}
+	}
+	for i, d := range system.DebugInfo {
+		m.Stacks[i] = d.Stack
+		if m.Stacks[i] == nil {
+			m.Stacks[i] = []int{}
+		}
+	}
+
+	// merge consecutive constraints with the same debug information.
+	cIDs := make([]int, 0, len(system.MDebug))
+	for cID := range system.MDebug {
+		cIDs = append(cIDs, cID)
+	}
+	sort.Ints(cIDs)
+	for _, cID := range cIDs {
+		dID := system.MDebug[cID]
+		if n := len(m.Constraints); n > 0 && m.Constraints[n-1][1] == cID-1 && m.Constraints[n-1][2] == dID {
+			m.Constraints[n-1][1] = cID
+			continue
+		}
+		m.Constraints = append(m.Constraints, [3]int{cID, cID, dID})
+	}
+
+	return json.NewEncoder(w).Encode(&m)
+}
+
+// ReadSourceMap reads a SourceMap written by System.WriteSourceMap.
+func ReadSourceMap(r io.Reader) (*SourceMap, error) {
+	var m SourceMap
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+	if m.Version != SourceMapVersion {
+		return nil, fmt.Errorf("unsupported source map version %d", m.Version)
+	}
+	if err := m.check(); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	return &m, nil
+}
+
+// check checks that the indexes of the tables are in range, and that the
+// constraint ranges are sorted and disjoint.
+func (m *SourceMap) check() error {
+	for i, l := range m.Locations {
+		if l[0] < 0 || l[0] >= int64(len(m.Functions)) {
+			return fmt.Errorf("location %d: function %d out of range", i, l[0])
+		}
+	}
+	for i, stack := range m.Stacks {
+		for _, lID := range stack {
+			if lID < 0 || lID >= len(m.Locations) {
+				return fmt.Errorf("stack %d: location %d out of range", i, lID)
+			}
+		}
+	}
+	for i, c := range m.Constraints {
+		if c[0] < 0 || c[0] > c[1] || c[1] >= m.NbConstraints {
+			return fmt.Errorf("constraints %d: invalid range [%d, %d]", i, c[0], c[1])
+		}
+		if i > 0 && c[0] <= m.Constraints[i-1][1] {
+			return fmt.Errorf("constraints %d: range [%d, %d] not after the previous one", i, c[0], c[1])
+		}
+		if c[2] < 0 || c[2] >= len(m.Stacks) {
+			return fmt.Errorf("constraints %d: stack %d out of range", i, c[2])
+		}
+	}
+	return nil
+}
+
+// Stack returns the call stack of the constraint, innermost frame first, or
+// nil if the constraint has no debug information. It also returns nil if the
+// indexes of the tables are out of range, which ReadSourceMap rejects.
+func (m *SourceMap) Stack(constraintID int) []StackFrame {
+	i := sort.Search(len(m.Constraints), func(i int) bool {
+		return m.Constraints[i][1] >= constraintID
+	})
+	if i == len(m.Constraints) || m.Constraints[i][0] > constraintID {
+		return nil
+	}
+	sID := m.Constraints[i][2]
+	if sID < 0 || sID >= len(m.Stacks) {
+		return nil
+	}
+	stack := m.Stacks[sID]
+	frames := make([]StackFrame, len(stack))
+	for j, lID := range stack {
+		if lID < 0 || lID >= len(m.Locations) {
+			return nil
+		}
+		l := m.Locations[lID]
+		if l[0] < 0 || l[0] >= int64(len(m.Functions)) {
+			return nil
+		}
+		f := m.Functions[l[0]]
+		frames[j] = StackFrame{Function: f.Name, File: f.File, Line: l[1]}
+	}
+	return frames
+}
@@ -0,0 +1,71 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type sourceMapCircuit struct {
+	A, B, C frontend.Variable
+}
+
+func (c *sourceMapCircuit) Define(api frontend.API) error {
+	api.Mul(c.A, c.B)
+	// division records debug information for its two constraints
+	api.AssertIsEqual(api.Div(c.A, c.B), c.C)
+	return nil
+}
+
+func TestSourceMap(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sourceMapCircuit{})
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(ccs.WriteSourceMap(&buf))
+	m, err := constraint.ReadSourceMap(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.GetNbConstraints(), m.NbConstraints)
+
+	nbWithStack := 0
+	for cID := 0; cID < ccs.GetNbConstraints(); cID++ {
+		expected := ccs.GetCallStack(cID)
+		assert.Equal(expected, m.Stack(cID), "constraint %d", cID)
+		if len(expected) > 0 {
+			nbWithStack++
+		}
+	}
+	assert.GreaterOrEqual(nbWithStack, 2)
+	assert.Nil(m.Stack(ccs.GetNbConstraints()))
+}
+
+func TestSourceMapOutOfRange(t *testing.T) {
+	assert := require.New(t)
+	valid := `{"version": 1, "nbConstraints": 4, "functions": [{"name": "f", "file": "f.go"}], "locations": [[0, 1]], "stacks": [[0]], "constraints": [[0, 1, 0]]}`
+	m, err := constraint.ReadSourceMap(bytes.NewBufferString(valid))
+	assert.NoError(err)
+	assert.Equal([]constraint.StackFrame{{Function: "f", File: "f.go", Line: 1}}, m.Stack(1))
+
+	for _, invalid := range []string{
+		`{"version": 1, "nbConstraints": 4, "functions": [], "locations": [[0, 1]], "stacks": [[0]], "constraints": [[0, 1, 0]]}`,
+		`{"version": 1, "nbConstraints": 4, "functions": [{"name": "f", "file": "f.go"}], "locations": [[0, 1]], "stacks": [[1]], "constraints": [[0, 1, 0]]}`,
+		`{"version": 1, "nbConstraints": 4, "functions": [{"name": "f", "file": "f.go"}], "locations": [[0, 1]], "stacks": [[0]], "constraints": [[0, 1, 1]]}`,
+		`{"version": 1, "nbConstraints": 4, "functions": [{"name": "f", "file": "f.go"}], "locations": [[0, 1]], "stacks": [[0]], "constraints": [[2, 4, 0]]}`,
+		`{"version": 1, "nbConstraints": 4, "functions": [{"name": "f", "file": "f.go"}], "locations": [[0, 1]], "stacks": [[0]], "constraints": [[0, 2, 0], [1, 3, 0]]}`,
+	} {
+		_, err := constraint.ReadSourceMap(bytes.NewBufferString(invalid))
+		assert.ErrorContains(err, "invalid source map", invalid)
+	}
+
+	// a source map which isn't read with ReadSourceMap
+	m.Stacks[0] = []int{-1}
+	assert.Nil(m.Stack(0))
+	m.Constraints[0][2] = 3
+	assert.Nil(m.Stack(0))
+}
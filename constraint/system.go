@@ -4,6 +4,7 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/blang/semver/v4"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint/solver"
 )
@@ -25,6 +26,12 @@ type ConstraintSystem interface {
 	// Returns a typed solution (R1CSSolution or SparseR1CSSolution) and nil otherwise.
 	Solve(witness witness.Witness, opts ...solver.Option) (any, error)
 
+	// SolvePartial solves the constraint system as far as possible without
+	// the inputs missing, given by their wire IDs, and returns the state of
+	// the solver, from which the solving is finished once they are known.
+	// See PartialSolution.
+	SolvePartial(witness witness.Witness, missing []int, opts ...solver.Option) (PartialSolution, error)
+
 	// GetNbVariables return number of internal, secret and public Variables
 	// Deprecated: use GetNbSecretVariables() instead
 	GetNbVariables() (internal, secret, public int)
@@ -34,6 +41,8 @@ type ConstraintSystem interface {
 	GetNbPublicVariables() int
 
 	GetNbInstructions() int
+	// GetNbHints returns the number of hint calls in the constraint system
+	GetNbHints() int
 	GetNbConstraints() int
 	GetNbCoefficients() int
 
@@ -66,18 +75,66 @@ type ConstraintSystem interface {
 
 	NewDebugInfo(errName string, i ...interface{}) DebugInfo
 
+	// NewMessageDebugInfo returns the debug info of an assertion failing with
+	// a user-defined message, formatted when solving.
+	NewMessageDebugInfo(format string, args ...interface{}) DebugInfo
+
 	// AttachDebugInfo enables attaching debug information to multiple constraints.
 	// This is more efficient than using the AddR1C(.., debugInfo) since it will store the
 	// debug information only once.
 	AttachDebugInfo(debugInfo DebugInfo, constraintID []int)
 
+	// SkipSolverData stops recording the data which is needed only to solve
+	// the system: the levels of the solver, the logs and the debug
+	// information. It is used when only the size of the system is of
+	// interest, the system can't be solved afterwards.
+	SkipSolverData()
+
 	// CheckUnconstrainedWires returns and error if the constraint system has wires that are not uniquely constrained.
 	// This is experimental.
 	CheckUnconstrainedWires() error
 
 	GetInstruction(int) Instruction
 
+	// GetCallStack returns the call stack recorded with the debug information
+	// of the constraint, innermost frame first, or nil if there is none.
+	GetCallStack(constraintID int) []StackFrame
+
+	// WriteSourceMap writes a JSON mapping of the constraints to their call
+	// stacks, see SourceMap.
+	WriteSourceMap(w io.Writer) error
+
+	// WriteStreamTo writes the system to w by chunks, for systems too large
+	// to be serialized in memory with WriteTo.
+	WriteStreamTo(w io.Writer) (int64, error)
+	// ReadStreamFrom reads the system written by WriteStreamTo.
+	ReadStreamFrom(r io.Reader) (int64, error)
+
+	// WriteDebugInfo writes the debug information of the system, to be
+	// stored separately from the system and attached with ReadDebugInfo.
+	WriteDebugInfo(w io.Writer) (int64, error)
+	// ReadDebugInfo attaches the debug information written by WriteDebugInfo.
+	ReadDebugInfo(r io.Reader) (int64, error)
+	// StripDebugInfo removes the debug information of the system.
+	StripDebugInfo()
+
 	GetCoefficient(i int) Element
+
+	// Iterate calls f on the decompressed constraints of the system, in
+	// order, until f returns false. See ConstraintView.
+	Iterate(f func(cID int, view ConstraintView) bool)
+
+	// ConstraintsInvolving returns the constraints, hints and gadgets
+	// referencing the wire, see WireUsage.
+	ConstraintsInvolving(wireID int) WireUsage
+
+	// DependencyGraph returns the dependency graph of the instructions of the
+	// system, annotated with the levels of the solver.
+	DependencyGraph() DependencyGraph
+
+	// SetGnarkVersion sets the gnark version recorded in the constraint system
+	// when it is serialized, see package io/migrate.
+	SetGnarkVersion(v semver.Version)
 }
 
 type CustomizableSystem interface {
@@ -88,3 +145,26 @@ type CustomizableSystem interface {
 	// if the blueprint declared any outputs.
 	AddInstruction(bID BlueprintID, calldata []uint32) []uint32
 }
+
+// PartialSolution is the state of the solver after a call to
+// ConstraintSystem.SolvePartial, with some of the inputs missing. The wires
+// and the instructions are indexed as in WireUsage: the wires as in the
+// solution (public inputs, then secret inputs, then internal wires), and the
+// hints by their instruction.
+type PartialSolution interface {
+	// SolvedWires returns the IDs of the solved wires, in increasing order.
+	SolvedWires() []int
+
+	// Blocking returns the hints and the constraints which are not solved
+	// while the instructions they depend on are: they read one of the missing
+	// inputs, or are deferred to Finish as the lookups and the memory
+	// accesses, whose blueprints have a state.
+	Blocking() (hints, constraints []int)
+
+	// Finish solves the rest of the constraint system with the full witness,
+	// and returns the solution as ConstraintSystem.Solve. The inputs known
+	// when solving partially must have the same values in the witness.
+	// Finish may be called several times, for example with different values
+	// of the missing inputs.
+	Finish(witness witness.Witness, opts ...solver.Option) (any, error)
+}
@@ -0,0 +1,324 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidSystem is returned by Validate when the constraint system breaks
+// one of its internal invariants.
+var ErrInvalidSystem = errors.New("invalid constraint system")
+
+// Validate checks the internal invariants of a constraint system, so that a
+// system deserialized from an untrusted source can be rejected before it is
+// solved or given to a prover, which assume them and may panic otherwise:
+//
+//   - the instructions reference existing blueprints, of the kind of the
+//     system for the ones defining constraints, and their calldata and
+//     constraint offsets are contiguous;
+//   - the wires referenced by the instructions, the commitments, the logs and
+//     the debug information are in bounds, as are the coefficients of the
+//     constraints and of the hints;
+//   - each internal wire is solved by exactly one instruction, the levels list
+//     each instruction once, and an instruction only reads wires solved at a
+//     previous level;
+//   - the hints called by the instructions are declared in the hint
+//     dependencies of the system.
+//
+// The custom blueprints (for example of the lookups and of the memory) are
+// checked through the wires they report to the instruction tree; the
+// coefficients they read from their calldata are not checked.
+//
+// Validate returns an error wrapping ErrInvalidSystem for the first invariant
+// found broken. It runs in time linear in the size of the system.
+func Validate(cs ConstraintSystem) error {
+	s, ok := cs.(interface{ core() *System })
+	if !ok {
+		return fmt.Errorf("unsupported constraint system %T", cs)
+	}
+	if err := s.core().validate(cs.GetNbCoefficients()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSystem, err)
+	}
+	return nil
+}
+
+// core returns the system, for the functions of the package taking a
+// ConstraintSystem.
+func (system *System) core() *System {
+	return system
+}
+
+func (system *System) validate(nbCoefficients int) error {
+	if system.Type != SystemR1CS && system.Type != SystemSparseR1CS {
+		return fmt.Errorf("unknown system type %d", system.Type)
+	}
+	for i, b := range system.Blueprints {
+		if b == nil {
+			return fmt.Errorf("blueprint %d is nil", i)
+		}
+	}
+	if system.NbInternalVariables < 0 || uint64(system.internalWireOffset())+uint64(system.NbInternalVariables) >= math.MaxUint32 {
+		return fmt.Errorf("invalid number of wires")
+	}
+
+	// the offsets of the instructions, before decoding their calldata
+	var (
+		startCallData    uint64
+		constraintOffset int
+	)
+	for i, pi := range system.Instructions {
+		if int(pi.BlueprintID) >= len(system.Blueprints) {
+			return fmt.Errorf("instruction %d: unknown blueprint %d", i, pi.BlueprintID)
+		}
+		blueprint := system.Blueprints[pi.BlueprintID]
+		if pi.StartCallData != startCallData {
+			return fmt.Errorf("instruction %d: calldata starts at %d, expected %d", i, pi.StartCallData, startCallData)
+		}
+		size := blueprint.CalldataSize()
+		if size < 0 {
+			if pi.StartCallData >= uint64(len(system.CallData)) {
+				return fmt.Errorf("instruction %d: calldata out of bounds", i)
+			}
+			size = int(system.CallData[pi.StartCallData])
+			if size < 1 {
+				return fmt.Errorf("instruction %d: empty calldata", i)
+			}
+		}
+		startCallData += uint64(size)
+		if startCallData > uint64(len(system.CallData)) {
+			return fmt.Errorf("instruction %d: calldata out of bounds", i)
+		}
+
+		if int(pi.ConstraintOffset) != constraintOffset {
+			return fmt.Errorf("instruction %d: constraint offset %d, expected %d", i, pi.ConstraintOffset, constraintOffset)
+		}
+		if nbConstraints := blueprint.NbConstraints(); nbConstraints > 0 {
+			_, isR1C := blueprint.(BlueprintR1C)
+			_, isSparseR1C := blueprint.(BlueprintSparseR1C)
+			if (system.Type == SystemR1CS && !isR1C) || (system.Type == SystemSparseR1CS && !isSparseR1C) {
+				return fmt.Errorf("instruction %d: blueprint %s does not define constraints of the system", i, blueprintName(blueprint))
+			}
+			constraintOffset += nbConstraints
+		}
+	}
+	if startCallData != uint64(len(system.CallData)) {
+		return fmt.Errorf("%d calldata words not used by the instructions", uint64(len(system.CallData))-startCallData)
+	}
+	if constraintOffset != system.NbConstraints {
+		return fmt.Errorf("%d constraints, expected %d", constraintOffset, system.NbConstraints)
+	}
+
+	// the levels
+	levelOf := make([]int, len(system.Instructions))
+	for i := range levelOf {
+		levelOf[i] = -1
+	}
+	for l, level := range system.Levels {
+		for _, iID := range level {
+			if int(iID) >= len(levelOf) {
+				return fmt.Errorf("level %d: unknown instruction %d", l, iID)
+			}
+			if levelOf[iID] != -1 {
+				return fmt.Errorf("instruction %d is in levels %d and %d", iID, levelOf[iID], l)
+			}
+			levelOf[iID] = l
+		}
+	}
+	for i, l := range levelOf {
+		if l == -1 {
+			return fmt.Errorf("instruction %d is in no level", i)
+		}
+	}
+
+	// the wires and the coefficients of the instructions
+	v := systemValidator{
+		nbWires:        system.internalWireOffset() + uint32(system.NbInternalVariables),
+		nbCoefficients: nbCoefficients,
+		tree: validationTree{
+			offset:   system.internalWireOffset(),
+			producer: make([]int, system.NbInternalVariables),
+			levelOf:  levelOf,
+		},
+	}
+	for i := range v.tree.producer {
+		v.tree.producer[i] = -1
+	}
+	for i := range system.Instructions {
+		if err := v.instruction(system, i); err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+	}
+	for w, p := range v.tree.producer {
+		if p == -1 {
+			return fmt.Errorf("wire %d is solved by no instruction", w+int(v.tree.offset))
+		}
+	}
+
+	// the references to the wires and to the constraints outside of the
+	// instructions
+	switch commitments := system.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i, c := range commitments {
+			wires := append([]int{c.CommitmentIndex}, c.PublicAndCommitmentCommitted...)
+			for _, w := range append(wires, c.PrivateCommitted...) {
+				if w < 0 || w >= int(v.nbWires) {
+					return fmt.Errorf("commitment %d: wire %d out of bounds", i, w)
+				}
+			}
+		}
+	case PlonkCommitments:
+		for i, c := range commitments {
+			for _, cID := range append([]int{c.CommitmentIndex}, c.Committed...) {
+				if cID < 0 || cID >= system.NbConstraints {
+					return fmt.Errorf("commitment %d: constraint %d out of bounds", i, cID)
+				}
+			}
+		}
+	}
+	for i := range system.Logs {
+		if err := v.linearExpressions(system.Logs[i].ToResolve...); err != nil {
+			return fmt.Errorf("log %d: %w", i, err)
+		}
+	}
+	for i := range system.DebugInfo {
+		if err := v.linearExpressions(system.DebugInfo[i].ToResolve...); err != nil {
+			return fmt.Errorf("debug info %d: %w", i, err)
+		}
+	}
+	for cID, dID := range system.MDebug {
+		if cID < 0 || cID >= system.NbConstraints || dID < 0 || dID >= len(system.DebugInfo) {
+			return fmt.Errorf("debug info %d of constraint %d out of bounds", dID, cID)
+		}
+	}
+	return nil
+}
+
+// systemValidator checks the instructions of a system, see System.validate.
+type systemValidator struct {
+	nbWires        uint32
+	nbCoefficients int
+	tree           validationTree
+
+	r1c       R1C
+	sparseR1C SparseR1C
+	hint      HintMapping
+}
+
+// instruction checks the instruction i, whose calldata is in bounds. The
+// blueprints decode the calldata without checking it, so that their panics on
+// malformed calldata are returned as errors.
+func (v *systemValidator) instruction(system *System, i int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed calldata: %v", r)
+		}
+	}()
+
+	pi := system.Instructions[i]
+	blueprint := system.Blueprints[pi.BlueprintID]
+	inst := pi.Unpack(system)
+	if uint64(inst.WireOffset)+uint64(blueprint.NbOutputs(inst)) > uint64(v.nbWires) {
+		return errors.New("output wires out of bounds")
+	}
+
+	switch b := blueprint.(type) {
+	case BlueprintR1C:
+		b.DecompressR1C(&v.r1c, inst)
+		err = v.linearExpressions(v.r1c.L, v.r1c.R, v.r1c.O)
+	case BlueprintSparseR1C:
+		b.DecompressSparseR1C(&v.sparseR1C, inst)
+		c := &v.sparseR1C
+		err = v.terms([]uint32{c.QL, c.QR, c.QO, c.QM, c.QC}, []uint32{c.XA, c.XB, c.XC})
+	case BlueprintHint:
+		b.DecompressHint(&v.hint, inst)
+		if _, ok := system.MHintsDependencies[v.hint.HintID]; !ok {
+			return fmt.Errorf("hint %d is not in the hint dependencies", v.hint.HintID)
+		}
+		err = v.linearExpressions(v.hint.Inputs...)
+	}
+	if err != nil {
+		return err
+	}
+
+	v.tree.current = i
+	blueprint.UpdateInstructionTree(inst, &v.tree)
+	return v.tree.err
+}
+
+// linearExpressions checks the wires and the coefficients of the terms of the
+// expressions.
+func (v *systemValidator) linearExpressions(expressions ...LinearExpression) error {
+	for _, l := range expressions {
+		for _, t := range l {
+			if err := v.terms([]uint32{t.CID}, []uint32{t.VID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *systemValidator) terms(coefficients, wires []uint32) error {
+	for _, cID := range coefficients {
+		if int(cID) >= v.nbCoefficients {
+			return fmt.Errorf("coefficient %d out of bounds", cID)
+		}
+	}
+	for _, w := range wires {
+		if w >= v.nbWires && w != math.MaxUint32 {
+			return fmt.Errorf("wire %d out of bounds", w)
+		}
+	}
+	return nil
+}
+
+// validationTree implements InstructionTree to check the wires read and solved
+// by the instructions against the levels of the system.
+type validationTree struct {
+	offset   uint32
+	producer []int // internal wire -> instruction solving it, -1 if not solved yet
+	levelOf  []int // instruction -> level
+	current  int
+	err      error
+}
+
+func (t *validationTree) fail(format string, args ...any) {
+	if t.err == nil {
+		t.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (t *validationTree) InsertWire(wire uint32, _ Level) {
+	if wire < t.offset || wire-t.offset >= uint32(len(t.producer)) {
+		t.fail("solves wire %d, which is not an internal wire", wire)
+		return
+	}
+	if p := t.producer[wire-t.offset]; p != -1 {
+		t.fail("solves wire %d, already solved by instruction %d", wire, p)
+		return
+	}
+	t.producer[wire-t.offset] = t.current
+}
+
+func (t *validationTree) HasWire(wire uint32) bool {
+	if wire == math.MaxUint32 || wire < t.offset {
+		return false
+	}
+	if wire-t.offset >= uint32(len(t.producer)) {
+		t.fail("wire %d out of bounds", wire)
+		return false
+	}
+	return true
+}
+
+func (t *validationTree) GetWireLevel(wire uint32) Level {
+	p := t.producer[wire-t.offset]
+	if p == -1 {
+		return LevelUnset
+	}
+	if p != t.current && t.levelOf[p] >= t.levelOf[t.current] {
+		t.fail("reads wire %d at level %d, solved at level %d", wire, t.levelOf[t.current], t.levelOf[p])
+	}
+	return Level(t.levelOf[p])
+}
@@ -0,0 +1,93 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type validateCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *validateCircuit) Define(api frontend.API) error {
+	inv, err := api.Compiler().NewHint(solver.InvZeroHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(inv[0], c.X), 1)
+	commitment, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(api.Add(commitment, c.X), c.Y)
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &validateCircuit{})
+		assert.NoError(err)
+		assert.NoError(constraint.Validate(ccs))
+	}
+
+	// read a fresh copy of the system for each tampering
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &validateCircuit{})
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = ccs.WriteTo(&buf)
+	assert.NoError(err)
+	load := func() *cs_bn254.SparseR1CS {
+		res := &cs_bn254.SparseR1CS{}
+		_, err := res.ReadFrom(bytes.NewReader(buf.Bytes()))
+		assert.NoError(err)
+		assert.NoError(constraint.Validate(res))
+		return res
+	}
+	nbWires := uint32(ccs.GetNbInternalVariables() + ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables())
+
+	for name, tamper := range map[string]func(s *cs_bn254.SparseR1CS){
+		"truncated calldata": func(s *cs_bn254.SparseR1CS) {
+			s.CallData = s.CallData[:len(s.CallData)-1]
+		},
+		"wire out of bounds": func(s *cs_bn254.SparseR1CS) {
+			s.CallData[len(s.CallData)-9] = nbWires
+		},
+		"coefficient out of bounds": func(s *cs_bn254.SparseR1CS) {
+			s.CallData[len(s.CallData)-6] = uint32(len(s.Coefficients))
+		},
+		"missing hint dependency": func(s *cs_bn254.SparseR1CS) {
+			clear(s.MHintsDependencies)
+		},
+		"levels out of order": func(s *cs_bn254.SparseR1CS) {
+			s.Levels[0], s.Levels[len(s.Levels)-1] = s.Levels[len(s.Levels)-1], s.Levels[0]
+		},
+		"instruction in no level": func(s *cs_bn254.SparseR1CS) {
+			s.Levels = s.Levels[:len(s.Levels)-1]
+		},
+		"wire solved by no instruction": func(s *cs_bn254.SparseR1CS) {
+			s.NbInternalVariables++
+		},
+		"constraint count": func(s *cs_bn254.SparseR1CS) {
+			s.NbConstraints--
+		},
+		"debug info out of bounds": func(s *cs_bn254.SparseR1CS) {
+			s.MDebug[s.NbConstraints] = 0
+		},
+	} {
+		s := load()
+		tamper(s)
+		assert.ErrorIs(constraint.Validate(s), constraint.ErrInvalidSystem, name)
+	}
+}
@@ -0,0 +1,85 @@
+package constraint
+
+// WireUsage lists where a wire is referenced in a constraint system. See
+// System.ConstraintsInvolving.
+type WireUsage struct {
+	// Constraints are the IDs of the constraints in which the wire appears
+	// with a non-zero coefficient, in order. The constraints of custom
+	// blueprints are included if the blueprint reads or solves the wire.
+	Constraints []int
+	// Hints are the IDs of the instructions without constraints, such as hint
+	// calls, reading or solving the wire, in order.
+	Hints []int
+	// Gadgets are the call stack frames recorded with the debug information of
+	// the constraints, in order of first appearance and without duplicates.
+	Gadgets []StackFrame
+}
+
+// ConstraintsInvolving returns the constraints and hints referencing the wire,
+// indexed as in the solution: public inputs, then secret inputs, then internal
+// wires. For instance, a secret input involved in no constraint is
+// unconstrained.
+//
+// As Blame, it replays the blueprints of the system, in time linear in the
+// size of the system.
+func (system *System) ConstraintsInvolving(wireID int) WireUsage {
+	var (
+		res       WireUsage
+		r1c       R1C
+		sparseR1C SparseR1C
+	)
+	wire := uint32(wireID)
+	rec, reads := system.replayDependencies()
+	solvedBy := -1
+	if wire >= rec.offset && int(wire-rec.offset) < len(rec.producer) {
+		solvedBy = rec.producer[wire-rec.offset]
+	}
+	inExpression := func(l LinearExpression) bool {
+		for _, t := range l {
+			if t.VID == wire && t.CID != CoeffIdZero {
+				return true
+			}
+		}
+		return false
+	}
+
+	seenFrames := make(map[StackFrame]struct{})
+	for iID, pi := range system.Instructions {
+		blueprint := system.Blueprints[pi.BlueprintID]
+		nbConstraints := blueprint.NbConstraints()
+		var involved bool
+		switch b := blueprint.(type) {
+		case BlueprintR1C:
+			b.DecompressR1C(&r1c, pi.Unpack(system))
+			involved = inExpression(r1c.L) || inExpression(r1c.R) || inExpression(r1c.O)
+		case BlueprintSparseR1C:
+			b.DecompressSparseR1C(&sparseR1C, pi.Unpack(system))
+			qM := sparseR1C.QM != CoeffIdZero
+			involved = (sparseR1C.XA == wire && (qM || sparseR1C.QL != CoeffIdZero)) ||
+				(sparseR1C.XB == wire && (qM || sparseR1C.QR != CoeffIdZero)) ||
+				(sparseR1C.XC == wire && sparseR1C.QO != CoeffIdZero)
+		default:
+			involved = iID == solvedBy
+			for _, w := range reads[iID] {
+				involved = involved || w == wire
+			}
+		}
+		if !involved {
+			continue
+		}
+		if nbConstraints == 0 {
+			res.Hints = append(res.Hints, iID)
+			continue
+		}
+		for cID := int(pi.ConstraintOffset); cID < int(pi.ConstraintOffset)+nbConstraints; cID++ {
+			res.Constraints = append(res.Constraints, cID)
+			for _, f := range system.GetCallStack(cID) {
+				if _, ok := seenFrames[f]; !ok {
+					seenFrames[f] = struct{}{}
+					res.Gadgets = append(res.Gadgets, f)
+				}
+			}
+		}
+	}
+	return res
+}
@@ -2,6 +2,7 @@ package gnark_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -48,11 +49,11 @@ func TestPrintln(t *testing.T) {
 	witness.B = 11
 
 	var expected bytes.Buffer
-	expected.WriteString("debug_test.go:30 > 13 is the addition\n")
-	expected.WriteString("debug_test.go:32 > 26 42\n")
-	expected.WriteString("debug_test.go:34 > bits 1\n")
-	expected.WriteString("debug_test.go:35 > circuit {A: 2, B: 11}\n")
-	expected.WriteString("debug_test.go:39 > m .*\n")
+	expected.WriteString("debug_test.go:31 > 13 is the addition\n")
+	expected.WriteString("debug_test.go:33 > 26 42\n")
+	expected.WriteString("debug_test.go:35 > bits 1\n")
+	expected.WriteString("debug_test.go:36 > circuit {A: 2, B: 11}\n")
+	expected.WriteString("debug_test.go:40 > m .*\n")
 
 	{
 		trace, _ := getGroth16Trace(&circuit, &witness)
@@ -65,6 +66,59 @@ func TestPrintln(t *testing.T) {
 	}
 }
 
+// -------------------------------------------------------------------------------------------------
+// test structured logs
+type logCircuit struct {
+	A, B frontend.Variable
+}
+
+func (circuit *logCircuit) Define(api frontend.API) error {
+	c := api.Add(circuit.A, circuit.B)
+	api.(frontend.StructuredLogger).Log("a", circuit.A, "sum", c, "ratio", "100%", "circuit", circuit)
+	api.(frontend.LevelLogger).LogWithLevel(zerolog.WarnLevel, "b", circuit.B)
+	return nil
+}
+
+func TestLog(t *testing.T) {
+	assert := require.New(t)
+
+	witness, err := frontend.NewWitness(&logCircuit{A: 2, B: 11}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &logCircuit{})
+		assert.NoError(err)
+
+		var buf bytes.Buffer
+		_, err = ccs.Solve(witness, solver.WithLogSink(&buf))
+		assert.NoError(err)
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		assert.Len(lines, 2)
+
+		var entry map[string]interface{}
+		assert.NoError(json.Unmarshal(lines[0], &entry))
+		assert.Equal("debug", entry["level"])
+		assert.Equal("debug_test.go:77", entry["caller"])
+		assert.Equal("2", entry["a"])
+		assert.Equal("13", entry["sum"])
+		assert.Equal("100%", entry["ratio"])
+		assert.Equal("{A: 2, B: 11}", entry["circuit"])
+
+		entry = nil
+		assert.NoError(json.Unmarshal(lines[1], &entry))
+		assert.Equal("warn", entry["level"])
+		assert.Equal("11", entry["b"])
+
+		// entries below the logger level are filtered out
+		buf.Reset()
+		_, err = ccs.Solve(witness, solver.WithLogger(zerolog.New(&buf).Level(zerolog.InfoLevel)))
+		assert.NoError(err)
+		assert.NotContains(buf.String(), `"sum"`)
+		assert.Contains(buf.String(), `"b":"11"`)
+	}
+}
+
 // -------------------------------------------------------------------------------------------------
 // Div by 0
 type divBy0Trace struct {
@@ -153,6 +207,78 @@ func TestTraceNotEqual(t *testing.T) {
 	}
 }
 
+// -------------------------------------------------------------------------------------------------
+// Assertion messages
+type messageTrace struct {
+	Nullifier, Spent frontend.Variable
+	Amount, Balance  frontend.Variable
+}
+
+func (circuit *messageTrace) Define(api frontend.API) error {
+	m := api.(frontend.MessageAsserter)
+	m.AssertIsDifferentWithMessage(circuit.Nullifier, circuit.Spent, "nullifier %d already spent", circuit.Nullifier)
+	m.AssertIsLessOrEqualWithMessage(circuit.Amount, circuit.Balance, "amount %v exceeds the balance %v (100%%)", circuit.Amount, circuit.Balance)
+	return nil
+}
+
+func TestTraceAssertionMessage(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit messageTrace
+	spent := messageTrace{Nullifier: 42, Spent: 42, Amount: 1, Balance: 2}
+	overdrawn := messageTrace{Nullifier: 42, Spent: 43, Amount: 3, Balance: 2}
+
+	for _, trace := range []func(frontend.Circuit, frontend.Circuit) (string, error){getGroth16Trace, getPlonkTrace} {
+		_, err := trace(&circuit, &spent)
+		assert.Error(err)
+		assert.Contains(err.Error(), "is not satisfied: nullifier 42 already spent")
+		assert.Contains(err.Error(), "(*messageTrace).Define")
+
+		_, err = trace(&circuit, &overdrawn)
+		assert.Error(err)
+		assert.Contains(err.Error(), "is not satisfied: amount 3 exceeds the balance 2 (100%)")
+	}
+}
+
+// -------------------------------------------------------------------------------------------------
+// Blame
+type blameTrace struct {
+	A, B, C, D frontend.Variable
+}
+
+func blameGadget(api frontend.API, b, c frontend.Variable) frontend.Variable {
+	return api.Div(b, c)
+}
+
+func (circuit *blameTrace) Define(api frontend.API) error {
+	api.AssertIsEqual(circuit.A, blameGadget(api, circuit.B, circuit.C))
+	api.AssertIsEqual(circuit.D, 4)
+	return nil
+}
+
+func TestTraceBlame(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit blameTrace
+	witness := blameTrace{A: 1, B: 6, C: 2, D: 4}
+
+	{
+		_, err := getGroth16Trace(&circuit, &witness)
+		assert.Error(err)
+		assert.Contains(err.Error(), "depends on inputs: A=1, B=6, C=2")
+		assert.NotContains(err.Error(), "D=4")
+		assert.Contains(err.Error(), "through gadgets:")
+		assert.Contains(err.Error(), "gnark_test.blameGadget")
+	}
+
+	{
+		_, err := getPlonkTrace(&circuit, &witness)
+		assert.Error(err)
+		assert.Contains(err.Error(), "depends on inputs: A=1, B=6, C=2")
+		assert.NotContains(err.Error(), "D=4")
+	}
+}
+
 func getPlonkTrace(circuit, w frontend.Circuit) (string, error) {
 	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, circuit)
 	if err != nil {
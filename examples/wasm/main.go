@@ -0,0 +1,122 @@
+//go:build js && wasm
+
+// Command wasm exposes a Groth16 prover and verifier on BN254 to JavaScript,
+// so that small proofs can be generated client side:
+//
+//	GOOS=js GOARCH=wasm go build -o gnark.wasm ./examples/wasm
+//
+// Once the module is started (see wasm_exec.js in the Go distribution), the
+// following global functions are available. Their arguments and results are
+// Uint8Array holding gnark objects in their binary format (WriteTo /
+// MarshalBinary):
+//
+//	gnarkProve(ccs, pk, fullWitness) -> {proof, publicWitness} | {error}
+//	gnarkVerify(vk, proof, publicWitness) -> {} | {error}
+package main
+
+import (
+	"bytes"
+	"syscall/js"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func main() {
+	js.Global().Set("gnarkProve", js.FuncOf(jsProve))
+	js.Global().Set("gnarkVerify", js.FuncOf(jsVerify))
+	select {}
+}
+
+func jsProve(_ js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return jsError("gnarkProve expects 3 arguments")
+	}
+	proof, publicWitness, err := prove(jsBytes(args[0]), jsBytes(args[1]), jsBytes(args[2]))
+	if err != nil {
+		return jsError(err.Error())
+	}
+	return map[string]any{"proof": toJS(proof), "publicWitness": toJS(publicWitness)}
+}
+
+func jsVerify(_ js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return jsError("gnarkVerify expects 3 arguments")
+	}
+	if err := verify(jsBytes(args[0]), jsBytes(args[1]), jsBytes(args[2])); err != nil {
+		return jsError(err.Error())
+	}
+	return map[string]any{}
+}
+
+func jsError(msg string) any {
+	return map[string]any{"error": msg}
+}
+
+func jsBytes(v js.Value) []byte {
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
+
+func toJS(b []byte) js.Value {
+	v := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(v, b)
+	return v
+}
+
+func prove(ccsData, pkData, witnessData []byte) (proof, publicWitness []byte, err error) {
+	ccs := groth16.NewCS(ecc.BN254)
+	if _, err := ccs.ReadFrom(bytes.NewReader(ccsData)); err != nil {
+		return nil, nil, err
+	}
+	pk := groth16.NewProvingKey(ecc.BN254)
+	if _, err := pk.ReadFrom(bytes.NewReader(pkData)); err != nil {
+		return nil, nil, err
+	}
+	fullWitness, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := fullWitness.UnmarshalBinary(witnessData); err != nil {
+		return nil, nil, err
+	}
+
+	p, err := groth16.Prove(ccs, pk, fullWitness)
+	if err != nil {
+		return nil, nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := p.WriteTo(&buf); err != nil {
+		return nil, nil, err
+	}
+	pw, err := fullWitness.Public()
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err = pw.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), publicWitness, nil
+}
+
+func verify(vkData, proofData, publicWitnessData []byte) error {
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if _, err := vk.ReadFrom(bytes.NewReader(vkData)); err != nil {
+		return err
+	}
+	proof := groth16.NewProof(ecc.BN254)
+	if _, err := proof.ReadFrom(bytes.NewReader(proofData)); err != nil {
+		return err
+	}
+	publicWitness, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return err
+	}
+	if err := publicWitness.UnmarshalBinary(publicWitnessData); err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, publicWitness)
+}
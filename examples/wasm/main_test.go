@@ -0,0 +1,46 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func serialize(t *testing.T, o io.WriterTo) []byte {
+	var buf bytes.Buffer
+	_, err := o.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestProveVerify(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&cubic.Circuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	witnessData, err := w.MarshalBinary()
+	assert.NoError(err)
+
+	proof, publicWitness, err := prove(serialize(t, ccs), serialize(t, pk), witnessData)
+	assert.NoError(err)
+	assert.NoError(verify(serialize(t, vk), proof, publicWitness))
+
+	w, err = frontend.NewWitness(&cubic.Circuit{Y: 36}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	publicWitness, err = w.MarshalBinary()
+	assert.NoError(err)
+	assert.Error(verify(serialize(t, vk), proof, publicWitness))
+}
@@ -20,6 +20,7 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/constraint/solver"
+	"github.com/rs/zerolog"
 )
 
 // API represents the available functions to circuit developers
@@ -157,6 +158,76 @@ type BatchInverter interface {
 	BatchInvert(i1 []Variable) []Variable
 }
 
+// MessageAsserter is implemented by builders which attach a message to the
+// constraints of an assertion, the solver reporting fmt.Sprintf(format,
+// args...) when the assertion fails. The arguments which are variables are
+// formatted with their values:
+//
+//	if m, ok := api.(frontend.MessageAsserter); ok {
+//		m.AssertIsLessOrEqualWithMessage(amount, balance, "amount %d exceeds the balance", amount)
+//	} else {
+//		api.AssertIsLessOrEqual(amount, balance)
+//	}
+type MessageAsserter interface {
+	// AssertIsEqualWithMessage is as API.AssertIsEqual, with a message
+	// reported on failure.
+	AssertIsEqualWithMessage(i1, i2 Variable, format string, args ...interface{})
+
+	// AssertIsDifferentWithMessage is as API.AssertIsDifferent, with a message
+	// reported on failure.
+	AssertIsDifferentWithMessage(i1, i2 Variable, format string, args ...interface{})
+
+	// AssertIsBooleanWithMessage is as API.AssertIsBoolean, with a message
+	// reported on failure.
+	AssertIsBooleanWithMessage(i1 Variable, format string, args ...interface{})
+
+	// AssertIsLessOrEqualWithMessage is as API.AssertIsLessOrEqual, with a
+	// message reported on failure.
+	AssertIsLessOrEqualWithMessage(v Variable, bound Variable, format string, args ...interface{})
+}
+
+// CompilerAsserter is implemented by builders which check conditions on
+// constants at compile time, typically the parameters of a gadget, so that a
+// misconfigured gadget fails at compile time instead of producing a circuit
+// which can't be satisfied:
+//
+//	if a, ok := api.(frontend.CompilerAsserter); ok {
+//		a.CompilerAssert(api.IsZero(api.Sub(nbLimbs, 4)), "expected 4 limbs, got %d", nbLimbs)
+//	}
+type CompilerAsserter interface {
+	// CompilerAssert fails the compilation if condition is 0, with the
+	// formatted message and the location of the call. The condition must be
+	// a boolean computed from constants only. It doesn't add any constraint
+	// and fails the compilation if the condition is not a constant.
+	CompilerAssert(condition Variable, format string, args ...interface{})
+}
+
+// StructuredLogger is implemented by builders which record structured log
+// entries from key-value pairs, where keys are strings and values are
+// variables, constants or structs of variables (as in API.Println):
+//
+//	if l, ok := api.(frontend.StructuredLogger); ok {
+//		l.Log("step", i, "acc", acc)
+//	}
+//
+// The values are resolved at runtime by the solver, which logs the entry with
+// one field per key. See [solver.WithLogSink] to write the entries as JSON.
+type StructuredLogger interface {
+	Log(keyvals ...Variable)
+}
+
+// LevelLogger is implemented by builders which record structured log entries
+// at a given level (see [StructuredLogger], which logs at zerolog.DebugLevel).
+// The solver drops the entries below the level of its logger, so that verbose
+// gadget logs can be filtered out with [solver.WithLogger]:
+//
+//	if l, ok := api.(frontend.LevelLogger); ok {
+//		l.LogWithLevel(zerolog.InfoLevel, "round", i, "state", state)
+//	}
+type LevelLogger interface {
+	LogWithLevel(level zerolog.Level, keyvals ...Variable)
+}
+
 type PlonkAPI interface {
 	// EvaluatePlonkExpression returns res = qL.a + qR.b + qM.ab + qC
 	EvaluatePlonkExpression(a, b Variable, qL, qR, qM, qC int) Variable
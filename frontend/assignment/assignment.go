@@ -0,0 +1,238 @@
+// Package assignment provides reflection-based helpers to build the
+// assignments of circuits with nested structures, such as slices of generic
+// recursion types, without writing a dedicated assignment function for each
+// outer circuit.
+//
+// The values are located by a path of Go field names (or gnark tag names) and
+// slice or array indexes, for example "Proofs[2].Ar.X". Nil pointers are
+// allocated and slices are grown as needed along the path, so that an empty
+// assignment can be filled incrementally.
+package assignment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+var tVariable = reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+
+// Set assigns value to the element of the circuit at path. circuit must be a
+// pointer to the assignment. value is either assignable to the type of the
+// element, for example the result of emulated.ValueOf for an emulated
+// element, or any value accepted as frontend.Variable if the element is a
+// frontend.Variable.
+func Set(circuit frontend.Circuit, path string, value any) error {
+	v, err := lookup(circuit, path)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fmt.Errorf("%s: nil value", path)
+	}
+	rv := reflect.ValueOf(value)
+	if v.Type() != tVariable && !rv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("%s: cannot assign %s to %s", path, rv.Type(), v.Type())
+	}
+	v.Set(rv)
+	return nil
+}
+
+// FillFromJSON assigns the values of the JSON object data to the circuit. The
+// object mirrors the circuit struct: objects for the structs, keyed by Go field
+// names or gnark tag names, arrays for the slices and arrays, and numbers or
+// strings (decimal or 0x-prefixed hexadecimal) for the frontend.Variable
+// leaves. The slices of the circuit are resized to the length of the JSON
+// arrays and the fields missing from the JSON object are left unchanged. For
+// instance an emulated element is given by its limbs:
+//
+//	{"Proofs": [{"Ar": {"X": {"Limbs": ["0x1", "0x2", "0x3", "0x4"]}, ...}}]}
+func FillFromJSON(circuit frontend.Circuit, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(circuit)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("the circuit must be a non-nil pointer")
+	}
+	return fill(v.Elem(), decoded, "")
+}
+
+// lookup returns the settable value at path in circuit.
+func lookup(circuit frontend.Circuit, path string) (reflect.Value, error) {
+	v := reflect.ValueOf(circuit)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return v, errors.New("the circuit must be a non-nil pointer")
+	}
+	v = v.Elem()
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes, err := parseSegment(segment)
+		if err != nil {
+			return v, fmt.Errorf("%s: %w", path, err)
+		}
+		if v = deref(v); v.Kind() != reflect.Struct {
+			return v, fmt.Errorf("%s: %s is not a struct", path, v.Type())
+		}
+		f, ok := fieldByName(v, name)
+		if !ok {
+			return v, fmt.Errorf("%s: no field %s in %s", path, name, v.Type())
+		}
+		v = f
+		for _, i := range indexes {
+			switch v = deref(v); v.Kind() {
+			case reflect.Slice:
+				if i >= v.Len() {
+					grow(v, i+1)
+				}
+			case reflect.Array:
+				if i >= v.Len() {
+					return v, fmt.Errorf("%s: index %d out of range of %s", path, i, v.Type())
+				}
+			default:
+				return v, fmt.Errorf("%s: %s is not a slice nor an array", path, v.Type())
+			}
+			v = v.Index(i)
+		}
+	}
+	return v, nil
+}
+
+// parseSegment parses "Name[1][2]".
+func parseSegment(segment string) (string, []int, error) {
+	name, rest, _ := strings.Cut(segment, "[")
+	if name == "" {
+		return "", nil, fmt.Errorf("missing field name in %q", segment)
+	}
+	var indexes []int
+	for rest != "" {
+		index, after, ok := strings.Cut(rest, "]")
+		if !ok {
+			return "", nil, fmt.Errorf("missing ] in %q", segment)
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 {
+			return "", nil, fmt.Errorf("invalid index %q in %q", index, segment)
+		}
+		indexes = append(indexes, i)
+		if after != "" && !strings.HasPrefix(after, "[") {
+			return "", nil, fmt.Errorf("unexpected %q in %q", after, segment)
+		}
+		rest = strings.TrimPrefix(after, "[")
+	}
+	return name, indexes, nil
+}
+
+// fieldByName returns the exported field of the struct v with the Go name or
+// the gnark tag name.
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tagName, _, _ := strings.Cut(f.Tag.Get("gnark"), ",")
+		if f.Name == name || (tagName != "" && tagName != "-" && tagName == name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// deref allocates the nil pointers and returns the pointed value.
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// grow sets the length of the slice v to n, keeping its first elements.
+func grow(v reflect.Value, n int) {
+	s := reflect.MakeSlice(v.Type(), n, n)
+	reflect.Copy(s, v)
+	v.Set(s)
+}
+
+func fill(v reflect.Value, data any, path string) error {
+	if v.Type() == tVariable {
+		var s string
+		switch d := data.(type) {
+		case json.Number:
+			s = d.String()
+		case string:
+			s = d
+		default:
+			return fmt.Errorf("%s: expected a number or a string, got %T", path, data)
+		}
+		b, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return fmt.Errorf("%s: invalid value %q", path, s)
+		}
+		v.Set(reflect.ValueOf(b))
+		return nil
+	}
+	switch v = deref(v); v.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object for %s, got %T", path, v.Type(), data)
+		}
+		for name, d := range m {
+			f, ok := fieldByName(v, name)
+			if !ok {
+				return fmt.Errorf("%s: no field %s in %s", path, name, v.Type())
+			}
+			if err := fill(f, d, join(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		a, ok := data.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an array for %s, got %T", path, v.Type(), data)
+		}
+		if v.Kind() == reflect.Slice && v.Len() != len(a) {
+			grow(v, len(a))
+		} else if v.Len() != len(a) {
+			return fmt.Errorf("%s: expected %d elements, got %d", path, v.Len(), len(a))
+		}
+		for i := range a {
+			if err := fill(v.Index(i), a[i], path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// other fields (configuration of the circuit) are decoded as JSON.
+		b, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := json.Unmarshal(b, v.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
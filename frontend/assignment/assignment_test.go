@@ -0,0 +1,95 @@
+package assignment_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/assignment"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type point[T emulated.FieldParams] struct {
+	X, Y emulated.Element[T]
+}
+
+type claim[T emulated.FieldParams] struct {
+	Points []point[T]
+	Scalar frontend.Variable
+}
+
+type outerCircuit[T emulated.FieldParams] struct {
+	Claims []claim[T]
+	Sum    frontend.Variable `gnark:"sum,public"`
+}
+
+func (c *outerCircuit[T]) Define(api frontend.API) error {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	sum := frontend.Variable(0)
+	for _, cl := range c.Claims {
+		sum = api.Add(sum, cl.Scalar)
+		for i := range cl.Points {
+			p := &cl.Points[i]
+			f.AssertIsEqual(&p.Y, f.Mul(&p.X, &p.X))
+		}
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+type fp = emparams.Secp256k1Fp
+
+func placeholder() *outerCircuit[fp] {
+	return &outerCircuit[fp]{Claims: []claim[fp]{{Points: make([]point[fp], 2)}, {Points: make([]point[fp], 1)}}}
+}
+
+func TestSet(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var w outerCircuit[fp]
+	assert.NoError(assignment.Set(&w, "sum", 12))
+	for i, n := range []int{2, 1} {
+		assert.NoError(assignment.Set(&w, fmt.Sprintf("Claims[%d].Scalar", i), 5+2*i))
+		for j := 0; j < n; j++ {
+			assert.NoError(assignment.Set(&w, fmt.Sprintf("Claims[%d].Points[%d]", i, j), point[fp]{
+				X: emulated.ValueOf[fp](j + 2),
+				Y: emulated.ValueOf[fp]((j + 2) * (j + 2)),
+			}))
+		}
+	}
+	assert.NoError(test.IsSolved(placeholder(), &w, ecc.BN254.ScalarField()))
+	_, err := frontend.NewWitness(&w, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	assert.Error(assignment.Set(&w, "Claims[0].Missing", 1))
+	assert.Error(assignment.Set(&w, "Claims[0].Points[0].X", 1))
+	assert.Error(assignment.Set(&w, "Claims[x]", 1))
+	assert.Error(assignment.Set(&w, "Sum.X", 1))
+}
+
+func TestFillFromJSON(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var w outerCircuit[fp]
+	assert.NoError(assignment.FillFromJSON(&w, []byte(`{
+		"Sum": 7,
+		"Claims": [
+			{"Scalar": "0x3", "Points": [{"X": {"Limbs": [3, 0, 0, 0]}, "Y": {"Limbs": [9, 0, 0, 0]}}]},
+			{"Scalar": "4", "Points": []}
+		]
+	}`)))
+	assert.Len(w.Claims, 2)
+	assert.Len(w.Claims[1].Points, 0)
+	c := &outerCircuit[fp]{Claims: []claim[fp]{{Points: make([]point[fp], 1)}, {}}}
+	assert.NoError(test.IsSolved(c, &w, ecc.BN254.ScalarField()))
+
+	assert.Error(assignment.FillFromJSON(&w, []byte(`{"Sum": [1]}`)))
+	assert.Error(assignment.FillFromJSON(&w, []byte(`{"Claims": [{"Scalar": "abc"}]}`)))
+	assert.Error(assignment.FillFromJSON(&w, []byte(`{"Unknown": 1}`)))
+}
@@ -44,6 +44,11 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 			return nil, fmt.Errorf("apply option: %w", err)
 		}
 	}
+	return compile(field, newBuilder, circuit, opt)
+}
+
+func compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opt CompileConfig) (constraint.ConstraintSystem, error) {
+	log := logger.Logger()
 
 	// instantiate new builder
 	builder, err := newBuilder(field, opt)
@@ -154,8 +159,18 @@ type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
 	CompressThreshold         int
+	HashCommitment            HashCommitFunc
+	// SkipSolverData is set by [Estimate], the builders then don't record the
+	// data needed only to solve the constraint system.
+	SkipSolverData bool
 }
 
+// HashCommitFunc computes in the circuit the commitment to the variables
+// toCommit, see [WithHashCommitment]. depth is the number of previous calls to
+// [Committer.Commit] in the circuit, so that committing twice to the same
+// values yields different commitments.
+type HashCommitFunc func(api API, depth int, toCommit ...Variable) (Variable, error)
+
 // WithCapacity is a compile option that specifies the estimated capacity needed
 // for internal variables and constraints. If not set, then the initial capacity
 // is 0 and is dynamically allocated as needed.
@@ -203,6 +218,21 @@ func WithCompressThreshold(threshold int) CompileOption {
 	}
 }
 
+// WithHashCommitment is a compile option which makes the builders implement
+// [Committer] by computing the commitments in the circuit with commit, usually
+// a hash of the committed variables, instead of with the commitment scheme of
+// the backend (Pedersen for Groth16, KZG for PLONK). The challenges derived
+// from the commitments are then sound for any backend, including transparent
+// ones, at the cost of the constraints of the hash, and a circuit may commit
+// any number of times. See [github.com/consensys/gnark/std/commitments/hashcommit]
+// for the commitment functions.
+func WithHashCommitment(commit HashCommitFunc) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.HashCommitment = commit
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
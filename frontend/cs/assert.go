@@ -0,0 +1,30 @@
+package cs
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+)
+
+// CompilerAssert panics with the formatted message if the condition c is not
+// the constant 1, as implemented by the builders for
+// frontend.CompilerAsserter. The panic, turned into an error by
+// frontend.Compile, reports the location of the call to the API.
+func CompilerAssert(c *big.Int, isConstant bool, format string, args []interface{}) {
+	var failure string
+	switch {
+	case !isConstant:
+		failure = "condition is not a constant"
+	case c.IsUint64() && c.Uint64() == 1:
+		return
+	case c.Sign() == 0:
+		failure = "compiler assertion failed"
+	default:
+		failure = fmt.Sprintf("condition %s is not a boolean", c)
+	}
+	// skip this function and the API method
+	if _, file, line, ok := runtime.Caller(2); ok {
+		panic(fmt.Sprintf("%s:%d: %s: %s", file, line, failure, fmt.Sprintf(format, args...)))
+	}
+	panic(fmt.Sprintf("%s: %s", failure, fmt.Sprintf(format, args...)))
+}
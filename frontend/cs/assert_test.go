@@ -0,0 +1,44 @@
+package cs_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type compilerAssertCircuit struct {
+	X       frontend.Variable
+	nbLimbs int
+	witness bool
+}
+
+func (c *compilerAssertCircuit) Define(api frontend.API) error {
+	a := api.(frontend.CompilerAsserter)
+	a.CompilerAssert(api.IsZero(api.Sub(c.nbLimbs, 4)), "expected 4 limbs, got %d", c.nbLimbs)
+	if c.witness {
+		a.CompilerAssert(api.IsZero(c.X), "condition on the witness")
+	}
+	api.AssertIsEqual(c.X, c.nbLimbs)
+	return nil
+}
+
+func TestCompilerAssert(t *testing.T) {
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &compilerAssertCircuit{nbLimbs: 4})
+			assert.NoError(err)
+			assert.Equal(1, ccs.GetNbConstraints())
+
+			_, err = frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &compilerAssertCircuit{nbLimbs: 3})
+			assert.ErrorContains(err, "assert_test.go:21: compiler assertion failed: expected 4 limbs, got 3")
+
+			_, err = frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &compilerAssertCircuit{nbLimbs: 4, witness: true})
+			assert.ErrorContains(err, "assert_test.go:23: condition is not a constant: condition on the witness")
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/rs/zerolog"
 )
 
 // ---------------------------------------------------------------------------------------------
@@ -636,6 +637,51 @@ func (builder *builder) Println(a ...frontend.Variable) {
 	builder.cs.AddLog(log)
 }
 
+// Log records a structured log entry from key-value pairs, see
+// frontend.StructuredLogger.
+//
+// the entry will be logged once the Solve() method is executed
+func (builder *builder) Log(keyvals ...frontend.Variable) {
+	builder.addLog(zerolog.DebugLevel, keyvals)
+}
+
+// LogWithLevel records a structured log entry at the given level, see
+// frontend.LevelLogger.
+func (builder *builder) LogWithLevel(level zerolog.Level, keyvals ...frontend.Variable) {
+	builder.addLog(level, keyvals)
+}
+
+func (builder *builder) addLog(level zerolog.Level, keyvals []frontend.Variable) {
+	if len(keyvals)%2 != 0 {
+		panic("Log expects key-value pairs")
+	}
+	log := constraint.LogEntry{Level: level}
+
+	// prefix log line with file.go:line of the caller of Log
+	if _, file, line, ok := runtime.Caller(2); ok {
+		log.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("Log key %v is not a string", keyvals[i]))
+		}
+		var sbb strings.Builder
+		if v, ok := keyvals[i+1].(expr.LinearExpression); ok {
+			assertIsSet(v)
+			sbb.WriteString("%s")
+			log.ToResolve = append(log.ToResolve, builder.getLinearExpression(v))
+		} else {
+			builder.printArg(&log, &sbb, keyvals[i+1])
+		}
+		log.Keys = append(log.Keys, key)
+		log.Values = append(log.Values, sbb.String())
+	}
+
+	builder.cs.AddLog(log)
+}
+
 func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable) {
 
 	leafCount, err := schema.Walk(a, tVariable, nil)
@@ -643,7 +689,7 @@ func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder,
 
 	// no variables in nested struct, we use fmt std print function
 	if count == 0 || err != nil {
-		sbb.WriteString(fmt.Sprint(a))
+		sbb.WriteString(strings.ReplaceAll(fmt.Sprint(a), "%", "%%"))
 		return
 	}
 
@@ -683,6 +729,10 @@ func (builder *builder) Compiler() frontend.Compiler {
 }
 
 func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error) {
+	if builder.config.HashCommitment != nil {
+		builder.nbHashCommitments++
+		return builder.config.HashCommitment(builder, builder.nbHashCommitments-1, v...)
+	}
 
 	commitments := builder.cs.GetCommitments().(constraint.Groth16Commitments)
 	existingCommitmentIndexes := commitments.CommitmentIndexes()
@@ -22,6 +22,7 @@ import (
 
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/std/math/bits"
 )
@@ -247,3 +248,60 @@ func (builder *builder) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.
 		builder.cs.AttachDebugInfo(debug, added)
 	}
 }
+
+// AssertIsEqualWithMessage adds an assertion in the constraint builder (i1 ==
+// i2), the solver reporting the formatted message if it fails.
+func (builder *builder) AssertIsEqualWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsEqual(i1, i2) }, format, args)
+}
+
+// AssertIsDifferentWithMessage constrain i1 and i2 to be different, the solver
+// reporting the formatted message if they are not.
+func (builder *builder) AssertIsDifferentWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsDifferent(i1, i2) }, format, args)
+}
+
+// AssertIsBooleanWithMessage adds an assertion in the constraint builder (v ==
+// 0 ∥ v == 1), the solver reporting the formatted message if it fails.
+func (builder *builder) AssertIsBooleanWithMessage(i1 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsBoolean(i1) }, format, args)
+}
+
+// AssertIsLessOrEqualWithMessage adds assertion in constraint builder (v ⩽
+// bound), the solver reporting the formatted message if it fails.
+func (builder *builder) AssertIsLessOrEqualWithMessage(v frontend.Variable, bound frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsLessOrEqual(v, bound) }, format, args)
+}
+
+// CompilerAssert adds no constraint to the system, it fails the compilation
+// if condition is not the constant 1.
+func (builder *builder) CompilerAssert(condition frontend.Variable, format string, args ...interface{}) {
+	c, isConstant := builder.ConstantValue(condition)
+	cs.CompilerAssert(c, isConstant, format, args)
+}
+
+// withMessage attaches the message to the constraints added by assert, so that
+// the solver reports it in place of the unsatisfied constraint.
+func (builder *builder) withMessage(assert func(), format string, args []interface{}) {
+	from := builder.cs.GetNbConstraints()
+	assert()
+	to := builder.cs.GetNbConstraints()
+	if from == to {
+		return
+	}
+
+	resolved := make([]interface{}, len(args))
+	for i := range args {
+		if v, ok := args[i].(expr.LinearExpression); ok {
+			assertIsSet(v)
+			resolved[i] = builder.getLinearExpression(v)
+		} else {
+			resolved[i] = args[i]
+		}
+	}
+	cIDs := make([]int, 0, to-from)
+	for cID := from; cID < to; cID++ {
+		cIDs = append(cIDs, cID)
+	}
+	builder.cs.AttachDebugInfo(builder.cs.NewMessageDebugInfo(format, resolved...), cIDs)
+}
@@ -72,6 +72,9 @@ type builder struct {
 	mbuf2 expr.LinearExpression
 
 	genericGate constraint.BlueprintID
+
+	// number of commitments computed with config.HashCommitment
+	nbHashCommitments int
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -116,6 +119,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		}
 		panic("not implemented")
 	}
+	if config.SkipSolverData {
+		builder.cs.SkipSolverData()
+	}
 
 	builder.tOne = builder.cs.One()
 	builder.cs.AddPublicVariable("1")
@@ -168,6 +174,11 @@ func (builder *builder) Field() *big.Int {
 	return builder.cs.Field()
 }
 
+// GetNbConstraints returns the number of constraints added so far.
+func (builder *builder) GetNbConstraints() int {
+	return builder.cs.GetNbConstraints()
+}
+
 func (builder *builder) FieldBitLen() int {
 	return builder.cs.FieldBitLen()
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/frontendtype"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/rs/zerolog"
 )
 
 // Add returns res = i1+i2+...in
@@ -591,6 +592,50 @@ func (builder *builder) Println(a ...frontend.Variable) {
 	builder.cs.AddLog(log)
 }
 
+// Log records a structured log entry from key-value pairs, see
+// frontend.StructuredLogger.
+//
+// the entry will be logged once the Solve() method is executed
+func (builder *builder) Log(keyvals ...frontend.Variable) {
+	builder.addLog(zerolog.DebugLevel, keyvals)
+}
+
+// LogWithLevel records a structured log entry at the given level, see
+// frontend.LevelLogger.
+func (builder *builder) LogWithLevel(level zerolog.Level, keyvals ...frontend.Variable) {
+	builder.addLog(level, keyvals)
+}
+
+func (builder *builder) addLog(level zerolog.Level, keyvals []frontend.Variable) {
+	if len(keyvals)%2 != 0 {
+		panic("Log expects key-value pairs")
+	}
+	log := constraint.LogEntry{Level: level}
+
+	// prefix log line with file.go:line of the caller of Log
+	if _, file, line, ok := runtime.Caller(2); ok {
+		log.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("Log key %v is not a string", keyvals[i]))
+		}
+		var sbb strings.Builder
+		if v, ok := keyvals[i+1].(expr.Term); ok {
+			sbb.WriteString("%s")
+			log.ToResolve = append(log.ToResolve, constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)})
+		} else {
+			builder.printArg(&log, &sbb, keyvals[i+1])
+		}
+		log.Keys = append(log.Keys, key)
+		log.Values = append(log.Values, sbb.String())
+	}
+
+	builder.cs.AddLog(log)
+}
+
 func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable) {
 
 	leafCount, err := schema.Walk(a, tVariable, nil)
@@ -598,7 +643,7 @@ func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder,
 
 	// no variables in nested struct, we use fmt std print function
 	if count == 0 || err != nil {
-		sbb.WriteString(fmt.Sprint(a))
+		sbb.WriteString(strings.ReplaceAll(fmt.Sprint(a), "%", "%%"))
 		return
 	}
 
@@ -628,6 +673,10 @@ func (builder *builder) Compiler() frontend.Compiler {
 }
 
 func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error) {
+	if builder.config.HashCommitment != nil {
+		builder.nbHashCommitments++
+		return builder.config.HashCommitment(builder, builder.nbHashCommitments-1, v...)
+	}
 
 	commitments := builder.cs.GetCommitments().(constraint.PlonkCommitments)
 	v = filterConstants(v) // TODO: @Tabaie Settle on a way to represent even constants; conventional hash?
@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/std/math/bits"
 )
@@ -296,3 +298,61 @@ func (builder *builder) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.
 	}
 
 }
+
+// AssertIsEqualWithMessage fails if i1 != i2, the solver reporting the
+// formatted message.
+func (builder *builder) AssertIsEqualWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsEqual(i1, i2) }, format, args)
+}
+
+// AssertIsDifferentWithMessage fails if i1 == i2, the solver reporting the
+// formatted message.
+func (builder *builder) AssertIsDifferentWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsDifferent(i1, i2) }, format, args)
+}
+
+// AssertIsBooleanWithMessage fails if v != 0 ∥ v != 1, the solver reporting
+// the formatted message.
+func (builder *builder) AssertIsBooleanWithMessage(i1 frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsBoolean(i1) }, format, args)
+}
+
+// AssertIsLessOrEqualWithMessage fails if v > bound, the solver reporting the
+// formatted message.
+func (builder *builder) AssertIsLessOrEqualWithMessage(v frontend.Variable, bound frontend.Variable, format string, args ...interface{}) {
+	builder.withMessage(func() { builder.AssertIsLessOrEqual(v, bound) }, format, args)
+}
+
+// CompilerAssert fails the compilation if condition is not the constant 1.
+func (builder *builder) CompilerAssert(condition frontend.Variable, format string, args ...interface{}) {
+	c, isConstant := builder.ConstantValue(condition)
+	cs.CompilerAssert(c, isConstant, format, args)
+}
+
+// withMessage attaches the message to the constraints added by assert, so that
+// the solver reports it in place of the unsatisfied constraint.
+func (builder *builder) withMessage(assert func(), format string, args []interface{}) {
+	from := builder.cs.GetNbConstraints()
+	assert()
+	to := builder.cs.GetNbConstraints()
+	if from == to {
+		return
+	}
+
+	resolved := make([]interface{}, len(args))
+	for i := range args {
+		switch v := args[i].(type) {
+		case expr.Term:
+			resolved[i] = constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)}
+		case constraint.Element:
+			resolved[i] = builder.cs.ToBigInt(v)
+		default:
+			resolved[i] = args[i]
+		}
+	}
+	cIDs := make([]int, 0, to-from)
+	for cID := from; cID < to; cID++ {
+		cIDs = append(cIDs, cID)
+	}
+	builder.cs.AttachDebugInfo(builder.cs.NewMessageDebugInfo(format, resolved...), cIDs)
+}
@@ -74,6 +74,9 @@ type builder struct {
 	// used to avoid repeated allocations
 	bufL expr.LinearExpression
 	bufH []constraint.LinearExpression
+
+	// number of commitments computed with config.HashCommitment
+	nbHashCommitments int
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -114,6 +117,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		}
 		panic("not implemented")
 	}
+	if config.SkipSolverData {
+		b.cs.SkipSolverData()
+	}
 
 	b.tOne = b.cs.One()
 	b.tMinusOne = b.cs.FromInterface(-1)
@@ -130,6 +136,11 @@ func (builder *builder) Field() *big.Int {
 	return builder.cs.Field()
 }
 
+// GetNbConstraints returns the number of constraints added so far.
+func (builder *builder) GetNbConstraints() int {
+	return builder.cs.GetNbConstraints()
+}
+
 func (builder *builder) FieldBitLen() int {
 	return builder.cs.FieldBitLen()
 }
@@ -0,0 +1,53 @@
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/logger"
+)
+
+// Estimation is the size of a circuit, see [Estimate]. The counts are those
+// returned by the corresponding methods of [constraint.ConstraintSystem].
+type Estimation struct {
+	NbConstraints       int
+	NbInternalVariables int
+	NbPublicVariables   int
+	NbSecretVariables   int
+	NbHints             int
+}
+
+// Estimate returns the size of the constraint system which Compile would
+// return for the same arguments.
+//
+// The circuit is compiled with the builder returned by newBuilder, so that the
+// counts are exact, but the builder doesn't record the data which is needed
+// only to solve the constraint system: the levels of the solver, the logs and
+// the debug information with the call stacks of the constraints. The resulting
+// system can't be solved and is dropped. The estimation saves the memory of
+// that data, but the circuit is defined as with Compile, so that it is only
+// marginally faster.
+func Estimate(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (Estimation, error) {
+	log := logger.Logger()
+	opt := defaultCompileConfig()
+	for _, o := range opts {
+		if err := o(&opt); err != nil {
+			return Estimation{}, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	opt.SkipSolverData = true
+
+	ccs, err := compile(field, newBuilder, circuit, opt)
+	if err != nil {
+		return Estimation{}, err
+	}
+	e := Estimation{
+		NbConstraints:       ccs.GetNbConstraints(),
+		NbInternalVariables: ccs.GetNbInternalVariables(),
+		NbPublicVariables:   ccs.GetNbPublicVariables(),
+		NbSecretVariables:   ccs.GetNbSecretVariables(),
+		NbHints:             ccs.GetNbHints(),
+	}
+	log.Info().Int("nbConstraints", e.NbConstraints).Msg("estimated circuit size")
+	return e, nil
+}
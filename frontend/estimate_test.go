@@ -0,0 +1,86 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/stretchr/testify/require"
+)
+
+type estimateCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *estimateCircuit) Define(api frontend.API) error {
+	p := api.Mul(c.X, c.Y, c.Z)
+	bits := api.ToBinary(p, 32)
+	api.AssertIsEqual(api.FromBinary(bits[16:]...), api.Add(c.X, 3))
+	api.AssertIsEqual(api.Cmp(c.X, c.Y), 1)
+	api.AssertIsLessOrEqual(c.Y, 1000)
+	api.AssertIsLessOrEqual(c.Y, c.X)
+	b := api.IsZero(api.Sub(c.X, c.Z))
+	api.AssertIsDifferent(api.Select(b, c.X, c.Y), api.Xor(b, bits[0]))
+	api.AssertIsEqual(api.Div(c.X, c.Y), api.Lookup2(bits[1], bits[2], c.X, c.Y, c.Z, 4))
+	return nil
+}
+
+type estimateGadgetCircuit struct {
+	X [8]frontend.Variable
+}
+
+func (c *estimateGadgetCircuit) Define(api frontend.API) error {
+	t := logderivlookup.New(api)
+	for i := 0; i < 128; i++ {
+		t.Insert(i * i)
+	}
+	rc := rangecheck.New(api)
+	for i := range c.X {
+		rc.Check(c.X[i], 16+i)
+		api.AssertIsEqual(t.Lookup(c.X[i])[0], i)
+	}
+	return nil
+}
+
+// estimateSumCircuit has a linear expression longer than the compression
+// threshold of R1CS.
+type estimateSumCircuit struct {
+	X [400]frontend.Variable
+}
+
+func (c *estimateSumCircuit) Define(api frontend.API) error {
+	var sum frontend.Variable = 0
+	for i := range c.X {
+		sum = api.Add(sum, c.X[i])
+	}
+	api.AssertIsEqual(api.Mul(sum, sum), c.X[0])
+	return nil
+}
+
+func TestEstimate(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		for _, circuit := range []frontend.Circuit{
+			&estimateCircuit{},
+			&estimateGadgetCircuit{},
+			&estimateSumCircuit{},
+		} {
+			ccs, err := frontend.Compile(field, newBuilder, circuit)
+			assert.NoError(err)
+			e, err := frontend.Estimate(field, newBuilder, circuit)
+			assert.NoError(err)
+
+			assert.Equal(ccs.GetNbConstraints(), e.NbConstraints)
+			assert.Equal(ccs.GetNbInternalVariables(), e.NbInternalVariables)
+			assert.Equal(ccs.GetNbPublicVariables(), e.NbPublicVariables)
+			assert.Equal(ccs.GetNbSecretVariables(), e.NbSecretVariables)
+			assert.Equal(ccs.GetNbHints(), e.NbHints)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package frontend
+
+import "github.com/consensys/gnark/constraint/solver"
+
+// NewResolverHint initializes internal variables whose values are computed by
+// the resolver hint f, with access to the context and the resolver given to
+// the solver with [solver.WithResolver]. The hint must be registered with
+// [solver.RegisterResolverHint]. As for [Compiler.NewHint], the returned
+// variables are not constrained.
+func NewResolverHint(c Compiler, f solver.ResolverHint, nbOutputs int, inputs ...Variable) ([]Variable, error) {
+	hintInputs := make([]Variable, 0, len(inputs)+1)
+	hintInputs = append(hintInputs, uint64(solver.GetResolverHintID(f)))
+	hintInputs = append(hintInputs, inputs...)
+	return c.NewHint(solver.ResolveHint, nbOutputs, hintInputs...)
+}
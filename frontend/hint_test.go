@@ -0,0 +1,57 @@
+package frontend_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// database is the resolver of the test, storing the squares of the keys.
+type database map[uint64]uint64
+
+func fetchHint(ctx context.Context, resolver solver.Resolver, _ *big.Int, inputs, outputs []*big.Int) error {
+	db, ok := resolver.(database)
+	if !ok {
+		return errors.New("expected a database resolver")
+	}
+	v, ok := db[inputs[0].Uint64()]
+	if !ok {
+		return errors.New("key not found")
+	}
+	outputs[0].SetUint64(v)
+	return nil
+}
+
+func init() {
+	solver.RegisterResolverHint(fetchHint)
+}
+
+type resolverCircuit struct {
+	Key frontend.Variable `gnark:",public"`
+}
+
+func (c *resolverCircuit) Define(api frontend.API) error {
+	res, err := frontend.NewResolverHint(api.Compiler(), fetchHint, 1, c.Key)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], api.Mul(c.Key, c.Key))
+	return nil
+}
+
+func TestResolverHint(t *testing.T) {
+	assert := test.NewAssert(t)
+	ctx := context.Background()
+	db := database{3: 9, 4: 15}
+	assert.CheckCircuit(&resolverCircuit{},
+		test.WithValidAssignment(&resolverCircuit{Key: 3}),
+		test.WithInvalidAssignment(&resolverCircuit{Key: 4}),
+		test.WithInvalidAssignment(&resolverCircuit{Key: 5}),
+		test.WithSolverOpts(solver.WithResolver(ctx, db)),
+		test.WithTestEngineOpts(test.WithResolver(ctx, db)))
+}
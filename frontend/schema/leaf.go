@@ -7,7 +7,10 @@ import "reflect"
 type LeafInfo struct {
 	Visibility Visibility
 	FullName   func() string // in most instances, we don't need to actually evaluate the name.
-	name       string
+	// NbBits is the expected bit-length of the leaf value as given by the
+	// "boolean" (1) or "bits=n" tag options, and 0 if not set.
+	NbBits int
+	name   string
 }
 
 // LeafCount stores the number of secret and public interface of type target(reflect.Type)
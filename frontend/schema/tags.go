@@ -15,7 +15,14 @@ import (
 //   - [TagOptInherit] ("inherit"): element's visibility is inherited from its
 //     parent visibility. Is useful for defining custom types to allow consistent
 //     visibility;
-//   - [TagOptOmit] ("-"): do not insert the element into a witness.
+//   - [TagOptOmit] ("-"): do not insert the element into a witness;
+//   - [TagOptBoolean] ("boolean"): the element is expected to be 0 or 1;
+//   - [TagOptBits] ("bits=n"): the element is expected to fit in n bits.
+//
+// The options "boolean" and "bits=n" are not enforced by the compiler, the
+// circuit must still constrain the values. They describe the expected domain
+// of the witness element for tooling, for example when generating random
+// assignments in tests. They are inherited by the children of the element.
 //
 // # Examples
 //
@@ -77,6 +84,8 @@ const (
 	TagOptSecret  TagOpt = "secret"  // secret witness element
 	TagOptInherit TagOpt = "inherit" // inherit the visibility of the witness element from its parent.
 	TagOptOmit    TagOpt = "-"       // do not parse the field as witness element
+	TagOptBoolean TagOpt = "boolean" // witness element is expected to be boolean
+	TagOptBits    TagOpt = "bits"    // witness element is expected to fit in given number of bits (bits=n)
 )
 
 const (
@@ -119,6 +128,22 @@ func (o tagOptions) contains(optionName TagOpt) bool {
 	return false
 }
 
+// value returns the value of a "name=value" option in the comma-separated
+// list of options.
+func (o tagOptions) value(optionName TagOpt) (string, bool) {
+	if len(o) == 0 {
+		return "", false
+	}
+	optList := strings.Split(string(o), ",")
+	for i := 0; i < len(optList); i++ {
+		name, value, ok := strings.Cut(strings.TrimSpace(optList[i]), "=")
+		if ok && strings.TrimSpace(name) == string(optionName) {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
 func isValidTag(s string) bool {
 	if s == "" {
 		return false
@@ -162,3 +162,33 @@ func TestStructTags(t *testing.T) {
 	}
 
 }
+
+func TestStructTagsNbBits(t *testing.T) {
+	assert := require.New(t)
+
+	type inner struct {
+		A variable
+		B variable `gnark:",boolean"`
+	}
+	s := struct {
+		A variable    `gnark:",secret,boolean"`
+		B [2]variable `gnark:",bits=8"`
+		C inner       `gnark:",bits=16"`
+		D variable    `gnark:",public"`
+	}{}
+
+	collected := make(map[string]int)
+	collectHandler := func(f LeafInfo, _ reflect.Value) error {
+		collected[f.FullName()] = f.NbBits
+		return nil
+	}
+	_, err := Walk(&s, tVariable, collectHandler)
+	assert.NoError(err)
+	assert.Equal(map[string]int{"A": 1, "B_0": 8, "B_1": 8, "C_A": 16, "C_B": 1, "D": 0}, collected)
+
+	invalid := struct {
+		A variable `gnark:",bits=foo"`
+	}{}
+	_, err = Walk(&invalid, tVariable, collectHandler)
+	assert.Error(err)
+}
@@ -59,7 +59,7 @@ func (w *walker) Interface(value reflect.Value) error {
 
 	// call the handler.
 	if w.handler != nil {
-		if err := w.handler(LeafInfo{Visibility: v, FullName: w.name, name: ""}, value); err != nil {
+		if err := w.handler(LeafInfo{Visibility: v, FullName: w.name, NbBits: w.nbBits(), name: ""}, value); err != nil {
 			return err
 		}
 	}
@@ -92,7 +92,7 @@ func (w *walker) Slice(value reflect.Value) error {
 }
 
 func (w *walker) arraySliceElem(index int, v reflect.Value) error {
-	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index)})
+	w.path.push(LeafInfo{Visibility: w.visibility(), NbBits: w.nbBits(), name: strconv.Itoa(index)})
 	if v.CanAddr() && v.Addr().CanInterface() {
 		// TODO @gbotrel don't like that hook, undesirable side effects
 		// will be hard to detect; (for example calling Parse multiple times will init multiple times!)
@@ -134,12 +134,13 @@ func (w *walker) handleLeaves(value reflect.Value) error {
 	// call the handler.
 	if w.handler != nil {
 		n := w.name()
+		nbBits := w.nbBits()
 		for i := 0; i < value.Len(); i++ {
 			fName := func() string {
 				return n + "_" + strconv.Itoa(i)
 			}
 			vv := value.Index(i)
-			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, name: ""}, vv); err != nil {
+			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, NbBits: nbBits, name: ""}, vv); err != nil {
 				return err
 			}
 		}
@@ -183,6 +184,7 @@ func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
 	info := LeafInfo{
 		name:       sf.Name,
 		Visibility: parentVisibility,
+		NbBits:     w.nbBits(),
 	}
 
 	var nameInTag string
@@ -204,6 +206,15 @@ func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
 		case opts.contains(TagOptPublic):
 			info.Visibility = Public
 		}
+		if opts.contains(TagOptBoolean) {
+			info.NbBits = 1
+		} else if bits, ok := opts.value(TagOptBits); ok {
+			nbBits, err := strconv.Atoi(bits)
+			if err != nil || nbBits <= 0 {
+				return fmt.Errorf("invalid tag option %s=%s for %s", TagOptBits, bits, info.name)
+			}
+			info.NbBits = nbBits
+		}
 	}
 
 	if parentVisibility != Unset && parentVisibility != info.Visibility {
@@ -240,6 +251,14 @@ func (w *walker) visibility() Visibility {
 	return Unset
 }
 
+// defaults to 0 (no bound)
+func (w *walker) nbBits() int {
+	if !w.path.isEmpty() {
+		return w.path.top().NbBits
+	}
+	return 0
+}
+
 func (w *walker) name() string {
 	if w.path.isEmpty() {
 		return ""
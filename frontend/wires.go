@@ -0,0 +1,24 @@
+package frontend
+
+import (
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// WireIDs returns the IDs of the wires of the inputs of the compiled circuit
+// declared by the field with the given name, in wire order, for use with
+// [constraint.ConstraintSystem.ConstraintsInvolving]. The name is the full name
+// of the field in the schema of the circuit: the names (or gnark tags) of the
+// nested fields and the indexes of the array elements joined by "_", for
+// example "Points_0_X". For a struct or array field, the wires of all its
+// leaves are returned.
+func WireIDs(ccs constraint.ConstraintSystem, name string) []int {
+	var res []int
+	for i := 0; i < ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables(); i++ {
+		if n := ccs.VariableToString(i); n == name || strings.HasPrefix(n, name+"_") {
+			res = append(res, i)
+		}
+	}
+	return res
+}
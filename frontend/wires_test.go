@@ -0,0 +1,58 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type wiresCircuit struct {
+	Points [2]struct {
+		X, Y frontend.Variable
+	}
+	Unused frontend.Variable
+	Sum    frontend.Variable `gnark:",public"`
+}
+
+func (c *wiresCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.Points[0].X, c.Points[1].X), c.Sum)
+	api.AssertIsEqual(api.Mul(c.Points[0].Y, c.Points[1].Y), c.Sum)
+	api.AssertIsEqual(api.Div(c.Points[0].X, c.Points[0].Y), c.Points[0].Y)
+	return nil
+}
+
+func TestWireIDs(t *testing.T) {
+	assert := require.New(t)
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &wiresCircuit{})
+		assert.NoError(err)
+
+		assert.Len(frontend.WireIDs(ccs, "Points"), 4)
+		assert.Len(frontend.WireIDs(ccs, "Points_0"), 2)
+		assert.Empty(frontend.WireIDs(ccs, "Missing"))
+
+		unused := frontend.WireIDs(ccs, "Unused")
+		assert.Len(unused, 1)
+		usage := ccs.ConstraintsInvolving(unused[0])
+		assert.Empty(usage.Constraints)
+		assert.Empty(usage.Hints)
+
+		y := frontend.WireIDs(ccs, "Points_0_Y")
+		assert.Len(y, 1)
+		usage = ccs.ConstraintsInvolving(y[0])
+		assert.GreaterOrEqual(len(usage.Constraints), 2)
+		for _, cID := range usage.Constraints {
+			for _, f := range ccs.GetCallStack(cID) {
+				assert.Contains(usage.Gadgets, f)
+			}
+		}
+
+		sum := frontend.WireIDs(ccs, "Sum")
+		assert.Len(sum, 1)
+		assert.NotEmpty(ccs.ConstraintsInvolving(sum[0]).Constraints)
+	}
+}
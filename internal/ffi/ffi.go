@@ -0,0 +1,263 @@
+// Package ffi implements the language agnostic layer of the foreign function
+// interfaces of gnark (see cmd/libgnark and mobile): reading serialized
+// objects, building witnesses from JSON, proving and verifying, for any
+// backend and curve.
+package ffi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Object is a gnark object (constraint system, key or witness) with the
+// backend and curve it was read for.
+type Object struct {
+	Backend backend.ID
+	Curve   ecc.ID
+	Value   any
+}
+
+func checkIDs(b backend.ID, curve ecc.ID) error {
+	if b != backend.GROTH16 && b != backend.PLONK {
+		return fmt.Errorf("unknown backend %d", b)
+	}
+	for _, c := range gnark.Curves() {
+		if c == curve {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported curve %d", curve)
+}
+
+// ReadConstraintSystem reads a constraint system serialized with WriteTo.
+func ReadConstraintSystem(b backend.ID, curve ecc.ID, data []byte) (*Object, error) {
+	if err := checkIDs(b, curve); err != nil {
+		return nil, err
+	}
+	var ccs constraint.ConstraintSystem
+	if b == backend.GROTH16 {
+		ccs = groth16.NewCS(curve)
+	} else {
+		ccs = plonk.NewCS(curve)
+	}
+	if _, err := ccs.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("read constraint system: %w", err)
+	}
+	return &Object{Backend: b, Curve: curve, Value: ccs}, nil
+}
+
+// ReadProvingKey reads a proving key serialized with WriteTo.
+func ReadProvingKey(b backend.ID, curve ecc.ID, data []byte) (*Object, error) {
+	if err := checkIDs(b, curve); err != nil {
+		return nil, err
+	}
+	var pk io.ReaderFrom
+	if b == backend.GROTH16 {
+		pk = groth16.NewProvingKey(curve)
+	} else {
+		pk = plonk.NewProvingKey(curve)
+	}
+	if _, err := pk.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("read proving key: %w", err)
+	}
+	return &Object{Backend: b, Curve: curve, Value: pk}, nil
+}
+
+// ReadVerifyingKey reads a verifying key serialized with WriteTo.
+func ReadVerifyingKey(b backend.ID, curve ecc.ID, data []byte) (*Object, error) {
+	if err := checkIDs(b, curve); err != nil {
+		return nil, err
+	}
+	var vk io.ReaderFrom
+	if b == backend.GROTH16 {
+		vk = groth16.NewVerifyingKey(curve)
+	} else {
+		vk = plonk.NewVerifyingKey(curve)
+	}
+	if _, err := vk.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("read verifying key: %w", err)
+	}
+	return &Object{Backend: b, Curve: curve, Value: vk}, nil
+}
+
+// NewWitness builds the full witness of the constraint system from a JSON
+// object mapping the names of the inputs of the circuit, as recorded in the
+// constraint system ("X", "Y_0", "Inner_A"...), to their values. Values are
+// JSON numbers or strings in base 10, or in base 16 with the "0x" prefix.
+func NewWitness(ccs *Object, data []byte) (*Object, error) {
+	cs, ok := ccs.Value.(constraint.ConstraintSystem)
+	if !ok {
+		return nil, errors.New("handle is not a constraint system")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("decode witness: %w", err)
+	}
+
+	nbPublic, nbSecret := cs.GetNbPublicVariables(), cs.GetNbSecretVariables()
+	first := 0
+	if ccs.Backend == backend.GROTH16 {
+		first = 1 // constant wire
+	}
+	inputs := make([]any, 0, nbPublic+nbSecret-first)
+	for i := first; i < nbPublic+nbSecret; i++ {
+		name := cs.VariableToString(i)
+		v, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for input %q", name)
+		}
+		var s string
+		switch v := v.(type) {
+		case json.Number:
+			s = v.String()
+		case string:
+			s = v
+		default:
+			return nil, fmt.Errorf("invalid value for input %q: %v", name, v)
+		}
+		b, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for input %q: %s", name, s)
+		}
+		inputs = append(inputs, b)
+	}
+	if len(values) != len(inputs) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(inputs), len(values))
+	}
+
+	w, err := witness.New(ccs.Curve.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	chValues := make(chan any, len(inputs))
+	for _, v := range inputs {
+		chValues <- v
+	}
+	close(chValues)
+	if err := w.Fill(nbPublic-first, nbSecret, chValues); err != nil {
+		return nil, err
+	}
+	return &Object{Backend: ccs.Backend, Curve: ccs.Curve, Value: w}, nil
+}
+
+// ReadWitness reads a full or public witness serialized with MarshalBinary.
+func ReadWitness(curve ecc.ID, data []byte) (*Object, error) {
+	if err := checkIDs(backend.GROTH16, curve); err != nil {
+		return nil, err
+	}
+	w, err := witness.New(curve.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("read witness: %w", err)
+	}
+	return &Object{Curve: curve, Value: w}, nil
+}
+
+// PublicWitness returns the public part of the witness.
+func PublicWitness(w *Object) (*Object, error) {
+	fw, ok := w.Value.(witness.Witness)
+	if !ok {
+		return nil, errors.New("handle is not a witness")
+	}
+	pw, err := fw.Public()
+	if err != nil {
+		return nil, err
+	}
+	return &Object{Backend: w.Backend, Curve: w.Curve, Value: pw}, nil
+}
+
+// WriteWitness serializes the witness with MarshalBinary.
+func WriteWitness(w *Object) ([]byte, error) {
+	fw, ok := w.Value.(witness.Witness)
+	if !ok {
+		return nil, errors.New("handle is not a witness")
+	}
+	return fw.MarshalBinary()
+}
+
+// Prove computes a proof of the full witness and returns it serialized with
+// WriteTo.
+func Prove(ccs, pk, w *Object) ([]byte, error) {
+	cs, ok := ccs.Value.(constraint.ConstraintSystem)
+	if !ok {
+		return nil, errors.New("handle is not a constraint system")
+	}
+	fw, ok := w.Value.(witness.Witness)
+	if !ok {
+		return nil, errors.New("handle is not a witness")
+	}
+	if pk.Backend != ccs.Backend || pk.Curve != ccs.Curve {
+		return nil, errors.New("proving key and constraint system mismatch")
+	}
+
+	var proof io.WriterTo
+	var err error
+	switch pk.Backend {
+	case backend.GROTH16:
+		groth16PK, ok := pk.Value.(groth16.ProvingKey)
+		if !ok {
+			return nil, errors.New("handle is not a proving key")
+		}
+		proof, err = groth16.Prove(cs, groth16PK, fw)
+	default:
+		plonkPK, ok := pk.Value.(plonk.ProvingKey)
+		if !ok {
+			return nil, errors.New("handle is not a proving key")
+		}
+		proof, err = plonk.Prove(cs, plonkPK, fw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify verifies a proof serialized with WriteTo against the public witness.
+func Verify(vk *Object, proof []byte, publicWitness *Object) error {
+	pw, ok := publicWitness.Value.(witness.Witness)
+	if !ok {
+		return errors.New("handle is not a witness")
+	}
+	switch vk.Backend {
+	case backend.GROTH16:
+		groth16VK, ok := vk.Value.(groth16.VerifyingKey)
+		if !ok {
+			return errors.New("handle is not a verifying key")
+		}
+		p := groth16.NewProof(vk.Curve)
+		if _, err := p.ReadFrom(bytes.NewReader(proof)); err != nil {
+			return fmt.Errorf("read proof: %w", err)
+		}
+		return groth16.Verify(p, groth16VK, pw)
+	default:
+		plonkVK, ok := vk.Value.(plonk.VerifyingKey)
+		if !ok {
+			return errors.New("handle is not a verifying key")
+		}
+		p := plonk.NewProof(vk.Curve)
+		if _, err := p.ReadFrom(bytes.NewReader(proof)); err != nil {
+			return fmt.Errorf("read proof: %w", err)
+		}
+		return plonk.Verify(p, plonkVK, pw)
+	}
+}
@@ -0,0 +1,97 @@
+package ffi
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+func serialize(t *testing.T, o io.WriterTo) []byte {
+	var buf bytes.Buffer
+	_, err := o.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestProveVerify(t *testing.T) {
+	for _, b := range []backend.ID{backend.GROTH16, backend.PLONK} {
+		t.Run(b.String(), func(t *testing.T) {
+			assert := require.New(t)
+
+			// setup in Go, everything else through the serialized objects.
+			var ccsData, pkData, vkData []byte
+			if b == backend.GROTH16 {
+				ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+				ccsData, pkData, vkData = serialize(t, ccs), serialize(t, pk), serialize(t, vk)
+			} else {
+				ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubic.Circuit{})
+				assert.NoError(err)
+				srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+				assert.NoError(err)
+				pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+				assert.NoError(err)
+				ccsData, pkData, vkData = serialize(t, ccs), serialize(t, pk), serialize(t, vk)
+			}
+
+			ccs, err := ReadConstraintSystem(b, ecc.BN254, ccsData)
+			assert.NoError(err)
+			pk, err := ReadProvingKey(b, ecc.BN254, pkData)
+			assert.NoError(err)
+			vk, err := ReadVerifyingKey(b, ecc.BN254, vkData)
+			assert.NoError(err)
+
+			w, err := NewWitness(ccs, []byte(`{"x": 3, "Y": "0x23"}`))
+			assert.NoError(err)
+			proof, err := Prove(ccs, pk, w)
+			assert.NoError(err)
+
+			pw, err := PublicWitness(w)
+			assert.NoError(err)
+			data, err := WriteWitness(pw)
+			assert.NoError(err)
+			pw, err = ReadWitness(ecc.BN254, data)
+			assert.NoError(err)
+			assert.NoError(Verify(vk, proof, pw))
+
+			// wrong public input
+			pw, err = NewWitness(ccs, []byte(`{"x": 3, "Y": 36}`))
+			assert.NoError(err)
+			pw, err = PublicWitness(pw)
+			assert.NoError(err)
+			assert.Error(Verify(vk, proof, pw))
+
+			// invalid witnesses
+			_, err = NewWitness(ccs, []byte(`{"x": 3}`))
+			assert.ErrorContains(err, `missing value for input "Y"`)
+			_, err = NewWitness(ccs, []byte(`{"x": 3, "Y": 35, "Z": 1}`))
+			assert.Error(err)
+			_, err = NewWitness(ccs, []byte(`{"x": "three", "Y": 35}`))
+			assert.Error(err)
+		})
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := ReadConstraintSystem(backend.UNKNOWN, ecc.BN254, nil)
+	assert.Error(err)
+	_, err = ReadProvingKey(backend.GROTH16, ecc.BLS12_378, nil)
+	assert.Error(err)
+	_, err = ReadVerifyingKey(backend.PLONK, ecc.BN254, []byte{1, 2, 3})
+	assert.Error(err)
+}
@@ -105,6 +105,7 @@ func main() {
 				groth16Dir         = strings.Replace(d.RootPath, "{?}", "groth16", 1)
 				groth16MpcSetupDir = filepath.Join(groth16Dir, "mpcsetup")
 				plonkDir           = strings.Replace(d.RootPath, "{?}", "plonk", 1)
+				commitmentsDir     = strings.Replace(d.RootPath, "{?}", "commitments", 1)
 			)
 
 			if err := os.MkdirAll(groth16Dir, 0700); err != nil {
@@ -196,11 +197,51 @@ func main() {
 				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal.go"), Templates: []string{"plonk/plonk.marshal.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal_test.go"), Templates: []string{"plonk/tests/marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "checkpoint_test.go"), Templates: []string{"plonk/tests/checkpoint.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "plonk", "./template/zkpschemes/", entries...); err != nil {
 				panic(err)
 			}
 
+			// plonk shplonk
+			shplonkDir := filepath.Join(plonkDir, "shplonk")
+			if err := os.MkdirAll(shplonkDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(shplonkDir, "shplonk.go"), Templates: []string{"plonk/shplonk/shplonk.go.tmpl", importCurve}},
+				{File: filepath.Join(shplonkDir, "shplonk_test.go"), Templates: []string{"plonk/shplonk/shplonk_test.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "shplonk", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
+			// zeromorph
+			zeromorphDir := filepath.Join(commitmentsDir, "zeromorph")
+			if err := os.MkdirAll(zeromorphDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(zeromorphDir, "zeromorph.go"), Templates: []string{"zeromorph/zeromorph.go.tmpl", importCurve}},
+				{File: filepath.Join(zeromorphDir, "zeromorph_test.go"), Templates: []string{"zeromorph/zeromorph_test.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "zeromorph", "./template/commitments/", entries...); err != nil {
+				panic(err)
+			}
+
+			// ligero
+			ligeroDir := filepath.Join(commitmentsDir, "ligero")
+			if err := os.MkdirAll(ligeroDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(ligeroDir, "ligero.go"), Templates: []string{"ligero/ligero.go.tmpl", importCurve}},
+				{File: filepath.Join(ligeroDir, "ligero_test.go"), Templates: []string{"ligero/ligero_test.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "ligero", "./template/commitments/", entries...); err != nil {
+				panic(err)
+			}
+
 		}(d)
 
 	}
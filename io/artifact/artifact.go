@@ -0,0 +1,119 @@
+// Package artifact loads serialized gnark objects (proving and verifying keys,
+// SRS, constraint systems) from artifact stores, such as a shared directory or
+// an HTTP server in front of an object storage bucket.
+//
+// Artifacts are addressed by their fingerprint, the hex encoded SHA2-256 of
+// their serialized content (see [Fingerprint]). They are streamed from the
+// store directly to the ReadFrom method of the object, without being copied
+// to the local disk first, and the fingerprint is checked while reading.
+package artifact
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// Store is a store of artifacts.
+type Store interface {
+	// Open returns a reader on the content of the artifact with the given
+	// fingerprint, and its size in bytes.
+	Open(ctx context.Context, fingerprint string) (io.ReaderAt, int64, error)
+}
+
+// ErrNotFound is returned by stores when the artifact doesn't exist.
+var ErrNotFound = errors.New("artifact not found")
+
+// bufferSize is the size of the reads from the stores while streaming.
+const bufferSize = 4 << 20
+
+// Fingerprint returns the fingerprint of the object, serialized with WriteTo.
+func Fingerprint(object io.WriterTo) (string, error) {
+	h := sha256.New()
+	if _, err := object.WriteTo(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Load reads the artifact with the given fingerprint into the object, for
+// example a proving key created with groth16.NewProvingKey. It returns an
+// error if the content of the artifact doesn't match its fingerprint.
+func Load(ctx context.Context, store Store, fingerprint string, object io.ReaderFrom) error {
+	ra, size, err := store.Open(ctx, fingerprint)
+	if err != nil {
+		return fmt.Errorf("open artifact %s: %w", fingerprint, err)
+	}
+	if c, ok := ra.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	h := sha256.New()
+	r := &hashReader{r: bufio.NewReaderSize(io.NewSectionReader(ra, 0, size), bufferSize), h: h}
+	if _, err := object.ReadFrom(r); err != nil {
+		return fmt.Errorf("read artifact %s: %w", fingerprint, err)
+	}
+	// hash the bytes not consumed by ReadFrom, if any.
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return fmt.Errorf("read artifact %s: %w", fingerprint, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != fingerprint {
+		return fmt.Errorf("artifact %s: fingerprint mismatch, got %s", fingerprint, got)
+	}
+	return nil
+}
+
+// hashReader hashes the bytes read, without allocating as io.TeeReader would
+// for each read through the interface.
+type hashReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+func (r *hashReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	return n, err
+}
+
+// Lazy is an object loaded from a store on first use. It is safe for
+// concurrent use.
+type Lazy[T io.ReaderFrom] struct {
+	store       Store
+	fingerprint string
+	newObject   func() T
+
+	once   sync.Once
+	object T
+	err    error
+}
+
+// NewLazy returns an object which is loaded from the store on the first call
+// to [Lazy.Get]. newObject returns the empty object to read into, for example:
+//
+//	pk := artifact.NewLazy(store, fingerprint, func() groth16.ProvingKey {
+//		return groth16.NewProvingKey(ecc.BN254)
+//	})
+func NewLazy[T io.ReaderFrom](store Store, fingerprint string, newObject func() T) *Lazy[T] {
+	return &Lazy[T]{store: store, fingerprint: fingerprint, newObject: newObject}
+}
+
+// Get loads the object if needed and returns it. The result of the first load
+// is returned to all callers: on error, a new Lazy must be created to retry.
+func (l *Lazy[T]) Get(ctx context.Context) (T, error) {
+	l.once.Do(func() {
+		object := l.newObject()
+		if err := Load(ctx, l.store, l.fingerprint, object); err != nil {
+			l.err = err
+			return
+		}
+		l.object = object
+	})
+	return l.object, l.err
+}
@@ -0,0 +1,109 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func serialize(t *testing.T, o io.WriterTo) []byte {
+	var buf bytes.Buffer
+	_, err := o.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// storeKeys writes the keys of the cubic circuit in dir and returns their
+// fingerprints.
+func storeKeys(t *testing.T, dir string) (pk groth16.ProvingKey, pkFingerprint, vkFingerprint string) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	for _, o := range []io.WriterTo{pk, vk} {
+		fingerprint, err := Fingerprint(o)
+		assert.NoError(err)
+		assert.NoError(os.WriteFile(filepath.Join(dir, fingerprint), serialize(t, o), 0o600))
+		if o == pk {
+			pkFingerprint = fingerprint
+		} else {
+			vkFingerprint = fingerprint
+		}
+	}
+	return pk, pkFingerprint, vkFingerprint
+}
+
+func TestDirStore(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	pk, pkFingerprint, vkFingerprint := storeKeys(t, dir)
+	store := DirStore(dir)
+
+	loaded := groth16.NewProvingKey(ecc.BN254)
+	assert.NoError(Load(context.Background(), store, pkFingerprint, loaded))
+	assert.Equal(serialize(t, pk), serialize(t, loaded))
+
+	// not found
+	err := Load(context.Background(), store, "00", groth16.NewVerifyingKey(ecc.BN254))
+	assert.ErrorIs(err, ErrNotFound)
+	err = Load(context.Background(), store, "../"+vkFingerprint, groth16.NewVerifyingKey(ecc.BN254))
+	assert.Error(err)
+
+	// content doesn't match the fingerprint
+	assert.NoError(os.Rename(filepath.Join(dir, vkFingerprint), filepath.Join(dir, "00")))
+	err = Load(context.Background(), store, "00", groth16.NewVerifyingKey(ecc.BN254))
+	assert.ErrorContains(err, "fingerprint mismatch")
+}
+
+func TestHTTPStore(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	pk, pkFingerprint, _ := storeKeys(t, dir)
+
+	nbRanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			nbRanges++
+		}
+		http.FileServer(http.Dir(dir)).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	store := &HTTPStore{BaseURL: server.URL + "/", Header: http.Header{"Authorization": {"secret"}}}
+	lazy := NewLazy(store, pkFingerprint, func() groth16.ProvingKey { return groth16.NewProvingKey(ecc.BN254) })
+	assert.Equal(0, nbRanges)
+	loaded, err := lazy.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal(serialize(t, pk), serialize(t, loaded))
+	assert.NotZero(nbRanges)
+
+	// loaded once
+	n := nbRanges
+	loaded2, err := lazy.Get(context.Background())
+	assert.NoError(err)
+	assert.Equal(loaded, loaded2)
+	assert.Equal(n, nbRanges)
+
+	err = Load(context.Background(), store, "00", groth16.NewVerifyingKey(ecc.BN254))
+	assert.ErrorIs(err, ErrNotFound)
+	err = Load(context.Background(), &HTTPStore{BaseURL: server.URL}, pkFingerprint, groth16.NewProvingKey(ecc.BN254))
+	assert.ErrorContains(err, "403")
+}
@@ -0,0 +1,133 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DirStore is a Store of artifacts in a directory, for example a network file
+// system mounted on the workers. Artifacts are stored in files named after
+// their fingerprint.
+type DirStore string
+
+// Open implements Store.
+func (d DirStore) Open(_ context.Context, fingerprint string) (io.ReaderAt, int64, error) {
+	if err := checkFingerprint(fingerprint); err != nil {
+		return nil, 0, err
+	}
+	f, err := os.Open(filepath.Join(string(d), fingerprint))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// HTTPStore is a Store of artifacts served over HTTP, at BaseURL followed by
+// their fingerprint. The server must support range requests, as do object
+// storage services (S3, GCS) and http.FileServer; each read from the store is
+// a range request.
+type HTTPStore struct {
+	BaseURL string
+	// Client is the client used for the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Header is added to the requests, for example for authorization.
+	Header http.Header
+}
+
+// Open implements Store.
+func (s *HTTPStore) Open(ctx context.Context, fingerprint string) (io.ReaderAt, int64, error) {
+	if err := checkFingerprint(fingerprint); err != nil {
+		return nil, 0, err
+	}
+	u, err := url.JoinPath(s.BaseURL, fingerprint)
+	if err != nil {
+		return nil, 0, err
+	}
+	r := &httpReaderAt{ctx: ctx, store: s, url: u}
+
+	resp, err := r.do(http.MethodHead, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, 0, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, 0, fmt.Errorf("HEAD %s: %s", u, resp.Status)
+	case resp.ContentLength < 0:
+		return nil, 0, fmt.Errorf("HEAD %s: unknown content length", u)
+	}
+	return r, resp.ContentLength, nil
+}
+
+type httpReaderAt struct {
+	ctx   context.Context
+	store *HTTPStore
+	url   string
+}
+
+func (r *httpReaderAt) do(method, byteRange string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, method, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.store.Header {
+		req.Header[k] = v
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	client := r.store.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (r *httpReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	resp, err := r.do(http.MethodGet, "bytes="+strconv.FormatInt(off, 10)+"-"+strconv.FormatInt(off+int64(len(p))-1, 10))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	default:
+		return 0, fmt.Errorf("GET %s: %s", r.url, resp.Status)
+	}
+	n, err := io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF // range truncated at the end of the artifact
+	}
+	return n, err
+}
+
+// checkFingerprint prevents fingerprints from escaping the store.
+func checkFingerprint(fingerprint string) error {
+	if fingerprint == "" || strings.ContainsAny(fingerprint, `/\.`) {
+		return fmt.Errorf("invalid fingerprint %q", fingerprint)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package migrate rewrites the constraint systems, proving keys and verifying
+// keys serialized by previous gnark releases in the current format, so that a
+// deployment can upgrade gnark without re-running the setup ceremony or
+// re-compiling the circuit.
+//
+// The keys don't record the gnark version which serialized them, so it must be
+// given by the caller. The supported migrations are:
+//
+//   - Groth16 keys from v0.9: the format is unchanged, the keys are decoded
+//     and encoded again.
+//   - PLONK verifying keys from v0.9: the precomputed lines of the KZG
+//     verifying key were added in v0.10, they are computed from [τ]G₂.
+//   - PLONK proving keys from v0.9: the FFT domains and the trace of the
+//     circuit, which are recomputed by the prover since v0.10, are dropped.
+//   - constraint systems from v0.10: the version in the header is updated.
+//     The constraint systems serialized before v0.10 were encoded with CBOR
+//     and are not supported.
+package migrate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+)
+
+// ErrUnsupportedVersion is returned when the object was serialized by a gnark
+// version which can't be migrated.
+var ErrUnsupportedVersion = errors.New("unsupported gnark version")
+
+var (
+	v0_9  = semver.MustParse("0.9.0")
+	v0_10 = semver.MustParse("0.10.0")
+)
+
+// v09Reader is implemented by the PLONK keys of all the curves.
+type v09Reader interface {
+	ReadFromV09(r io.Reader) (int64, error)
+}
+
+// VerifyingKey reads a verifying key of the given proof system and curve
+// serialized by gnark version from, and writes it to w in the current format.
+func VerifyingKey(w io.Writer, r io.Reader, scheme backend.ID, curve ecc.ID, from semver.Version) error {
+	if err := checkKeyVersion(from); err != nil {
+		return err
+	}
+	var vk io.WriterTo
+	switch scheme {
+	case backend.GROTH16:
+		gvk := groth16.NewVerifyingKey(curve)
+		if _, err := gvk.ReadFrom(r); err != nil {
+			return fmt.Errorf("read verifying key: %w", err)
+		}
+		vk = gvk
+	case backend.PLONK:
+		pvk := plonk.NewVerifyingKey(curve)
+		if err := readPlonk(pvk, r, from); err != nil {
+			return fmt.Errorf("read verifying key: %w", err)
+		}
+		vk = pvk
+	default:
+		return fmt.Errorf("unsupported proof system %s", scheme)
+	}
+	if _, err := vk.WriteTo(w); err != nil {
+		return fmt.Errorf("write verifying key: %w", err)
+	}
+	return nil
+}
+
+// ProvingKey reads a proving key of the given proof system and curve
+// serialized by gnark version from, and writes it to w in the current format.
+func ProvingKey(w io.Writer, r io.Reader, scheme backend.ID, curve ecc.ID, from semver.Version) error {
+	if err := checkKeyVersion(from); err != nil {
+		return err
+	}
+	var pk io.WriterTo
+	switch scheme {
+	case backend.GROTH16:
+		gpk := groth16.NewProvingKey(curve)
+		if _, err := gpk.ReadFrom(r); err != nil {
+			return fmt.Errorf("read proving key: %w", err)
+		}
+		pk = gpk
+	case backend.PLONK:
+		ppk := plonk.NewProvingKey(curve)
+		if err := readPlonk(ppk, r, from); err != nil {
+			return fmt.Errorf("read proving key: %w", err)
+		}
+		pk = ppk
+	default:
+		return fmt.Errorf("unsupported proof system %s", scheme)
+	}
+	if _, err := pk.WriteTo(w); err != nil {
+		return fmt.Errorf("write proving key: %w", err)
+	}
+	return nil
+}
+
+func readPlonk(key io.ReaderFrom, r io.Reader, from semver.Version) error {
+	if from.GTE(v0_10) {
+		_, err := key.ReadFrom(r)
+		return err
+	}
+	legacy, ok := key.(v09Reader)
+	if !ok {
+		return fmt.Errorf("%T: %w %s", key, ErrUnsupportedVersion, from)
+	}
+	_, err := legacy.ReadFromV09(r)
+	return err
+}
+
+func checkKeyVersion(from semver.Version) error {
+	if from.LT(v0_9) || from.GT(gnark.Version) {
+		return fmt.Errorf("%w %s", ErrUnsupportedVersion, from)
+	}
+	return nil
+}
+
+// ConstraintSystem reads a constraint system for the given proof system and
+// curve, and writes it to w with the current gnark version. The version which
+// serialized the constraint system is read from its header.
+func ConstraintSystem(w io.Writer, r io.Reader, scheme backend.ID, curve ecc.ID) error {
+	// totalLen, major, minor, patch
+	var header [4]uint64
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	from := semver.Version{Major: header[1], Minor: header[2], Patch: header[3]}
+	if from.LT(v0_10) || from.GT(gnark.Version) {
+		return fmt.Errorf("%w %s", ErrUnsupportedVersion, from)
+	}
+
+	var ccs constraint.ConstraintSystem
+	switch scheme {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(curve)
+	case backend.PLONK:
+		ccs = plonk.NewCS(curve)
+	default:
+		return fmt.Errorf("unsupported proof system %s", scheme)
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := ccs.ReadFrom(io.MultiReader(&buf, r)); err != nil {
+		return fmt.Errorf("read constraint system: %w", err)
+	}
+	ccs.SetGnarkVersion(gnark.Version)
+	if _, err := ccs.WriteTo(w); err != nil {
+		return fmt.Errorf("write constraint system: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/stretchr/testify/require"
+)
+
+// The testdata holds the cubic circuit on BN254 with its keys, the keys are in
+// the format of gnark v0.9.1 and the constraint systems in the format of
+// gnark v0.10.0, the first version with the binary format.
+var v0_9_1 = semver.MustParse("0.9.1")
+
+// migrate migrates the testdata file name with fn, and reads the result in o.
+func migrate(t *testing.T, o io.ReaderFrom, name string, fn func(w io.Writer, r io.Reader) error) {
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer f.Close()
+	var migrated bytes.Buffer
+	require.NoError(t, fn(&migrated, f))
+	_, err = o.ReadFrom(&migrated)
+	require.NoError(t, err)
+}
+
+func TestGroth16V09(t *testing.T) {
+	assert := require.New(t)
+	ccs := groth16.NewCS(ecc.BN254)
+	migrate(t, ccs, "cubic.r1cs", func(w io.Writer, r io.Reader) error {
+		return ConstraintSystem(w, r, backend.GROTH16, ecc.BN254)
+	})
+	pk := groth16.NewProvingKey(ecc.BN254)
+	migrate(t, pk, "cubic.groth16.pk", func(w io.Writer, r io.Reader) error {
+		return ProvingKey(w, r, backend.GROTH16, ecc.BN254, v0_9_1)
+	})
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	migrate(t, vk, "cubic.groth16.vk", func(w io.Writer, r io.Reader) error {
+		return VerifyingKey(w, r, backend.GROTH16, ecc.BN254, v0_9_1)
+	})
+
+	w, err := frontend.NewWitness(&cubic.Circuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pw))
+}
+
+func TestPlonkV09(t *testing.T) {
+	assert := require.New(t)
+	ccs := plonk.NewCS(ecc.BN254)
+	migrate(t, ccs, "cubic.scs", func(w io.Writer, r io.Reader) error {
+		return ConstraintSystem(w, r, backend.PLONK, ecc.BN254)
+	})
+	pk := plonk.NewProvingKey(ecc.BN254)
+	migrate(t, pk, "cubic.plonk.pk", func(w io.Writer, r io.Reader) error {
+		return ProvingKey(w, r, backend.PLONK, ecc.BN254, v0_9_1)
+	})
+	vk := plonk.NewVerifyingKey(ecc.BN254)
+	migrate(t, vk, "cubic.plonk.vk", func(w io.Writer, r io.Reader) error {
+		return VerifyingKey(w, r, backend.PLONK, ecc.BN254, v0_9_1)
+	})
+	assert.Equal(pk.VerifyingKey(), vk)
+
+	w, err := frontend.NewWitness(&cubic.Circuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, pw))
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	assert := require.New(t)
+	vk, err := os.ReadFile("testdata/cubic.groth16.vk")
+	assert.NoError(err)
+	err = VerifyingKey(io.Discard, bytes.NewReader(vk), backend.GROTH16, ecc.BN254, semver.MustParse("0.8.1"))
+	assert.True(errors.Is(err, ErrUnsupportedVersion))
+
+	// the version of the header is checked before decoding
+	b, err := os.ReadFile("testdata/cubic.r1cs")
+	assert.NoError(err)
+	binary.LittleEndian.PutUint64(b[16:], 9)
+	err = ConstraintSystem(io.Discard, bytes.NewReader(b), backend.GROTH16, ecc.BN254)
+	assert.True(errors.Is(err, ErrUnsupportedVersion))
+}
+
+// TestGenerateTestdata writes the testdata, the keys are written in the format
+// of gnark v0.9.1.
+func TestGenerateTestdata(t *testing.T) {
+	t.Skip("test used only to generate testdata")
+	assert := require.New(t)
+
+	write := func(name string, fn func(w io.Writer) error) {
+		f, err := os.Create("testdata/" + name)
+		assert.NoError(err)
+		defer f.Close()
+		assert.NoError(fn(f))
+	}
+	writeTo := func(o io.WriterTo) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := o.WriteTo(w)
+			return err
+		}
+	}
+
+	// the constraint systems are written with the header of v0.10.0
+	ccsR1CS, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	ccsR1CS.SetGnarkVersion(v0_10)
+	write("cubic.r1cs", writeTo(ccsR1CS))
+	ccsSCS, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	ccsSCS.SetGnarkVersion(v0_10)
+	write("cubic.scs", writeTo(ccsSCS))
+
+	// the format of the Groth16 keys is unchanged since v0.9
+	gpk, gvk, err := groth16.Setup(ccsR1CS)
+	assert.NoError(err)
+	write("cubic.groth16.pk", writeTo(gpk))
+	write("cubic.groth16.vk", writeTo(gvk))
+
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccsSCS)
+	assert.NoError(err)
+	ppk, pvk, err := plonk.Setup(ccsSCS, srs, srsLagrange)
+	assert.NoError(err)
+	write("cubic.plonk.pk", func(w io.Writer) error {
+		return writeProvingKeyV09(w, ccsSCS, ppk.(*plonk_bn254.ProvingKey))
+	})
+	write("cubic.plonk.vk", func(w io.Writer) error {
+		return writeVerifyingKeyV09(w, pvk.(*plonk_bn254.VerifyingKey))
+	})
+}
+
+// writeVerifyingKeyV09 writes vk in the format of gnark v0.9.
+func writeVerifyingKeyV09(w io.Writer, vk *plonk_bn254.VerifyingKey) error {
+	enc := curve.NewEncoder(w)
+	toEncode := []interface{}{
+		vk.Size,
+		&vk.SizeInv,
+		&vk.Generator,
+		vk.NbPublicVariables,
+		&vk.CosetShift,
+		&vk.S[0],
+		&vk.S[1],
+		&vk.S[2],
+		&vk.Ql,
+		&vk.Qr,
+		&vk.Qm,
+		&vk.Qo,
+		&vk.Qk,
+		vk.Qcp,
+		&vk.Kzg.G1,
+		&vk.Kzg.G2[0],
+		&vk.Kzg.G2[1],
+		vk.CommitmentConstraintIndexes,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeProvingKeyV09 writes pk in the format of gnark v0.9: the verifying key,
+// the FFT domains, the KZG keys and the trace of the circuit, with its
+// polynomials in canonical form, Qk in Lagrange form and the permutation.
+func writeProvingKeyV09(w io.Writer, ccs constraint.ConstraintSystem, pk *plonk_bn254.ProvingKey) error {
+	if err := writeVerifyingKeyV09(w, pk.Vk); err != nil {
+		return err
+	}
+	n := pk.Vk.Size
+	domain := fft.NewDomain(n)
+	for _, d := range []*fft.Domain{domain, fft.NewDomain(4 * n)} {
+		if _, err := d.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	if _, err := pk.Kzg.WriteTo(w); err != nil {
+		return err
+	}
+	if _, err := pk.KzgLagrange.WriteTo(w); err != nil {
+		return err
+	}
+
+	trace := plonk_bn254.NewTrace(ccs.(*cs_bn254.SparseR1CS), domain)
+	lQk := trace.Qk.Clone()
+	polys := []*iop.Polynomial{trace.Ql, trace.Qr, trace.Qm, trace.Qo, trace.Qk, trace.S1, trace.S2, trace.S3}
+	for _, p := range append(polys, trace.Qcp...) {
+		p.ToCanonical(domain).ToRegular()
+	}
+	qcp := make([][]fr.Element, len(trace.Qcp))
+	for i := range trace.Qcp {
+		qcp[i] = trace.Qcp[i].Coefficients()
+	}
+	enc := curve.NewEncoder(w)
+	toEncode := []interface{}{
+		trace.Ql.Coefficients(),
+		trace.Qr.Coefficients(),
+		trace.Qm.Coefficients(),
+		trace.Qo.Coefficients(),
+		trace.Qk.Coefficients(),
+		qcp,
+		lQk.Coefficients(),
+		trace.S1.Coefficients(),
+		trace.S2.Coefficients(),
+		trace.S3.Coefficients(),
+		trace.S,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
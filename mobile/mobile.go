@@ -0,0 +1,105 @@
+// Package mobile is a gomobile compatible API to prove and verify precompiled
+// circuits on iOS and Android:
+//
+//	gomobile bind -target=android github.com/consensys/gnark/mobile
+//	gomobile bind -target=ios github.com/consensys/gnark/mobile
+//
+// The exported API only uses the types supported by gomobile: objects are
+// exchanged as byte slices, in the binary formats of the corresponding Go
+// objects (WriteTo / MarshalBinary), and witnesses are built from JSON objects
+// mapping the names of the circuit inputs to their values:
+//
+//	{"X": "3", "Y": "0x23"}
+//
+// The constraint system and the keys are produced ahead of time, with the
+// regular Go API.
+package mobile
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/ffi"
+)
+
+// Backends.
+const (
+	Groth16 = int(backend.GROTH16)
+	Plonk   = int(backend.PLONK)
+)
+
+// Curves.
+const (
+	BN254     = int(ecc.BN254)
+	BLS12_377 = int(ecc.BLS12_377)
+	BLS12_381 = int(ecc.BLS12_381)
+	BLS24_315 = int(ecc.BLS24_315)
+	BLS24_317 = int(ecc.BLS24_317)
+	BW6_761   = int(ecc.BW6_761)
+	BW6_633   = int(ecc.BW6_633)
+)
+
+// Prover computes proofs for a constraint system.
+type Prover struct {
+	ccs, pk *ffi.Object
+}
+
+// NewProver returns a prover for the serialized constraint system and proving
+// key, for the given backend and curve.
+func NewProver(backendID, curveID int, ccs, pk []byte) (*Prover, error) {
+	ccsObject, err := ffi.ReadConstraintSystem(backend.ID(backendID), ecc.ID(curveID), ccs)
+	if err != nil {
+		return nil, err
+	}
+	pkObject, err := ffi.ReadProvingKey(backend.ID(backendID), ecc.ID(curveID), pk)
+	if err != nil {
+		return nil, err
+	}
+	return &Prover{ccs: ccsObject, pk: pkObject}, nil
+}
+
+// Prove returns the serialized proof of the witness, given as a JSON object.
+func (p *Prover) Prove(witnessJSON []byte) ([]byte, error) {
+	w, err := ffi.NewWitness(p.ccs, witnessJSON)
+	if err != nil {
+		return nil, err
+	}
+	return ffi.Prove(p.ccs, p.pk, w)
+}
+
+// PublicWitness returns the serialized public part of the witness, given as a
+// JSON object, to be sent with the proof to the verifier.
+func (p *Prover) PublicWitness(witnessJSON []byte) ([]byte, error) {
+	w, err := ffi.NewWitness(p.ccs, witnessJSON)
+	if err != nil {
+		return nil, err
+	}
+	pw, err := ffi.PublicWitness(w)
+	if err != nil {
+		return nil, err
+	}
+	return ffi.WriteWitness(pw)
+}
+
+// Verifier verifies proofs.
+type Verifier struct {
+	vk *ffi.Object
+}
+
+// NewVerifier returns a verifier for the serialized verifying key, for the
+// given backend and curve.
+func NewVerifier(backendID, curveID int, vk []byte) (*Verifier, error) {
+	vkObject, err := ffi.ReadVerifyingKey(backend.ID(backendID), ecc.ID(curveID), vk)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{vk: vkObject}, nil
+}
+
+// Verify verifies the serialized proof against the serialized public witness.
+func (v *Verifier) Verify(proof, publicWitness []byte) error {
+	pw, err := ffi.ReadWitness(v.vk.Curve, publicWitness)
+	if err != nil {
+		return err
+	}
+	return ffi.Verify(v.vk, proof, pw)
+}
@@ -0,0 +1,51 @@
+package mobile
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/examples/cubic"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func serialize(t *testing.T, o io.WriterTo) []byte {
+	var buf bytes.Buffer
+	_, err := o.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestProveVerify(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic.Circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	prover, err := NewProver(Groth16, BN254, serialize(t, ccs), serialize(t, pk))
+	assert.NoError(err)
+	verifier, err := NewVerifier(Groth16, BN254, serialize(t, vk))
+	assert.NoError(err)
+
+	witness := []byte(`{"x": "3", "Y": "35"}`)
+	proof, err := prover.Prove(witness)
+	assert.NoError(err)
+	publicWitness, err := prover.PublicWitness(witness)
+	assert.NoError(err)
+	assert.NoError(verifier.Verify(proof, publicWitness))
+
+	publicWitness, err = prover.PublicWitness([]byte(`{"x": "3", "Y": "36"}`))
+	assert.NoError(err)
+	assert.Error(verifier.Verify(proof, publicWitness))
+
+	_, err = prover.Prove([]byte(`{"x": "2", "Y": "35"}`))
+	assert.Error(err)
+	_, err = NewProver(Groth16, BN254, serialize(t, ccs), nil)
+	assert.Error(err)
+}
@@ -0,0 +1,106 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/pprof/profile"
+)
+
+// FunctionDelta is the difference in the number of constraints attributed to
+// a function between two profiles.
+//
+// Constraints are counted cumulatively: a constraint is attributed to every
+// function in its call stack, so that the delta of a gadget includes the
+// deltas of the functions it calls.
+type FunctionDelta struct {
+	Function string // short name of the function, as displayed by pprof
+	Before   int64  // number of constraints in the first profile
+	After    int64  // number of constraints in the second profile
+}
+
+// Delta returns After - Before.
+func (d FunctionDelta) Delta() int64 {
+	return d.After - d.Before
+}
+
+// Deltas is a list of per-function constraint deltas as returned by [Diff].
+type Deltas []FunctionDelta
+
+// Diff compares the constraints attributed to each function in two profiles,
+// for example the profiles of a circuit before and after an optimization or a
+// gnark version upgrade. Functions whose number of constraints did not change
+// are omitted. The result is sorted by decreasing absolute delta, then by
+// function name.
+func Diff(before, after *Profile) Deltas {
+	b, a := before.cumulative(), after.cumulative()
+
+	var res Deltas
+	for f, nb := range b {
+		if na := a[f]; na != nb {
+			res = append(res, FunctionDelta{Function: f, Before: nb, After: na})
+		}
+	}
+	for f, na := range a {
+		if _, ok := b[f]; !ok {
+			res = append(res, FunctionDelta{Function: f, After: na})
+		}
+	}
+
+	abs := func(x int64) int64 {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	sort.Slice(res, func(i, j int) bool {
+		di, dj := abs(res[i].Delta()), abs(res[j].Delta())
+		if di != dj {
+			return di > dj
+		}
+		return res[i].Function < res[j].Function
+	})
+	return res
+}
+
+// String returns a human readable table of the deltas.
+func (d Deltas) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "before\tafter\tdelta\t\t")
+	for _, fd := range d {
+		fmt.Fprintf(w, "%d\t%d\t%+d\t\t%s\n", fd.Before, fd.After, fd.Delta(), fd.Function)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// cumulative returns the number of constraints attributed to each function,
+// counting each function at most once per sample.
+func (p *Profile) cumulative() map[string]int64 {
+	return cumulative(p.pprof.Sample)
+}
+
+// cumulative returns the sum of the values of the samples attributed to each
+// function, counting each function at most once per sample.
+func cumulative(samples []*profile.Sample) map[string]int64 {
+	res := make(map[string]int64)
+	seen := make(map[string]struct{})
+	for _, s := range samples {
+		for k := range seen {
+			delete(seen, k)
+		}
+		for _, l := range s.Location {
+			for _, line := range l.Line {
+				if _, ok := seen[line.Function.Name]; ok {
+					continue
+				}
+				seen[line.Function.Name] = struct{}{}
+				res[line.Function.Name] += s.Value[0]
+			}
+		}
+	}
+	return res
+}
@@ -5,10 +5,14 @@
 package profile
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,6 +25,7 @@ import (
 var (
 	sessions       []*Profile // active sessions
 	activeSessions uint32
+	solveSessions  uint32 // active sessions recording the instructions
 )
 
 // Profile represents an active constraint system profiling session.
@@ -29,6 +34,9 @@ type Profile struct {
 	// if blank, profiile is not written to disk
 	filePath string
 
+	// if set, folded stacks are written to this path on Stop
+	foldedPath string
+
 	// actual pprof profile struct
 	// details on pprof format: https://github.com/google/pprof/blob/main/proto/README.md
 	pprof profile.Profile
@@ -36,6 +44,11 @@ type Profile struct {
 	functions map[string]*profile.Function
 	locations map[uint64]*profile.Location
 
+	// if set, the call stack of each instruction is recorded in instructions,
+	// with the time spent solving it
+	solveTime    bool
+	instructions []*profile.Sample
+
 	onceSetName sync.Once
 
 	chDone chan struct{}
@@ -62,6 +75,26 @@ func WithNoOutput() Option {
 	}
 }
 
+// WithFoldedPath writes, in addition to the pprof file, the profile in the
+// folded stacks format to the given path when the profile is stopped. See
+// [Profile.WriteFolded].
+func WithFoldedPath(path string) Option {
+	return func(p *Profile) {
+		p.foldedPath = path
+	}
+}
+
+// WithSolveTime records, in addition to the constraints, the call stack of
+// each instruction of the constraint system, so that the time spent solving
+// the instructions can be attributed to the gadgets with [Profile.SolverOption].
+// The session must be started before compiling the circuit, and cover a
+// single compilation.
+func WithSolveTime() Option {
+	return func(p *Profile) {
+		p.solveTime = true
+	}
+}
+
 // Start creates a new active profiling session. When Stop() is called, this session is removed from
 // active profiling sessions and may be serialized to disk as a pprof compatible file (see ProfilePath option).
 //
@@ -100,11 +133,15 @@ func Start(options ...Option) *Profile {
 	// add the session to active sessions
 	chCommands <- command{p: &p}
 	atomic.AddUint32(&activeSessions, 1)
+	if p.solveTime {
+		atomic.AddUint32(&solveSessions, 1)
+	}
 
 	return &p
 }
 
 // Stop removes the profile from active session and may write the pprof file to disk. See ProfilePath option.
+// If the WithFoldedPath option is set, the folded stacks are written to disk as well.
 func (p *Profile) Stop() {
 	log := logger.Logger()
 
@@ -134,6 +171,64 @@ func (p *Profile) Stop() {
 		log.Warn().Msg("gnark profiling disabled [not writing to disk]")
 	}
 
+	if p.foldedPath != "" {
+		f, err := os.Create(p.foldedPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create gnark folded profile")
+		}
+		if err := p.WriteFolded(f); err != nil {
+			log.Error().Err(err).Msg("writing folded profile")
+		}
+		f.Close()
+		log.Info().Str("path", p.foldedPath).Msg("gnark folded profile written")
+	}
+}
+
+// WritePprof writes the profile in the gzip-compressed protobuf pprof format,
+// where the samples count the constraints added at each source location. The
+// output can be explored with
+//
+//	go tool pprof -http=:8080 gnark.pprof
+func (p *Profile) WritePprof(w io.Writer) error {
+	return p.pprof.Write(w)
+}
+
+// WriteFolded writes the profile in the folded stacks format used by
+// flamegraph.pl, speedscope or inferno. Each line is a semicolon separated
+// call stack from the circuit Define method to the frontend API followed by
+// the number of constraints added by this stack:
+//
+//	(*Circuit).Define circuit.go:21;r1cs.(*builder).Mul api.go:221 1
+//
+// Lines are sorted, identical stacks are merged.
+func (p *Profile) WriteFolded(w io.Writer) error {
+	counts := make(map[string]int64)
+	var frames []string
+	for _, s := range p.pprof.Sample {
+		frames = frames[:0]
+		// locations are ordered from the leaf to the root
+		for i := len(s.Location) - 1; i >= 0; i-- {
+			for j := len(s.Location[i].Line) - 1; j >= 0; j-- {
+				l := s.Location[i].Line[j]
+				frames = append(frames, fmt.Sprintf("%s %s:%d", l.Function.Name, filepath.Base(l.Function.Filename), l.Line))
+			}
+		}
+		counts[strings.Join(frames, ";")] += s.Value[0]
+	}
+
+	stacks := make([]string, 0, len(counts))
+	for stack := range counts {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	bw := bufio.NewWriter(w)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", stack, counts[stack]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
 }
 
 // NbConstraints return number of collected samples (constraints) by the profile session
@@ -141,6 +236,37 @@ func (p *Profile) NbConstraints() int {
 	return len(p.pprof.Sample)
 }
 
+// Gadgets returns the number of constraints attributed to each function
+// calling the frontend API, that is the innermost gadget of the call stack of
+// each constraint. Unlike the cumulative counts of [Diff], a constraint is
+// attributed to a single function, so that the counts sum to
+// [Profile.NbConstraints].
+func (p *Profile) Gadgets() map[string]int64 {
+	res := make(map[string]int64)
+	for _, s := range p.pprof.Sample {
+		res[gadget(s)] += s.Value[0]
+	}
+	return res
+}
+
+// gadget returns the name of the first function of the sample which is not a
+// method of the builders, or the name of the leaf if there is none.
+func gadget(s *profile.Sample) string {
+	var leaf string
+	for _, l := range s.Location {
+		for _, line := range l.Line {
+			name := line.Function.Name
+			if leaf == "" {
+				leaf = name
+			}
+			if !strings.HasPrefix(name, "r1cs.(*builder).") && !strings.HasPrefix(name, "scs.(*builder).") {
+				return name
+			}
+		}
+	}
+	return leaf
+}
+
 // Top return a similar output than pprof top command
 func (p *Profile) Top() string {
 	r := report.NewDefault(&p.pprof, report.Options{
@@ -172,6 +298,22 @@ func RecordConstraint() {
 	chCommands <- command{pc: pc}
 }
 
+// RecordInstruction records the call stack of a new instruction in the active
+// profiling sessions with the [WithSolveTime] option.
+func RecordInstruction() {
+	if n := atomic.LoadUint32(&solveSessions); n == 0 {
+		return
+	}
+
+	pc := make([]uintptr, 20)
+	n := runtime.Callers(3, pc)
+	if n == 0 {
+		return
+	}
+	pc = pc[:n]
+	chCommands <- command{pc: pc, instruction: true}
+}
+
 func (p *Profile) getLocation(frame *runtime.Frame) *profile.Location {
 	l, ok := p.locations[uint64(frame.PC)]
 	if !ok {
@@ -3,12 +3,17 @@
 package profile_test
 
 import (
+	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
+	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/profile"
+	pprof "github.com/google/pprof/profile"
 )
 
 type Circuit struct {
@@ -59,3 +64,167 @@ func Example() {
 	// Output:
 	// 2
 }
+
+func TestWriteFolded(t *testing.T) {
+	p := profile.Start(profile.WithNoOutput())
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	var buf bytes.Buffer
+	if err := p.WriteFolded(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 stacks, got %d:\n%s", len(lines), buf.String())
+	}
+	total := 0
+	for _, l := range lines {
+		i := strings.LastIndexByte(l, ' ')
+		n, err := strconv.Atoi(l[i+1:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += n
+		if !strings.HasPrefix(l, "profile_test.(*Circuit).Define profile_test.go:") {
+			t.Fatalf("stack should start at the circuit definition: %s", l)
+		}
+	}
+	if total != p.NbConstraints() {
+		t.Fatalf("expected %d constraints, got %d", p.NbConstraints(), total)
+	}
+}
+
+func TestWritePprof(t *testing.T) {
+	p := profile.Start(profile.WithNoOutput())
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	var buf bytes.Buffer
+	if err := p.WritePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := pprof.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Sample) != p.NbConstraints() {
+		t.Fatalf("expected %d samples, got %d", p.NbConstraints(), len(parsed.Sample))
+	}
+}
+
+type doubleCircuit struct {
+	A frontend.Variable
+}
+
+func (circuit *doubleCircuit) Define(api frontend.API) error {
+	var o obj
+	o.Define(api, circuit.A)
+	o.Define(api, circuit.A)
+	return nil
+}
+
+func TestDiff(t *testing.T) {
+	before := profile.Start(profile.WithNoOutput())
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before.Stop()
+
+	after := profile.Start(profile.WithNoOutput())
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &doubleCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after.Stop()
+
+	deltas := make(map[string]int64)
+	for _, d := range profile.Diff(before, after) {
+		deltas[d.Function] = d.Delta()
+	}
+	expected := map[string]int64{
+		"profile_test.(*Circuit).Define":       -2,
+		"profile_test.(*doubleCircuit).Define": 4,
+		"profile_test.(*obj).Define":           2,
+		"r1cs.(*builder).Mul":                  1,
+		"r1cs.(*builder).AssertIsEqual":        1,
+	}
+	if len(deltas) != len(expected) {
+		t.Fatalf("unexpected deltas:\n%s", profile.Diff(before, after))
+	}
+	for f, d := range expected {
+		if deltas[f] != d {
+			t.Fatalf("expected delta %+d for %s, got %+d:\n%s", d, f, deltas[f], profile.Diff(before, after))
+		}
+	}
+}
+
+func TestGadgets(t *testing.T) {
+	p := profile.Start(profile.WithNoOutput())
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &doubleCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	gadgets := p.Gadgets()
+	if len(gadgets) != 1 || gadgets["profile_test.(*obj).Define"] != 4 {
+		t.Fatalf("unexpected gadgets: %v", gadgets)
+	}
+}
+
+func TestSolveTime(t *testing.T) {
+	p := profile.Start(profile.WithNoOutput(), profile.WithSolveTime())
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &doubleCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	w, err := frontend.NewWitness(&doubleCircuit{A: 1}, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ccs.Solve(w, p.SolverOption()); err != nil {
+		t.Fatal(err)
+	}
+	if p.SolveTime() <= 0 {
+		t.Fatal("no solve time recorded")
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteSolvePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := pprof.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Sample) != ccs.GetNbInstructions() {
+		t.Fatalf("expected %d samples, got %d", ccs.GetNbInstructions(), len(parsed.Sample))
+	}
+
+	buf.Reset()
+	if err := p.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"profile_test.(*doubleCircuit).Define", "profile_test.(*obj).Define", "r1cs.(*builder).Mul"} {
+		if !strings.Contains(buf.String(), f) {
+			t.Fatalf("missing %s in report:\n%s", f, buf.String())
+		}
+	}
+
+	// the solve time isn't recorded without the option
+	p = profile.Start(profile.WithNoOutput())
+	p.Stop()
+	if _, err := ccs.Solve(w, p.SolverOption()); err == nil {
+		t.Fatal("expected error without WithSolveTime")
+	}
+}
@@ -18,9 +18,10 @@ var chCommands = make(chan command, 100)
 var onceInit sync.Once
 
 type command struct {
-	p      *Profile
-	pc     []uintptr
-	remove bool
+	p           *Profile
+	pc          []uintptr
+	remove      bool
+	instruction bool
 }
 
 func worker() {
@@ -38,6 +39,9 @@ func worker() {
 
 				// decrement active sessions
 				atomic.AddUint32(&activeSessions, ^uint32(0))
+				if c.p.solveTime {
+					atomic.AddUint32(&solveSessions, ^uint32(0))
+				}
 			} else {
 				sessions = append(sessions, c.p)
 			}
@@ -45,7 +49,11 @@ func worker() {
 		}
 
 		// it's a sampling of event
-		collectSample(c.pc)
+		if c.instruction {
+			collectInstruction(c.pc)
+		} else {
+			collectSample(c.pc)
+		}
 	}
 
 }
@@ -57,7 +65,37 @@ func collectSample(pc []uintptr) {
 	for i := 0; i < len(samples); i++ {
 		samples[i] = &profile.Sample{Value: []int64{1}} // for now, we just collect new constraints count
 	}
+	addLocations(sessions, samples, pc)
+
+	for i := 0; i < len(sessions); i++ {
+		sessions[i].pprof.Sample = append(sessions[i].pprof.Sample, samples[i])
+	}
+
+}
+
+// collectInstruction must be called from the worker go routine
+func collectInstruction(pc []uintptr) {
+	var targets []*Profile
+	for _, p := range sessions {
+		if p.solveTime {
+			targets = append(targets, p)
+		}
+	}
+	// the value is the time spent solving the instruction, set when solving
+	samples := make([]*profile.Sample, len(targets))
+	for i := range samples {
+		samples[i] = &profile.Sample{Value: []int64{0}}
+	}
+	addLocations(targets, samples, pc)
+
+	for i, p := range targets {
+		p.instructions = append(p.instructions, samples[i])
+	}
+}
 
+// addLocations adds the frames of the call stack pc to the sample of each
+// session.
+func addLocations(sessions []*Profile, samples []*profile.Sample, pc []uintptr) {
 	frames := runtime.CallersFrames(pc)
 	// Loop to get frames.
 	// A fixed number of pcs can expand to an indefinite number of Frames.
@@ -77,7 +115,7 @@ func collectSample(pc []uintptr) {
 		}
 
 		// filter internal builder functions
-		if filterSCSPrivateFunc(frame.Function) || filterR1CSPrivateFunc(frame.Function) {
+		if filterSCSPrivateFunc(frame.Function) || filterR1CSPrivateFunc(frame.Function) || filterSystemFunc(frame.Function) {
 			continue
 		}
 
@@ -108,11 +146,6 @@ func collectSample(pc []uintptr) {
 			// break --> we break when we hit frontend.parseCircuit; in case we have nested Define calls in the stack.
 		}
 	}
-
-	for i := 0; i < len(sessions); i++ {
-		sessions[i].pprof.Sample = append(sessions[i].pprof.Sample, samples[i])
-	}
-
 }
 
 func filterSCSPrivateFunc(f string) bool {
@@ -127,6 +160,12 @@ func filterSCSPrivateFunc(f string) bool {
 	return false
 }
 
+// filterSystemFunc filters the methods of the constraint system adding the
+// instructions.
+func filterSystemFunc(f string) bool {
+	return strings.HasPrefix(f, "github.com/consensys/gnark/constraint.(*System).")
+}
+
 func filterR1CSPrivateFunc(f string) bool {
 	const r1csPrefix = "github.com/consensys/gnark/frontend/cs/r1cs.(*builder)."
 	if strings.HasPrefix(f, r1csPrefix) && len(f) > len(r1csPrefix) {
@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/google/pprof/profile"
+)
+
+// SolverOption returns a solver option attributing the time spent solving each
+// instruction to its call stack, recorded with the [WithSolveTime] option. It
+// must be given to the Solve method (or to the prover with
+// backend.WithSolverOptions) of the constraint system compiled during the
+// session, after the session is stopped. The times of several solving runs are
+// added.
+//
+// The option sets an instruction hook (see [solver.WithInstructionHook]), so
+// that the instructions are solved sequentially and the time of an instruction
+// is the wall-clock time until the next one. It replaces the hooks of the
+// other options, such as the debugger's.
+func (p *Profile) SolverOption() solver.Option {
+	return func(opt *solver.Config) error {
+		if !p.solveTime {
+			return errors.New("profile: solve time is not recorded, see WithSolveTime")
+		}
+		if p.chDone != nil {
+			return errors.New("profile: the session must be stopped before solving")
+		}
+		last := time.Now()
+		opt.InstructionHook = func(instructionID int, _ solver.State, _ error) error {
+			now := time.Now()
+			if instructionID < len(p.instructions) {
+				p.instructions[instructionID].Value[0] += now.Sub(last).Nanoseconds()
+			}
+			last = now
+			return nil
+		}
+		return nil
+	}
+}
+
+// SolveTime returns the total time spent solving the instructions recorded by
+// the session.
+func (p *Profile) SolveTime() time.Duration {
+	var total int64
+	for _, s := range p.instructions {
+		total += s.Value[0]
+	}
+	return time.Duration(total)
+}
+
+// WriteSolvePprof writes the solve-time profile in the pprof format, where
+// the samples are the time spent solving the instructions at each source
+// location. See [Profile.WritePprof].
+func (p *Profile) WriteSolvePprof(w io.Writer) error {
+	solvePprof := profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "solve_time", Unit: "nanoseconds"}},
+		Sample:     p.instructions,
+		Location:   p.pprof.Location,
+		Function:   p.pprof.Function,
+		Mapping:    p.pprof.Mapping,
+	}
+	return solvePprof.Write(w)
+}
+
+// WriteReport writes a table of the number of constraints and of the solve
+// time attributed to each function, sorted by decreasing solve time. As for
+// [Diff], a function is attributed the constraints and the time of all the
+// functions it calls:
+//
+//	constraints  solve time  solve%
+//	       1024     1.2ms    85.3%  sha2.(*digest).Sum
+//	...
+func (p *Profile) WriteReport(w io.Writer) error {
+	constraints := p.cumulative()
+	solveTimes := cumulative(p.instructions)
+	total := p.SolveTime()
+
+	functions := make([]string, 0, len(constraints))
+	for f := range constraints {
+		functions = append(functions, f)
+	}
+	for f := range solveTimes {
+		if _, ok := constraints[f]; !ok {
+			functions = append(functions, f)
+		}
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		ti, tj := solveTimes[functions[i]], solveTimes[functions[j]]
+		if ti != tj {
+			return ti > tj
+		}
+		if constraints[functions[i]] != constraints[functions[j]] {
+			return constraints[functions[i]] > constraints[functions[j]]
+		}
+		return functions[i] < functions[j]
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "constraints\tsolve time\tsolve%\t\t")
+	for _, f := range functions {
+		share := 0.
+		if total > 0 {
+			share = 100 * float64(solveTimes[f]) / float64(total)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.1f%%\t\t%s\n", constraints[f], time.Duration(solveTimes[f]), share, f)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,350 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Tree is a Merkle tree computed out of circuit, whose proofs are verified in
+// circuit by [MerkleProof.VerifyProof] with the FieldHasher counterpart of its
+// hash, for example the MiMC implementations of gnark-crypto and
+// [github.com/consensys/gnark/std/hash/mimc]. As in circuit, the leaves are
+// field elements, a leaf is hashed alone and a node is the hash of its
+// children, without domain separation.
+//
+// The tree has a fixed depth and 2^depth leaves, which are 0 until inserted.
+// Only the nodes above the inserted leaves are stored.
+type Tree struct {
+	h     stdhash.Hash
+	depth int
+
+	leaves map[uint64]*big.Int
+	nodes  []map[uint64][]byte // by level, the leaves' hashes at level 0
+	empty  [][]byte            // by level, the hash of the subtrees with only zero leaves
+}
+
+// Proof is the proof that a leaf is in a [Tree], see [Tree.Prove].
+type Proof struct {
+	Root  []byte
+	Index uint64 // index of the leaf
+
+	// Path is the encoding of the leaf followed by the siblings of its
+	// ancestors, from the leaf to the root, as in [MerkleProof].
+	Path [][]byte
+}
+
+// MultiProof is the proof that several leaves are in a [Tree], sharing the
+// nodes common to their paths, see [Tree.MultiProve].
+type MultiProof struct {
+	Root    []byte
+	Indices []uint64   // indices of the leaves, increasing
+	Leaves  []*big.Int // values of the leaves
+
+	// Nodes are the siblings of the ancestors of the leaves which are not
+	// ancestors of the leaves, level by level from the leaves to the root, and
+	// by increasing index in a level.
+	Nodes [][]byte
+}
+
+// NewTree returns a tree of the given depth with zero leaves, hashing with h.
+// The field elements are written to h in big-endian encoding of h.BlockSize()
+// bytes.
+func NewTree(h stdhash.Hash, depth int) (*Tree, error) {
+	if depth < 0 || depth > 63 {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+	t := &Tree{
+		h:      h,
+		depth:  depth,
+		leaves: make(map[uint64]*big.Int),
+		nodes:  make([]map[uint64][]byte, depth+1),
+		empty:  make([][]byte, depth+1),
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[uint64][]byte)
+	}
+	var err error
+	if t.empty[0], err = leafHash(h, new(big.Int)); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= depth; i++ {
+		if t.empty[i], err = nodeHash(h, t.empty[i-1], t.empty[i-1]); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return slices.Clone(t.node(t.depth, 0))
+}
+
+// Leaf returns the value of the leaf at index.
+func (t *Tree) Leaf(index uint64) (*big.Int, error) {
+	if err := t.checkIndex(index); err != nil {
+		return nil, err
+	}
+	if v, ok := t.leaves[index]; ok {
+		return new(big.Int).Set(v), nil
+	}
+	return new(big.Int), nil
+}
+
+// Insert sets the leaf at index to value and updates its ancestors.
+func (t *Tree) Insert(index uint64, value *big.Int) error {
+	if err := t.checkIndex(index); err != nil {
+		return err
+	}
+	digest, err := leafHash(t.h, value)
+	if err != nil {
+		return err
+	}
+	t.leaves[index] = new(big.Int).Set(value)
+	t.nodes[0][index] = digest
+	for level := 1; level <= t.depth; level++ {
+		index >>= 1
+		digest, err = nodeHash(t.h, t.node(level-1, 2*index), t.node(level-1, 2*index+1))
+		if err != nil {
+			return err
+		}
+		t.nodes[level][index] = digest
+	}
+	return nil
+}
+
+// Prove returns the proof that the leaf at index is in the tree.
+func (t *Tree) Prove(index uint64) (Proof, error) {
+	value, err := t.Leaf(index)
+	if err != nil {
+		return Proof{}, err
+	}
+	leaf, err := encode(t.h, value)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof := Proof{Root: t.Root(), Index: index, Path: [][]byte{leaf}}
+	for level := 0; level < t.depth; level++ {
+		proof.Path = append(proof.Path, slices.Clone(t.node(level, index^1)))
+		index >>= 1
+	}
+	return proof, nil
+}
+
+// MultiProve returns the proof that the leaves at the given indices are in the
+// tree. It is smaller than the proofs of the leaves, as the nodes common to
+// their paths are not repeated.
+func (t *Tree) MultiProve(indices ...uint64) (MultiProof, error) {
+	indices = slices.Clone(indices)
+	slices.Sort(indices)
+	indices = slices.Compact(indices)
+	proof := MultiProof{Root: t.Root(), Indices: indices}
+	for _, i := range indices {
+		value, err := t.Leaf(i)
+		if err != nil {
+			return MultiProof{}, err
+		}
+		proof.Leaves = append(proof.Leaves, value)
+	}
+
+	known := indices
+	for level := 0; level < t.depth; level++ {
+		var parents []uint64
+		for j, i := range known {
+			if i%2 == 1 && j > 0 && known[j-1] == i-1 {
+				continue // the sibling is known, and the parent added
+			}
+			if j+1 >= len(known) || known[j+1] != i^1 {
+				proof.Nodes = append(proof.Nodes, slices.Clone(t.node(level, i^1)))
+			}
+			parents = append(parents, i>>1)
+		}
+		known = parents
+	}
+	return proof, nil
+}
+
+func (t *Tree) node(level int, index uint64) []byte {
+	if n, ok := t.nodes[level][index]; ok {
+		return n
+	}
+	return t.empty[level]
+}
+
+func (t *Tree) checkIndex(index uint64) error {
+	if index >= 1<<t.depth {
+		return fmt.Errorf("index %d out of bounds for a tree of depth %d", index, t.depth)
+	}
+	return nil
+}
+
+// VerifyProof returns true if the proof, given by a tree hashing with h, is
+// valid.
+func VerifyProof(h stdhash.Hash, proof Proof) bool {
+	if len(proof.Path) == 0 || len(proof.Path) > 64 || proof.Index>>(len(proof.Path)-1) != 0 {
+		return false
+	}
+	h.Reset()
+	if _, err := h.Write(proof.Path[0]); err != nil {
+		return false
+	}
+	digest := h.Sum(nil)
+	index := proof.Index
+	for _, sibling := range proof.Path[1:] {
+		var err error
+		if index%2 == 0 {
+			digest, err = nodeHash(h, digest, sibling)
+		} else {
+			digest, err = nodeHash(h, sibling, digest)
+		}
+		if err != nil {
+			return false
+		}
+		index >>= 1
+	}
+	return bytes.Equal(digest, proof.Root)
+}
+
+// Proofs returns the proofs of the leaves of the multi proof, for a tree of
+// the given depth hashing with h, for example to verify them in circuit. It
+// returns an error if the multi proof is invalid.
+func (proof *MultiProof) Proofs(h stdhash.Hash, depth int) ([]Proof, error) {
+	nodes, err := proof.nodes(h, depth)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Proof, len(proof.Indices))
+	for j, index := range proof.Indices {
+		leaf, err := encode(h, proof.Leaves[j])
+		if err != nil {
+			return nil, err
+		}
+		res[j] = Proof{Root: slices.Clone(proof.Root), Index: index, Path: [][]byte{leaf}}
+		for level := 0; level < depth; level++ {
+			res[j].Path = append(res[j].Path, slices.Clone(nodes[level][index^1]))
+			index >>= 1
+		}
+	}
+	return res, nil
+}
+
+// VerifyMultiProof returns true if the multi proof, given by a tree of the
+// given depth hashing with h, is valid.
+func VerifyMultiProof(h stdhash.Hash, depth int, proof MultiProof) bool {
+	_, err := proof.nodes(h, depth)
+	return err == nil
+}
+
+// nodes returns, by level, the nodes of the paths of the leaves and their
+// siblings, after checking that they lead to the root.
+func (proof *MultiProof) nodes(h stdhash.Hash, depth int) ([]map[uint64][]byte, error) {
+	errInvalid := errors.New("invalid multi proof")
+	if depth < 0 || depth > 63 || len(proof.Indices) == 0 || len(proof.Indices) != len(proof.Leaves) {
+		return nil, errInvalid
+	}
+	nodes := make([]map[uint64][]byte, depth+1)
+	for i := range nodes {
+		nodes[i] = make(map[uint64][]byte)
+	}
+	for j, index := range proof.Indices {
+		if index>>depth != 0 || (j > 0 && index <= proof.Indices[j-1]) {
+			return nil, errInvalid
+		}
+		digest, err := leafHash(h, proof.Leaves[j])
+		if err != nil {
+			return nil, err
+		}
+		nodes[0][index] = digest
+	}
+
+	known, remaining := proof.Indices, proof.Nodes
+	for level := 0; level < depth; level++ {
+		var parents []uint64
+		for j, i := range known {
+			if i%2 == 1 && j > 0 && known[j-1] == i-1 {
+				continue
+			}
+			if _, ok := nodes[level][i^1]; !ok {
+				if len(remaining) == 0 {
+					return nil, errInvalid
+				}
+				nodes[level][i^1], remaining = remaining[0], remaining[1:]
+			}
+			left, right := nodes[level][i&^1], nodes[level][i|1]
+			digest, err := nodeHash(h, left, right)
+			if err != nil {
+				return nil, err
+			}
+			nodes[level+1][i>>1] = digest
+			parents = append(parents, i>>1)
+		}
+		known = parents
+	}
+	if len(remaining) != 0 || !bytes.Equal(nodes[depth][0], proof.Root) {
+		return nil, errInvalid
+	}
+	return nodes, nil
+}
+
+// Assignment returns the assignment of the proof in circuit, with the index of
+// the leaf to give to [MerkleProof.VerifyProof].
+func (proof *Proof) Assignment() (mp MerkleProof, leaf frontend.Variable) {
+	mp.RootHash = new(big.Int).SetBytes(proof.Root)
+	mp.Path = make([]frontend.Variable, len(proof.Path))
+	for i := range proof.Path {
+		mp.Path[i] = new(big.Int).SetBytes(proof.Path[i])
+	}
+	return mp, proof.Index
+}
+
+// Placeholder returns a proof for a tree of the given depth, to define a
+// circuit verifying it.
+func Placeholder(depth int) MerkleProof {
+	return MerkleProof{Path: make([]frontend.Variable, depth+1)}
+}
+
+// encode returns the big-endian encoding of the field element v, as written
+// to h.
+func encode(h stdhash.Hash, v *big.Int) ([]byte, error) {
+	res := make([]byte, h.BlockSize())
+	if v.Sign() < 0 || v.BitLen() > 8*len(res) {
+		return nil, fmt.Errorf("leaf %s is not a field element", v)
+	}
+	return v.FillBytes(res), nil
+}
+
+// leafHash hashes the leaf as leafSum in circuit.
+func leafHash(h stdhash.Hash, v *big.Int) ([]byte, error) {
+	leaf, err := encode(h, v)
+	if err != nil {
+		return nil, err
+	}
+	h.Reset()
+	if _, err := h.Write(leaf); err != nil {
+		return nil, fmt.Errorf("hash leaf: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// nodeHash hashes the children as nodeSum in circuit.
+func nodeHash(h stdhash.Hash, left, right []byte) ([]byte, error) {
+	h.Reset()
+	if _, err := h.Write(left); err != nil {
+		return nil, fmt.Errorf("hash node: %w", err)
+	}
+	if _, err := h.Write(right); err != nil {
+		return nil, fmt.Errorf("hash node: %w", err)
+	}
+	return h.Sum(nil), nil
+}
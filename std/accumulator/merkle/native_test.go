@@ -0,0 +1,80 @@
+package merkle
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	assert := require.New(t)
+	const depth = 5
+	h := hash.MIMC_BN254.New()
+
+	tree, err := NewTree(h, depth)
+	assert.NoError(err)
+
+	// a full tree has the root of gnark-crypto
+	var buf bytes.Buffer
+	for i := uint64(0); i < 1<<depth; i++ {
+		v := big.NewInt(int64(3*i + 1))
+		assert.NoError(tree.Insert(i, v))
+		leaf, err := encode(h, v)
+		assert.NoError(err)
+		buf.Write(leaf)
+	}
+	root, _, _, err := merkletree.BuildReaderProof(&buf, h, h.BlockSize(), 0)
+	assert.NoError(err)
+	assert.Equal(root, tree.Root())
+
+	// a sparse tree
+	tree, err = NewTree(h, depth)
+	assert.NoError(err)
+	assert.NoError(tree.Insert(3, big.NewInt(42)))
+	assert.NoError(tree.Insert(17, big.NewInt(7)))
+	assert.NoError(tree.Insert(17, big.NewInt(8)))
+	assert.Error(tree.Insert(1<<depth, big.NewInt(1)))
+	v, err := tree.Leaf(17)
+	assert.NoError(err)
+	assert.Equal(int64(8), v.Int64())
+
+	circuit := MerkleProofTest{M: Placeholder(depth)}
+	for _, index := range []uint64{3, 17, 20} {
+		proof, err := tree.Prove(index)
+		assert.NoError(err)
+		assert.True(VerifyProof(h, proof))
+		var assignment MerkleProofTest
+		assignment.M, assignment.Leaf = proof.Assignment()
+		assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+
+		proof.Path[0], proof.Path[1] = proof.Path[1], proof.Path[0]
+		assert.False(VerifyProof(h, proof))
+	}
+
+	// a multi proof gives the proofs of the leaves
+	indices := []uint64{20, 3, 2, 17, 31}
+	multi, err := tree.MultiProve(indices...)
+	assert.NoError(err)
+	assert.True(VerifyMultiProof(h, depth, multi))
+	assert.Less(len(multi.Nodes), len(indices)*depth)
+	proofs, err := multi.Proofs(h, depth)
+	assert.NoError(err)
+	assert.Len(proofs, len(indices))
+	for _, proof := range proofs {
+		expected, err := tree.Prove(proof.Index)
+		assert.NoError(err)
+		assert.Equal(expected, proof)
+	}
+
+	multi.Leaves[1] = big.NewInt(41)
+	assert.False(VerifyMultiProof(h, depth, multi))
+	multi.Leaves[1] = big.NewInt(42)
+	multi.Nodes = multi.Nodes[1:]
+	assert.False(VerifyMultiProof(h, depth, multi))
+}
@@ -42,7 +42,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package merkle provides a ZKP-circuit function to verify merkle proofs.
+// Package merkle provides a ZKP-circuit function to verify merkle proofs, and
+// a native Merkle tree computing the proofs it verifies.
 package merkle
 
 import (
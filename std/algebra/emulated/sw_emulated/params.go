@@ -9,6 +9,7 @@ import (
 	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
 )
 
 // CurveParams defines parameters of an elliptic curve in short Weierstrass form
@@ -133,6 +134,40 @@ func GetBW6761Params() CurveParams {
 	}
 }
 
+// GetPallasParams returns the curve parameters for the curve Pallas of the
+// Pasta cycle. When initialising new curve, use the base field
+// [emparams.PastaFp] and scalar field [emparams.PastaFq].
+func GetPallasParams() CurveParams {
+	fp := emparams.PastaFp{}.Modulus()
+	gx := new(big.Int).Sub(fp, big.NewInt(1))
+	return CurveParams{
+		A:            big.NewInt(0),
+		B:            big.NewInt(5),
+		Gx:           gx,
+		Gy:           big.NewInt(2),
+		Gm:           computePastaTable(fp, gx, big.NewInt(2)),
+		Eigenvalue:   nil,
+		ThirdRootOne: nil,
+	}
+}
+
+// GetVestaParams returns the curve parameters for the curve Vesta of the Pasta
+// cycle. When initialising new curve, use the base field [emparams.PastaFq]
+// and scalar field [emparams.PastaFp].
+func GetVestaParams() CurveParams {
+	fq := emparams.PastaFq{}.Modulus()
+	gx := new(big.Int).Sub(fq, big.NewInt(1))
+	return CurveParams{
+		A:            big.NewInt(0),
+		B:            big.NewInt(5),
+		Gx:           gx,
+		Gy:           big.NewInt(2),
+		Gm:           computePastaTable(fq, gx, big.NewInt(2)),
+		Eigenvalue:   nil,
+		ThirdRootOne: nil,
+	}
+}
+
 // GetCurveParams returns suitable curve parameters given the parametric type
 // Base as base field. It caches the parameters and modifying the values in the
 // parameters struct leads to undefined behaviour.
@@ -151,6 +186,10 @@ func GetCurveParams[Base emulated.FieldParams]() CurveParams {
 		return p384Params
 	case emulated.BW6761Fp{}.Modulus().String():
 		return bw6761Params
+	case emparams.PastaFp{}.Modulus().String():
+		return pallasParams
+	case emparams.PastaFq{}.Modulus().String():
+		return vestaParams
 	default:
 		panic("no stored parameters")
 	}
@@ -163,6 +202,8 @@ var (
 	p256Params      CurveParams
 	p384Params      CurveParams
 	bw6761Params    CurveParams
+	pallasParams    CurveParams
+	vestaParams     CurveParams
 )
 
 func init() {
@@ -172,4 +213,6 @@ func init() {
 	p256Params = GetP256Params()
 	p384Params = GetP384Params()
 	bw6761Params = GetBW6761Params()
+	pallasParams = GetPallasParams()
+	vestaParams = GetVestaParams()
 }
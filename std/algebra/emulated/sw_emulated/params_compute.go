@@ -157,3 +157,50 @@ func computeBW6761Table() [][2]*big.Int {
 	}
 	return table
 }
+
+// computePastaTable computes the table of the Pasta curves Y² = X³ + 5 over
+// the field of modulus p, in affine coordinates as there is no implementation
+// of the curves in gnark-crypto.
+func computePastaTable(p, gx, gy *big.Int) [][2]*big.Int {
+	nbBits := p.BitLen()
+	table := make([][2]*big.Int, nbBits)
+	tmpx, tmpy := new(big.Int).Set(gx), new(big.Int).Set(gy)
+	for i := 1; i < nbBits; i++ {
+		tmpx, tmpy = pastaAdd(p, tmpx, tmpy, tmpx, tmpy)
+		switch i {
+		case 1, 2:
+			xx, yy := pastaAdd(p, tmpx, tmpy, gx, gy)
+			table[i-1] = [2]*big.Int{xx, yy}
+		case 3:
+			xx, yy := pastaAdd(p, tmpx, tmpy, gx, new(big.Int).Sub(p, gy))
+			table[i-1] = [2]*big.Int{xx, yy}
+			fallthrough
+		default:
+			table[i] = [2]*big.Int{tmpx, tmpy}
+		}
+	}
+	return table
+}
+
+// pastaAdd adds or doubles affine points of a curve with a = 0, different
+// from the point at infinity and not opposite.
+func pastaAdd(p, x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	num, den := new(big.Int), new(big.Int)
+	if x1.Cmp(x2) == 0 && y1.Cmp(y2) == 0 {
+		// λ = 3x²/2y
+		num.Mul(x1, x1).Mul(num, big.NewInt(3))
+		den.Lsh(y1, 1)
+	} else {
+		// λ = (y2-y1)/(x2-x1)
+		num.Sub(y2, y1)
+		den.Sub(x2, x1)
+	}
+	den.Mod(den, p).ModInverse(den, p)
+	lambda := num.Mul(num, den)
+	lambda.Mod(lambda, p)
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, x1).Sub(x, x2).Mod(x, p)
+	y := new(big.Int).Sub(x1, x)
+	y.Mul(y, lambda).Sub(y, y1).Mod(y, p)
+	return x, y
+}
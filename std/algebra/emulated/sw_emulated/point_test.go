@@ -619,6 +619,38 @@ func TestScalarMulBase6(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestScalarMulBase7(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := GetPallasParams()
+	s, err := rand.Int(rand.Reader, emparams.PastaFq{}.Modulus())
+	assert.NoError(err)
+	qx, qy := pastaScalarMul(emparams.PastaFp{}.Modulus(), params.Gx, params.Gy, s)
+
+	circuit := ScalarMulBaseTest[emparams.PastaFp, emparams.PastaFq]{}
+	witness := ScalarMulBaseTest[emparams.PastaFp, emparams.PastaFq]{
+		S: emulated.ValueOf[emparams.PastaFq](s),
+		Q: AffinePoint[emparams.PastaFp]{
+			X: emulated.ValueOf[emparams.PastaFp](qx),
+			Y: emulated.ValueOf[emparams.PastaFp](qy),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
+// pastaScalarMul computes [s](x, y) on a Pasta curve with double-and-add, for
+// a non-zero scalar s.
+func pastaScalarMul(p, x, y, s *big.Int) (*big.Int, *big.Int) {
+	rx, ry := new(big.Int).Set(x), new(big.Int).Set(y)
+	for i := s.BitLen() - 2; i >= 0; i-- {
+		rx, ry = pastaAdd(p, rx, ry, rx, ry)
+		if s.Bit(i) == 1 {
+			rx, ry = pastaAdd(p, rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
+
 type ScalarMulTest[T, S emulated.FieldParams] struct {
 	P, Q AffinePoint[T]
 	S    emulated.Element[S]
@@ -784,6 +816,31 @@ func TestScalarMul6(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestScalarMul7(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := GetVestaParams()
+	fq := emparams.PastaFq{}.Modulus()
+	s, err := rand.Int(rand.Reader, emparams.PastaFp{}.Modulus())
+	assert.NoError(err)
+	px, py := pastaAdd(fq, params.Gx, params.Gy, params.Gx, params.Gy)
+	qx, qy := pastaScalarMul(fq, px, py, s)
+
+	circuit := ScalarMulTest[emparams.PastaFq, emparams.PastaFp]{}
+	witness := ScalarMulTest[emparams.PastaFq, emparams.PastaFp]{
+		S: emulated.ValueOf[emparams.PastaFp](s),
+		P: AffinePoint[emparams.PastaFq]{
+			X: emulated.ValueOf[emparams.PastaFq](px),
+			Y: emulated.ValueOf[emparams.PastaFq](py),
+		},
+		Q: AffinePoint[emparams.PastaFq]{
+			X: emulated.ValueOf[emparams.PastaFq](qx),
+			Y: emulated.ValueOf[emparams.PastaFq](qy),
+		},
+	}
+	err = test.IsSolved(&circuit, &witness, testCurve.ScalarField())
+	assert.NoError(err)
+}
+
 type ScalarMulEdgeCasesTest[T, S emulated.FieldParams] struct {
 	P, R AffinePoint[T]
 	S    emulated.Element[S]
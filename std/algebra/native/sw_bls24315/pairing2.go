@@ -39,7 +39,7 @@ func NewCurve(api frontend.API) (*Curve, error) {
 func (c *Curve) MarshalScalar(s Scalar) []frontend.Variable {
 	nbBits := 8 * ((ScalarField{}.Modulus().BitLen() + 7) / 8)
 	ss := c.fr.Reduce(&s)
-	x := c.fr.ToBits(ss)
+	x := c.fr.ToBits(ss)[:nbBits]
 	for i, j := 0, nbBits-1; i < j; {
 		x[i], x[j] = x[j], x[i]
 		i++
@@ -0,0 +1,13 @@
+// Package sw_grumpkin implements the arithmetics of the Grumpkin curve as a
+// SNARK circuit over BN254. Grumpkin is defined over the scalar field of BN254
+// and its group order is the base field modulus of BN254, so that the two
+// curves form a cycle: the point operations use native field arithmetic and
+// the scalars are emulated.
+//
+// The curve is y² = x³ - 17 and has a prime order, so that it has no point of
+// order 2. The point at infinity is represented as (0, 0), which is not on the
+// curve.
+//
+// References:
+// Grumpkin: https://hackmd.io/@aztec-network/ByzgNxBfd#2-Grumpkin---A-curve-on-top-of-BN-254-for-SNARK-efficient-group-operations
+package sw_grumpkin
@@ -0,0 +1,241 @@
+package sw_grumpkin
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// B is the constant of the curve equation y² = x³ + B.
+const B = -17
+
+var (
+	gx    = big.NewInt(1)
+	gy, _ = new(big.Int).SetString("17631683881184975370165255887551781615748388533673675138860", 10)
+)
+
+// ScalarField defines the [emulated.FieldParams] implementation of the scalar
+// field of Grumpkin, which is the base field of BN254.
+type ScalarField = emparams.BN254Fp
+
+// Scalar is a scalar of Grumpkin, emulated in the circuits over BN254.
+type Scalar = emulated.Element[ScalarField]
+
+// G1Affine is a point of Grumpkin in affine coordinates.
+type G1Affine struct {
+	X, Y frontend.Variable
+}
+
+// Generator returns the coordinates of the generator of Grumpkin.
+func Generator() (x, y *big.Int) {
+	return new(big.Int).Set(gx), new(big.Int).Set(gy)
+}
+
+// NewG1Affine allocates a witness from the coordinates of a point.
+func NewG1Affine(x, y *big.Int) G1Affine {
+	return G1Affine{X: new(big.Int).Set(x), Y: new(big.Int).Set(y)}
+}
+
+// NewScalar allocates a witness from the scalar.
+func NewScalar(v *big.Int) Scalar {
+	return emulated.ValueOf[ScalarField](v)
+}
+
+// Curve allows computing the group operations of Grumpkin in a circuit over
+// BN254.
+type Curve struct {
+	api frontend.API
+	fr  *emulated.Field[ScalarField]
+}
+
+// NewCurve initializes a new [Curve] instance. It returns an error if the
+// native field is not the scalar field of BN254.
+func NewCurve(api frontend.API) (*Curve, error) {
+	if api.Compiler().Field().Cmp(ecc.BN254.ScalarField()) != 0 {
+		return nil, fmt.Errorf("native field must be the scalar field of BN254")
+	}
+	f, err := emulated.NewField[ScalarField](api)
+	if err != nil {
+		return nil, fmt.Errorf("scalar field: %w", err)
+	}
+	return &Curve{api: api, fr: f}, nil
+}
+
+// MarshalScalar returns the binary decomposition of the scalar in big-endian
+// order.
+func (c *Curve) MarshalScalar(s Scalar) []frontend.Variable {
+	nbBits := 8 * ((ScalarField{}.Modulus().BitLen() + 7) / 8)
+	ss := c.fr.Reduce(&s)
+	x := c.fr.ToBits(ss)[:nbBits]
+	for i, j := 0, nbBits-1; i < j; {
+		x[i], x[j] = x[j], x[i]
+		i++
+		j--
+	}
+	return x
+}
+
+// MarshalG1 returns [P.X || P.Y] in binary, both in big-endian order. As in
+// the native serialization, the second most significant bit flags the point at
+// infinity.
+func (c *Curve) MarshalG1(P G1Affine) []frontend.Variable {
+	nbBits := 8 * ((ecc.BN254.ScalarField().BitLen() + 7) / 8)
+	res := make([]frontend.Variable, 2*nbBits)
+	x := bits.ToBinary(c.api, P.X, bits.WithNbDigits(nbBits))
+	y := bits.ToBinary(c.api, P.Y, bits.WithNbDigits(nbBits))
+	for i := 0; i < nbBits; i++ {
+		res[i] = x[nbBits-1-i]
+		res[i+nbBits] = y[nbBits-1-i]
+	}
+	res[1] = c.isInfinity(&P)
+	return res
+}
+
+// Add returns P+Q. It does not modify the inputs. The points must be
+// different, not opposite and not at infinity, see [Curve.AddUnified] for the
+// complete addition.
+func (c *Curve) Add(P, Q *G1Affine) *G1Affine {
+	// λ = (yq-yp)/(xq-xp)
+	λ := c.api.DivUnchecked(c.api.Sub(Q.Y, P.Y), c.api.Sub(Q.X, P.X))
+	return c.chord(λ, P, Q)
+}
+
+// double returns 2P for P not at infinity.
+func (c *Curve) double(P *G1Affine) *G1Affine {
+	// λ = 3xp²/2yp
+	λ := c.api.DivUnchecked(c.api.Mul(P.X, P.X, 3), c.api.Mul(P.Y, 2))
+	return c.chord(λ, P, P)
+}
+
+// chord returns the third point on the line of slope λ through P and Q,
+// negated.
+func (c *Curve) chord(λ frontend.Variable, P, Q *G1Affine) *G1Affine {
+	// xr = λ²-xp-xq
+	xr := c.api.Sub(c.api.Mul(λ, λ), P.X, Q.X)
+	// yr = λ(xp-xr)-yp
+	yr := c.api.Sub(c.api.Mul(λ, c.api.Sub(P.X, xr)), P.Y)
+	return &G1Affine{X: xr, Y: yr}
+}
+
+// AddUnified returns P+Q for any points P and Q, including equal or opposite
+// points and the point at infinity (0, 0). It does not modify the inputs.
+func (c *Curve) AddUnified(P, Q *G1Affine) *G1Affine {
+	api := c.api
+	sameX := api.IsZero(api.Sub(Q.X, P.X))
+	// λ = (yq-yp)/(xq-xp), or 3xp²/2yp when doubling. As there is no point of
+	// order 2, yp is non-zero when doubling a point which is not at infinity.
+	num := api.Select(sameX, api.Mul(P.X, P.X, 3), api.Sub(Q.Y, P.Y))
+	den := api.Select(sameX, api.Mul(P.Y, 2), api.Sub(Q.X, P.X))
+	isDenZero := api.IsZero(den)
+	λ := api.DivUnchecked(num, api.Select(isDenZero, 1, den))
+	R := c.chord(λ, P, Q)
+
+	// P = -Q, or P = Q = O
+	isInf := api.And(sameX, api.Or(isDenZero, api.IsZero(api.Add(P.Y, Q.Y))))
+	R = c.Select(isInf, &G1Affine{X: 0, Y: 0}, R)
+	// P = O or Q = O
+	R = c.Select(c.isInfinity(P), Q, R)
+	R = c.Select(c.isInfinity(Q), P, R)
+	return R
+}
+
+func (c *Curve) isInfinity(P *G1Affine) frontend.Variable {
+	return c.api.And(c.api.IsZero(P.X), c.api.IsZero(P.Y))
+}
+
+// AssertIsEqual asserts that P and Q are the same point.
+func (c *Curve) AssertIsEqual(P, Q *G1Affine) {
+	c.api.AssertIsEqual(P.X, Q.X)
+	c.api.AssertIsEqual(P.Y, Q.Y)
+}
+
+// AssertIsOnCurve asserts that P is on the curve or is the point at infinity.
+func (c *Curve) AssertIsOnCurve(P *G1Affine) {
+	// y² = x³ - 17, or (x, y) = (0, 0)
+	left := c.api.Mul(P.Y, P.Y)
+	right := c.api.Add(c.api.Mul(P.X, P.X, P.X), B)
+	c.api.AssertIsEqual(c.api.Select(c.isInfinity(P), right, left), right)
+}
+
+// Neg returns -P. It does not modify the input.
+func (c *Curve) Neg(P *G1Affine) *G1Affine {
+	return &G1Affine{X: P.X, Y: c.api.Neg(P.Y)}
+}
+
+// ScalarMul returns [s]P. It does not modify the inputs. The scalar
+// multiplication is complete, the option [algopts.WithCompleteArithmetic] is
+// accepted for compatibility.
+func (c *Curve) ScalarMul(P *G1Affine, s *Scalar, opts ...algopts.AlgebraOption) *G1Affine {
+	if _, err := algopts.NewConfig(opts...); err != nil {
+		panic(err)
+	}
+	sBits := c.fr.ToBits(c.fr.Reduce(s))
+	res := &G1Affine{X: 0, Y: 0}
+	for i := len(sBits) - 1; i >= 0; i-- {
+		res = c.AddUnified(res, res)
+		res = c.Select(sBits[i], c.AddUnified(res, P), res)
+	}
+	return res
+}
+
+// ScalarMulBase returns [s]G, where G is the generator of Grumpkin.
+func (c *Curve) ScalarMulBase(s *Scalar, opts ...algopts.AlgebraOption) *G1Affine {
+	return c.ScalarMul(&G1Affine{X: gx, Y: gy}, s, opts...)
+}
+
+// MultiScalarMul returns ∑ [scalars[i]]P[i]. It returns an error if the input
+// lengths mismatch.
+func (c *Curve) MultiScalarMul(P []*G1Affine, scalars []*Scalar, opts ...algopts.AlgebraOption) (*G1Affine, error) {
+	if len(P) != len(scalars) {
+		return nil, fmt.Errorf("mismatching points and scalars slice lengths")
+	}
+	res := &G1Affine{X: 0, Y: 0}
+	for i := range P {
+		res = c.AddUnified(res, c.ScalarMul(P[i], scalars[i], opts...))
+	}
+	return res, nil
+}
+
+// Select sets p1 if b=1, p2 if b=0, and returns it. b must be boolean constrained
+func (c *Curve) Select(b frontend.Variable, p1, p2 *G1Affine) *G1Affine {
+	return &G1Affine{
+		X: c.api.Select(b, p1.X, p2.X),
+		Y: c.api.Select(b, p1.Y, p2.Y),
+	}
+}
+
+// Lookup2 performs a 2-bit lookup between p1, p2, p3, p4 based on bits b0  and b1.
+// Returns:
+//   - p1 if b0=0 and b1=0,
+//   - p2 if b0=1 and b1=0,
+//   - p3 if b0=0 and b1=1,
+//   - p4 if b0=1 and b1=1.
+func (c *Curve) Lookup2(b1, b2 frontend.Variable, p1, p2, p3, p4 *G1Affine) *G1Affine {
+	return &G1Affine{
+		X: c.api.Lookup2(b1, b2, p1.X, p2.X, p3.X, p4.X),
+		Y: c.api.Lookup2(b1, b2, p1.Y, p2.Y, p3.Y, p4.Y),
+	}
+}
+
+// Mux performs a lookup from the inputs and returns inputs[sel]. It is most
+// efficient for power of two lengths of the inputs, but works for any number of
+// inputs.
+func (c *Curve) Mux(sel frontend.Variable, inputs ...*G1Affine) *G1Affine {
+	xs := make([]frontend.Variable, len(inputs))
+	ys := make([]frontend.Variable, len(inputs))
+	for i := range inputs {
+		xs[i] = inputs[i].X
+		ys[i] = inputs[i].Y
+	}
+	return &G1Affine{
+		X: selector.Mux(c.api, sel, xs...),
+		Y: selector.Mux(c.api, sel, ys...),
+	}
+}
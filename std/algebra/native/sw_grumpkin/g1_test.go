@@ -0,0 +1,251 @@
+package sw_grumpkin
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// reference arithmetic of Grumpkin over the scalar field of BN254, the point
+// at infinity is nil.
+
+var modulus = ecc.BN254.ScalarField()
+
+type point struct{ x, y *big.Int }
+
+func (p *point) witness() G1Affine {
+	if p == nil {
+		return G1Affine{X: 0, Y: 0}
+	}
+	return NewG1Affine(p.x, p.y)
+}
+
+func add(p, q *point) *point {
+	if p == nil {
+		return q
+	}
+	if q == nil {
+		return p
+	}
+	var λ big.Int
+	if p.x.Cmp(q.x) == 0 {
+		if new(big.Int).Add(p.y, q.y).Mod(new(big.Int).Add(p.y, q.y), modulus).Sign() == 0 {
+			return nil
+		}
+		num := new(big.Int).Mul(p.x, p.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(p.y, 1)
+		λ.Mul(num, den.ModInverse(den, modulus))
+	} else {
+		num := new(big.Int).Sub(q.y, p.y)
+		den := new(big.Int).Sub(q.x, p.x)
+		den.Mod(den, modulus)
+		λ.Mul(num, den.ModInverse(den, modulus))
+	}
+	λ.Mod(&λ, modulus)
+	x := new(big.Int).Mul(&λ, &λ)
+	x.Sub(x, p.x).Sub(x, q.x).Mod(x, modulus)
+	y := new(big.Int).Sub(p.x, x)
+	y.Mul(y, &λ).Sub(y, p.y).Mod(y, modulus)
+	return &point{x, y}
+}
+
+func neg(p *point) *point {
+	return &point{p.x, new(big.Int).Sub(modulus, p.y)}
+}
+
+func scalarMul(p *point, s *big.Int) *point {
+	var res *point
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = add(res, res)
+		if s.Bit(i) == 1 {
+			res = add(res, p)
+		}
+	}
+	return res
+}
+
+func generator() *point {
+	x, y := Generator()
+	return &point{x, y}
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	s, err := rand.Int(rand.Reader, ScalarField{}.Modulus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGroupOrder(t *testing.T) {
+	if scalarMul(generator(), ScalarField{}.Modulus()) != nil {
+		t.Fatal("the generator is not of order the base field of BN254")
+	}
+}
+
+type addUnifiedCircuit struct {
+	P, Q, R G1Affine
+}
+
+func (c *addUnifiedCircuit) Define(api frontend.API) error {
+	cr, err := NewCurve(api)
+	if err != nil {
+		return err
+	}
+	cr.AssertIsOnCurve(&c.P)
+	cr.AssertIsOnCurve(&c.Q)
+	cr.AssertIsEqual(cr.AddUnified(&c.P, &c.Q), &c.R)
+	return nil
+}
+
+func TestAddUnified(t *testing.T) {
+	assert := test.NewAssert(t)
+	g := generator()
+	p := scalarMul(g, randomScalar(t))
+	q := scalarMul(g, randomScalar(t))
+	for _, tc := range []struct {
+		name string
+		p, q *point
+	}{
+		{"distinct", p, q},
+		{"double", p, p},
+		{"opposite", p, neg(p)},
+		{"left-infinity", nil, q},
+		{"right-infinity", p, nil},
+		{"both-infinity", nil, nil},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			assert.CheckCircuit(&addUnifiedCircuit{}, test.WithCurves(ecc.BN254),
+				test.WithValidAssignment(&addUnifiedCircuit{P: tc.p.witness(), Q: tc.q.witness(), R: add(tc.p, tc.q).witness()}),
+				test.WithInvalidAssignment(&addUnifiedCircuit{P: tc.p.witness(), Q: tc.q.witness(), R: add(add(tc.p, tc.q), g).witness()}),
+			)
+		}, tc.name)
+	}
+}
+
+type scalarMulCircuit struct {
+	P, R G1Affine
+	S    Scalar
+}
+
+func (c *scalarMulCircuit) Define(api frontend.API) error {
+	cr, err := NewCurve(api)
+	if err != nil {
+		return err
+	}
+	cr.AssertIsEqual(cr.ScalarMul(&c.P, &c.S), &c.R)
+	cr.AssertIsEqual(cr.ScalarMulBase(&c.S), cr.ScalarMul(&G1Affine{X: 1, Y: gy}, &c.S))
+	return nil
+}
+
+func TestScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	p := scalarMul(generator(), randomScalar(t))
+	s := randomScalar(t)
+	for _, tc := range []struct {
+		name string
+		s    *big.Int
+	}{
+		{"random", s},
+		{"zero", big.NewInt(0)},
+		{"one", big.NewInt(1)},
+		{"order-minus-one", new(big.Int).Sub(ScalarField{}.Modulus(), big.NewInt(1))},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(&scalarMulCircuit{}, &scalarMulCircuit{P: p.witness(), S: NewScalar(tc.s), R: scalarMul(p, tc.s).witness()}, modulus)
+			assert.NoError(err)
+			err = test.IsSolved(&scalarMulCircuit{}, &scalarMulCircuit{P: p.witness(), S: NewScalar(tc.s), R: scalarMul(p, new(big.Int).Add(tc.s, big.NewInt(1))).witness()}, modulus)
+			assert.Error(err)
+		}, tc.name)
+	}
+}
+
+type multiScalarMulCircuit struct {
+	P []G1Affine
+	S []Scalar
+	R G1Affine
+}
+
+func (c *multiScalarMulCircuit) Define(api frontend.API) error {
+	cr, err := NewCurve(api)
+	if err != nil {
+		return err
+	}
+	ps := make([]*G1Affine, len(c.P))
+	ss := make([]*Scalar, len(c.S))
+	for i := range c.P {
+		ps[i] = &c.P[i]
+		ss[i] = &c.S[i]
+	}
+	res, err := cr.MultiScalarMul(ps, ss)
+	if err != nil {
+		return err
+	}
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestMultiScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 3
+	circuit := multiScalarMulCircuit{P: make([]G1Affine, n), S: make([]Scalar, n)}
+	witness := multiScalarMulCircuit{P: make([]G1Affine, n), S: make([]Scalar, n)}
+	var res *point
+	for i := 0; i < n; i++ {
+		p := scalarMul(generator(), randomScalar(t))
+		s := randomScalar(t)
+		witness.P[i] = p.witness()
+		witness.S[i] = NewScalar(s)
+		res = add(res, scalarMul(p, s))
+	}
+	witness.R = res.witness()
+	assert.NoError(test.IsSolved(&circuit, &witness, modulus))
+}
+
+type marshalG1Circuit struct {
+	P G1Affine
+	R [2 * 256]frontend.Variable
+}
+
+func (c *marshalG1Circuit) Define(api frontend.API) error {
+	cr, err := NewCurve(api)
+	if err != nil {
+		return err
+	}
+	r := cr.MarshalG1(c.P)
+	for i := range c.R {
+		api.AssertIsEqual(r[i], c.R[i])
+	}
+	return nil
+}
+
+func TestMarshalG1(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		name string
+		p    *point
+	}{
+		{"random", scalarMul(generator(), randomScalar(t))},
+		{"infinity", nil},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			witness := marshalG1Circuit{P: tc.p.witness()}
+			var buf [64]byte
+			if tc.p != nil {
+				tc.p.x.FillBytes(buf[:32])
+				tc.p.y.FillBytes(buf[32:])
+			} else {
+				buf[0] = 0x40
+			}
+			for i := range witness.R {
+				witness.R[i] = (buf[i/8] >> (7 - i%8)) & 1
+			}
+			assert.NoError(test.IsSolved(&marshalG1Circuit{}, &witness, modulus))
+		}, tc.name)
+	}
+}
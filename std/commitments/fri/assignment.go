@@ -0,0 +1,46 @@
+package fri
+
+import (
+	"fmt"
+
+	fri_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/fri"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+)
+
+// ValueOfProofOfProximity returns the assignment of a radix-2 proof of
+// proximity built with gnark-crypto over the BN254 scalar field. The assignment
+// also gives the shape of the proof for compiling the circuit.
+func ValueOfProofOfProximity(proof fri_bn254.ProofOfProximity) (ProofOfProximity, error) {
+	if len(proof.Rounds) != nbRounds {
+		return ProofOfProximity{}, fmt.Errorf("expected %d rounds, got %d", nbRounds, len(proof.Rounds))
+	}
+	var res ProofOfProximity
+	res.Rounds = make([]Round, nbRounds)
+	for i := range proof.Rounds {
+		res.Rounds[i].Evaluation = proof.Rounds[i].Evaluation
+		res.Rounds[i].Interactions = make([][2]merkle.MerkleProof, len(proof.Rounds[i].Interactions))
+		for j, interaction := range proof.Rounds[i].Interactions {
+			// only the longest of the two paths is filled in the native proof,
+			// the first two entries of both paths are always set.
+			c := 0
+			if len(interaction[1].ProofSet) > len(interaction[0].ProofSet) {
+				c = 1
+			}
+			n := len(interaction[c].ProofSet)
+			if len(interaction[1-c].ProofSet) < 2 {
+				return ProofOfProximity{}, fmt.Errorf("round %d, step %d: truncated Merkle path", i, j)
+			}
+			for k := range interaction {
+				res.Rounds[i].Interactions[j][k].RootHash = interaction[k].MerkleRoot
+				res.Rounds[i].Interactions[j][k].Path = make([]frontend.Variable, n)
+				res.Rounds[i].Interactions[j][k].Path[0] = interaction[k].ProofSet[0]
+				res.Rounds[i].Interactions[j][k].Path[1] = interaction[k].ProofSet[1]
+				for l := 2; l < n; l++ {
+					res.Rounds[i].Interactions[j][k].Path[l] = interaction[c].ProofSet[l]
+				}
+			}
+		}
+	}
+	return res, nil
+}
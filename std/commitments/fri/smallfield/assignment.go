@@ -0,0 +1,55 @@
+package smallfield
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// ValueOfProof returns the assignment of a proof computed out of circuit.
+func ValueOfProof(proof NativeProof) Proof {
+	res := Proof{
+		Roots:   toVariables(proof.Roots),
+		Final:   toVariables(proof.Final),
+		Queries: make([][]Opening, len(proof.Queries)),
+	}
+	for k := range proof.Queries {
+		res.Queries[k] = make([]Opening, len(proof.Queries[k]))
+		for l, o := range proof.Queries[k] {
+			res.Queries[k][l] = Opening{
+				Values: [2][]frontend.Variable{toVariables(o.Values[0]), toVariables(o.Values[1])},
+				Path:   toVariables(o.Path),
+			}
+		}
+	}
+	return res
+}
+
+// PlaceholderProof returns a proof with the shape given by the parameters, for
+// compiling the circuit.
+func PlaceholderProof(params Params) Proof {
+	res := Proof{
+		Roots:   make([]frontend.Variable, params.LogDegree),
+		Final:   make([]frontend.Variable, params.ExtensionDegree),
+		Queries: make([][]Opening, params.NbQueries),
+	}
+	for k := range res.Queries {
+		res.Queries[k] = make([]Opening, params.LogDegree)
+		for l := range res.Queries[k] {
+			res.Queries[k][l] = Opening{
+				Values: [2][]frontend.Variable{
+					make([]frontend.Variable, params.ExtensionDegree),
+					make([]frontend.Variable, params.ExtensionDegree),
+				},
+				Path: make([]frontend.Variable, params.logSize()-l-1),
+			}
+		}
+	}
+	return res
+}
+
+func toVariables[T any](xs []T) []frontend.Variable {
+	res := make([]frontend.Variable, len(xs))
+	for i := range xs {
+		res[i] = xs[i]
+	}
+	return res
+}
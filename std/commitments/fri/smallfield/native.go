@@ -0,0 +1,423 @@
+package smallfield
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// NativeOpening is the opening of a pair of evaluations of a layer, out of
+// circuit.
+type NativeOpening struct {
+	Values [2][]*big.Int
+	Path   []*big.Int
+}
+
+// NativeProof is a proof of proximity out of circuit. See [Proof] for the
+// description of the fields.
+type NativeProof struct {
+	Roots   []*big.Int
+	Final   []*big.Int
+	Queries [][]NativeOpening
+}
+
+// Prove returns the proof of proximity of the polynomial with the given
+// coefficients in the base field FR, of degree less than 2^LogDegree, and the
+// hash function h over the native field of the verifier, such as the MiMC
+// implementation of gnark-crypto.
+func Prove[FR emulated.FieldParams](params Params, h hash.Hash, coefficients []*big.Int) (NativeProof, error) {
+	var fr FR
+	e := newExtension(fr.Modulus(), params)
+	if err := params.check(e.p); err != nil {
+		return NativeProof{}, err
+	}
+	if len(coefficients) > 1<<params.LogDegree {
+		return NativeProof{}, fmt.Errorf("expected at most %d coefficients, got %d", 1<<params.LogDegree, len(coefficients))
+	}
+
+	// evaluations of the first layer
+	n := 1 << params.logSize()
+	omega := params.omega(e.p)
+	evaluations := make([][]*big.Int, n)
+	x := big.NewInt(1)
+	for i := range evaluations {
+		y := new(big.Int)
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			y.Mul(y, x).Add(y, coefficients[j]).Mod(y, e.p)
+		}
+		evaluations[i] = e.fromBase(y)
+		x = new(big.Int).Mul(x, omega)
+		x.Mod(x, e.p)
+	}
+
+	var proof NativeProof
+	trees := make([][][]*big.Int, params.LogDegree)
+	layers := make([][][]*big.Int, params.LogDegree)
+	state := new(big.Int)
+	omegaInv := new(big.Int).ModInverse(omega, e.p)
+	for l := range layers {
+		layers[l] = evaluations
+		trees[l] = buildTree(h, evaluations)
+		root := trees[l][len(trees[l])-1][0]
+		proof.Roots = append(proof.Roots, root)
+		state = hashInts(h, state, root)
+		beta := e.challenge(state)
+		evaluations = e.foldLayer(evaluations, beta, omegaInv)
+		omegaInv = new(big.Int).Mul(omegaInv, omegaInv)
+		omegaInv.Mod(omegaInv, e.p)
+	}
+	for i := range evaluations {
+		if !e.equal(evaluations[i], evaluations[0]) {
+			return NativeProof{}, errors.New("the polynomial exceeds the degree bound")
+		}
+	}
+	proof.Final = evaluations[0]
+	state = hashInts(h, append([]*big.Int{state}, proof.Final...)...)
+
+	proof.Queries = make([][]NativeOpening, params.NbQueries)
+	for k := range proof.Queries {
+		index := queryIndex(h, state, k, params.logSize())
+		proof.Queries[k] = make([]NativeOpening, params.LogDegree)
+		for l := range proof.Queries[k] {
+			half := len(layers[l]) / 2
+			j := index % half
+			o := &proof.Queries[k][l]
+			o.Values = [2][]*big.Int{layers[l][j], layers[l][j+half]}
+			for level := 0; level < len(trees[l])-1; level++ {
+				o.Path = append(o.Path, trees[l][level][j^1])
+				j >>= 1
+			}
+		}
+	}
+	return proof, nil
+}
+
+// Verify verifies the proof of proximity out of circuit, as the [Verifier]
+// does in a circuit.
+func Verify[FR emulated.FieldParams](params Params, h hash.Hash, proof NativeProof) error {
+	var fr FR
+	e := newExtension(fr.Modulus(), params)
+	if err := params.check(e.p); err != nil {
+		return err
+	}
+	if err := checkNativeShape(params, e.p.BitLen(), proof); err != nil {
+		return err
+	}
+	state := new(big.Int)
+	betas := make([][]*big.Int, len(proof.Roots))
+	for l := range proof.Roots {
+		state = hashInts(h, state, proof.Roots[l])
+		betas[l] = e.challenge(state)
+	}
+	state = hashInts(h, append([]*big.Int{state}, proof.Final...)...)
+
+	omegaInv := params.omega(e.p)
+	omegaInv.ModInverse(omegaInv, e.p)
+	for k := range proof.Queries {
+		index := queryIndex(h, state, k, params.logSize())
+		var folded []*big.Int
+		cInv := new(big.Int).Set(omegaInv)
+		for l, o := range proof.Queries[k] {
+			nbBits := params.logSize() - l - 1
+			j := index & (1<<nbBits - 1)
+			leaf := hashInts(h, append(append([]*big.Int{}, o.Values[0]...), o.Values[1]...)...)
+			cur := leaf
+			for i := range o.Path {
+				if (j>>i)&1 == 1 {
+					cur = hashInts(h, o.Path[i], cur)
+				} else {
+					cur = hashInts(h, cur, o.Path[i])
+				}
+			}
+			if cur.Cmp(proof.Roots[l]) != 0 {
+				return fmt.Errorf("query %d, layer %d: invalid Merkle path", k, l)
+			}
+			a, b := e.reduce(o.Values[0]), e.reduce(o.Values[1])
+			if l > 0 {
+				queried := a
+				if (index>>nbBits)&1 == 1 {
+					queried = b
+				}
+				if !e.equal(folded, queried) {
+					return fmt.Errorf("query %d, layer %d: invalid folding", k, l)
+				}
+			}
+			xInv := new(big.Int).Exp(cInv, big.NewInt(int64(j)), e.p)
+			folded = e.fold(a, b, betas[l], xInv)
+			cInv.Mul(cInv, cInv).Mod(cInv, e.p)
+		}
+		if !e.equal(folded, e.reduce(proof.Final)) {
+			return fmt.Errorf("query %d: invalid final folding", k)
+		}
+	}
+	return nil
+}
+
+// checkNativeShape checks the shape of the proof, and that the coordinates fit
+// on the bit length of the modulus as in the circuit.
+func checkNativeShape(params Params, nbBits int, proof NativeProof) error {
+	if len(proof.Roots) != params.LogDegree || len(proof.Final) != params.ExtensionDegree || len(proof.Queries) != params.NbQueries {
+		return errors.New("invalid number of roots, of final coordinates or of queries")
+	}
+	for k := range proof.Queries {
+		if len(proof.Queries[k]) != params.LogDegree {
+			return fmt.Errorf("query %d: invalid number of openings", k)
+		}
+		for l, o := range proof.Queries[k] {
+			if len(o.Values[0]) != params.ExtensionDegree || len(o.Values[1]) != params.ExtensionDegree || len(o.Path) != params.logSize()-l-1 {
+				return fmt.Errorf("query %d, layer %d: invalid opening", k, l)
+			}
+			for _, x := range append(append([]*big.Int{}, o.Values[0]...), o.Values[1]...) {
+				if x.BitLen() > nbBits {
+					return fmt.Errorf("query %d, layer %d: coordinate on more than %d bits", k, l, nbBits)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// buildTree returns the levels of the Merkle tree of the pairs of evaluations,
+// from the leaves to the root.
+func buildTree(h hash.Hash, evaluations [][]*big.Int) [][]*big.Int {
+	half := len(evaluations) / 2
+	level := make([]*big.Int, half)
+	for j := range level {
+		level[j] = hashInts(h, append(append([]*big.Int{}, evaluations[j]...), evaluations[j+half]...)...)
+	}
+	levels := [][]*big.Int{level}
+	for len(level) > 1 {
+		next := make([]*big.Int, len(level)/2)
+		for j := range next {
+			next[j] = hashInts(h, level[2*j], level[2*j+1])
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// queryIndex returns the position of the query k.
+func queryIndex(h hash.Hash, state *big.Int, k, logSize int) int {
+	s := hashInts(h, state, big.NewInt(int64(k)))
+	return int(new(big.Int).And(s, big.NewInt(1<<logSize-1)).Int64())
+}
+
+// hashInts hashes the integers written on blocks of h.
+func hashInts(h hash.Hash, data ...*big.Int) *big.Int {
+	h.Reset()
+	buf := make([]byte, h.BlockSize())
+	for _, x := range data {
+		x.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// extension implements the arithmetic of F[X]/(Xᴰ-W).
+type extension struct {
+	p, w *big.Int
+	d    int
+}
+
+func newExtension(p *big.Int, params Params) extension {
+	return extension{p: p, w: params.NonResidue, d: params.ExtensionDegree}
+}
+
+func (e extension) fromBase(x *big.Int) []*big.Int {
+	res := make([]*big.Int, e.d)
+	res[0] = new(big.Int).Mod(x, e.p)
+	for i := 1; i < e.d; i++ {
+		res[i] = new(big.Int)
+	}
+	return res
+}
+
+func (e extension) reduce(a []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(a))
+	for i := range a {
+		res[i] = new(big.Int).Mod(a[i], e.p)
+	}
+	return res
+}
+
+func (e extension) equal(a, b []*big.Int) bool {
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (e extension) mul(a, b []*big.Int) []*big.Int {
+	res := make([]*big.Int, e.d)
+	for i := range res {
+		res[i] = new(big.Int)
+	}
+	t := new(big.Int)
+	for i := range a {
+		for j := range b {
+			t.Mul(a[i], b[j])
+			if i+j >= e.d {
+				t.Mul(t, e.w)
+			}
+			res[(i+j)%e.d].Add(res[(i+j)%e.d], t)
+		}
+	}
+	return e.reduce(res)
+}
+
+// challenge returns the element drawn from the state, see the package
+// documentation.
+func (e extension) challenge(state *big.Int) []*big.Int {
+	nbBits := uint(e.p.BitLen())
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), nbBits), big.NewInt(1))
+	res := make([]*big.Int, e.d)
+	for i := range res {
+		res[i] = new(big.Int).Rsh(state, uint(i)*nbBits)
+		res[i].And(res[i], mask).Mod(res[i], e.p)
+	}
+	return res
+}
+
+// fold returns (a+b)/2 + β(a-b)/2x.
+func (e extension) fold(a, b, beta []*big.Int, xInv *big.Int) []*big.Int {
+	diff := make([]*big.Int, e.d)
+	for i := range diff {
+		diff[i] = new(big.Int).Sub(a[i], b[i])
+	}
+	odd := e.mul(beta, e.reduce(diff))
+	inv2 := new(big.Int).ModInverse(big.NewInt(2), e.p)
+	res := make([]*big.Int, e.d)
+	for i := range res {
+		res[i] = new(big.Int).Mul(odd[i], xInv)
+		res[i].Add(res[i], a[i]).Add(res[i], b[i]).Mul(res[i], inv2).Mod(res[i], e.p)
+	}
+	return res
+}
+
+// foldLayer folds the evaluations on the domain generated by ωₗ, given ωₗ⁻¹.
+func (e extension) foldLayer(evaluations [][]*big.Int, beta []*big.Int, omegaInv *big.Int) [][]*big.Int {
+	half := len(evaluations) / 2
+	res := make([][]*big.Int, half)
+	xInv := big.NewInt(1)
+	for j := range res {
+		res[j] = e.fold(evaluations[j], evaluations[j+half], beta, xInv)
+		xInv = new(big.Int).Mul(xInv, omegaInv)
+		xInv.Mod(xInv, e.p)
+	}
+	return res
+}
+
+// WriteTo writes the binary encoding of the proof to w: the numbers of layers,
+// of coordinates and of queries, and the logarithm of the size of the domain,
+// as big-endian uint32, followed by the roots, the final coordinates and the
+// openings of the queries, each with its values and its path. The digests are
+// written on 32 bytes and the elements of the small field on 8 bytes, in
+// big-endian order.
+func (proof *NativeProof) WriteTo(w io.Writer) (int64, error) {
+	var logSize int
+	if len(proof.Queries) > 0 && len(proof.Queries[0]) > 0 {
+		logSize = len(proof.Queries[0][0].Path) + 1
+	}
+	header := []uint32{uint32(len(proof.Roots)), uint32(len(proof.Final)), uint32(len(proof.Queries)), uint32(logSize)}
+	ew := encodingWriter{w: w}
+	for _, v := range header {
+		ew.write(binary.BigEndian.AppendUint32(nil, v))
+	}
+	ew.writeInts(proof.Roots, 32)
+	ew.writeInts(proof.Final, 8)
+	for _, query := range proof.Queries {
+		for _, o := range query {
+			ew.writeInts(o.Values[0], 8)
+			ew.writeInts(o.Values[1], 8)
+			ew.writeInts(o.Path, 32)
+		}
+	}
+	return ew.n, ew.err
+}
+
+// ReadFrom reads the binary encoding of a proof written by
+// [NativeProof.WriteTo].
+func (proof *NativeProof) ReadFrom(r io.Reader) (int64, error) {
+	er := encodingReader{r: r}
+	var header [4]int
+	for i := range header {
+		header[i] = int(binary.BigEndian.Uint32(er.read(4)))
+	}
+	if er.err != nil {
+		return er.n, er.err
+	}
+	nbLayers, d, nbQueries, logSize := header[0], header[1], header[2], header[3]
+	if d > 64 || logSize > 64 || nbQueries > 1<<16 || (nbQueries > 0 && nbLayers > logSize) || (nbQueries == 0 && nbLayers > 64) {
+		return er.n, errors.New("invalid header")
+	}
+	proof.Roots = er.readInts(nbLayers, 32)
+	proof.Final = er.readInts(d, 8)
+	proof.Queries = make([][]NativeOpening, nbQueries)
+	for k := range proof.Queries {
+		proof.Queries[k] = make([]NativeOpening, nbLayers)
+		for l := range proof.Queries[k] {
+			o := &proof.Queries[k][l]
+			o.Values[0] = er.readInts(d, 8)
+			o.Values[1] = er.readInts(d, 8)
+			o.Path = er.readInts(logSize-l-1, 32)
+		}
+	}
+	return er.n, er.err
+}
+
+type encodingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (ew *encodingWriter) write(buf []byte) {
+	if ew.err != nil {
+		return
+	}
+	n, err := ew.w.Write(buf)
+	ew.n += int64(n)
+	ew.err = err
+}
+
+func (ew *encodingWriter) writeInts(xs []*big.Int, size int) {
+	for _, x := range xs {
+		if ew.err == nil && x.BitLen() > 8*size {
+			ew.err = fmt.Errorf("%s doesn't fit in %d bytes", x, size)
+		}
+		ew.write(x.FillBytes(make([]byte, size)))
+	}
+}
+
+type encodingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (er *encodingReader) read(size int) []byte {
+	buf := make([]byte, size)
+	if er.err != nil {
+		return buf
+	}
+	n, err := io.ReadFull(er.r, buf)
+	er.n += int64(n)
+	er.err = err
+	return buf
+}
+
+func (er *encodingReader) readInts(nb, size int) []*big.Int {
+	res := make([]*big.Int, nb)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(er.read(size))
+	}
+	return res
+}
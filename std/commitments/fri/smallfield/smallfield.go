@@ -0,0 +1,331 @@
+// Package smallfield implements the verifier of the FRI proximity test over
+// small prime fields, such as Goldilocks and BabyBear, for verifying the proofs
+// of the hash-based systems over these fields in a circuit.
+//
+// The field elements are emulated, while the Merkle trees and the Fiat-Shamir
+// transcript are hashed with a hash function over the native field of the
+// circuit, such as MiMC over BN254, as done by the last recursion layer of the
+// provers targeting Ethereum. The protocol is the following, with p the modulus
+// of the base field, D and W the degree and the non-residue of the extension
+// F[X]/(Xᴰ-W), L the logarithm of the degree bound and m = L + the logarithm
+// of the blowup factor:
+//
+//   - the committed function f₀ is evaluated on the subgroup of order n = 2ᵐ of
+//     the base field, generated by ω = g^((p-1)/n) where g generates the
+//     multiplicative group;
+//   - for each layer l < L, the function fₗ on the subgroup of order nₗ = n/2ˡ,
+//     generated by ωₗ = ω^(2ˡ), is committed in a Merkle tree of nₗ/2 leaves. The
+//     leaf j is H(fₗ(ωₗʲ), fₗ(-ωₗʲ)), with the D coordinates of each value,
+//     where -ωₗʲ = ωₗ^(j+nₗ/2), and the nodes are H(left, right);
+//   - the transcript has the state s = 0, and absorbing x₁…xₖ sets s to
+//     H(s, x₁…xₖ). The root of each layer l is absorbed and the challenge βₗ
+//     is drawn from the state: the i-th coordinate of an element of the
+//     extension is given by the bits [iB, (i+1)B) of s, where B is the bit
+//     length of p, reduced modulo p;
+//   - the layer l+1 folds the layer l with fₗ₊₁(x²) = (fₗ(x)+fₗ(-x))/2 +
+//     βₗ(fₗ(x)-fₗ(-x))/2x. The last function f_L is a constant c, whose
+//     coordinates are absorbed;
+//   - the query k is at the position given by the m low bits of H(s, k) in the
+//     domain of f₀. The proof opens the pairs containing the positions of the
+//     query in all the layers, with their Merkle paths.
+//
+// The soundness is driven by the number of queries, the blowup factor and the
+// size of the extension. [Prove] and [Verify] implement the protocol out of
+// circuit, and [NativeProof.WriteTo] the encoding of the proofs.
+package smallfield
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Params are the parameters of the proximity test and of the field.
+type Params struct {
+	// LogDegree is the logarithm of the degree bound of the committed
+	// polynomial, which is the number of folding layers.
+	LogDegree int
+	// LogBlowup is the logarithm of the blowup factor of the code.
+	LogBlowup int
+	// NbQueries is the number of queries.
+	NbQueries int
+	// Generator generates the multiplicative group of the base field.
+	Generator *big.Int
+	// ExtensionDegree and NonResidue define the extension F[X]/(Xᴰ-W) where
+	// the challenges are drawn.
+	ExtensionDegree int
+	NonResidue      *big.Int
+}
+
+// GoldilocksParams returns the parameters for the Goldilocks field, with its
+// quadratic extension by X²-7.
+func GoldilocksParams(logDegree, logBlowup, nbQueries int) Params {
+	return Params{
+		LogDegree:       logDegree,
+		LogBlowup:       logBlowup,
+		NbQueries:       nbQueries,
+		Generator:       big.NewInt(7),
+		ExtensionDegree: 2,
+		NonResidue:      big.NewInt(7),
+	}
+}
+
+// BabyBearParams returns the parameters for the BabyBear field, with its
+// quartic extension by X⁴-11.
+func BabyBearParams(logDegree, logBlowup, nbQueries int) Params {
+	return Params{
+		LogDegree:       logDegree,
+		LogBlowup:       logBlowup,
+		NbQueries:       nbQueries,
+		Generator:       big.NewInt(31),
+		ExtensionDegree: 4,
+		NonResidue:      big.NewInt(11),
+	}
+}
+
+// logSize returns the logarithm of the size of the evaluation domain.
+func (p Params) logSize() int {
+	return p.LogDegree + p.LogBlowup
+}
+
+func (p Params) check(modulus *big.Int) error {
+	if p.LogDegree < 1 || p.LogBlowup < 1 || p.NbQueries < 1 || p.ExtensionDegree < 1 {
+		return errors.New("the degree, the blowup factor, the number of queries and the extension degree must be positive")
+	}
+	if p.Generator == nil || p.NonResidue == nil {
+		return errors.New("missing generator or non-residue")
+	}
+	pm1 := new(big.Int).Sub(modulus, big.NewInt(1))
+	if pm1.TrailingZeroBits() < uint(p.logSize()) {
+		return fmt.Errorf("no subgroup of order 2^%d in the field", p.logSize())
+	}
+	return nil
+}
+
+// omega returns the generator of the evaluation domain.
+func (p Params) omega(modulus *big.Int) *big.Int {
+	e := new(big.Int).Sub(modulus, big.NewInt(1))
+	e.Rsh(e, uint(p.logSize()))
+	return e.Exp(p.Generator, e, modulus)
+}
+
+// Opening is the opening of a pair of evaluations of a layer.
+type Opening struct {
+	// Values are the coordinates of fₗ(x) and fₗ(-x).
+	Values [2][]frontend.Variable
+	// Path are the siblings of the leaf, from the leaves to the root.
+	Path []frontend.Variable
+}
+
+// Proof is a proof of proximity in a circuit.
+type Proof struct {
+	// Roots are the Merkle roots of the layers, the first one being the
+	// commitment to the tested function.
+	Roots []frontend.Variable
+	// Final are the coordinates of the constant of the last layer.
+	Final []frontend.Variable
+	// Queries are the openings of the layers, per query.
+	Queries [][]Opening
+}
+
+// Verifier verifies proofs of proximity over the emulated field FR.
+type Verifier[FR emulated.FieldParams] struct {
+	api    frontend.API
+	f      *emulated.Field[FR]
+	h      hash.FieldHasher
+	params Params
+}
+
+// NewVerifier returns a verifier of the proofs with the given parameters,
+// hashed with h. It returns an error if the parameters don't fit the field FR,
+// or if the challenges don't fit in a digest of h.
+func NewVerifier[FR emulated.FieldParams](api frontend.API, params Params, h hash.FieldHasher) (*Verifier[FR], error) {
+	var fr FR
+	if err := params.check(fr.Modulus()); err != nil {
+		return nil, err
+	}
+	nbBits := api.Compiler().FieldBitLen()
+	if params.ExtensionDegree*fr.Modulus().BitLen() >= nbBits || params.logSize() >= nbBits {
+		return nil, errors.New("the challenges don't fit in a native field element")
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	return &Verifier[FR]{api: api, f: f, h: h, params: params}, nil
+}
+
+// VerifyProofOfProximity asserts that the function committed in the first root
+// of the proof is close to a polynomial of degree less than 2^LogDegree.
+func (v *Verifier[FR]) VerifyProofOfProximity(proof Proof) error {
+	if err := v.checkShape(proof); err != nil {
+		return err
+	}
+
+	// transcript
+	var state frontend.Variable = 0
+	betas := make([][]*emulated.Element[FR], len(proof.Roots))
+	for l := range proof.Roots {
+		state = v.hash(state, proof.Roots[l])
+		betas[l] = v.challenge(state)
+	}
+	state = v.hash(append([]frontend.Variable{state}, proof.Final...)...)
+	final := v.toExt(proof.Final)
+
+	for k := range proof.Queries {
+		index := v.api.ToBinary(v.hash(state, k))[:v.params.logSize()]
+		v.verifyQuery(proof.Roots, index, betas, final, proof.Queries[k])
+	}
+	return nil
+}
+
+func (v *Verifier[FR]) checkShape(proof Proof) error {
+	if len(proof.Roots) != v.params.LogDegree {
+		return fmt.Errorf("expected %d roots, got %d", v.params.LogDegree, len(proof.Roots))
+	}
+	if len(proof.Final) != v.params.ExtensionDegree {
+		return fmt.Errorf("expected %d coordinates of the final value, got %d", v.params.ExtensionDegree, len(proof.Final))
+	}
+	if len(proof.Queries) != v.params.NbQueries {
+		return fmt.Errorf("expected %d queries, got %d", v.params.NbQueries, len(proof.Queries))
+	}
+	for k := range proof.Queries {
+		if len(proof.Queries[k]) != v.params.LogDegree {
+			return fmt.Errorf("query %d: expected %d openings, got %d", k, v.params.LogDegree, len(proof.Queries[k]))
+		}
+		for l, o := range proof.Queries[k] {
+			if len(o.Values[0]) != v.params.ExtensionDegree || len(o.Values[1]) != v.params.ExtensionDegree {
+				return fmt.Errorf("query %d, layer %d: expected %d coordinates", k, l, v.params.ExtensionDegree)
+			}
+			if len(o.Path) != v.params.logSize()-l-1 {
+				return fmt.Errorf("query %d, layer %d: expected a path of length %d, got %d", k, l, v.params.logSize()-l-1, len(o.Path))
+			}
+		}
+	}
+	return nil
+}
+
+// verifyQuery checks the Merkle paths of the openings and their foldings, at
+// the position given by the bits of index.
+func (v *Verifier[FR]) verifyQuery(roots []frontend.Variable, index []frontend.Variable, betas [][]*emulated.Element[FR], final []*emulated.Element[FR], openings []Opening) {
+	var folded []*emulated.Element[FR]
+	for l, o := range openings {
+		// the pair is at the position given by the low bits, and the queried
+		// value in the pair by the next bit.
+		nbBits := v.params.logSize() - l - 1
+		leaf := v.hash(append(append([]frontend.Variable{}, o.Values[0]...), o.Values[1]...)...)
+		v.verifyPath(roots[l], leaf, index[:nbBits], o.Path)
+
+		a, b := v.toExt(o.Values[0]), v.toExt(o.Values[1])
+		if l > 0 {
+			for i := range folded {
+				v.f.AssertIsEqual(folded[i], v.f.Select(index[nbBits], b[i], a[i]))
+			}
+		}
+		folded = v.fold(a, b, betas[l], v.inverseDomainPoint(l, index[:nbBits]))
+	}
+	for i := range folded {
+		v.f.AssertIsEqual(folded[i], final[i])
+	}
+}
+
+func (v *Verifier[FR]) verifyPath(root, leaf frontend.Variable, index, path []frontend.Variable) {
+	cur := leaf
+	for i := range path {
+		left := v.api.Select(index[i], path[i], cur)
+		right := v.api.Select(index[i], cur, path[i])
+		cur = v.hash(left, right)
+	}
+	v.api.AssertIsEqual(cur, root)
+}
+
+// fold returns (a+b)/2 + β(a-b)/2x.
+func (v *Verifier[FR]) fold(a, b, beta []*emulated.Element[FR], xInv *emulated.Element[FR]) []*emulated.Element[FR] {
+	var fr FR
+	inv2 := v.f.NewElement(new(big.Int).ModInverse(big.NewInt(2), fr.Modulus()))
+	diff := make([]*emulated.Element[FR], len(a))
+	for i := range a {
+		diff[i] = v.f.Sub(a[i], b[i])
+	}
+	odd := v.extMul(beta, diff)
+	res := make([]*emulated.Element[FR], len(a))
+	for i := range a {
+		res[i] = v.f.Add(v.f.Add(a[i], b[i]), v.f.Mul(odd[i], xInv))
+		res[i] = v.f.Mul(res[i], inv2)
+	}
+	return res
+}
+
+// inverseDomainPoint returns ωₗ^(-j), where j is given by its bits.
+func (v *Verifier[FR]) inverseDomainPoint(l int, bits []frontend.Variable) *emulated.Element[FR] {
+	var fr FR
+	modulus := fr.Modulus()
+	c := v.params.omega(modulus)
+	c.ModInverse(c, modulus)
+	for i := 0; i < l; i++ {
+		c.Mul(c, c).Mod(c, modulus)
+	}
+	res := v.f.One()
+	for i := range bits {
+		res = v.f.Mul(res, v.f.Select(bits[i], v.f.NewElement(new(big.Int).Set(c)), v.f.One()))
+		c.Mul(c, c).Mod(c, modulus)
+	}
+	return res
+}
+
+// extMul multiplies elements of the extension.
+func (v *Verifier[FR]) extMul(a, b []*emulated.Element[FR]) []*emulated.Element[FR] {
+	d := len(a)
+	lo := make([]*emulated.Element[FR], d)
+	hi := make([]*emulated.Element[FR], d)
+	for i := range lo {
+		lo[i] = v.f.Zero()
+		hi[i] = v.f.Zero()
+	}
+	for i := range a {
+		for j := range b {
+			if i+j < d {
+				lo[i+j] = v.f.Add(lo[i+j], v.f.Mul(a[i], b[j]))
+			} else {
+				hi[i+j-d] = v.f.Add(hi[i+j-d], v.f.Mul(a[i], b[j]))
+			}
+		}
+	}
+	for i := range lo {
+		lo[i] = v.f.Add(lo[i], v.f.MulConst(hi[i], v.params.NonResidue))
+	}
+	return lo
+}
+
+// challenge returns the element of the extension drawn from the state.
+func (v *Verifier[FR]) challenge(state frontend.Variable) []*emulated.Element[FR] {
+	var fr FR
+	nbBits := fr.Modulus().BitLen()
+	bits := v.api.ToBinary(state)
+	res := make([]*emulated.Element[FR], v.params.ExtensionDegree)
+	for i := range res {
+		res[i] = v.f.FromBits(bits[i*nbBits : (i+1)*nbBits]...)
+	}
+	return res
+}
+
+// toExt returns the element of the extension with the given coordinates.
+func (v *Verifier[FR]) toExt(coordinates []frontend.Variable) []*emulated.Element[FR] {
+	var fr FR
+	nbBits := fr.Modulus().BitLen()
+	res := make([]*emulated.Element[FR], len(coordinates))
+	for i := range coordinates {
+		res[i] = v.f.FromBits(v.api.ToBinary(coordinates[i], nbBits)...)
+	}
+	return res
+}
+
+func (v *Verifier[FR]) hash(data ...frontend.Variable) frontend.Variable {
+	v.h.Reset()
+	v.h.Write(data...)
+	return v.h.Sum()
+}
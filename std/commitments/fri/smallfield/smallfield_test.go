@@ -0,0 +1,93 @@
+package smallfield
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type proximityCircuit[FR emulated.FieldParams] struct {
+	Params Params `gnark:"-"`
+	Proof  Proof
+}
+
+func (c *proximityCircuit[FR]) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier[FR](api, c.Params, &h)
+	if err != nil {
+		return err
+	}
+	return v.VerifyProofOfProximity(c.Proof)
+}
+
+func randomCoefficients(t *testing.T, modulus *big.Int, n int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := range res {
+		var err error
+		res[i], err = rand.Int(rand.Reader, modulus)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res
+}
+
+func testProximity[FR emulated.FieldParams](t *testing.T, params Params) {
+	assert := test.NewAssert(t)
+	var fr FR
+	h := hash.MIMC_BN254.New()
+
+	coefficients := randomCoefficients(t, fr.Modulus(), 1<<params.LogDegree)
+	proof, err := Prove[FR](params, h, coefficients)
+	assert.NoError(err)
+	assert.NoError(Verify[FR](params, h, proof))
+
+	// encoding
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	encoded := buf.Bytes()
+	var read NativeProof
+	_, err = read.ReadFrom(bytes.NewReader(encoded))
+	assert.NoError(err)
+	assert.NoError(Verify[FR](params, h, read))
+	var reencoded bytes.Buffer
+	_, err = read.WriteTo(&reencoded)
+	assert.NoError(err)
+	assert.Equal(encoded, reencoded.Bytes())
+
+	circuit := &proximityCircuit[FR]{Params: params, Proof: PlaceholderProof(params)}
+	assert.NoError(test.IsSolved(circuit, &proximityCircuit[FR]{Proof: ValueOfProof(proof)}, ecc.BN254.ScalarField()))
+
+	// a modified evaluation fails the folding
+	_, err = read.ReadFrom(bytes.NewReader(encoded))
+	assert.NoError(err)
+	last := len(read.Queries[0]) - 1
+	read.Queries[0][last].Values[0][0].Add(read.Queries[0][last].Values[0][0], big.NewInt(1))
+	assert.Error(Verify[FR](params, h, read))
+	assert.Error(test.IsSolved(circuit, &proximityCircuit[FR]{Proof: ValueOfProof(read)}, ecc.BN254.ScalarField()))
+
+	// the degree is bounded
+	_, err = Prove[FR](params, h, randomCoefficients(t, fr.Modulus(), 2<<params.LogDegree))
+	assert.Error(err)
+}
+
+func TestProximityGoldilocks(t *testing.T) {
+	testProximity[emparams.Goldilocks](t, GoldilocksParams(3, 2, 2))
+}
+
+func TestProximityBabyBear(t *testing.T) {
+	testProximity[emparams.BabyBear](t, BabyBearParams(3, 2, 2))
+}
@@ -0,0 +1,58 @@
+// Package hashcommit computes the commitments of [frontend.Committer] in the
+// circuit with a hash function, for the backends without a commitment scheme
+// of their own, for example the transparent ones based on FRI, IPA or folding.
+//
+// The commitment to the variables v₀, ..., vₙ₋₁ is
+//
+//	H(depth, n, v₀, ..., vₙ₋₁)
+//
+// where depth is the number of previous commitments in the circuit. It is
+// computed from the values of the variables by the constraints of the hash,
+// so the prover can't choose the committed values after seeing the challenge
+// derived from the commitment, as with the Fiat-Shamir transform. The gadgets
+// deriving challenges with [frontend.Committer] or with the multicommit
+// package, like log-derivative lookups, then work the same on all backends:
+//
+//	ccs, err := frontend.Compile(field, scs.NewBuilder, &circuit, hashcommit.WithMiMC())
+//
+// Each commitment costs the constraints of hashing the committed variables,
+// while the commitments of the Groth16 and PLONK backends only cost one
+// constraint per committed variable in PLONK, and none in Groth16.
+package hashcommit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// WithHash returns a compile option computing the commitments with the hash
+// function returned by newHasher, see [frontend.WithHashCommitment].
+func WithHash(newHasher func(api frontend.API) (hash.FieldHasher, error)) frontend.CompileOption {
+	return frontend.WithHashCommitment(func(api frontend.API, depth int, toCommit ...frontend.Variable) (frontend.Variable, error) {
+		if len(toCommit) == 0 {
+			return nil, fmt.Errorf("must commit to at least one variable")
+		}
+		h, err := newHasher(api)
+		if err != nil {
+			return nil, fmt.Errorf("new hasher: %w", err)
+		}
+		h.Write(depth, len(toCommit))
+		h.Write(toCommit...)
+		return h.Sum(), nil
+	})
+}
+
+// WithMiMC returns a compile option computing the commitments with the MiMC
+// hash function.
+func WithMiMC() frontend.CompileOption {
+	return WithHash(func(api frontend.API) (hash.FieldHasher, error) {
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return nil, err
+		}
+		return &h, nil
+	})
+}
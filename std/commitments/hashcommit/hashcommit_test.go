@@ -0,0 +1,63 @@
+package hashcommit_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/commitments/hashcommit"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/test"
+)
+
+type lookupCircuit struct {
+	Table   [4]frontend.Variable
+	Indices [2]frontend.Variable
+	Results [2]frontend.Variable `gnark:",public"`
+}
+
+func (c *lookupCircuit) Define(api frontend.API) error {
+	t := logderivlookup.New(api)
+	for i := range c.Table {
+		t.Insert(c.Table[i])
+	}
+	res := t.Lookup(c.Indices[:]...)
+	for i := range res {
+		api.AssertIsEqual(res[i], c.Results[i])
+	}
+	// the commitments are distinct
+	cmt1, err := api.(frontend.Committer).Commit(c.Indices[0])
+	if err != nil {
+		return err
+	}
+	cmt2, err := api.(frontend.Committer).Commit(c.Indices[0])
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt1, cmt2)
+	return nil
+}
+
+func TestLookup(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&lookupCircuit{},
+		test.WithValidAssignment(&lookupCircuit{Table: [4]frontend.Variable{5, 6, 7, 8}, Indices: [2]frontend.Variable{1, 3}, Results: [2]frontend.Variable{6, 8}}),
+		test.WithInvalidAssignment(&lookupCircuit{Table: [4]frontend.Variable{5, 6, 7, 8}, Indices: [2]frontend.Variable{1, 3}, Results: [2]frontend.Variable{6, 7}}),
+		test.WithCompileOpts(hashcommit.WithMiMC()),
+		test.WithCurves(ecc.BN254, ecc.BLS12_377))
+}
+
+func TestNoBackendCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &lookupCircuit{}, hashcommit.WithMiMC())
+		assert.NoError(err)
+		assert.Empty(ccs.GetCommitments().CommitmentIndexes())
+
+		estimation, err := frontend.Estimate(ecc.BN254.ScalarField(), newBuilder, &lookupCircuit{}, hashcommit.WithMiMC())
+		assert.NoError(err)
+		assert.Equal(ccs.GetNbConstraints(), estimation.NbConstraints)
+	}
+}
@@ -0,0 +1,57 @@
+package ipa
+
+import (
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// ValueOfPoint returns the assignment of a point.
+func ValueOfPoint[B emulated.FieldParams](p NativePoint) sw_emulated.AffinePoint[B] {
+	return sw_emulated.AffinePoint[B]{
+		X: emulated.ValueOf[B](p.X),
+		Y: emulated.ValueOf[B](p.Y),
+	}
+}
+
+// ValueOfVerifyingKey returns the assignment of a verifying key. The key is
+// usually embedded in the circuit as a constant, in a field with the tag
+// `gnark:"-"`.
+func ValueOfVerifyingKey[B emulated.FieldParams](vk NativeVerifyingKey) VerifyingKey[B] {
+	res := VerifyingKey[B]{
+		G: make([]sw_emulated.AffinePoint[B], len(vk.G)),
+		U: ValueOfPoint[B](vk.U),
+		W: ValueOfPoint[B](vk.W),
+	}
+	for i := range vk.G {
+		res.G[i] = ValueOfPoint[B](vk.G[i])
+	}
+	return res
+}
+
+// ValueOfProof returns the assignment of an opening proof.
+func ValueOfProof[B, S emulated.FieldParams](proof NativeProof) Proof[B, S] {
+	res := Proof[B, S]{
+		L:     make([]sw_emulated.AffinePoint[B], len(proof.L)),
+		R:     make([]sw_emulated.AffinePoint[B], len(proof.R)),
+		A:     emulated.ValueOf[S](proof.A),
+		Blind: emulated.ValueOf[S](proof.Blind),
+	}
+	for j := range proof.L {
+		res.L[j] = ValueOfPoint[B](proof.L[j])
+		res.R[j] = ValueOfPoint[B](proof.R[j])
+	}
+	return res
+}
+
+// PlaceholderProof returns a proof for size generators, for compiling the
+// circuit.
+func PlaceholderProof[B, S emulated.FieldParams](size int) Proof[B, S] {
+	k := 0
+	for 1<<k < size {
+		k++
+	}
+	return Proof[B, S]{
+		L: make([]sw_emulated.AffinePoint[B], k),
+		R: make([]sw_emulated.AffinePoint[B], k),
+	}
+}
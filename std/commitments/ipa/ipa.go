@@ -0,0 +1,211 @@
+// Package ipa implements the verifier of the openings of the inner product
+// argument commitments, as used by the Halo2-style systems over the Pasta
+// curves, in a circuit.
+//
+// A polynomial a of size n = 2ᵏ is committed to C = ⟨a, G⟩ + [r]W, where G
+// and W are generators given by the verifying key and r is a blinding factor.
+// The opening at z proves that v = ⟨a, b⟩ with b = (1, z, …, zⁿ⁻¹), in k
+// rounds halving the vectors:
+//
+//   - the transcript has the state s = 0, and absorbing x₁…xₘ sets s to
+//     H(s, x₁…xₘ), where H is a hash function over the native field of the
+//     circuit. The points are absorbed as the coordinates of their affine
+//     representation and the scalars by their canonical value, each of them
+//     split in its 128 low bits and its high bits. The challenges are the
+//     states, as scalars;
+//   - C, z and v are absorbed, and the challenge ξ gives U' = [ξ]U;
+//   - at the round j, the prover sends L = ⟨a_lo, G_hi⟩ + [⟨a_lo, b_hi⟩]U' +
+//     [l]W and R = ⟨a_hi, G_lo⟩ + [⟨a_hi, b_lo⟩]U' + [r]W, which are absorbed to
+//     draw the challenge u, and folds a' = u·a_lo + u⁻¹·a_hi, b' = u⁻¹·b_lo +
+//     u·b_hi and G' = [u⁻¹]G_lo + [u]G_hi;
+//   - the prover sends the last coefficient A and the accumulated blinding
+//     factor B, and the verifier checks that C + [v]U' + Σ [uⱼ²]Lⱼ + [uⱼ⁻²]Rⱼ
+//     = [A](G' + [b']U') + [B]W, recomputing G' = ⟨s, G⟩ and b' = ⟨s, b⟩ from
+//     the vector s of the products of the challenges.
+//
+// The transcript being algebraic, the proofs to wrap must be computed with it,
+// which the Halo2 implementations allow by replacing their transcript. The
+// generators are given by the verifying key: [GenerateKey] derives them by
+// hashing, while the keys of an existing setup are assigned with
+// [ValueOfVerifyingKey]. [Commit], [Open] and [Verify] implement the scheme out
+// of circuit.
+package ipa
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// VerifyingKey are the generators of the commitment scheme.
+type VerifyingKey[B emulated.FieldParams] struct {
+	G    []sw_emulated.AffinePoint[B]
+	U, W sw_emulated.AffinePoint[B]
+}
+
+// Proof is an opening proof in a circuit.
+type Proof[B, S emulated.FieldParams] struct {
+	// L and R are the cross terms of the rounds.
+	L, R []sw_emulated.AffinePoint[B]
+	// A is the last coefficient and Blind the accumulated blinding factor.
+	A, Blind emulated.Element[S]
+}
+
+// Verifier verifies opening proofs over the curve with base field B and scalar
+// field S.
+type Verifier[B, S emulated.FieldParams] struct {
+	api       frontend.API
+	curve     *sw_emulated.Curve[B, S]
+	baseApi   *emulated.Field[B]
+	scalarApi *emulated.Field[S]
+	h         hash.FieldHasher
+}
+
+// NewVerifier returns a verifier over the curve with the given parameters,
+// with the transcript hashed by h. The challenges being native field elements,
+// it returns an error if the native field is larger than the scalar field.
+func NewVerifier[B, S emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, h hash.FieldHasher) (*Verifier[B, S], error) {
+	var fr S
+	if api.Compiler().FieldBitLen() > fr.Modulus().BitLen() {
+		return nil, errors.New("the native field is larger than the scalar field")
+	}
+	curve, err := sw_emulated.New[B, S](api, params)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	baseApi, err := emulated.NewField[B](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	scalarApi, err := emulated.NewField[S](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	return &Verifier[B, S]{api: api, curve: curve, baseApi: baseApi, scalarApi: scalarApi, h: h}, nil
+}
+
+// VerifyOpening asserts that the polynomial committed in commitment evaluates
+// to value at point.
+func (v *Verifier[B, S]) VerifyOpening(vk VerifyingKey[B], commitment sw_emulated.AffinePoint[B], point, value emulated.Element[S], proof Proof[B, S]) error {
+	k := len(proof.L)
+	if len(proof.R) != k || len(vk.G) != 1<<k {
+		return fmt.Errorf("expected %d rounds for %d generators, got %d and %d cross terms", k, len(vk.G), len(proof.L), len(proof.R))
+	}
+	v.curve.AssertIsOnCurve(&commitment)
+	for j := range proof.L {
+		v.curve.AssertIsOnCurve(&proof.L[j])
+		v.curve.AssertIsOnCurve(&proof.R[j])
+	}
+
+	// transcript
+	data := []frontend.Variable{0}
+	data = append(data, v.marshalPoint(&commitment)...)
+	data = append(data, v.marshalScalar(&point)...)
+	data = append(data, v.marshalScalar(&value)...)
+	state := v.hash(data...)
+	xi := v.challenge(state)
+	u := make([]*emulated.Element[S], k)
+	uInv := make([]*emulated.Element[S], k)
+	for j := range u {
+		data = append([]frontend.Variable{state}, v.marshalPoint(&proof.L[j])...)
+		data = append(data, v.marshalPoint(&proof.R[j])...)
+		state = v.hash(data...)
+		u[j] = v.challenge(state)
+		uInv[j] = v.scalarApi.Inverse(u[j])
+	}
+
+	// s has the products of the challenges, the first round giving the most
+	// significant bit of the index.
+	s := []*emulated.Element[S]{v.scalarApi.One()}
+	for j := range u {
+		next := make([]*emulated.Element[S], 2*len(s))
+		for i := range s {
+			next[2*i] = v.scalarApi.Mul(s[i], uInv[j])
+			next[2*i+1] = v.scalarApi.Mul(s[i], u[j])
+		}
+		s = next
+	}
+	// b' = Π (uⱼ⁻¹ + uⱼ z^(2^(k-1-j)))
+	zPow := make([]*emulated.Element[S], k)
+	if k > 0 {
+		zPow[k-1] = &point
+		for j := k - 2; j >= 0; j-- {
+			zPow[j] = v.scalarApi.Mul(zPow[j+1], zPow[j+1])
+		}
+	}
+	bFinal := v.scalarApi.One()
+	for j := range u {
+		bFinal = v.scalarApi.Mul(bFinal, v.scalarApi.Add(uInv[j], v.scalarApi.Mul(u[j], zPow[j])))
+	}
+
+	// [A](⟨s, G⟩ + [b']U') + [B]W
+	points := make([]*sw_emulated.AffinePoint[B], 0, len(vk.G)+2)
+	scalars := make([]*emulated.Element[S], 0, len(vk.G)+2)
+	for i := range vk.G {
+		points = append(points, &vk.G[i])
+		scalars = append(scalars, v.scalarApi.Mul(&proof.A, s[i]))
+	}
+	points = append(points, &vk.U, &vk.W)
+	uCoeff := v.scalarApi.Mul(xi, v.scalarApi.Sub(v.scalarApi.Mul(&proof.A, bFinal), &value))
+	scalars = append(scalars, uCoeff, &proof.Blind)
+	lhs, err := v.curve.MultiScalarMul(points, scalars, algopts.WithCompleteArithmetic())
+	if err != nil {
+		return err
+	}
+
+	// C + Σ [uⱼ²]Lⱼ + [uⱼ⁻²]Rⱼ
+	points = points[:0]
+	scalars = scalars[:0]
+	for j := range u {
+		points = append(points, &proof.L[j], &proof.R[j])
+		scalars = append(scalars, v.scalarApi.Mul(u[j], u[j]), v.scalarApi.Mul(uInv[j], uInv[j]))
+	}
+	rhs, err := v.curve.MultiScalarMul(points, scalars, algopts.WithCompleteArithmetic())
+	if err != nil {
+		return err
+	}
+	rhs = v.curve.AddUnified(rhs, &commitment)
+
+	v.curve.AssertIsEqual(lhs, rhs)
+	return nil
+}
+
+// marshalPoint returns the coordinates of p split for the transcript.
+func (v *Verifier[B, S]) marshalPoint(p *sw_emulated.AffinePoint[B]) []frontend.Variable {
+	return append(marshal(v.api, v.baseApi, &p.X), marshal(v.api, v.baseApi, &p.Y)...)
+}
+
+// marshalScalar returns the scalar split for the transcript.
+func (v *Verifier[B, S]) marshalScalar(s *emulated.Element[S]) []frontend.Variable {
+	return marshal(v.api, v.scalarApi, s)
+}
+
+// marshal returns the 128 low bits and the high bits of the canonical value of
+// x.
+func marshal[T emulated.FieldParams](api frontend.API, f *emulated.Field[T], x *emulated.Element[T]) []frontend.Variable {
+	var fp T
+	nbBits := fp.Modulus().BitLen()
+	r := f.Reduce(x)
+	f.AssertIsInRange(r)
+	bits := f.ToBits(r)[:nbBits]
+	if nbBits <= 128 {
+		return []frontend.Variable{api.FromBinary(bits...), 0}
+	}
+	return []frontend.Variable{api.FromBinary(bits[:128]...), api.FromBinary(bits[128:]...)}
+}
+
+// challenge returns the state as a scalar.
+func (v *Verifier[B, S]) challenge(state frontend.Variable) *emulated.Element[S] {
+	return v.scalarApi.FromBits(v.api.ToBinary(state)...)
+}
+
+func (v *Verifier[B, S]) hash(data ...frontend.Variable) frontend.Variable {
+	v.h.Reset()
+	v.h.Write(data...)
+	return v.h.Sum()
+}
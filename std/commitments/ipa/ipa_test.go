@@ -0,0 +1,113 @@
+package ipa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type openingCircuit[B, S emulated.FieldParams] struct {
+	VerifyingKey VerifyingKey[B] `gnark:"-"`
+	Commitment   sw_emulated.AffinePoint[B]
+	Point, Value emulated.Element[S]
+	Proof        Proof[B, S]
+}
+
+func (c *openingCircuit[B, S]) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier[B, S](api, sw_emulated.GetCurveParams[B](), &h)
+	if err != nil {
+		return err
+	}
+	return v.VerifyOpening(c.VerifyingKey, c.Commitment, c.Point, c.Value, c.Proof)
+}
+
+func randomScalars(t *testing.T, modulus *big.Int, n int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := range res {
+		var err error
+		res[i], err = rand.Int(rand.Reader, modulus)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res
+}
+
+func TestOpeningVesta(t *testing.T) {
+	assert := test.NewAssert(t)
+	const size = 4
+	fp := emparams.PastaFp{}.Modulus()
+	h := hash.MIMC_BN254.New()
+
+	vk, err := GenerateKey[emparams.PastaFq, emparams.PastaFp](sw_emulated.GetVestaParams(), size, []byte("test"))
+	assert.NoError(err)
+	coefficients := randomScalars(t, fp, size)
+	r := randomScalars(t, fp, 2)
+	blinding, point := r[0], r[1]
+	commitment, err := Commit[emparams.PastaFq, emparams.PastaFp](vk, coefficients, blinding)
+	assert.NoError(err)
+	proof, value, err := Open[emparams.PastaFq, emparams.PastaFp](vk, h, coefficients, blinding, point)
+	assert.NoError(err)
+	assert.NoError(Verify[emparams.PastaFq, emparams.PastaFp](vk, h, commitment, point, value, proof))
+
+	// the value is the evaluation of the polynomial
+	expected := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		expected.Mul(expected, point).Add(expected, coefficients[i]).Mod(expected, fp)
+	}
+	assert.Equal(0, expected.Cmp(value))
+
+	circuit := &openingCircuit[emparams.PastaFq, emparams.PastaFp]{
+		VerifyingKey: ValueOfVerifyingKey[emparams.PastaFq](vk),
+		Proof:        PlaceholderProof[emparams.PastaFq, emparams.PastaFp](size),
+	}
+	assignment := &openingCircuit[emparams.PastaFq, emparams.PastaFp]{
+		Commitment: ValueOfPoint[emparams.PastaFq](commitment),
+		Point:      emulated.ValueOf[emparams.PastaFp](point),
+		Value:      emulated.ValueOf[emparams.PastaFp](value),
+		Proof:      ValueOfProof[emparams.PastaFq, emparams.PastaFp](proof),
+	}
+	assert.NoError(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()))
+
+	// another value fails
+	wrong := new(big.Int).Add(value, big.NewInt(1))
+	wrong.Mod(wrong, fp)
+	assert.Error(Verify[emparams.PastaFq, emparams.PastaFp](vk, h, commitment, point, wrong, proof))
+	assignment.Value = emulated.ValueOf[emparams.PastaFp](wrong)
+	assert.Error(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()))
+}
+
+func TestOpeningPallasNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	const size = 8
+	fq := emparams.PastaFq{}.Modulus()
+	h := hash.MIMC_BN254.New()
+
+	vk, err := GenerateKey[emparams.PastaFp, emparams.PastaFq](sw_emulated.GetPallasParams(), size, []byte("test"))
+	assert.NoError(err)
+	coefficients := randomScalars(t, fq, size-1)
+	r := randomScalars(t, fq, 2)
+	commitment, err := Commit[emparams.PastaFp, emparams.PastaFq](vk, coefficients, r[0])
+	assert.NoError(err)
+	proof, value, err := Open[emparams.PastaFp, emparams.PastaFq](vk, h, coefficients, r[0], r[1])
+	assert.NoError(err)
+	assert.NoError(Verify[emparams.PastaFp, emparams.PastaFq](vk, h, commitment, r[1], value, proof))
+
+	// the proof is bound to the commitment
+	other, err := Commit[emparams.PastaFp, emparams.PastaFq](vk, coefficients, new(big.Int).Add(r[0], big.NewInt(1)))
+	assert.NoError(err)
+	assert.Error(Verify[emparams.PastaFp, emparams.PastaFq](vk, h, other, r[1], value, proof))
+}
@@ -0,0 +1,349 @@
+package ipa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// NativePoint is an affine point out of circuit, (0,0) being the point at
+// infinity as in [sw_emulated.AffinePoint].
+type NativePoint struct {
+	X, Y *big.Int
+}
+
+// NativeVerifyingKey are the generators of the commitment scheme out of
+// circuit, with the parameters of their curve.
+type NativeVerifyingKey struct {
+	Curve sw_emulated.CurveParams
+	G     []NativePoint
+	U, W  NativePoint
+}
+
+// NativeProof is an opening proof out of circuit. See [Proof] for the
+// description of the fields.
+type NativeProof struct {
+	L, R     []NativePoint
+	A, Blind *big.Int
+}
+
+// GenerateKey derives size generators, a power of two, and the generators U
+// and W from the domain separator, for the curve of base field B and scalar
+// field S with the given parameters. The generator of index i, U being of
+// index size and W of index size+1, is the first point whose x-coordinate is
+// SHA-256(domain ‖ i ‖ c) modulo the modulus of B, for the smallest counter c,
+// i and c being written as big-endian uint32, with the smallest of its two
+// y-coordinates.
+func GenerateKey[B, S emulated.FieldParams](params sw_emulated.CurveParams, size int, domain []byte) (NativeVerifyingKey, error) {
+	if size < 1 || size&(size-1) != 0 {
+		return NativeVerifyingKey{}, fmt.Errorf("size %d is not a power of two", size)
+	}
+	c := newCurve[B, S](params)
+	points := make([]NativePoint, size+2)
+	for i := range points {
+		for counter := uint32(0); ; counter++ {
+			h := sha256.New()
+			h.Write(domain)
+			h.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+			h.Write(binary.BigEndian.AppendUint32(nil, counter))
+			x := new(big.Int).SetBytes(h.Sum(nil))
+			x.Mod(x, c.p)
+			if y := c.sqrt(c.rhs(x)); y != nil {
+				if neg := new(big.Int).Sub(c.p, y); neg.Cmp(y) < 0 {
+					y = neg
+				}
+				points[i] = NativePoint{X: x, Y: y}
+				break
+			}
+		}
+	}
+	return NativeVerifyingKey{Curve: params, G: points[:size], U: points[size], W: points[size+1]}, nil
+}
+
+// Commit returns the commitment to the polynomial with the given coefficients,
+// blinded by blinding.
+func Commit[B, S emulated.FieldParams](vk NativeVerifyingKey, coefficients []*big.Int, blinding *big.Int) (NativePoint, error) {
+	if len(coefficients) > len(vk.G) {
+		return NativePoint{}, fmt.Errorf("expected at most %d coefficients, got %d", len(vk.G), len(coefficients))
+	}
+	c := newCurve[B, S](vk.Curve)
+	return c.add(c.msm(vk.G[:len(coefficients)], coefficients), c.scalarMul(vk.W, blinding)), nil
+}
+
+// Open returns the opening proof at point of the polynomial with the given
+// coefficients, committed with blinding, and its evaluation. The transcript is
+// hashed with h over the native field of the verifier, such as the MiMC
+// implementation of gnark-crypto.
+func Open[B, S emulated.FieldParams](vk NativeVerifyingKey, h hash.Hash, coefficients []*big.Int, blinding, point *big.Int) (NativeProof, *big.Int, error) {
+	c := newCurve[B, S](vk.Curve)
+	commitment, err := Commit[B, S](vk, coefficients, blinding)
+	if err != nil {
+		return NativeProof{}, nil, err
+	}
+	n := len(vk.G)
+	a := make([]*big.Int, n)
+	b := make([]*big.Int, n)
+	x := big.NewInt(1)
+	for i := range a {
+		a[i] = new(big.Int)
+		if i < len(coefficients) {
+			a[i].Mod(coefficients[i], c.r)
+		}
+		b[i] = x
+		x = new(big.Int).Mul(x, point)
+		x.Mod(x, c.r)
+	}
+	value := c.inner(a, b)
+	g := append([]NativePoint{}, vk.G...)
+
+	state := hashInts(h, append(append(append([]*big.Int{new(big.Int)}, c.marshalPoint(commitment)...), c.marshalScalar(point)...), c.marshalScalar(value)...)...)
+	u := c.scalarMul(vk.U, new(big.Int).Mod(state, c.r))
+
+	var proof NativeProof
+	blind := new(big.Int).Mod(blinding, c.r)
+	for len(a) > 1 {
+		half := len(a) / 2
+		bl, err := rand.Int(rand.Reader, c.r)
+		if err != nil {
+			return NativeProof{}, nil, err
+		}
+		br, err := rand.Int(rand.Reader, c.r)
+		if err != nil {
+			return NativeProof{}, nil, err
+		}
+		l := c.add(c.add(c.msm(g[half:], a[:half]), c.scalarMul(u, c.inner(a[:half], b[half:]))), c.scalarMul(vk.W, bl))
+		r := c.add(c.add(c.msm(g[:half], a[half:]), c.scalarMul(u, c.inner(a[half:], b[:half]))), c.scalarMul(vk.W, br))
+		proof.L = append(proof.L, l)
+		proof.R = append(proof.R, r)
+
+		state = hashInts(h, append(append([]*big.Int{state}, c.marshalPoint(l)...), c.marshalPoint(r)...)...)
+		uj := new(big.Int).Mod(state, c.r)
+		ujInv := new(big.Int).ModInverse(uj, c.r)
+		if ujInv == nil {
+			return NativeProof{}, nil, errors.New("zero challenge")
+		}
+		for i := 0; i < half; i++ {
+			a[i] = c.addScalars(c.mulScalars(uj, a[i]), c.mulScalars(ujInv, a[i+half]))
+			b[i] = c.addScalars(c.mulScalars(ujInv, b[i]), c.mulScalars(uj, b[i+half]))
+			g[i] = c.add(c.scalarMul(g[i], ujInv), c.scalarMul(g[i+half], uj))
+		}
+		a, b, g = a[:half], b[:half], g[:half]
+		uj2 := c.mulScalars(uj, uj)
+		ujInv2 := c.mulScalars(ujInv, ujInv)
+		blind = c.addScalars(blind, c.addScalars(c.mulScalars(uj2, bl), c.mulScalars(ujInv2, br)))
+	}
+	proof.A = a[0]
+	proof.Blind = blind
+	return proof, value, nil
+}
+
+// Verify verifies the opening proof out of circuit, as the [Verifier] does in
+// a circuit.
+func Verify[B, S emulated.FieldParams](vk NativeVerifyingKey, h hash.Hash, commitment NativePoint, point, value *big.Int, proof NativeProof) error {
+	c := newCurve[B, S](vk.Curve)
+	k := len(proof.L)
+	if len(proof.R) != k || len(vk.G) != 1<<k {
+		return fmt.Errorf("expected %d rounds for %d generators, got %d and %d cross terms", k, len(vk.G), len(proof.L), len(proof.R))
+	}
+	for _, p := range append(append([]NativePoint{commitment}, proof.L...), proof.R...) {
+		if !c.isOnCurve(p) {
+			return errors.New("point not on the curve")
+		}
+	}
+	for _, x := range []*big.Int{point, value, proof.A, proof.Blind} {
+		if x.Sign() < 0 || x.Cmp(c.r) >= 0 {
+			return errors.New("scalar not reduced")
+		}
+	}
+
+	state := hashInts(h, append(append(append([]*big.Int{new(big.Int)}, c.marshalPoint(commitment)...), c.marshalScalar(point)...), c.marshalScalar(value)...)...)
+	xi := new(big.Int).Mod(state, c.r)
+	u := make([]*big.Int, k)
+	uInv := make([]*big.Int, k)
+	for j := range u {
+		state = hashInts(h, append(append([]*big.Int{state}, c.marshalPoint(proof.L[j])...), c.marshalPoint(proof.R[j])...)...)
+		u[j] = new(big.Int).Mod(state, c.r)
+		uInv[j] = new(big.Int).ModInverse(u[j], c.r)
+		if uInv[j] == nil {
+			return errors.New("zero challenge")
+		}
+	}
+	s := []*big.Int{big.NewInt(1)}
+	for j := range u {
+		next := make([]*big.Int, 2*len(s))
+		for i := range s {
+			next[2*i] = c.mulScalars(s[i], uInv[j])
+			next[2*i+1] = c.mulScalars(s[i], u[j])
+		}
+		s = next
+	}
+	bFinal := big.NewInt(1)
+	zPow := new(big.Int).Set(point)
+	for j := k - 1; j >= 0; j-- {
+		bFinal = c.mulScalars(bFinal, c.addScalars(uInv[j], c.mulScalars(u[j], zPow)))
+		zPow = c.mulScalars(zPow, zPow)
+	}
+
+	scalars := make([]*big.Int, len(s))
+	for i := range s {
+		scalars[i] = c.mulScalars(proof.A, s[i])
+	}
+	lhs := c.msm(vk.G, scalars)
+	uCoeff := c.mulScalars(xi, c.addScalars(c.mulScalars(proof.A, bFinal), new(big.Int).Neg(value)))
+	lhs = c.add(lhs, c.add(c.scalarMul(vk.U, uCoeff), c.scalarMul(vk.W, proof.Blind)))
+
+	rhs := commitment
+	for j := range u {
+		rhs = c.add(rhs, c.add(c.scalarMul(proof.L[j], c.mulScalars(u[j], u[j])), c.scalarMul(proof.R[j], c.mulScalars(uInv[j], uInv[j]))))
+	}
+	if lhs.X.Cmp(rhs.X) != 0 || lhs.Y.Cmp(rhs.Y) != 0 {
+		return errors.New("invalid opening proof")
+	}
+	return nil
+}
+
+// hashInts hashes the integers written on blocks of h.
+func hashInts(h hash.Hash, data ...*big.Int) *big.Int {
+	h.Reset()
+	buf := make([]byte, h.BlockSize())
+	for _, x := range data {
+		x.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// curve implements the arithmetic of the short Weierstrass curve in affine
+// coordinates.
+type curve struct {
+	p, r, a, b *big.Int
+}
+
+func newCurve[B, S emulated.FieldParams](params sw_emulated.CurveParams) curve {
+	var fp B
+	var fr S
+	return curve{p: fp.Modulus(), r: fr.Modulus(), a: params.A, b: params.B}
+}
+
+func (c curve) rhs(x *big.Int) *big.Int {
+	res := new(big.Int).Mul(x, x)
+	res.Add(res, c.a).Mul(res, x).Add(res, c.b)
+	return res.Mod(res, c.p)
+}
+
+func (c curve) sqrt(x *big.Int) *big.Int {
+	return new(big.Int).ModSqrt(x, c.p)
+}
+
+func isInfinity(p NativePoint) bool {
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+func infinity() NativePoint {
+	return NativePoint{X: new(big.Int), Y: new(big.Int)}
+}
+
+func (c curve) isOnCurve(p NativePoint) bool {
+	if p.X == nil || p.Y == nil {
+		return false
+	}
+	if isInfinity(p) {
+		return true
+	}
+	if p.X.Sign() < 0 || p.X.Cmp(c.p) >= 0 || p.Y.Sign() < 0 || p.Y.Cmp(c.p) >= 0 {
+		return false
+	}
+	y2 := new(big.Int).Mul(p.Y, p.Y)
+	return y2.Mod(y2, c.p).Cmp(c.rhs(p.X)) == 0
+}
+
+func (c curve) add(p, q NativePoint) NativePoint {
+	if isInfinity(p) {
+		return q
+	}
+	if isInfinity(q) {
+		return p
+	}
+	num, den := new(big.Int), new(big.Int)
+	if p.X.Cmp(q.X) == 0 {
+		if sum := new(big.Int).Add(p.Y, q.Y); sum.Mod(sum, c.p).Sign() == 0 {
+			return infinity()
+		}
+		// λ = (3x²+a)/2y
+		num.Mul(p.X, p.X).Mul(num, big.NewInt(3)).Add(num, c.a)
+		den.Lsh(p.Y, 1)
+	} else {
+		// λ = (y₂-y₁)/(x₂-x₁)
+		num.Sub(q.Y, p.Y)
+		den.Sub(q.X, p.X)
+	}
+	den.Mod(den, c.p).ModInverse(den, c.p)
+	lambda := num.Mul(num, den)
+	lambda.Mod(lambda, c.p)
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, p.X).Sub(x, q.X).Mod(x, c.p)
+	y := new(big.Int).Sub(p.X, x)
+	y.Mul(y, lambda).Sub(y, p.Y).Mod(y, c.p)
+	return NativePoint{X: x, Y: y}
+}
+
+func (c curve) scalarMul(p NativePoint, s *big.Int) NativePoint {
+	e := new(big.Int).Mod(s, c.r)
+	res := infinity()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = c.add(res, res)
+		if e.Bit(i) == 1 {
+			res = c.add(res, p)
+		}
+	}
+	return res
+}
+
+func (c curve) msm(points []NativePoint, scalars []*big.Int) NativePoint {
+	res := infinity()
+	for i := range points {
+		res = c.add(res, c.scalarMul(points[i], scalars[i]))
+	}
+	return res
+}
+
+func (c curve) inner(a, b []*big.Int) *big.Int {
+	res := new(big.Int)
+	for i := range a {
+		res.Add(res, new(big.Int).Mul(a[i], b[i]))
+	}
+	return res.Mod(res, c.r)
+}
+
+func (c curve) addScalars(a, b *big.Int) *big.Int {
+	res := new(big.Int).Add(a, b)
+	return res.Mod(res, c.r)
+}
+
+func (c curve) mulScalars(a, b *big.Int) *big.Int {
+	res := new(big.Int).Mul(a, b)
+	return res.Mod(res, c.r)
+}
+
+// marshalPoint returns the coordinates of p split as in the circuit.
+func (c curve) marshalPoint(p NativePoint) []*big.Int {
+	return append(split(p.X), split(p.Y)...)
+}
+
+// marshalScalar returns the scalar split as in the circuit.
+func (c curve) marshalScalar(s *big.Int) []*big.Int {
+	return split(new(big.Int).Mod(s, c.r))
+}
+
+// split returns the 128 low bits and the high bits of x.
+func split(x *big.Int) []*big.Int {
+	lo := new(big.Int).And(x, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+	return []*big.Int{lo, new(big.Int).Rsh(x, 128)}
+}
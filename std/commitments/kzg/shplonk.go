@@ -0,0 +1,256 @@
+package kzg
+
+import (
+	"fmt"
+
+	shplonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377/shplonk"
+	shplonk_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381/shplonk"
+	shplonk_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315/shplonk"
+	shplonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254/shplonk"
+	shplonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761/shplonk"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// MultiPointOpeningProof is a shplonk batch opening proof of several
+// polynomials, each at its own set of points, as computed by the shplonk
+// package of the PLONK backend of the curve. ClaimedValues[i][j] is the value
+// of the polynomial i at its j-th point. Use [ValueOfMultiPointOpeningProof] to
+// initialize a witness from a native proof.
+type MultiPointOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	W, WPrime     G1El
+	ClaimedValues [][]emulated.Element[FR]
+}
+
+// ValueOfMultiPointOpeningProof initializes a shplonk batch opening proof
+// witness from a native proof. It returns an error if there is a mismatch
+// between the type parameters and the provided proof type.
+func ValueOfMultiPointOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT](proof any) (MultiPointOpeningProof[FR, G1El], error) {
+	var ret MultiPointOpeningProof[FR, G1El]
+	switch s := any(&ret).(type) {
+	case *MultiPointOpeningProof[sw_bn254.ScalarField, sw_bn254.G1Affine]:
+		tProof, ok := proof.(shplonk_bn254.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.W = sw_bn254.NewG1Affine(tProof.W)
+		s.WPrime = sw_bn254.NewG1Affine(tProof.WPrime)
+		s.ClaimedValues = make([][]emulated.Element[sw_bn254.ScalarField], len(tProof.ClaimedValues))
+		for i := range s.ClaimedValues {
+			s.ClaimedValues[i] = make([]emulated.Element[sw_bn254.ScalarField], len(tProof.ClaimedValues[i]))
+			for j := range s.ClaimedValues[i] {
+				s.ClaimedValues[i][j] = sw_bn254.NewScalar(tProof.ClaimedValues[i][j])
+			}
+		}
+	case *MultiPointOpeningProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine]:
+		tProof, ok := proof.(shplonk_bls12377.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.W = sw_bls12377.NewG1Affine(tProof.W)
+		s.WPrime = sw_bls12377.NewG1Affine(tProof.WPrime)
+		s.ClaimedValues = make([][]emulated.Element[sw_bls12377.ScalarField], len(tProof.ClaimedValues))
+		for i := range s.ClaimedValues {
+			s.ClaimedValues[i] = make([]emulated.Element[sw_bls12377.ScalarField], len(tProof.ClaimedValues[i]))
+			for j := range s.ClaimedValues[i] {
+				s.ClaimedValues[i][j] = sw_bls12377.NewScalar(tProof.ClaimedValues[i][j])
+			}
+		}
+	case *MultiPointOpeningProof[sw_bls12381.ScalarField, sw_bls12381.G1Affine]:
+		tProof, ok := proof.(shplonk_bls12381.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.W = sw_bls12381.NewG1Affine(tProof.W)
+		s.WPrime = sw_bls12381.NewG1Affine(tProof.WPrime)
+		s.ClaimedValues = make([][]emulated.Element[sw_bls12381.ScalarField], len(tProof.ClaimedValues))
+		for i := range s.ClaimedValues {
+			s.ClaimedValues[i] = make([]emulated.Element[sw_bls12381.ScalarField], len(tProof.ClaimedValues[i]))
+			for j := range s.ClaimedValues[i] {
+				s.ClaimedValues[i][j] = sw_bls12381.NewScalar(tProof.ClaimedValues[i][j])
+			}
+		}
+	case *MultiPointOpeningProof[sw_bw6761.ScalarField, sw_bw6761.G1Affine]:
+		tProof, ok := proof.(shplonk_bw6761.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.W = sw_bw6761.NewG1Affine(tProof.W)
+		s.WPrime = sw_bw6761.NewG1Affine(tProof.WPrime)
+		s.ClaimedValues = make([][]emulated.Element[sw_bw6761.ScalarField], len(tProof.ClaimedValues))
+		for i := range s.ClaimedValues {
+			s.ClaimedValues[i] = make([]emulated.Element[sw_bw6761.ScalarField], len(tProof.ClaimedValues[i]))
+			for j := range s.ClaimedValues[i] {
+				s.ClaimedValues[i][j] = sw_bw6761.NewScalar(tProof.ClaimedValues[i][j])
+			}
+		}
+	case *MultiPointOpeningProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine]:
+		tProof, ok := proof.(shplonk_bls24315.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.W = sw_bls24315.NewG1Affine(tProof.W)
+		s.WPrime = sw_bls24315.NewG1Affine(tProof.WPrime)
+		s.ClaimedValues = make([][]emulated.Element[sw_bls24315.ScalarField], len(tProof.ClaimedValues))
+		for i := range s.ClaimedValues {
+			s.ClaimedValues[i] = make([]emulated.Element[sw_bls24315.ScalarField], len(tProof.ClaimedValues[i]))
+			for j := range s.ClaimedValues[i] {
+				s.ClaimedValues[i][j] = sw_bls24315.NewScalar(tProof.ClaimedValues[i][j])
+			}
+		}
+	default:
+		return ret, fmt.Errorf("unknown type parametrization")
+	}
+	return ret, nil
+}
+
+// BatchVerifyMultiPointSets verifies a shplonk batch opening proof of the
+// polynomials committed to in digests, where the polynomial i is opened at the
+// points points[k] for k in sets[i]. The points must be distinct: they are the
+// union of the sets of points of the native prover, in any order. It costs a
+// multi-scalar multiplication and a pairing check, independently of the number
+// of points.
+//
+// The challenges are derived with the transcript of [recursion.NewTranscript],
+// so the native proof must be computed with the hash of [recursion.NewShort].
+// The elements of dataTranscript are bound as their native encoding.
+func (v *Verifier[FR, G1El, G2El, GTEl]) BatchVerifyMultiPointSets(digests []Commitment[G1El], proof MultiPointOpeningProof[FR, G1El], points []emulated.Element[FR], sets [][]int, vk VerifyingKey[G1El, G2El], dataTranscript ...emulated.Element[FR]) error {
+	if len(digests) == 0 {
+		return fmt.Errorf("number of digests should be nonzero")
+	}
+	if len(digests) != len(sets) || len(digests) != len(proof.ClaimedValues) {
+		return fmt.Errorf("number of opening sets is not the same as the number of polynomials")
+	}
+	for i := range sets {
+		if len(sets[i]) == 0 {
+			return fmt.Errorf("opening set %d is empty", i)
+		}
+		if len(sets[i]) != len(proof.ClaimedValues[i]) {
+			return fmt.Errorf("number of claimed values of polynomial %d doesn't match its opening set", i)
+		}
+		for _, k := range sets[i] {
+			if k < 0 || k >= len(points) {
+				return fmt.Errorf("opening set %d: point %d out of range", i, k)
+			}
+		}
+	}
+
+	gamma, z, err := v.deriveShplonkChallenges(digests, proof, points, sets, dataTranscript...)
+	if err != nil {
+		return fmt.Errorf("derive challenges: %w", err)
+	}
+
+	// zMinus[k] = z - points[k], and Z_T(z) = ∏ₖ (z - points[k])
+	zMinus := make([]*emulated.Element[FR], len(points))
+	zT := v.scalarApi.One()
+	for k := range points {
+		zMinus[k] = v.scalarApi.Sub(z, &points[k])
+		zT = v.scalarApi.Mul(zT, zMinus[k])
+	}
+
+	// [L] + z[W'] = Σᵢ γⁱ Z_{T\Sᵢ}(z)[fᵢ] - (Σᵢ γⁱ Z_{T\Sᵢ}(z)rᵢ(z))[1] - Z_T(z)[W] + z[W']
+	bases := make([]*G1El, len(digests)+3)
+	scalars := make([]*emulated.Element[FR], len(digests)+3)
+	gammaI := v.scalarApi.One()
+	constant := v.scalarApi.Zero()
+	for i := range digests {
+		zSi := v.scalarApi.One()
+		for _, k := range sets[i] {
+			zSi = v.scalarApi.Mul(zSi, zMinus[k])
+		}
+		scalars[i] = v.scalarApi.Mul(gammaI, v.scalarApi.Div(zT, zSi))
+		bases[i] = &digests[i].G1El
+		ri := v.evalInterpolation(points, sets[i], proof.ClaimedValues[i], zMinus)
+		constant = v.scalarApi.Add(constant, v.scalarApi.Mul(scalars[i], ri))
+		gammaI = v.scalarApi.Mul(gammaI, gamma)
+	}
+	n := len(digests)
+	bases[n], scalars[n] = &vk.G1, v.scalarApi.Neg(constant)
+	bases[n+1], scalars[n+1] = &proof.W, v.scalarApi.Neg(zT)
+	bases[n+2], scalars[n+2] = &proof.WPrime, z
+	lhs, err := v.curve.MultiScalarMul(bases, scalars)
+	if err != nil {
+		return fmt.Errorf("multi scalar mul: %w", err)
+	}
+
+	// e([L] + z[W'], [1]) = e([W'], [τ])
+	if err := v.pairing.PairingCheck(
+		[]*G1El{lhs, v.curve.Neg(&proof.WPrime)},
+		[]*G2El{&vk.G2[0], &vk.G2[1]},
+	); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// evalInterpolation returns r(z), where r is the polynomial of degree less
+// than len(set) such that r(points[set[j]]) = values[j], and zMinus[k] = z -
+// points[k].
+func (v *Verifier[FR, G1El, G2El, GTEl]) evalInterpolation(points []emulated.Element[FR], set []int, values []emulated.Element[FR], zMinus []*emulated.Element[FR]) *emulated.Element[FR] {
+	res := v.scalarApi.Zero()
+	for j := range set {
+		// Lagrange basis polynomial at z: ∏ₗ≠ⱼ(z-sₗ)/(sⱼ-sₗ)
+		numerator, denominator := v.scalarApi.One(), v.scalarApi.One()
+		for l := range set {
+			if l == j {
+				continue
+			}
+			numerator = v.scalarApi.Mul(numerator, zMinus[set[l]])
+			denominator = v.scalarApi.Mul(denominator, v.scalarApi.Sub(&points[set[j]], &points[set[l]]))
+		}
+		term := v.scalarApi.Mul(&values[j], v.scalarApi.Div(numerator, denominator))
+		res = v.scalarApi.Add(res, term)
+	}
+	return res
+}
+
+// deriveShplonkChallenges derives the challenges γ and z of the shplonk
+// verifier, as the native verifier.
+func (v *Verifier[FR, G1El, G2El, GTEl]) deriveShplonkChallenges(digests []Commitment[G1El], proof MultiPointOpeningProof[FR, G1El], points []emulated.Element[FR], sets [][]int, dataTranscript ...emulated.Element[FR]) (gamma, z *emulated.Element[FR], err error) {
+	var fr FR
+	fs, err := recursion.NewTranscript(v.api, fr.Modulus(), []string{"gamma", "z"})
+	if err != nil {
+		return nil, nil, fmt.Errorf("new transcript: %w", err)
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", v.curve.MarshalG1(digests[i].G1El)); err != nil {
+			return nil, nil, fmt.Errorf("bind %d-th commitment: %w", i, err)
+		}
+	}
+	for i := range sets {
+		for j, k := range sets[i] {
+			if err := fs.Bind("gamma", v.curve.MarshalScalar(points[k])); err != nil {
+				return nil, nil, fmt.Errorf("bind point %d of polynomial %d: %w", j, i, err)
+			}
+			if err := fs.Bind("gamma", v.curve.MarshalScalar(proof.ClaimedValues[i][j])); err != nil {
+				return nil, nil, fmt.Errorf("bind claimed value %d of polynomial %d: %w", j, i, err)
+			}
+		}
+	}
+	for i := range dataTranscript {
+		if err := fs.Bind("gamma", v.curve.MarshalScalar(dataTranscript[i])); err != nil {
+			return nil, nil, fmt.Errorf("bind %d-ith data transcript: %w", i, err)
+		}
+	}
+	gammaV, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return nil, nil, fmt.Errorf("compute challenge gamma: %w", err)
+	}
+	if err := fs.Bind("z", v.curve.MarshalG1(proof.W)); err != nil {
+		return nil, nil, fmt.Errorf("bind W: %w", err)
+	}
+	zV, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return nil, nil, fmt.Errorf("compute challenge z: %w", err)
+	}
+	gamma = v.scalarApi.FromBits(bits.ToBinary(v.api, gammaV, bits.WithNbDigits(fr.Modulus().BitLen()))...)
+	z = v.scalarApi.FromBits(bits.ToBinary(v.api, zV, bits.WithNbDigits(fr.Modulus().BitLen()))...)
+	return gamma, z, nil
+}
@@ -0,0 +1,115 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	shplonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377/shplonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/test"
+)
+
+type ShplonkVerificationCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GTEl algebra.GtElementT] struct {
+	Vk      VerifyingKey[G1El, G2El]
+	Digests []Commitment[G1El]
+	Proof   MultiPointOpeningProof[FR, G1El]
+	Points  []emulated.Element[FR]
+	sets    [][]int
+}
+
+func (c *ShplonkVerificationCircuit[FR, G1El, G2El, GTEl]) Define(api frontend.API) error {
+	verifier, err := NewVerifier[FR, G1El, G2El, GTEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	if err := verifier.BatchVerifyMultiPointSets(c.Digests, c.Proof, c.Points, c.sets, c.Vk); err != nil {
+		return fmt.Errorf("assert proof: %w", err)
+	}
+	return nil
+}
+
+func TestShplonkVerificationTwoChain(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	srs, err := kzg_bls12377.NewSRS(kzgSize, alpha)
+	assert.NoError(err)
+
+	// the polynomials are opened at subsets of the points, as the PLONK
+	// polynomials at ζ and Z at ζ and ωζ
+	var points [3]fr_bls12377.Element
+	for i := range points {
+		points[i].SetRandom()
+	}
+	sets := [][]int{{0, 1}, {0}, {2}, {1, 2, 0}}
+	polynomials := make([][]fr_bls12377.Element, len(sets))
+	digests := make([]kzg_bls12377.Digest, len(sets))
+	nativePoints := make([][]fr_bls12377.Element, len(sets))
+	for i := range sets {
+		polynomials[i] = make([]fr_bls12377.Element, polynomialSize)
+		for j := range polynomials[i] {
+			polynomials[i][j].SetRandom()
+		}
+		digests[i], err = kzg_bls12377.Commit(polynomials[i], srs.Pk)
+		assert.NoError(err)
+		for _, k := range sets[i] {
+			nativePoints[i] = append(nativePoints[i], points[k])
+		}
+	}
+	h, err := recursion.NewShort(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	proof, err := shplonk_bls12377.BatchOpen(polynomials, digests, nativePoints, h, srs.Pk)
+	assert.NoError(err)
+	h.Reset()
+	assert.NoError(shplonk_bls12377.BatchVerify(&proof, digests, nativePoints, h, srs.Vk))
+
+	newAssignment := func(proof shplonk_bls12377.OpeningProof) *ShplonkVerificationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT] {
+		var assignment ShplonkVerificationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]
+		assignment.Vk, err = ValueOfVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine](srs.Vk)
+		assert.NoError(err)
+		for i := range digests {
+			wCmt, err := ValueOfCommitment[sw_bls12377.G1Affine](digests[i])
+			assert.NoError(err)
+			assignment.Digests = append(assignment.Digests, wCmt)
+		}
+		assignment.Proof, err = ValueOfMultiPointOpeningProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine](proof)
+		assert.NoError(err)
+		for i := range points {
+			wPt, err := ValueOfScalar[sw_bls12377.ScalarField](points[i])
+			assert.NoError(err)
+			assignment.Points = append(assignment.Points, wPt)
+		}
+		return &assignment
+	}
+
+	// a wrong claimed value
+	wrongProof := proof
+	wrongProof.ClaimedValues = make([][]fr_bls12377.Element, len(proof.ClaimedValues))
+	for i := range proof.ClaimedValues {
+		wrongProof.ClaimedValues[i] = append([]fr_bls12377.Element{}, proof.ClaimedValues[i]...)
+	}
+	wrongProof.ClaimedValues[3][1].SetOne()
+
+	circuit := ShplonkVerificationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Digests: make([]Commitment[sw_bls12377.G1Affine], len(sets)),
+		Points:  make([]emulated.Element[sw_bls12377.ScalarField], len(points)),
+		sets:    sets,
+	}
+	circuit.Proof.ClaimedValues = make([][]emulated.Element[sw_bls12377.ScalarField], len(sets))
+	for i := range sets {
+		circuit.Proof.ClaimedValues[i] = make([]emulated.Element[sw_bls12377.ScalarField], len(sets[i]))
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(newAssignment(proof)),
+		test.WithInvalidAssignment(newAssignment(wrongProof)),
+		test.WithCurves(ecc.BW6_761), test.NoFuzzing())
+}
@@ -0,0 +1,251 @@
+// Package ligero implements in-circuit verification of openings of the
+// Ligero polynomial commitment scheme.
+//
+// The scheme is transparent, it doesn't need a trusted setup, and relies only
+// on a hash function: the polynomial is arranged in a matrix whose rows are
+// encoded with a Reed-Solomon code, and the commitment is the root of a Merkle
+// tree over the columns of the encoded matrix. The commitments and openings
+// are computed natively with the ligero package of the curve whose scalar
+// field is the native field of the circuit, for example
+// github.com/consensys/gnark/backend/commitments/bn254/ligero, with the MiMC hash
+// function.
+package ligero
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	ligero_bls12377 "github.com/consensys/gnark/backend/commitments/bls12-377/ligero"
+	ligero_bls12381 "github.com/consensys/gnark/backend/commitments/bls12-381/ligero"
+	ligero_bls24315 "github.com/consensys/gnark/backend/commitments/bls24-315/ligero"
+	ligero_bls24317 "github.com/consensys/gnark/backend/commitments/bls24-317/ligero"
+	ligero_bn254 "github.com/consensys/gnark/backend/commitments/bn254/ligero"
+	ligero_bw6633 "github.com/consensys/gnark/backend/commitments/bw6-633/ligero"
+	ligero_bw6761 "github.com/consensys/gnark/backend/commitments/bw6-761/ligero"
+	"github.com/consensys/gnark/frontend"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Params are the public parameters of the commitment scheme, as in the native
+// package. Generator is the generator of the evaluation domain of the code,
+// returned by the Generator method of the native parameters.
+type Params struct {
+	NbRows, NbColumns int
+	Rho               int
+	NbQueries         int
+	Generator         big.Int
+}
+
+// OpeningProof is an opening proof of a committed polynomial. Use
+// [ValueOfOpeningProof] to initialize a witness from a native proof and
+// [PlaceholderOpeningProof] to define the circuit.
+type OpeningProof struct {
+	ClaimedValue              frontend.Variable
+	CombinedRow, ProximityRow []frontend.Variable
+	Columns                   [][]frontend.Variable
+	Paths                     [][]frontend.Variable
+}
+
+// PlaceholderOpeningProof returns a placeholder opening proof for the
+// parameters, for circuit compilation.
+func PlaceholderOpeningProof(params Params) OpeningProof {
+	depth := bits.TrailingZeros(uint(params.NbColumns * params.Rho))
+	proof := OpeningProof{
+		CombinedRow:  make([]frontend.Variable, params.NbColumns),
+		ProximityRow: make([]frontend.Variable, params.NbColumns),
+		Columns:      make([][]frontend.Variable, params.NbQueries),
+		Paths:        make([][]frontend.Variable, params.NbQueries),
+	}
+	for q := range proof.Columns {
+		proof.Columns[q] = make([]frontend.Variable, params.NbRows)
+		proof.Paths[q] = make([]frontend.Variable, depth)
+	}
+	return proof
+}
+
+// ValueOfOpeningProof initializes an opening proof witness from a native
+// opening proof.
+func ValueOfOpeningProof(proof any) (OpeningProof, error) {
+	switch p := proof.(type) {
+	case ligero_bn254.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bls12377.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bls12381.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bls24315.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bls24317.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bw6633.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	case ligero_bw6761.OpeningProof:
+		return valueOf(p.ClaimedValue, p.CombinedRow, p.ProximityRow, p.Columns, p.Paths), nil
+	default:
+		return OpeningProof{}, fmt.Errorf("unknown opening proof type %T", proof)
+	}
+}
+
+type element[E any] interface {
+	*E
+	BigInt(*big.Int) *big.Int
+}
+
+func valueOf[E any, PE element[E]](claimedValue E, combinedRow, proximityRow []E, columns [][]E, paths [][][]byte) OpeningProof {
+	toVariables := func(s []E) []frontend.Variable {
+		res := make([]frontend.Variable, len(s))
+		for i := range s {
+			res[i] = PE(&s[i]).BigInt(new(big.Int))
+		}
+		return res
+	}
+	proof := OpeningProof{
+		ClaimedValue: PE(&claimedValue).BigInt(new(big.Int)),
+		CombinedRow:  toVariables(combinedRow),
+		ProximityRow: toVariables(proximityRow),
+		Columns:      make([][]frontend.Variable, len(columns)),
+		Paths:        make([][]frontend.Variable, len(paths)),
+	}
+	for q := range columns {
+		proof.Columns[q] = toVariables(columns[q])
+		proof.Paths[q] = make([]frontend.Variable, len(paths[q]))
+		for i := range paths[q] {
+			proof.Paths[q][i] = new(big.Int).SetBytes(paths[q][i])
+		}
+	}
+	return proof
+}
+
+// Verifier verifies opening proofs in a circuit.
+type Verifier struct {
+	api    frontend.API
+	params Params
+	h      hash.FieldHasher
+}
+
+// NewVerifier returns a verifier of opening proofs with the parameters. h
+// must be the in-circuit version of the hash function used natively.
+func NewVerifier(api frontend.API, params Params, h hash.FieldHasher) (*Verifier, error) {
+	if params.NbRows <= 0 || params.NbColumns <= 0 || params.NbQueries <= 0 {
+		return nil, errors.New("number of rows, columns and queries must be positive")
+	}
+	if bits.OnesCount(uint(params.NbColumns)) != 1 || bits.OnesCount(uint(params.Rho)) != 1 || params.Rho < 2 {
+		return nil, errors.New("number of columns and Rho must be powers of 2, with Rho ≥ 2")
+	}
+	return &Verifier{api: api, params: params, h: h}, nil
+}
+
+// CheckOpeningProof asserts that the polynomial committed to in root
+// evaluates to proof.ClaimedValue at z. dataTranscript must be the data bound
+// to the transcript by the prover.
+func (v *Verifier) CheckOpeningProof(root frontend.Variable, proof OpeningProof, z frontend.Variable, dataTranscript ...frontend.Variable) error {
+	api, params := v.api, v.params
+	size := params.NbColumns * params.Rho
+	depth := bits.TrailingZeros(uint(size))
+	if len(proof.CombinedRow) != params.NbColumns || len(proof.ProximityRow) != params.NbColumns ||
+		len(proof.Columns) != params.NbQueries || len(proof.Paths) != params.NbQueries {
+		return errors.New("opening proof doesn't match the parameters")
+	}
+	for q := range proof.Columns {
+		if len(proof.Columns[q]) != params.NbRows || len(proof.Paths[q]) != depth {
+			return errors.New("opening proof doesn't match the parameters")
+		}
+	}
+
+	names := make([]string, params.NbQueries+1)
+	names[0] = "r"
+	for q := 1; q < len(names); q++ {
+		names[q] = "query" + strconv.Itoa(q-1)
+	}
+	fs := fiatshamir.NewTranscript(api, v.h, names)
+	if err := fs.Bind("r", []frontend.Variable{root, z}); err != nil {
+		return err
+	}
+	if err := fs.Bind("r", dataTranscript); err != nil {
+		return err
+	}
+	r, err := fs.ComputeChallenge("r")
+	if err != nil {
+		return err
+	}
+
+	// the evaluation is aᵀMb with b = (1, z, …, zᵐ⁻¹), a = (1, zᵐ, z²ᵐ, …)
+	b := powers(api, z, params.NbColumns)
+	a := powers(api, api.Mul(b[params.NbColumns-1], z), params.NbRows)
+	api.AssertIsEqual(innerProduct(api, proof.CombinedRow, b), proof.ClaimedValue)
+
+	if err := fs.Bind("query0", proof.CombinedRow); err != nil {
+		return err
+	}
+	if err := fs.Bind("query0", proof.ProximityRow); err != nil {
+		return err
+	}
+
+	// g²ⁱ, to compute g^index from the bits of the index
+	generatorPowers := make([]big.Int, depth)
+	generatorPowers[0].Set(&params.Generator)
+	for i := 1; i < depth; i++ {
+		generatorPowers[i].Mul(&generatorPowers[i-1], &generatorPowers[i-1]).Mod(&generatorPowers[i], api.Compiler().Field())
+	}
+
+	rPowers := powers(api, r, params.NbRows)
+	for q := 0; q < params.NbQueries; q++ {
+		c, err := fs.ComputeChallenge(names[q+1])
+		if err != nil {
+			return err
+		}
+		index := api.ToBinary(c)[:depth]
+
+		// Merkle path of the column
+		v.h.Reset()
+		v.h.Write(proof.Columns[q]...)
+		node := v.h.Sum()
+		for level, sibling := range proof.Paths[q] {
+			left := api.Select(index[level], sibling, node)
+			right := api.Select(index[level], node, sibling)
+			v.h.Reset()
+			v.h.Write(left, right)
+			node = v.h.Sum()
+		}
+		api.AssertIsEqual(node, root)
+
+		// the encoded rows at g^index are the combinations of the column
+		var x frontend.Variable = 1
+		for i := range index {
+			x = api.Mul(x, api.Select(index[i], &generatorPowers[i], 1))
+		}
+		api.AssertIsEqual(innerProduct(api, a, proof.Columns[q]), evaluate(api, proof.CombinedRow, x))
+		api.AssertIsEqual(innerProduct(api, rPowers, proof.Columns[q]), evaluate(api, proof.ProximityRow, x))
+	}
+	return nil
+}
+
+func powers(api frontend.API, x frontend.Variable, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	res[0] = 1
+	for i := 1; i < n; i++ {
+		res[i] = api.Mul(res[i-1], x)
+	}
+	return res
+}
+
+func innerProduct(api frontend.API, a, b []frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := range a {
+		res = api.Add(res, api.Mul(a[i], b[i]))
+	}
+	return res
+}
+
+// evaluate evaluates the polynomial p, in canonical basis, at x.
+func evaluate(api frontend.API, p []frontend.Variable, x frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := len(p) - 1; i >= 0; i-- {
+		res = api.Add(api.Mul(res, x), p[i])
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package ligero
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	ligero_bn254 "github.com/consensys/gnark/backend/commitments/bn254/ligero"
+	"github.com/consensys/gnark/frontend"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type ligeroCircuit struct {
+	params Params
+
+	Root  frontend.Variable
+	Proof OpeningProof
+	Point frontend.Variable
+}
+
+func (c *ligeroCircuit) Define(api frontend.API) error {
+	h, err := stdmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	verifier, err := NewVerifier(api, c.params, &h)
+	if err != nil {
+		return err
+	}
+	return verifier.CheckOpeningProof(c.Root, c.Proof, c.Point)
+}
+
+func TestCheckOpeningProof(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	nativeParams := ligero_bn254.Params{NbRows: 4, NbColumns: 8, Rho: 2, NbQueries: 6}
+	p := make([]fr.Element, 30)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	commitment, err := ligero_bn254.Commit(p, nativeParams, mimc.NewMiMC())
+	assert.NoError(err)
+	var z fr.Element
+	z.SetRandom()
+	proof, err := commitment.Open(z, mimc.NewMiMC())
+	assert.NoError(err)
+	assert.NoError(ligero_bn254.Verify(commitment.Root, &proof, z, nativeParams, mimc.NewMiMC()))
+
+	generator, err := nativeParams.Generator()
+	assert.NoError(err)
+	params := Params{NbRows: nativeParams.NbRows, NbColumns: nativeParams.NbColumns, Rho: nativeParams.Rho, NbQueries: nativeParams.NbQueries}
+	generator.BigInt(&params.Generator)
+
+	wProof, err := ValueOfOpeningProof(proof)
+	assert.NoError(err)
+	assignment := ligeroCircuit{Root: commitment.Root, Proof: wProof, Point: z.String()}
+	invalid := ligeroCircuit{Root: commitment.Root, Proof: wProof, Point: 42}
+	circuit := ligeroCircuit{params: params, Proof: PlaceholderOpeningProof(params)}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&assignment), test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+}
@@ -117,6 +117,51 @@ func ValueOfVerifyingKeyFixed[G2El algebra.G2ElementT](vk any) (VerifyingKey[G2E
 	return ret, nil
 }
 
+// ValueOfProvingKey returns a ProvingKey from a native Pedersen proving key, to
+// verify openings in-circuit. It returns an error if the input does not match
+// the expected type.
+func ValueOfProvingKey[G1El algebra.G1ElementT](pk any) (ProvingKey[G1El], error) {
+	var basis, basisExpSigma []any
+	switch tPk := pk.(type) {
+	case *ped_bls12377.ProvingKey:
+		basis, basisExpSigma = toAny(tPk.Basis), toAny(tPk.BasisExpSigma)
+	case *ped_bls12381.ProvingKey:
+		basis, basisExpSigma = toAny(tPk.Basis), toAny(tPk.BasisExpSigma)
+	case *ped_bls24315.ProvingKey:
+		basis, basisExpSigma = toAny(tPk.Basis), toAny(tPk.BasisExpSigma)
+	case *ped_bw6761.ProvingKey:
+		basis, basisExpSigma = toAny(tPk.Basis), toAny(tPk.BasisExpSigma)
+	case *ped_bn254.ProvingKey:
+		basis, basisExpSigma = toAny(tPk.Basis), toAny(tPk.BasisExpSigma)
+	default:
+		return ProvingKey[G1El]{}, fmt.Errorf("unknown proving key type: %T", pk)
+	}
+	ret := ProvingKey[G1El]{
+		Basis:         make([]G1El, len(basis)),
+		BasisExpSigma: make([]G1El, len(basisExpSigma)),
+	}
+	var err error
+	for i := range basis {
+		if ret.Basis[i], err = valueOfG1El[G1El](basis[i]); err != nil {
+			return ret, err
+		}
+	}
+	for i := range basisExpSigma {
+		if ret.BasisExpSigma[i], err = valueOfG1El[G1El](basisExpSigma[i]); err != nil {
+			return ret, err
+		}
+	}
+	return ret, nil
+}
+
+func toAny[T any](s []T) []any {
+	ret := make([]any, len(s))
+	for i := range s {
+		ret[i] = s[i]
+	}
+	return ret
+}
+
 // ValueOfCommitment returns a Commitment from a native Pedersen commitment. It
 // returns an error if the input does not match the expected type.
 func ValueOfCommitment[G1El algebra.G1ElementT](cmt any) (Commitment[G1El], error) {
@@ -6,7 +6,10 @@ import (
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/hash/sha2"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
 )
 
 // Commitment is a Pedersen commitment to a vector.
@@ -19,6 +22,13 @@ type KnowledgeProof[G1El algebra.G1ElementT] struct {
 	G1El G1El
 }
 
+// ProvingKey is the basis of Pedersen vector commitments, needed to verify
+// their openings.
+type ProvingKey[G1El algebra.G1ElementT] struct {
+	Basis         []G1El
+	BasisExpSigma []G1El // σ[Basis] for toxic σ
+}
+
 // VerifyingKey is a verifying key for Pedersen vector commitments.
 type VerifyingKey[G2El algebra.G2ElementT] struct {
 	G             G2El
@@ -27,21 +37,34 @@ type VerifyingKey[G2El algebra.G2ElementT] struct {
 
 // Verifier verifies the knowledge proofs for a Pedersen commitments
 type Verifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
-	pairing algebra.Pairing[G1El, G2El, GtEl]
+	api       frontend.API
+	scalarApi *emulated.Field[FR]
+	curve     algebra.Curve[FR, G1El]
+	pairing   algebra.Pairing[G1El, G2El, GtEl]
 }
 
 // NewVerifier returns a new verifier for Pedersen commitments.
 func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API) (*Verifier[FR, G1El, G2El, GtEl], error) {
+	curve, err := algebra.GetCurve[FR, G1El](api)
+	if err != nil {
+		return nil, fmt.Errorf("get curve: %w", err)
+	}
+	scalarApi, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
 	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
 	if err != nil {
 		return nil, fmt.Errorf("get pairing: %w", err)
 	}
-	return &Verifier[FR, G1El, G2El, GtEl]{pairing: pairing}, nil
+	return &Verifier[FR, G1El, G2El, GtEl]{api: api, scalarApi: scalarApi, curve: curve, pairing: pairing}, nil
 }
 
-// FoldCommitments folds the given commitments into a single commitment for efficient verification.
-//
-// Currently the function panics as folding is not implemented yet.
+// FoldCommitments folds the given commitments into a single commitment for
+// efficient verification, as a random linear combination ∑ᵢ rⁱCᵢ. The
+// challenge r is derived from the auxiliary transcript with SHA2-256, as in
+// the native FoldCommitments of gnark-crypto, so that the knowledge proofs of
+// multiple commitments computed natively can be verified in-circuit.
 func (v *Verifier[FR, G1El, G2El, GtEl]) FoldCommitments(commitments []Commitment[G1El], auxTranscript ...*emulated.Element[FR]) (Commitment[G1El], error) {
 	if len(commitments) == 0 {
 		return Commitment[G1El]{}, fmt.Errorf("number of commitments must be at least 1")
@@ -49,7 +72,60 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) FoldCommitments(commitments []Commitmen
 	if len(commitments) == 1 { // no need to fold
 		return commitments[0], nil
 	}
-	panic("folding not implemented yet")
+	r, err := v.deriveChallenge(auxTranscript)
+	if err != nil {
+		return Commitment[G1El]{}, fmt.Errorf("derive challenge: %w", err)
+	}
+	points := make([]*G1El, len(commitments)-1)
+	scalars := make([]*emulated.Element[FR], len(commitments)-1)
+	for i := range points {
+		points[i] = &commitments[i+1].G1El
+	}
+	scalars[0] = r
+	for i := 1; i < len(scalars); i++ {
+		scalars[i] = v.scalarApi.Mul(scalars[i-1], r)
+	}
+	folded, err := v.curve.MultiScalarMul(points, scalars)
+	if err != nil {
+		return Commitment[G1El]{}, fmt.Errorf("multi scalar mul: %w", err)
+	}
+	return Commitment[G1El]{G1El: *v.curve.Add(folded, &commitments[0].G1El)}, nil
+}
+
+// deriveChallenge computes the challenge "r" of a SHA2-256 Fiat-Shamir
+// transcript binding the marshalled scalars, reduced in the scalar field.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveChallenge(transcript []*emulated.Element[FR]) (*emulated.Element[FR], error) {
+	h, err := sha2.New(v.api)
+	if err != nil {
+		return nil, err
+	}
+	bf, err := uints.New[uints.U32](v.api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(uints.NewU8Array([]byte("r")))
+	for i := range transcript {
+		// big-endian bits, most significant bit of each byte first
+		b := v.curve.MarshalScalar(*transcript[i])
+		for j := 0; j < len(b); j += 8 {
+			byteBits := make([]frontend.Variable, 8)
+			for k := range byteBits {
+				byteBits[k] = b[j+7-k]
+			}
+			h.Write([]uints.U8{bf.ByteValueOf(v.api.FromBinary(byteBits...))})
+		}
+	}
+	digest := h.Sum()
+
+	// the digest is a big-endian integer, FromBits expects the least
+	// significant bit first.
+	digestBits := make([]frontend.Variable, 0, 8*len(digest))
+	for i := len(digest) - 1; i >= 0; i-- {
+		digestBits = append(digestBits, v.api.ToBinary(digest[i].Val, 8)...)
+	}
+	// the digest may be larger than the modulus and Reduce omits the elements
+	// without overflow, so we reduce it with a multiplication.
+	return v.scalarApi.Mul(v.scalarApi.FromBits(digestBits...), v.scalarApi.One()), nil
 }
 
 // AssertCommitment verifies the given commitment and knowledge proof against the given verifying key.
@@ -67,4 +143,49 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertCommitment(commitment Commitment[
 	return nil
 }
 
+// AssertOpening asserts that the commitment opens to the given values, that is
+// that it is ∑ᵢ vᵢGᵢ for the basis Gᵢ of the proving key. The values may be
+// zero, the multi-scalar multiplication uses complete arithmetic.
+func (v *Verifier[FR, G1El, G2El, GtEl]) AssertOpening(commitment Commitment[G1El], values []*emulated.Element[FR], pk ProvingKey[G1El]) error {
+	expected, err := v.commit(pk.Basis, values)
+	if err != nil {
+		return err
+	}
+	v.curve.AssertIsEqual(&commitment.G1El, expected)
+	return nil
+}
+
+// AssertKnowledgeProofOpening asserts that the knowledge proof is the one of
+// the commitment to the given values, that is ∑ᵢ vᵢσGᵢ. Together with
+// [Verifier.AssertOpening], it checks the knowledge proof without the
+// pairing of [Verifier.AssertCommitment], when the values are known in the
+// circuit.
+func (v *Verifier[FR, G1El, G2El, GtEl]) AssertKnowledgeProofOpening(knowledgeProof KnowledgeProof[G1El], values []*emulated.Element[FR], pk ProvingKey[G1El]) error {
+	expected, err := v.commit(pk.BasisExpSigma, values)
+	if err != nil {
+		return err
+	}
+	v.curve.AssertIsEqual(&knowledgeProof.G1El, expected)
+	return nil
+}
+
+// commit returns ∑ᵢ vᵢBᵢ.
+func (v *Verifier[FR, G1El, G2El, GtEl]) commit(basis []G1El, values []*emulated.Element[FR]) (*G1El, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("number of values must be at least 1")
+	}
+	if len(values) != len(basis) {
+		return nil, fmt.Errorf("got %d values for a basis of size %d", len(values), len(basis))
+	}
+	points := make([]*G1El, len(basis))
+	for i := range basis {
+		points[i] = &basis[i]
+	}
+	res, err := v.curve.MultiScalarMul(points, values, algopts.WithCompleteArithmetic())
+	if err != nil {
+		return nil, fmt.Errorf("multi scalar mul: %w", err)
+	}
+	return res, nil
+}
+
 // TODO: add asserting with switches between different keys
@@ -0,0 +1,138 @@
+package pedersen
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	ped_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/pedersen"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	ped_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/pedersen"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type openingCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Commitment     Commitment[G1El]
+	KnowledgeProof KnowledgeProof[G1El]
+	Values         []emulated.Element[FR]
+	ProvingKey     ProvingKey[G1El]
+}
+
+func (c *openingCircuit[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	v, err := NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	values := make([]*emulated.Element[FR], len(c.Values))
+	for i := range c.Values {
+		values[i] = &c.Values[i]
+	}
+	if err := v.AssertOpening(c.Commitment, values, c.ProvingKey); err != nil {
+		return fmt.Errorf("assert opening: %w", err)
+	}
+	if err := v.AssertKnowledgeProofOpening(c.KnowledgeProof, values, c.ProvingKey); err != nil {
+		return fmt.Errorf("assert knowledge proof opening: %w", err)
+	}
+	return nil
+}
+
+func TestOpeningBLS12377(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbValues = 3
+	basis := make([]bls12377.G1Affine, nbValues)
+	for i := range basis {
+		var s fr_bls12377.Element
+		s.SetRandom()
+		basis[i].ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	}
+	pks, _, err := ped_bls12377.Setup(basis)
+	assert.NoError(err)
+	values := make([]fr_bls12377.Element, nbValues)
+	values[0].SetRandom()
+	values[1].SetRandom()
+	// values[2] is zero, the opening must handle it
+	cmt, err := pks[0].Commit(values)
+	assert.NoError(err)
+	pok, err := pks[0].ProveKnowledge(values)
+	assert.NoError(err)
+
+	pk, err := ValueOfProvingKey[sw_bls12377.G1Affine](&pks[0])
+	assert.NoError(err)
+	circuit := openingCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Values:     make([]emulated.Element[sw_bls12377.ScalarField], nbValues),
+		ProvingKey: ProvingKey[sw_bls12377.G1Affine]{Basis: make([]sw_bls12377.G1Affine, nbValues), BasisExpSigma: make([]sw_bls12377.G1Affine, nbValues)},
+	}
+	newAssignment := func(cmt, pok bls12377.G1Affine) *openingCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT] {
+		a := openingCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+			Values:     make([]emulated.Element[sw_bls12377.ScalarField], nbValues),
+			ProvingKey: pk,
+		}
+		a.Commitment, err = ValueOfCommitment[sw_bls12377.G1Affine](cmt)
+		assert.NoError(err)
+		a.KnowledgeProof, err = ValueOfKnowledgeProof[sw_bls12377.G1Affine](pok)
+		assert.NoError(err)
+		for i := range values {
+			a.Values[i] = emulated.ValueOf[sw_bls12377.ScalarField](values[i])
+		}
+		return &a
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(newAssignment(cmt, pok)),
+		test.WithInvalidAssignment(newAssignment(pok, pok)),
+		test.WithInvalidAssignment(newAssignment(cmt, cmt)),
+		test.WithCurves(ecc.BW6_761), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+func TestOpeningBN254(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbValues = 2
+	basis := make([]bn254.G1Affine, nbValues)
+	for i := range basis {
+		var s fr_bn254.Element
+		s.SetRandom()
+		basis[i].ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	}
+	pks, _, err := ped_bn254.Setup(basis)
+	assert.NoError(err)
+	values := make([]fr_bn254.Element, nbValues)
+	values[0].SetRandom()
+	values[1].SetRandom()
+	cmt, err := pks[0].Commit(values)
+	assert.NoError(err)
+	pok, err := pks[0].ProveKnowledge(values)
+	assert.NoError(err)
+
+	pk, err := ValueOfProvingKey[sw_bn254.G1Affine](&pks[0])
+	assert.NoError(err)
+	circuit := openingCircuit[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]{
+		Values:     make([]emulated.Element[sw_bn254.ScalarField], nbValues),
+		ProvingKey: ProvingKey[sw_bn254.G1Affine]{Basis: make([]sw_bn254.G1Affine, nbValues), BasisExpSigma: make([]sw_bn254.G1Affine, nbValues)},
+	}
+	assignment := openingCircuit[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]{
+		Values:     make([]emulated.Element[sw_bn254.ScalarField], nbValues),
+		ProvingKey: pk,
+	}
+	assignment.Commitment, err = ValueOfCommitment[sw_bn254.G1Affine](cmt)
+	assert.NoError(err)
+	assignment.KnowledgeProof, err = ValueOfKnowledgeProof[sw_bn254.G1Affine](pok)
+	assert.NoError(err)
+	for i := range values {
+		assignment.Values[i] = emulated.ValueOf[sw_bn254.ScalarField](values[i])
+	}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// opening to other values
+	assignment.Values[0] = emulated.ValueOf[sw_bn254.ScalarField](values[1])
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,317 @@
+// Package zeromorph implements in-circuit verification of Zeromorph opening
+// proofs of multilinear polynomials committed to with KZG.
+//
+// Zeromorph (https://eprint.iacr.org/2023/917) reduces the opening of a
+// multilinear polynomial at a point to a univariate KZG opening, so that the
+// same SRS and verifying key as for KZG are used, along with the size of the
+// SRS. The proofs are computed natively with the Open function of the
+// zeromorph package of the curve, for example
+// github.com/consensys/gnark/backend/commitments/bn254/zeromorph, with a hash
+// function matching the in-circuit transcript (see [recursion.NewShort]).
+package zeromorph
+
+import (
+	"fmt"
+
+	zeromorph_bls12377 "github.com/consensys/gnark/backend/commitments/bls12-377/zeromorph"
+	zeromorph_bls12381 "github.com/consensys/gnark/backend/commitments/bls12-381/zeromorph"
+	zeromorph_bls24315 "github.com/consensys/gnark/backend/commitments/bls24-315/zeromorph"
+	zeromorph_bn254 "github.com/consensys/gnark/backend/commitments/bn254/zeromorph"
+	zeromorph_bw6761 "github.com/consensys/gnark/backend/commitments/bw6-761/zeromorph"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// OpeningProof is a Zeromorph opening proof of a multilinear polynomial in
+// n variables. Use [ValueOfOpeningProof] to initialize a witness from a native
+// proof and [PlaceholderOpeningProof] to define the circuit.
+type OpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	Quotients       []G1El
+	BatchedQuotient G1El
+	ShiftedQuotient G1El
+	Quotient        G1El
+	ClaimedValue    emulated.Element[FR]
+}
+
+// PlaceholderOpeningProof returns a placeholder opening proof of a multilinear
+// polynomial in nbVariables variables, for circuit compilation.
+func PlaceholderOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT](nbVariables int) OpeningProof[FR, G1El] {
+	return OpeningProof[FR, G1El]{
+		Quotients: make([]G1El, nbVariables),
+	}
+}
+
+// ValueOfOpeningProof initializes an opening proof witness from a native
+// opening proof. It returns an error if there is a mismatch between the type
+// parameters and the type of the provided proof.
+func ValueOfOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT](proof any) (OpeningProof[FR, G1El], error) {
+	var ret OpeningProof[FR, G1El]
+	switch s := any(&ret).(type) {
+	case *OpeningProof[sw_bn254.ScalarField, sw_bn254.G1Affine]:
+		tProof, ok := proof.(zeromorph_bn254.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.Quotients = make([]sw_bn254.G1Affine, len(tProof.Quotients))
+		for i := range tProof.Quotients {
+			s.Quotients[i] = sw_bn254.NewG1Affine(tProof.Quotients[i])
+		}
+		s.BatchedQuotient = sw_bn254.NewG1Affine(tProof.BatchedQuotient)
+		s.ShiftedQuotient = sw_bn254.NewG1Affine(tProof.ShiftedQuotient)
+		s.Quotient = sw_bn254.NewG1Affine(tProof.Quotient)
+		s.ClaimedValue = sw_bn254.NewScalar(tProof.ClaimedValue)
+	case *OpeningProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine]:
+		tProof, ok := proof.(zeromorph_bls12377.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.Quotients = make([]sw_bls12377.G1Affine, len(tProof.Quotients))
+		for i := range tProof.Quotients {
+			s.Quotients[i] = sw_bls12377.NewG1Affine(tProof.Quotients[i])
+		}
+		s.BatchedQuotient = sw_bls12377.NewG1Affine(tProof.BatchedQuotient)
+		s.ShiftedQuotient = sw_bls12377.NewG1Affine(tProof.ShiftedQuotient)
+		s.Quotient = sw_bls12377.NewG1Affine(tProof.Quotient)
+		s.ClaimedValue = sw_bls12377.NewScalar(tProof.ClaimedValue)
+	case *OpeningProof[sw_bls12381.ScalarField, sw_bls12381.G1Affine]:
+		tProof, ok := proof.(zeromorph_bls12381.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.Quotients = make([]sw_bls12381.G1Affine, len(tProof.Quotients))
+		for i := range tProof.Quotients {
+			s.Quotients[i] = sw_bls12381.NewG1Affine(tProof.Quotients[i])
+		}
+		s.BatchedQuotient = sw_bls12381.NewG1Affine(tProof.BatchedQuotient)
+		s.ShiftedQuotient = sw_bls12381.NewG1Affine(tProof.ShiftedQuotient)
+		s.Quotient = sw_bls12381.NewG1Affine(tProof.Quotient)
+		s.ClaimedValue = sw_bls12381.NewScalar(tProof.ClaimedValue)
+	case *OpeningProof[sw_bw6761.ScalarField, sw_bw6761.G1Affine]:
+		tProof, ok := proof.(zeromorph_bw6761.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.Quotients = make([]sw_bw6761.G1Affine, len(tProof.Quotients))
+		for i := range tProof.Quotients {
+			s.Quotients[i] = sw_bw6761.NewG1Affine(tProof.Quotients[i])
+		}
+		s.BatchedQuotient = sw_bw6761.NewG1Affine(tProof.BatchedQuotient)
+		s.ShiftedQuotient = sw_bw6761.NewG1Affine(tProof.ShiftedQuotient)
+		s.Quotient = sw_bw6761.NewG1Affine(tProof.Quotient)
+		s.ClaimedValue = sw_bw6761.NewScalar(tProof.ClaimedValue)
+	case *OpeningProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine]:
+		tProof, ok := proof.(zeromorph_bls24315.OpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		s.Quotients = make([]sw_bls24315.G1Affine, len(tProof.Quotients))
+		for i := range tProof.Quotients {
+			s.Quotients[i] = sw_bls24315.NewG1Affine(tProof.Quotients[i])
+		}
+		s.BatchedQuotient = sw_bls24315.NewG1Affine(tProof.BatchedQuotient)
+		s.ShiftedQuotient = sw_bls24315.NewG1Affine(tProof.ShiftedQuotient)
+		s.Quotient = sw_bls24315.NewG1Affine(tProof.Quotient)
+		s.ClaimedValue = sw_bls24315.NewScalar(tProof.ClaimedValue)
+	default:
+		return ret, fmt.Errorf("unknown type parametrization")
+	}
+	return ret, nil
+}
+
+// VerifyingKey is the KZG verifying key of the SRS along with its size. Use
+// [ValueOfVerifyingKey] to initialize a witness from a native verifying key.
+type VerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT] struct {
+	Kzg kzg.VerifyingKey[G1El, G2El]
+
+	// SRSSize is the number of powers of τ in G1 in the SRS. It is a constant
+	// of the circuit and must be set when defining it.
+	SRSSize uint64 `gnark:"-"`
+}
+
+// ValueOfVerifyingKey initializes a verifying key witness from a native
+// verifying key. It returns an error if there is a mismatch between the type
+// parameters and the type of the provided verifying key.
+func ValueOfVerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk any) (VerifyingKey[G1El, G2El], error) {
+	var ret VerifyingKey[G1El, G2El]
+	var kzgVk any
+	switch tVk := vk.(type) {
+	case zeromorph_bn254.VerifyingKey:
+		kzgVk, ret.SRSSize = tVk.VerifyingKey, tVk.SRSSize
+	case zeromorph_bls12377.VerifyingKey:
+		kzgVk, ret.SRSSize = tVk.VerifyingKey, tVk.SRSSize
+	case zeromorph_bls12381.VerifyingKey:
+		kzgVk, ret.SRSSize = tVk.VerifyingKey, tVk.SRSSize
+	case zeromorph_bw6761.VerifyingKey:
+		kzgVk, ret.SRSSize = tVk.VerifyingKey, tVk.SRSSize
+	case zeromorph_bls24315.VerifyingKey:
+		kzgVk, ret.SRSSize = tVk.VerifyingKey, tVk.SRSSize
+	default:
+		return ret, fmt.Errorf("unknown verifying key type %T", vk)
+	}
+	var err error
+	ret.Kzg, err = kzg.ValueOfVerifyingKey[G1El, G2El](kzgVk)
+	return ret, err
+}
+
+// Verifier allows verifying Zeromorph opening proofs.
+type Verifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.G2ElementT] struct {
+	api       frontend.API
+	scalarApi *emulated.Field[FR]
+	curve     algebra.Curve[FR, G1El]
+	pairing   algebra.Pairing[G1El, G2El, GtEl]
+}
+
+// NewVerifier initializes a new Verifier instance.
+func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.G2ElementT](api frontend.API) (*Verifier[FR, G1El, G2El, GtEl], error) {
+	curve, err := algebra.GetCurve[FR, G1El](api)
+	if err != nil {
+		return nil, err
+	}
+	scalarApi, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, err
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier[FR, G1El, G2El, GtEl]{
+		api:       api,
+		scalarApi: scalarApi,
+		curve:     curve,
+		pairing:   pairing,
+	}, nil
+}
+
+// CheckOpeningProof asserts that the multilinear polynomial committed to in
+// commitment evaluates to proof.ClaimedValue at point. dataTranscript must be
+// the data bound to the transcript by the prover, marshalled as scalars.
+func (v *Verifier[FR, G1El, G2El, GtEl]) CheckOpeningProof(commitment kzg.Commitment[G1El], proof OpeningProof[FR, G1El], point []emulated.Element[FR], vk VerifyingKey[G1El, G2El], dataTranscript ...emulated.Element[FR]) error {
+	n := len(point)
+	if len(proof.Quotients) != n {
+		return fmt.Errorf("number of quotients %d doesn't match the number of variables %d", len(proof.Quotients), n)
+	}
+	if vk.SRSSize < 1<<n {
+		return fmt.Errorf("SRS size %d is smaller than the number of evaluations %d", vk.SRSSize, 1<<n)
+	}
+	y, x, z, err := v.deriveChallenges(commitment, proof, point, dataTranscript)
+	if err != nil {
+		return fmt.Errorf("derive challenges: %w", err)
+	}
+
+	// x²ᵏ, Φₙ₋ₖ(x²ᵏ) = Πᵢ₌ₖⁿ⁻¹(1+x²ⁱ) and xᴺ⁻²ᵏ = Πᵢ₌ₖⁿ⁻¹x²ⁱ
+	powers := make([]*emulated.Element[FR], n+1)
+	powers[0] = x
+	for k := 1; k <= n; k++ {
+		powers[k] = v.scalarApi.Mul(powers[k-1], powers[k-1])
+	}
+	one := v.scalarApi.One()
+	phi := make([]*emulated.Element[FR], n+1)
+	shift := make([]*emulated.Element[FR], n+1)
+	phi[n], shift[n] = one, one
+	for k := n - 1; k >= 0; k-- {
+		phi[k] = v.scalarApi.Mul(phi[k+1], v.scalarApi.Add(powers[k], one))
+		shift[k] = v.scalarApi.Mul(shift[k+1], powers[k])
+	}
+
+	// [ζₓ + zZₓ + z²(Xᴰ⁻ᴺq̂ - xᴰ⁻ᴺq̂)] + x[π] =
+	// (1-z²xᴰ⁻ᴺ)[q̂] + z²[Xᴰ⁻ᴺq̂] + z[f] - zvΦₙ(x)[1] + Σₖ cₖ[qₖ] + x[π] where
+	// cₖ = -yᵏxᴺ⁻²ᵏ - z(x²ᵏΦₙ₋ₖ₋₁(x²ᵏ⁺¹) - uₖΦₙ₋ₖ(x²ᵏ)) and D is the size of
+	// the SRS. The shifted quotient bounds the degree of q̂ by N.
+	zSquare := v.scalarApi.Mul(z, z)
+	hatCoeff := v.scalarApi.Sub(one, v.scalarApi.Mul(zSquare, v.expConst(x, vk.SRSSize-1<<n)))
+	bases := []*G1El{&proof.BatchedQuotient, &proof.ShiftedQuotient, &commitment.G1El, &vk.Kzg.G1, &proof.Quotient}
+	constant := v.scalarApi.Mul(z, v.scalarApi.Mul(&proof.ClaimedValue, phi[0]))
+	scalars := []*emulated.Element[FR]{hatCoeff, zSquare, z, v.scalarApi.Neg(constant), x}
+	yk := one
+	for k := 0; k < n; k++ {
+		t := v.scalarApi.Sub(v.scalarApi.Mul(powers[k], phi[k+1]), v.scalarApi.Mul(&point[k], phi[k]))
+		c := v.scalarApi.Add(v.scalarApi.Mul(z, t), v.scalarApi.Mul(yk, shift[k]))
+		bases = append(bases, &proof.Quotients[k])
+		scalars = append(scalars, v.scalarApi.Neg(c))
+		yk = v.scalarApi.Mul(yk, y)
+	}
+	lhs, err := v.curve.MultiScalarMul(bases, scalars)
+	if err != nil {
+		return fmt.Errorf("multi scalar mul: %w", err)
+	}
+
+	// e([ζₓ + zZₓ] + x[π], [1]) = e([π], [τ])
+	if err := v.pairing.PairingCheck(
+		[]*G1El{lhs, v.curve.Neg(&proof.Quotient)},
+		[]*G2El{&vk.Kzg.G2[0], &vk.Kzg.G2[1]},
+	); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// expConst returns xᵉ for a constant exponent e.
+func (v *Verifier[FR, G1El, G2El, GtEl]) expConst(x *emulated.Element[FR], e uint64) *emulated.Element[FR] {
+	res := v.scalarApi.One()
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = v.scalarApi.Mul(res, x)
+		}
+		if e > 1 {
+			x = v.scalarApi.Mul(x, x)
+		}
+	}
+	return res
+}
+
+// deriveChallenges derives the challenges y, x and z as the native prover.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveChallenges(commitment kzg.Commitment[G1El], proof OpeningProof[FR, G1El], point []emulated.Element[FR], dataTranscript []emulated.Element[FR]) (y, x, z *emulated.Element[FR], err error) {
+	var fr FR
+	fs, err := recursion.NewTranscript(v.api, fr.Modulus(), []string{"y", "x", "z"})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("new transcript: %w", err)
+	}
+	if err := fs.Bind("y", v.curve.MarshalG1(commitment.G1El)); err != nil {
+		return nil, nil, nil, fmt.Errorf("bind commitment: %w", err)
+	}
+	for i := range point {
+		if err := fs.Bind("y", v.curve.MarshalScalar(point[i])); err != nil {
+			return nil, nil, nil, fmt.Errorf("bind %d-th coordinate: %w", i, err)
+		}
+	}
+	if err := fs.Bind("y", v.curve.MarshalScalar(proof.ClaimedValue)); err != nil {
+		return nil, nil, nil, fmt.Errorf("bind claimed value: %w", err)
+	}
+	for i := range dataTranscript {
+		if err := fs.Bind("y", v.curve.MarshalScalar(dataTranscript[i])); err != nil {
+			return nil, nil, nil, fmt.Errorf("bind %d-th data transcript: %w", i, err)
+		}
+	}
+	for i := range proof.Quotients {
+		if err := fs.Bind("y", v.curve.MarshalG1(proof.Quotients[i])); err != nil {
+			return nil, nil, nil, fmt.Errorf("bind %d-th quotient: %w", i, err)
+		}
+	}
+	if err := fs.Bind("x", v.curve.MarshalG1(proof.BatchedQuotient)); err != nil {
+		return nil, nil, nil, fmt.Errorf("bind batched quotient: %w", err)
+	}
+	if err := fs.Bind("x", v.curve.MarshalG1(proof.ShiftedQuotient)); err != nil {
+		return nil, nil, nil, fmt.Errorf("bind shifted quotient: %w", err)
+	}
+
+	challenges := make([]*emulated.Element[FR], 3)
+	for i, name := range []string{"y", "x", "z"} {
+		c, err := fs.ComputeChallenge(name)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("compute challenge %s: %w", name, err)
+		}
+		bc := bits.ToBinary(v.api, c, bits.WithNbDigits(fr.Modulus().BitLen()))
+		challenges[i] = v.scalarApi.FromBits(bc...)
+	}
+	return challenges[0], challenges[1], challenges[2], nil
+}
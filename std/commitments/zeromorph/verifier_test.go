@@ -0,0 +1,90 @@
+package zeromorph
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	zeromorph_bls12377 "github.com/consensys/gnark/backend/commitments/bls12-377/zeromorph"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/test"
+)
+
+type ZeromorphVerificationCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GTEl algebra.GtElementT] struct {
+	VerifyingKey VerifyingKey[G1El, G2El]
+	Commitment   kzg.Commitment[G1El]
+	Proof        OpeningProof[FR, G1El]
+	Point        []emulated.Element[FR]
+}
+
+func (c *ZeromorphVerificationCircuit[FR, G1El, G2El, GTEl]) Define(api frontend.API) error {
+	verifier, err := NewVerifier[FR, G1El, G2El, GTEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return verifier.CheckOpeningProof(c.Commitment, c.Proof, c.Point, c.VerifyingKey)
+}
+
+func TestZeromorphVerificationTwoChain(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbVariables = 4
+
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	// an SRS larger than the number of evaluations needs the degree check
+	srs, err := kzg_bls12377.NewSRS(1<<(nbVariables+1), alpha)
+	assert.NoError(err)
+	vk := zeromorph_bls12377.VerifyingKey{VerifyingKey: srs.Vk, SRSSize: uint64(len(srs.Pk.G1))}
+
+	evaluations := make([]fr_bls12377.Element, 1<<nbVariables)
+	for i := range evaluations {
+		evaluations[i].SetRandom()
+	}
+	point := make([]fr_bls12377.Element, nbVariables)
+	for i := range point {
+		point[i].SetRandom()
+	}
+	com, err := zeromorph_bls12377.Commit(evaluations, srs.Pk)
+	assert.NoError(err)
+	h, err := recursion.NewShort(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	proof, err := zeromorph_bls12377.Open(evaluations, point, com, h, srs.Pk)
+	assert.NoError(err)
+	assert.NoError(zeromorph_bls12377.Verify(&proof, com, point, h, vk))
+
+	wCmt, err := kzg.ValueOfCommitment[sw_bls12377.G1Affine](com)
+	assert.NoError(err)
+	wProof, err := ValueOfOpeningProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine](proof)
+	assert.NoError(err)
+	wVk, err := ValueOfVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine](vk)
+	assert.NoError(err)
+	wPoint := make([]emulated.Element[sw_bls12377.ScalarField], nbVariables)
+	for i := range point {
+		wPoint[i], err = kzg.ValueOfScalar[sw_bls12377.ScalarField](point[i])
+		assert.NoError(err)
+	}
+
+	circuit := ZeromorphVerificationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		VerifyingKey: VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine]{SRSSize: vk.SRSSize},
+		Proof:        PlaceholderOpeningProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine](nbVariables),
+		Point:        make([]emulated.Element[sw_bls12377.ScalarField], nbVariables),
+	}
+	assignment := ZeromorphVerificationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		VerifyingKey: wVk,
+		Commitment:   wCmt,
+		Proof:        wProof,
+		Point:        wPoint,
+	}
+	invalid := assignment
+	invalid.Proof.ClaimedValue, err = kzg.ValueOfScalar[sw_bls12377.ScalarField](point[0])
+	assert.NoError(err)
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&assignment), test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BW6_761))
+}
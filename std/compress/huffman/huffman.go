@@ -0,0 +1,148 @@
+// Package huffman implements the decoding of canonical Huffman codes in
+// circuit, as used by DEFLATE [RFC 1951].
+//
+// A canonical Huffman code is defined by the code lengths of the symbols: the
+// codes of the same length are consecutive integers in the order of the
+// symbols, and the shorter codes lexicographically precede the longer ones.
+//
+// The decoding is table-driven. With L the maximal code length, the next L bits
+// of the stream are looked up in a table of 2^L entries which gives the symbol
+// they start with and its length. The lookups use the log-derivative argument
+// of package [github.com/consensys/gnark/std/lookup/logderivlookup], so that
+// decoding m symbols from n bits costs O(n + m + 2^L) constraints.
+//
+// [RFC 1951]: https://www.rfc-editor.org/rfc/rfc1951#section-3.2.2
+package huffman
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+)
+
+// maxCodeLength bounds the size of the decoding table. DEFLATE codes are at
+// most 15 bits long.
+const maxCodeLength = 16
+
+// Codes returns the canonical Huffman codes of the symbols given their code
+// lengths, as defined in RFC 1951. The symbols of length 0 don't occur and get
+// the code 0. It returns an error if the lengths don't define a prefix code.
+func Codes(lengths []int) ([]uint64, error) {
+	var blCount [maxCodeLength + 1]int
+	for s, l := range lengths {
+		if l < 0 || l > maxCodeLength {
+			return nil, fmt.Errorf("symbol %d: code length %d out of range [0, %d]", s, l, maxCodeLength)
+		}
+		blCount[l]++
+	}
+	blCount[0] = 0
+
+	// check the Kraft inequality, ∑ 2^-l ≤ 1
+	var kraft uint64
+	for l := 1; l <= maxCodeLength; l++ {
+		kraft += uint64(blCount[l]) << (maxCodeLength - l)
+	}
+	if kraft == 0 {
+		return nil, errors.New("no symbol occurs")
+	}
+	if kraft > 1<<maxCodeLength {
+		return nil, errors.New("code lengths are over-subscribed")
+	}
+
+	var nextCode [maxCodeLength + 1]uint64
+	var code uint64
+	for l := 1; l <= maxCodeLength; l++ {
+		code = (code + uint64(blCount[l-1])) << 1
+		nextCode[l] = code
+	}
+	codes := make([]uint64, len(lengths))
+	for s, l := range lengths {
+		if l != 0 {
+			codes[s] = nextCode[l]
+			nextCode[l]++
+		}
+	}
+	return codes, nil
+}
+
+// Decode decodes len(out) symbols from the stream of bits, read in order, the
+// code of every symbol starting with its most significant bit. lengths are the
+// code lengths of the symbols, see [Codes]. It returns the number of bits
+// read.
+//
+// The bits are asserted to be boolean. No proof can be generated if the
+// stream doesn't start with the codes of len(out) symbols.
+func Decode(api frontend.API, bits []frontend.Variable, lengths []int, out []frontend.Variable) (nbBitsRead frontend.Variable, err error) {
+	codes, err := Codes(lengths)
+	if err != nil {
+		return nil, err
+	}
+	maxLength := 0
+	for _, l := range lengths {
+		maxLength = max(maxLength, l)
+	}
+
+	// the table of the symbols and code lengths indexed by the next maxLength
+	// bits. The length is 0 for the indices which don't start with a code.
+	symbols := make([]frontend.Variable, 1<<maxLength)
+	symbolLengths := make([]frontend.Variable, 1<<maxLength)
+	for i := range symbols {
+		symbols[i], symbolLengths[i] = 0, 0
+	}
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		start := codes[s] << (maxLength - l)
+		for i := start; i < start+1<<(maxLength-l); i++ {
+			symbols[i], symbolLengths[i] = s, l
+		}
+	}
+	symbolTable := newTable(api, symbols)
+	lengthTable := newTable(api, symbolLengths)
+
+	// windows[i] holds the bits [i, i+maxLength) of the stream as a big-endian
+	// number, the stream being padded with zeros. The last entry is for the end
+	// of the stream.
+	for i := range bits {
+		api.AssertIsBoolean(bits[i])
+	}
+	padded := make([]frontend.Variable, len(bits)+maxLength)
+	copy(padded, bits)
+	for i := len(bits); i < len(padded); i++ {
+		padded[i] = 0
+	}
+	windows := logderivlookup.New(api)
+	var window frontend.Variable = 0
+	for i := 0; i < maxLength; i++ {
+		window = api.Add(api.Mul(window, 2), padded[i])
+	}
+	windows.Insert(window)
+	for i := 1; i <= len(bits); i++ {
+		// shift out bit i-1 and shift in bit i-1+maxLength
+		window = api.Add(api.Mul(window, 2), api.Mul(padded[i-1], -(1<<maxLength)), padded[i-1+maxLength])
+		windows.Insert(window)
+	}
+
+	var pos frontend.Variable = 0
+	for j := range out {
+		w := windows.Lookup(pos)[0]
+		out[j] = symbolTable.Lookup(w)[0]
+		l := lengthTable.Lookup(w)[0]
+		api.AssertIsDifferent(l, 0)
+		pos = api.Add(pos, l)
+	}
+	// the last code ends in the stream: the lookup fails past its end
+	windows.Lookup(pos)
+	return pos, nil
+}
+
+func newTable(api frontend.API, entries []frontend.Variable) *logderivlookup.Table {
+	t := logderivlookup.New(api)
+	for i := range entries {
+		t.Insert(entries[i])
+	}
+	return t
+}
@@ -0,0 +1,149 @@
+package huffman
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodes(t *testing.T) {
+	// example of RFC 1951, section 3.2.2
+	codes, err := Codes([]int{3, 3, 3, 3, 3, 2, 4, 4})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0b010, 0b011, 0b100, 0b101, 0b110, 0b00, 0b1110, 0b1111}, codes)
+
+	_, err = Codes([]int{1, 1, 1})
+	require.Error(t, err, "over-subscribed")
+	_, err = Codes([]int{0, 0})
+	require.Error(t, err, "no symbol")
+}
+
+// fixedLiteralLengths returns the code lengths of the fixed literal/length
+// code of DEFLATE.
+func fixedLiteralLengths() []int {
+	lengths := make([]int, 288)
+	for s := range lengths {
+		switch {
+		case s < 144:
+			lengths[s] = 8
+		case s < 256:
+			lengths[s] = 9
+		case s < 280:
+			lengths[s] = 7
+		default:
+			lengths[s] = 8
+		}
+	}
+	return lengths
+}
+
+// encode returns the concatenated codes of the symbols, most significant bit
+// first.
+func encode(t *testing.T, lengths []int, symbols []int) []frontend.Variable {
+	codes, err := Codes(lengths)
+	require.NoError(t, err)
+	var bits []frontend.Variable
+	for _, s := range symbols {
+		for i := lengths[s] - 1; i >= 0; i-- {
+			bits = append(bits, (codes[s]>>i)&1)
+		}
+	}
+	return bits
+}
+
+type decodeCircuit struct {
+	Bits    []frontend.Variable
+	Symbols []frontend.Variable
+	NbBits  frontend.Variable
+	lengths []int
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	out := make([]frontend.Variable, len(c.Symbols))
+	nbBits, err := Decode(api, c.Bits, c.lengths, out)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		api.AssertIsEqual(out[i], c.Symbols[i])
+	}
+	api.AssertIsEqual(nbBits, c.NbBits)
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	lengths := []int{3, 3, 3, 3, 3, 2, 4, 4}
+	symbols := []int{5, 0, 7, 6, 2, 5}
+	bits := encode(t, lengths, symbols)
+	nbBits := len(bits)
+	// trailing bits are not decoded
+	bits = append(bits, 1, 0, 1)
+
+	circuit := &decodeCircuit{
+		Bits:    make([]frontend.Variable, len(bits)),
+		Symbols: make([]frontend.Variable, len(symbols)),
+		lengths: lengths,
+	}
+	valid := &decodeCircuit{Bits: bits, Symbols: make([]frontend.Variable, len(symbols)), NbBits: nbBits}
+	for i := range symbols {
+		valid.Symbols[i] = symbols[i]
+	}
+	wrongSymbol := &decodeCircuit{Bits: bits, Symbols: make([]frontend.Variable, len(symbols)), NbBits: nbBits}
+	copy(wrongSymbol.Symbols, valid.Symbols)
+	wrongSymbol.Symbols[2] = 6
+	wrongLength := &decodeCircuit{Bits: bits, Symbols: valid.Symbols, NbBits: nbBits + 1}
+
+	assert.CheckCircuit(circuit,
+		test.WithValidAssignment(valid),
+		test.WithInvalidAssignment(wrongSymbol),
+		test.WithInvalidAssignment(wrongLength),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestDecodeFixedLiterals(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	lengths := fixedLiteralLengths()
+	symbols := []int{'g', 'n', 'a', 'r', 'k', 200, 257, 285, 0, 256}
+	bits := encode(t, lengths, symbols)
+
+	circuit := &decodeCircuit{
+		Bits:    make([]frontend.Variable, len(bits)),
+		Symbols: make([]frontend.Variable, len(symbols)),
+		lengths: lengths,
+	}
+	valid := &decodeCircuit{Bits: bits, Symbols: make([]frontend.Variable, len(symbols)), NbBits: len(bits)}
+	for i := range symbols {
+		valid.Symbols[i] = symbols[i]
+	}
+	assert.CheckCircuit(circuit, test.WithValidAssignment(valid), test.WithCurves(ecc.BN254))
+}
+
+type decodeTruncatedCircuit struct {
+	Bits    []frontend.Variable
+	lengths []int
+	nbOut   int
+}
+
+func (c *decodeTruncatedCircuit) Define(api frontend.API) error {
+	_, err := Decode(api, c.Bits, c.lengths, make([]frontend.Variable, c.nbOut))
+	return err
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	lengths := []int{3, 3, 3, 3, 3, 2, 4, 4}
+	bits := encode(t, lengths, []int{0, 7})
+	// the code of the last symbol is cut, the padding completing it
+	truncated := bits[:len(bits)-2]
+	circuit := &decodeTruncatedCircuit{Bits: make([]frontend.Variable, len(truncated)), lengths: lengths, nbOut: 2}
+	assert.CheckCircuit(circuit,
+		test.WithInvalidAssignment(&decodeTruncatedCircuit{Bits: truncated}),
+		test.WithCurves(ecc.BN254))
+}
@@ -1,6 +1,9 @@
 package lzss
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/consensys/compress/lzss"
 	hint "github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
@@ -136,6 +139,55 @@ func Decompress(api frontend.API, c []frontend.Variable, cLength frontend.Variab
 	return dLength, nil
 }
 
+// AssertDecompressesTo asserts that the compressed stream c of length cLength
+// decompresses to d of length dLength, using dict as the dictionary which must
+// come pre "augmented". d is zero past dLength. The sizes of the streams are
+// bounded by len(c) and len(d): no proof can be generated if d doesn't fit in
+// len(d) bytes. As for [Decompress], it is on the caller to ensure that the
+// dictionary is correct.
+func AssertDecompressesTo(api frontend.API, c []frontend.Variable, cLength frontend.Variable, d []frontend.Variable, dLength frontend.Variable, dict []frontend.Variable) error {
+	out := make([]frontend.Variable, len(d))
+	outLength, err := Decompress(api, c, cLength, out, dict)
+	if err != nil {
+		return err
+	}
+	if len(c) == 3 {
+		// the stream only holds the header, Decompress leaves out unset
+		api.AssertIsEqual(dLength, 0)
+		for i := range d {
+			api.AssertIsEqual(d[i], 0)
+		}
+		return nil
+	}
+	// the length is -1 when the output doesn't fit
+	api.AssertIsDifferent(outLength, -1)
+	api.AssertIsEqual(outLength, dLength)
+	for i := range d {
+		api.AssertIsEqual(d[i], out[i])
+	}
+	return nil
+}
+
+// AssertPackedDecompressesTo is as [AssertDecompressesTo], where the compressed
+// stream is packed into field elements of bytesPerElem bytes each, big endian,
+// as in a data-availability blob. The bytes are range checked when
+// decompressing, so bytesPerElem bytes must fit in a field element for the
+// unpacking to be unique.
+func AssertPackedDecompressesTo(api frontend.API, packed []frontend.Variable, bytesPerElem int, cLength frontend.Variable, d []frontend.Variable, dLength frontend.Variable, dict []frontend.Variable) error {
+	if 8*bytesPerElem >= api.Compiler().FieldBitLen() {
+		return fmt.Errorf("%d bytes don't fit in a field element", bytesPerElem)
+	}
+	c, err := api.Compiler().NewHint(compress.UnpackIntoBytesHint, bytesPerElem*len(packed), packed...)
+	if err != nil {
+		return err
+	}
+	radix := big.NewInt(256)
+	for i := range packed {
+		api.AssertIsEqual(packed[i], compress.ReadNum(api, c[i*bytesPerElem:(i+1)*bytesPerElem], radix))
+	}
+	return AssertDecompressesTo(api, c, cLength, d, dLength, dict)
+}
+
 func sliceToTable(api frontend.API, slice []frontend.Variable) *logderivlookup.Table {
 	table := logderivlookup.New(api)
 	for i := range slice {
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/consensys/gnark/frontend/cs/scs"
+	"math/big"
 	"os"
 	"testing"
 
@@ -401,3 +402,92 @@ func (c *testNoZeroPaddingOutputCircuit) Define(api frontend.API) error {
 	}
 	return nil
 }
+
+type assertDecompressionCircuit struct {
+	C, D             []frontend.Variable
+	CLength, DLength frontend.Variable
+	bytesPerElem     int
+}
+
+func (c *assertDecompressionCircuit) Define(api frontend.API) error {
+	dict := test_vector_utils.ToVariableSlice(lzss.AugmentDict(nil))
+	if c.bytesPerElem != 0 {
+		return AssertPackedDecompressesTo(api, c.C, c.bytesPerElem, c.CLength, c.D, c.DLength, dict)
+	}
+	return AssertDecompressesTo(api, c.C, c.CLength, c.D, c.DLength, dict)
+}
+
+func TestAssertDecompressesTo(t *testing.T) {
+	assert := test.NewAssert(t)
+	d := []byte{1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3, 4}
+	compressor, err := lzss.NewCompressor(nil)
+	assert.NoError(err)
+	c, err := compressor.Compress(d)
+	assert.NoError(err)
+	c = append(c, make([]byte, inputExtraBytes)...)
+	dPadded := append(d, 0, 0)
+
+	tampered := make([]byte, len(dPadded))
+	copy(tampered, dPadded)
+	tampered[4]++
+
+	RegisterHints()
+	assert.CheckCircuit(&assertDecompressionCircuit{C: make([]frontend.Variable, len(c)), D: make([]frontend.Variable, len(dPadded))},
+		test.WithValidAssignment(&assertDecompressionCircuit{
+			C: test_vector_utils.ToVariableSlice(c), CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(dPadded), DLength: len(d),
+		}),
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: test_vector_utils.ToVariableSlice(c), CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(tampered), DLength: len(d),
+		}),
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: test_vector_utils.ToVariableSlice(c), CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(dPadded), DLength: len(d) + 1,
+		}),
+		test.WithBackends(backend.PLONK), test.WithCurves(ecc.BLS12_377), test.NoFuzzing())
+
+	// the output doesn't fit
+	assert.CheckCircuit(&assertDecompressionCircuit{C: make([]frontend.Variable, len(c)), D: make([]frontend.Variable, len(d)-1)},
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: test_vector_utils.ToVariableSlice(c), CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(d[:len(d)-1]), DLength: -1,
+		}),
+		test.WithBackends(backend.PLONK), test.WithCurves(ecc.BLS12_377), test.NoFuzzing())
+
+	// the stream only holds the header
+	header := []frontend.Variable{0, 1, 0}
+	assert.CheckCircuit(&assertDecompressionCircuit{C: make([]frontend.Variable, len(header)), D: make([]frontend.Variable, 2)},
+		test.WithValidAssignment(&assertDecompressionCircuit{
+			C: header, CLength: len(header),
+			D: []frontend.Variable{0, 0}, DLength: 0,
+		}),
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: header, CLength: len(header),
+			D: []frontend.Variable{1, 0}, DLength: 0,
+		}),
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: header, CLength: len(header),
+			D: []frontend.Variable{0, 0}, DLength: 1,
+		}),
+		test.WithBackends(backend.PLONK), test.WithCurves(ecc.BLS12_377), test.NoFuzzing())
+
+	// packed into field elements, big endian
+	const bytesPerElem = 31
+	packed := make([]frontend.Variable, (len(c)+bytesPerElem-1)/bytesPerElem)
+	for i := range packed {
+		var elem [bytesPerElem]byte
+		copy(elem[:], c[i*bytesPerElem:])
+		packed[i] = new(big.Int).SetBytes(elem[:])
+	}
+	assert.CheckCircuit(&assertDecompressionCircuit{C: make([]frontend.Variable, len(packed)), D: make([]frontend.Variable, len(dPadded)), bytesPerElem: bytesPerElem},
+		test.WithValidAssignment(&assertDecompressionCircuit{
+			C: packed, CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(dPadded), DLength: len(d),
+		}),
+		test.WithInvalidAssignment(&assertDecompressionCircuit{
+			C: packed, CLength: len(c) - inputExtraBytes,
+			D: test_vector_utils.ToVariableSlice(tampered), DLength: len(d),
+		}),
+		test.WithBackends(backend.PLONK), test.WithCurves(ecc.BLS12_377), test.NoFuzzing())
+}
@@ -0,0 +1,22 @@
+// Package der implements an in-circuit parser of DER-encoded ASN.1 structures.
+//
+// Certificates (X.509) and travel documents (ICAO 9303) sign DER-encoded
+// structures, from which the credential circuits extract the fields they
+// constrain, such as public keys, validity dates or data group hashes. The
+// [Parser] walks such a structure given as a bounded array of bytes: starting
+// from [Parser.Root], [Parser.Child] parses the i-th element of a sequence or a
+// set, and the content of the elements is read with [Parser.Content],
+// [Parser.Integer], [Parser.BitString] and [Parser.IsObjectIdentifier].
+//
+// As the circuit depends on the path walked in the structure, the depth of the
+// structure is bounded by the circuit. When the position of an element depends
+// on the document, for example for the extensions of a certificate, the element
+// is selected with [Parser.ChildAt] among a bounded number of siblings. Its
+// index is part of the witness, computed with the native parser [Parse] and
+// [Node.IndexOf].
+//
+// The parser supports the subset of DER used by these documents: tags are
+// encoded on a single byte and lengths on at most three bytes (16 MiB). The
+// length encodings must be minimal. The content of the primitive elements is not
+// checked, it is up to the circuit to constrain the content it reads.
+package der
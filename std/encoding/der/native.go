@@ -0,0 +1,161 @@
+package der
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// maxDepth bounds the nesting of the elements parsed by [Parse].
+const maxDepth = 32
+
+// ErrUnsupported is returned by [Parse] for encodings which are not DER, or
+// which the circuit doesn't support.
+var ErrUnsupported = errors.New("unsupported encoding")
+
+// Node is a DER element parsed natively. It gives the offsets of the element in
+// the document, and the indices of the elements to select in-circuit with
+// [Parser.ChildAt].
+type Node struct {
+	Tag           byte
+	Offset        int // offset of the tag byte
+	ContentOffset int // offset of the content
+	Length        int // length of the content
+	Children      []*Node
+
+	data []byte
+}
+
+// Parse parses the DER document data with the encodings supported by the
+// circuit. The elements with a constructed tag are parsed recursively.
+func Parse(data []byte) (*Node, error) {
+	n, err := parseNode(data, 0, len(data), 0)
+	if err != nil {
+		return nil, err
+	}
+	if n.End() != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes", len(data)-n.End())
+	}
+	return n, nil
+}
+
+func parseNode(data []byte, offset, end, depth int) (*Node, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nesting deeper than %d", maxDepth)
+	}
+	if end-offset < 2 {
+		return nil, fmt.Errorf("truncated header at offset %d", offset)
+	}
+	n := &Node{Tag: data[offset], Offset: offset, data: data}
+	if n.Tag&0x1f == 0x1f {
+		return nil, fmt.Errorf("multi-byte tag at offset %d: %w", offset, ErrUnsupported)
+	}
+	l := int(data[offset+1])
+	n.ContentOffset = offset + 2
+	if l < 0x80 {
+		n.Length = l
+	} else {
+		nbBytes := l - 0x80
+		if nbBytes < 1 || nbBytes > 3 {
+			return nil, fmt.Errorf("length on %d bytes at offset %d: %w", nbBytes, offset, ErrUnsupported)
+		}
+		if end-n.ContentOffset < nbBytes {
+			return nil, fmt.Errorf("truncated length at offset %d", offset)
+		}
+		for _, b := range data[n.ContentOffset : n.ContentOffset+nbBytes] {
+			n.Length = n.Length<<8 | int(b)
+		}
+		if data[n.ContentOffset] == 0 || (nbBytes == 1 && n.Length < 0x80) {
+			return nil, fmt.Errorf("non-minimal length at offset %d: %w", offset, ErrUnsupported)
+		}
+		n.ContentOffset += nbBytes
+	}
+	if n.End() > end {
+		return nil, fmt.Errorf("element at offset %d exceeds its parent", offset)
+	}
+	if n.Tag&0x20 != 0 {
+		for o := n.ContentOffset; o < n.End(); {
+			c, err := parseNode(data, o, n.End(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			n.Children = append(n.Children, c)
+			o = c.End()
+		}
+	}
+	return n, nil
+}
+
+// End returns the offset after the content of the element.
+func (n *Node) End() int {
+	return n.ContentOffset + n.Length
+}
+
+// Content returns the content of the element.
+func (n *Node) Content() []byte {
+	return n.data[n.ContentOffset:n.End()]
+}
+
+// Child returns the element at the given path of indices from n.
+func (n *Node) Child(path ...int) (*Node, error) {
+	c := n
+	for _, i := range path {
+		if i < 0 || i >= len(c.Children) {
+			return nil, fmt.Errorf("no child %d in element at offset %d", i, c.Offset)
+		}
+		c = c.Children[i]
+	}
+	return c, nil
+}
+
+// IndexOf returns the index of the first child of n matching, to select it
+// in-circuit with [Parser.ChildAt].
+func (n *Node) IndexOf(match func(*Node) bool) (int, error) {
+	for i, c := range n.Children {
+		if match(c) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no matching child in element at offset %d", n.Offset)
+}
+
+// IsObjectIdentifier returns true if the element is the OBJECT IDENTIFIER oid.
+func (n *Node) IsObjectIdentifier(oid asn1.ObjectIdentifier) bool {
+	content, err := encodeObjectIdentifier(oid)
+	if err != nil || n.Tag != TagObjectIdentifier {
+		return false
+	}
+	return string(n.Content()) == string(content)
+}
+
+// ValueOf returns the assignment of the data of [New] for the document data, padded
+// with zeros to capacity bytes.
+func ValueOf(data []byte, capacity int) ([]frontend.Variable, error) {
+	if len(data) > capacity {
+		return nil, fmt.Errorf("document of %d bytes exceeds the capacity %d", len(data), capacity)
+	}
+	res := make([]frontend.Variable, capacity)
+	for i := range res {
+		if i < len(data) {
+			res[i] = data[i]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res, nil
+}
+
+// encodeObjectIdentifier returns the content of the DER encoding of oid.
+func encodeObjectIdentifier(oid asn1.ObjectIdentifier) ([]byte, error) {
+	enc, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, fmt.Errorf("encode object identifier: %w", err)
+	}
+	n, err := Parse(enc)
+	if err != nil {
+		return nil, fmt.Errorf("encode object identifier: %w", err)
+	}
+	return n.Content(), nil
+}
@@ -0,0 +1,287 @@
+package der
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Tags of the universal types used by the parser.
+const (
+	TagInteger          = 0x02
+	TagBitString        = 0x03
+	TagOctetString      = 0x04
+	TagNull             = 0x05
+	TagObjectIdentifier = 0x06
+	TagSequence         = 0x30
+	TagSet              = 0x31
+)
+
+// maxHeaderSize is the size of the largest header supported: a tag byte, and a
+// long form length on three bytes.
+const maxHeaderSize = 5
+
+// Element is a DER element parsed in-circuit.
+type Element struct {
+	Tag           frontend.Variable // tag byte
+	Offset        frontend.Variable // offset of the tag byte
+	ContentOffset frontend.Variable // offset of the content
+	Length        frontend.Variable // length of the content
+	End           frontend.Variable // offset after the content
+
+	constructed frontend.Variable
+}
+
+// Parser parses DER-encoded structures in-circuit. See the package
+// documentation for the supported encodings.
+type Parser struct {
+	api    frontend.API
+	rc     frontend.Rangechecker
+	table  *logderivlookup.Table
+	length frontend.Variable
+	nbBits int // number of bits of the offsets
+}
+
+// New returns a parser of the first length bytes of data. The bytes after
+// length are ignored, so that the circuit supports documents of size up to
+// len(data). The bytes of data are range checked.
+func New(api frontend.API, data []frontend.Variable, length frontend.Variable) (*Parser, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty data")
+	}
+	p := &Parser{
+		api:    api,
+		rc:     rangecheck.New(api),
+		table:  logderivlookup.New(api),
+		length: length,
+		nbBits: bits.Len(uint(len(data) + maxHeaderSize)),
+	}
+	for i := range data {
+		p.rc.Check(data[i], 8)
+		p.table.Insert(data[i])
+	}
+	// the headers are read on maxHeaderSize bytes, including after the end of
+	// the data for the elements ending the document.
+	for i := 0; i < maxHeaderSize; i++ {
+		p.table.Insert(0)
+	}
+	p.rc.Check(api.Sub(len(data), length), p.nbBits)
+	return p, nil
+}
+
+// Root parses the element at the start of the data, and asserts that it spans
+// the whole document.
+func (p *Parser) Root() Element {
+	e := p.header(0, 1)
+	p.api.AssertIsEqual(e.End, p.length)
+	return e
+}
+
+// Child parses the i-th element of the content of parent, which must be a
+// constructed element with at least i+1 elements.
+func (p *Parser) Child(parent Element, i int) Element {
+	if i < 0 {
+		panic("negative child index")
+	}
+	p.api.AssertIsEqual(parent.constructed, 1)
+	offset := parent.ContentOffset
+	var e Element
+	for k := 0; k <= i; k++ {
+		e = p.header(offset, 1)
+		p.rc.Check(p.api.Sub(parent.End, e.End), p.nbBits)
+		offset = e.End
+	}
+	return e
+}
+
+// ChildAt parses the element at index of the content of parent, where index is
+// a variable lower than maxChildren. The siblings are parsed up to maxChildren,
+// or until the end of parent if it has less elements. The proof fails if
+// parent has no element at index.
+//
+// The cost of ChildAt is the one of parsing maxChildren elements, even if
+// parent has less elements.
+func (p *Parser) ChildAt(parent Element, index frontend.Variable, maxChildren int) Element {
+	if maxChildren <= 0 {
+		panic("maxChildren must be positive")
+	}
+	api := p.api
+	api.AssertIsEqual(parent.constructed, 1)
+	offset := parent.ContentOffset
+	present := make([]frontend.Variable, maxChildren)
+	fields := make([][]frontend.Variable, maxChildren)
+	for k := 0; k < maxChildren; k++ {
+		// the children are parsed until the end of the parent. The next
+		// headers are read at the end of the parent, and are not checked.
+		present[k] = api.Sub(1, api.IsZero(api.Sub(parent.End, offset)))
+		e := p.header(offset, present[k])
+		p.rc.Check(api.Mul(present[k], api.Sub(parent.End, e.End)), p.nbBits)
+		offset = api.Select(present[k], e.End, offset)
+		fields[k] = []frontend.Variable{present[k], e.Tag, e.Offset, e.ContentOffset, e.Length, e.End, e.constructed}
+	}
+	selected := selector.BatchMux(api, index, fields...)
+	api.AssertIsEqual(selected[0], 1)
+	return Element{
+		Tag:           selected[1],
+		Offset:        selected[2],
+		ContentOffset: selected[3],
+		Length:        selected[4],
+		End:           selected[5],
+		constructed:   selected[6],
+	}
+}
+
+// header parses the tag and the length of the element at offset. The encoding
+// is checked only if enabled is 1.
+func (p *Parser) header(offset, enabled frontend.Variable) Element {
+	api := p.api
+	b := p.table.Lookup(
+		offset,
+		api.Add(offset, 1),
+		api.Add(offset, 2),
+		api.Add(offset, 3),
+		api.Add(offset, 4),
+	)
+
+	// the tag is on a single byte, the low five bits aren't all set.
+	tagBits := api.ToBinary(b[0], 8)
+	api.AssertIsEqual(api.Mul(enabled, tagBits[0], tagBits[1], tagBits[2], tagBits[3], tagBits[4]), 0)
+
+	// the length is either in short form, or in long form on nbBytes bytes.
+	lenBits := api.ToBinary(b[1], 8)
+	isLong := lenBits[7]
+	nbBytes := api.Sub(b[1], api.Mul(isLong, 0x80))
+	is1 := api.Mul(isLong, api.IsZero(api.Sub(nbBytes, 1)))
+	is2 := api.Mul(isLong, api.IsZero(api.Sub(nbBytes, 2)))
+	is3 := api.Mul(isLong, api.IsZero(api.Sub(nbBytes, 3)))
+	api.AssertIsEqual(api.Mul(enabled, isLong, api.Sub(1, api.Add(is1, is2, is3))), 0)
+	length := api.Add(
+		api.Mul(api.Sub(1, isLong), nbBytes),
+		api.Mul(is1, b[2]),
+		api.Mul(is2, api.Add(api.Mul(b[2], 1<<8), b[3])),
+		api.Mul(is3, api.Add(api.Mul(b[2], 1<<16), api.Mul(b[3], 1<<8), b[4])),
+	)
+	// the long form is minimal: a single length byte encodes lengths from 128,
+	// and the first of several length bytes isn't zero.
+	p.rc.Check(api.Mul(enabled, is1, api.Sub(b[2], 0x80)), 7)
+	api.AssertIsEqual(api.Mul(enabled, api.Add(is2, is3), api.IsZero(b[2])), 0)
+
+	contentOffset := api.Add(offset, 2, api.Mul(isLong, nbBytes))
+	return Element{
+		Tag:           b[0],
+		Offset:        offset,
+		ContentOffset: contentOffset,
+		Length:        length,
+		End:           api.Add(contentOffset, length),
+		constructed:   tagBits[5],
+	}
+}
+
+// AssertTag asserts that the tag of e is tag.
+func (p *Parser) AssertTag(e Element, tag byte) {
+	p.api.AssertIsEqual(e.Tag, tag)
+}
+
+// Content returns the content of e on maxLen bytes. The content is at the
+// start of the returned bytes, followed by zeros. The proof fails if the
+// content of e is longer than maxLen.
+func (p *Parser) Content(e Element, maxLen int) []frontend.Variable {
+	if maxLen < 2 {
+		panic("maxLen must be at least 2")
+	}
+	api := p.api
+	mask := selector.Partition(api, e.Length, false, ones(maxLen))
+	indices := make([]frontend.Variable, maxLen)
+	for j := range indices {
+		indices[j] = api.Mul(mask[j], api.Add(e.ContentOffset, j))
+	}
+	res := p.table.Lookup(indices...)
+	for j := range res {
+		res[j] = api.Mul(mask[j], res[j])
+	}
+	return res
+}
+
+// Integer returns the content of the INTEGER e on maxLen bytes. The content is
+// at the end of the returned bytes, preceded by zeros, so that the result is
+// the big-endian encoding of the integer if it is positive. The proof fails if
+// e isn't an INTEGER or if its content is longer than maxLen.
+func (p *Parser) Integer(e Element, maxLen int) []frontend.Variable {
+	if maxLen < 2 {
+		panic("maxLen must be at least 2")
+	}
+	api := p.api
+	p.AssertTag(e, TagInteger)
+	mask := selector.Partition(api, api.Sub(maxLen, e.Length), true, ones(maxLen))
+	indices := make([]frontend.Variable, maxLen)
+	for j := range indices {
+		indices[j] = api.Mul(mask[j], api.Add(e.End, j-maxLen))
+	}
+	res := p.table.Lookup(indices...)
+	for j := range res {
+		res[j] = api.Mul(mask[j], res[j])
+	}
+	return res
+}
+
+// BitString returns the bits of the BIT STRING e on maxLen bytes, as for
+// [Parser.Content], and their number of bytes. The proof fails if e isn't a
+// BIT STRING of whole bytes, or if it is longer than maxLen bytes.
+func (p *Parser) BitString(e Element, maxLen int) (bytes []frontend.Variable, length frontend.Variable) {
+	api := p.api
+	p.AssertTag(e, TagBitString)
+	// the first byte of the content is the number of unused bits
+	unused := p.table.Lookup(e.ContentOffset)[0]
+	api.AssertIsEqual(unused, 0)
+	content := Element{
+		Tag:           e.Tag,
+		Offset:        e.Offset,
+		ContentOffset: api.Add(e.ContentOffset, 1),
+		Length:        api.Sub(e.Length, 1),
+		End:           e.End,
+		constructed:   e.constructed,
+	}
+	return p.Content(content, maxLen), content.Length
+}
+
+// IsObjectIdentifier returns 1 if e is the OBJECT IDENTIFIER oid, and 0
+// otherwise. It panics if oid can't be encoded.
+func (p *Parser) IsObjectIdentifier(e Element, oid asn1.ObjectIdentifier) frontend.Variable {
+	content, err := encodeObjectIdentifier(oid)
+	if err != nil {
+		panic(err)
+	}
+	api := p.api
+	isLength := api.IsZero(api.Sub(e.Length, len(content)))
+	// the content is read only if it has the expected length, so that the
+	// lookups don't exceed the data.
+	indices := make([]frontend.Variable, len(content))
+	for j := range indices {
+		indices[j] = api.Mul(isLength, api.Add(e.ContentOffset, j))
+	}
+	read := p.table.Lookup(indices...)
+	res := api.Mul(api.IsZero(api.Sub(e.Tag, TagObjectIdentifier)), isLength)
+	for j := range read {
+		res = api.Mul(res, api.IsZero(api.Sub(read[j], content[j])))
+	}
+	return res
+}
+
+// AssertObjectIdentifier asserts that e is the OBJECT IDENTIFIER oid. It
+// panics if oid can't be encoded.
+func (p *Parser) AssertObjectIdentifier(e Element, oid asn1.ObjectIdentifier) {
+	p.api.AssertIsEqual(p.IsObjectIdentifier(e, oid), 1)
+}
+
+func ones(n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = 1
+	}
+	return res
+}
@@ -0,0 +1,179 @@
+package der
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	capacity    = 512
+	serialLen   = 24
+	keyLen      = 40
+	valueLen    = 320
+	maxChildren = 4
+)
+
+var extensionOID = asn1.ObjectIdentifier{2, 5, 29, 15}
+
+type extension struct {
+	ID    asn1.ObjectIdentifier
+	Value []byte
+}
+
+// document has the layout of the start of an X.509 TBSCertificate.
+type document struct {
+	Version    int `asn1:"explicit,tag:0"`
+	Serial     *big.Int
+	Key        asn1.BitString
+	Extensions []extension
+}
+
+func newDocument(t *testing.T) []byte {
+	value := bytes.Repeat([]byte{0xaa}, valueLen)
+	data, err := asn1.Marshal(document{
+		Version: 2,
+		Serial:  new(big.Int).SetBytes(bytes.Repeat([]byte{0x7f}, serialLen-1)),
+		Key:     asn1.BitString{Bytes: bytes.Repeat([]byte{0x42}, keyLen), BitLength: 8 * keyLen},
+		Extensions: []extension{
+			{ID: asn1.ObjectIdentifier{2, 5, 29, 19}, Value: []byte{0x30, 0x00}},
+			{ID: extensionOID, Value: value},
+			{ID: asn1.ObjectIdentifier{2, 5, 29, 37}, Value: []byte{0x01}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+type documentCircuit struct {
+	Data   []frontend.Variable
+	Length frontend.Variable
+	Index  frontend.Variable
+	Serial []frontend.Variable
+	Key    []frontend.Variable
+	Value  []frontend.Variable
+}
+
+func (c *documentCircuit) Define(api frontend.API) error {
+	p, err := New(api, c.Data, c.Length)
+	if err != nil {
+		return err
+	}
+	root := p.Root()
+	p.AssertTag(root, TagSequence)
+
+	version := p.Child(p.Child(root, 0), 0)
+	api.AssertIsEqual(p.Integer(version, 2)[1], 2)
+
+	serial := p.Integer(p.Child(root, 1), serialLen)
+	for i := range serial {
+		api.AssertIsEqual(serial[i], c.Serial[i])
+	}
+
+	key, keyLength := p.BitString(p.Child(root, 2), keyLen)
+	api.AssertIsEqual(keyLength, keyLen)
+	for i := range key {
+		api.AssertIsEqual(key[i], c.Key[i])
+	}
+
+	ext := p.ChildAt(p.Child(root, 3), c.Index, maxChildren)
+	p.AssertObjectIdentifier(p.Child(ext, 0), extensionOID)
+	value := p.Child(ext, 1)
+	p.AssertTag(value, TagOctetString)
+	api.AssertIsEqual(value.Length, valueLen)
+	content := p.Content(value, valueLen)
+	for i := range content {
+		api.AssertIsEqual(content[i], c.Value[i])
+	}
+	return nil
+}
+
+func newAssignment(t *testing.T, data []byte, index int) *documentCircuit {
+	root, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := root.Child(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := root.Child(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := root.Child(3, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &documentCircuit{
+		Length: len(data),
+		Index:  index,
+		Serial: make([]frontend.Variable, serialLen),
+		Key:    make([]frontend.Variable, keyLen),
+		Value:  make([]frontend.Variable, valueLen),
+	}
+	if a.Data, err = ValueOf(data, capacity); err != nil {
+		t.Fatal(err)
+	}
+	// the integer is at the end of the bytes
+	for i := range a.Serial {
+		a.Serial[i] = 0
+	}
+	for i, b := range serial.Content() {
+		a.Serial[serialLen-serial.Length+i] = b
+	}
+	for i, b := range key.Content()[1:] {
+		a.Key[i] = b
+	}
+	for i, b := range value.Content() {
+		a.Value[i] = b
+	}
+	return a
+}
+
+func TestParser(t *testing.T) {
+	assert := test.NewAssert(t)
+	data := newDocument(t)
+
+	// the index witness of the extension
+	root, err := Parse(data)
+	assert.NoError(err)
+	extensions, err := root.Child(3)
+	assert.NoError(err)
+	index, err := extensions.IndexOf(func(n *Node) bool {
+		return len(n.Children) > 0 && n.Children[0].IsObjectIdentifier(extensionOID)
+	})
+	assert.NoError(err)
+	assert.Equal(1, index)
+
+	// the length of the root is in long form on two bytes
+	tampered := bytes.Clone(data)
+	tampered[2] = 0
+	_, err = Parse(tampered)
+	assert.ErrorIs(err, ErrUnsupported)
+
+	circuit := &documentCircuit{
+		Data:   make([]frontend.Variable, capacity),
+		Serial: make([]frontend.Variable, serialLen),
+		Key:    make([]frontend.Variable, keyLen),
+		Value:  make([]frontend.Variable, valueLen),
+	}
+	assert.CheckCircuit(circuit,
+		test.WithValidAssignment(newAssignment(t, data, index)),
+		test.WithInvalidAssignment(newAssignment(t, data, 0)),
+		test.WithInvalidAssignment(newAssignment(t, data, 3)),
+		test.WithInvalidAssignment(newAssignment(t, data, maxChildren)),
+		test.WithInvalidAssignment(func() *documentCircuit {
+			a := newAssignment(t, data, index)
+			a.Data[2] = 0
+			return a
+		}()),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
 )
 
 // Expmod implements [MODEXP] precompile contract at address 0x05.
@@ -29,3 +30,96 @@ func Expmod(api frontend.API, base, exp, modulus *emulated.Element[emparams.Mod1
 	res = f.Select(isZeroMod, f.Zero(), res)
 	return res
 }
+
+// expmodMaxBytes is the maximal length of the inputs of [ExpmodBytes], so that
+// they fit in a [emparams.Mod1e4096] element.
+const expmodMaxBytes = 511
+
+// ExpmodBytes implements [MODEXP] precompile contract at address 0x05 on the
+// big-endian byte strings of the input of the precompile. The result is
+// big-endian and has the length of the modulus, as the output of the
+// precompile. The bytes of the inputs must be range checked.
+//
+// The inputs are at most 511 bytes long. The length of the exponent is the
+// number of iterations of the exponentiation, whereas the base and modulus are
+// always represented with 4k elements. As left-padding with zeros doesn't
+// change the values, inputs whose length is only known at proving time can be
+// padded to a fixed bound, the result then being the output of the precompile
+// padded to the bound.
+func ExpmodBytes(api frontend.API, base, exp, modulus []uints.U8) []uints.U8 {
+	if len(base) > expmodMaxBytes || len(exp) > expmodMaxBytes || len(modulus) > expmodMaxBytes {
+		panic(fmt.Sprintf("inputs longer than %d bytes", expmodMaxBytes))
+	}
+	if len(modulus) == 0 {
+		return nil
+	}
+	f, err := emulated.NewField[emparams.Mod1e4096](api)
+	if err != nil {
+		panic(fmt.Sprintf("new field: %v", err))
+	}
+	b := bytesToElement(api, f, base)
+	m := bytesToElement(api, f, modulus)
+	// x mod 0 = 0, which we get computing modulo 1
+	m = f.Select(f.IsZero(m), f.One(), m)
+
+	// square and multiply from the most significant bit of the exponent
+	res := f.One()
+	for i := range exp {
+		bits := api.ToBinary(exp[i].Val, 8)
+		for j := 7; j >= 0; j-- {
+			res = f.ModMul(res, res, m)
+			res = f.Select(bits[j], f.ModMul(res, b, m), res)
+		}
+	}
+
+	// res is only congruent to the result, the precompile returns the
+	// representative in [0, m)
+	reduced, err := f.NewHint(modReduceHint, 1, res, m)
+	if err != nil {
+		panic(fmt.Sprintf("reduce: %v", err))
+	}
+	f.ModAssertIsEqual(reduced[0], res, m)
+	out := elementToBytes(api, reduced[0], len(modulus))
+	api.AssertIsEqual(f.IsLess(reduced[0], m), 1)
+	return out
+}
+
+// bytesToElement returns the element with the big-endian bytes bts.
+func bytesToElement(api frontend.API, f *emulated.Field[emparams.Mod1e4096], bts []uints.U8) *emulated.Element[emparams.Mod1e4096] {
+	var fp emparams.Mod1e4096
+	bytesPerLimb := int(fp.BitsPerLimb() / 8)
+	limbs := make([]frontend.Variable, fp.NbLimbs())
+	for i := range limbs {
+		limbs[i] = 0
+	}
+	for i := range bts {
+		// the i-th least significant byte
+		pos := len(bts) - 1 - i
+		limbs[i/bytesPerLimb] = api.Add(limbs[i/bytesPerLimb], api.Mul(bts[pos].Val, 1<<(8*(i%bytesPerLimb))))
+	}
+	return f.NewElement(limbs)
+}
+
+// elementToBytes returns the nbBytes big-endian bytes of e. It asserts that e
+// is less than 256^nbBytes.
+func elementToBytes(api frontend.API, e *emulated.Element[emparams.Mod1e4096], nbBytes int) []uints.U8 {
+	var fp emparams.Mod1e4096
+	bytesPerLimb := int(fp.BitsPerLimb() / 8)
+	res := make([]uints.U8, 0, nbBytes)
+	for i := range e.Limbs {
+		n := min(nbBytes-i*bytesPerLimb, bytesPerLimb)
+		if n <= 0 {
+			api.AssertIsEqual(e.Limbs[i], 0)
+			continue
+		}
+		bits := api.ToBinary(e.Limbs[i], 8*n)
+		for j := 0; j < n; j++ {
+			res = append(res, uints.U8{Val: api.FromBinary(bits[8*j : 8*j+8]...)})
+		}
+	}
+	// res is little-endian
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/test"
 )
 
@@ -84,3 +85,62 @@ func TestEdgeCases(t *testing.T) {
 		}, fmt.Sprintf("edge-%d", i))
 	}
 }
+
+type expmodBytesCircuit struct {
+	Base, Exp, Mod, Result []uints.U8
+}
+
+func (c *expmodBytesCircuit) Define(api frontend.API) error {
+	res := ExpmodBytes(api, c.Base, c.Exp, c.Mod)
+	if len(res) != len(c.Result) {
+		return fmt.Errorf("got %d bytes, expected %d", len(res), len(c.Result))
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Result[i].Val)
+	}
+	return nil
+}
+
+func TestExpmodBytes(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the byte lengths of the base, exponent and modulus
+	for _, lengths := range [][3]int{{0, 0, 0}, {1, 1, 1}, {32, 2, 32}, {40, 3, 16}, {8, 0, 8}} {
+		for _, zeroModulus := range []bool{false, true} {
+			assert.Run(func(assert *test.Assert) {
+				b := make([]byte, lengths[0])
+				e := make([]byte, lengths[1])
+				m := make([]byte, lengths[2])
+				rand.Read(b)
+				rand.Read(e)
+				if !zeroModulus {
+					rand.Read(m)
+				}
+				// as the precompile, 0 for a zero modulus
+				res := make([]byte, len(m))
+				if bm := new(big.Int).SetBytes(m); bm.Sign() != 0 {
+					new(big.Int).Exp(new(big.Int).SetBytes(b), new(big.Int).SetBytes(e), bm).FillBytes(res)
+				}
+				circuit := &expmodBytesCircuit{
+					Base:   make([]uints.U8, len(b)),
+					Exp:    make([]uints.U8, len(e)),
+					Mod:    make([]uints.U8, len(m)),
+					Result: make([]uints.U8, len(res)),
+				}
+				assignment := &expmodBytesCircuit{
+					Base:   uints.NewU8Array(b),
+					Exp:    uints.NewU8Array(e),
+					Mod:    uints.NewU8Array(m),
+					Result: uints.NewU8Array(res),
+				}
+				err := test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField())
+				assert.NoError(err)
+
+				if len(res) > 0 {
+					assignment.Result[0] = uints.NewU8(res[0] ^ 1)
+					err = test.IsSolved(circuit, assignment, ecc.BLS12_377.ScalarField())
+					assert.Error(err)
+				}
+			}, fmt.Sprintf("lengths=%v/zero=%t", lengths, zeroModulus))
+		}
+	}
+}
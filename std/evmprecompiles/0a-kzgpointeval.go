@@ -0,0 +1,113 @@
+package evmprecompiles
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// blobCommitmentVersionKZG is the version byte of the versioned hashes of the
+// blob commitments.
+const blobCommitmentVersionKZG = 0x01
+
+// KZGPointEval implements [POINT_EVALUATION] precompile contract at address
+// 0x0A, introduced by EIP-4844.
+//
+// It asserts that versionedHash is the versioned hash of the commitment and
+// that proof is a valid KZG proof that the polynomial committed to evaluates to
+// y at z. The commitment and the proof are checked to be in G1. vk must be the
+// verifying key of the setup of the Ethereum KZG ceremony, see
+// [kzg.ValueOfVerifyingKey]. The output of the precompile is a constant (the
+// number of field elements per blob and the modulus of the scalar field) and
+// is not returned.
+//
+// The scalars z and y are given in their canonical form, which is checked. The
+// commitments and proofs at infinity are not supported. The witness of the
+// arguments is obtained from the input of the precompile with
+// [ValueOfKZGPointEval], and [VerifyKZGPointEval] performs the same checks
+// natively.
+//
+// [POINT_EVALUATION]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func KZGPointEval(api frontend.API, versionedHash [32]uints.U8, z, y *emulated.Element[sw_bls12381.ScalarField], commitment, proof *sw_bls12381.G1Affine, vk kzg.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine]) {
+	fr, err := emulated.NewField[sw_bls12381.ScalarField](api)
+	if err != nil {
+		panic(fmt.Sprintf("new scalar field: %v", err))
+	}
+	fp, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		panic(fmt.Sprintf("new base field: %v", err))
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		panic(fmt.Sprintf("new pairing: %v", err))
+	}
+	verifier, err := kzg.NewVerifier[sw_bls12381.ScalarField, sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl](api)
+	if err != nil {
+		panic(fmt.Sprintf("new verifier: %v", err))
+	}
+
+	// 1. the versioned hash is 0x01 ‖ sha256(commitment)[1:]
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new hasher: %v", err))
+	}
+	h.Write(compressG1(api, fp, commitment))
+	digest := h.Sum()
+	api.AssertIsEqual(versionedHash[0].Val, blobCommitmentVersionKZG)
+	for i := 1; i < len(versionedHash); i++ {
+		api.AssertIsEqual(versionedHash[i].Val, digest[i].Val)
+	}
+
+	// 2. z and y are canonical, the points are in G1
+	fr.AssertIsInRange(z)
+	fr.AssertIsInRange(y)
+	pairing.AssertIsOnG1(commitment)
+	pairing.AssertIsOnG1(proof)
+
+	// 3. the opening proof is valid
+	if err := verifier.CheckOpeningProof(
+		kzg.Commitment[sw_bls12381.G1Affine]{G1El: *commitment},
+		kzg.OpeningProof[sw_bls12381.ScalarField, sw_bls12381.G1Affine]{Quotient: *proof, ClaimedValue: *y},
+		*z, vk); err != nil {
+		panic(fmt.Sprintf("check opening proof: %v", err))
+	}
+}
+
+// compressG1 returns the 48 bytes of the compressed encoding of the point as in
+// the Zcash serialization of BLS12-381 points: the big-endian x-coordinate,
+// with the three most significant bits set to the compression flag, the
+// infinity flag and the sign of y.
+func compressG1(api frontend.API, fp *emulated.Field[sw_bls12381.BaseField], p *sw_bls12381.G1Affine) []uints.U8 {
+	var params sw_bls12381.BaseField
+	nbBits := params.Modulus().BitLen()
+
+	x := fp.Reduce(&p.X)
+	fp.AssertIsInRange(x)
+	xBits := fp.ToBits(x)[:nbBits]
+
+	// y is lexicographically largest if y > (p-1)/2
+	halfP := new(big.Int).Rsh(params.Modulus(), 1)
+	yIsLargest := fp.IsLess(fp.NewElement(halfP), &p.Y)
+	isInfinity := api.And(fp.IsZero(&p.X), fp.IsZero(&p.Y))
+
+	bits := make([]frontend.Variable, 384)
+	copy(bits, xBits)
+	for i := nbBits; i < len(bits); i++ {
+		bits[i] = 0
+	}
+	bits[383], bits[382], bits[381] = 1, isInfinity, yIsLargest
+
+	res := make([]uints.U8, 48)
+	for i := range res {
+		// the i-th byte from the most significant one
+		j := 8 * (len(res) - 1 - i)
+		res[i] = uints.U8{Val: api.FromBinary(bits[j : j+8]...)}
+	}
+	return res
+}
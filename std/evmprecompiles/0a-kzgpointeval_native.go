@@ -0,0 +1,168 @@
+package evmprecompiles
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// FieldElementsPerBlob is the number of scalars of an EIP-4844 blob.
+	FieldElementsPerBlob = 4096
+	// BytesPerBlob is the size of an EIP-4844 blob.
+	BytesPerBlob = FieldElementsPerBlob * fr.Bytes
+	// KZGPointEvalInputSize is the size of the input of the POINT_EVALUATION
+	// precompile: versioned hash ‖ z ‖ y ‖ commitment ‖ proof.
+	KZGPointEvalInputSize = 32 + 2*fr.Bytes + 2*bls12381.SizeOfG1AffineCompressed
+)
+
+// BlobToPolynomial returns the coefficients of the polynomial of the blob. As
+// in EIP-4844, the blob is the sequence of the big-endian canonical encodings of
+// the evaluations of the polynomial on the roots of unity of order
+// [FieldElementsPerBlob], in bit-reversed order.
+func BlobToPolynomial(blob []byte) ([]fr.Element, error) {
+	if len(blob) != BytesPerBlob {
+		return nil, fmt.Errorf("blob of %d bytes, expected %d", len(blob), BytesPerBlob)
+	}
+	p := make([]fr.Element, FieldElementsPerBlob)
+	for i := range p {
+		if err := p[i].SetBytesCanonical(blob[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, fmt.Errorf("scalar %d: %w", i, err)
+		}
+	}
+	// the generator of the domain is 7^((r-1)/4096), as in the consensus specs
+	domain := fft.NewDomain(FieldElementsPerBlob)
+	domain.FFTInverse(p, fft.DIT)
+	return p, nil
+}
+
+// BlobCommitment returns the KZG commitment to the blob. pk must be the
+// proving key of the setup of the Ethereum KZG ceremony, in monomial form.
+func BlobCommitment(blob []byte, pk kzg_bls12381.ProvingKey) (kzg_bls12381.Digest, error) {
+	p, err := BlobToPolynomial(blob)
+	if err != nil {
+		return kzg_bls12381.Digest{}, err
+	}
+	return kzg_bls12381.Commit(p, pk)
+}
+
+// BlobProof returns the KZG proof of the evaluation of the polynomial of the
+// blob at z. The evaluation is the ClaimedValue of the proof.
+func BlobProof(blob []byte, z fr.Element, pk kzg_bls12381.ProvingKey) (kzg_bls12381.OpeningProof, error) {
+	p, err := BlobToPolynomial(blob)
+	if err != nil {
+		return kzg_bls12381.OpeningProof{}, err
+	}
+	return kzg_bls12381.Open(p, z, pk)
+}
+
+// VersionedHash returns the versioned hash of the commitment, 0x01 ‖
+// sha256(commitment)[1:], as checked by [KZGPointEval].
+func VersionedHash(commitment kzg_bls12381.Digest) [32]byte {
+	compressed := commitment.Bytes()
+	h := sha256.Sum256(compressed[:])
+	h[0] = blobCommitmentVersionKZG
+	return h
+}
+
+// KZGPointEvalInput returns the input of the POINT_EVALUATION precompile
+// proving the evaluation of the committed polynomial at z.
+func KZGPointEvalInput(commitment kzg_bls12381.Digest, z fr.Element, proof kzg_bls12381.OpeningProof) []byte {
+	res := make([]byte, 0, KZGPointEvalInputSize)
+	versionedHash := VersionedHash(commitment)
+	zBytes, yBytes := z.Bytes(), proof.ClaimedValue.Bytes()
+	commitmentBytes, proofBytes := commitment.Bytes(), proof.H.Bytes()
+	res = append(res, versionedHash[:]...)
+	res = append(res, zBytes[:]...)
+	res = append(res, yBytes[:]...)
+	res = append(res, commitmentBytes[:]...)
+	res = append(res, proofBytes[:]...)
+	return res
+}
+
+// kzgPointEvalInput is the decoded input of the POINT_EVALUATION precompile.
+type kzgPointEvalInput struct {
+	versionedHash     [32]byte
+	z, y              fr.Element
+	commitment, proof bls12381.G1Affine
+}
+
+// parseKZGPointEvalInput decodes the input with the checks of the precompile
+// on the encodings: the scalars are canonical and the points are compressed
+// points of G1.
+func parseKZGPointEvalInput(input []byte) (kzgPointEvalInput, error) {
+	var res kzgPointEvalInput
+	if len(input) != KZGPointEvalInputSize {
+		return res, fmt.Errorf("input of %d bytes, expected %d", len(input), KZGPointEvalInputSize)
+	}
+	copy(res.versionedHash[:], input[:32])
+	input = input[32:]
+	if err := res.z.SetBytesCanonical(input[:fr.Bytes]); err != nil {
+		return res, fmt.Errorf("z: %w", err)
+	}
+	input = input[fr.Bytes:]
+	if err := res.y.SetBytesCanonical(input[:fr.Bytes]); err != nil {
+		return res, fmt.Errorf("y: %w", err)
+	}
+	input = input[fr.Bytes:]
+	if _, err := res.commitment.SetBytes(input[:bls12381.SizeOfG1AffineCompressed]); err != nil {
+		return res, fmt.Errorf("commitment: %w", err)
+	}
+	input = input[bls12381.SizeOfG1AffineCompressed:]
+	if _, err := res.proof.SetBytes(input); err != nil {
+		return res, fmt.Errorf("proof: %w", err)
+	}
+	if res.commitment.IsInfinity() || res.proof.IsInfinity() {
+		return res, errors.New("points at infinity are not supported")
+	}
+	return res, nil
+}
+
+// VerifyKZGPointEval is the native counterpart of [KZGPointEval]: it returns an
+// error if the input of the POINT_EVALUATION precompile is rejected by the
+// gadget.
+func VerifyKZGPointEval(input []byte, vk kzg_bls12381.VerifyingKey) error {
+	in, err := parseKZGPointEvalInput(input)
+	if err != nil {
+		return err
+	}
+	if in.versionedHash != VersionedHash(in.commitment) {
+		return errors.New("versioned hash mismatch")
+	}
+	return kzg_bls12381.Verify(&in.commitment, &kzg_bls12381.OpeningProof{H: in.proof, ClaimedValue: in.y}, in.z, vk)
+}
+
+// KZGPointEvalWitness is the witness of the arguments of [KZGPointEval], see
+// [ValueOfKZGPointEval].
+type KZGPointEvalWitness struct {
+	VersionedHash     [32]uints.U8
+	Z, Y              emulated.Element[sw_bls12381.ScalarField]
+	Commitment, Proof sw_bls12381.G1Affine
+}
+
+// ValueOfKZGPointEval returns the witness of the arguments of [KZGPointEval]
+// from the input of the POINT_EVALUATION precompile. It returns an error if the
+// input is malformed; the versioned hash and the opening are checked in-circuit
+// only.
+func ValueOfKZGPointEval(input []byte) (KZGPointEvalWitness, error) {
+	in, err := parseKZGPointEvalInput(input)
+	if err != nil {
+		return KZGPointEvalWitness{}, err
+	}
+	res := KZGPointEvalWitness{
+		Z:          sw_bls12381.NewScalar(in.z),
+		Y:          sw_bls12381.NewScalar(in.y),
+		Commitment: sw_bls12381.NewG1Affine(in.commitment),
+		Proof:      sw_bls12381.NewG1Affine(in.proof),
+	}
+	copy(res.VersionedHash[:], uints.NewU8Array(in.versionedHash[:]))
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package evmprecompiles
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type kzgPointEvalCircuit struct {
+	Input KZGPointEvalWitness
+	Vk    kzg.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine]
+}
+
+func (c *kzgPointEvalCircuit) Define(api frontend.API) error {
+	KZGPointEval(api, c.Input.VersionedHash, &c.Input.Z, &c.Input.Y, &c.Input.Commitment, &c.Input.Proof, c.Vk)
+	return nil
+}
+
+func randomBlob() []byte {
+	blob := make([]byte, 0, BytesPerBlob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var e fr.Element
+		e.SetRandom()
+		b := e.Bytes()
+		blob = append(blob, b[:]...)
+	}
+	return blob
+}
+
+func TestKZGPointEvalNative(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	srs, err := kzg_bls12381.NewSRS(FieldElementsPerBlob, big.NewInt(42))
+	assert.NoError(err)
+	blob := randomBlob()
+	commitment, err := BlobCommitment(blob, srs.Pk)
+	assert.NoError(err)
+
+	// the blob is in bit-reversed order: its second scalar is the evaluation at
+	// ω²⁰⁴⁸ = -1
+	var z fr.Element
+	z.SetOne().Neg(&z)
+	proof, err := BlobProof(blob, z, srs.Pk)
+	assert.NoError(err)
+	assert.Equal(blob[fr.Bytes:2*fr.Bytes], proof.ClaimedValue.Marshal())
+
+	input := KZGPointEvalInput(commitment, z, proof)
+	assert.Len(input, KZGPointEvalInputSize)
+	assert.NoError(VerifyKZGPointEval(input, srs.Vk))
+
+	// wrong evaluation
+	wrongEval := append([]byte{}, input...)
+	wrongEval[32+2*fr.Bytes-1] ^= 1
+	assert.Error(VerifyKZGPointEval(wrongEval, srs.Vk))
+
+	// wrong version
+	wrongHash := append([]byte{}, input...)
+	wrongHash[0] = 0x02
+	assert.Error(VerifyKZGPointEval(wrongHash, srs.Vk))
+
+	// non-canonical z
+	nonCanonical := append([]byte{}, input...)
+	for i := 32; i < 32+fr.Bytes; i++ {
+		nonCanonical[i] = 0xff
+	}
+	assert.Error(VerifyKZGPointEval(nonCanonical, srs.Vk))
+	_, err = ValueOfKZGPointEval(nonCanonical)
+	assert.Error(err)
+}
+
+func TestKZGPointEval(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	srs, err := kzg_bls12381.NewSRS(FieldElementsPerBlob, big.NewInt(42))
+	assert.NoError(err)
+	blob := randomBlob()
+	commitment, err := BlobCommitment(blob, srs.Pk)
+	assert.NoError(err)
+	var z fr.Element
+	z.SetRandom()
+	proof, err := BlobProof(blob, z, srs.Pk)
+	assert.NoError(err)
+	input := KZGPointEvalInput(commitment, z, proof)
+	assert.NoError(VerifyKZGPointEval(input, srs.Vk))
+
+	vk, err := kzg.ValueOfVerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine](srs.Vk)
+	assert.NoError(err)
+	w, err := ValueOfKZGPointEval(input)
+	assert.NoError(err)
+	assignment := kzgPointEvalCircuit{Input: w, Vk: vk}
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong evaluation
+	var y fr.Element
+	y.Add(&proof.ClaimedValue, new(fr.Element).SetOne())
+	wrongEval := assignment
+	wrongEval.Input.Y = sw_bls12381.NewScalar(y)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &wrongEval, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// wrong version
+	wrongHash := assignment
+	wrongHash.Input.VersionedHash[0] = uints.NewU8(0x02)
+	err = test.IsSolved(&kzgPointEvalCircuit{}, &wrongHash, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -7,11 +7,12 @@
 //  2. SHA256 ❌ -- in progress
 //  3. RIPEMD160 ❌ -- postponed
 //  4. ID ❌ -- trivial to implement without function
-//  5. EXPMOD ✅ -- function [Expmod]
+//  5. EXPMOD ✅ -- functions [Expmod] and [ExpmodBytes]
 //  6. BN_ADD ✅ -- function [ECAdd]
 //  7. BN_MUL ✅ -- function [ECMul]
 //  8. SNARKV ✅ -- function [ECPair]
 //  9. BLAKE2F ❌ -- postponed
+//  10. POINT_EVALUATION ✅ -- function [KZGPointEval]
 //
 // This package uses local representation for the arguments. It is up to the
 // user to instantiate corresponding types from their application-specific data.
@@ -17,7 +17,7 @@ func init() {
 
 // GetHints returns all the hints used in this package.
 func GetHints() []solver.Hint {
-	return []solver.Hint{recoverPublicKeyHint}
+	return []solver.Hint{recoverPublicKeyHint, modReduceHint}
 }
 
 func recoverPublicKeyHintArgs(msg emulated.Element[emulated.Secp256k1Fr],
@@ -77,3 +77,17 @@ func recoverPublicKeyHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) err
 	outputs[2*emfp.NbLimbs()].SetInt64(int64(isZero))
 	return nil
 }
+
+// modReduceHint returns the first input modulo the second one.
+func modReduceHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(_ *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 2 || len(outputs) != 1 {
+			return fmt.Errorf("expected 2 inputs and 1 output, got %d and %d", len(inputs), len(outputs))
+		}
+		if inputs[1].Sign() == 0 {
+			return fmt.Errorf("modulus is zero")
+		}
+		outputs[0].Mod(inputs[0], inputs[1])
+		return nil
+	})
+}
@@ -0,0 +1,133 @@
+package hash
+
+import (
+	stdhash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// ByteHasher absorbs a message of bytes into a [FieldHasher], such as MiMC.
+//
+// The bytes are packed into field elements of ChunkSize bytes, in big-endian
+// order, after padding the message with the byte 0x80 and then zero bytes to a
+// multiple of ChunkSize. The padding is always added, so that messages of any
+// length are encoded injectively and the encoding doesn't depend on how the
+// message was split across the calls to Write. The native counterpart is
+// [NewNativeByteHasher].
+type ByteHasher struct {
+	api      frontend.API
+	h        FieldHasher
+	rchecker frontend.Rangechecker
+	buf      []uints.U8
+}
+
+// NewByteHasher returns a [ByteHasher] writing to h. The state of h is shared,
+// so h should not be written directly until the message is hashed.
+func NewByteHasher(api frontend.API, h FieldHasher) *ByteHasher {
+	return &ByteHasher{api: api, h: h, rchecker: rangecheck.New(api)}
+}
+
+// ChunkSize returns the number of bytes packed in a field element for a field
+// of bitlength fieldBitLen, so that the packed value is less than the modulus.
+func ChunkSize(fieldBitLen int) int {
+	return (fieldBitLen - 1) / 8
+}
+
+// Write writes more bytes of the message. The bytes are range checked.
+// The complete chunks are written to the underlying hasher.
+func (h *ByteHasher) Write(data []uints.U8) {
+	chunkSize := ChunkSize(h.api.Compiler().FieldBitLen())
+	for i := range data {
+		h.rchecker.Check(data[i].Val, 8)
+		h.buf = append(h.buf, data[i])
+		if len(h.buf) == chunkSize {
+			h.h.Write(h.pack(h.buf))
+			h.buf = h.buf[:0]
+		}
+	}
+}
+
+// Sum pads the message, writes the last chunk to the underlying hasher and
+// returns its digest. As for the underlying hasher, the hasher must be reset
+// before hashing another message.
+func (h *ByteHasher) Sum() frontend.Variable {
+	chunkSize := ChunkSize(h.api.Compiler().FieldBitLen())
+	last := append(h.buf, uints.NewU8(0x80))
+	for len(last) < chunkSize {
+		last = append(last, uints.NewU8(0))
+	}
+	h.h.Write(h.pack(last))
+	h.buf = nil
+	return h.h.Sum()
+}
+
+// Reset empties the message and resets the underlying hasher.
+func (h *ByteHasher) Reset() {
+	h.buf = nil
+	h.h.Reset()
+}
+
+// pack returns Σ 256**(n-1-i) * chunk[i].
+func (h *ByteHasher) pack(chunk []uints.U8) frontend.Variable {
+	res := frontend.Variable(0)
+	for i := range chunk {
+		res = h.api.Add(h.api.Mul(res, 256), chunk[i].Val)
+	}
+	return res
+}
+
+type nativeByteHasher struct {
+	h         stdhash.Hash
+	chunkSize int
+	msg       []byte
+}
+
+// NewNativeByteHasher returns a hash computing out of circuit the digests of
+// [ByteHasher] over the field with modulus field. h is the native counterpart
+// of the [FieldHasher], which takes as input the field elements in big-endian
+// encoding of h.BlockSize() bytes, such as the MiMC implementations of
+// gnark-crypto.
+//
+// Unlike in circuit, Sum doesn't change the state, as for any hash.Hash: the
+// message is buffered, and Sum hashes a padded copy of it from a reset state of
+// h. h is owned by the returned hash.
+func NewNativeByteHasher(h stdhash.Hash, field *big.Int) stdhash.Hash {
+	return &nativeByteHasher{h: h, chunkSize: ChunkSize(field.BitLen())}
+}
+
+func (h *nativeByteHasher) Write(p []byte) (int, error) {
+	h.msg = append(h.msg, p...)
+	return len(p), nil
+}
+
+func (h *nativeByteHasher) Sum(b []byte) []byte {
+	padded := make([]byte, (len(h.msg)/h.chunkSize+1)*h.chunkSize)
+	copy(padded, h.msg)
+	padded[len(h.msg)] = 0x80
+	h.h.Reset()
+	elem := make([]byte, h.h.BlockSize())
+	for i := 0; i < len(padded); i += h.chunkSize {
+		// the chunk as a field element, in big-endian
+		copy(elem[len(elem)-h.chunkSize:], padded[i:i+h.chunkSize])
+		if _, err := h.h.Write(elem); err != nil {
+			panic(err)
+		}
+	}
+	return h.h.Sum(b)
+}
+
+func (h *nativeByteHasher) Reset() {
+	h.msg = h.msg[:0]
+	h.h.Reset()
+}
+
+func (h *nativeByteHasher) Size() int {
+	return h.h.Size()
+}
+
+func (h *nativeByteHasher) BlockSize() int {
+	return h.chunkSize
+}
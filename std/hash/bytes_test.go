@@ -0,0 +1,79 @@
+package hash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type byteHasherCircuit struct {
+	In       []uints.U8
+	Expected frontend.Variable
+}
+
+func (c *byteHasherCircuit) Define(api frontend.API) error {
+	m, err := stdmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h := hash.NewByteHasher(api, &m)
+	// the encoding doesn't depend on the split of the message
+	h.Write(c.In[:len(c.In)/3])
+	h.Write(c.In[len(c.In)/3:])
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestByteHasher(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, n := range []int{0, 1, 30, 31, 32, 62, 100} {
+		msg := make([]byte, n)
+		for i := range msg {
+			msg[i] = byte(i*7 + 3)
+		}
+		h := hash.NewNativeByteHasher(mimc.NewMiMC(), ecc.BN254.ScalarField())
+		h.Write(msg)
+		expected := h.Sum(nil)
+
+		h.Reset()
+		h.Write(append(msg, 0x80))
+		other := h.Sum(nil)
+		assert.NotEqual(expected, other, "padding must be injective")
+
+		assert.Run(func(assert *test.Assert) {
+			assert.CheckCircuit(&byteHasherCircuit{In: make([]uints.U8, n)},
+				test.WithValidAssignment(&byteHasherCircuit{In: uints.NewU8Array(msg), Expected: expected}),
+				test.WithInvalidAssignment(&byteHasherCircuit{In: uints.NewU8Array(msg), Expected: other}),
+				test.WithCurves(ecc.BN254))
+		}, fmt.Sprintf("len=%d", n))
+	}
+}
+
+func TestNativeByteHasherSum(t *testing.T) {
+	assert := require.New(t)
+	msg := []byte("the quick brown fox jumps over the lazy dog")
+	h := hash.NewNativeByteHasher(mimc.NewMiMC(), ecc.BN254.ScalarField())
+	h.Write(msg)
+	expected := h.Sum(nil)
+
+	// Sum doesn't change the state
+	assert.Equal(expected, h.Sum(nil))
+
+	// and the message continues after Sum
+	h.Write(msg)
+	h2 := hash.NewNativeByteHasher(mimc.NewMiMC(), ecc.BN254.ScalarField())
+	h2.Write(append(msg, msg...))
+	assert.Equal(h2.Sum(nil), h.Sum(nil))
+
+	h.Reset()
+	h.Write(msg)
+	assert.Equal(expected, h.Sum(nil))
+}
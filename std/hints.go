@@ -18,6 +18,9 @@ import (
 	"github.com/consensys/gnark/std/math/bitslice"
 	"github.com/consensys/gnark/std/math/cmp"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/matrix"
+	"github.com/consensys/gnark/std/ml"
+	"github.com/consensys/gnark/std/multiset"
 	"github.com/consensys/gnark/std/rangecheck"
 	"github.com/consensys/gnark/std/selector"
 )
@@ -42,6 +45,9 @@ func registerHints() {
 	solver.RegisterHint(evmprecompiles.GetHints()...)
 	solver.RegisterHint(logderivarg.GetHints()...)
 	solver.RegisterHint(bitslice.GetHints()...)
+	solver.RegisterHint(matrix.GetHints()...)
+	solver.RegisterHint(ml.GetHints()...)
+	solver.RegisterHint(multiset.GetHints()...)
 	// emulated fields
 	solver.RegisterHint(fields_bls12381.GetHints()...)
 	solver.RegisterHint(fields_bn254.GetHints()...)
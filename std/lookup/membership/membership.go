@@ -0,0 +1,171 @@
+// Package membership implements the assertion that a variable belongs to a
+// set of constants.
+//
+// The naive check asserts that ∏_{s∈S} (x-s) == 0, which costs |S|-1
+// multiplications for each checked variable. Instead, the queries against the
+// same set are collected during the circuit definition and, when the circuit
+// is compiled, checked with a log-derivative argument [Haböck22] over the set
+// as a table: the cost is then linear in |S| plus the number of queries. The
+// builder must implement [frontend.Committer] for the argument and it is only
+// used when it is cheaper than the product of differences, which is used
+// otherwise.
+//
+// [Haböck22]: https://eprint.iacr.org/2022/1530
+package membership
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+)
+
+type ctxCheckerKey struct{}
+
+// AssertIsInSet asserts that v is one of the elements of set. The elements are
+// reduced modulo the field and their order and multiplicity don't matter. Only
+// the sets of constants are checked with the lookup argument, the others with
+// the product of differences.
+func AssertIsInSet(api frontend.API, v frontend.Variable, set []frontend.Variable) {
+	if len(set) == 0 {
+		panic("empty set")
+	}
+	elems, ok := normalize(api, set)
+	if !ok {
+		prod := api.Sub(v, set[0])
+		for i := 1; i < len(set); i++ {
+			prod = api.Mul(prod, api.Sub(v, set[i]))
+		}
+		api.AssertIsEqual(prod, 0)
+		return
+	}
+	if vc, ok := api.Compiler().ConstantValue(v); ok {
+		vc = new(big.Int).Mod(vc, api.Compiler().Field())
+		for i := range elems {
+			if elems[i].Cmp(vc) == 0 {
+				return
+			}
+		}
+		panic(fmt.Sprintf("constant %s is not in the set", vc))
+	}
+	if len(elems) == 1 {
+		api.AssertIsEqual(v, elems[0])
+		return
+	}
+	newChecker(api).add(elems, v)
+}
+
+// normalize returns the elements of set, reduced modulo the field, sorted and
+// deduplicated. It returns false if an element is not a constant.
+func normalize(api frontend.API, set []frontend.Variable) ([]*big.Int, bool) {
+	field := api.Compiler().Field()
+	elems := make([]*big.Int, len(set))
+	for i := range set {
+		c, ok := api.Compiler().ConstantValue(set[i])
+		if !ok {
+			return nil, false
+		}
+		elems[i] = new(big.Int).Mod(c, field)
+	}
+	slices.SortFunc(elems, func(a, b *big.Int) int { return a.Cmp(b) })
+	return slices.CompactFunc(elems, func(a, b *big.Int) bool { return a.Cmp(b) == 0 }), true
+}
+
+// queries are the variables checked against the same set.
+type queries struct {
+	set []*big.Int
+	vs  []frontend.Variable
+}
+
+type checker struct {
+	sets   map[string]*queries
+	keys   []string // in insertion order, for deterministic compilation
+	closed bool
+}
+
+func newChecker(api frontend.API) *checker {
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		panic("builder should implement key-value store")
+	}
+	if c := kv.GetKeyValue(ctxCheckerKey{}); c != nil {
+		if ct, ok := c.(*checker); ok {
+			return ct
+		}
+		panic("stored membership checker is not valid")
+	}
+	c := &checker{sets: make(map[string]*queries)}
+	kv.SetKeyValue(ctxCheckerKey{}, c)
+	api.Compiler().Defer(c.build)
+	return c
+}
+
+func (c *checker) add(set []*big.Int, v frontend.Variable) {
+	if c.closed {
+		panic("membership checker already closed")
+	}
+	var sb strings.Builder
+	for i := range set {
+		sb.WriteString(set[i].Text(16))
+		sb.WriteByte(',')
+	}
+	key := sb.String()
+	q, ok := c.sets[key]
+	if !ok {
+		q = &queries{set: set}
+		c.sets[key] = q
+		c.keys = append(c.keys, key)
+	}
+	q.vs = append(q.vs, v)
+}
+
+func (c *checker) build(api frontend.API) error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	_, committer := api.(frontend.Committer)
+	for _, key := range c.keys {
+		q := c.sets[key]
+		if committer && lookupCost(len(q.set), len(q.vs)) < productCost(len(q.set), len(q.vs)) {
+			table := make([]frontend.Variable, len(q.set))
+			for i := range q.set {
+				table[i] = q.set[i]
+			}
+			if err := logderivarg.Build(api, logderivarg.AsTable(table), logderivarg.AsTable(q.vs)); err != nil {
+				return fmt.Errorf("build set membership argument: %w", err)
+			}
+			continue
+		}
+		for _, v := range q.vs {
+			assertProduct(api, v, q.set)
+		}
+	}
+	return nil
+}
+
+// assertProduct asserts that ∏_{s∈set} (v-s) == 0.
+func assertProduct(api frontend.API, v frontend.Variable, set []*big.Int) {
+	prod := api.Sub(v, set[0])
+	for i := 1; i < len(set); i++ {
+		prod = api.Mul(prod, api.Sub(v, set[i]))
+	}
+	api.AssertIsEqual(prod, 0)
+}
+
+// productCost is the approximate number of constraints of the product of
+// differences.
+func productCost(nbSet, nbQueries int) int {
+	return nbQueries * nbSet
+}
+
+// lookupCost is the approximate number of constraints of the log-derivative
+// argument: a division per set element, an inversion per query and the
+// commitment.
+func lookupCost(nbSet, nbQueries int) int {
+	return nbSet + 2*nbQueries + 8
+}
@@ -0,0 +1,71 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+var primes = []frontend.Variable{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97}
+
+type setCircuit struct {
+	X []frontend.Variable
+}
+
+func (c *setCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		AssertIsInSet(api, c.X[i], primes)
+	}
+	// constant members don't add constraints
+	AssertIsInSet(api, 97, primes)
+	return nil
+}
+
+func TestAssertIsInSet(t *testing.T) {
+	assert := test.NewAssert(t)
+	// with a single query the product of differences is used, with more the
+	// lookup argument.
+	for _, nbQueries := range []int{1, 10} {
+		valid, invalid := &setCircuit{X: make([]frontend.Variable, nbQueries)}, &setCircuit{X: make([]frontend.Variable, nbQueries)}
+		for i := range valid.X {
+			valid.X[i] = primes[(3*i)%len(primes)]
+			invalid.X[i] = primes[i%len(primes)]
+		}
+		invalid.X[nbQueries-1] = 4
+		assert.CheckCircuit(&setCircuit{X: make([]frontend.Variable, nbQueries)},
+			test.WithValidAssignment(valid),
+			test.WithInvalidAssignment(invalid),
+			test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+	}
+}
+
+type naiveCircuit struct {
+	X []frontend.Variable
+}
+
+func (c *naiveCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		prod := frontend.Variable(1)
+		for _, p := range primes {
+			prod = api.Mul(prod, api.Sub(c.X[i], p))
+		}
+		api.AssertIsEqual(prod, 0)
+	}
+	return nil
+}
+
+func TestLookupCost(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbQueries = 100
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		lookup, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &setCircuit{X: make([]frontend.Variable, nbQueries)})
+		assert.NoError(err)
+		naive, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &naiveCircuit{X: make([]frontend.Variable, nbQueries)})
+		assert.NoError(err)
+		assert.Less(lookup.GetNbConstraints(), naive.GetNbConstraints()/4)
+	}
+}
@@ -0,0 +1,98 @@
+// Package loop implements loops with a data-dependent number of iterations,
+// unrolled to a bound.
+package loop
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/logger"
+)
+
+// Body is the body of a loop of [Bounded]: it returns the state after the
+// iteration i. active is 1 if the iteration runs, and 0 if the loop already
+// exited.
+type Body func(i int, active frontend.Variable, state []frontend.Variable) []frontend.Variable
+
+// Condition is the condition of a loop of [Bounded], the loop running while it
+// returns 1.
+type Condition func(state []frontend.Variable) frontend.Variable
+
+// constraintCounter is implemented by the builders which report the number of
+// constraints of the system being built.
+type constraintCounter interface {
+	GetNbConstraints() int
+}
+
+// Bounded runs the loop
+//
+//	for i := 0; cond(state) == 1; i++ {
+//		state = body(i, active, state)
+//	}
+//
+// and returns the final state. The loop is unrolled to maxIter iterations, the
+// iterations after the condition becomes false leaving the state unchanged,
+// and the circuit is not satisfied if the condition is still true after maxIter
+// iterations. The condition must return a boolean, which is asserted.
+//
+// The body is called for all the iterations, with active set to 1 while the
+// loop runs and to 0 after it exits, its result being discarded then. The
+// assertions of the body must thus hold on the discarded iterations, or be
+// conditioned by active, for example:
+//
+//	api.AssertIsEqual(api.Mul(active, api.Sub(x, y)), 0)
+//
+// When compiling, the number of constraints of the loop is logged at debug
+// level with the location of the call.
+func Bounded(api frontend.API, maxIter int, state []frontend.Variable, body Body, cond Condition) []frontend.Variable {
+	if maxIter < 0 {
+		panic(fmt.Sprintf("bounded loop: negative number of iterations %d", maxIter))
+	}
+	counter, hasCounter := api.Compiler().(constraintCounter)
+	var start int
+	if hasCounter {
+		start = counter.GetNbConstraints()
+	}
+
+	state = append([]frontend.Variable(nil), state...)
+	condition := func() frontend.Variable {
+		c := cond(state)
+		api.AssertIsBoolean(c)
+		return c
+	}
+
+	// once the condition is false, the state is not updated anymore, so that
+	// the condition stays false
+	active := condition()
+	for i := 0; i < maxIter; i++ {
+		next := body(i, active, state)
+		if len(next) != len(state) {
+			panic(fmt.Sprintf("bounded loop: body returned %d variables, expected %d", len(next), len(state)))
+		}
+		for j := range state {
+			state[j] = api.Select(active, next[j], state[j])
+		}
+		active = api.Mul(active, condition())
+	}
+	if m, ok := api.(frontend.MessageAsserter); ok {
+		m.AssertIsEqualWithMessage(active, 0, "bounded loop: condition still true after %d iterations", maxIter)
+	} else {
+		api.AssertIsEqual(active, 0)
+	}
+
+	if hasCounter {
+		cost := counter.GetNbConstraints() - start
+		log := logger.Logger()
+		e := log.Debug().Int("iterations", maxIter).Int("nbConstraints", cost)
+		if maxIter > 0 {
+			e = e.Int("perIteration", cost/maxIter)
+		}
+		if _, file, line, ok := runtime.Caller(1); ok {
+			e = e.Str("caller", fmt.Sprintf("%s:%d", filepath.Base(file), line))
+		}
+		e.Msg("bounded loop")
+	}
+	return state
+}
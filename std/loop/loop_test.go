@@ -0,0 +1,68 @@
+package loop_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/loop"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// boundedLoopCircuit sums the integers from N down to 1.
+type boundedLoopCircuit struct {
+	N   frontend.Variable
+	Sum frontend.Variable `gnark:",public"`
+}
+
+func (c *boundedLoopCircuit) Define(api frontend.API) error {
+	res := loop.Bounded(api, 10, []frontend.Variable{c.N, 0},
+		func(i int, active frontend.Variable, state []frontend.Variable) []frontend.Variable {
+			// only holds while the loop runs
+			api.AssertIsEqual(api.Mul(active, api.IsZero(state[0])), 0)
+			return []frontend.Variable{api.Sub(state[0], 1), api.Add(state[1], state[0])}
+		},
+		func(state []frontend.Variable) frontend.Variable {
+			return api.Sub(1, api.IsZero(state[0]))
+		})
+	api.AssertIsEqual(res[0], 0)
+	api.AssertIsEqual(res[1], c.Sum)
+	return nil
+}
+
+func TestBoundedLoop(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(field, newBuilder, &boundedLoopCircuit{})
+			assert.NoError(err)
+			for _, tc := range []struct {
+				n, sum int
+				ok     bool
+			}{
+				{0, 0, true},
+				{4, 10, true},
+				{10, 55, true},
+				{4, 9, false},
+				{11, 66, false}, // more iterations than the bound
+			} {
+				assignment := &boundedLoopCircuit{N: tc.n, Sum: tc.sum}
+				w, err := frontend.NewWitness(assignment, field)
+				assert.NoError(err)
+				_, err = ccs.Solve(w)
+				engineErr := test.IsSolved(&boundedLoopCircuit{}, assignment, field)
+				if tc.ok {
+					assert.NoError(err, tc.n)
+					assert.NoError(engineErr, tc.n)
+				} else {
+					assert.Error(err, tc.n)
+					assert.Error(engineErr, tc.n)
+				}
+			}
+		})
+	}
+}
@@ -15,33 +15,51 @@ const (
 
 	// Ternary base decomposes scalar into trits (0-1-2)
 	Ternary Base = 3
+
+	// Quaternary base decomposes scalar into 2-bit digits (0-3)
+	Quaternary Base = 4
+
+	// Hexadecimal base decomposes scalar into nibbles (0-15)
+	Hexadecimal Base = 16
 )
 
 // ToBase decomposes scalar v into digits in given base using options opts. The
-// decomposition is in little-endian order.
+// decomposition is in little-endian order, unless [WithBigEndian] is set.
 func ToBase(api frontend.API, base Base, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	var digits []frontend.Variable
 	switch base {
 	case Binary:
-		return toBinary(api, v, opts...)
+		digits = toBinary(api, v, opts...)
 	case Ternary:
-		return toTernary(api, v, opts...)
+		digits = toTernary(api, v, opts...)
+	case Quaternary, Hexadecimal:
+		digits = toPowerOfTwo(api, base.bitsPerDigit(), v, opts...)
 	default:
 		panic("not implemented")
 	}
+	if newConfig(opts...).BigEndian {
+		reverse(digits)
+	}
+	return digits
 }
 
 // FromBase compute from a set of digits its canonical representation in
-// little-endian order.
+// little-endian order, unless [WithBigEndian] is set.
 // For example for base 2, it returns Σbi = Σ (2**i * digits[i])
 func FromBase(api frontend.API, base Base, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
 	if len(digits) == 0 {
 		panic("FromBase needs at least 1 digit")
 	}
+	if newConfig(opts...).BigEndian {
+		digits = reversed(digits)
+	}
 	switch base {
 	case Binary:
 		return fromBinary(api, digits, opts...)
 	case Ternary:
 		return fromTernary(api, digits, opts...)
+	case Quaternary, Hexadecimal:
+		return fromPowerOfTwo(api, base.bitsPerDigit(), digits, opts...)
 	default:
 		panic("not implemented")
 	}
@@ -51,10 +69,22 @@ type baseConversionConfig struct {
 	NbDigits             int
 	UnconstrainedOutputs bool
 	UnconstrainedInputs  bool
+	BigEndian            bool
+	Rangechecker         frontend.Rangechecker
 
 	omitModulusCheck bool
 }
 
+func newConfig(opts ...BaseConversionOption) baseConversionConfig {
+	var cfg baseConversionConfig
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			panic(err)
+		}
+	}
+	return cfg
+}
+
 // BaseConversionOption configures the behaviour of scalar decomposition.
 type BaseConversionOption func(opt *baseConversionConfig) error
 
@@ -118,3 +148,42 @@ func OmitModulusCheck() BaseConversionOption {
 		return nil
 	}
 }
+
+// WithBigEndian sets the order of the digits to most significant digit first,
+// both for the digits returned by [ToBase], [ToBytes] and [ConvertDigits] and
+// for the digits given to [FromBase], [FromBytes] and [ConvertDigits].
+func WithBigEndian() BaseConversionOption {
+	return func(opt *baseConversionConfig) error {
+		opt.BigEndian = true
+		return nil
+	}
+}
+
+// WithRangechecker constrains the digits of 2, 4 and 8 bits with rc, typically
+// [github.com/consensys/gnark/std/rangecheck.New], instead of decomposing every
+// digit into bits. The range checker uses a lookup table shared by all the
+// range checks of the circuit, which adds a fixed cost, so this pays off when
+// many digits are constrained, for instance when converting hash inputs to
+// bytes.
+//
+// The full decompositions which need the comparison to the native modulus
+// still use the binary decomposition, see [OmitModulusCheck].
+func WithRangechecker(rc frontend.Rangechecker) BaseConversionOption {
+	return func(opt *baseConversionConfig) error {
+		opt.Rangechecker = rc
+		return nil
+	}
+}
+
+func reverse(s []frontend.Variable) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func reversed(s []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(s))
+	copy(res, s)
+	reverse(res)
+	return res
+}
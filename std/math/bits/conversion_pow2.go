@@ -0,0 +1,228 @@
+package bits
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ToQuaternary is an alias of ToBase(api, Quaternary, v, opts...)
+func ToQuaternary(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	return ToBase(api, Quaternary, v, opts...)
+}
+
+// FromQuaternary is an alias of FromBase(api, Quaternary, digits, opts...)
+func FromQuaternary(api frontend.API, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
+	return FromBase(api, Quaternary, digits, opts...)
+}
+
+// ToHexadecimal is an alias of ToBase(api, Hexadecimal, v, opts...)
+func ToHexadecimal(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	return ToBase(api, Hexadecimal, v, opts...)
+}
+
+// FromHexadecimal is an alias of FromBase(api, Hexadecimal, digits, opts...)
+func FromHexadecimal(api frontend.API, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
+	return FromBase(api, Hexadecimal, digits, opts...)
+}
+
+// ToBytes decomposes scalar v into bytes using options opts, as [ToBase] does
+// for the bases which fit in a [Base].
+func ToBytes(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	digits := toPowerOfTwo(api, 8, v, opts...)
+	if newConfig(opts...).BigEndian {
+		reverse(digits)
+	}
+	return digits
+}
+
+// FromBytes computes from a set of bytes its canonical representation, as
+// [FromBase] does for the bases which fit in a [Base].
+func FromBytes(api frontend.API, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
+	if len(digits) == 0 {
+		panic("FromBytes needs at least 1 digit")
+	}
+	if newConfig(opts...).BigEndian {
+		digits = reversed(digits)
+	}
+	return fromPowerOfTwo(api, 8, digits, opts...)
+}
+
+// ConvertDigits converts digits of fromBits bits to digits of toBits bits,
+// where the sizes are 1, 2, 4 or 8 bits (the bases [Binary], [Quaternary],
+// [Hexadecimal] and bytes), without recomposing the value. Converting to
+// larger digits groups the digits, which is free when the inputs are already
+// constrained (see [WithUnconstrainedInputs]), and the last group is padded
+// with zero digits. Converting to smaller digits decomposes every digit, which
+// also constrains the inputs unless [WithUnconstrainedOutputs] is set.
+//
+// The options [WithBigEndian] and [WithRangechecker] apply, [WithNbDigits] is
+// ignored.
+func ConvertDigits(api frontend.API, fromBits, toBits int, digits []frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	if !isDigitSize(fromBits) || !isDigitSize(toBits) {
+		panic(fmt.Sprintf("conversion from %d-bit to %d-bit digits not implemented", fromBits, toBits))
+	}
+	cfg := newConfig(opts...)
+	if cfg.BigEndian {
+		digits = reversed(digits)
+	}
+	var res []frontend.Variable
+	switch {
+	case fromBits == toBits:
+		res = make([]frontend.Variable, len(digits))
+		copy(res, digits)
+		if !cfg.UnconstrainedInputs {
+			for i := range digits {
+				assertIsDigit(api, fromBits, digits[i], cfg)
+			}
+		}
+	case fromBits < toBits:
+		ratio := toBits / fromBits
+		res = make([]frontend.Variable, 0, (len(digits)+ratio-1)/ratio)
+		for i := 0; i < len(digits); i += ratio {
+			res = append(res, recompose(api, fromBits, digits[i:min(i+ratio, len(digits))], cfg))
+		}
+	default:
+		ratio := fromBits / toBits
+		split := cfg
+		split.NbDigits = ratio
+		res = make([]frontend.Variable, 0, len(digits)*ratio)
+		for i := range digits {
+			if split.UnconstrainedOutputs && !cfg.UnconstrainedInputs {
+				assertIsDigit(api, fromBits, digits[i], cfg)
+			}
+			res = append(res, decompose(api, toBits, digits[i], split)...)
+		}
+	}
+	if cfg.BigEndian {
+		reverse(res)
+	}
+	return res
+}
+
+// bitsPerDigit returns log2(base) if base is a power of two, and 0 otherwise.
+func (b Base) bitsPerDigit() int {
+	switch b {
+	case Binary:
+		return 1
+	case Quaternary:
+		return 2
+	case Hexadecimal:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// isDigitSize returns true if the digits of k bits are supported by
+// [ConvertDigits].
+func isDigitSize(k int) bool {
+	return k == 1 || k == 2 || k == 4 || k == 8
+}
+
+// fromPowerOfTwo recomposes digits of k bits.
+func fromPowerOfTwo(api frontend.API, k int, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
+	return recompose(api, k, digits, newConfig(opts...))
+}
+
+// toPowerOfTwo decomposes v into digits of k bits.
+func toPowerOfTwo(api frontend.API, k int, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	cfg := baseConversionConfig{
+		NbDigits: (api.Compiler().FieldBitLen() + k - 1) / k,
+	}
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			panic(err)
+		}
+	}
+	return decompose(api, k, v, cfg)
+}
+
+// recompose returns Σ (2**(k*i) * digits[i]), constraining the digits to k
+// bits unless cfg.UnconstrainedInputs is set.
+func recompose(api frontend.API, k int, digits []frontend.Variable, cfg baseConversionConfig) frontend.Variable {
+	Σdi := frontend.Variable(0)
+	c := big.NewInt(1)
+	for i := 0; i < len(digits); i++ {
+		if !cfg.UnconstrainedInputs {
+			assertIsDigit(api, k, digits[i], cfg)
+		}
+		Σdi = api.Add(Σdi, api.Mul(c, digits[i])) // no constraint is recorded
+		c.Lsh(c, uint(k))
+	}
+	return Σdi
+}
+
+// decompose returns cfg.NbDigits digits of k bits of v.
+func decompose(api frontend.API, k int, v frontend.Variable, cfg baseConversionConfig) []frontend.Variable {
+	nbBits := cfg.NbDigits * k
+	if k == 1 || (!cfg.UnconstrainedOutputs && !useLookup(api, k, nbBits, cfg)) {
+		// the binary decomposition constrains the bits and checks the
+		// uniqueness of the full decompositions, the digits are then linear
+		// combinations of the bits.
+		binOpts := []BaseConversionOption{WithNbDigits(nbBits)}
+		if cfg.UnconstrainedOutputs {
+			binOpts = append(binOpts, WithUnconstrainedOutputs())
+		}
+		if cfg.omitModulusCheck {
+			binOpts = append(binOpts, OmitModulusCheck())
+		}
+		bits := toBinary(api, v, binOpts...)
+		if k == 1 {
+			return bits
+		}
+		digits := make([]frontend.Variable, cfg.NbDigits)
+		for i := range digits {
+			digits[i] = fromBinary(api, bits[i*k:(i+1)*k], WithUnconstrainedInputs())
+		}
+		return digits
+	}
+
+	digits, err := api.Compiler().NewHint(nDigits, cfg.NbDigits, k, v)
+	if err != nil {
+		panic(err)
+	}
+	Σdi := frontend.Variable(0)
+	c := big.NewInt(1)
+	for i := 0; i < cfg.NbDigits; i++ {
+		Σdi = api.Add(Σdi, api.Mul(digits[i], c))
+		c.Lsh(c, uint(k))
+		if !cfg.UnconstrainedOutputs {
+			assertIsDigit(api, k, digits[i], cfg)
+		}
+	}
+
+	// record the constraint Σ (2**(k*i) * d[i]) == v
+	api.AssertIsEqual(Σdi, v)
+	return digits
+}
+
+// useLookup returns true if the digits of a decomposition into nbBits are
+// constrained with the range checker, which is not the case when the
+// decomposition could wrap around the native modulus.
+func useLookup(api frontend.API, k, nbBits int, cfg baseConversionConfig) bool {
+	if cfg.Rangechecker == nil {
+		return false
+	}
+	fieldBitLen := api.Compiler().FieldBitLen()
+	return nbBits < fieldBitLen || (nbBits == fieldBitLen && cfg.omitModulusCheck)
+}
+
+// assertIsDigit constrains v to be in [0, 2**k).
+func assertIsDigit(api frontend.API, k int, v frontend.Variable, cfg baseConversionConfig) {
+	if c, ok := api.Compiler().ConstantValue(v); ok {
+		if c.BitLen() <= k {
+			return
+		}
+		panic(fmt.Sprintf("value %s is not a %d-bit digit", c, k))
+	}
+	switch {
+	case k == 1:
+		api.AssertIsBoolean(v)
+	case cfg.Rangechecker != nil:
+		cfg.Rangechecker.Check(v, k)
+	default:
+		toBinary(api, v, WithNbDigits(k))
+	}
+}
@@ -1,10 +1,12 @@
 package bits_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/rangecheck"
 	"github.com/consensys/gnark/test"
 )
 
@@ -64,3 +66,100 @@ func TestToTernary(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.CheckCircuit(&toTernaryCircuit{}, test.WithValidAssignment(&toTernaryCircuit{A: 5, T0: 2, T1: 1, T2: 0}))
 }
+
+type powerOfTwoCircuit struct {
+	A          frontend.Variable
+	Bytes      [4]frontend.Variable
+	k          int // bits per digit
+	nbBits     int
+	rangecheck bool
+	bigEndian  bool
+}
+
+func (c *powerOfTwoCircuit) Define(api frontend.API) error {
+	var opts []bits.BaseConversionOption
+	if c.rangecheck {
+		opts = append(opts, bits.WithRangechecker(rangecheck.New(api)))
+	}
+	if c.bigEndian {
+		opts = append(opts, bits.WithBigEndian())
+	}
+	nbDigits := c.nbBits / c.k
+	var digits []frontend.Variable
+	var a frontend.Variable
+	if c.k == 8 {
+		digits = bits.ToBytes(api, c.A, append(opts, bits.WithNbDigits(nbDigits))...)
+		a = bits.FromBytes(api, digits, opts...)
+	} else {
+		base := bits.Base(1 << c.k)
+		digits = bits.ToBase(api, base, c.A, append(opts, bits.WithNbDigits(nbDigits))...)
+		a = bits.FromBase(api, base, digits, opts...)
+	}
+	if len(digits) != nbDigits {
+		return fmt.Errorf("expected %d digits, got %d", nbDigits, len(digits))
+	}
+	api.AssertIsEqual(a, c.A)
+
+	// the bytes are given in the same order as the digits
+	bytes := bits.ConvertDigits(api, c.k, 8, digits, opts...)
+	if len(bytes) != len(c.Bytes) {
+		return fmt.Errorf("expected %d bytes, got %d", len(c.Bytes), len(bytes))
+	}
+	for i := range bytes {
+		api.AssertIsEqual(bytes[i], c.Bytes[i])
+	}
+	back := bits.ConvertDigits(api, 8, c.k, c.Bytes[:], opts...)
+	for i := range back {
+		api.AssertIsEqual(back[i], digits[i])
+	}
+	api.AssertIsEqual(bits.FromBytes(api, c.Bytes[:], opts...), c.A)
+	return nil
+}
+
+func TestPowerOfTwoBases(t *testing.T) {
+	assert := test.NewAssert(t)
+	le := [4]frontend.Variable{0x78, 0x56, 0x34, 0x12}
+	be := [4]frontend.Variable{0x12, 0x34, 0x56, 0x78}
+	for _, k := range []int{1, 2, 4, 8} {
+		for _, rc := range []bool{false, true} {
+			for _, bigEndian := range []bool{false, true} {
+				bytes := le
+				if bigEndian {
+					bytes = be
+				}
+				assert.Run(func(assert *test.Assert) {
+					circuit := &powerOfTwoCircuit{k: k, nbBits: 32, rangecheck: rc, bigEndian: bigEndian}
+					assert.CheckCircuit(circuit,
+						test.WithValidAssignment(&powerOfTwoCircuit{A: 0x12345678, Bytes: bytes}),
+						test.WithInvalidAssignment(&powerOfTwoCircuit{A: 0x12345678, Bytes: [4]frontend.Variable{0x78, 0x56, 0x34, 0x13}}),
+						test.WithInvalidAssignment(&powerOfTwoCircuit{A: 0x12345678, Bytes: [4]frontend.Variable{0x78, 0x56 + 256, 0x33, 0x12}}),
+						test.WithInvalidAssignment(&powerOfTwoCircuit{A: 1 << 32, Bytes: [4]frontend.Variable{0, 0, 0, 0}}),
+					)
+				}, fmt.Sprintf("k=%d/rangecheck=%t/bigEndian=%t", k, rc, bigEndian))
+			}
+		}
+	}
+}
+
+type fullPowerOfTwoCircuit struct {
+	A frontend.Variable
+}
+
+func (c *fullPowerOfTwoCircuit) Define(api frontend.API) error {
+	opts := []bits.BaseConversionOption{bits.WithRangechecker(rangecheck.New(api))}
+	for _, base := range []bits.Base{bits.Quaternary, bits.Hexadecimal} {
+		digits := bits.ToBase(api, base, c.A, opts...)
+		api.AssertIsEqual(bits.FromBase(api, base, digits, opts...), c.A)
+	}
+	bytes := bits.ToBytes(api, c.A, opts...)
+	api.AssertIsEqual(bits.FromBytes(api, bytes, opts...), c.A)
+	return nil
+}
+
+func TestPowerOfTwoBasesFullDecomposition(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&fullPowerOfTwoCircuit{},
+		test.WithValidAssignment(&fullPowerOfTwoCircuit{A: -1}),
+		test.WithValidAssignment(&fullPowerOfTwoCircuit{A: 0x1234}),
+	)
+}
@@ -13,6 +13,7 @@ func GetHints() []solver.Hint {
 		nBits,
 		nTrits,
 		nNaf,
+		nDigits,
 	}
 }
 
@@ -62,6 +63,24 @@ func nTrits(_ *big.Int, inputs []*big.Int, results []*big.Int) error {
 	return nil
 }
 
+// nDigits returns the first digits of the second input in base 2^k, where k
+// is the first input. The number of returned digits is defined by the length
+// of the results slice.
+func nDigits(_ *big.Int, inputs []*big.Int, results []*big.Int) error {
+	if !inputs[0].IsUint64() {
+		return errors.New("invalid number of bits per digit")
+	}
+	k := uint(inputs[0].Uint64())
+	mask := new(big.Int).Lsh(big.NewInt(1), k)
+	mask.Sub(mask, big.NewInt(1))
+	n := new(big.Int).Set(inputs[1])
+	for i := range results {
+		results[i].And(n, mask)
+		n.Rsh(n, k)
+	}
+	return nil
+}
+
 // NNAF returns the NAF decomposition of the input. The number of digits is
 // defined by the number of elements in the results slice.
 func nNaf(_ *big.Int, inputs []*big.Int, results []*big.Int) error {
@@ -0,0 +1,139 @@
+// Package boolean implements gadgets combining many booleans, which cost less
+// than folding them pairwise with the operations of frontend.API.
+package boolean
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/frontendtype"
+)
+
+// AndMany returns 1 if all the booleans b are 1 and 0 otherwise. It returns 1
+// if b is empty.
+//
+// The b are asserted to be booleans, which is free for the ones known to be.
+// In R1CS, where the linear combinations are free, the sum of the b is
+// compared to len(b) in 2 constraints. Otherwise, the b are combined with
+// api.And in a balanced tree.
+func AndMany(api frontend.API, b ...frontend.Variable) frontend.Variable {
+	vars, nbOnes := splitBooleans(api, b)
+	if nbOnes+len(vars) < len(b) {
+		return 0
+	}
+	return andMany(api, vars)
+}
+
+// OrMany returns 1 if one of the booleans b is 1 and 0 otherwise. It returns 0
+// if b is empty. See AndMany for the cost.
+func OrMany(api frontend.API, b ...frontend.Variable) frontend.Variable {
+	vars, nbOnes := splitBooleans(api, b)
+	if nbOnes > 0 {
+		return 1
+	}
+	return orMany(api, vars)
+}
+
+// Threshold returns 1 if at least k of the booleans b are 1 and 0 otherwise.
+// Apart from the cases k=1 and k=len(b), computed as OrMany and AndMany, the
+// sum of the b is compared to k with a decomposition in log(len(b))+2 bits.
+func Threshold(api frontend.API, k int, b ...frontend.Variable) frontend.Variable {
+	vars, nbOnes := splitBooleans(api, b)
+	k -= nbOnes
+	switch {
+	case k <= 0:
+		return 1
+	case k > len(vars):
+		return 0
+	case k == 1:
+		return orMany(api, vars)
+	case k == len(vars):
+		return andMany(api, vars)
+	}
+
+	// with 2ᵐ > len(vars) ≥ k, s = Σb + 2ᵐ - k is in [0, 2ᵐ⁺¹) and its m-th
+	// bit is set iff Σb ≥ k
+	m := bits.Len(uint(len(vars)))
+	s := api.Add(sum(api, vars), (1<<m)-k)
+	return api.ToBinary(s, m+1)[m]
+}
+
+// Majority returns 1 if more than half of the booleans b are 1 and 0
+// otherwise, including on a tie. It is Threshold(api, len(b)/2+1, b...).
+func Majority(api frontend.API, b ...frontend.Variable) frontend.Variable {
+	return Threshold(api, len(b)/2+1, b...)
+}
+
+// splitBooleans asserts that the variables b are booleans, which is free for
+// the ones known to be, and returns the ones which are not constant and the
+// number of constants equal to 1.
+func splitBooleans(api frontend.API, b []frontend.Variable) (vars []frontend.Variable, nbOnes int) {
+	vars = make([]frontend.Variable, 0, len(b))
+	for _, v := range b {
+		api.AssertIsBoolean(v)
+		c, isConstant := api.Compiler().ConstantValue(v)
+		switch {
+		case !isConstant:
+			vars = append(vars, v)
+		case c.Sign() != 0:
+			nbOnes++
+		}
+	}
+	return vars, nbOnes
+}
+
+// isLinear returns true if the linear combinations are free in the
+// arithmetization of api.
+func isLinear(api frontend.API) bool {
+	ft, ok := api.(frontendtype.FrontendTyper)
+	return ok && ft.FrontendType() == frontendtype.R1CS
+}
+
+func andMany(api frontend.API, vars []frontend.Variable) frontend.Variable {
+	if isLinear(api) && len(vars) > 2 {
+		// Σb = n iff all the inputs are 1, n being smaller than the modulus
+		res := api.IsZero(api.Sub(len(vars), sum(api, vars)))
+		api.Compiler().MarkBoolean(res)
+		return res
+	}
+	return balancedTree(vars, 1, api.And)
+}
+
+func orMany(api frontend.API, vars []frontend.Variable) frontend.Variable {
+	if isLinear(api) && len(vars) > 2 {
+		res := api.Sub(1, api.IsZero(sum(api, vars)))
+		api.Compiler().MarkBoolean(res)
+		return res
+	}
+	return balancedTree(vars, 0, api.Or)
+}
+
+// balancedTree reduces vars with op, in a tree of depth log(len(vars)) so that
+// the solver can solve the operations of a level in parallel. It returns
+// neutral if vars is empty. vars is overwritten.
+func balancedTree(vars []frontend.Variable, neutral frontend.Variable, op func(a, b frontend.Variable) frontend.Variable) frontend.Variable {
+	if len(vars) == 0 {
+		return neutral
+	}
+	for len(vars) > 1 {
+		next := vars[:0]
+		for i := 0; i+1 < len(vars); i += 2 {
+			next = append(next, op(vars[i], vars[i+1]))
+		}
+		if len(vars)%2 == 1 {
+			next = append(next, vars[len(vars)-1])
+		}
+		vars = next
+	}
+	return vars[0]
+}
+
+func sum(api frontend.API, vars []frontend.Variable) frontend.Variable {
+	switch len(vars) {
+	case 0:
+		return 0
+	case 1:
+		return vars[0]
+	}
+	return api.Add(vars[0], vars[1], vars[2:]...)
+}
@@ -0,0 +1,119 @@
+package boolean_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/math/boolean"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type booleansCircuit struct {
+	B                                [5]frontend.Variable
+	And, Or, AtLeast2, AtLeast4, Maj frontend.Variable `gnark:",public"`
+}
+
+func (c *booleansCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(boolean.AndMany(api, c.B[:]...), c.And)
+	api.AssertIsEqual(boolean.OrMany(api, c.B[:]...), c.Or)
+	api.AssertIsEqual(boolean.Threshold(api, 2, c.B[:]...), c.AtLeast2)
+	api.AssertIsEqual(boolean.Threshold(api, 4, c.B[:]...), c.AtLeast4)
+	api.AssertIsEqual(boolean.Majority(api, c.B[:]...), c.Maj)
+
+	// the constants are folded
+	api.AssertIsEqual(boolean.AndMany(api, c.B[0], 1, c.B[0]), c.B[0])
+	api.AssertIsEqual(boolean.OrMany(api, c.B[0], 0), c.B[0])
+	api.AssertIsEqual(boolean.Threshold(api, 2, c.B[1], 1, 0), c.B[1])
+	api.AssertIsEqual(boolean.AndMany(api), 1)
+	api.AssertIsEqual(boolean.OrMany(api), 0)
+	api.AssertIsEqual(boolean.Majority(api, c.B[0], c.B[1]), api.And(c.B[0], c.B[1]))
+	return nil
+}
+
+func TestBooleans(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(field, newBuilder, &booleansCircuit{})
+			assert.NoError(err)
+
+			for bits := 0; bits < 1<<5; bits++ {
+				var assignment booleansCircuit
+				count := 0
+				for i := range assignment.B {
+					assignment.B[i] = bits >> i & 1
+					count += bits >> i & 1
+				}
+				b := func(v bool) int {
+					if v {
+						return 1
+					}
+					return 0
+				}
+				assignment.And, assignment.Or = b(count == 5), b(count > 0)
+				assignment.AtLeast2, assignment.AtLeast4, assignment.Maj = b(count >= 2), b(count >= 4), b(count >= 3)
+
+				w, err := frontend.NewWitness(&assignment, field)
+				assert.NoError(err)
+				_, err = ccs.Solve(w)
+				assert.NoError(err, bits)
+				assert.NoError(test.IsSolved(&booleansCircuit{}, &assignment, field), bits)
+
+				// a wrong result
+				assignment.Maj = 1 - b(count >= 3)
+				w, err = frontend.NewWitness(&assignment, field)
+				assert.NoError(err)
+				_, err = ccs.Solve(w)
+				assert.Error(err, bits)
+			}
+
+			// the inputs must be booleans
+			assignment := booleansCircuit{B: [5]frontend.Variable{2, 0, 0, 0, 0}, And: 0, Or: 1, AtLeast2: 0, AtLeast4: 0, Maj: 0}
+			w, err := frontend.NewWitness(&assignment, field)
+			assert.NoError(err)
+			_, err = ccs.Solve(w)
+			assert.Error(err)
+		})
+	}
+}
+
+// naiveAndCircuit folds known booleans with And, or with AndMany if UseGadget
+// is set.
+type naiveAndCircuit struct {
+	X         [16]frontend.Variable
+	UseGadget bool `gnark:"-"`
+}
+
+func (c *naiveAndCircuit) Define(api frontend.API) error {
+	b := make([]frontend.Variable, len(c.X))
+	for i := range c.X {
+		b[i] = api.IsZero(c.X[i])
+	}
+	var res frontend.Variable = 1
+	if c.UseGadget {
+		res = boolean.AndMany(api, b...)
+	} else {
+		for i := range b {
+			res = api.And(res, b[i])
+		}
+	}
+	api.AssertIsEqual(res, 0)
+	return nil
+}
+
+func TestAndManyCost(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		naive, err := frontend.Compile(field, newBuilder, &naiveAndCircuit{})
+		assert.NoError(err)
+		gadget, err := frontend.Compile(field, newBuilder, &naiveAndCircuit{UseGadget: true})
+		assert.NoError(err)
+		assert.LessOrEqual(gadget.GetNbConstraints(), naive.GetNbConstraints())
+	}
+}
@@ -58,6 +58,21 @@ func (fp Goldilocks) BitsPerLimb() uint { return 64 }
 func (fp Goldilocks) IsPrime() bool     { return true }
 func (fp Goldilocks) Modulus() *big.Int { return goldilocks.Modulus() }
 
+// BabyBear provides type parametrization for field emulation:
+//   - limbs: 1
+//   - limb width: 32 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x78000001 (base 16)
+//	2013265921 (base 10)
+type BabyBear struct{}
+
+func (fp BabyBear) NbLimbs() uint     { return 1 }
+func (fp BabyBear) BitsPerLimb() uint { return 32 }
+func (fp BabyBear) IsPrime() bool     { return true }
+func (fp BabyBear) Modulus() *big.Int { return big.NewInt(2013265921) }
+
 // Secp256k1Fp provides type parametrization for field emulation:
 //   - limbs: 4
 //   - limb width: 64 bits
@@ -282,6 +297,41 @@ type BLS24315Fr struct{ fourLimbPrimeField }
 
 func (fr BLS24315Fr) Modulus() *big.Int { return ecc.BLS24_315.ScalarField() }
 
+// PastaFp provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x40000000000000000000000000000000224698fc094cf91b992d30ed00000001 (base 16)
+//	28948022309329048855892746252171976963363056481941560715954676764349967630337 (base 10)
+//
+// This is the base field of the Pallas curve and the scalar field of the Vesta
+// curve.
+type PastaFp struct{ fourLimbPrimeField }
+
+func (fp PastaFp) Modulus() *big.Int { return pastaFp }
+
+// PastaFq provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x40000000000000000000000000000000224698fc0994a8dd8c46eb2100000001 (base 16)
+//	28948022309329048855892746252171976963363056481941647379679742748393362948097 (base 10)
+//
+// This is the scalar field of the Pallas curve and the base field of the Vesta
+// curve.
+type PastaFq struct{ fourLimbPrimeField }
+
+func (fq PastaFq) Modulus() *big.Int { return pastaFq }
+
+var (
+	pastaFp, _ = new(big.Int).SetString("40000000000000000000000000000000224698fc094cf91b992d30ed00000001", 16)
+	pastaFq, _ = new(big.Int).SetString("40000000000000000000000000000000224698fc0994a8dd8c46eb2100000001", 16)
+)
+
 // Mod1e4096 provides type parametrization for emulated aritmetic:
 //   - limbs: 64
 //   - limb width: 64 bits
@@ -52,14 +52,19 @@ func (f *Field[T]) AssertIsEqual(a, b *Element[T]) {
 
 // AssertIsLessOrEqual ensures that e is less or equal than a. For proper
 // bitwise comparison first reduce the element using [Reduce] and then assert
-// that its value is less than the modulus using [AssertIsInRange].
+// that its value is less than the modulus using [AssertIsInRange]. To compare
+// the canonical representatives of the elements, use [Field.IsLessOrEqual].
 func (f *Field[T]) AssertIsLessOrEqual(e, a *Element[T]) {
 	// we omit conditional width assertion as is done in ToBits below
 	if e.overflow+a.overflow > 0 {
 		panic("inputs must have 0 overflow")
 	}
-	eBits := f.ToBits(e)
-	aBits := f.ToBits(a)
+	f.assertBitsLessOrEqual(f.ToBits(e), f.ToBits(a))
+}
+
+// assertBitsLessOrEqual ensures that the integer with the bits eBits is less or
+// equal than the integer with the bits aBits, both in little-endian order.
+func (f *Field[T]) assertBitsLessOrEqual(eBits, aBits []frontend.Variable) {
 	ff := func(xbits, ybits []frontend.Variable) []frontend.Variable {
 		diff := len(xbits) - len(ybits)
 		ybits = append(ybits, make([]frontend.Variable, diff)...)
@@ -129,25 +134,67 @@ func (f *Field[T]) IsZero(a *Element[T]) frontend.Variable {
 	return f.api.IsZero(limbSum)
 }
 
-// // Cmp returns:
-// //   - -1 if a < b
-// //   - 0 if a = b
-// //   - 1 if a > b
-// //
-// // The method internally reduces the element and asserts that the value is less
-// // than the modulus.
-// func (f *Field[T]) Cmp(a, b *Element[T]) frontend.Variable {
-// 	ca := f.Reduce(a)
-// 	f.AssertIsInRange(ca)
-// 	cb := f.Reduce(b)
-// 	f.AssertIsInRange(cb)
-// 	var res frontend.Variable = 0
-// 	for i := int(f.fParams.NbLimbs() - 1); i >= 0; i-- {
-// 		lmbCmp := f.api.Cmp(ca.Limbs[i], cb.Limbs[i])
-// 		res = f.api.Select(f.api.IsZero(res), lmbCmp, res)
-// 	}
-// 	return res
-// }
+// canonicalBits returns the bits of the canonical representative of a in
+// [0, p), in little-endian order. There are as many bits as the bit-length of
+// the modulus.
+func (f *Field[T]) canonicalBits(a *Element[T]) []frontend.Variable {
+	nbBits := f.fParams.Modulus().BitLen()
+	if ba, aConst := f.constantValue(a); aConst {
+		ba.Mod(ba, f.fParams.Modulus())
+		res := make([]frontend.Variable, nbBits)
+		for i := range res {
+			res[i] = ba.Bit(i)
+		}
+		return res
+	}
+	// [Reduce] omits the reduction of the elements with no overflow, which may
+	// still be larger than the modulus (for example when constructed from
+	// bits). The hint of the multiplication returns the remainder.
+	ca := f.mulMod(a, f.One(), 0, nil)
+	caBits := f.ToBits(ca)
+	f.assertBitsLessOrEqual(caBits, f.ToBits(f.modulusPrev()))
+	// the excess bits are zero as ca < p
+	return caBits[:nbBits]
+}
+
+// Cmp returns:
+//   - -1 if a < b
+//   - 0 if a = b
+//   - 1 if a > b
+//
+// The canonical representatives of the elements in [0, p) are compared, so the
+// inputs don't have to be reduced.
+func (f *Field[T]) Cmp(a, b *Element[T]) frontend.Variable {
+	aBits := f.canonicalBits(a)
+	bBits := f.canonicalBits(b)
+	// from the least significant bit, the result is overridden by the
+	// difference of the bits when they differ.
+	var res frontend.Variable = 0
+	for i := range aBits {
+		d := f.api.Sub(aBits[i], bBits[i])
+		differ := f.api.Mul(d, d)
+		res = f.api.Add(res, f.api.Mul(differ, f.api.Sub(d, res)))
+	}
+	return res
+}
+
+// IsLess returns a boolean indicating if a < b, comparing the canonical
+// representatives of the elements in [0, p). The result can be used directly
+// as a selector in [Field.Select].
+func (f *Field[T]) IsLess(a, b *Element[T]) frontend.Variable {
+	// (c² - c)/2 is 1 if c = -1 and 0 if c ∈ {0, 1}
+	c := f.Cmp(a, b)
+	return f.api.Div(f.api.Sub(f.api.Mul(c, c), c), 2)
+}
+
+// IsLessOrEqual returns a boolean indicating if a ≤ b, comparing the canonical
+// representatives of the elements in [0, p). The result can be used directly
+// as a selector in [Field.Select].
+func (f *Field[T]) IsLessOrEqual(a, b *Element[T]) frontend.Variable {
+	// (c² + c)/2 is 1 if c = 1 and 0 if c ∈ {-1, 0}
+	c := f.Cmp(a, b)
+	return f.api.Sub(1, f.api.Div(f.api.Add(f.api.Mul(c, c), c), 2))
+}
 
 // TODO(@ivokub)
 // func (f *Field[T]) AssertIsDifferent(a, b *Element[T]) {
@@ -1,9 +1,12 @@
 package emulated
 
 import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/test"
-	"testing"
 )
 
 type ZeroCircuit[T FieldParams] struct {
@@ -52,3 +55,80 @@ func TestZeroCircuit(t *testing.T) {
 		assert.NoError(err)
 	}
 }
+
+type CmpCircuit[T FieldParams] struct {
+	A, B          Element[T]
+	Cmp           frontend.Variable `gnark:",public"`
+	IsLess        frontend.Variable `gnark:",public"`
+	IsLessOrEqual frontend.Variable `gnark:",public"`
+}
+
+func (c *CmpCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(f.Cmp(&c.A, &c.B), c.Cmp)
+	api.AssertIsEqual(f.IsLess(&c.A, &c.B), c.IsLess)
+	api.AssertIsEqual(f.IsLessOrEqual(&c.A, &c.B), c.IsLessOrEqual)
+	// the canonical representatives are compared
+	api.AssertIsEqual(f.Cmp(f.Add(&c.A, f.Modulus()), &c.A), 0)
+	return nil
+}
+
+func TestCmpCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	type T = BN254Fp
+	var fp T
+	// a non-canonical element with limbs encoding v+p
+	nonCanonical := func(v *big.Int) Element[T] {
+		limbs := make([]*big.Int, fp.NbLimbs())
+		for i := range limbs {
+			limbs[i] = new(big.Int)
+		}
+		if err := decompose(new(big.Int).Add(v, fp.Modulus()), fp.BitsPerLimb(), limbs); err != nil {
+			t.Fatal(err)
+		}
+		el := Element[T]{Limbs: make([]frontend.Variable, len(limbs))}
+		for i := range limbs {
+			el.Limbs[i] = limbs[i]
+		}
+		return el
+	}
+	a, err := rand.Int(rand.Reader, fp.Modulus())
+	assert.NoError(err)
+	b, err := rand.Int(rand.Reader, fp.Modulus())
+	assert.NoError(err)
+	if a.Cmp(b) > 0 {
+		a, b = b, a
+	}
+	small := big.NewInt(3)
+	var opts []test.TestingOption
+	for _, tc := range []struct {
+		a, b              Element[T]
+		cmp, less, lessEq int
+	}{
+		{ValueOf[T](a), ValueOf[T](b), -1, 1, 1},
+		{ValueOf[T](b), ValueOf[T](a), 1, 0, 0},
+		{ValueOf[T](a), ValueOf[T](a), 0, 0, 1},
+		{nonCanonical(small), ValueOf[T](4), -1, 1, 1},
+		{nonCanonical(small), ValueOf[T](3), 0, 0, 1},
+		{ValueOf[T](4), nonCanonical(small), 1, 0, 0},
+	} {
+		opts = append(opts, test.WithValidAssignment(&CmpCircuit[T]{A: tc.a, B: tc.b, Cmp: tc.cmp, IsLess: tc.less, IsLessOrEqual: tc.lessEq}))
+		// the results of the comparisons are checked independently
+		opts = append(opts,
+			test.WithInvalidAssignment(&CmpCircuit[T]{A: tc.a, B: tc.b, Cmp: (tc.cmp+2)%3 - 1, IsLess: tc.less, IsLessOrEqual: tc.lessEq}),
+			test.WithInvalidAssignment(&CmpCircuit[T]{A: tc.a, B: tc.b, Cmp: tc.cmp, IsLess: 1 - tc.less, IsLessOrEqual: tc.lessEq}),
+			test.WithInvalidAssignment(&CmpCircuit[T]{A: tc.a, B: tc.b, Cmp: tc.cmp, IsLess: tc.less, IsLessOrEqual: 1 - tc.lessEq}),
+		)
+	}
+	// the results of comparing the limbs of the non-reduced inputs, instead
+	// of their canonical representatives
+	opts = append(opts,
+		test.WithInvalidAssignment(&CmpCircuit[T]{A: nonCanonical(small), B: ValueOf[T](4), Cmp: 1, IsLess: 0, IsLessOrEqual: 0}),
+		test.WithInvalidAssignment(&CmpCircuit[T]{A: nonCanonical(small), B: ValueOf[T](3), Cmp: 1, IsLess: 0, IsLessOrEqual: 0}),
+		test.WithInvalidAssignment(&CmpCircuit[T]{A: ValueOf[T](4), B: nonCanonical(small), Cmp: -1, IsLess: 1, IsLessOrEqual: 1}),
+	)
+	assert.CheckCircuit(&CmpCircuit[T]{}, append(opts, test.WithCurves(testCurve), test.NoFuzzing(), test.NoSerializationChecks())...)
+}
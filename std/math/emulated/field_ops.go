@@ -291,9 +291,8 @@ func (f *Field[T]) Lookup2(b0, b1 frontend.Variable, a, b, c, d *Element[T]) *El
 
 // Mux selects element inputs[sel] and returns it. The number of the limbs and
 // overflow in the result is the maximum of the inputs'. If the inputs are very
-// unbalanced, then reduce the inputs before calling the method. It is most
-// efficient for power of two lengths of the inputs, but works for any
-// number of inputs.
+// unbalanced, then reduce the inputs before calling the method. The selector is
+// decomposed once for all the limbs, see [selector.BatchMux].
 func (f *Field[T]) Mux(sel frontend.Variable, inputs ...*Element[T]) *Element[T] {
 	if len(inputs) == 0 {
 		return nil
@@ -324,18 +323,7 @@ func (f *Field[T]) Mux(sel frontend.Variable, inputs ...*Element[T]) *Element[T]
 	for i := range inputs {
 		normLimbs[i] = normalize(inputs[i].Limbs)
 	}
-	normLimbsTransposed := make([][]frontend.Variable, nbLimbs)
-	for i := range normLimbsTransposed {
-		normLimbsTransposed[i] = make([]frontend.Variable, nbInputs)
-		for j := range normLimbsTransposed[i] {
-			normLimbsTransposed[i][j] = normLimbs[j][i]
-		}
-	}
-	e := f.newInternalElement(make([]frontend.Variable, nbLimbs), overflow)
-	for i := range inputs[0].Limbs {
-		e.Limbs[i] = selector.Mux(f.api, sel, normLimbsTransposed[i]...)
-	}
-	return e
+	return f.newInternalElement(selector.BatchMux(f.api, sel, normLimbs...), overflow)
 }
 
 // reduceAndOp applies op on the inputs. If the pre-condition check preCond
@@ -12,7 +12,13 @@ import (
 // inside a func, then it becomes anonymous and hint identification is screwed.
 
 func init() {
-	solver.RegisterHint(GetHints()...)
+	// the hints are versioned, so that the constraint systems serialized with
+	// a previous version of the hints fail to solve with a clear error.
+	solver.RegisterVersionedHint("gnark/std/emulated/DivHint", 1, DivHint)
+	solver.RegisterVersionedHint("gnark/std/emulated/InverseHint", 1, InverseHint)
+	solver.RegisterVersionedHint("gnark/std/emulated/SqrtHint", 1, SqrtHint)
+	solver.RegisterVersionedHint("gnark/std/emulated/mulHint", 1, mulHint)
+	solver.RegisterVersionedHint("gnark/std/emulated/subPaddingHint", 1, subPaddingHint)
 }
 
 // GetHints returns all hint functions used in the package.
@@ -0,0 +1,181 @@
+// Package matrix implements matrix products over the native field.
+//
+// Instead of computing the n·m·p multiplications of the product C = A·B in the
+// circuit, the product is computed out of the circuit in a hint and checked
+// with the randomized test of Freivalds [Fre77]: for a random vector r,
+//
+//	A·(B·r) == C·r,
+//
+// which only costs n·m + m·p + n·p multiplications. The vector r is (1, x, x²,
+// ...) for a challenge x derived from the commitment to the matrices, so that
+// a wrong product is accepted with probability at most (p-1)/|F|. The builder
+// must implement [frontend.Committer].
+//
+// [Fre77]: https://doi.org/10.1007/3-540-08353-7_135
+package matrix
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{mulHint}
+}
+
+// Mul returns the product of the n×m matrix a by the m×p matrix b. The product
+// is computed in a hint and checked with [AssertProduct].
+func Mul(api frontend.API, a, b [][]frontend.Variable) [][]frontend.Variable {
+	n, m, p := dims(a, b)
+	inputs := make([]frontend.Variable, 0, 3+n*m+m*p)
+	inputs = append(inputs, n, m, p)
+	for i := range a {
+		inputs = append(inputs, a[i]...)
+	}
+	for i := range b {
+		inputs = append(inputs, b[i]...)
+	}
+	res, err := api.Compiler().NewHint(mulHint, n*p, inputs...)
+	if err != nil {
+		panic(fmt.Sprintf("new hint: %v", err))
+	}
+	c := make([][]frontend.Variable, n)
+	for i := range c {
+		c[i] = res[i*p : (i+1)*p]
+	}
+	AssertProduct(api, a, b, c)
+	return c
+}
+
+// AssertProduct asserts that c = a·b, where a is a n×m matrix, b a m×p matrix
+// and c a n×p matrix.
+func AssertProduct(api frontend.API, a, b, c [][]frontend.Variable) {
+	n, _, p := dims(a, b)
+	if len(c) != n {
+		panic(fmt.Sprintf("product has %d rows, expected %d", len(c), n))
+	}
+	var toCommit []frontend.Variable
+	for _, mat := range [][][]frontend.Variable{a, b, c} {
+		for i := range mat {
+			if len(mat[i]) != len(mat[0]) {
+				panic("rows of different lengths")
+			}
+			for _, v := range mat[i] {
+				if _, isConstant := api.Compiler().ConstantValue(v); !isConstant {
+					toCommit = append(toCommit, v)
+				}
+			}
+		}
+	}
+	if len(c[0]) != p {
+		panic(fmt.Sprintf("product has %d columns, expected %d", len(c[0]), p))
+	}
+	if len(toCommit) == 0 {
+		// the product of constants is computed at compile time
+		for i := range c {
+			for j := range c[i] {
+				var s frontend.Variable = 0
+				for k := range b {
+					s = api.Add(s, api.Mul(a[i][k], b[k][j]))
+				}
+				api.AssertIsEqual(c[i][j], s)
+			}
+		}
+		return
+	}
+	// the caller may modify the matrices before the deferred check, which
+	// requests the commitment at compile time as the other multicommit users.
+	a, b, c = clone(a), clone(b), clone(c)
+	api.Compiler().Defer(func(api frontend.API) error {
+		multicommit.WithCommitment(api, func(api frontend.API, x frontend.Variable) error {
+			r := make([]frontend.Variable, p)
+			r[0] = 1
+			for j := 1; j < p; j++ {
+				r[j] = api.Mul(r[j-1], x)
+			}
+			br := mulVector(api, b, r)
+			abr := mulVector(api, a, br)
+			cr := mulVector(api, c, r)
+			for i := range abr {
+				api.AssertIsEqual(abr[i], cr[i])
+			}
+			return nil
+		}, toCommit...)
+		return nil
+	})
+}
+
+func clone(a [][]frontend.Variable) [][]frontend.Variable {
+	res := make([][]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = append([]frontend.Variable(nil), a[i]...)
+	}
+	return res
+}
+
+// mulVector returns the product of the matrix a by the vector v.
+func mulVector(api frontend.API, a [][]frontend.Variable, v []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = 0
+		for j := range a[i] {
+			res[i] = api.MulAcc(res[i], a[i][j], v[j])
+		}
+	}
+	return res
+}
+
+// dims returns the dimensions of the product of a by b.
+func dims(a, b [][]frontend.Variable) (n, m, p int) {
+	if len(a) == 0 || len(b) == 0 || len(b[0]) == 0 {
+		panic("empty matrix")
+	}
+	n, m, p = len(a), len(b), len(b[0])
+	for i := range a {
+		if len(a[i]) != m {
+			panic(fmt.Sprintf("row %d of the left matrix has %d columns, expected %d", i, len(a[i]), m))
+		}
+	}
+	for i := range b {
+		if len(b[i]) != p {
+			panic(fmt.Sprintf("row %d of the right matrix has %d columns, expected %d", i, len(b[i]), p))
+		}
+	}
+	return n, m, p
+}
+
+func mulHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 3 {
+		return fmt.Errorf("expected at least 3 inputs, got %d", len(inputs))
+	}
+	n, m, p := int(inputs[0].Int64()), int(inputs[1].Int64()), int(inputs[2].Int64())
+	if len(inputs) != 3+n*m+m*p {
+		return fmt.Errorf("expected %d inputs, got %d", 3+n*m+m*p, len(inputs))
+	}
+	if len(outputs) != n*p {
+		return fmt.Errorf("expected %d outputs, got %d", n*p, len(outputs))
+	}
+	a, b := inputs[3:3+n*m], inputs[3+n*m:]
+	var tmp big.Int
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			outputs[i*p+j].SetUint64(0)
+			for k := 0; k < m; k++ {
+				tmp.Mul(a[i*m+k], b[k*p+j])
+				outputs[i*p+j].Add(outputs[i*p+j], &tmp)
+			}
+			outputs[i*p+j].Mod(outputs[i*p+j], mod)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type productCircuit struct {
+	A, B, C [][]frontend.Variable
+}
+
+func (c *productCircuit) Define(api frontend.API) error {
+	AssertProduct(api, c.A, c.B, c.C)
+	res := Mul(api, c.A, c.B)
+	for i := range res {
+		for j := range res[i] {
+			api.AssertIsEqual(res[i][j], c.C[i][j])
+		}
+	}
+	return nil
+}
+
+func newMatrix(n, m int, f func(i, j int) frontend.Variable) [][]frontend.Variable {
+	res := make([][]frontend.Variable, n)
+	for i := range res {
+		res[i] = make([]frontend.Variable, m)
+		for j := range res[i] {
+			if f != nil {
+				res[i][j] = f(i, j)
+			}
+		}
+	}
+	return res
+}
+
+func TestAssertProduct(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n, m, p = 3, 4, 2
+	a := newMatrix(n, m, func(i, j int) frontend.Variable { return i*m + j + 1 })
+	b := newMatrix(m, p, func(i, j int) frontend.Variable { return 2*i - j })
+	c := newMatrix(n, p, func(i, j int) frontend.Variable {
+		s := 0
+		for k := 0; k < m; k++ {
+			s += (i*m + k + 1) * (2*k - j)
+		}
+		return s
+	})
+	wrong := newMatrix(n, p, func(i, j int) frontend.Variable { return c[i][j] })
+	wrong[n-1][p-1] = 0
+	assert.CheckCircuit(&productCircuit{A: newMatrix(n, m, nil), B: newMatrix(m, p, nil), C: newMatrix(n, p, nil)},
+		test.WithValidAssignment(&productCircuit{A: a, B: b, C: c}),
+		test.WithInvalidAssignment(&productCircuit{A: a, B: b, C: wrong}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type mulCircuit struct {
+	A, B [][]frontend.Variable
+}
+
+func (c *mulCircuit) Define(api frontend.API) error {
+	Mul(api, c.A, c.B)
+	return nil
+}
+
+type naiveMulCircuit struct {
+	A, B [][]frontend.Variable
+}
+
+func (c *naiveMulCircuit) Define(api frontend.API) error {
+	for i := range c.A {
+		for j := range c.B[0] {
+			var s frontend.Variable = 0
+			for k := range c.B {
+				s = api.Add(s, api.Mul(c.A[i][k], c.B[k][j]))
+			}
+			api.AssertIsEqual(s, s)
+		}
+	}
+	return nil
+}
+
+func TestMulCost(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 16
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &mulCircuit{A: newMatrix(n, n, nil), B: newMatrix(n, n, nil)})
+	assert.NoError(err)
+	naive, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &naiveMulCircuit{A: newMatrix(n, n, nil), B: newMatrix(n, n, nil)})
+	assert.NoError(err)
+	assert.Less(ccs.GetNbConstraints(), naive.GetNbConstraints()/4)
+}
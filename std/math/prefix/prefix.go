@@ -0,0 +1,83 @@
+// Package prefix implements prefix aggregations over lists of variables, as
+// the running sums of balances or fees in rollup circuits.
+//
+// Each prefix value is a single wire, constrained by a single constraint with
+// the previous one. With R1CS builders, where the sums are otherwise linear
+// expressions growing with the list, the prefix sums are solved by a single
+// instruction of a dedicated blueprint which works directly on the field
+// elements instead of a hint per value.
+package prefix
+
+import (
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+type ctxBlueprintKey struct{}
+
+// Sums returns the prefix sums of xs: the i-th output is xs[0] + … + xs[i].
+func Sums(api frontend.API, xs []frontend.Variable) []frontend.Variable {
+	if len(xs) == 0 {
+		return nil
+	}
+	res := make([]frontend.Variable, len(xs))
+	if _, ok := api.(frontend.PlonkAPI); ok {
+		// each addition is already a wire and a constraint
+		res[0] = xs[0]
+		for i := 1; i < len(xs); i++ {
+			res[i] = api.Add(res[i-1], xs[i])
+		}
+		return res
+	}
+	compiler := api.Compiler()
+	b, bID := blueprint(api)
+	inputs := make([]constraint.Compressible, len(xs))
+	for i := range xs {
+		inputs[i] = compiler.ToCanonicalVariable(xs[i])
+	}
+	wires := compiler.AddInstruction(bID, b.PrefixSum(inputs...))
+	var previous frontend.Variable = 0
+	for i := range wires {
+		res[i] = compiler.InternalVariable(wires[i])
+		api.AssertIsEqual(res[i], api.Add(previous, xs[i]))
+		previous = res[i]
+	}
+	return res
+}
+
+// Products returns the prefix products of xs: the i-th output is xs[0] × … ×
+// xs[i].
+func Products(api frontend.API, xs []frontend.Variable) []frontend.Variable {
+	if len(xs) == 0 {
+		return nil
+	}
+	// each multiplication is already a wire and a constraint, which is solved
+	// by the constraint itself
+	res := make([]frontend.Variable, len(xs))
+	res[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		res[i] = api.Mul(res[i-1], xs[i])
+	}
+	return res
+}
+
+// blueprint returns the blueprint of the prefix sums, shared by the calls in
+// the circuit.
+func blueprint(api frontend.API) (*constraint.BlueprintPrefixSum, constraint.BlueprintID) {
+	type entry struct {
+		b   *constraint.BlueprintPrefixSum
+		bID constraint.BlueprintID
+	}
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		panic("builder should implement key-value store")
+	}
+	if e, ok := kv.GetKeyValue(ctxBlueprintKey{}).(entry); ok {
+		return e.b, e.bID
+	}
+	b := &constraint.BlueprintPrefixSum{}
+	e := entry{b: b, bID: api.Compiler().AddBlueprint(b)}
+	kv.SetKeyValue(ctxBlueprintKey{}, e)
+	return e.b, e.bID
+}
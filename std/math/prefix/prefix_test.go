@@ -0,0 +1,104 @@
+package prefix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+type prefixCircuit struct {
+	X        []frontend.Variable
+	Sums     []frontend.Variable
+	Products []frontend.Variable
+}
+
+func (c *prefixCircuit) Define(api frontend.API) error {
+	sums, products := Sums(api, c.X), Products(api, c.X)
+	for i := range c.X {
+		api.AssertIsEqual(sums[i], c.Sums[i])
+		api.AssertIsEqual(products[i], c.Products[i])
+	}
+	return nil
+}
+
+func newPrefixCircuit(n int) *prefixCircuit {
+	return &prefixCircuit{X: make([]frontend.Variable, n), Sums: make([]frontend.Variable, n), Products: make([]frontend.Variable, n)}
+}
+
+func newAssignment(xs ...int) *prefixCircuit {
+	c := newPrefixCircuit(len(xs))
+	sum, prod := 0, 1
+	for i, x := range xs {
+		sum, prod = sum+x, prod*x
+		c.X[i], c.Sums[i], c.Products[i] = x, sum, prod
+	}
+	return c
+}
+
+func TestPrefix(t *testing.T) {
+	assert := test.NewAssert(t)
+	invalid := newAssignment(1, 2, 3, 4, 5)
+	invalid.Sums[2] = 7
+	assert.CheckCircuit(newPrefixCircuit(5),
+		test.WithValidAssignment(newAssignment(1, 2, 3, 4, 5)),
+		test.WithInvalidAssignment(invalid),
+		test.WithCurves(ecc.BN254), test.NoFuzzing())
+}
+
+type sumsCircuit struct {
+	X   []frontend.Variable
+	Sum frontend.Variable
+}
+
+func (c *sumsCircuit) Define(api frontend.API) error {
+	sums := Sums(api, c.X)
+	api.AssertIsEqual(sums[len(sums)-1], c.Sum)
+	return nil
+}
+
+func TestSumsSolver(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 100
+	assignment := &sumsCircuit{X: make([]frontend.Variable, n)}
+	sum := 0
+	for i := range assignment.X {
+		assignment.X[i] = i
+		sum += i
+	}
+	assignment.Sum = sum
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	for _, b := range []struct {
+		newBuilder frontend.NewBuilder
+		newCS      func(ecc.ID) constraint.ConstraintSystem
+	}{{r1cs.NewBuilder, groth16.NewCS}, {scs.NewBuilder, plonk.NewCS}} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), b.newBuilder, &sumsCircuit{X: make([]frontend.Variable, n)})
+		assert.NoError(err)
+		// a constraint per prefix sum, and the final assertion
+		assert.LessOrEqual(ccs.GetNbConstraints(), n+1)
+		assert.NoError(ccs.IsSolved(w))
+
+		// the blueprint is serialized with the constraint system
+		var buf bytes.Buffer
+		_, err = ccs.WriteTo(&buf)
+		assert.NoError(err)
+		ccs2 := b.newCS(ecc.BN254)
+		_, err = ccs2.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.NoError(ccs2.IsSolved(w))
+
+		assignment.Sum = sum + 1
+		wrong, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(wrong))
+		assignment.Sum = sum
+	}
+}
@@ -0,0 +1,221 @@
+// Package memory implements a random-access memory with offline memory
+// checking.
+//
+// The memory holds a fixed number of cells, which are read and written at
+// variable addresses. Every access is given a time, the index of the access
+// starting from 1, the initial values of the cells having time 0. The prover
+// returns for each access the current value of the accessed cell and the time
+// of its previous access, which is asserted to be before the time of the
+// access. The access consumes the tuple (address, value, previous time) from
+// the memory and gives back the tuple (address, new value, time). Following
+// [BEG+91], the memory is consistent if the multiset of the given back tuples,
+// including the initial values, equals the multiset of the consumed tuples,
+// including the final values of the cells returned by the prover after the last
+// access. The multiset equality is checked with a log-derivative argument
+// [Haböck22] when the circuit is compiled.
+//
+// Every access costs a constant number of constraints, plus a range check of
+// the size of the number of accesses, and the argument costs a constant number
+// of constraints per cell. This is much cheaper than the mux trees of package
+// [github.com/consensys/gnark/std/selector] for memories larger than a few
+// cells. The builder must implement [frontend.Committer].
+//
+// [BEG+91]: https://doi.org/10.1109/SFCS.1991.185352
+// [Haböck22]: https://eprint.iacr.org/2022/1530
+package memory
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Memory is a random-access memory of a fixed number of cells.
+type Memory struct {
+	api     frontend.API
+	checker frontend.Rangechecker
+
+	init     []frontend.Variable
+	nbAccess int
+	// previous is an output of the previous access, which orders the accesses
+	// when solving.
+	previous frontend.Variable
+	closed   bool
+
+	// tuples (address, value, time) consumed and given back by the accesses
+	consumed, given [][3]frontend.Variable
+
+	// each memory has a unique blueprint, which replays the accesses
+	bID       constraint.BlueprintID
+	blueprint constraint.BlueprintMemory
+}
+
+// New returns a new [*Memory] with the cells initialized to init. It
+// additionally defers building the consistency argument.
+func New(api frontend.API, init []frontend.Variable) *Memory {
+	if len(init) == 0 {
+		panic("memory without cells")
+	}
+	m := &Memory{
+		api:     api,
+		checker: rangecheck.New(api),
+		init:    init,
+	}
+	for i := range init {
+		m.given = append(m.given, [3]frontend.Variable{i, init[i], 0})
+	}
+	m.bID = api.Compiler().AddBlueprint(&m.blueprint)
+	api.Compiler().Defer(m.commit)
+	return m
+}
+
+// Read returns the value of the cell at address addr. The address must be less
+// than the number of cells, otherwise no proof can be generated.
+func (m *Memory) Read(addr frontend.Variable) frontend.Variable {
+	value, _ := m.access(constraint.MemoryRead, addr, nil)
+	return value
+}
+
+// Write sets the cell at address addr to value. The address must be less than
+// the number of cells, otherwise no proof can be generated.
+func (m *Memory) Write(addr, value frontend.Variable) {
+	m.access(constraint.MemoryWrite, addr, value)
+}
+
+// ReadWrite sets the cell at address addr to value and returns its previous
+// value, for the cost of a single access.
+func (m *Memory) ReadWrite(addr, value frontend.Variable) (previous frontend.Variable) {
+	previous, _ = m.access(constraint.MemoryWrite, addr, value)
+	return previous
+}
+
+func (m *Memory) access(kind uint32, addr, newValue frontend.Variable) (value, previousTime frontend.Variable) {
+	if m.closed {
+		panic("memory already closed")
+	}
+	outputs := m.instruction(kind, addr, newValue)
+	value, previousTime = outputs[0], outputs[1]
+	m.nbAccess++
+	time := m.nbAccess
+
+	// the previous access is before this one: time - 1 - previousTime is in
+	// [0, 2^nbBits). As the consumed tuple is given back by a previous access,
+	// previousTime is a time and can't be negative.
+	if nbBits := bits.Len(uint(time - 1)); nbBits == 0 {
+		m.api.AssertIsEqual(previousTime, 0)
+	} else {
+		m.checker.Check(m.api.Sub(time-1, previousTime), nbBits)
+	}
+	if kind == constraint.MemoryRead {
+		newValue = value
+	}
+	m.consumed = append(m.consumed, [3]frontend.Variable{addr, value, previousTime})
+	m.given = append(m.given, [3]frontend.Variable{addr, newValue, time})
+	return value, previousTime
+}
+
+// instruction adds the instruction of the next access to the constraint
+// system and returns its outputs.
+func (m *Memory) instruction(kind uint32, inputs ...frontend.Variable) []frontend.Variable {
+	compiler := m.api.Compiler()
+	var init []constraint.Compressible
+	if m.nbAccess == 0 {
+		init = make([]constraint.Compressible, len(m.init))
+		for i := range m.init {
+			init[i] = compiler.ToCanonicalVariable(m.init[i])
+		}
+	}
+	if m.previous != nil {
+		inputs = append(inputs, m.previous)
+	}
+	var toCompress []constraint.Compressible
+	for _, in := range inputs {
+		if in != nil {
+			toCompress = append(toCompress, compiler.ToCanonicalVariable(in))
+		}
+	}
+	calldata := m.blueprint.MemoryAccess(m.nbAccess, kind, len(m.init), init, toCompress...)
+	wires := compiler.AddInstruction(m.bID, calldata)
+	outputs := make([]frontend.Variable, len(wires))
+	for i := range wires {
+		outputs[i] = compiler.InternalVariable(wires[i])
+	}
+	m.previous = outputs[0]
+	return outputs
+}
+
+func (m *Memory) commit(api frontend.API) error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	// the final values and times of the cells are consumed
+	final := m.instruction(constraint.MemoryFinal)
+	size := len(m.init)
+	for i := 0; i < size; i++ {
+		m.consumed = append(m.consumed, [3]frontend.Variable{i, final[i], final[size+i]})
+	}
+
+	var toCommit []frontend.Variable
+	for _, tuples := range [][][3]frontend.Variable{m.consumed, m.given} {
+		for _, t := range tuples {
+			for _, v := range t {
+				if _, isConst := api.Compiler().ConstantValue(v); !isConst {
+					toCommit = append(toCommit, v)
+				}
+			}
+		}
+	}
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		coeffs, err := randomCoefficients(api, commitment)
+		if err != nil {
+			return err
+		}
+		lhs := sumOfInverses(api, commitment, coeffs, m.consumed)
+		rhs := sumOfInverses(api, commitment, coeffs, m.given)
+		api.AssertIsEqual(lhs, rhs)
+		return nil
+	}, toCommit...)
+	return nil
+}
+
+// randomCoefficients returns the coefficients of the random linear combination
+// of the values and times of the tuples.
+func randomCoefficients(api frontend.API, commitment frontend.Variable) ([2]frontend.Variable, error) {
+	var coeffs [2]frontend.Variable
+	hasher, err := mimc.NewMiMC(api)
+	if err != nil {
+		return coeffs, fmt.Errorf("new hasher: %w", err)
+	}
+	for i := range coeffs {
+		hasher.Reset()
+		hasher.Write(i+1, commitment)
+		coeffs[i] = hasher.Sum()
+	}
+	return coeffs, nil
+}
+
+// sumOfInverses returns ∑ 1/(x - (a + c₀·v + c₁·t)) over the tuples (a, v, t).
+func sumOfInverses(api frontend.API, x frontend.Variable, coeffs [2]frontend.Variable, tuples [][3]frontend.Variable) frontend.Variable {
+	toInvert := make([]frontend.Variable, len(tuples))
+	for i, t := range tuples {
+		toInvert[i] = api.Sub(x, t[0], api.Mul(coeffs[0], t[1]), api.Mul(coeffs[1], t[2]))
+	}
+	if bapi, ok := api.(frontend.BatchInverter); ok {
+		toInvert = bapi.BatchInvert(toInvert)
+	} else {
+		for i := range toInvert {
+			toInvert[i] = api.Inverse(toInvert[i])
+		}
+	}
+	var res frontend.Variable = 0
+	for i := range toInvert {
+		res = api.Add(res, toInvert[i])
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type memoryCircuit struct {
+	Init     [8]frontend.Variable
+	Addrs    [4]frontend.Variable
+	Values   [4]frontend.Variable
+	Expected [4]frontend.Variable
+}
+
+func (c *memoryCircuit) Define(api frontend.API) error {
+	m := New(api, c.Init[:])
+	for i := range c.Addrs {
+		// read the cell, then increment it by the value
+		v := m.Read(c.Addrs[i])
+		api.AssertIsEqual(v, c.Expected[i])
+		m.Write(c.Addrs[i], api.Add(v, c.Values[i]))
+	}
+	// swap the first two cells
+	a := m.ReadWrite(0, m.Read(1))
+	m.Write(1, a)
+	return nil
+}
+
+func TestMemory(t *testing.T) {
+	assert := test.NewAssert(t)
+	init := [8]frontend.Variable{10, 11, 12, 13, 14, 15, 16, 17}
+	assert.CheckCircuit(&memoryCircuit{},
+		test.WithValidAssignment(&memoryCircuit{
+			Init:     init,
+			Addrs:    [4]frontend.Variable{3, 5, 3, 7},
+			Values:   [4]frontend.Variable{1, 2, 3, 4},
+			Expected: [4]frontend.Variable{13, 15, 14, 17},
+		}),
+		test.WithInvalidAssignment(&memoryCircuit{
+			Init:     init,
+			Addrs:    [4]frontend.Variable{3, 5, 3, 7},
+			Values:   [4]frontend.Variable{1, 2, 3, 4},
+			Expected: [4]frontend.Variable{13, 15, 13, 17},
+		}),
+		test.WithInvalidAssignment(&memoryCircuit{
+			Init:     init,
+			Addrs:    [4]frontend.Variable{3, 5, 8, 7},
+			Values:   [4]frontend.Variable{1, 2, 3, 4},
+			Expected: [4]frontend.Variable{13, 15, 0, 17},
+		}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(),
+	)
+}
@@ -0,0 +1,79 @@
+package ml
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{divHint, isNonNegativeHint, maxHint, splitHint}
+}
+
+// signed returns the signed integer represented by the field element v.
+func signed(mod, v *big.Int) *big.Int {
+	if v.Cmp(new(big.Int).Rsh(mod, 1)) > 0 {
+		return new(big.Int).Sub(v, mod)
+	}
+	return new(big.Int).Set(v)
+}
+
+// divHint returns the Euclidean division of the signed integer x by d > 0.
+func divHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 {
+		return fmt.Errorf("expected 2 inputs and 2 outputs")
+	}
+	if inputs[1].Sign() == 0 {
+		return fmt.Errorf("division by zero")
+	}
+	outputs[0].DivMod(signed(mod, inputs[0]), inputs[1], outputs[1])
+	outputs[0].Mod(outputs[0], mod)
+	return nil
+}
+
+// isNonNegativeHint returns 1 if the signed integer x is non-negative, 0
+// otherwise.
+func isNonNegativeHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expected 1 input and 1 output")
+	}
+	if signed(mod, inputs[0]).Sign() >= 0 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+	}
+	return nil
+}
+
+// maxHint returns the maximum of the signed integers xs.
+func maxHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) == 0 || len(outputs) != 1 {
+		return fmt.Errorf("expected inputs and 1 output")
+	}
+	m := signed(mod, inputs[0])
+	for i := 1; i < len(inputs); i++ {
+		if v := signed(mod, inputs[i]); v.Cmp(m) > 0 {
+			m = v
+		}
+	}
+	outputs[0].Mod(m, mod)
+	return nil
+}
+
+// splitHint returns (x >> n, x mod 2ⁿ).
+func splitHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 {
+		return fmt.Errorf("expected 2 inputs and 2 outputs")
+	}
+	n := uint(inputs[1].Uint64())
+	outputs[0].Rsh(inputs[0], n)
+	outputs[1].Sub(inputs[0], new(big.Int).Lsh(outputs[0], n))
+	return nil
+}
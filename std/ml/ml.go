@@ -0,0 +1,263 @@
+// Package ml implements the operators of quantized neural networks.
+//
+// The values are signed fixed-point numbers: a variable v represents v/2^f,
+// where f is the number of fractional bits, and v must fit in a signed integer
+// of nbBits bits. The operators range check their outputs, so that a proof
+// can't be computed if an intermediate value overflows: the model must be
+// quantized so that its values fit in nbBits bits. The weights can be
+// quantized with [Quantize] and read with [ReadNPY] or [ReadSafetensors].
+//
+// The products of fixed-point numbers are rescaled with rounding and the
+// non-linear functions are computed with range checks and lookup tables, whose
+// sizes depend on the parameters: softmax uses tables of 2^f and 2^(nbBits-f)
+// entries, which must be at most 2^16.
+package ml
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/matrix"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// maxTableBits bounds the sizes of the lookup tables of softmax.
+const maxTableBits = 16
+
+// FixedPoint implements the operators over fixed-point numbers of nbBits bits
+// with f fractional bits.
+type FixedPoint struct {
+	api    frontend.API
+	rc     frontend.Rangechecker
+	nbBits int
+	frac   int
+
+	// lookup tables of the exponential, initialized on first use
+	expFrac *logderivlookup.Table // lo ↦ 2^f·exp(-lo/2^f), lo < 2^f
+	expInt  *logderivlookup.Table // hi ↦ 2^f·exp(-hi), hi < 2^(nbBits-f)
+}
+
+// New returns a [FixedPoint] for the numbers of nbBits bits with frac
+// fractional bits.
+func New(api frontend.API, nbBits, frac int) (*FixedPoint, error) {
+	if nbBits < 2 || frac < 0 || frac >= nbBits {
+		return nil, fmt.Errorf("invalid fixed-point parameters: %d bits, %d fractional bits", nbBits, frac)
+	}
+	// the products and sums of the matrix products must not overflow
+	if 2*nbBits+32 >= api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("%d bits too large for the field", nbBits)
+	}
+	return &FixedPoint{
+		api:    api,
+		rc:     rangecheck.New(api),
+		nbBits: nbBits,
+		frac:   frac,
+	}, nil
+}
+
+// assertSigned asserts that v is in [-2^(nbBits-1), 2^(nbBits-1)).
+func (fp *FixedPoint) assertSigned(v frontend.Variable) {
+	fp.rc.Check(fp.api.Add(v, new(big.Int).Lsh(big.NewInt(1), uint(fp.nbBits-1))), fp.nbBits)
+}
+
+// assertLess asserts that 0 ≤ v < bound, for a constant bound.
+func (fp *FixedPoint) assertLess(v frontend.Variable, bound *big.Int) {
+	nbBits := new(big.Int).Sub(bound, big.NewInt(1)).BitLen()
+	if nbBits == 0 {
+		fp.api.AssertIsEqual(v, 0)
+		return
+	}
+	fp.rc.Check(v, nbBits)
+	if bound.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(nbBits))) != 0 {
+		fp.rc.Check(fp.api.Sub(new(big.Int).Sub(bound, big.NewInt(1)), v), nbBits)
+	}
+}
+
+// DivRound returns x/d rounded to the nearest integer, for a positive constant
+// d. The result must be a fixed-point number.
+func (fp *FixedPoint) DivRound(x frontend.Variable, d *big.Int) frontend.Variable {
+	if d.Sign() <= 0 {
+		panic("divisor must be positive")
+	}
+	// x + ⌊d/2⌋ = q·d + r, 0 ≤ r < d
+	x = fp.api.Add(x, new(big.Int).Rsh(d, 1))
+	res, err := fp.api.Compiler().NewHint(divHint, 2, x, d)
+	if err != nil {
+		panic(fmt.Sprintf("new hint: %v", err))
+	}
+	q, r := res[0], res[1]
+	fp.assertLess(r, d)
+	fp.assertSigned(q)
+	fp.api.AssertIsEqual(x, fp.api.Add(fp.api.Mul(q, d), r))
+	return q
+}
+
+// Rescale returns x/2^f rounded to the nearest integer, to rescale the
+// product of two fixed-point numbers.
+func (fp *FixedPoint) Rescale(x frontend.Variable) frontend.Variable {
+	return fp.DivRound(x, new(big.Int).Lsh(big.NewInt(1), uint(fp.frac)))
+}
+
+// Mul returns the product of the fixed-point numbers x and y.
+func (fp *FixedPoint) Mul(x, y frontend.Variable) frontend.Variable {
+	return fp.Rescale(fp.api.Mul(x, y))
+}
+
+// MatMul returns the product of the n×m matrix a by the m×p matrix b of
+// fixed-point numbers. The product is checked with [matrix.AssertProduct]
+// before rescaling, which needs a builder implementing [frontend.Committer].
+func (fp *FixedPoint) MatMul(a, b [][]frontend.Variable) [][]frontend.Variable {
+	c := matrix.Mul(fp.api, a, b)
+	for i := range c {
+		for j := range c[i] {
+			c[i][j] = fp.Rescale(c[i][j])
+		}
+	}
+	return c
+}
+
+// Dense returns x·w + bias, the output of a fully connected layer with the
+// input vector x of length m, the m×p matrix of weights w and the vector bias
+// of length p.
+func (fp *FixedPoint) Dense(x []frontend.Variable, w [][]frontend.Variable, bias []frontend.Variable) []frontend.Variable {
+	res := fp.MatMul([][]frontend.Variable{x}, w)[0]
+	if len(bias) != len(res) {
+		panic(fmt.Sprintf("got %d biases, expected %d", len(bias), len(res)))
+	}
+	for i := range res {
+		res[i] = fp.api.Add(res[i], bias[i])
+		fp.assertSigned(res[i])
+	}
+	return res
+}
+
+// ReLU returns max(x, 0).
+func (fp *FixedPoint) ReLU(x frontend.Variable) frontend.Variable {
+	res, err := fp.api.Compiler().NewHint(isNonNegativeHint, 1, x)
+	if err != nil {
+		panic(fmt.Sprintf("new hint: %v", err))
+	}
+	s := res[0]
+	fp.api.AssertIsBoolean(s)
+	// t = x if x ≥ 0, -x-1 otherwise, must be in [0, 2^(nbBits-1))
+	t := fp.api.Sub(fp.api.Mul(fp.api.Sub(fp.api.Add(s, s), 1), x), fp.api.Sub(1, s))
+	fp.rc.Check(t, fp.nbBits-1)
+	return fp.api.Mul(s, x)
+}
+
+// Max returns the maximum of xs.
+func (fp *FixedPoint) Max(xs ...frontend.Variable) frontend.Variable {
+	if len(xs) == 0 {
+		panic("no values")
+	}
+	if len(xs) == 1 {
+		return xs[0]
+	}
+	res, err := fp.api.Compiler().NewHint(maxHint, 1, xs...)
+	if err != nil {
+		panic(fmt.Sprintf("new hint: %v", err))
+	}
+	m := res[0]
+	fp.assertSigned(m)
+	// m ≥ xᵢ for all i and m is one of the xᵢ
+	prod := frontend.Variable(1)
+	for i := range xs {
+		d := fp.api.Sub(m, xs[i])
+		fp.rc.Check(d, fp.nbBits)
+		prod = fp.api.Mul(prod, d)
+	}
+	fp.api.AssertIsEqual(prod, 0)
+	return m
+}
+
+// MaxPool2D returns the maximums of the size×size windows of x, moved by
+// stride.
+func (fp *FixedPoint) MaxPool2D(x [][]frontend.Variable, size, stride int) [][]frontend.Variable {
+	return pool2D(x, size, stride, func(window []frontend.Variable) frontend.Variable {
+		return fp.Max(window...)
+	})
+}
+
+// AvgPool2D returns the rounded averages of the size×size windows of x, moved
+// by stride.
+func (fp *FixedPoint) AvgPool2D(x [][]frontend.Variable, size, stride int) [][]frontend.Variable {
+	return pool2D(x, size, stride, func(window []frontend.Variable) frontend.Variable {
+		return fp.DivRound(fp.api.Add(0, 0, window...), big.NewInt(int64(len(window))))
+	})
+}
+
+func pool2D(x [][]frontend.Variable, size, stride int, f func([]frontend.Variable) frontend.Variable) [][]frontend.Variable {
+	if size <= 0 || stride <= 0 || len(x) < size || len(x[0]) < size {
+		panic("invalid pooling window")
+	}
+	res := make([][]frontend.Variable, (len(x)-size)/stride+1)
+	for i := range res {
+		res[i] = make([]frontend.Variable, (len(x[0])-size)/stride+1)
+		for j := range res[i] {
+			window := make([]frontend.Variable, 0, size*size)
+			for k := 0; k < size; k++ {
+				window = append(window, x[i*stride+k][j*stride:j*stride+size]...)
+			}
+			res[i][j] = f(window)
+		}
+	}
+	return res
+}
+
+// Softmax returns an approximation of exp(xᵢ)/Σⱼexp(xⱼ). The exponentials are
+// computed on xᵢ - max(x) with lookup tables, with an error of a few units in
+// the last place, and the outputs are rounded down.
+func (fp *FixedPoint) Softmax(xs []frontend.Variable) []frontend.Variable {
+	fp.initExp()
+	m := fp.Max(xs...)
+	e := make([]frontend.Variable, len(xs))
+	for i := range xs {
+		// d = m-xᵢ = hi·2^f + lo, the lookups check the bounds of hi and lo
+		res, err := fp.api.Compiler().NewHint(splitHint, 2, fp.api.Sub(m, xs[i]), fp.frac)
+		if err != nil {
+			panic(fmt.Sprintf("new hint: %v", err))
+		}
+		hi, lo := res[0], res[1]
+		fp.api.AssertIsEqual(fp.api.Sub(m, xs[i]), fp.api.Add(fp.api.Mul(hi, 1<<fp.frac), lo))
+		e[i] = fp.Mul(fp.expFrac.Lookup(lo)[0], fp.expInt.Lookup(hi)[0])
+	}
+	// the maximum contributes 2^f to the sum
+	sum := fp.api.Add(0, 0, e...)
+	sumBits := fp.frac + 1 + bits.Len(uint(len(xs)))
+	res := make([]frontend.Variable, len(xs))
+	for i := range e {
+		// e·2^f = q·sum + r, 0 ≤ r < sum
+		qr, err := fp.api.Compiler().NewHint(divHint, 2, fp.api.Mul(e[i], 1<<fp.frac), sum)
+		if err != nil {
+			panic(fmt.Sprintf("new hint: %v", err))
+		}
+		fp.rc.Check(qr[0], fp.frac+1)
+		fp.rc.Check(qr[1], sumBits)
+		fp.rc.Check(fp.api.Sub(sum, fp.api.Add(qr[1], 1)), sumBits)
+		fp.api.AssertIsEqual(fp.api.Mul(e[i], 1<<fp.frac), fp.api.Add(fp.api.Mul(qr[0], sum), qr[1]))
+		res[i] = qr[0]
+	}
+	return res
+}
+
+func (fp *FixedPoint) initExp() {
+	if fp.expFrac != nil {
+		return
+	}
+	if fp.frac > maxTableBits || fp.nbBits-fp.frac > maxTableBits {
+		panic(fmt.Sprintf("softmax tables too large for %d bits with %d fractional bits", fp.nbBits, fp.frac))
+	}
+	scale := math.Ldexp(1, fp.frac)
+	fp.expFrac = logderivlookup.New(fp.api)
+	for lo := 0; lo < 1<<fp.frac; lo++ {
+		fp.expFrac.Insert(int64(math.Round(scale * math.Exp(-float64(lo)/scale))))
+	}
+	fp.expInt = logderivlookup.New(fp.api)
+	for hi := 0; hi < 1<<(fp.nbBits-fp.frac); hi++ {
+		fp.expInt.Insert(int64(math.Round(scale * math.Exp(-float64(hi)))))
+	}
+}
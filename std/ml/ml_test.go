@@ -0,0 +1,217 @@
+package ml
+
+import (
+	"math"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	testBits = 16
+	testFrac = 8
+)
+
+// divRound is the reference of DivRound.
+func divRound(x, d int64) int64 {
+	q := (x + d/2) / d
+	if (x+d/2)%d < 0 {
+		q--
+	}
+	return q
+}
+
+type opsCircuit struct {
+	X, Y   []frontend.Variable
+	Mul    []frontend.Variable
+	ReLU   []frontend.Variable
+	Max    frontend.Variable
+	Dense  []frontend.Variable
+	Bias   []frontend.Variable
+	Weight [][]frontend.Variable
+}
+
+func (c *opsCircuit) Define(api frontend.API) error {
+	fp, err := New(api, testBits, testFrac)
+	if err != nil {
+		return err
+	}
+	for i := range c.X {
+		api.AssertIsEqual(fp.Mul(c.X[i], c.Y[i]), c.Mul[i])
+		api.AssertIsEqual(fp.ReLU(c.X[i]), c.ReLU[i])
+	}
+	api.AssertIsEqual(fp.Max(c.X...), c.Max)
+	dense := fp.Dense(c.X, c.Weight, c.Bias)
+	for i := range dense {
+		api.AssertIsEqual(dense[i], c.Dense[i])
+	}
+	return nil
+}
+
+func TestOperators(t *testing.T) {
+	assert := test.NewAssert(t)
+	x := []int64{-300, 512, 77, -1}
+	y := []int64{256, -129, 1000, 3}
+	w := [][]int64{{1, -2}, {300, 4}, {-5, 600}, {7, 8}}
+	bias := []int64{10, -10}
+
+	assignment := &opsCircuit{Max: int64(512)}
+	for i := range x {
+		assignment.X = append(assignment.X, x[i])
+		assignment.Y = append(assignment.Y, y[i])
+		assignment.Mul = append(assignment.Mul, divRound(x[i]*y[i], 1<<testFrac))
+		assignment.ReLU = append(assignment.ReLU, max(x[i], 0))
+		assignment.Weight = append(assignment.Weight, []frontend.Variable{w[i][0], w[i][1]})
+	}
+	for j := range bias {
+		var s int64
+		for i := range x {
+			s += x[i] * w[i][j]
+		}
+		assignment.Bias = append(assignment.Bias, bias[j])
+		assignment.Dense = append(assignment.Dense, divRound(s, 1<<testFrac)+bias[j])
+	}
+	invalid := *assignment
+	invalid.ReLU = []frontend.Variable{-300, 512, 77, -1}
+
+	circuit := &opsCircuit{
+		X: make([]frontend.Variable, len(x)), Y: make([]frontend.Variable, len(x)),
+		Mul: make([]frontend.Variable, len(x)), ReLU: make([]frontend.Variable, len(x)),
+		Dense: make([]frontend.Variable, len(bias)), Bias: make([]frontend.Variable, len(bias)),
+		Weight: [][]frontend.Variable{make([]frontend.Variable, 2), make([]frontend.Variable, 2), make([]frontend.Variable, 2), make([]frontend.Variable, 2)},
+	}
+	assert.CheckCircuit(circuit,
+		test.WithValidAssignment(assignment),
+		test.WithInvalidAssignment(&invalid),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type overflowCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *overflowCircuit) Define(api frontend.API) error {
+	fp, err := New(api, testBits, testFrac)
+	if err != nil {
+		return err
+	}
+	fp.Mul(c.X, c.Y)
+	return nil
+}
+
+func TestOverflow(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&overflowCircuit{},
+		test.WithValidAssignment(&overflowCircuit{X: 1 << 10, Y: 1 << 10}),
+		test.WithInvalidAssignment(&overflowCircuit{X: 1 << 14, Y: 1 << 14}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type poolCircuit struct {
+	X        [][]frontend.Variable
+	Max, Avg [][]frontend.Variable
+}
+
+func (c *poolCircuit) Define(api frontend.API) error {
+	fp, err := New(api, testBits, testFrac)
+	if err != nil {
+		return err
+	}
+	maxPool, avgPool := fp.MaxPool2D(c.X, 2, 1), fp.AvgPool2D(c.X, 2, 1)
+	for i := range maxPool {
+		for j := range maxPool[i] {
+			api.AssertIsEqual(maxPool[i][j], c.Max[i][j])
+			api.AssertIsEqual(avgPool[i][j], c.Avg[i][j])
+		}
+	}
+	return nil
+}
+
+func TestPool(t *testing.T) {
+	assert := test.NewAssert(t)
+	assignment := &poolCircuit{
+		X:   [][]frontend.Variable{{1, -2, 3}, {-4, 5, -6}, {7, -8, 9}},
+		Max: [][]frontend.Variable{{5, 5}, {7, 9}},
+		Avg: [][]frontend.Variable{{divRound(0, 4), divRound(0, 4)}, {divRound(0, 4), divRound(0, 4)}},
+	}
+	invalid := &poolCircuit{X: assignment.X, Max: [][]frontend.Variable{{5, 5}, {7, 8}}, Avg: assignment.Avg}
+	circuit := &poolCircuit{
+		X:   [][]frontend.Variable{make([]frontend.Variable, 3), make([]frontend.Variable, 3), make([]frontend.Variable, 3)},
+		Max: [][]frontend.Variable{make([]frontend.Variable, 2), make([]frontend.Variable, 2)},
+		Avg: [][]frontend.Variable{make([]frontend.Variable, 2), make([]frontend.Variable, 2)},
+	}
+	assert.CheckCircuit(circuit,
+		test.WithValidAssignment(assignment),
+		test.WithInvalidAssignment(invalid),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type softmaxCircuit struct {
+	X, Expected []frontend.Variable
+}
+
+func (c *softmaxCircuit) Define(api frontend.API) error {
+	fp, err := New(api, testBits, testFrac)
+	if err != nil {
+		return err
+	}
+	res := fp.Softmax(c.X)
+	for i := range res {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+// softmax is the reference of Softmax.
+func softmax(xs []int64) []int64 {
+	const scale = 1 << testFrac
+	m := xs[0]
+	for _, x := range xs {
+		m = max(m, x)
+	}
+	e := make([]int64, len(xs))
+	var sum int64
+	for i, x := range xs {
+		hi, lo := (m-x)>>testFrac, (m-x)&(scale-1)
+		eLo := int64(math.Round(scale * math.Exp(-float64(lo)/scale)))
+		eHi := int64(math.Round(scale * math.Exp(-float64(hi))))
+		e[i] = divRound(eLo*eHi, scale)
+		sum += e[i]
+	}
+	res := make([]int64, len(xs))
+	for i := range e {
+		res[i] = e[i] * scale / sum
+	}
+	return res
+}
+
+func TestSoftmax(t *testing.T) {
+	assert := test.NewAssert(t)
+	xs := []int64{256, -512, 100, 1000, 0}
+	expected := softmax(xs)
+
+	// the approximation is close to the softmax
+	var sum float64
+	for _, x := range xs {
+		sum += math.Exp(float64(x) / (1 << testFrac))
+	}
+	for i, x := range xs {
+		exact := math.Exp(float64(x)/(1<<testFrac)) / sum
+		assert.InDelta(exact, float64(expected[i])/(1<<testFrac), 0.02)
+	}
+
+	assignment := &softmaxCircuit{}
+	for i := range xs {
+		assignment.X = append(assignment.X, xs[i])
+		assignment.Expected = append(assignment.Expected, expected[i])
+	}
+	invalid := &softmaxCircuit{X: assignment.X, Expected: make([]frontend.Variable, len(xs))}
+	copy(invalid.Expected, assignment.Expected)
+	invalid.Expected[3] = expected[3] + 1
+	assert.CheckCircuit(&softmaxCircuit{X: make([]frontend.Variable, len(xs)), Expected: make([]frontend.Variable, len(xs))},
+		test.WithValidAssignment(assignment),
+		test.WithInvalidAssignment(invalid),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
@@ -0,0 +1,251 @@
+package ml
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Quantize returns v as a fixed-point number with frac fractional bits, that
+// is round(v·2^frac).
+func Quantize(v float64, frac int) *big.Int {
+	res, _ := new(big.Float).SetFloat64(math.Round(math.Ldexp(v, frac))).Int(nil)
+	return res
+}
+
+// QuantizeSlice returns the values quantized with [Quantize], to be used in a
+// witness assignment.
+func QuantizeSlice(vs []float64, frac int) []frontend.Variable {
+	res := make([]frontend.Variable, len(vs))
+	for i := range vs {
+		res[i] = Quantize(vs[i], frac)
+	}
+	return res
+}
+
+// QuantizeMatrix returns the rows×(len(vs)/rows) matrix of the values, given in
+// row-major order, quantized with [Quantize].
+func QuantizeMatrix(vs []float64, rows, frac int) ([][]frontend.Variable, error) {
+	if rows <= 0 || len(vs)%rows != 0 {
+		return nil, fmt.Errorf("%d values can't be split in %d rows", len(vs), rows)
+	}
+	q := QuantizeSlice(vs, frac)
+	cols := len(vs) / rows
+	res := make([][]frontend.Variable, rows)
+	for i := range res {
+		res[i] = q[i*cols : (i+1)*cols]
+	}
+	return res, nil
+}
+
+// Tensor is an array of values in row-major order.
+type Tensor struct {
+	Shape []int
+	Data  []float64
+}
+
+// readValues reads n values of the given type, in little-endian.
+func readValues(r io.Reader, dtype string, n int) ([]float64, error) {
+	var size int
+	switch dtype {
+	case "f4", "i4":
+		size = 4
+	case "f8", "i8":
+		size = 8
+	case "i2":
+		size = 2
+	case "i1", "u1":
+		size = 1
+	default:
+		return nil, fmt.Errorf("unsupported type %q", dtype)
+	}
+	buf := make([]byte, size)
+	res := make([]float64, n)
+	for i := range res {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("read value %d: %w", i, err)
+		}
+		switch dtype {
+		case "f4":
+			res[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf)))
+		case "f8":
+			res[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf))
+		case "i1":
+			res[i] = float64(int8(buf[0]))
+		case "u1":
+			res[i] = float64(buf[0])
+		case "i2":
+			res[i] = float64(int16(binary.LittleEndian.Uint16(buf)))
+		case "i4":
+			res[i] = float64(int32(binary.LittleEndian.Uint32(buf)))
+		case "i8":
+			res[i] = float64(int64(binary.LittleEndian.Uint64(buf)))
+		}
+	}
+	return res, nil
+}
+
+func nbValues(shape []int) (int, error) {
+	n := 1
+	for _, d := range shape {
+		if d < 0 {
+			return 0, fmt.Errorf("invalid shape %v", shape)
+		}
+		n *= d
+	}
+	return n, nil
+}
+
+var npyHeader = regexp.MustCompile(`'descr':\s*'([<|])([a-z]\d)',\s*'fortran_order':\s*(True|False),\s*'shape':\s*\(([\d,\s]*)\)`)
+
+// ReadNPY reads an array in the NumPy .npy format, as written by numpy.save.
+// The array must be in C order, with little-endian floats or integers.
+func ReadNPY(r io.Reader) (Tensor, error) {
+	br := bufio.NewReader(r)
+	var magic [8]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return Tensor{}, fmt.Errorf("read magic: %w", err)
+	}
+	if string(magic[:6]) != "\x93NUMPY" {
+		return Tensor{}, errors.New("not a npy file")
+	}
+	var headerLen int
+	switch magic[6] {
+	case 1:
+		var l [2]byte
+		if _, err := io.ReadFull(br, l[:]); err != nil {
+			return Tensor{}, fmt.Errorf("read header length: %w", err)
+		}
+		headerLen = int(binary.LittleEndian.Uint16(l[:]))
+	case 2, 3:
+		var l [4]byte
+		if _, err := io.ReadFull(br, l[:]); err != nil {
+			return Tensor{}, fmt.Errorf("read header length: %w", err)
+		}
+		headerLen = int(binary.LittleEndian.Uint32(l[:]))
+	default:
+		return Tensor{}, fmt.Errorf("unsupported npy version %d", magic[6])
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return Tensor{}, fmt.Errorf("read header: %w", err)
+	}
+	m := npyHeader.FindStringSubmatch(string(header))
+	if m == nil {
+		return Tensor{}, fmt.Errorf("unsupported header %q", header)
+	}
+	if m[3] == "True" {
+		return Tensor{}, errors.New("fortran order not supported")
+	}
+	var t Tensor
+	for _, d := range strings.Split(m[4], ",") {
+		if d = strings.TrimSpace(d); d == "" {
+			continue
+		}
+		v, err := strconv.Atoi(d)
+		if err != nil {
+			return Tensor{}, fmt.Errorf("invalid shape: %w", err)
+		}
+		t.Shape = append(t.Shape, v)
+	}
+	n, err := nbValues(t.Shape)
+	if err != nil {
+		return Tensor{}, err
+	}
+	if t.Data, err = readValues(br, m[2], n); err != nil {
+		return Tensor{}, err
+	}
+	return t, nil
+}
+
+var safetensorsTypes = map[string]string{
+	"F32": "f4",
+	"F64": "f8",
+	"I8":  "i1",
+	"U8":  "u1",
+	"I16": "i2",
+	"I32": "i4",
+	"I64": "i8",
+}
+
+// ReadSafetensors reads the tensors of a file in the safetensors format, by
+// name. The tensors must have float or integer types.
+func ReadSafetensors(r io.Reader) (map[string]Tensor, error) {
+	var l [8]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, fmt.Errorf("read header length: %w", err)
+	}
+	headerLen := binary.LittleEndian.Uint64(l[:])
+	if headerLen > 100<<20 {
+		return nil, fmt.Errorf("header too large: %d bytes", headerLen)
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(header, &entries); err != nil {
+		return nil, fmt.Errorf("parse header: %w", err)
+	}
+	type entry struct {
+		name    string
+		DType   string   `json:"dtype"`
+		Shape   []int    `json:"shape"`
+		Offsets [2]int64 `json:"data_offsets"`
+	}
+	var tensors []entry
+	for name, raw := range entries {
+		if name == "__metadata__" {
+			continue
+		}
+		e := entry{name: name}
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("parse tensor %s: %w", name, err)
+		}
+		tensors = append(tensors, e)
+	}
+	// the tensors are read in the order of the data
+	sort.Slice(tensors, func(i, j int) bool { return tensors[i].Offsets[0] < tensors[j].Offsets[0] })
+
+	res := make(map[string]Tensor, len(tensors))
+	var offset int64
+	for _, e := range tensors {
+		dtype, ok := safetensorsTypes[e.DType]
+		if !ok {
+			return nil, fmt.Errorf("tensor %s: unsupported type %s", e.name, e.DType)
+		}
+		if e.Offsets[0] < offset {
+			return nil, fmt.Errorf("tensor %s: overlapping data", e.name)
+		}
+		if _, err := io.CopyN(io.Discard, r, e.Offsets[0]-offset); err != nil {
+			return nil, fmt.Errorf("tensor %s: %w", e.name, err)
+		}
+		n, err := nbValues(e.Shape)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %s: %w", e.name, err)
+		}
+		// the size of the values is the digit of the NumPy type
+		size, _ := strconv.Atoi(dtype[1:])
+		if e.Offsets[1]-e.Offsets[0] != int64(n*size) {
+			return nil, fmt.Errorf("tensor %s: data size mismatch", e.name)
+		}
+		data, err := readValues(r, dtype, n)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %s: %w", e.name, err)
+		}
+		offset = e.Offsets[1]
+		res[e.name] = Tensor{Shape: e.Shape, Data: data}
+	}
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package ml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantize(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal(big.NewInt(384), Quantize(1.5, 8))
+	assert.Equal(big.NewInt(-384), Quantize(-1.5, 8))
+	assert.Equal(big.NewInt(1), Quantize(0.003, 8))
+
+	m, err := QuantizeMatrix([]float64{1, 2, 3, 4, 5, 6}, 2, 0)
+	assert.NoError(err)
+	assert.Len(m, 2)
+	assert.Equal(big.NewInt(6), m[1][2])
+	_, err = QuantizeMatrix([]float64{1, 2, 3}, 2, 0)
+	assert.Error(err)
+}
+
+func TestReadNPY(t *testing.T) {
+	assert := require.New(t)
+	// numpy.save(f, numpy.array([[1.5, -2], [0, 3.25], [4, 5]], dtype='<f4'))
+	header := "{'descr': '<f4', 'fortran_order': False, 'shape': (3, 2), }"
+	for (10+len(header)+1)%64 != 0 {
+		header += " "
+	}
+	header += "\n"
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	values := []float32{1.5, -2, 0, 3.25, 4, 5}
+	binary.Write(&buf, binary.LittleEndian, values)
+
+	tensor, err := ReadNPY(&buf)
+	assert.NoError(err)
+	assert.Equal([]int{3, 2}, tensor.Shape)
+	assert.Equal([]float64{1.5, -2, 0, 3.25, 4, 5}, tensor.Data)
+
+	_, err = ReadNPY(bytes.NewReader([]byte("not a npy file")))
+	assert.Error(err)
+}
+
+func TestReadSafetensors(t *testing.T) {
+	assert := require.New(t)
+	header := `{"__metadata__":{"format":"pt"},"bias":{"dtype":"F64","shape":[2],"data_offsets":[6,22]},"weight":{"dtype":"I8","shape":[2,3],"data_offsets":[0,6]}}`
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint64(len(header)))
+	buf.WriteString(header)
+	buf.Write([]byte{1, 0xff, 2, 0xfe, 3, 0xfd})
+	binary.Write(&buf, binary.LittleEndian, []float64{0.5, math.Pi})
+
+	tensors, err := ReadSafetensors(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Len(tensors, 2)
+	assert.Equal(Tensor{Shape: []int{2, 3}, Data: []float64{1, -1, 2, -2, 3, -3}}, tensors["weight"])
+	assert.Equal(Tensor{Shape: []int{2}, Data: []float64{0.5, math.Pi}}, tensors["bias"])
+
+	// truncated data
+	_, err = ReadSafetensors(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+}
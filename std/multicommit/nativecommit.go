@@ -13,6 +13,18 @@
 // committed variables and then uses this commitment to derive a per-callback
 // unique commitment. The callbacks are then called with these unique derived
 // commitments instead.
+//
+// The ordering is fixed by the order of the calls in the circuit definition:
+//   - the committed variables are all the variables given to [WithCommitment],
+//     [WithNamedCommitment] and [Contribute], in the order of the calls;
+//   - the callbacks are called in the order of their registration, the first
+//     one receiving the root commitment and each following distinct commitment
+//     being the square of the previous one;
+//   - the callbacks registered with [WithNamedCommitment] under the same name
+//     share the commitment derived on the first registration of the name.
+//
+// This allows independent gadgets to contribute variables to the same
+// commitment and to agree on a challenge, only by sharing its name.
 package multicommit
 
 import (
@@ -26,6 +38,8 @@ type multicommitter struct {
 	closed bool
 	vars   []frontend.Variable
 	cbs    []WithCommitmentFn
+	// names are the names of the callbacks, empty for the unnamed ones.
+	names []string
 }
 
 type ctxMulticommitterKey struct{}
@@ -81,6 +95,10 @@ func (mct *multicommitter) commitAndCall(api frontend.API) error {
 	// close collecting input in case anyone wants to check more variables to commit to.
 	mct.closed = true
 	if len(mct.cbs) == 0 {
+		if len(mct.vars) != 0 {
+			// variables were contributed, but no gadget uses the commitment.
+			return nil
+		}
 		// shouldn't happen. we defer this function on creating multicommitter
 		// instance. It is probably some race.
 		panic("calling commiter with zero callbacks")
@@ -93,12 +111,27 @@ func (mct *multicommitter) commitAndCall(api frontend.API) error {
 	if err != nil {
 		return fmt.Errorf("commit: %w", err)
 	}
-	if err = mct.cbs[0](api, cmt); err != nil {
-		return fmt.Errorf("callback 0: %w", err)
-	}
-	for i := 1; i < len(mct.cbs); i++ {
-		cmt = api.Mul(cmt, cmt)
-		if err := mct.cbs[i](api, cmt); err != nil {
+	var (
+		derived frontend.Variable
+		named   = make(map[string]frontend.Variable)
+	)
+	for i, cb := range mct.cbs {
+		c, ok := named[mct.names[i]]
+		if mct.names[i] == "" || !ok {
+			if derived == nil {
+				derived = cmt
+			} else {
+				derived = api.Mul(derived, derived)
+			}
+			c = derived
+			if mct.names[i] != "" {
+				named[mct.names[i]] = c
+			}
+		}
+		if err := cb(api, c); err != nil {
+			if mct.names[i] != "" {
+				return fmt.Errorf("callback %d (%s): %w", i, mct.names[i], err)
+			}
 			return fmt.Errorf("callback %d: %w", i, err)
 		}
 	}
@@ -118,10 +151,29 @@ type WithCommitmentFn func(api frontend.API, commitment frontend.Variable) error
 // commitment. We append the variables committedVariables to be committed to
 // with the native [frontend.Committer] interface.
 func WithCommitment(api frontend.API, cb WithCommitmentFn, committedVariables ...frontend.Variable) {
+	WithNamedCommitment(api, "", cb, committedVariables...)
+}
+
+// WithNamedCommitment is as [WithCommitment], but all the callbacks scheduled
+// with the same name receive the same commitment, so that independent gadgets
+// can derive a shared challenge. An empty name is as [WithCommitment].
+func WithNamedCommitment(api frontend.API, name string, cb WithCommitmentFn, committedVariables ...frontend.Variable) {
 	mct := getCached(api)
 	if mct.closed {
 		panic("called WithCommitment recursively")
 	}
 	mct.vars = append(mct.vars, committedVariables...)
 	mct.cbs = append(mct.cbs, cb)
+	mct.names = append(mct.names, name)
+}
+
+// Contribute appends the variables committedVariables to the variables to
+// commit to, without scheduling a callback. It allows a gadget to bind its
+// variables to the commitments received by the other gadgets.
+func Contribute(api frontend.API, committedVariables ...frontend.Variable) {
+	mct := getCached(api)
+	if mct.closed {
+		panic("called Contribute in a commitment callback")
+	}
+	mct.vars = append(mct.vars, committedVariables...)
 }
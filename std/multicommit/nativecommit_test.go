@@ -70,3 +70,56 @@ func TestNoCommitVariable(t *testing.T) {
 	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
 	assert.Error(err)
 }
+
+type namedCommitmentCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *namedCommitmentCircuit) Define(api frontend.API) error {
+	var first, unnamed frontend.Variable
+	Contribute(api, c.Y)
+	WithNamedCommitment(api, "challenge", func(api frontend.API, commitment frontend.Variable) error {
+		first = commitment
+		return nil
+	}, c.X)
+	WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		api.AssertIsDifferent(first, commitment)
+		api.AssertIsEqual(api.Mul(first, first), commitment)
+		unnamed = commitment
+		return nil
+	})
+	// callbacks sharing a name receive the same commitment, and do not consume
+	// a derived commitment
+	WithNamedCommitment(api, "challenge", func(api frontend.API, commitment frontend.Variable) error {
+		api.AssertIsEqual(first, commitment)
+		return nil
+	})
+	WithNamedCommitment(api, "other", func(api frontend.API, commitment frontend.Variable) error {
+		api.AssertIsEqual(api.Mul(unnamed, unnamed), commitment)
+		return nil
+	})
+	return nil
+}
+
+func TestNamedCommitments(t *testing.T) {
+	circuit := namedCommitmentCircuit{}
+	assignment := namedCommitmentCircuit{X: 10, Y: 20}
+	assert := test.NewAssert(t)
+	assert.ProverSucceeded(&circuit, &assignment, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+}
+
+type contributionOnlyCircuit struct {
+	X frontend.Variable
+}
+
+func (c *contributionOnlyCircuit) Define(api frontend.API) error {
+	Contribute(api, c.X)
+	return nil
+}
+
+func TestContributionOnly(t *testing.T) {
+	circuit := contributionOnlyCircuit{}
+	assert := test.NewAssert(t)
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+	assert.NoError(err)
+}
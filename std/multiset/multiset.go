@@ -0,0 +1,260 @@
+// Package multiset implements permutation and sortedness checks.
+//
+// A list b is a permutation of a list a if they are equal as multisets, which
+// is checked with the grand product argument of [Lipton89]: for a challenge x
+// derived from the commitment to the lists,
+//
+//	∏ᵢ (x-aᵢ) == ∏ᵢ (x-bᵢ).
+//
+// A wrong permutation of n elements is accepted with probability at most
+// n/|F|. The lists of tuples are compressed with random linear combinations.
+// The builder must implement [frontend.Committer].
+//
+// A list is sorted if its consecutive differences are range checked, which uses
+// a lookup argument when the builder supports commitments (see package
+// [github.com/consensys/gnark/std/rangecheck]). Together with a permutation
+// check, this allows to sort lists in the circuit, as needed by memory checking
+// or aggregation circuits.
+//
+// [Lipton89]: https://doi.org/10.1016/0020-0190(89)90207-5
+package multiset
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{sortHint}
+}
+
+// Option allows to configure the sortedness checks.
+type Option func(*config) error
+
+type config struct {
+	strict bool
+	rcOpts []rangecheck.Option
+}
+
+// WithStrictOrder asserts that the lists are strictly increasing, that is
+// sorted without duplicates.
+func WithStrictOrder() Option {
+	return func(cfg *config) error {
+		cfg.strict = true
+		return nil
+	}
+}
+
+// WithRangecheckOptions sets the options of the range checker used for the
+// sortedness checks, for example to force the lookup argument or the binary
+// decomposition with [rangecheck.WithStrategy].
+func WithRangecheckOptions(opts ...rangecheck.Option) Option {
+	return func(cfg *config) error {
+		cfg.rcOpts = opts
+		return nil
+	}
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			panic(fmt.Sprintf("apply option: %v", err))
+		}
+	}
+	return cfg
+}
+
+// AssertIsPermutation asserts that b is a permutation of a.
+func AssertIsPermutation(api frontend.API, a, b []frontend.Variable) {
+	AssertIsPermutationRows(api, asRows(a), asRows(b))
+}
+
+// AssertIsPermutationRows asserts that the rows of b are a permutation of the
+// rows of a. All the rows must have the same length.
+func AssertIsPermutationRows(api frontend.API, a, b [][]frontend.Variable) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("lists of different lengths %d and %d", len(a), len(b)))
+	}
+	if len(a) == 0 {
+		return
+	}
+	width := len(a[0])
+	var toCommit []frontend.Variable
+	for _, rows := range [][][]frontend.Variable{a, b} {
+		for i := range rows {
+			if len(rows[i]) != width {
+				panic("rows of different lengths")
+			}
+			for _, v := range rows[i] {
+				if _, isConst := api.Compiler().ConstantValue(v); !isConst {
+					toCommit = append(toCommit, v)
+				}
+			}
+		}
+	}
+	if len(toCommit) == 0 {
+		// the lists are constants, compare them at compile time
+		if !equalMultisets(api, a, b) {
+			panic("constant lists are not permutations")
+		}
+		return
+	}
+	// the lists are copied as the check is deferred. The commitment is only
+	// requested when compiling, so that the range checkers created later in
+	// the circuit can still register theirs.
+	a, b = clone(a), clone(b)
+	api.Compiler().Defer(func(api frontend.API) error {
+		multicommit.WithCommitment(api, func(api frontend.API, x frontend.Variable) error {
+			coeffs, err := randomCoefficients(api, x, width)
+			if err != nil {
+				return err
+			}
+			api.AssertIsEqual(grandProduct(api, x, coeffs, a), grandProduct(api, x, coeffs, b))
+			return nil
+		}, toCommit...)
+		return nil
+	})
+}
+
+// AssertIsSorted asserts that a is sorted in increasing order. The elements
+// must be less than 2^nbBits.
+//
+// The elements are the sums of the first element and of the differences, which
+// are less than 2^(nbBits+bits.Len(len(a))) and must not wrap around the
+// modulus: the function panics unless nbBits+bits.Len(len(a)) is less than the
+// bit length of the field.
+func AssertIsSorted(api frontend.API, a []frontend.Variable, nbBits int, opts ...Option) {
+	if len(a) == 0 {
+		return
+	}
+	if fieldBitLen := api.Compiler().FieldBitLen(); nbBits+bits.Len(uint(len(a))) >= fieldBitLen {
+		panic(fmt.Sprintf("%d elements of %d bits may wrap around the modulus of %d bits", len(a), nbBits, fieldBitLen))
+	}
+	cfg := newConfig(opts)
+	rc := rangecheck.New(api, cfg.rcOpts...)
+	// the first element and the differences are bounded, so that the sums
+	// don't wrap around the modulus
+	rc.Check(a[0], nbBits)
+	for i := 1; i < len(a); i++ {
+		d := api.Sub(a[i], a[i-1])
+		if cfg.strict {
+			d = api.Sub(d, 1)
+		}
+		rc.Check(d, nbBits)
+	}
+}
+
+// Sort returns the elements of a sorted in increasing order. The elements must
+// be less than 2^nbBits.
+func Sort(api frontend.API, a []frontend.Variable, nbBits int, opts ...Option) []frontend.Variable {
+	if len(a) <= 1 {
+		return a
+	}
+	sorted, err := api.Compiler().NewHint(sortHint, len(a), a...)
+	if err != nil {
+		panic(fmt.Sprintf("new hint: %v", err))
+	}
+	AssertIsPermutation(api, a, sorted)
+	AssertIsSorted(api, sorted, nbBits, opts...)
+	return sorted
+}
+
+func equalMultisets(api frontend.API, a, b [][]frontend.Variable) bool {
+	count := make(map[string]int)
+	key := func(row []frontend.Variable) string {
+		var k string
+		for _, v := range row {
+			c, _ := api.Compiler().ConstantValue(v)
+			k += new(big.Int).Mod(c, api.Compiler().Field()).Text(16) + ","
+		}
+		return k
+	}
+	for i := range a {
+		count[key(a[i])]++
+		count[key(b[i])]--
+	}
+	for _, c := range count {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func asRows(a []frontend.Variable) [][]frontend.Variable {
+	res := make([][]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = []frontend.Variable{a[i]}
+	}
+	return res
+}
+
+func clone(a [][]frontend.Variable) [][]frontend.Variable {
+	res := make([][]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = append([]frontend.Variable(nil), a[i]...)
+	}
+	return res
+}
+
+// randomCoefficients returns the coefficients of the random linear combination
+// of the columns, the first one being 1.
+func randomCoefficients(api frontend.API, commitment frontend.Variable, width int) ([]frontend.Variable, error) {
+	coeffs := make([]frontend.Variable, width)
+	coeffs[0] = 1
+	if width == 1 {
+		return coeffs, nil
+	}
+	hasher, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, fmt.Errorf("new hasher: %w", err)
+	}
+	for i := 1; i < width; i++ {
+		hasher.Reset()
+		hasher.Write(i, commitment)
+		coeffs[i] = hasher.Sum()
+	}
+	return coeffs, nil
+}
+
+// grandProduct returns ∏ (x - ∑ⱼ cⱼ·rⱼ) over the rows r.
+func grandProduct(api frontend.API, x frontend.Variable, coeffs []frontend.Variable, rows [][]frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 1
+	for _, r := range rows {
+		t := api.Sub(x, r[0])
+		for j := 1; j < len(r); j++ {
+			t = api.Sub(t, api.Mul(coeffs[j], r[j]))
+		}
+		res = api.Mul(res, t)
+	}
+	return res
+}
+
+// sortHint sorts the inputs in increasing order.
+func sortHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != len(outputs) {
+		return fmt.Errorf("expected %d outputs, got %d", len(inputs), len(outputs))
+	}
+	sorted := make([]*big.Int, len(inputs))
+	copy(sorted, inputs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	for i := range sorted {
+		outputs[i].Set(sorted[i])
+	}
+	return nil
+}
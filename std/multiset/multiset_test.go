@@ -0,0 +1,103 @@
+package multiset
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type permutationCircuit struct {
+	A, B []frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	AssertIsPermutation(api, c.A, c.B)
+	return nil
+}
+
+func TestAssertIsPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&permutationCircuit{A: make([]frontend.Variable, 5), B: make([]frontend.Variable, 5)},
+		test.WithValidAssignment(&permutationCircuit{A: []frontend.Variable{1, 2, 3, 3, 5}, B: []frontend.Variable{3, 5, 1, 3, 2}}),
+		test.WithInvalidAssignment(&permutationCircuit{A: []frontend.Variable{1, 2, 3, 3, 5}, B: []frontend.Variable{3, 5, 1, 2, 2}}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type permutationRowsCircuit struct {
+	A, B [][2]frontend.Variable
+}
+
+func (c *permutationRowsCircuit) Define(api frontend.API) error {
+	a, b := make([][]frontend.Variable, len(c.A)), make([][]frontend.Variable, len(c.B))
+	for i := range c.A {
+		a[i], b[i] = c.A[i][:], c.B[i][:]
+	}
+	AssertIsPermutationRows(api, a, b)
+	return nil
+}
+
+func TestAssertIsPermutationRows(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := [][2]frontend.Variable{{1, 10}, {2, 20}, {3, 30}}
+	assert.CheckCircuit(&permutationRowsCircuit{A: make([][2]frontend.Variable, 3), B: make([][2]frontend.Variable, 3)},
+		test.WithValidAssignment(&permutationRowsCircuit{A: a, B: [][2]frontend.Variable{{3, 30}, {1, 10}, {2, 20}}}),
+		// same columns as multisets, but the rows are mixed
+		test.WithInvalidAssignment(&permutationRowsCircuit{A: a, B: [][2]frontend.Variable{{3, 10}, {1, 30}, {2, 20}}}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type sortCircuit struct {
+	A, Sorted []frontend.Variable
+	strict    bool
+}
+
+func (c *sortCircuit) Define(api frontend.API) error {
+	var opts []Option
+	if c.strict {
+		opts = append(opts, WithStrictOrder())
+	}
+	sorted := Sort(api, c.A, 16, opts...)
+	for i := range sorted {
+		api.AssertIsEqual(sorted[i], c.Sorted[i])
+	}
+	AssertIsSorted(api, c.Sorted, 16, opts...)
+	return nil
+}
+
+func TestSort(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&sortCircuit{A: make([]frontend.Variable, 5), Sorted: make([]frontend.Variable, 5)},
+		test.WithValidAssignment(&sortCircuit{A: []frontend.Variable{40000, 2, 7, 2, 0}, Sorted: []frontend.Variable{0, 2, 2, 7, 40000}}),
+		test.WithInvalidAssignment(&sortCircuit{A: []frontend.Variable{40000, 2, 7, 2, 0}, Sorted: []frontend.Variable{0, 2, 7, 2, 40000}}),
+		// out of range
+		test.WithInvalidAssignment(&sortCircuit{A: []frontend.Variable{70000, 2, 7, 2, 0}, Sorted: []frontend.Variable{0, 2, 2, 7, 70000}}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+	assert.CheckCircuit(&sortCircuit{A: make([]frontend.Variable, 3), Sorted: make([]frontend.Variable, 3), strict: true},
+		test.WithValidAssignment(&sortCircuit{A: []frontend.Variable{3, 1, 2}, Sorted: []frontend.Variable{1, 2, 3}}),
+		test.WithInvalidAssignment(&sortCircuit{A: []frontend.Variable{3, 1, 1}, Sorted: []frontend.Variable{1, 1, 3}}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type sortedCircuit struct {
+	A      []frontend.Variable
+	nbBits int
+}
+
+func (c *sortedCircuit) Define(api frontend.API) error {
+	AssertIsSorted(api, c.A, c.nbBits)
+	return nil
+}
+
+func TestAssertIsSortedBound(t *testing.T) {
+	assert := require.New(t)
+	// 4 elements of 250 bits fit in the 254 bits of the BN254 scalar field, 8
+	// elements may wrap around the modulus
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &sortedCircuit{A: make([]frontend.Variable, 4), nbBits: 250})
+	assert.NoError(err)
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &sortedCircuit{A: make([]frontend.Variable, 8), nbBits: 250})
+	assert.ErrorContains(err, "may wrap around the modulus")
+}
@@ -0,0 +1,214 @@
+package polynomial
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Domain is a multiplicative subgroup of the native field of power of two
+// cardinality n, possibly shifted: {g·ωⁱ, 0 ≤ i < n}. The values of the
+// polynomials on the domain are given in this order, or in bit-reversed order
+// with [WithBitReversedOrder].
+//
+// The constants of the domain are computed once, outside of the circuit, so
+// that a Domain may be shared by the gadgets evaluating polynomials on it. For
+// the scalar fields of the curves supported by gnark, ω is the same as in the
+// fft package of gnark-crypto.
+type Domain struct {
+	Cardinality uint64
+	Generator   *big.Int // ω, primitive n-th root of unity
+	Shift       *big.Int // g, 1 if the domain is not shifted
+
+	field       *big.Int
+	bitReversed bool
+	elements    []*big.Int // domain elements, in the order of the values
+	weights     []*big.Int // barycentric weights, xᵢ/(n·gⁿ)
+	shiftN      *big.Int   // gⁿ
+}
+
+// DomainOption defines option for altering the definition of a [Domain].
+type DomainOption func(*Domain) error
+
+// WithShift shifts the domain by g, which must not be in the subgroup of
+// cardinality n.
+func WithShift(g *big.Int) DomainOption {
+	return func(d *Domain) error {
+		d.Shift = new(big.Int).Set(g)
+		return nil
+	}
+}
+
+// WithBitReversedOrder orders the values of the polynomials by the bit
+// reversal of the exponent of ω, as in the blobs of EIP-4844.
+func WithBitReversedOrder() DomainOption {
+	return func(d *Domain) error {
+		d.bitReversed = true
+		return nil
+	}
+}
+
+// NewDomain returns the domain of the given cardinality in the field, which
+// must be a power of two.
+func NewDomain(field *big.Int, cardinality uint64, opts ...DomainOption) (*Domain, error) {
+	if cardinality == 0 || cardinality&(cardinality-1) != 0 {
+		return nil, fmt.Errorf("cardinality %d is not a power of two", cardinality)
+	}
+	d := &Domain{
+		Cardinality: cardinality,
+		Shift:       big.NewInt(1),
+		field:       new(big.Int).Set(field),
+	}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if d.Generator, err = rootOfUnity(field, cardinality); err != nil {
+		return nil, err
+	}
+	d.Shift.Mod(d.Shift, field)
+	d.shiftN = new(big.Int).Exp(d.Shift, new(big.Int).SetUint64(cardinality), field)
+	if d.Shift.Sign() == 0 || (d.Shift.Cmp(big.NewInt(1)) != 0 && d.shiftN.Cmp(big.NewInt(1)) == 0) {
+		return nil, errors.New("shift is in the subgroup")
+	}
+
+	// xᵢ = g·ωⁱ and the weights of the barycentric formula
+	//  f(z) = (zⁿ-gⁿ)·Σᵢ f(xᵢ)·wᵢ/(z-xᵢ), wᵢ = xᵢ/(n·gⁿ)
+	d.elements = make([]*big.Int, cardinality)
+	d.weights = make([]*big.Int, cardinality)
+	c := new(big.Int).SetUint64(cardinality)
+	c.Mul(c, d.shiftN).ModInverse(c, field)
+	x := new(big.Int).Set(d.Shift)
+	logN := bits.TrailingZeros64(cardinality)
+	for i := uint64(0); i < cardinality; i++ {
+		j := i
+		if d.bitReversed {
+			j = bits.Reverse64(i) >> (64 - logN)
+		}
+		d.elements[j] = new(big.Int).Set(x)
+		d.weights[j] = new(big.Int).Mul(x, c)
+		d.weights[j].Mod(d.weights[j], field)
+		x.Mul(x, d.Generator).Mod(x, field)
+	}
+	return d, nil
+}
+
+// Elements returns the elements of the domain, in the order of the values.
+func (d *Domain) Elements() []*big.Int {
+	return d.elements
+}
+
+// VanishingAt returns zⁿ-gⁿ, the vanishing polynomial of the domain evaluated
+// at z.
+func (d *Domain) VanishingAt(api frontend.API, z frontend.Variable) frontend.Variable {
+	zn := z
+	for i := uint64(1); i < d.Cardinality; i <<= 1 {
+		zn = api.Mul(zn, zn)
+	}
+	return api.Sub(zn, d.shiftN)
+}
+
+// LagrangeAt returns the evaluations at z of the Lagrange polynomials of the
+// domain, in the order of the values. z must not be an element of the domain,
+// which holds with overwhelming probability for a random challenge.
+func (d *Domain) LagrangeAt(api frontend.API, z frontend.Variable) []frontend.Variable {
+	d.checkField(api)
+	vanishing := d.VanishingAt(api, z)
+	res := make([]frontend.Variable, d.Cardinality)
+	for i := range res {
+		res[i] = api.Div(api.Mul(vanishing, d.weights[i]), api.Sub(z, d.elements[i]))
+	}
+	return res
+}
+
+// Interpolate returns f(z), where f is the polynomial of degree less than n
+// such that f(xᵢ) = values[i] for the elements xᵢ of the domain. z must not be
+// an element of the domain, which holds with overwhelming probability for a
+// random challenge.
+func (d *Domain) Interpolate(api frontend.API, z frontend.Variable, values []frontend.Variable) frontend.Variable {
+	if uint64(len(values)) != d.Cardinality {
+		panic(fmt.Sprintf("got %d values, expected %d", len(values), d.Cardinality))
+	}
+	d.checkField(api)
+	res := frontend.Variable(0)
+	for i := range values {
+		res = api.Add(res, api.Div(api.Mul(values[i], d.weights[i]), api.Sub(z, d.elements[i])))
+	}
+	return api.Mul(res, d.VanishingAt(api, z))
+}
+
+func (d *Domain) checkField(api frontend.API) {
+	if api.Compiler().Field().Cmp(d.field) != 0 {
+		panic("domain defined over a different field")
+	}
+}
+
+// rootOfUnity returns a primitive n-th root of unity of the field.
+func rootOfUnity(field *big.Int, n uint64) (*big.Int, error) {
+	var (
+		res = new(big.Int)
+		err error
+	)
+	switch utils.FieldToCurve(field) {
+	case ecc.BN254:
+		var e bn254.Element
+		e, err = bn254.Generator(n)
+		e.BigInt(res)
+	case ecc.BLS12_377:
+		var e bls12377.Element
+		e, err = bls12377.Generator(n)
+		e.BigInt(res)
+	case ecc.BLS12_381:
+		var e bls12381.Element
+		e, err = bls12381.Generator(n)
+		e.BigInt(res)
+	case ecc.BLS24_315:
+		var e bls24315.Element
+		e, err = bls24315.Generator(n)
+		e.BigInt(res)
+	case ecc.BLS24_317:
+		var e bls24317.Element
+		e, err = bls24317.Generator(n)
+		e.BigInt(res)
+	case ecc.BW6_633:
+		var e bw6633.Element
+		e, err = bw6633.Generator(n)
+		e.BigInt(res)
+	case ecc.BW6_761:
+		var e bw6761.Element
+		e, err = bw6761.Generator(n)
+		e.BigInt(res)
+	default:
+		return genericRootOfUnity(field, n)
+	}
+	return res, err
+}
+
+// genericRootOfUnity returns a primitive n-th root of unity of a prime field
+// by raising its smallest quadratic non-residue to (p-1)/n.
+func genericRootOfUnity(field *big.Int, n uint64) (*big.Int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	if uint(bits.TrailingZeros64(n)) > pMinusOne.TrailingZeroBits() {
+		return nil, fmt.Errorf("no root of unity of order %d in the field", n)
+	}
+	g := big.NewInt(2)
+	for big.Jacobi(g, field) != -1 {
+		g.Add(g, big.NewInt(1))
+	}
+	e := new(big.Int).Div(pMinusOne, new(big.Int).SetUint64(n))
+	return g.Exp(g, e, field), nil
+}
@@ -0,0 +1,92 @@
+package polynomial
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type interpolateDomainCircuit struct {
+	Values   []frontend.Variable
+	Z        frontend.Variable
+	Expected frontend.Variable
+
+	domain *Domain
+}
+
+func (c *interpolateDomainCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.domain.Interpolate(api, c.Z, c.Values), c.Expected)
+	lagrange := c.domain.LagrangeAt(api, c.Z)
+	res := frontend.Variable(0)
+	for i := range lagrange {
+		res = api.Add(res, api.Mul(lagrange[i], c.Values[i]))
+	}
+	api.AssertIsEqual(res, c.Expected)
+	return nil
+}
+
+func evalBN254(coeffs []fr.Element, x *big.Int) fr.Element {
+	var xe, res fr.Element
+	xe.SetBigInt(x)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(&res, &xe).Add(&res, &coeffs[i])
+	}
+	return res
+}
+
+func TestDomainInterpolate(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 16
+	coeffs := make([]fr.Element, n)
+	for i := range coeffs {
+		coeffs[i].SetRandom()
+	}
+	var z fr.Element
+	z.SetRandom()
+	expected := evalBN254(coeffs, z.BigInt(new(big.Int)))
+
+	for name, opts := range map[string][]DomainOption{
+		"natural":     nil,
+		"shifted":     {WithShift(big.NewInt(5))},
+		"bitreversed": {WithBitReversedOrder()},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			domain, err := NewDomain(ecc.BN254.ScalarField(), n, opts...)
+			assert.NoError(err)
+			values := make([]frontend.Variable, n)
+			for i, x := range domain.Elements() {
+				values[i] = evalBN254(coeffs, x)
+			}
+			assert.CheckCircuit(&interpolateDomainCircuit{Values: make([]frontend.Variable, n), domain: domain},
+				test.WithValidAssignment(&interpolateDomainCircuit{Values: values, Z: z, Expected: expected}),
+				test.WithInvalidAssignment(&interpolateDomainCircuit{Values: values, Z: z, Expected: 1}),
+				test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+		}, name)
+	}
+}
+
+func TestDomainGenerator(t *testing.T) {
+	assert := test.NewAssert(t)
+	domain, err := NewDomain(ecc.BN254.ScalarField(), 1<<10)
+	assert.NoError(err)
+	expected := fft.NewDomain(1 << 10).Generator
+	assert.Equal(expected.BigInt(new(big.Int)), domain.Generator)
+
+	// the root of unity of a field without curve is computed generically
+	domain, err = NewDomain(big.NewInt(97), 8)
+	assert.NoError(err)
+	assert.Equal(big.NewInt(1), new(big.Int).Exp(domain.Generator, big.NewInt(8), big.NewInt(97)))
+	assert.NotEqual(big.NewInt(1), new(big.Int).Exp(domain.Generator, big.NewInt(4), big.NewInt(97)))
+
+	_, err = NewDomain(ecc.BN254.ScalarField(), 12)
+	assert.Error(err)
+	omega, err := fr.Generator(8)
+	assert.NoError(err)
+	_, err = NewDomain(ecc.BN254.ScalarField(), 8, WithShift(omega.BigInt(new(big.Int))))
+	assert.Error(err)
+}
@@ -0,0 +1,117 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// FFT returns the values on the domain of the polynomial of degree less than n
+// with the given coefficients, in the order of the values. The twiddle factors
+// are constants, so that the butterflies only cost additions: the gadget is
+// meant for moderate cardinalities.
+func (d *Domain) FFT(api frontend.API, coeffs []frontend.Variable) []frontend.Variable {
+	if uint64(len(coeffs)) > d.Cardinality {
+		panic(fmt.Sprintf("got %d coefficients, expected at most %d", len(coeffs), d.Cardinality))
+	}
+	d.checkField(api)
+	a := make([]frontend.Variable, d.Cardinality)
+	gk := big.NewInt(1)
+	for i := range a {
+		if i >= len(coeffs) {
+			a[i] = 0
+			continue
+		}
+		// f(g·x) = Σ cₖ·gᵏ·xᵏ
+		a[i] = api.Mul(coeffs[i], gk)
+		gk = new(big.Int).Mul(gk, d.Shift)
+		gk.Mod(gk, d.field)
+	}
+	d.fft(api, a, d.Generator)
+	return d.fromNatural(a)
+}
+
+// IFFT returns the coefficients of the polynomial of degree less than n with
+// the given values on the domain, in the order of the values.
+func (d *Domain) IFFT(api frontend.API, values []frontend.Variable) []frontend.Variable {
+	if uint64(len(values)) != d.Cardinality {
+		panic(fmt.Sprintf("got %d values, expected %d", len(values), d.Cardinality))
+	}
+	d.checkField(api)
+	a := d.toNatural(values)
+	d.fft(api, a, new(big.Int).ModInverse(d.Generator, d.field))
+	// cₖ = g⁻ᵏ/n·Σᵢ f(g·ωⁱ)·ω⁻ⁱᵏ
+	c := new(big.Int).SetUint64(d.Cardinality)
+	c.ModInverse(c, d.field)
+	gInv := new(big.Int).ModInverse(d.Shift, d.field)
+	for i := range a {
+		a[i] = api.Mul(a[i], c)
+		c = new(big.Int).Mul(c, gInv)
+		c.Mod(c, d.field)
+	}
+	return a
+}
+
+// fft computes in place the values of the polynomial with coefficients a on
+// the subgroup generated by omega, in natural order, with the iterative radix-2
+// Cooley-Tukey algorithm.
+func (d *Domain) fft(api frontend.API, a []frontend.Variable, omega *big.Int) {
+	n := len(a)
+	logN := bits.TrailingZeros(uint(n))
+	for i := range a {
+		if j := int(bits.Reverse64(uint64(i)) >> (64 - logN)); i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	for m := 2; m <= n; m <<= 1 {
+		// ωₘ = ω^(n/m) is a primitive m-th root of unity
+		wm := new(big.Int).Exp(omega, big.NewInt(int64(n/m)), d.field)
+		twiddles := make([]*big.Int, m/2)
+		twiddles[0] = big.NewInt(1)
+		for j := 1; j < m/2; j++ {
+			twiddles[j] = new(big.Int).Mul(twiddles[j-1], wm)
+			twiddles[j].Mod(twiddles[j], d.field)
+		}
+		for k := 0; k < n; k += m {
+			for j := 0; j < m/2; j++ {
+				t := a[k+j+m/2]
+				if j != 0 {
+					t = api.Mul(t, twiddles[j])
+				}
+				a[k+j+m/2] = api.Sub(a[k+j], t)
+				a[k+j] = api.Add(a[k+j], t)
+			}
+		}
+	}
+}
+
+// fromNatural reorders values given in the natural order of the exponents of
+// ω in the order of the domain.
+func (d *Domain) fromNatural(a []frontend.Variable) []frontend.Variable {
+	if !d.bitReversed {
+		return a
+	}
+	logN := bits.TrailingZeros64(d.Cardinality)
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		res[bits.Reverse64(uint64(i))>>(64-logN)] = a[i]
+	}
+	return res
+}
+
+// toNatural returns a copy of the values given in the order of the domain,
+// reordered in the natural order of the exponents of ω.
+func (d *Domain) toNatural(values []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(values))
+	if !d.bitReversed {
+		copy(res, values)
+		return res
+	}
+	logN := bits.TrailingZeros64(d.Cardinality)
+	for i := range values {
+		res[i] = values[bits.Reverse64(uint64(i))>>(64-logN)]
+	}
+	return res
+}
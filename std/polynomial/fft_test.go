@@ -0,0 +1,68 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type fftCircuit struct {
+	Coeffs []frontend.Variable
+	Values []frontend.Variable
+
+	domain *Domain
+}
+
+func (c *fftCircuit) Define(api frontend.API) error {
+	values := c.domain.FFT(api, c.Coeffs)
+	for i := range values {
+		api.AssertIsEqual(values[i], c.Values[i])
+	}
+	coeffs := c.domain.IFFT(api, c.Values)
+	for i := range coeffs {
+		if i < len(c.Coeffs) {
+			api.AssertIsEqual(coeffs[i], c.Coeffs[i])
+		} else {
+			api.AssertIsEqual(coeffs[i], 0)
+		}
+	}
+	return nil
+}
+
+func TestFFT(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 16
+	for name, opts := range map[string][]DomainOption{
+		"natural":     nil,
+		"shifted":     {WithShift(big.NewInt(5))},
+		"bitreversed": {WithBitReversedOrder()},
+	} {
+		for _, nbCoeffs := range []int{n, n / 2} {
+			assert.Run(func(assert *test.Assert) {
+				domain, err := NewDomain(ecc.BN254.ScalarField(), n, opts...)
+				assert.NoError(err)
+				coeffs := make([]fr.Element, nbCoeffs)
+				assignment := &fftCircuit{Coeffs: make([]frontend.Variable, nbCoeffs), Values: make([]frontend.Variable, n)}
+				for i := range coeffs {
+					coeffs[i].SetRandom()
+					assignment.Coeffs[i] = coeffs[i]
+				}
+				for i, x := range domain.Elements() {
+					assignment.Values[i] = evalBN254(coeffs, x)
+				}
+				invalid := &fftCircuit{Coeffs: assignment.Coeffs, Values: make([]frontend.Variable, n)}
+				copy(invalid.Values, assignment.Values)
+				invalid.Values[1] = 0
+				assert.CheckCircuit(&fftCircuit{Coeffs: make([]frontend.Variable, nbCoeffs), Values: make([]frontend.Variable, n), domain: domain},
+					test.WithValidAssignment(assignment),
+					test.WithInvalidAssignment(invalid),
+					test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+			}, name, fmt.Sprintf("coeffs=%d", nbCoeffs))
+		}
+	}
+}
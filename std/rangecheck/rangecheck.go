@@ -1,15 +1,33 @@
 // Package rangecheck implements range checking gadget
 //
-// This package chooses the most optimal path for performing range checks:
+// The range checks are performed with one of the following strategies, see
+// [Strategy]:
 //   - if the backend supports native range checking and the frontend exports the variables in the proprietary format by implementing [frontend.Rangechecker], then use it directly;
 //   - if the backend supports creating a commitment of variables by implementing [frontend.Committer], then we use the log-derivative variant [[Haböck22]] of the product argument as in [[BCG+18]] . [r1cs.NewBuilder] returns a builder which implements this interface;
 //   - lacking these, we perform binary decomposition of variable into bits.
 //
+// By default ([StrategyAuto]) the first available strategy in this order is
+// used. As the commitment-based checks share a lookup table, they only pay off
+// for enough checked variables: when all the checks of a circuit use the
+// default strategy, the cost of the lookup table is compared to the cost of the
+// binary decomposition when the circuit is compiled and the cheapest is used.
+// The strategy can be fixed per checker with [WithStrategy], to get constraint
+// counts which don't depend on the rest of the circuit.
+//
+// The checks are deduplicated in the circuit: checking a variable which was
+// already checked to be at most as many bits, by any checker returned by [New]
+// and whatever the strategy, doesn't add constraints. The checks with the
+// commitment-based strategy are also deduplicated when the circuit is compiled,
+// so that a check against a smaller bound replaces the previous checks of the
+// variable.
+//
 // [BCG+18]: https://eprint.iacr.org/2018/380
 // [Haböck22]: https://eprint.iacr.org/2022/1530
 package rangecheck
 
 import (
+	"fmt"
+
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -20,15 +38,95 @@ import (
 // package anyway in test.
 var _ = r1cs.NewBuilder
 
-// New returns a new range checker depending on the frontend capabilities.
-func New(api frontend.API) frontend.Rangechecker {
-	if rc, ok := api.(frontend.Rangechecker); ok {
-		return rc
+// Strategy is the method used to range check the variables.
+type Strategy int
+
+const (
+	// StrategyAuto uses the native range checks of the builder if available,
+	// and otherwise the cheapest of the commitment-based checks and binary
+	// decomposition.
+	StrategyAuto Strategy = iota
+	// StrategyNative uses the range checks of the builder, which must
+	// implement [frontend.Rangechecker].
+	StrategyNative
+	// StrategyCommit uses a log-derivative argument over a lookup table of
+	// small values, the builder must implement [frontend.Committer].
+	StrategyCommit
+	// StrategyBinary decomposes the variables into bits.
+	StrategyBinary
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategyAuto:
+		return "auto"
+	case StrategyNative:
+		return "native"
+	case StrategyCommit:
+		return "commit"
+	case StrategyBinary:
+		return "binary"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
 	}
-	if _, ok := api.(frontend.Committer); ok {
-		return newCommitRangechecker(api)
+}
+
+// Option allows to configure the range checker returned by [New].
+type Option func(*config) error
+
+type config struct {
+	strategy Strategy
+}
+
+// WithStrategy sets the strategy used by the range checker. It panics in [New]
+// if the builder doesn't support the strategy.
+func WithStrategy(s Strategy) Option {
+	return func(cfg *config) error {
+		if s < StrategyAuto || s > StrategyBinary {
+			return fmt.Errorf("unknown strategy %s", s)
+		}
+		cfg.strategy = s
+		return nil
+	}
+}
+
+// New returns a new range checker depending on the frontend capabilities and
+// the options.
+func New(api frontend.API, opts ...Option) frontend.Rangechecker {
+	var cfg config
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			panic(fmt.Sprintf("apply option: %v", err))
+		}
+	}
+	return newDedupChecker(api, newChecker(api, cfg.strategy))
+}
+
+func newChecker(api frontend.API, strategy Strategy) frontend.Rangechecker {
+	rc, isNative := api.(frontend.Rangechecker)
+	_, isCommitter := api.(frontend.Committer)
+	switch strategy {
+	case StrategyAuto:
+		if isNative {
+			return rc
+		}
+		if isCommitter {
+			return newCommitRangechecker(api)
+		}
+		return plainChecker{api: api}
+	case StrategyNative:
+		if !isNative {
+			panic("builder doesn't implement native range checks")
+		}
+		return rc
+	case StrategyCommit:
+		if !isCommitter {
+			panic("builder doesn't implement commitments")
+		}
+		return forcedCommitChecker{newCommitRangechecker(api)}
+	default:
+		return plainChecker{api: api}
 	}
-	return plainChecker{api: api}
 }
 
 // GetHints returns all hints used in this package
@@ -15,7 +15,7 @@ import (
 type ctxCheckerKey struct{}
 
 func init() {
-	solver.RegisterHint(DecomposeHint)
+	solver.RegisterVersionedHint("gnark/std/rangecheck/DecomposeHint", 1, DecomposeHint)
 }
 
 type checkedVariable struct {
@@ -26,6 +26,20 @@ type checkedVariable struct {
 type commitChecker struct {
 	collected []checkedVariable
 	closed    bool
+	// forced is set when a check explicitly requested the commitment-based
+	// strategy, in which case we don't fall back to binary decomposition.
+	forced bool
+}
+
+// forcedCommitChecker is the view of the shared commitChecker for the checks
+// with [StrategyCommit].
+type forcedCommitChecker struct {
+	*commitChecker
+}
+
+func (c forcedCommitChecker) Check(in frontend.Variable, bits int) {
+	c.commitChecker.Check(in, bits)
+	c.forced = true
 }
 
 func newCommitRangechecker(api frontend.API) *commitChecker {
@@ -70,7 +84,18 @@ func (c *commitChecker) commit(api frontend.API) error {
 	if len(c.collected) == 0 {
 		return nil
 	}
-	baseLength := c.getOptimalBasewidth(api)
+	// a check is collected when the variable wasn't checked against a smaller
+	// bound before, but it may be checked against a smaller bound after.
+	c.collected = deduplicate(api, c.collected)
+	baseLength, cost := c.getOptimalBasewidth(api)
+	if !c.forced && c.binaryCost(api) <= cost {
+		// the lookup table doesn't pay off for few checked variables
+		pl := plainChecker{api: api}
+		for i := range c.collected {
+			pl.Check(c.collected[i].v, c.collected[i].bits)
+		}
+		return nil
+	}
 	// decompose into smaller limbs
 	decomposed := make([]frontend.Variable, 0, len(c.collected))
 	collected := make([]frontend.Variable, len(c.collected))
@@ -141,7 +166,7 @@ func DecomposeHint(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	return nil
 }
 
-func (c *commitChecker) getOptimalBasewidth(api frontend.API) int {
+func (c *commitChecker) getOptimalBasewidth(api frontend.API) (width, cost int) {
 	if ft, ok := api.(frontendtype.FrontendTyper); ok {
 		switch ft.FrontendType() {
 		case frontendtype.R1CS:
@@ -153,7 +178,7 @@ func (c *commitChecker) getOptimalBasewidth(api frontend.API) int {
 	return optimalWidth(nbR1CSConstraints, c.collected)
 }
 
-func optimalWidth(countFn func(baseLength int, collected []checkedVariable) int, collected []checkedVariable) int {
+func optimalWidth(countFn func(baseLength int, collected []checkedVariable) int, collected []checkedVariable) (width, cost int) {
 	min := math.MaxInt64
 	minVal := 0
 	for j := 2; j < 18; j++ {
@@ -163,7 +188,22 @@ func optimalWidth(countFn func(baseLength int, collected []checkedVariable) int,
 			minVal = j
 		}
 	}
-	return minVal
+	return minVal, min
+}
+
+// binaryCost returns the number of constraints for checking the collected
+// variables with binary decomposition.
+func (c *commitChecker) binaryCost(api frontend.API) int {
+	nbBits := 0
+	for i := range c.collected {
+		nbBits += c.collected[i].bits
+	}
+	if ft, ok := api.(frontendtype.FrontendTyper); ok && ft.FrontendType() == frontendtype.SCS {
+		// boolean constraint and accumulation per bit
+		return 2 * nbBits
+	}
+	// boolean constraint per bit and recomposition per variable
+	return nbBits + len(c.collected)
 }
 
 func nbR1CSConstraints(baseLength int, collected []checkedVariable) int {
@@ -0,0 +1,78 @@
+package rangecheck
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+type ctxCheckedKey struct{}
+
+// checkedSet records the smallest bound each variable of the circuit was
+// checked against, indexed by the compressed canonical variable. It is shared
+// by all the range checkers of the circuit.
+type checkedSet map[string]int
+
+type dedupChecker struct {
+	api     frontend.API
+	checker frontend.Rangechecker
+	checked checkedSet
+}
+
+func newDedupChecker(api frontend.API, checker frontend.Rangechecker) frontend.Rangechecker {
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		// we can't share the checked variables between the checkers
+		return checker
+	}
+	ch := kv.GetKeyValue(ctxCheckedKey{})
+	if ch == nil {
+		ch = make(checkedSet)
+		kv.SetKeyValue(ctxCheckedKey{}, ch)
+	}
+	checked, ok := ch.(checkedSet)
+	if !ok {
+		panic("stored checked variables are not valid")
+	}
+	return &dedupChecker{api: api, checker: checker, checked: checked}
+}
+
+func (d *dedupChecker) Check(in frontend.Variable, bits int) {
+	key := variableKey(d.api, in)
+	if b, ok := d.checked[key]; ok && b <= bits {
+		return
+	}
+	d.checked[key] = bits
+	d.checker.Check(in, bits)
+}
+
+// variableKey returns the key of the variable in a checkedSet.
+func variableKey(api frontend.API, v frontend.Variable) string {
+	var calldata []uint32
+	api.Compiler().ToCanonicalVariable(v).Compress(&calldata)
+	buf := make([]byte, 0, 4*len(calldata))
+	for _, w := range calldata {
+		buf = binary.LittleEndian.AppendUint32(buf, w)
+	}
+	return string(buf)
+}
+
+// deduplicate returns the checks with a single check per variable, against
+// the smallest bound it was checked against: it is the only check which isn't
+// implied by another one. The checks are in the order of the first check of
+// each variable.
+func deduplicate(api frontend.API, checks []checkedVariable) []checkedVariable {
+	res := make([]checkedVariable, 0, len(checks))
+	idx := make(map[string]int, len(checks))
+	for _, c := range checks {
+		key := variableKey(api, c.v)
+		if i, ok := idx[key]; ok {
+			res[i].bits = min(res[i].bits, c.bits)
+			continue
+		}
+		idx[key] = len(res)
+		res = append(res, c)
+	}
+	return res
+}
@@ -6,9 +6,12 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
 )
 
 type CheckCircuit struct {
@@ -44,3 +47,113 @@ func TestCheck(t *testing.T) {
 	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit, frontend.WithCompressThreshold(100))
 	assert.NoError(err)
 }
+
+func TestDecomposeHintVersion(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &StrategyCircuit{strategy: StrategyCommit, nbChecks: 1})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&StrategyCircuit{X: 255, Y: 65535}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the constraint system records the version of the hint
+	deps := ccs.(*cs_bn254.R1CS).MHintsDependencies
+	id := solver.GetHintID(DecomposeHint)
+	assert.Equal("gnark/std/rangecheck/DecomposeHint@v1", deps[id])
+	_, err = ccs.Solve(w)
+	assert.NoError(err)
+
+	// a constraint system compiled with a previous version of the hint
+	deps[id] = "gnark/std/rangecheck/DecomposeHint@v0"
+	_, err = ccs.Solve(w)
+	assert.ErrorContains(err, "requires version 0, but the solver provides version 1")
+}
+
+type StrategyCircuit struct {
+	X, Y     frontend.Variable
+	strategy Strategy
+	nbChecks int
+}
+
+func (c *StrategyCircuit) Define(api frontend.API) error {
+	for i := 0; i < c.nbChecks; i++ {
+		// the checks from different checkers are deduplicated
+		r := New(api, WithStrategy(c.strategy))
+		r.Check(c.X, 8)
+		r.Check(c.Y, 16)
+		r.Check(c.X, 10)
+	}
+	return nil
+}
+
+func TestStrategy(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, strategy := range []Strategy{StrategyAuto, StrategyCommit, StrategyBinary} {
+		assert.Run(func(assert *test.Assert) {
+			assert.CheckCircuit(&StrategyCircuit{strategy: strategy, nbChecks: 1},
+				test.WithValidAssignment(&StrategyCircuit{X: 255, Y: 65535}),
+				test.WithInvalidAssignment(&StrategyCircuit{X: 256, Y: 1}),
+				test.WithInvalidAssignment(&StrategyCircuit{X: 1, Y: 65536}),
+				test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+
+			once, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &StrategyCircuit{strategy: strategy, nbChecks: 1})
+			assert.NoError(err)
+			twice, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &StrategyCircuit{strategy: strategy, nbChecks: 2})
+			assert.NoError(err)
+			assert.Equal(once.GetNbConstraints(), twice.GetNbConstraints())
+		}, strategy.String())
+	}
+
+	// the automatic strategy picks the cheapest of the two
+	counts := make(map[Strategy]int)
+	for _, strategy := range []Strategy{StrategyAuto, StrategyCommit, StrategyBinary} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &StrategyCircuit{strategy: strategy, nbChecks: 1})
+		assert.NoError(err)
+		counts[strategy] = ccs.GetNbConstraints()
+	}
+	assert.Equal(min(counts[StrategyBinary], counts[StrategyCommit]), counts[StrategyAuto])
+}
+
+func TestFallbackToBinary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the lookup table doesn't pay off for a single small variable
+	circuit := CheckCircuit{Vals: make([]frontend.Variable, 1), bits: 8}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+	assert.NoError(err)
+	assert.Equal(9, ccs.GetNbConstraints())
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&CheckCircuit{Vals: []frontend.Variable{255}}),
+		test.WithInvalidAssignment(&CheckCircuit{Vals: []frontend.Variable{256}}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type SubsumedCircuit struct {
+	X, Y   frontend.Variable
+	looser bool
+}
+
+func (c *SubsumedCircuit) Define(api frontend.API) error {
+	r := New(api, WithStrategy(StrategyCommit))
+	if c.looser {
+		// implied by the following checks
+		r.Check(c.X, 16)
+		r.Check(c.Y, 12)
+	}
+	r.Check(c.X, 8)
+	r.Check(c.Y, 10)
+	return nil
+}
+
+func TestSubsumedChecks(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&SubsumedCircuit{looser: true},
+		test.WithValidAssignment(&SubsumedCircuit{X: 255, Y: 1023}),
+		test.WithInvalidAssignment(&SubsumedCircuit{X: 256, Y: 1}),
+		test.WithInvalidAssignment(&SubsumedCircuit{X: 1, Y: 1024}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+
+	tight, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &SubsumedCircuit{})
+	assert.NoError(err)
+	both, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &SubsumedCircuit{looser: true})
+	assert.NoError(err)
+	assert.Equal(tight.GetNbConstraints(), both.GetNbConstraints())
+}
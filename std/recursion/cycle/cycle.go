@@ -0,0 +1,111 @@
+package cycle
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// ExportNative returns the limbs of the emulated representation of the native
+// variable v, where T describes the native field. The limbs are reduced modulo
+// the native field, so that [ImportEmulated] in the other circuit of the cycle
+// returns the same value. It returns an error if the modulus of T is not the
+// native modulus.
+func ExportNative[T emulated.FieldParams](api frontend.API, v frontend.Variable) ([]frontend.Variable, error) {
+	var fp T
+	if err := checkNative(api, fp); err != nil {
+		return nil, err
+	}
+	vBits := bits.ToBinary(api, v)
+	return group(api, fp, vBits), nil
+}
+
+// ImportNative returns the native variable with the emulated representation
+// limbs, as returned by [ExportEmulated] in the other circuit of the cycle.
+// It asserts that the limbs are the canonical representation of a native
+// value. It returns an error if the modulus of T is not the native modulus or
+// if the number of limbs doesn't match T.
+func ImportNative[T emulated.FieldParams](api frontend.API, limbs []frontend.Variable) (frontend.Variable, error) {
+	var fp T
+	if err := checkNative(api, fp); err != nil {
+		return nil, err
+	}
+	if len(limbs) != int(fp.NbLimbs()) {
+		return nil, fmt.Errorf("expected %d limbs, got %d", fp.NbLimbs(), len(limbs))
+	}
+	nbBits := api.Compiler().FieldBitLen()
+	limbBits := make([]frontend.Variable, 0, fp.NbLimbs()*fp.BitsPerLimb())
+	for i := range limbs {
+		limbBits = append(limbBits, bits.ToBinary(api, limbs[i], bits.WithNbDigits(int(fp.BitsPerLimb())))...)
+	}
+	for i := nbBits; i < len(limbBits); i++ {
+		api.AssertIsEqual(limbBits[i], 0)
+	}
+	// the value may wrap around the modulus, the canonical decomposition then
+	// differs from the bits of the limbs.
+	v := bits.FromBinary(api, limbBits[:nbBits], bits.WithUnconstrainedInputs())
+	vBits := bits.ToBinary(api, v)
+	for i := range vBits {
+		api.AssertIsEqual(vBits[i], limbBits[i])
+	}
+	return v, nil
+}
+
+// ExportEmulated returns the limbs of the canonical representation of the
+// emulated element e, to be imported with [ImportNative] in the other circuit
+// of the cycle, where T is the native field.
+func ExportEmulated[T emulated.FieldParams](f *emulated.Field[T], e *emulated.Element[T]) []frontend.Variable {
+	r := f.Reduce(e)
+	f.AssertIsInRange(r)
+	res := make([]frontend.Variable, len(r.Limbs))
+	copy(res, r.Limbs)
+	return res
+}
+
+// ImportEmulated returns the emulated element with the limbs returned by
+// [ExportNative] in the other circuit of the cycle. The limbs are range checked
+// and the element is asserted to be reduced.
+func ImportEmulated[T emulated.FieldParams](f *emulated.Field[T], limbs []frontend.Variable) (*emulated.Element[T], error) {
+	var fp T
+	if len(limbs) != int(fp.NbLimbs()) {
+		return nil, fmt.Errorf("expected %d limbs, got %d", fp.NbLimbs(), len(limbs))
+	}
+	e := f.NewElement(limbs)
+	f.AssertIsInRange(e)
+	return e, nil
+}
+
+// ValueOfLimbs returns the limbs of v modulo the modulus of T, for assigning
+// the values exchanged between the circuits of the cycle.
+func ValueOfLimbs[T emulated.FieldParams](v *big.Int) []frontend.Variable {
+	var fp T
+	r := new(big.Int).Mod(v, fp.Modulus())
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(fp.BitsPerLimb())), big.NewInt(1))
+	res := make([]frontend.Variable, fp.NbLimbs())
+	for i := range res {
+		res[i] = new(big.Int).And(r, mask)
+		r.Rsh(r, uint(fp.BitsPerLimb()))
+	}
+	return res
+}
+
+func checkNative[T emulated.FieldParams](api frontend.API, fp T) error {
+	if fp.Modulus().Cmp(api.Compiler().Field()) != 0 {
+		return fmt.Errorf("the modulus of the parameters is not the native modulus")
+	}
+	return nil
+}
+
+// group packs the bits into the limbs of T, padding the last limbs with zeros.
+func group[T emulated.FieldParams](api frontend.API, fp T, vBits []frontend.Variable) []frontend.Variable {
+	k := int(fp.BitsPerLimb())
+	res := make([]frontend.Variable, fp.NbLimbs())
+	for i := range res {
+		lo, hi := min(i*k, len(vBits)), min((i+1)*k, len(vBits))
+		res[i] = bits.FromBinary(api, vBits[lo:hi], bits.WithUnconstrainedInputs())
+	}
+	return res
+}
@@ -0,0 +1,120 @@
+package cycle
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+// exportCircuit runs in the circuit where T is native.
+type exportCircuit[T emulated.FieldParams] struct {
+	V     frontend.Variable
+	Limbs []frontend.Variable
+}
+
+func (c *exportCircuit[T]) Define(api frontend.API) error {
+	limbs, err := ExportNative[T](api, c.V)
+	if err != nil {
+		return err
+	}
+	for i := range limbs {
+		api.AssertIsEqual(limbs[i], c.Limbs[i])
+	}
+	v, err := ImportNative[T](api, c.Limbs)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(v, c.V)
+	return nil
+}
+
+// importCircuit runs in the circuit where T is emulated.
+type importCircuit[T emulated.FieldParams] struct {
+	E     emulated.Element[T]
+	Limbs []frontend.Variable
+}
+
+func (c *importCircuit[T]) Define(api frontend.API) error {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	e, err := ImportEmulated(f, c.Limbs)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(e, &c.E)
+	limbs := ExportEmulated(f, &c.E)
+	for i := range limbs {
+		api.AssertIsEqual(limbs[i], c.Limbs[i])
+	}
+	return nil
+}
+
+// testShuttle checks the exchange of values of the field T between the
+// circuit over T and the circuit over U.
+func testShuttle[T, U emulated.FieldParams](t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp T
+	var fq U
+	nbLimbs := int(fp.NbLimbs())
+	v, err := rand.Int(rand.Reader, fp.Modulus())
+	assert.NoError(err)
+	for _, tc := range []struct {
+		name string
+		v    *big.Int
+	}{
+		{"random", v},
+		{"zero", big.NewInt(0)},
+		{"max", new(big.Int).Sub(fp.Modulus(), big.NewInt(1))},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			limbs := ValueOfLimbs[T](tc.v)
+			err := test.IsSolved(&exportCircuit[T]{Limbs: make([]frontend.Variable, nbLimbs)}, &exportCircuit[T]{V: tc.v, Limbs: limbs}, fp.Modulus())
+			assert.NoError(err)
+			err = test.IsSolved(&importCircuit[T]{Limbs: make([]frontend.Variable, nbLimbs)}, &importCircuit[T]{E: emulated.ValueOf[T](tc.v), Limbs: limbs}, fq.Modulus())
+			assert.NoError(err)
+
+			// the non-reduced representations are rejected on both sides.
+			unreduced := unreducedLimbs[T](tc.v)
+			err = test.IsSolved(&exportCircuit[T]{Limbs: make([]frontend.Variable, nbLimbs)}, &exportCircuit[T]{V: tc.v, Limbs: unreduced}, fp.Modulus())
+			assert.Error(err)
+			err = test.IsSolved(&importCircuit[T]{Limbs: make([]frontend.Variable, nbLimbs)}, &importCircuit[T]{E: emulated.ValueOf[T](tc.v), Limbs: unreduced}, fq.Modulus())
+			assert.Error(err)
+		}, tc.name)
+	}
+}
+
+// unreducedLimbs returns the limbs of v + modulus.
+func unreducedLimbs[T emulated.FieldParams](v *big.Int) []frontend.Variable {
+	var fp T
+	r := new(big.Int).Add(v, fp.Modulus())
+	res := make([]frontend.Variable, fp.NbLimbs())
+	for i := range res {
+		res[i] = new(big.Int).SetUint64(r.Uint64())
+		r.Rsh(r, uint(fp.BitsPerLimb()))
+	}
+	return res
+}
+
+func TestShuttleBN254Grumpkin(t *testing.T) {
+	// the values of the BN254 circuit, such as the coordinates of the Grumpkin
+	// points, are emulated in the Grumpkin circuit.
+	testShuttle[emparams.BN254Fr, emparams.BN254Fp](t)
+}
+
+func TestShuttleGrumpkinBN254(t *testing.T) {
+	testShuttle[emparams.BN254Fp, emparams.BN254Fr](t)
+}
+
+func TestNotNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp emparams.BN254Fp
+	err := test.IsSolved(&exportCircuit[emparams.BN254Fr]{Limbs: make([]frontend.Variable, 4)}, &exportCircuit[emparams.BN254Fr]{V: 1, Limbs: ValueOfLimbs[emparams.BN254Fr](big.NewInt(1))}, fp.Modulus())
+	assert.Error(err)
+}
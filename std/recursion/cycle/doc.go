@@ -0,0 +1,21 @@
+// Package cycle provides the glue to carry state between the two circuits of
+// a cycle of curves, such as BN254 and Grumpkin, where the scalar field of one
+// curve is the base field of the other.
+//
+// In a folding scheme over a cycle, each circuit accumulates the instances of
+// the other one: the values which are native in one circuit, for example the
+// coordinates of the points or the challenges, are emulated in the other one
+// and conversely. The state is passed between the circuits as public inputs of
+// small limbs, which fit in both fields:
+//
+//   - [ExportNative] decomposes a native variable into the limbs of its
+//     emulated representation and [ImportEmulated] builds the emulated element
+//     from these limbs in the other circuit;
+//   - [ExportEmulated] returns the limbs of the canonical representation of an
+//     emulated element and [ImportNative] recomposes them into a native
+//     variable in the other circuit.
+//
+// All the functions assert that the values are reduced, so that every value
+// has a unique encoding and both circuits agree on the state. [ValueOfLimbs]
+// computes the limbs out of circuit for the assignments.
+package cycle
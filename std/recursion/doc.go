@@ -1,2 +1,20 @@
 // Package recursion provides in-circuit verifiers for different proofs systems.
+//
+// The verifiers are generic over the inner curve. The proofs over BLS12-377
+// and BLS24-315 are verified with native arithmetic in circuits over the
+// scalar fields of BW6-761 and BW6-633 respectively (2-chains). The circuits
+// over BW6-633 are cheaper to prove than over BW6-761, at the cost of a more
+// expensive pairing in circuit. The other combinations, such as BN254 in
+// BN254, use field emulation.
+//
+// There is no folding scheme, and so no incrementally verifiable computation.
+// A computation shaped as a tree, such as a map-reduce pipeline, is proved by
+// verifying the proofs of the children in the circuit of their parent, the
+// circuits of different nodes being verified with
+// [github.com/consensys/gnark/std/recursion/plonk.Verifier.AssertDifferentProofs].
+// Each level of the tree alternates the curves of a 2-chain or uses field
+// emulation, so that the depth of the tree is fixed by the circuits. Package
+// [github.com/consensys/gnark/std/recursion/pcd] instead defers the pairing
+// checks of the PLONK proofs to a decider at the root, for proof-carrying data
+// over a graph of unbounded depth.
 package recursion
@@ -566,6 +566,11 @@ func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.
 
 // AssertProof asserts that the SNARK proof holds for the given witness and
 // verifying key.
+//
+// The proof may have multiple commitments, whose knowledge proofs are folded.
+// A commitment without secret variables constrained in the circuit is the
+// point at infinity, which is not supported by the incomplete arithmetic of
+// the verifier.
 func (v *Verifier[FR, G1El, G2El, GtEl]) AssertProof(vk VerifyingKey[G1El, G2El, GtEl], proof Proof[G1El, G2El], witness Witness[FR], opts ...VerifierOption) error {
 	var fr FR
 	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
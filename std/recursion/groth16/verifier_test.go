@@ -453,3 +453,126 @@ func TestBW6InBN254Commitment(t *testing.T) {
 	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+// tests with multiple commitments
+
+type InnerCircuitMultiCommitment struct {
+	P, Q, R frontend.Variable
+	N       frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuitMultiCommitment) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q, c.R)
+	api.AssertIsEqual(res, c.N)
+
+	committer := api.Compiler().(frontend.Committer)
+	// independent commitments, to a secret and to a secret and a public input
+	c1, err := committer.Commit(c.P)
+	if err != nil {
+		return err
+	}
+	c2, err := committer.Commit(c.Q, c.N)
+	if err != nil {
+		return err
+	}
+	// commitment to a previous commitment and a new secret
+	c3, err := committer.Commit(c1, c.R)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(c1, c2)
+	api.AssertIsDifferent(c2, c3)
+	return nil
+}
+
+func TestBLS12InBW6MultiCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	field, outer := ecc.BLS12_377.ScalarField(), ecc.BW6_761.ScalarField()
+
+	innerCcs, err := frontend.Compile(field, r1cs.NewBuilder, &InnerCircuitMultiCommitment{})
+	assert.NoError(err)
+	assert.Equal(3, len(innerCcs.GetCommitments().CommitmentIndexes()))
+	innerPK, innerVK, err := groth16.Setup(innerCcs)
+	assert.NoError(err)
+	innerWitness, err := frontend.NewWitness(&InnerCircuitMultiCommitment{P: 3, Q: 5, R: 7, N: 105}, field)
+	assert.NoError(err)
+	innerProof, err := groth16.Prove(innerCcs, innerPK, innerWitness, GetNativeProverOptions(outer, field))
+	assert.NoError(err)
+	innerPubWitness, err := innerWitness.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(innerProof, innerVK, innerPubWitness, GetNativeVerifierOptions(outer, field)))
+
+	// outer proof
+	circuitVk, err := ValueOfVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[sw_bls12377.ScalarField](innerPubWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Proof:        PlaceholderProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerCcs),
+		InnerWitness: PlaceholderWitness[sw_bls12377.ScalarField](innerCcs),
+		VerifyingKey: PlaceholderVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerCcs),
+	}
+	outerAssignment := &OuterCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+		VerifyingKey: circuitVk,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, outer)
+	assert.NoError(err)
+}
+
+func TestBLS24InBW6(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInner(assert, ecc.BLS24_315.ScalarField())
+
+	// outer proof
+	circuitVk, err := ValueOfVerifyingKey[sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[sw_bls24315.ScalarField](innerWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: PlaceholderWitness[sw_bls24315.ScalarField](innerCcs),
+		VerifyingKey: PlaceholderVerifyingKey[sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT](innerCcs),
+	}
+	outerAssignment := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+		VerifyingKey: circuitVk,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_633.ScalarField())
+	assert.NoError(err)
+}
+
+func TestBLS24InBW6Commitment(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	innerCcs, innerVK, innerWitness, innerProof := getInnerCommitment(assert, ecc.BLS24_315.ScalarField(), ecc.BW6_633.ScalarField())
+	assert.Equal(len(innerCcs.GetCommitments().CommitmentIndexes()), 1)
+
+	// outer proof
+	circuitVk, err := ValueOfVerifyingKey[sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[sw_bls24315.ScalarField](innerWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		Proof:        PlaceholderProof[sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerCcs),
+		InnerWitness: PlaceholderWitness[sw_bls24315.ScalarField](innerCcs),
+		VerifyingKey: PlaceholderVerifyingKey[sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT](innerCcs),
+	}
+	outerAssignment := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+		VerifyingKey: circuitVk,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_633.ScalarField())
+	assert.NoError(err)
+}
@@ -0,0 +1,37 @@
+// Package pcd implements proof-carrying data over PLONK proofs, by deferring
+// their KZG pairing checks: a computation shaped as a directed acyclic graph,
+// such as a map-reduce pipeline, is proved by a proof per node, whose circuit
+// verifies the proofs of the children of the node except for their pairing
+// checks, which are accumulated and checked once by a decider at the root.
+//
+// An accumulator is a pair of points (L, R) of G₁, such that the accumulated
+// checks hold if e(L, [1]G₂)⋅e(R, [τ]G₂) = 1. In the circuit of a node, a
+// [Node]:
+//   - verifies the proof of each child up to its pairing check with
+//     [Node.Accumulate], which returns the accumulator of the check;
+//   - takes the accumulators of the children as witnesses, and binds each of
+//     them to the proof of its child with [Node.AssertDigest], the digest of the
+//     accumulator being a public input of the child;
+//   - combines all the accumulators into the accumulator of the node with
+//     [Node.Combine], which may fold any number of incoming accumulators, and
+//     sets its digest, see [Node.Digest], as a public input.
+//
+// The accumulators are computed out of circuit in the same way with
+// [Accumulate] and [Combine], to assign the witnesses of the parent, and
+// serialized with [NativeAccumulator.WriteTo] to be sent along the proofs
+// between the provers of the nodes. At the root, [Decide] checks the pairing
+// equation of the accumulator, after checking that the root proof is valid and
+// that its public digest is the one of the accumulator. A final circuit may
+// also check it with [Node.Decide].
+//
+// The digest is computed in the field of the circuit of the child, so that a
+// parent binds the accumulators of children proving on the same curve, with
+// field emulation, for example BN254 proofs verified in BN254 circuits: the
+// depth of the graph is then unbounded. A node verifying proofs of its own
+// circuit has its verifying key as a witness: it binds it by adding
+// [Node.DigestVerifyingKey] to the data of the digests, and the decider checks
+// it with [VerifyingKeyDigest].
+//
+// The proofs of the nodes are computed with the options of
+// [github.com/consensys/gnark/std/recursion/plonk.GetNativeProverOptions].
+package pcd
@@ -0,0 +1,478 @@
+package pcd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+	backend_plonk "github.com/consensys/gnark/backend/plonk"
+	plonkbackend_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonkbackend_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonkbackend_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315"
+	plonkbackend_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	plonkbackend_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/std/recursion/plonk"
+)
+
+// NativeAccumulator is an accumulator out of circuit, of points of G₁ of the
+// curve of the accumulated proofs. It is serialized with WriteTo, the points
+// being uncompressed, and deserialized with ReadFrom into an accumulator
+// returned by [NewAccumulator].
+type NativeAccumulator interface {
+	io.WriterTo
+	io.ReaderFrom
+
+	// CurveID returns the curve of the accumulated proofs.
+	CurveID() ecc.ID
+}
+
+// NewAccumulator returns an empty accumulator for the proofs on curve, to be
+// deserialized.
+func NewAccumulator(curve ecc.ID) (NativeAccumulator, error) {
+	switch curve {
+	case ecc.BN254:
+		return &accumulator[bn254.G1Affine, *bn254.G1Affine]{curve: curve}, nil
+	case ecc.BLS12_377:
+		return &accumulator[bls12377.G1Affine, *bls12377.G1Affine]{curve: curve}, nil
+	case ecc.BLS12_381:
+		return &accumulator[bls12381.G1Affine, *bls12381.G1Affine]{curve: curve}, nil
+	case ecc.BW6_761:
+		return &accumulator[bw6761.G1Affine, *bw6761.G1Affine]{curve: curve}, nil
+	case ecc.BLS24_315:
+		return &accumulator[bls24315.G1Affine, *bls24315.G1Affine]{curve: curve}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+}
+
+// g1Affine is the affine point of G₁ of a curve.
+type g1Affine[T any] interface {
+	*T
+	Add(a, b *T) *T
+	Neg(a *T) *T
+	ScalarMultiplication(a *T, s *big.Int) *T
+	Marshal() []byte
+	Unmarshal(buf []byte) error
+}
+
+type accumulator[T any, PT g1Affine[T]] struct {
+	curve ecc.ID
+	L, R  T
+}
+
+func (a *accumulator[T, PT]) CurveID() ecc.ID {
+	return a.curve
+}
+
+func (a *accumulator[T, PT]) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, p := range []PT{&a.L, &a.R} {
+		m, err := w.Write(p.Marshal())
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (a *accumulator[T, PT]) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, len(PT(&a.L).Marshal()))
+	var n int64
+	for _, p := range []PT{&a.L, &a.R} {
+		m, err := io.ReadFull(r, buf)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if err := p.Unmarshal(buf); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// openings are the KZG openings of a proof, whose pairing check is deferred.
+type openings[T any] struct {
+	digests, quotients []T
+	claimedValues      []*big.Int
+	points             []*big.Int
+}
+
+func (o *openings[T]) add(digest, quotient T, claimedValue, point interface{ BigInt(*big.Int) *big.Int }) {
+	o.digests = append(o.digests, digest)
+	o.quotients = append(o.quotients, quotient)
+	o.claimedValues = append(o.claimedValues, claimedValue.BigInt(new(big.Int)))
+	o.points = append(o.points, point.BigInt(new(big.Int)))
+}
+
+// Accumulate verifies the PLONK proof up to its KZG pairing check, and returns
+// the accumulator of this check computed as [Node.Accumulate] in a circuit
+// over the field outer. The proof must be computed with
+// [plonk.GetNativeProverOptions].
+func Accumulate(outer *big.Int, vk backend_plonk.VerifyingKey, proof backend_plonk.Proof, publicWitness witness.Witness) (NativeAccumulator, error) {
+	switch tVk := vk.(type) {
+	case *plonkbackend_bn254.VerifyingKey:
+		tProof, ok := proof.(*plonkbackend_bn254.Proof)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, proof)
+		}
+		w, ok := publicWitness.Vector().(fr_bn254.Vector)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, publicWitness.Vector())
+		}
+		digests, proofs, points, err := plonkbackend_bn254.VerifyDeferred(tProof, tVk, w, plonk.GetNativeVerifierOptions(outer, ecc.BN254.ScalarField()))
+		if err != nil {
+			return nil, err
+		}
+		var o openings[bn254.G1Affine]
+		for i := range digests {
+			o.add(digests[i], proofs[i].H, &proofs[i].ClaimedValue, &points[i])
+		}
+		return foldOpenings[bn254.G1Affine](ecc.BN254, outer, tVk.Kzg.G1, o)
+	case *plonkbackend_bls12377.VerifyingKey:
+		tProof, ok := proof.(*plonkbackend_bls12377.Proof)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, proof)
+		}
+		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, publicWitness.Vector())
+		}
+		digests, proofs, points, err := plonkbackend_bls12377.VerifyDeferred(tProof, tVk, w, plonk.GetNativeVerifierOptions(outer, ecc.BLS12_377.ScalarField()))
+		if err != nil {
+			return nil, err
+		}
+		var o openings[bls12377.G1Affine]
+		for i := range digests {
+			o.add(digests[i], proofs[i].H, &proofs[i].ClaimedValue, &points[i])
+		}
+		return foldOpenings[bls12377.G1Affine](ecc.BLS12_377, outer, tVk.Kzg.G1, o)
+	case *plonkbackend_bls12381.VerifyingKey:
+		tProof, ok := proof.(*plonkbackend_bls12381.Proof)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, proof)
+		}
+		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, publicWitness.Vector())
+		}
+		digests, proofs, points, err := plonkbackend_bls12381.VerifyDeferred(tProof, tVk, w, plonk.GetNativeVerifierOptions(outer, ecc.BLS12_381.ScalarField()))
+		if err != nil {
+			return nil, err
+		}
+		var o openings[bls12381.G1Affine]
+		for i := range digests {
+			o.add(digests[i], proofs[i].H, &proofs[i].ClaimedValue, &points[i])
+		}
+		return foldOpenings[bls12381.G1Affine](ecc.BLS12_381, outer, tVk.Kzg.G1, o)
+	case *plonkbackend_bw6761.VerifyingKey:
+		tProof, ok := proof.(*plonkbackend_bw6761.Proof)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, proof)
+		}
+		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, publicWitness.Vector())
+		}
+		digests, proofs, points, err := plonkbackend_bw6761.VerifyDeferred(tProof, tVk, w, plonk.GetNativeVerifierOptions(outer, ecc.BW6_761.ScalarField()))
+		if err != nil {
+			return nil, err
+		}
+		var o openings[bw6761.G1Affine]
+		for i := range digests {
+			o.add(digests[i], proofs[i].H, &proofs[i].ClaimedValue, &points[i])
+		}
+		return foldOpenings[bw6761.G1Affine](ecc.BW6_761, outer, tVk.Kzg.G1, o)
+	case *plonkbackend_bls24315.VerifyingKey:
+		tProof, ok := proof.(*plonkbackend_bls24315.Proof)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, proof)
+		}
+		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", vk, publicWitness.Vector())
+		}
+		digests, proofs, points, err := plonkbackend_bls24315.VerifyDeferred(tProof, tVk, w, plonk.GetNativeVerifierOptions(outer, ecc.BLS24_315.ScalarField()))
+		if err != nil {
+			return nil, err
+		}
+		var o openings[bls24315.G1Affine]
+		for i := range digests {
+			o.add(digests[i], proofs[i].H, &proofs[i].ClaimedValue, &points[i])
+		}
+		return foldOpenings[bls24315.G1Affine](ecc.BLS24_315, outer, tVk.Kzg.G1, o)
+	default:
+		return nil, fmt.Errorf("unsupported verifying key type %T", vk)
+	}
+}
+
+// foldOpenings folds the openings as [kzg.Verifier.FoldProofsMultiPoint]:
+// L = ∑ᵢλⁱ([fᵢ(α)]G₁ + zᵢ[Hᵢ(α)]G₁) - [∑ᵢλⁱfᵢ(zᵢ)]G₁ and R = -∑ᵢλⁱ[Hᵢ(α)]G₁.
+func foldOpenings[T any, PT g1Affine[T]](curve ecc.ID, outer *big.Int, g1 T, o openings[T]) (NativeAccumulator, error) {
+	fr := curve.ScalarField()
+	nbBytes := (fr.BitLen() + 7) / 8
+	h, err := recursion.NewShort(outer, fr)
+	if err != nil {
+		return nil, err
+	}
+	for i := range o.digests {
+		h.Write(PT(&o.digests[i]).Marshal())
+		h.Write(PT(&o.quotients[i]).Marshal())
+		h.Write(o.claimedValues[i].FillBytes(make([]byte, nbBytes)))
+		h.Write(o.points[i].FillBytes(make([]byte, nbBytes)))
+	}
+	lambda := new(big.Int).SetBytes(h.Sum(nil))
+
+	acc := &accumulator[T, PT]{curve: curve}
+	var l, r, tmp T
+	lambdaI := big.NewInt(1)
+	evals := new(big.Int)
+	for i := range o.digests {
+		// λⁱ(fᵢ(α) + zᵢHᵢ(α))
+		PT(&tmp).ScalarMultiplication(&o.quotients[i], o.points[i])
+		PT(&tmp).Add(&tmp, &o.digests[i])
+		PT(&tmp).ScalarMultiplication(&tmp, lambdaI)
+		PT(&l).Add(&l, &tmp)
+		// λⁱHᵢ(α)
+		PT(&tmp).ScalarMultiplication(&o.quotients[i], lambdaI)
+		PT(&r).Add(&r, &tmp)
+		// λⁱfᵢ(zᵢ)
+		evals.Add(evals, new(big.Int).Mul(lambdaI, o.claimedValues[i]))
+		lambdaI.Mul(lambdaI, lambda).Mod(lambdaI, fr)
+	}
+	evals.Mod(evals, fr)
+	PT(&tmp).ScalarMultiplication(&g1, evals)
+	PT(&tmp).Neg(&tmp)
+	PT(&acc.L).Add(&l, &tmp)
+	PT(&acc.R).Neg(&r)
+	return acc, nil
+}
+
+// Combine returns the accumulator of the checks of the accumulators, computed
+// as [Node.Combine] in a circuit over the field outer.
+func Combine(outer *big.Int, accs ...NativeAccumulator) (NativeAccumulator, error) {
+	if len(accs) == 0 {
+		return nil, errors.New("no accumulator to combine")
+	}
+	switch accs[0].(type) {
+	case *accumulator[bn254.G1Affine, *bn254.G1Affine]:
+		return combine[bn254.G1Affine](outer, accs)
+	case *accumulator[bls12377.G1Affine, *bls12377.G1Affine]:
+		return combine[bls12377.G1Affine](outer, accs)
+	case *accumulator[bls12381.G1Affine, *bls12381.G1Affine]:
+		return combine[bls12381.G1Affine](outer, accs)
+	case *accumulator[bw6761.G1Affine, *bw6761.G1Affine]:
+		return combine[bw6761.G1Affine](outer, accs)
+	case *accumulator[bls24315.G1Affine, *bls24315.G1Affine]:
+		return combine[bls24315.G1Affine](outer, accs)
+	default:
+		return nil, fmt.Errorf("unsupported accumulator type %T", accs[0])
+	}
+}
+
+func combine[T any, PT g1Affine[T]](outer *big.Int, accs []NativeAccumulator) (NativeAccumulator, error) {
+	tAccs := make([]*accumulator[T, PT], len(accs))
+	for i := range accs {
+		tAcc, ok := accs[i].(*accumulator[T, PT])
+		if !ok {
+			return nil, fmt.Errorf("mismatching types %T %T", accs[0], accs[i])
+		}
+		tAccs[i] = tAcc
+	}
+	if len(tAccs) == 1 {
+		return tAccs[0], nil
+	}
+	fr := tAccs[0].curve.ScalarField()
+	h, err := recursion.NewShort(outer, fr)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tAccs {
+		h.Write(PT(&tAccs[i].L).Marshal())
+		h.Write(PT(&tAccs[i].R).Marshal())
+	}
+	challenge := new(big.Int).SetBytes(h.Sum(nil))
+
+	res := &accumulator[T, PT]{curve: tAccs[0].curve, L: tAccs[0].L, R: tAccs[0].R}
+	var tmp T
+	challengeI := new(big.Int).Set(challenge)
+	for i := 1; i < len(tAccs); i++ {
+		PT(&tmp).ScalarMultiplication(&tAccs[i].L, challengeI)
+		PT(&res.L).Add(&res.L, &tmp)
+		PT(&tmp).ScalarMultiplication(&tAccs[i].R, challengeI)
+		PT(&res.R).Add(&res.R, &tmp)
+		challengeI.Mul(challengeI, challenge).Mod(challengeI, fr)
+	}
+	return res, nil
+}
+
+// Digest returns the digest of the accumulator and of the data, computed as
+// [Node.Digest] in a circuit over the scalar field of the curve of the
+// accumulator.
+func Digest(acc NativeAccumulator, data ...*big.Int) (*big.Int, error) {
+	var l, r []byte
+	switch tAcc := acc.(type) {
+	case *accumulator[bn254.G1Affine, *bn254.G1Affine]:
+		l, r = tAcc.L.Marshal(), tAcc.R.Marshal()
+	case *accumulator[bls12377.G1Affine, *bls12377.G1Affine]:
+		l, r = tAcc.L.Marshal(), tAcc.R.Marshal()
+	case *accumulator[bls12381.G1Affine, *bls12381.G1Affine]:
+		l, r = tAcc.L.Marshal(), tAcc.R.Marshal()
+	case *accumulator[bw6761.G1Affine, *bw6761.G1Affine]:
+		l, r = tAcc.L.Marshal(), tAcc.R.Marshal()
+	case *accumulator[bls24315.G1Affine, *bls24315.G1Affine]:
+		l, r = tAcc.L.Marshal(), tAcc.R.Marshal()
+	default:
+		return nil, fmt.Errorf("unsupported accumulator type %T", acc)
+	}
+	parts := [][]byte{l, r}
+	nbBytes := (acc.CurveID().ScalarField().BitLen() + 7) / 8
+	for i := range data {
+		parts = append(parts, data[i].FillBytes(make([]byte, nbBytes)))
+	}
+	return digest(acc.CurveID().ScalarField(), parts...)
+}
+
+// VerifyingKeyDigest returns the digest of the verifying key, computed as
+// [Node.DigestVerifyingKey] in a circuit over the scalar field of the curve of
+// the key.
+func VerifyingKeyDigest(vk backend_plonk.VerifyingKey) (*big.Int, error) {
+	var (
+		curve                          ecc.ID
+		g1                             []byte
+		cosetShift, sizeInv, generator []byte
+		size                           uint64
+		commitments                    [][]byte
+		commitmentConstraintIndexes    []uint64
+	)
+	switch tVk := vk.(type) {
+	case *plonkbackend_bn254.VerifyingKey:
+		curve, g1, size = ecc.BN254, tVk.Kzg.G1.Marshal(), tVk.Size
+		cosetShift, sizeInv, generator = tVk.CosetShift.Marshal(), tVk.SizeInv.Marshal(), tVk.Generator.Marshal()
+		for _, c := range append([]kzg_bn254.Digest{tVk.S[0], tVk.S[1], tVk.S[2], tVk.Ql, tVk.Qr, tVk.Qm, tVk.Qo, tVk.Qk}, tVk.Qcp...) {
+			commitments = append(commitments, c.Marshal())
+		}
+		commitmentConstraintIndexes = tVk.CommitmentConstraintIndexes
+	case *plonkbackend_bls12377.VerifyingKey:
+		curve, g1, size = ecc.BLS12_377, tVk.Kzg.G1.Marshal(), tVk.Size
+		cosetShift, sizeInv, generator = tVk.CosetShift.Marshal(), tVk.SizeInv.Marshal(), tVk.Generator.Marshal()
+		for _, c := range append([]kzg_bls12377.Digest{tVk.S[0], tVk.S[1], tVk.S[2], tVk.Ql, tVk.Qr, tVk.Qm, tVk.Qo, tVk.Qk}, tVk.Qcp...) {
+			commitments = append(commitments, c.Marshal())
+		}
+		commitmentConstraintIndexes = tVk.CommitmentConstraintIndexes
+	case *plonkbackend_bls12381.VerifyingKey:
+		curve, g1, size = ecc.BLS12_381, tVk.Kzg.G1.Marshal(), tVk.Size
+		cosetShift, sizeInv, generator = tVk.CosetShift.Marshal(), tVk.SizeInv.Marshal(), tVk.Generator.Marshal()
+		for _, c := range append([]kzg_bls12381.Digest{tVk.S[0], tVk.S[1], tVk.S[2], tVk.Ql, tVk.Qr, tVk.Qm, tVk.Qo, tVk.Qk}, tVk.Qcp...) {
+			commitments = append(commitments, c.Marshal())
+		}
+		commitmentConstraintIndexes = tVk.CommitmentConstraintIndexes
+	case *plonkbackend_bw6761.VerifyingKey:
+		curve, g1, size = ecc.BW6_761, tVk.Kzg.G1.Marshal(), tVk.Size
+		cosetShift, sizeInv, generator = tVk.CosetShift.Marshal(), tVk.SizeInv.Marshal(), tVk.Generator.Marshal()
+		for _, c := range append([]kzg_bw6761.Digest{tVk.S[0], tVk.S[1], tVk.S[2], tVk.Ql, tVk.Qr, tVk.Qm, tVk.Qo, tVk.Qk}, tVk.Qcp...) {
+			commitments = append(commitments, c.Marshal())
+		}
+		commitmentConstraintIndexes = tVk.CommitmentConstraintIndexes
+	case *plonkbackend_bls24315.VerifyingKey:
+		curve, g1, size = ecc.BLS24_315, tVk.Kzg.G1.Marshal(), tVk.Size
+		cosetShift, sizeInv, generator = tVk.CosetShift.Marshal(), tVk.SizeInv.Marshal(), tVk.Generator.Marshal()
+		for _, c := range append([]kzg_bls24315.Digest{tVk.S[0], tVk.S[1], tVk.S[2], tVk.Ql, tVk.Qr, tVk.Qm, tVk.Qo, tVk.Qk}, tVk.Qcp...) {
+			commitments = append(commitments, c.Marshal())
+		}
+		commitmentConstraintIndexes = tVk.CommitmentConstraintIndexes
+	default:
+		return nil, fmt.Errorf("unsupported verifying key type %T", vk)
+	}
+	nbBytes := (curve.ScalarField().BitLen() + 7) / 8
+	uint64Bytes := func(v uint64) []byte {
+		return new(big.Int).SetUint64(v).FillBytes(make([]byte, nbBytes))
+	}
+	parts := [][]byte{g1, cosetShift, uint64Bytes(size), sizeInv, generator}
+	parts = append(parts, commitments...)
+	for _, idx := range commitmentConstraintIndexes {
+		parts = append(parts, uint64Bytes(idx))
+	}
+	return digest(curve.ScalarField(), parts...)
+}
+
+// digest returns the short hash of the parts over field, as in a circuit over
+// field.
+func digest(field *big.Int, parts ...[]byte) (*big.Int, error) {
+	h, err := recursion.NewShort(field, field)
+	if err != nil {
+		return nil, err
+	}
+	for i := range parts {
+		h.Write(parts[i])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+// Decide checks the pairing equation of the accumulator, which holds if all
+// the accumulated checks hold, with the KZG verifying key of the proofs.
+func Decide(vk backend_plonk.VerifyingKey, acc NativeAccumulator) error {
+	var ok bool
+	var err error
+	switch tVk := vk.(type) {
+	case *plonkbackend_bn254.VerifyingKey:
+		tAcc, isAcc := acc.(*accumulator[bn254.G1Affine, *bn254.G1Affine])
+		if !isAcc {
+			return fmt.Errorf("mismatching types %T %T", vk, acc)
+		}
+		ok, err = bn254.PairingCheck([]bn254.G1Affine{tAcc.L, tAcc.R}, tVk.Kzg.G2[:])
+	case *plonkbackend_bls12377.VerifyingKey:
+		tAcc, isAcc := acc.(*accumulator[bls12377.G1Affine, *bls12377.G1Affine])
+		if !isAcc {
+			return fmt.Errorf("mismatching types %T %T", vk, acc)
+		}
+		ok, err = bls12377.PairingCheck([]bls12377.G1Affine{tAcc.L, tAcc.R}, tVk.Kzg.G2[:])
+	case *plonkbackend_bls12381.VerifyingKey:
+		tAcc, isAcc := acc.(*accumulator[bls12381.G1Affine, *bls12381.G1Affine])
+		if !isAcc {
+			return fmt.Errorf("mismatching types %T %T", vk, acc)
+		}
+		ok, err = bls12381.PairingCheck([]bls12381.G1Affine{tAcc.L, tAcc.R}, tVk.Kzg.G2[:])
+	case *plonkbackend_bw6761.VerifyingKey:
+		tAcc, isAcc := acc.(*accumulator[bw6761.G1Affine, *bw6761.G1Affine])
+		if !isAcc {
+			return fmt.Errorf("mismatching types %T %T", vk, acc)
+		}
+		ok, err = bw6761.PairingCheck([]bw6761.G1Affine{tAcc.L, tAcc.R}, tVk.Kzg.G2[:])
+	case *plonkbackend_bls24315.VerifyingKey:
+		tAcc, isAcc := acc.(*accumulator[bls24315.G1Affine, *bls24315.G1Affine])
+		if !isAcc {
+			return fmt.Errorf("mismatching types %T %T", vk, acc)
+		}
+		ok, err = bls24315.PairingCheck([]bls24315.G1Affine{tAcc.L, tAcc.R}, tVk.Kzg.G2[:])
+	default:
+		return fmt.Errorf("unsupported verifying key type %T", vk)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("the accumulated pairing checks don't hold")
+	}
+	return nil
+}
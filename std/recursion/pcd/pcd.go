@@ -0,0 +1,260 @@
+package pcd
+
+import (
+	"fmt"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/std/recursion/plonk"
+)
+
+// Accumulator is the accumulator of KZG pairing checks: the checks hold if
+// e(L, [1]G₂)⋅e(R, [τ]G₂) = 1. Use [ValueOfAccumulator] to initialize a
+// witness from a native accumulator.
+type Accumulator[G1El algebra.G1ElementT] struct {
+	L, R G1El
+}
+
+// ValueOfAccumulator initializes an accumulator witness from a native
+// accumulator. It returns an error if there is a conflict between the type
+// parameters and the curve of the accumulator.
+func ValueOfAccumulator[G1El algebra.G1ElementT](acc NativeAccumulator) (Accumulator[G1El], error) {
+	var ret Accumulator[G1El]
+	switch s := any(&ret).(type) {
+	case *Accumulator[sw_bn254.G1Affine]:
+		tAcc, ok := acc.(*accumulator[bn254.G1Affine, *bn254.G1Affine])
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, acc)
+		}
+		s.L, s.R = sw_bn254.NewG1Affine(tAcc.L), sw_bn254.NewG1Affine(tAcc.R)
+	case *Accumulator[sw_bls12377.G1Affine]:
+		tAcc, ok := acc.(*accumulator[bls12377.G1Affine, *bls12377.G1Affine])
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, acc)
+		}
+		s.L, s.R = sw_bls12377.NewG1Affine(tAcc.L), sw_bls12377.NewG1Affine(tAcc.R)
+	case *Accumulator[sw_bls12381.G1Affine]:
+		tAcc, ok := acc.(*accumulator[bls12381.G1Affine, *bls12381.G1Affine])
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, acc)
+		}
+		s.L, s.R = sw_bls12381.NewG1Affine(tAcc.L), sw_bls12381.NewG1Affine(tAcc.R)
+	case *Accumulator[sw_bw6761.G1Affine]:
+		tAcc, ok := acc.(*accumulator[bw6761.G1Affine, *bw6761.G1Affine])
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, acc)
+		}
+		s.L, s.R = sw_bw6761.NewG1Affine(tAcc.L), sw_bw6761.NewG1Affine(tAcc.R)
+	case *Accumulator[sw_bls24315.G1Affine]:
+		tAcc, ok := acc.(*accumulator[bls24315.G1Affine, *bls24315.G1Affine])
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, acc)
+		}
+		s.L, s.R = sw_bls24315.NewG1Affine(tAcc.L), sw_bls24315.NewG1Affine(tAcc.R)
+	default:
+		return ret, fmt.Errorf("unknown type parametrization")
+	}
+	return ret, nil
+}
+
+// Node accumulates, in the circuit of a node of the computation, the proofs
+// and the accumulators of its children.
+type Node[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	api       frontend.API
+	scalarApi *emulated.Field[FR]
+	curve     algebra.Curve[FR, G1El]
+	pairing   algebra.Pairing[G1El, G2El, GtEl]
+	kzg       *kzg.Verifier[FR, G1El, G2El, GtEl]
+	verifier  *plonk.Verifier[FR, G1El, G2El, GtEl]
+}
+
+// NewNode returns a new [Node] instance.
+func NewNode[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API) (*Node[FR, G1El, G2El, GtEl], error) {
+	curve, err := algebra.GetCurve[FR, G1El](api)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("new pairing: %w", err)
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalars: %w", err)
+	}
+	kzgVerifier, err := kzg.NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("new kzg verifier: %w", err)
+	}
+	verifier, err := plonk.NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("new plonk verifier: %w", err)
+	}
+	return &Node[FR, G1El, G2El, GtEl]{
+		api:       api,
+		scalarApi: f,
+		curve:     curve,
+		pairing:   pairing,
+		kzg:       kzgVerifier,
+		verifier:  verifier,
+	}, nil
+}
+
+// Accumulate verifies the PLONK proof up to its KZG pairing check, and returns
+// the accumulator of this check. The native counterpart is [Accumulate].
+func (n *Node[FR, G1El, G2El, GtEl]) Accumulate(vk plonk.VerifyingKey[FR, G1El, G2El], proof plonk.Proof[FR, G1El, G2El], witness plonk.Witness[FR], opts ...plonk.VerifierOption) (Accumulator[G1El], error) {
+	digests, proofs, points, err := n.verifier.PrepareVerification(vk, proof, witness, opts...)
+	if err != nil {
+		return Accumulator[G1El]{}, fmt.Errorf("prepare verification: %w", err)
+	}
+	l, r, err := n.kzg.FoldProofsMultiPoint(digests, proofs, points, vk.Kzg)
+	if err != nil {
+		return Accumulator[G1El]{}, fmt.Errorf("fold openings: %w", err)
+	}
+	return Accumulator[G1El]{L: *l, R: *r}, nil
+}
+
+// Combine returns the accumulator of the checks of the accumulators, the sum of
+// the accumulators weighted by the powers of a challenge derived from them.
+// The native counterpart is [Combine].
+func (n *Node[FR, G1El, G2El, GtEl]) Combine(accs ...Accumulator[G1El]) (Accumulator[G1El], error) {
+	if len(accs) == 0 {
+		return Accumulator[G1El]{}, fmt.Errorf("no accumulator to combine")
+	}
+	if len(accs) == 1 {
+		return accs[0], nil
+	}
+	var fr FR
+	h, err := recursion.NewHash(n.api, fr.Modulus(), true)
+	if err != nil {
+		return Accumulator[G1El]{}, err
+	}
+	for i := range accs {
+		h.Write(n.curve.MarshalG1(accs[i].L)...)
+		h.Write(n.curve.MarshalG1(accs[i].R)...)
+	}
+	seed := bits.ToBinary(n.api, h.Sum(), bits.WithNbDigits(fr.Modulus().BitLen()))
+	challenges := make([]*emulated.Element[FR], len(accs)-1)
+	challenges[0] = n.scalarApi.FromBits(seed...)
+	for i := 1; i < len(challenges); i++ {
+		challenges[i] = n.scalarApi.Mul(challenges[0], challenges[i-1])
+	}
+	ls := make([]*G1El, len(accs)-1)
+	rs := make([]*G1El, len(accs)-1)
+	for i := range ls {
+		ls[i], rs[i] = &accs[i+1].L, &accs[i+1].R
+	}
+	l, err := n.curve.MultiScalarMul(ls, challenges)
+	if err != nil {
+		return Accumulator[G1El]{}, fmt.Errorf("combine L: %w", err)
+	}
+	r, err := n.curve.MultiScalarMul(rs, challenges)
+	if err != nil {
+		return Accumulator[G1El]{}, fmt.Errorf("combine R: %w", err)
+	}
+	return Accumulator[G1El]{
+		L: *n.curve.Add(&accs[0].L, l),
+		R: *n.curve.Add(&accs[0].R, r),
+	}, nil
+}
+
+// Digest returns the digest of the accumulator and of the data, to be set as a
+// public input of the node so that its parent binds the accumulator to the
+// proof of the node with [Node.AssertDigest]. The data are usually the digest
+// of the verifying key of the children, see [Node.DigestVerifyingKey]. The
+// native counterpart is [Digest].
+func (n *Node[FR, G1El, G2El, GtEl]) Digest(acc Accumulator[G1El], data ...frontend.Variable) (frontend.Variable, error) {
+	h, err := recursion.NewHash(n.api, n.api.Compiler().Field(), true)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(n.curve.MarshalG1(acc.L)...)
+	h.Write(n.curve.MarshalG1(acc.R)...)
+	for i := range data {
+		h.Write(n.marshalNative(data[i])...)
+	}
+	return h.Sum(), nil
+}
+
+// AssertDigest asserts that public, a public input of the proof of a child, is
+// the digest of the accumulator of the child and of the data, see
+// [Node.Digest]. As the digest is computed in the field of the circuit of the
+// child, the proofs must be on the curve whose scalar field is the native
+// field, with field emulation.
+func (n *Node[FR, G1El, G2El, GtEl]) AssertDigest(public emulated.Element[FR], acc Accumulator[G1El], data ...frontend.Variable) error {
+	var fr FR
+	if fr.Modulus().Cmp(n.api.Compiler().Field()) != 0 {
+		return fmt.Errorf("the scalar field of the proofs is not the native field")
+	}
+	d, err := n.Digest(acc, data...)
+	if err != nil {
+		return err
+	}
+	n.scalarApi.AssertIsEqual(&public, n.scalarApi.FromBits(bits.ToBinary(n.api, d)...))
+	return nil
+}
+
+// DigestVerifyingKey returns the digest of the verifying key, for the circuits
+// verifying the proofs of their own circuit, whose verifying key is a witness:
+// the nodes then add the digest to the data of [Node.Digest] and
+// [Node.AssertDigest] so that all the nodes use the same key, and the decider
+// checks it with [VerifyingKeyDigest]. The G₂ points of the key aren't used
+// in the circuit and are not part of the digest. The native counterpart is
+// [VerifyingKeyDigest].
+func (n *Node[FR, G1El, G2El, GtEl]) DigestVerifyingKey(vk plonk.VerifyingKey[FR, G1El, G2El]) (frontend.Variable, error) {
+	h, err := recursion.NewHash(n.api, n.api.Compiler().Field(), true)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(n.curve.MarshalG1(vk.Kzg.G1)...)
+	h.Write(n.curve.MarshalScalar(vk.CosetShift)...)
+	h.Write(n.marshalNative(vk.Size)...)
+	h.Write(n.curve.MarshalScalar(vk.SizeInv)...)
+	h.Write(n.curve.MarshalScalar(vk.Generator)...)
+	commitments := append([]kzg.Commitment[G1El]{vk.S[0], vk.S[1], vk.S[2], vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk}, vk.Qcp...)
+	for i := range commitments {
+		h.Write(n.curve.MarshalG1(commitments[i].G1El)...)
+	}
+	for i := range vk.CommitmentConstraintIndexes {
+		h.Write(n.marshalNative(vk.CommitmentConstraintIndexes[i])...)
+	}
+	return h.Sum(), nil
+}
+
+// Decide asserts that the checks of the accumulator hold, to prove the root of
+// the computation in a final circuit. The native counterpart is [Decide].
+func (n *Node[FR, G1El, G2El, GtEl]) Decide(acc Accumulator[G1El], vk kzg.VerifyingKey[G1El, G2El]) error {
+	if err := n.pairing.PairingCheck([]*G1El{&acc.L, &acc.R}, []*G2El{&vk.G2[0], &vk.G2[1]}); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// marshalNative returns the bits of the native variable v, on full bytes with
+// the most significant bit first, as the marshalled field elements.
+func (n *Node[FR, G1El, G2El, GtEl]) marshalNative(v frontend.Variable) []frontend.Variable {
+	nbBits := n.api.Compiler().FieldBitLen()
+	res := make([]frontend.Variable, 8*((nbBits+7)/8))
+	for i := range res {
+		res[i] = 0
+	}
+	vBits := bits.ToBinary(n.api, v, bits.WithNbDigits(nbBits))
+	for i := range vBits {
+		res[len(res)-1-i] = vBits[i]
+	}
+	return res
+}
@@ -0,0 +1,185 @@
+package pcd
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	native_plonk "github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+type leafCircuit struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *leafCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.P, c.Q), c.N)
+	return nil
+}
+
+// nodeCircuit accumulates the proofs of two leaves and the accumulator of a
+// child node.
+type nodeCircuit struct {
+	VerifyingKey plonk.VerifyingKey[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine] `gnark:"-"`
+	Proofs       [2]plonk.Proof[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine]
+	Witnesses    [2]plonk.Witness[sw_bn254.ScalarField]
+	Child        Accumulator[sw_bn254.G1Affine]
+	ChildDigest  emulated.Element[sw_bn254.ScalarField]
+	Digest       frontend.Variable `gnark:",public"`
+}
+
+func (c *nodeCircuit) Define(api frontend.API) error {
+	node, err := NewNode[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](api)
+	if err != nil {
+		return fmt.Errorf("new node: %w", err)
+	}
+	vkDigest, err := node.DigestVerifyingKey(c.VerifyingKey)
+	if err != nil {
+		return err
+	}
+	if err := node.AssertDigest(c.ChildDigest, c.Child, vkDigest); err != nil {
+		return err
+	}
+	accs := []Accumulator[sw_bn254.G1Affine]{c.Child}
+	for i := range c.Proofs {
+		acc, err := node.Accumulate(c.VerifyingKey, c.Proofs[i], c.Witnesses[i], plonk.WithCompleteArithmetic())
+		if err != nil {
+			return err
+		}
+		accs = append(accs, acc)
+	}
+	acc, err := node.Combine(accs...)
+	if err != nil {
+		return err
+	}
+	d, err := node.Digest(acc, vkDigest)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(d, c.Digest)
+	return nil
+}
+
+func proveLeaves(assert *test.Assert, field *big.Int, n int) (constraint.ConstraintSystem, native_plonk.VerifyingKey, []native_plonk.Proof, []witness.Witness) {
+	ccs, err := frontend.Compile(field, scs.NewBuilder, &leafCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := native_plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+
+	proofs := make([]native_plonk.Proof, n)
+	witnesses := make([]witness.Witness, n)
+	for i := range proofs {
+		w, err := frontend.NewWitness(&leafCircuit{P: 3, Q: i + 2, N: 3 * (i + 2)}, field)
+		assert.NoError(err)
+		proofs[i], err = native_plonk.Prove(ccs, pk, w, plonk.GetNativeProverOptions(field, field))
+		assert.NoError(err)
+		witnesses[i], err = w.Public()
+		assert.NoError(err)
+	}
+	return ccs, vk, proofs, witnesses
+}
+
+func TestNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	_, vk, proofs, witnesses := proveLeaves(assert, field, 2)
+
+	accs := make([]NativeAccumulator, len(proofs))
+	for i := range proofs {
+		var err error
+		accs[i], err = Accumulate(field, vk, proofs[i], witnesses[i])
+		assert.NoError(err)
+		assert.NoError(Decide(vk, accs[i]))
+	}
+	acc, err := Combine(field, accs...)
+	assert.NoError(err)
+	assert.NoError(Decide(vk, acc))
+
+	// serialization
+	var buf bytes.Buffer
+	_, err = acc.WriteTo(&buf)
+	assert.NoError(err)
+	read, err := NewAccumulator(ecc.BN254)
+	assert.NoError(err)
+	_, err = read.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.NoError(Decide(vk, read))
+	d, err := Digest(acc)
+	assert.NoError(err)
+	dRead, err := Digest(read)
+	assert.NoError(err)
+	assert.Equal(d, dRead)
+
+	// the points of different accumulators don't satisfy the check
+	var buf0, buf1 bytes.Buffer
+	_, err = accs[0].WriteTo(&buf0)
+	assert.NoError(err)
+	_, err = accs[1].WriteTo(&buf1)
+	assert.NoError(err)
+	half := buf0.Len() / 2
+	mixed, err := NewAccumulator(ecc.BN254)
+	assert.NoError(err)
+	_, err = mixed.ReadFrom(bytes.NewReader(append(buf0.Bytes()[:half], buf1.Bytes()[half:]...)))
+	assert.NoError(err)
+	assert.Error(Decide(vk, mixed))
+
+	// the proof must hold for the public witness
+	_, err = Accumulate(field, vk, proofs[0], witnesses[1])
+	assert.Error(err)
+}
+
+func TestNode(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	ccs, vk, proofs, witnesses := proveLeaves(assert, field, 3)
+
+	accs := make([]NativeAccumulator, len(proofs))
+	for i := range proofs {
+		var err error
+		accs[i], err = Accumulate(field, vk, proofs[i], witnesses[i])
+		assert.NoError(err)
+	}
+	vkDigest, err := VerifyingKeyDigest(vk)
+	assert.NoError(err)
+	// the third proof is accumulated by a child node
+	childDigest, err := Digest(accs[2], vkDigest)
+	assert.NoError(err)
+	acc, err := Combine(field, accs[2], accs[0], accs[1])
+	assert.NoError(err)
+	assert.NoError(Decide(vk, acc))
+	digest, err := Digest(acc, vkDigest)
+	assert.NoError(err)
+
+	circuitVk, err := plonk.ValueOfVerifyingKey[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine](vk)
+	assert.NoError(err)
+	circuit := &nodeCircuit{VerifyingKey: circuitVk}
+	assignment := &nodeCircuit{
+		ChildDigest: emulated.ValueOf[sw_bn254.ScalarField](childDigest),
+		Digest:      digest,
+	}
+	assignment.Child, err = ValueOfAccumulator[sw_bn254.G1Affine](accs[2])
+	assert.NoError(err)
+	for i := range circuit.Proofs {
+		circuit.Proofs[i] = plonk.PlaceholderProof[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine](ccs)
+		circuit.Witnesses[i] = plonk.PlaceholderWitness[sw_bn254.ScalarField](ccs)
+		assignment.Proofs[i], err = plonk.ValueOfProof[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine](proofs[i])
+		assert.NoError(err)
+		assignment.Witnesses[i], err = plonk.ValueOfWitness[sw_bn254.ScalarField](witnesses[i])
+		assert.NoError(err)
+	}
+	assert.NoError(test.IsSolved(circuit, assignment, field))
+}
@@ -271,6 +271,8 @@ func PlaceholderProof[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El alg
 
 // BaseVerifyingKey is the common part of the verification key for the circuits
 // with same size, same number of public inputs and same number of commitments.
+// The circuits verified with [Verifier.AssertDifferentProofs] may also differ in
+// size and number of public inputs.
 // Use [PlaceholderBaseVerifyingKey] for creating a placeholder for compiling
 // and [ValueOfBaseVerifyingKey] for witness assignment.
 type BaseVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT] struct {
@@ -302,6 +304,12 @@ type CircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT] struc
 	Qcp []kzg.Commitment[G1El]
 
 	CommitmentConstraintIndexes []frontend.Variable
+
+	// NbPublicInputs is the number of public inputs of the circuit. It is
+	// fixed when compiling the outer circuit and only used when verifying
+	// proofs of circuits with different numbers of public inputs, see
+	// [Verifier.AssertDifferentProofs].
+	NbPublicInputs uint64 `gnark:"-"`
 }
 
 // VerifyingKey is a typed PLONK verification key. Use [ValueOfVerifyingKey] or
@@ -391,6 +399,7 @@ func ValueOfCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT
 			return ret, fmt.Errorf("expected bls12377.VerifyingKey, got %T", vk)
 		}
 		r.Size = tVk.Size
+		r.NbPublicInputs = tVk.NbPublicVariables
 		r.SizeInv = sw_bls12377.NewScalar(tVk.SizeInv)
 		r.Generator = sw_bls12377.NewScalar(tVk.Generator)
 		for i := range r.S {
@@ -436,6 +445,7 @@ func ValueOfCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT
 			return ret, fmt.Errorf("expected bls12381.VerifyingKey, got %T", vk)
 		}
 		r.Size = tVk.Size
+		r.NbPublicInputs = tVk.NbPublicVariables
 		r.SizeInv = sw_bls12381.NewScalar(tVk.SizeInv)
 		r.Generator = sw_bls12381.NewScalar(tVk.Generator)
 		for i := range r.S {
@@ -481,6 +491,7 @@ func ValueOfCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT
 			return ret, fmt.Errorf("expected bls24315.VerifyingKey, got %T", vk)
 		}
 		r.Size = tVk.Size
+		r.NbPublicInputs = tVk.NbPublicVariables
 		r.SizeInv = sw_bls24315.NewScalar(tVk.SizeInv)
 		r.Generator = sw_bls24315.NewScalar(tVk.Generator)
 		for i := range r.S {
@@ -526,6 +537,7 @@ func ValueOfCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT
 			return ret, fmt.Errorf("expected bls12377.VerifyingKey, got %T", vk)
 		}
 		r.Size = tVk.Size
+		r.NbPublicInputs = tVk.NbPublicVariables
 		r.SizeInv = sw_bw6761.NewScalar(tVk.SizeInv)
 		r.Generator = sw_bw6761.NewScalar(tVk.Generator)
 		for i := range r.S {
@@ -571,6 +583,7 @@ func ValueOfCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT
 			return ret, fmt.Errorf("expected bn254.VerifyingKey, got %T", vk)
 		}
 		r.Size = tVk.Size
+		r.NbPublicInputs = tVk.NbPublicVariables
 		r.SizeInv = sw_bn254.NewScalar(tVk.SizeInv)
 		r.Generator = sw_bn254.NewScalar(tVk.Generator)
 		for i := range r.S {
@@ -653,6 +666,7 @@ func PlaceholderCircuitVerifyingKey[FR emulated.FieldParams, G1El algebra.G1Elem
 	return CircuitVerifyingKey[FR, G1El]{
 		CommitmentConstraintIndexes: make([]frontend.Variable, len(commitmentIndexes)),
 		Qcp:                         make([]kzg.Commitment[G1El], len(commitmentIndexes)),
+		NbPublicInputs:              uint64(ccs.GetNbPublicVariables()),
 	}
 }
 
@@ -739,6 +753,23 @@ func PlaceholderWitness[FR emulated.FieldParams](ccs constraint.ConstraintSystem
 	}
 }
 
+// ValueOfPaddedWitness returns the witness of w padded with zeros to nbPublic
+// public inputs, for verifying the proofs of circuits with different numbers
+// of public inputs with [Verifier.AssertDifferentProofs].
+func ValueOfPaddedWitness[FR emulated.FieldParams](w witness.Witness, nbPublic int) (Witness[FR], error) {
+	ret, err := ValueOfWitness[FR](w)
+	if err != nil {
+		return ret, err
+	}
+	if len(ret.Public) > nbPublic {
+		return ret, fmt.Errorf("witness has %d public inputs, more than %d", len(ret.Public), nbPublic)
+	}
+	for len(ret.Public) < nbPublic {
+		ret.Public = append(ret.Public, emulated.ValueOf[FR](0))
+	}
+	return ret, nil
+}
+
 // Verifier verifies PLONK proofs.
 type Verifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
 	api       frontend.API
@@ -778,8 +809,6 @@ func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.
 // PrepareVerification returns a list of (openingProof, commitment, point), which are to be
 // verified using kzg's BatchVerifyMultiPoints.
 func (v *Verifier[FR, G1El, G2El, GtEl]) PrepareVerification(vk VerifyingKey[FR, G1El, G2El], proof Proof[FR, G1El, G2El], witness Witness[FR], opts ...VerifierOption) ([]kzg.Commitment[G1El], []kzg.OpeningProof[FR, G1El], []emulated.Element[FR], error) {
-
-	var fr FR
 	cfg, err := newCfg(opts...)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("apply options: %w", err)
@@ -787,28 +816,44 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) PrepareVerification(vk VerifyingKey[FR,
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
 		return nil, nil, nil, fmt.Errorf("BSB22 commitment number mismatch")
 	}
+	ch, err := v.deriveChallenges(vk, proof, witness)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return v.prepareVerification(vk, proof, witness, ch, vk.NbPublicVariables, cfg)
+}
+
+// challenges are the Fiat-Shamir challenges of a PLONK proof.
+type challenges[FR emulated.FieldParams] struct {
+	gamma, beta, alpha, zeta *emulated.Element[FR]
+}
 
+// deriveChallenges computes the challenges of the proof, binding the public
+// inputs in witness.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveChallenges(vk VerifyingKey[FR, G1El, G2El], proof Proof[FR, G1El, G2El], witness Witness[FR]) (challenges[FR], error) {
+	var fr FR
+	var ch challenges[FR]
 	fs, err := recursion.NewTranscript(v.api, fr.Modulus(), []string{"gamma", "beta", "alpha", "zeta"})
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("init new transcript: %w", err)
+		return ch, fmt.Errorf("init new transcript: %w", err)
 	}
 
 	if err := v.bindPublicData(fs, "gamma", vk, witness); err != nil {
-		return nil, nil, nil, fmt.Errorf("bind public data: %w", err)
+		return ch, fmt.Errorf("bind public data: %w", err)
 	}
 
 	// The first challenge is derived using the public data: the commitments to the permutation,
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
-	gamma, err := v.deriveRandomness(fs, "gamma", proof.LRO[0].G1El, proof.LRO[1].G1El, proof.LRO[2].G1El)
+	ch.gamma, err = v.deriveRandomness(fs, "gamma", proof.LRO[0].G1El, proof.LRO[1].G1El, proof.LRO[2].G1El)
 	if err != nil {
-		return nil, nil, nil, err
+		return ch, err
 	}
 
 	// derive beta from Comm(l), Comm(r), Comm(o)
-	beta, err := v.deriveRandomness(fs, "beta")
+	ch.beta, err = v.deriveRandomness(fs, "beta")
 	if err != nil {
-		return nil, nil, nil, err
+		return ch, err
 	}
 
 	// derive alpha from Comm(l), Comm(r), Comm(o), Com(Z), Bsb22Commitments
@@ -817,16 +862,25 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) PrepareVerification(vk VerifyingKey[FR,
 		alphaDeps[i] = proof.Bsb22Commitments[i].G1El
 	}
 	alphaDeps[len(alphaDeps)-1] = proof.Z.G1El
-	alpha, err := v.deriveRandomness(fs, "alpha", alphaDeps...)
+	ch.alpha, err = v.deriveRandomness(fs, "alpha", alphaDeps...)
 	if err != nil {
-		return nil, nil, nil, err
+		return ch, err
 	}
 
 	// derive zeta, the point of evaluation
-	zeta, err := v.deriveRandomness(fs, "zeta", proof.H[0].G1El, proof.H[1].G1El, proof.H[2].G1El)
+	ch.zeta, err = v.deriveRandomness(fs, "zeta", proof.H[0].G1El, proof.H[1].G1El, proof.H[2].G1El)
 	if err != nil {
-		return nil, nil, nil, err
+		return ch, err
 	}
+	return ch, nil
+}
+
+// prepareVerification returns the KZG openings to check for the proof with
+// the challenges ch. nbPublic is the number of public inputs of the circuit,
+// which precede the commitment constraints.
+func (v *Verifier[FR, G1El, G2El, GtEl]) prepareVerification(vk VerifyingKey[FR, G1El, G2El], proof Proof[FR, G1El, G2El], witness Witness[FR], ch challenges[FR], nbPublic frontend.Variable, cfg *verifierCfg) ([]kzg.Commitment[G1El], []kzg.OpeningProof[FR, G1El], []emulated.Element[FR], error) {
+	var fr FR
+	gamma, beta, alpha, zeta := ch.gamma, ch.beta, ch.alpha, ch.zeta
 
 	// evaluation of zhZetaZ=ζⁿ-1
 	one := v.scalarApi.One()
@@ -868,7 +922,7 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) PrepareVerification(vk VerifyingKey[FR,
 			return nil, nil, nil, err
 		}
 		for i := range vk.CommitmentConstraintIndexes {
-			li := v.computeIthLagrangeAtZeta(v.api.Add(vk.CommitmentConstraintIndexes[i], vk.NbPublicVariables), zeta, zetaPowerN, vk)
+			li := v.computeIthLagrangeAtZeta(v.api.Add(vk.CommitmentConstraintIndexes[i], nbPublic), zeta, zetaPowerN, vk)
 			marshalledCommitment := v.curve.MarshalG1(proof.Bsb22Commitments[i].G1El)
 			hashToField.Write(marshalledCommitment...)
 			hashedCmt := hashToField.Sum()
@@ -1080,6 +1134,15 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertSameProofs(vk VerifyingKey[FR, G1
 // cvks. The selector which verification key to use ise given in slice switches.
 // The proofs and witnesses are given in the argumens and must correspond to
 // each other.
+//
+// The circuits may have different numbers of public inputs, as given by the
+// NbPublicInputs of the circuit verification keys, in which case the number
+// of public inputs of bvk is ignored. The witnesses must then have as many
+// inputs as the largest circuit, the inputs after the ones of the selected
+// circuit being asserted to be zero, see [ValueOfPaddedWitness]. As the
+// transcript of a proof depends on its number of public inputs, the
+// challenges are computed for every distinct number of public inputs and
+// selected with the verification key.
 func (v *Verifier[FR, G1El, G2El, GtEl]) AssertDifferentProofs(bvk BaseVerifyingKey[FR, G1El, G2El], cvks []CircuitVerifyingKey[FR, G1El],
 	switches []frontend.Variable, proofs []Proof[FR, G1El, G2El], witnesses []Witness[FR], opts ...VerifierOption) error {
 	if len(proofs) != len(witnesses) || len(proofs) != len(switches) {
@@ -1088,6 +1151,20 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertDifferentProofs(bvk BaseVerifying
 	if len(proofs) == 0 {
 		return fmt.Errorf("no proofs to check")
 	}
+	cfg, err := newCfg(opts...)
+	if err != nil {
+		return fmt.Errorf("apply options: %w", err)
+	}
+	var nbPublics []uint64
+	for i := range cvks {
+		if cvks[i].NbPublicInputs != cvks[0].NbPublicInputs {
+			nbPublics = make([]uint64, len(cvks))
+			for j := range cvks {
+				nbPublics[j] = cvks[j].NbPublicInputs
+			}
+			break
+		}
+	}
 	var foldedDigests []kzg.Commitment[G1El]
 	var foldedProofs []kzg.OpeningProof[FR, G1El]
 	var foldedPoints []emulated.Element[FR]
@@ -1096,7 +1173,20 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertDifferentProofs(bvk BaseVerifying
 		if err != nil {
 			return fmt.Errorf("switch verification key: %w", err)
 		}
-		dg, pr, pts, err := v.PrepareVerification(vk, proofs[i], witnesses[i], opts...)
+		if len(proofs[i].Bsb22Commitments) != len(vk.Qcp) {
+			return fmt.Errorf("proof %d: BSB22 commitment number mismatch", i)
+		}
+		var ch challenges[FR]
+		var nbPublic frontend.Variable = vk.NbPublicVariables
+		if nbPublics == nil {
+			ch, err = v.deriveChallenges(vk, proofs[i], witnesses[i])
+		} else {
+			ch, nbPublic, err = v.deriveSwitchedChallenges(vk, switches[i], nbPublics, proofs[i], witnesses[i])
+		}
+		if err != nil {
+			return fmt.Errorf("proof %d: %w", i, err)
+		}
+		dg, pr, pts, err := v.prepareVerification(vk, proofs[i], witnesses[i], ch, nbPublic, cfg)
 		if err != nil {
 			return fmt.Errorf("prepare proof %d: %w", i, err)
 		}
@@ -1110,6 +1200,66 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertDifferentProofs(bvk BaseVerifying
 	return nil
 }
 
+// deriveSwitchedChallenges computes the challenges of a proof for the circuit
+// idx, where circuit j has nbPublics[j] public inputs. It asserts that the
+// inputs of the witness after the ones of the circuit are zero and returns the
+// challenges and the number of public inputs of the circuit.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveSwitchedChallenges(vk VerifyingKey[FR, G1El, G2El], idx frontend.Variable, nbPublics []uint64,
+	proof Proof[FR, G1El, G2El], witness Witness[FR]) (challenges[FR], frontend.Variable, error) {
+	var ch challenges[FR]
+	nbPublicEls := make([]frontend.Variable, len(nbPublics))
+	for i := range nbPublics {
+		if nbPublics[i] == 0 || nbPublics[i] > uint64(len(witness.Public)) {
+			return ch, nil, fmt.Errorf("witness has %d public inputs, circuit %d expects %d", len(witness.Public), i, nbPublics[i])
+		}
+		nbPublicEls[i] = nbPublics[i]
+	}
+
+	// the padding inputs must be zero for the selected circuit.
+	zero := v.scalarApi.Zero()
+	isPadding := make([]frontend.Variable, len(nbPublics))
+	for j := range witness.Public {
+		for i := range nbPublics {
+			isPadding[i] = 0
+			if uint64(j) >= nbPublics[i] {
+				isPadding[i] = 1
+			}
+		}
+		padding := selector.Mux(v.api, idx, isPadding...)
+		if c, ok := v.api.Compiler().ConstantValue(padding); ok && c.Sign() == 0 {
+			continue
+		}
+		v.scalarApi.AssertIsEqual(v.scalarApi.Select(padding, zero, &witness.Public[j]), &witness.Public[j])
+	}
+
+	// the transcript binds only the public inputs of the circuit, we compute
+	// the challenges once per distinct number of inputs.
+	candidates := make(map[uint64]challenges[FR])
+	gammas := make([]*emulated.Element[FR], len(nbPublics))
+	betas := make([]*emulated.Element[FR], len(nbPublics))
+	alphas := make([]*emulated.Element[FR], len(nbPublics))
+	zetas := make([]*emulated.Element[FR], len(nbPublics))
+	for i, n := range nbPublics {
+		c, ok := candidates[n]
+		if !ok {
+			var err error
+			c, err = v.deriveChallenges(vk, proof, Witness[FR]{Public: witness.Public[:n]})
+			if err != nil {
+				return ch, nil, err
+			}
+			candidates[n] = c
+		}
+		gammas[i], betas[i], alphas[i], zetas[i] = c.gamma, c.beta, c.alpha, c.zeta
+	}
+	ch = challenges[FR]{
+		gamma: v.scalarApi.Mux(idx, gammas...),
+		beta:  v.scalarApi.Mux(idx, betas...),
+		alpha: v.scalarApi.Mux(idx, alphas...),
+		zeta:  v.scalarApi.Mux(idx, zetas...),
+	}
+	return ch, selector.Mux(v.api, idx, nbPublicEls...), nil
+}
+
 func (v *Verifier[FR, G1El, G2El, GtEl]) bindPublicData(fs *fiatshamir.Transcript, challenge string, vk VerifyingKey[FR, G1El, G2El], witness Witness[FR]) error {
 
 	// permutation
@@ -20,6 +20,7 @@ import (
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
 	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/recursion"
 	"github.com/consensys/gnark/test"
@@ -801,3 +802,167 @@ func TestAggregationDiff(t *testing.T) {
 	err = test.IsSolved(aggCircuit, aggAssignment, ecc.BW6_761.ScalarField())
 	assert.NoError(err)
 }
+
+// InnerCircuitTwoPublic has one more public input than InnerCircuitParametric.
+type InnerCircuitTwoPublic struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuitTwoPublic) Define(api frontend.API) error {
+	res := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(res, c.Y)
+	api.AssertIsEqual(api.Add(c.X, c.Y), c.Z)
+
+	commitment, err := api.(frontend.Committer).Commit(c.X, res)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commitment, res)
+	return nil
+}
+
+func TestBLS12InBW6Heterogeneous(t *testing.T) {
+	innerField := ecc.BLS12_377.ScalarField()
+	outerField := ecc.BW6_761.ScalarField()
+	assert := test.NewAssert(t)
+
+	ccsOne, err := frontend.Compile(innerField, scs.NewBuilder, &InnerCircuitParametric{parameter: 8})
+	assert.NoError(err)
+	ccsTwo, err := frontend.Compile(innerField, scs.NewBuilder, &InnerCircuitTwoPublic{})
+	assert.NoError(err)
+	ccss := []constraint.ConstraintSystem{ccsOne, ccsTwo}
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccsOne)
+	assert.NoError(err)
+	srsT, ok := srs.(*kzg_bls12377.SRS)
+	assert.True(ok)
+	srsLagrangeT, ok := srsLagrange.(*kzg_bls12377.SRS)
+	assert.True(ok)
+	vks := make([]native_plonk.VerifyingKey, len(ccss))
+	pks := make([]native_plonk.ProvingKey, len(ccss))
+	for i := range ccss {
+		sizeSystem := ccss[i].GetNbPublicVariables() + ccss[i].GetNbConstraints()
+		nextPowerTwo := 1 << stdbits.Len(uint(sizeSystem))
+		srsLagrangeT.Pk.G1, err = kzg_bls12377.ToLagrangeG1(srsT.Pk.G1[:nextPowerTwo])
+		assert.NoError(err)
+		pks[i], vks[i], err = native_plonk.Setup(ccss[i], srsT, srsLagrangeT)
+		assert.NoError(err)
+	}
+
+	// proofs for the circuits 0, 1, 1, 0
+	selectors := []int{0, 1, 1, 0}
+	nbProofs := len(selectors)
+	circuitSelector := make([]frontend.Variable, nbProofs)
+	circuitProofs := make([]Proof[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine], nbProofs)
+	circuitWitnesses := make([]Witness[sw_bls12377.ScalarField], nbProofs)
+	for i, sel := range selectors {
+		x := big.NewInt(int64(i + 2))
+		var assignment frontend.Circuit
+		if sel == 0 {
+			y := new(big.Int).Set(x)
+			for j := 0; j < 8; j++ {
+				y.Mul(y, y)
+				y.Mod(y, innerField)
+			}
+			assignment = &InnerCircuitParametric{X: x, Y: y}
+		} else {
+			y := new(big.Int).Exp(x, big.NewInt(3), innerField)
+			assignment = &InnerCircuitTwoPublic{X: x, Y: y, Z: new(big.Int).Add(x, y)}
+		}
+		innerWitness, err := frontend.NewWitness(assignment, innerField)
+		assert.NoError(err)
+		innerProof, err := native_plonk.Prove(ccss[sel], pks[sel], innerWitness, GetNativeProverOptions(outerField, innerField))
+		assert.NoError(err)
+		circuitSelector[i] = sel
+		circuitProofs[i], err = ValueOfProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerProof)
+		assert.NoError(err)
+		circuitWitnesses[i], err = ValueOfPaddedWitness[sw_bls12377.ScalarField](innerWitness, 2)
+		assert.NoError(err)
+	}
+	circuitBvk, err := ValueOfBaseVerifyingKey[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine](vks[0])
+	assert.NoError(err)
+	circuitVks := make([]CircuitVerifyingKey[sw_bls12377.ScalarField, sw_bls12377.G1Affine], len(ccss))
+	for i := range circuitVks {
+		circuitVks[i], err = ValueOfCircuitVerifyingKey[sw_bls12377.ScalarField, sw_bls12377.G1Affine](vks[i])
+		assert.NoError(err)
+	}
+
+	aggCircuit := &AggregationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		BaseKey:     circuitBvk,
+		CircuitKeys: make([]CircuitVerifyingKey[sw_bls12377.ScalarField, sw_bls12377.G1Affine], len(ccss)),
+		Selectors:   make([]frontend.Variable, nbProofs),
+		Proofs:      make([]Proof[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine], nbProofs),
+		Witnesses:   make([]Witness[sw_bls12377.ScalarField], nbProofs),
+	}
+	for i := range ccss {
+		aggCircuit.CircuitKeys[i] = PlaceholderCircuitVerifyingKey[sw_bls12377.ScalarField, sw_bls12377.G1Affine](ccss[i])
+	}
+	for i := 0; i < nbProofs; i++ {
+		aggCircuit.Proofs[i] = PlaceholderProof[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine](ccsTwo)
+		aggCircuit.Witnesses[i] = PlaceholderWitness[sw_bls12377.ScalarField](ccsTwo)
+	}
+	aggAssignment := &AggregationCircuit[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		CircuitKeys: circuitVks,
+		Selectors:   circuitSelector,
+		Proofs:      circuitProofs,
+		Witnesses:   circuitWitnesses,
+	}
+	err = test.IsSolved(aggCircuit, aggAssignment, outerField)
+	assert.NoError(err)
+
+	// the padding input of a proof for the first circuit must be zero
+	aggAssignment.Witnesses[0].Public[1] = emulated.ValueOf[sw_bls12377.ScalarField](1)
+	err = test.IsSolved(aggCircuit, aggAssignment, outerField)
+	assert.Error(err)
+}
+
+func TestBLS24InBW6WoCommit(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInnerWoCommit(assert, ecc.BLS24_315.ScalarField(), ecc.BW6_633.ScalarField())
+
+	// outer proof
+	circuitVk, err := ValueOfVerifyingKey[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[sw_bls24315.ScalarField](innerWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: PlaceholderWitness[sw_bls24315.ScalarField](innerCcs),
+		Proof:        PlaceholderProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerCcs),
+		VerifyingKey: circuitVk,
+	}
+	outerAssignment := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_633.ScalarField())
+	assert.NoError(err)
+}
+
+func TestBLS24InBW6Commit(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInnerCommit(assert, ecc.BLS24_315.ScalarField(), ecc.BW6_633.ScalarField())
+
+	// outer proof
+	circuitVk, err := ValueOfVerifyingKey[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[sw_bls24315.ScalarField](innerWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: PlaceholderWitness[sw_bls24315.ScalarField](innerCcs),
+		Proof:        PlaceholderProof[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine](innerCcs),
+		VerifyingKey: circuitVk,
+	}
+	outerAssignment := &OuterCircuit[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_633.ScalarField())
+	assert.NoError(err)
+}
@@ -0,0 +1,113 @@
+package wrap
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/commitments/fri/smallfield"
+	"github.com/consensys/gnark/std/commitments/ipa"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// FRICircuit is the outer circuit of the proofs of proximity of FRI over the
+// small field FR, see package [smallfield]. The Merkle trees and the transcript
+// are hashed with MiMC over BN254. The public input is the commitment to the
+// tested function, the first Merkle root of the proof.
+//
+// [smallfield]: https://pkg.go.dev/github.com/consensys/gnark/std/commitments/fri/smallfield
+type FRICircuit[FR emulated.FieldParams] struct {
+	Params smallfield.Params `gnark:"-"`
+	Root   frontend.Variable `gnark:",public"`
+	Proof  smallfield.Proof
+}
+
+// Define verifies the proof of proximity.
+func (c *FRICircuit[FR]) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	v, err := smallfield.NewVerifier[FR](api, c.Params, &h)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	if len(c.Proof.Roots) == 0 {
+		return errors.New("empty proof")
+	}
+	api.AssertIsEqual(c.Root, c.Proof.Roots[0])
+	return v.VerifyProofOfProximity(c.Proof)
+}
+
+// NewFRICircuit returns the outer circuit of the proofs with the given
+// parameters, to give to [Setup].
+func NewFRICircuit[FR emulated.FieldParams](params smallfield.Params) *FRICircuit[FR] {
+	return &FRICircuit[FR]{Params: params, Proof: smallfield.PlaceholderProof(params)}
+}
+
+// FRIAssignment returns the assignment of the outer circuit for the proof, to
+// give to [Wrapper.Wrap]. The proof is verified out of circuit first, so that
+// an invalid proof is reported before solving the circuit.
+func FRIAssignment[FR emulated.FieldParams](params smallfield.Params, proof smallfield.NativeProof) (*FRICircuit[FR], error) {
+	if err := smallfield.Verify[FR](params, hash.MIMC_BN254.New(), proof); err != nil {
+		return nil, fmt.Errorf("verify inner proof: %w", err)
+	}
+	return &FRICircuit[FR]{Root: proof.Roots[0], Proof: smallfield.ValueOfProof(proof)}, nil
+}
+
+// IPACircuit is the outer circuit of the openings of the inner product argument
+// commitments over the curve of base field B and scalar field S, such as the
+// Pasta curves, see package [ipa]. The transcript is hashed with MiMC over
+// BN254. The public inputs are the commitment, the point and the value of the
+// opening.
+//
+// [ipa]: https://pkg.go.dev/github.com/consensys/gnark/std/commitments/ipa
+type IPACircuit[B, S emulated.FieldParams] struct {
+	Curve        sw_emulated.CurveParams    `gnark:"-"`
+	VerifyingKey ipa.VerifyingKey[B]        `gnark:"-"`
+	Commitment   sw_emulated.AffinePoint[B] `gnark:",public"`
+	Point, Value emulated.Element[S]        `gnark:",public"`
+	Proof        ipa.Proof[B, S]
+}
+
+// Define verifies the opening.
+func (c *IPACircuit[B, S]) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	v, err := ipa.NewVerifier[B, S](api, c.Curve, &h)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return v.VerifyOpening(c.VerifyingKey, c.Commitment, c.Point, c.Value, c.Proof)
+}
+
+// NewIPACircuit returns the outer circuit of the openings with the verifying
+// key, to give to [Setup].
+func NewIPACircuit[B, S emulated.FieldParams](vk ipa.NativeVerifyingKey) *IPACircuit[B, S] {
+	return &IPACircuit[B, S]{
+		Curve:        vk.Curve,
+		VerifyingKey: ipa.ValueOfVerifyingKey[B](vk),
+		Proof:        ipa.PlaceholderProof[B, S](len(vk.G)),
+	}
+}
+
+// IPAAssignment returns the assignment of the outer circuit for the opening,
+// to give to [Wrapper.Wrap]. The proof is verified out of circuit first, so
+// that an invalid proof is reported before solving the circuit.
+func IPAAssignment[B, S emulated.FieldParams](vk ipa.NativeVerifyingKey, commitment ipa.NativePoint, point, value *big.Int, proof ipa.NativeProof) (*IPACircuit[B, S], error) {
+	if err := ipa.Verify[B, S](vk, hash.MIMC_BN254.New(), commitment, point, value, proof); err != nil {
+		return nil, fmt.Errorf("verify inner proof: %w", err)
+	}
+	return &IPACircuit[B, S]{
+		Commitment: ipa.ValueOfPoint[B](commitment),
+		Point:      emulated.ValueOf[S](point),
+		Value:      emulated.ValueOf[S](value),
+		Proof:      ipa.ValueOfProof[B, S](proof),
+	}, nil
+}
@@ -0,0 +1,43 @@
+// Package wrap implements the pipeline wrapping proofs of other proof systems
+// into BN254 Groth16 proofs, which are cheap to verify on Ethereum.
+//
+// The proof to wrap is verified by a gnark circuit over the BN254 scalar
+// field, the outer circuit, which is built from the in-circuit verifiers of the
+// standard library: the FRI proximity tests of [fri] over the native field and
+// of [smallfield] over small fields such as Goldilocks and BabyBear for
+// hash-based systems, the openings of the inner product argument of [ipa] over
+// the Pasta curves for Halo2-style systems, the verifiers of [recursion] for
+// PLONK and Groth16 proofs over other curves, or any other verifier gadget. The
+// public inputs of the outer circuit are the statement of the inner proof, and
+// the circuit is responsible for binding the inner proof to them, typically by
+// asserting that the commitments or the public inputs of the inner proof are
+// equal to its public variables.
+//
+// [FRICircuit] and [IPACircuit] are the outer circuits of the small-field FRI
+// and of the IPA openings, whose statements are the committed roots and the
+// opened evaluations. [NewFRICircuit] and [NewIPACircuit] return the circuits
+// to compile, and [FRIAssignment] and [IPAAssignment] convert the proofs
+// computed out of circuit, checking them first. The proofs computed by other
+// implementations are read with the encoding of the gadget packages, for
+// example [smallfield.NativeProof.ReadFrom], and must use their transcript.
+//
+// The pipeline then runs in three steps:
+//
+//   - [Setup] compiles the outer circuit and generates the Groth16 keys, or
+//     [New] loads keys generated by a ceremony;
+//   - [Wrapper.Wrap] solves the outer circuit for the assignment of an inner
+//     proof, proves it and verifies the result;
+//   - [Wrapper.ExportSolidity] exports the verifier contract, which takes the
+//     encoding of [Proof.Calldata].
+//
+// The Fiat-Shamir challenges of the inner proof are recomputed in the outer
+// circuit, with the in-circuit version of the hash function of the inner
+// system, so that the transcript of the inner proof doesn't have to be part of
+// the witness. The commitments of the outer circuit, if any, are hashed with the
+// hash-to-field function expected by the Solidity verifier.
+//
+// [fri]: https://pkg.go.dev/github.com/consensys/gnark/std/commitments/fri
+// [smallfield]: https://pkg.go.dev/github.com/consensys/gnark/std/commitments/fri/smallfield
+// [ipa]: https://pkg.go.dev/github.com/consensys/gnark/std/commitments/ipa
+// [recursion]: https://pkg.go.dev/github.com/consensys/gnark/std/recursion
+package wrap
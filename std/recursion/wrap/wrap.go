@@ -0,0 +1,167 @@
+package wrap
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// Option defines option for altering the behavior of the wrapper. See the
+// descriptions of functions returning instances of this type for implemented
+// options.
+type Option func(*Config) error
+
+// Config is the configuration of the wrapper with the options applied.
+type Config struct {
+	CompileOptions []frontend.CompileOption
+	ProverOptions  []backend.ProverOption
+}
+
+// WithCompileOptions sets the options for compiling the outer circuit in
+// [Setup].
+func WithCompileOptions(opts ...frontend.CompileOption) Option {
+	return func(cfg *Config) error {
+		cfg.CompileOptions = opts
+		return nil
+	}
+}
+
+// WithProverOptions sets the options of the Groth16 prover. The options
+// changing the hash-to-field function make the proofs incompatible with the
+// Solidity verifier.
+func WithProverOptions(opts ...backend.ProverOption) Option {
+	return func(cfg *Config) error {
+		cfg.ProverOptions = opts
+		return nil
+	}
+}
+
+// Wrapper proves the verification of inner proofs with Groth16 over BN254.
+type Wrapper struct {
+	cfg Config
+	ccs constraint.ConstraintSystem
+	pk  groth16.ProvingKey
+	vk  groth16.VerifyingKey
+}
+
+// Setup compiles the outer circuit, which verifies the inner proof, over the
+// BN254 scalar field and runs the Groth16 setup. The setup uses local
+// randomness, the keys used in production should come from a ceremony and be
+// given to [New] instead.
+func Setup(circuit frontend.Circuit, opts ...Option) (*Wrapper, error) {
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit, cfg.CompileOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("compile outer circuit: %w", err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, fmt.Errorf("setup: %w", err)
+	}
+	return &Wrapper{cfg: cfg, ccs: ccs, pk: pk, vk: vk}, nil
+}
+
+// New returns a wrapper with the compiled outer circuit and its Groth16 keys.
+// It returns an error if they are not over BN254.
+func New(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey, opts ...Option) (*Wrapper, error) {
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if ccs.Field().Cmp(ecc.BN254.ScalarField()) != 0 {
+		return nil, fmt.Errorf("outer circuit is not over BN254")
+	}
+	if pk.CurveID() != ecc.BN254 || vk.CurveID() != ecc.BN254 {
+		return nil, fmt.Errorf("keys are over %s and %s, expected BN254", pk.CurveID(), vk.CurveID())
+	}
+	return &Wrapper{cfg: cfg, ccs: ccs, pk: pk, vk: vk}, nil
+}
+
+func newConfig(opts ...Option) (Config, error) {
+	var cfg Config
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// ConstraintSystem returns the compiled outer circuit.
+func (w *Wrapper) ConstraintSystem() constraint.ConstraintSystem {
+	return w.ccs
+}
+
+// ProvingKey returns the Groth16 proving key of the outer circuit.
+func (w *Wrapper) ProvingKey() groth16.ProvingKey {
+	return w.pk
+}
+
+// VerifyingKey returns the Groth16 verifying key of the outer circuit.
+func (w *Wrapper) VerifyingKey() groth16.VerifyingKey {
+	return w.vk
+}
+
+// Proof is a wrapped proof, with the public witness of the outer circuit.
+type Proof struct {
+	Proof         groth16.Proof
+	PublicWitness witness.Witness
+}
+
+// Wrap proves that the inner proof in the assignment of the outer circuit is
+// valid. The wrapped proof is verified before being returned, so that an error
+// in the keys is detected before the proof is sent.
+func (w *Wrapper) Wrap(assignment frontend.Circuit) (*Proof, error) {
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("new witness: %w", err)
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return nil, fmt.Errorf("public witness: %w", err)
+	}
+	proof, err := groth16.Prove(w.ccs, w.pk, fullWitness, w.cfg.ProverOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("prove: %w", err)
+	}
+	if err := groth16.Verify(proof, w.vk, publicWitness); err != nil {
+		return nil, fmt.Errorf("verify wrapped proof: %w", err)
+	}
+	return &Proof{Proof: proof, PublicWitness: publicWitness}, nil
+}
+
+// ExportSolidity writes the Solidity verifier of the wrapped proofs.
+func (w *Wrapper) ExportSolidity(writer io.Writer) error {
+	return w.vk.ExportSolidity(writer)
+}
+
+// Calldata returns the arguments of the Solidity verifier: the encoded proof
+// and the public inputs.
+func (p *Proof) Calldata() (proof []byte, publicInputs []*big.Int, err error) {
+	tProof, ok := p.Proof.(*groth16_bn254.Proof)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected *groth16_bn254.Proof, got %T", p.Proof)
+	}
+	vector, ok := p.PublicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected fr_bn254.Vector, got %T", p.PublicWitness.Vector())
+	}
+	publicInputs = make([]*big.Int, len(vector))
+	for i := range vector {
+		publicInputs[i] = vector[i].BigInt(new(big.Int))
+	}
+	return tProof.MarshalSolidity(), publicInputs, nil
+}
@@ -0,0 +1,150 @@
+package wrap
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fri_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/fri"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/commitments/fri"
+	"github.com/consensys/gnark/std/commitments/fri/smallfield"
+	"github.com/consensys/gnark/std/commitments/ipa"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+const friSize = 32
+
+// friCircuit verifies a FRI proof of proximity to the polynomial committed in
+// Root.
+type friCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Proof fri.ProofOfProximity
+}
+
+func (c *friCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	// inverse of the generator of the domain of size 8·friSize
+	var gInv big.Int
+	gInv.SetString("14607982016670611764231825270871087984049314771307170893064215224383340934614", 10)
+	api.AssertIsEqual(c.Root, c.Proof.Rounds[0].Interactions[0][0].RootHash)
+	return fri.NewRadixTwoFri(friSize, &h, gInv).VerifyProofOfProximity(api, c.Proof)
+}
+
+func newFRIProof(t *testing.T) fri_bn254.ProofOfProximity {
+	polynomial := make([]fr.Element, friSize)
+	for i := range polynomial {
+		polynomial[i].SetRandom()
+	}
+	scheme := fri_bn254.RADIX_2_FRI.New(friSize, hash.MIMC_BN254.New())
+	proof, err := scheme.BuildProofOfProximity(polynomial)
+	require.NoError(t, err)
+	return proof
+}
+
+// newAssignment returns a new assignment of the circuit, as compiling a
+// circuit modifies its slices.
+func newAssignment(t *testing.T, root frontend.Variable, proof fri_bn254.ProofOfProximity) *friCircuit {
+	assignment, err := fri.ValueOfProofOfProximity(proof)
+	require.NoError(t, err)
+	return &friCircuit{Root: root, Proof: assignment}
+}
+
+func TestWrapFRI(t *testing.T) {
+	assert := require.New(t)
+	if testing.Short() {
+		t.Skip("skipping Groth16 setup of the FRI verifier in short mode")
+	}
+	proof := newFRIProof(t)
+	root := proof.Rounds[0].Interactions[0][0].MerkleRoot
+
+	w, err := Setup(newAssignment(t, 0, proof))
+	assert.NoError(err)
+	wrapped, err := w.Wrap(newAssignment(t, root, proof))
+	assert.NoError(err)
+
+	calldata, publicInputs, err := wrapped.Calldata()
+	assert.NoError(err)
+	assert.Len(calldata, 8*fr.Bytes)
+	assert.Equal([]*big.Int{new(big.Int).SetBytes(root)}, publicInputs)
+
+	// the proof is bound to the root
+	_, err = w.Wrap(newAssignment(t, 1, proof))
+	assert.Error(err)
+
+	// the keys can be reloaded
+	w2, err := New(w.ConstraintSystem(), w.ProvingKey(), w.VerifyingKey())
+	assert.NoError(err)
+	_, err = w2.Wrap(newAssignment(t, root, proof))
+	assert.NoError(err)
+}
+
+func randomInts(t *testing.T, modulus *big.Int, n int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := range res {
+		var err error
+		res[i], err = rand.Int(rand.Reader, modulus)
+		require.NoError(t, err)
+	}
+	return res
+}
+
+func TestWrapSmallFieldFRI(t *testing.T) {
+	assert := require.New(t)
+	if testing.Short() {
+		t.Skip("skipping Groth16 setup of the small-field FRI verifier in short mode")
+	}
+	params := smallfield.GoldilocksParams(3, 2, 2)
+	coefficients := randomInts(t, emparams.Goldilocks{}.Modulus(), 1<<params.LogDegree)
+	proof, err := smallfield.Prove[emparams.Goldilocks](params, hash.MIMC_BN254.New(), coefficients)
+	assert.NoError(err)
+
+	w, err := Setup(NewFRICircuit[emparams.Goldilocks](params))
+	assert.NoError(err)
+	assignment, err := FRIAssignment[emparams.Goldilocks](params, proof)
+	assert.NoError(err)
+	wrapped, err := w.Wrap(assignment)
+	assert.NoError(err)
+	_, publicInputs, err := wrapped.Calldata()
+	assert.NoError(err)
+	assert.Equal([]*big.Int{proof.Roots[0]}, publicInputs)
+
+	// the inner proof is checked before wrapping
+	proof.Roots[0] = new(big.Int).Add(proof.Roots[0], big.NewInt(1))
+	_, err = FRIAssignment[emparams.Goldilocks](params, proof)
+	assert.Error(err)
+}
+
+func TestWrapIPA(t *testing.T) {
+	assert := require.New(t)
+	const size = 4
+	fp := emparams.PastaFp{}.Modulus()
+	vk, err := ipa.GenerateKey[emparams.PastaFq, emparams.PastaFp](sw_emulated.GetVestaParams(), size, []byte("wrap"))
+	assert.NoError(err)
+	coefficients := randomInts(t, fp, size)
+	r := randomInts(t, fp, 2)
+	commitment, err := ipa.Commit[emparams.PastaFq, emparams.PastaFp](vk, coefficients, r[0])
+	assert.NoError(err)
+	proof, value, err := ipa.Open[emparams.PastaFq, emparams.PastaFp](vk, hash.MIMC_BN254.New(), coefficients, r[0], r[1])
+	assert.NoError(err)
+
+	// the Groth16 setup of the outer circuit is too large for the tests, which
+	// only solve it.
+	assignment, err := IPAAssignment[emparams.PastaFq, emparams.PastaFp](vk, commitment, r[1], value, proof)
+	assert.NoError(err)
+	assert.NoError(test.IsSolved(NewIPACircuit[emparams.PastaFq, emparams.PastaFp](vk), assignment, ecc.BN254.ScalarField()))
+
+	// the inner proof is checked before wrapping
+	_, err = IPAAssignment[emparams.PastaFq, emparams.PastaFp](vk, commitment, r[1], new(big.Int).Sub(fp, value), proof)
+	assert.Error(err)
+}
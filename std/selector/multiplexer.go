@@ -1,21 +1,21 @@
-// Package selector provides a lookup table and map, based on linear scan.
+// Package selector provides a lookup table and map, and variable shifts and
+// rotations of slices.
 //
 // The native [frontend.API] provides 1- and 2-bit lookups through the interface
 // methods Select and Lookup2. This package extends the lookups to
 // arbitrary-sized vectors. The lookups can be performed using the index of the
-// elements (function [Mux]) or using a key, for which the user needs to provide
-// the slice of keys (function [Map]).
+// elements (functions [Mux] and [BatchMux]) or using a key, for which the user
+// needs to provide the slice of keys (function [Map]).
 //
-// The implementation uses linear scan over all inputs.
+// The lookups by index use binary mux trees driven by the bits of the index.
+// The lookups by key use linear scan over all inputs.
 package selector
 
 import (
 	"fmt"
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/math/bits"
 	"math/big"
-	binary "math/bits"
 )
 
 func init() {
@@ -50,15 +50,16 @@ func Map(api frontend.API, queryKey frontend.Variable,
 // Mux is an n to 1 multiplexer: out = inputs[sel]. In other words, it selects
 // exactly one of its inputs based on sel. The index of inputs starts from zero.
 //
+// The selector is decomposed into bits which drive a binary mux tree, see
+// [BinaryMux], so that the multiplexer costs about n + 2·log(n) constraints.
+//
 // sel needs to be between 0 and n - 1 (inclusive), where n is the number of
 // inputs, otherwise the proof will fail.
 func Mux(api frontend.API, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
-	// we use BinaryMux when len(inputs) is a power of 2.
-	if binary.OnesCount(uint(len(inputs))) == 1 {
-		selBits := bits.ToBinary(api, sel, bits.WithNbDigits(binary.Len(uint(len(inputs)))-1))
-		return BinaryMux(api, selBits, inputs)
+	if len(inputs) == 0 {
+		panic("no input for Mux")
 	}
-	return dotProduct(api, inputs, Decoder(api, len(inputs), sel))
+	return binaryMuxRecursive(api, selectorBits(api, sel, len(inputs)), inputs)
 }
 
 // KeyDecoder is a decoder that associates keys to its output wires. It outputs
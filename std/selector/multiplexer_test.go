@@ -230,3 +230,34 @@ func TestMap(t *testing.T) {
 		})
 
 }
+
+type batchMuxCircuit struct {
+	SEL frontend.Variable
+	In  [5][3]frontend.Variable
+	OUT [3]frontend.Variable
+}
+
+func (c *batchMuxCircuit) Define(api frontend.API) error {
+	inputs := make([][]frontend.Variable, len(c.In))
+	for i := range c.In {
+		inputs[i] = c.In[i][:]
+	}
+	out := selector.BatchMux(api, c.SEL, inputs...)
+	for j := range c.OUT {
+		api.AssertIsEqual(out[j], c.OUT[j])
+	}
+	return nil
+}
+
+func TestBatchMux(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := [5][3]frontend.Variable{{10, 20, 30}, {11, 21, 31}, {12, 22, 32}, {13, 23, 33}, {14, 24, 34}}
+	assert.CheckCircuit(&batchMuxCircuit{},
+		test.WithValidAssignment(&batchMuxCircuit{SEL: 0, In: in, OUT: in[0]}),
+		test.WithValidAssignment(&batchMuxCircuit{SEL: 3, In: in, OUT: in[3]}),
+		test.WithValidAssignment(&batchMuxCircuit{SEL: 4, In: in, OUT: in[4]}),
+		test.WithInvalidAssignment(&batchMuxCircuit{SEL: 5, In: in, OUT: in[4]}),
+		test.WithInvalidAssignment(&batchMuxCircuit{SEL: 7, In: in, OUT: in[4]}),
+		test.WithInvalidAssignment(&batchMuxCircuit{SEL: 1, In: in, OUT: in[2]}),
+	)
+}
@@ -2,7 +2,10 @@ package selector
 
 import (
 	"fmt"
+	binary "math/bits"
+
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
 )
 
 // BinaryMux is a 2^k to 1 multiplexer which uses a binary selector. selBits are
@@ -43,3 +46,49 @@ func binaryMuxRecursive(api frontend.API, selBits, inputs []frontend.Variable) f
 	right := binaryMuxRecursive(api, nextSelBits, inputs[pivot:])
 	return api.Add(left, api.Mul(msb, api.Sub(right, left)))
 }
+
+// BatchMux is an n to 1 multiplexer of vectors: out[j] = inputs[sel][j]. The
+// selector is decomposed only once and shared by the mux trees of all the
+// outputs, so that selecting a vector of m elements costs about m·(n-1)
+// constraints, instead of m calls to [Mux].
+//
+// All the inputs must have the same length. sel needs to be between 0 and n - 1
+// (inclusive), where n is the number of inputs, otherwise the proof will fail.
+func BatchMux(api frontend.API, sel frontend.Variable, inputs ...[]frontend.Variable) []frontend.Variable {
+	if len(inputs) == 0 {
+		panic("no input for BatchMux")
+	}
+	for i := range inputs {
+		if len(inputs[i]) != len(inputs[0]) {
+			panic(fmt.Sprintf("inputs of different lengths for BatchMux (%d != %d)", len(inputs[i]), len(inputs[0])))
+		}
+	}
+	selBits := selectorBits(api, sel, len(inputs))
+	out := make([]frontend.Variable, len(inputs[0]))
+	column := make([]frontend.Variable, len(inputs))
+	for j := range out {
+		for i := range inputs {
+			column[i] = inputs[i][j]
+		}
+		out[j] = binaryMuxRecursive(api, selBits, column)
+	}
+	return out
+}
+
+// selectorBits returns the bits of sel, the index of one of n inputs, and
+// asserts that sel < n.
+func selectorBits(api frontend.API, sel frontend.Variable, n int) []frontend.Variable {
+	nbBits := binary.Len(uint(n - 1))
+	if nbBits == 0 {
+		api.AssertIsEqual(sel, 0)
+		return nil
+	}
+	selBits := bits.ToBinary(api, sel, bits.WithNbDigits(nbBits))
+	if n != 1<<nbBits {
+		// the mux tree returns one of the last inputs for the indices out of
+		// range, we need to exclude them. As sel < 2^nbBits, n - 1 - sel is
+		// negative (and larger than 2^nbBits in the field) iff sel >= n.
+		bits.ToBinary(api, api.Sub(n-1, sel), bits.WithNbDigits(nbBits))
+	}
+	return selBits
+}
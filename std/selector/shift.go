@@ -0,0 +1,92 @@
+package selector
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// ShiftLeft shifts the input left by a variable number of positions and fills
+// the vacated positions with zeros. More precisely, for each i we have:
+//
+//	if i + shift < len(input)
+//	    out[i] = input[i+shift]
+//	else
+//	    out[i] = 0
+//
+// shift needs to be between 0 and len(input) (inclusive), otherwise a proof
+// cannot be generated.
+func ShiftLeft(api frontend.API, shift frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	return barrelShift(api, shift, input, false, false)
+}
+
+// ShiftRight shifts the input right by a variable number of positions and
+// fills the vacated positions with zeros. More precisely, for each i we have:
+//
+//	if i >= shift
+//	    out[i] = input[i-shift]
+//	else
+//	    out[i] = 0
+//
+// shift needs to be between 0 and len(input) (inclusive), otherwise a proof
+// cannot be generated.
+func ShiftRight(api frontend.API, shift frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	return barrelShift(api, shift, input, true, false)
+}
+
+// RotateLeft rotates the input left by a variable number of positions, that is
+// out[i] = input[(i+shift) mod len(input)].
+//
+// shift needs to be between 0 and len(input) - 1 (inclusive), otherwise a
+// proof cannot be generated.
+func RotateLeft(api frontend.API, shift frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	return barrelShift(api, shift, input, false, true)
+}
+
+// RotateRight rotates the input right by a variable number of positions, that
+// is out[i] = input[(i-shift) mod len(input)].
+//
+// shift needs to be between 0 and len(input) - 1 (inclusive), otherwise a
+// proof cannot be generated.
+func RotateRight(api frontend.API, shift frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	return barrelShift(api, shift, input, true, true)
+}
+
+// barrelShift shifts or rotates the input by shift positions in log(n) layers,
+// where the k-th layer conditionally shifts by 2^k depending on the k-th bit of
+// shift. It costs about n·log(n) constraints, where n is the length of the
+// input.
+func barrelShift(api frontend.API, shift frontend.Variable, input []frontend.Variable, right, rotate bool) []frontend.Variable {
+	n := len(input)
+	if n == 0 {
+		return nil
+	}
+	var shiftBits []frontend.Variable
+	if rotate {
+		shiftBits = selectorBits(api, shift, n)
+	} else {
+		shiftBits = selectorBits(api, shift, n+1)
+	}
+
+	cur := make([]frontend.Variable, n)
+	copy(cur, input)
+	next := make([]frontend.Variable, n)
+	for k, b := range shiftBits {
+		offset := 1 << k
+		for i := range next {
+			src := i + offset
+			if right {
+				src = i - offset
+			}
+			var v frontend.Variable = 0
+			switch {
+			case rotate:
+				v = cur[((src%n)+n)%n]
+			case src >= 0 && src < n:
+				v = cur[src]
+			}
+			// next[i] = b ? v : cur[i]
+			next[i] = api.Add(cur[i], api.Mul(b, api.Sub(v, cur[i])))
+		}
+		cur, next = next, cur
+	}
+	return cur
+}
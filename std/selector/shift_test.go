@@ -0,0 +1,67 @@
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/test"
+)
+
+type shiftCircuit struct {
+	Shift       frontend.Variable
+	In          [5]frontend.Variable
+	Left, Right [5]frontend.Variable
+	rotate      bool
+}
+
+func (c *shiftCircuit) Define(api frontend.API) error {
+	var left, right []frontend.Variable
+	if c.rotate {
+		left = selector.RotateLeft(api, c.Shift, c.In[:])
+		right = selector.RotateRight(api, c.Shift, c.In[:])
+	} else {
+		left = selector.ShiftLeft(api, c.Shift, c.In[:])
+		right = selector.ShiftRight(api, c.Shift, c.In[:])
+	}
+	for i := range c.In {
+		api.AssertIsEqual(left[i], c.Left[i])
+		api.AssertIsEqual(right[i], c.Right[i])
+	}
+	return nil
+}
+
+func TestShift(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := [5]frontend.Variable{10, 11, 12, 13, 14}
+	assert.CheckCircuit(&shiftCircuit{},
+		test.WithValidAssignment(&shiftCircuit{Shift: 0, In: in, Left: in, Right: in}),
+		test.WithValidAssignment(&shiftCircuit{Shift: 2, In: in,
+			Left:  [5]frontend.Variable{12, 13, 14, 0, 0},
+			Right: [5]frontend.Variable{0, 0, 10, 11, 12}}),
+		test.WithValidAssignment(&shiftCircuit{Shift: 5, In: in,
+			Left:  [5]frontend.Variable{0, 0, 0, 0, 0},
+			Right: [5]frontend.Variable{0, 0, 0, 0, 0}}),
+		test.WithInvalidAssignment(&shiftCircuit{Shift: 6, In: in,
+			Left:  [5]frontend.Variable{0, 0, 0, 0, 0},
+			Right: [5]frontend.Variable{0, 0, 0, 0, 0}}),
+		test.WithInvalidAssignment(&shiftCircuit{Shift: 1, In: in,
+			Left:  [5]frontend.Variable{11, 12, 13, 14, 10},
+			Right: [5]frontend.Variable{0, 10, 11, 12, 13}}),
+	)
+}
+
+func TestRotate(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := [5]frontend.Variable{10, 11, 12, 13, 14}
+	assert.CheckCircuit(&shiftCircuit{rotate: true},
+		test.WithValidAssignment(&shiftCircuit{Shift: 0, In: in, Left: in, Right: in}),
+		test.WithValidAssignment(&shiftCircuit{Shift: 2, In: in,
+			Left:  [5]frontend.Variable{12, 13, 14, 10, 11},
+			Right: [5]frontend.Variable{13, 14, 10, 11, 12}}),
+		test.WithValidAssignment(&shiftCircuit{Shift: 4, In: in,
+			Left:  [5]frontend.Variable{14, 10, 11, 12, 13},
+			Right: [5]frontend.Variable{11, 12, 13, 14, 10}}),
+		test.WithInvalidAssignment(&shiftCircuit{Shift: 5, In: in, Left: in, Right: in}),
+	)
+}
@@ -0,0 +1,236 @@
+// Package substring proves that a pattern occurs, or does not occur, in a byte
+// string, for example to prove that a redacted document still contains a
+// field, or that a message doesn't contain a forbidden word.
+//
+// The string and the pattern are bounded arrays of bytes, with a variable
+// length: the bytes after the length are ignored, so that a circuit supports
+// strings and patterns up to the size of the arrays. The string is typically
+// committed to elsewhere in the circuit, for example hashed, and the gadget
+// only reads it.
+//
+// The bytes of the string are read with a lookup table, so that both
+// assertions cost O(n+m) constraints for a string of n bytes and a pattern of
+// m bytes, instead of the O(n·m) of comparing the pattern at each position:
+//
+//   - [Searcher.AssertContains] reads the window of the string at the position
+//     of an occurrence, computed by a hint, and compares it to the pattern;
+//   - [Searcher.AssertNotContains] compares the fingerprints of all the windows
+//     of the string to the fingerprint of the pattern, as polynomials evaluated
+//     at a random challenge derived from a commitment to the string and the
+//     pattern. A window equal to the pattern is found except with probability
+//     about n·(n+m)/|F|, so the field must be large, as the scalar fields of
+//     the pairing-friendly curves.
+package substring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{indexHint}
+}
+
+// Searcher searches patterns in a byte string.
+type Searcher struct {
+	api    frontend.API
+	rc     frontend.Rangechecker
+	data   []frontend.Variable
+	length frontend.Variable
+	table  *logderivlookup.Table
+	// number of zeros in the table after the data, so that the windows of
+	// the longest pattern searched can be read at every position
+	padding int
+}
+
+// New returns a searcher in the first length bytes of data, where length is at
+// most len(data). The bytes of data are range checked.
+func New(api frontend.API, data []frontend.Variable, length frontend.Variable) (*Searcher, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty data")
+	}
+	s := &Searcher{
+		api:    api,
+		rc:     rangecheck.New(api),
+		data:   data,
+		length: length,
+		table:  logderivlookup.New(api),
+	}
+	for i := range data {
+		s.rc.Check(data[i], 8)
+		s.table.Insert(data[i])
+	}
+	// 0 ≤ length ≤ len(data)
+	nbBits := bits.Len(uint(len(data)))
+	s.rc.Check(length, nbBits)
+	s.rc.Check(api.Sub(len(data), length), nbBits)
+	return s, nil
+}
+
+// AssertContains asserts that the first patternLen bytes of pattern occur in
+// the string, where patternLen is at most len(pattern), and returns the
+// position of an occurrence. The bytes of pattern after patternLen are
+// ignored.
+//
+// The position is computed by a hint as the position of the first occurrence,
+// but the circuit only asserts that the pattern occurs at the position.
+func (s *Searcher) AssertContains(pattern []frontend.Variable, patternLen frontend.Variable) (position frontend.Variable) {
+	api := s.api
+	mask := s.patternMask(pattern, patternLen)
+	inputs := []frontend.Variable{len(s.data), s.length, patternLen}
+	inputs = append(append(inputs, s.data...), pattern...)
+	res, err := api.Compiler().NewHint(indexHint, 1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	position = res[0]
+
+	// the window is in the string, and the reads of the window after the
+	// pattern are in the padding
+	nbBits := bits.Len(uint(len(s.data)))
+	s.rc.Check(position, nbBits)
+	s.rc.Check(api.Sub(s.length, api.Add(position, patternLen)), nbBits)
+	s.pad(len(pattern))
+	indices := make([]frontend.Variable, len(pattern))
+	for j := range indices {
+		indices[j] = api.Add(position, j)
+	}
+	window := s.table.Lookup(indices...)
+	for j := range window {
+		api.AssertIsEqual(api.Mul(mask[j], api.Sub(window[j], pattern[j])), 0)
+	}
+	return position
+}
+
+// AssertNotContains asserts that the first patternLen bytes of pattern don't
+// occur in the string, where patternLen is at most len(pattern). The bytes of
+// pattern after patternLen are ignored. The empty pattern occurs in every
+// string. See the package documentation for the soundness of the assertion.
+func (s *Searcher) AssertNotContains(pattern []frontend.Variable, patternLen frontend.Variable) {
+	api := s.api
+	n, m := len(s.data), len(pattern)
+	mask := s.patternMask(pattern, patternLen)
+	masked := make([]frontend.Variable, m)
+	for j := range masked {
+		masked[j] = api.Mul(mask[j], pattern[j])
+	}
+
+	// the string shifted by patternLen, and the positions of the windows in
+	// the string: i+patternLen ≤ length iff i < length+1-patternLen. The
+	// pivot is offset by m, as length+1-patternLen may be negative.
+	s.pad(m)
+	indices := make([]frontend.Variable, n)
+	for i := range indices {
+		indices[i] = api.Add(patternLen, i)
+	}
+	shifted := s.table.Lookup(indices...)
+	valid := selector.Partition(api, api.Sub(api.Add(s.length, 1, m), patternLen), false, ones(n+m+1))[m : m+n]
+
+	committed := append(append([]frontend.Variable{s.length, patternLen}, s.data...), pattern...)
+	multicommit.WithCommitment(api, func(api frontend.API, r frontend.Variable) error {
+		// with pre[k] = Σ_{j<k} data[j]⋅rʲ and preShifted[k] = Σ_{j<k}
+		// shifted[j]⋅rʲ, the fingerprint of the window at i, multiplied by
+		// rⁱ, is pre[i+patternLen] - pre[i] = r^patternLen⋅preShifted[i] +
+		// pre[patternLen] - pre[i].
+		size := max(n, m) + 1
+		pow := make([]frontend.Variable, size)
+		pre := make([]frontend.Variable, size)
+		pow[0], pre[0] = 1, 0
+		for k := 1; k < size; k++ {
+			pow[k] = api.Mul(pow[k-1], r)
+			pre[k] = pre[k-1]
+			if k <= n {
+				pre[k] = api.Add(pre[k-1], api.Mul(s.data[k-1], pow[k-1]))
+			}
+		}
+		rPatternLen := selector.Mux(api, patternLen, pow[:m+1]...)
+		prePatternLen := selector.Mux(api, patternLen, pre[:m+1]...)
+		var fingerprint frontend.Variable = 0
+		for j := range masked {
+			fingerprint = api.Add(fingerprint, api.Mul(masked[j], pow[j]))
+		}
+
+		var preShifted, prod frontend.Variable = 0, 1
+		for i := 0; i < n; i++ {
+			window := api.Sub(api.Add(api.Mul(rPatternLen, preShifted), prePatternLen), pre[i])
+			diff := api.Sub(window, api.Mul(pow[i], fingerprint))
+			prod = api.Mul(prod, api.Add(api.Mul(valid[i], api.Sub(diff, 1)), 1))
+			preShifted = api.Add(preShifted, api.Mul(shifted[i], pow[i]))
+		}
+		api.AssertIsDifferent(prod, 0)
+		return nil
+	}, committed...)
+}
+
+// patternMask returns mask[j] = 1 if j < patternLen and 0 otherwise, for the
+// bytes of pattern. It asserts that 0 ≤ patternLen ≤ len(pattern) and that the
+// bytes of the pattern are bytes.
+func (s *Searcher) patternMask(pattern []frontend.Variable, patternLen frontend.Variable) []frontend.Variable {
+	if len(pattern) == 0 {
+		panic("empty pattern")
+	}
+	for j := range pattern {
+		s.rc.Check(pattern[j], 8)
+	}
+	nbBits := bits.Len(uint(len(pattern)))
+	s.rc.Check(patternLen, nbBits)
+	s.rc.Check(s.api.Sub(len(pattern), patternLen), nbBits)
+	// the mask has at least the 2 entries of Partition
+	return selector.Partition(s.api, patternLen, false, ones(len(pattern)+1))[:len(pattern)]
+}
+
+// pad inserts zeros in the table after the data, so that m bytes can be read
+// from any position in the data.
+func (s *Searcher) pad(m int) {
+	for ; s.padding < m; s.padding++ {
+		s.table.Insert(0)
+	}
+}
+
+// indexHint returns the position of the first occurrence of the pattern. Its
+// inputs are the size of the data, the length of the string, the length of the
+// pattern, the data and the pattern.
+func indexHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	n := int(inputs[0].Int64())
+	length, patternLen := inputs[1].Int64(), inputs[2].Int64()
+	data, pattern := inputs[3:3+n], inputs[3+n:]
+	if length > int64(n) || patternLen > int64(len(pattern)) {
+		return errors.New("length out of bounds")
+	}
+	toBytes := func(values []*big.Int) []byte {
+		res := make([]byte, len(values))
+		for i := range values {
+			res[i] = byte(values[i].Uint64())
+		}
+		return res
+	}
+	i := bytes.Index(toBytes(data[:length]), toBytes(pattern[:patternLen]))
+	if i < 0 {
+		return fmt.Errorf("pattern not found")
+	}
+	outputs[0].SetInt64(int64(i))
+	return nil
+}
+
+func ones(n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = 1
+	}
+	return res
+}
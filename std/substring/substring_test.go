@@ -0,0 +1,118 @@
+package substring
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	capacity   = 48
+	maxPattern = 8
+)
+
+type searchCircuit struct {
+	Data         []frontend.Variable
+	Length       frontend.Variable
+	Allowed      []frontend.Variable
+	AllowedLen   frontend.Variable
+	Position     frontend.Variable `gnark:",public"`
+	Forbidden    []frontend.Variable
+	ForbiddenLen frontend.Variable
+}
+
+func (c *searchCircuit) Define(api frontend.API) error {
+	s, err := New(api, c.Data, c.Length)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(s.AssertContains(c.Allowed, c.AllowedLen), c.Position)
+	s.AssertNotContains(c.Forbidden, c.ForbiddenLen)
+	return nil
+}
+
+func newSearchCircuit() *searchCircuit {
+	return &searchCircuit{
+		Data:      make([]frontend.Variable, capacity),
+		Allowed:   make([]frontend.Variable, maxPattern),
+		Forbidden: make([]frontend.Variable, maxPattern),
+	}
+}
+
+func newSearchAssignment(data, allowed, forbidden string, position int) *searchCircuit {
+	toVariables := func(s string, n int) []frontend.Variable {
+		res := make([]frontend.Variable, n)
+		for i := range res {
+			res[i] = 0
+			if i < len(s) {
+				res[i] = s[i]
+			}
+		}
+		return res
+	}
+	return &searchCircuit{
+		Data:         toVariables(data+"trailing bytes are ignored", capacity),
+		Length:       len(data),
+		Allowed:      toVariables(allowed+"xyz", maxPattern),
+		AllowedLen:   len(allowed),
+		Position:     position,
+		Forbidden:    toVariables(forbidden+"xyz", maxPattern),
+		ForbiddenLen: len(forbidden),
+	}
+}
+
+func TestSearch(t *testing.T) {
+	assert := test.NewAssert(t)
+	const data = "the quick brown fox jumps"
+	assert.CheckCircuit(newSearchCircuit(),
+		test.WithValidAssignment(newSearchAssignment(data, "brown", "dog", 10)),
+		test.WithValidAssignment(newSearchAssignment(data, "the", "jumped", 0)),
+		test.WithValidAssignment(newSearchAssignment(data, "jumps", "umpst", 20)),
+		test.WithValidAssignment(newSearchAssignment(data, "", "ignored", 0)),
+		test.WithValidAssignment(newSearchAssignment("fox", "fox", "foxes", 0)),
+		// a wrong position, patterns not found or cut by the length, and
+		// forbidden patterns found
+		test.WithInvalidAssignment(newSearchAssignment(data, "brown", "dog", 11)),
+		test.WithInvalidAssignment(newSearchAssignment(data, "fox", "dog", 0)),
+		test.WithInvalidAssignment(newSearchAssignment(data, "ignored", "dog", 0)),
+		test.WithInvalidAssignment(newSearchAssignment(data, "brown", "fox", 10)),
+		test.WithInvalidAssignment(newSearchAssignment(data, "brown", "jumps", 10)),
+		test.WithInvalidAssignment(newSearchAssignment(data, "brown", "", 10)),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
+
+type notContainsCircuit struct {
+	Data         []frontend.Variable
+	Length       frontend.Variable
+	Forbidden    []frontend.Variable
+	ForbiddenLen frontend.Variable
+}
+
+func (c *notContainsCircuit) Define(api frontend.API) error {
+	s, err := New(api, c.Data, c.Length)
+	if err != nil {
+		return err
+	}
+	s.AssertNotContains(c.Forbidden, c.ForbiddenLen)
+	return nil
+}
+
+func TestNegativeLength(t *testing.T) {
+	assert := test.NewAssert(t)
+	newAssignment := func(length, forbiddenLen int) *notContainsCircuit {
+		a := newSearchAssignment("the quick brown fox jumps", "", "fox", 0)
+		return &notContainsCircuit{Data: a.Data, Length: length, Forbidden: a.Forbidden, ForbiddenLen: forbiddenLen}
+	}
+	// a negative length would disable all the windows of the string
+	assert.CheckCircuit(&notContainsCircuit{
+		Data:      make([]frontend.Variable, capacity),
+		Forbidden: make([]frontend.Variable, maxPattern),
+	},
+		test.WithValidAssignment(newAssignment(3, 3)),
+		test.WithInvalidAssignment(newAssignment(25, 3)),
+		test.WithInvalidAssignment(newAssignment(-1, 3)),
+		test.WithInvalidAssignment(newAssignment(25, -1)),
+		test.WithCurves(ecc.BN254), test.NoFuzzing(), test.NoSerializationChecks())
+}
@@ -126,16 +126,7 @@ func lazySchema(circuit frontend.Circuit) func() *schema.Schema {
 
 // compile the given circuit for given curve and backend, if not already present in cache
 func (assert *Assert) compile(circuit frontend.Circuit, curveID ecc.ID, backendID backend.ID, compileOpts []frontend.CompileOption) (constraint.ConstraintSystem, error) {
-	var newBuilder frontend.NewBuilder
-
-	switch backendID {
-	case backend.GROTH16:
-		newBuilder = r1cs.NewBuilder
-	case backend.PLONK:
-		newBuilder = scs.NewBuilder
-	default:
-		panic("not implemented")
-	}
+	newBuilder := newBuilder(backendID)
 
 	// else compile it and ensure it is deterministic
 	ccs, err := frontend.Compile(curveID.ScalarField(), newBuilder, circuit, compileOpts...)
@@ -155,6 +146,18 @@ func (assert *Assert) compile(circuit frontend.Circuit, curveID ecc.ID, backendI
 	return ccs, nil
 }
 
+// newBuilder returns the constraint system builder of the given backend.
+func newBuilder(backendID backend.ID) frontend.NewBuilder {
+	switch backendID {
+	case backend.GROTH16:
+		return r1cs.NewBuilder
+	case backend.PLONK:
+		return scs.NewBuilder
+	default:
+		panic("not implemented")
+	}
+}
+
 // error ensure the error is set, else fails the test
 // add a witness to the error message if provided
 func (assert *Assert) error(err error, w *_witness) {
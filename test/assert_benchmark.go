@@ -0,0 +1,181 @@
+package test
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// BenchmarkCircuit benchmarks the full life cycle of the circuit for each
+// curve and backend of the testing configuration: compilation, setup, proving
+// and verification with the first valid assignment (see [WithValidAssignment]).
+// When not restricted with [WithCurves] or [WithBackends], the circuit is
+// benchmarked on BN254 and BLS12-381 with all implemented backends.
+//
+// Each curve and backend runs as a sub-benchmark "<curve>/<backend>" and
+// reports, in addition to the total ns/op, the following metrics:
+//   - compile-ns/op, setup-ns/op, prove-ns/op, verify-ns/op: time per phase;
+//   - constraints, internal-wires, hints: size of the compiled circuit;
+//   - peak-heap-B: highest heap usage above the baseline, sampled during the run.
+//
+// The output follows the standard benchmark format, so results of different
+// versions can be compared with benchstat:
+//
+//	func BenchmarkMyCircuit(b *testing.B) {
+//		test.BenchmarkCircuit(b, &MyCircuit{}, test.WithValidAssignment(&MyCircuit{X: 3}))
+//	}
+func BenchmarkCircuit(b *testing.B, circuit frontend.Circuit, opts ...TestingOption) {
+	b.Helper()
+	opt := testingConfig{profile: constraintSolverChecks}
+	for _, option := range opts {
+		if err := option(&opt); err != nil {
+			b.Fatalf("parsing TestingOption: %v", err)
+		}
+	}
+	if len(opt.validAssignments) == 0 {
+		b.Fatal("BenchmarkCircuit requires a valid assignment")
+	}
+
+	for _, curve := range opt.curves {
+		for _, backendID := range opt.backends {
+			curve, backendID := curve, backendID
+			b.Run(curve.String()+"/"+backendID.String(), func(b *testing.B) {
+				benchmarkCircuit(b, circuit, opt.validAssignments[0], curve, backendID, &opt)
+			})
+		}
+	}
+}
+
+func benchmarkCircuit(b *testing.B, circuit, assignment frontend.Circuit, curve ecc.ID, backendID backend.ID, opt *testingConfig) {
+	var concreteBackend tBackend
+	switch backendID {
+	case backend.GROTH16:
+		concreteBackend = _groth16
+	case backend.PLONK:
+		concreteBackend = _plonk
+	default:
+		b.Fatalf("backend %s not implemented", backendID)
+	}
+
+	fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+	if err != nil {
+		b.Fatal(err)
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var compileTime, setupTime, proveTime, verifyTime time.Duration
+	var stats ConstraintStats
+	sampler := newHeapSampler()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		ccs, err := frontend.Compile(curve.ScalarField(), newBuilder(backendID), circuit, opt.compileOpts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		compileTime += time.Since(start)
+		stats = NewConstraintStats(ccs)
+
+		start = time.Now()
+		pk, vk, err := concreteBackend.setup(ccs, curve)
+		if err != nil {
+			b.Fatal(err)
+		}
+		setupTime += time.Since(start)
+
+		start = time.Now()
+		proof, err := concreteBackend.prove(ccs, pk, fullWitness, opt.proverOpts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		proveTime += time.Since(start)
+
+		start = time.Now()
+		if err = concreteBackend.verify(proof, vk, publicWitness, opt.verifierOpts...); err != nil {
+			b.Fatal(err)
+		}
+		verifyTime += time.Since(start)
+	}
+	b.StopTimer()
+	peak := sampler.stop()
+
+	perOp := func(d time.Duration) float64 { return float64(d.Nanoseconds()) / float64(b.N) }
+	b.ReportMetric(perOp(compileTime), "compile-ns/op")
+	b.ReportMetric(perOp(setupTime), "setup-ns/op")
+	b.ReportMetric(perOp(proveTime), "prove-ns/op")
+	b.ReportMetric(perOp(verifyTime), "verify-ns/op")
+	b.ReportMetric(float64(stats.NbConstraints), "constraints")
+	b.ReportMetric(float64(stats.NbInternalWires), "internal-wires")
+	b.ReportMetric(float64(stats.NbHints), "hints")
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+// heapSamplingPeriod is the period at which the heap usage is sampled while
+// benchmarking a circuit.
+const heapSamplingPeriod = time.Millisecond
+
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// heapSampler records the highest heap usage observed by periodic sampling,
+// relative to the heap usage after a garbage collection at creation.
+type heapSampler struct {
+	baseline uint64
+	peak     uint64
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newHeapSampler() *heapSampler {
+	runtime.GC()
+	s := &heapSampler{done: make(chan struct{})}
+	s.baseline = readHeapObjects()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(heapSamplingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				if h := readHeapObjects(); h > s.peak {
+					s.peak = h
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// stop stops the sampling and returns the peak heap usage above the baseline.
+func (s *heapSampler) stop() uint64 {
+	close(s.done)
+	s.wg.Wait()
+	if h := readHeapObjects(); h > s.peak {
+		s.peak = h
+	}
+	if s.peak < s.baseline {
+		return 0
+	}
+	return s.peak - s.baseline
+}
+
+func readHeapObjects() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
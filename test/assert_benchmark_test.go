@@ -0,0 +1,13 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func BenchmarkTaggedCircuit(b *testing.B) {
+	BenchmarkCircuit(b, &taggedCircuit{},
+		WithValidAssignment(&taggedCircuit{X: 3, Y: 27}),
+		WithCurves(ecc.BN254))
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	gnarkprofile "github.com/consensys/gnark/profile"
+)
+
+// snapshotDir is the directory (relative to the package under test) where the
+// constraint count snapshots are stored.
+const snapshotDir = "testdata/constraints"
+
+// ConstraintStats summarizes the size of a compiled circuit.
+type ConstraintStats struct {
+	NbConstraints   int `json:"constraints"`
+	NbInternalWires int `json:"internalWires"`
+	NbHints         int `json:"hints"`
+}
+
+func (s ConstraintStats) String() string {
+	return fmt.Sprintf("nbConstraints: %d, nbInternalWires: %d, nbHints: %d", s.NbConstraints, s.NbInternalWires, s.NbHints)
+}
+
+// NewConstraintStats returns the statistics of the compiled constraint system.
+func NewConstraintStats(ccs constraint.ConstraintSystem) ConstraintStats {
+	return ConstraintStats{
+		NbConstraints:   ccs.GetNbConstraints(),
+		NbInternalWires: ccs.GetNbInternalVariables(),
+		NbHints:         ccs.GetNbHints(),
+	}
+}
+
+// AssertConstraintBudget compiles the circuit with the given builder on the
+// scalar field of curve and fails the test if the number of constraints
+// exceeds budget.
+func AssertConstraintBudget(t testing.TB, circuit frontend.Circuit, newBuilder frontend.NewBuilder, curve ecc.ID, budget int, opts ...frontend.CompileOption) {
+	t.Helper()
+	ccs, err := frontend.Compile(curve.ScalarField(), newBuilder, circuit, opts...)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if nb := ccs.GetNbConstraints(); nb > budget {
+		t.Fatalf("circuit exceeds constraint budget on %s: %d > %d (%s)", curve, nb, budget, NewConstraintStats(ccs))
+	}
+}
+
+// constraintSnapshot is the entry of a golden file for a curve and a backend.
+// Gadgets is the number of constraints added by each function calling the
+// frontend API, see [gnarkprofile.Profile.Gadgets]. The constraints added by
+// custom blueprints are not attributed to a gadget.
+type constraintSnapshot struct {
+	ConstraintStats
+	Gadgets map[string]int64 `json:"gadgets"`
+}
+
+// snapshotLock serializes the profiling of the compilations of
+// CheckConstraintSnapshot, as a profile records the constraints of all the
+// compilations running.
+var snapshotLock sync.Mutex
+
+// CheckConstraintSnapshot compiles the circuit for all curves and backends of
+// the testing configuration and compares the resulting [ConstraintStats], and
+// the number of constraints added by each gadget, with the golden file
+// testdata/constraints/<name>.json of the package under test. The check fails
+// if any of the counts changed, so that a dependency bump or a refactoring can
+// not silently increase (or decrease) the size of a circuit, and reports the
+// gadgets whose counts changed.
+//
+// The gadgets are found by profiling the compilation (see package profile),
+// so the check must not run in parallel with other compilations of the test
+// binary.
+//
+// When the build tag "update_snapshots" is set, the golden file is written
+// instead of being compared against:
+//
+//	go test -tags=update_snapshots -run TestMyGadget
+func (assert *Assert) CheckConstraintSnapshot(name string, circuit frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+
+	// the test engine only profile doesn't set backends, but the snapshot
+	// needs compiled circuits.
+	backends := opt.backends
+	if len(backends) == 0 {
+		backends = backend.Implemented()
+	}
+
+	got := make(map[string]constraintSnapshot)
+	for _, curve := range opt.curves {
+		for _, b := range backends {
+			snapshotLock.Lock()
+			p := gnarkprofile.Start(gnarkprofile.WithNoOutput())
+			ccs, err := frontend.Compile(curve.ScalarField(), newBuilder(b), circuit, opt.compileOpts...)
+			p.Stop()
+			snapshotLock.Unlock()
+			assert.NoError(err, "compiling %s on %s", b, curve)
+
+			s := constraintSnapshot{ConstraintStats: NewConstraintStats(ccs), Gadgets: p.Gadgets()}
+			assert.LessOrEqual(p.NbConstraints(), s.NbConstraints, "constraints of concurrent compilations profiled")
+			got[snapshotKey(curve, b)] = s
+		}
+	}
+
+	path := filepath.Join(snapshotDir, name+".json")
+	if updateSnapshotsFlag {
+		assert.NoError(writeSnapshot(path, got), "writing constraint snapshot")
+		return
+	}
+
+	expected, err := readSnapshot(path)
+	assert.NoError(err, "reading constraint snapshot; run with -tags=update_snapshots to create it")
+
+	if diff := diffSnapshots(expected, got); len(diff) > 0 {
+		assert.FailNow(fmt.Sprintf("constraint snapshot %s mismatch:\n%s", name, strings.Join(diff, "\n")))
+	}
+}
+
+// diffSnapshots returns the sorted differences between the expected and the
+// obtained snapshots, an entry per curve and backend and per gadget.
+func diffSnapshots(expected, got map[string]constraintSnapshot) []string {
+	var diff []string
+	for k, s := range got {
+		ref, ok := expected[k]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("%s: missing in snapshot, got %s", k, s.ConstraintStats))
+			continue
+		}
+		if ref.ConstraintStats != s.ConstraintStats {
+			diff = append(diff, fmt.Sprintf("%s: expected %s, got %s", k, ref.ConstraintStats, s.ConstraintStats))
+		}
+		for g, nb := range s.Gadgets {
+			if nbRef := ref.Gadgets[g]; nbRef != nb {
+				diff = append(diff, fmt.Sprintf("%s: %s: expected %d constraints, got %d", k, g, nbRef, nb))
+			}
+		}
+		for g, nbRef := range ref.Gadgets {
+			if _, ok := s.Gadgets[g]; !ok {
+				diff = append(diff, fmt.Sprintf("%s: %s: expected %d constraints, got 0", k, g, nbRef))
+			}
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+func snapshotKey(curve ecc.ID, b backend.ID) string {
+	return curve.String() + "/" + b.String()
+}
+
+func readSnapshot(path string) (map[string]constraintSnapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path built from snapshot name in tests
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]constraintSnapshot)
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+	return res, nil
+}
+
+func writeSnapshot(path string, stats map[string]constraintSnapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
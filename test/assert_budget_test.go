@@ -0,0 +1,42 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+func TestConstraintBudget(t *testing.T) {
+	AssertConstraintBudget(t, &taggedCircuit{}, r1cs.NewBuilder, ecc.BN254, 10)
+	AssertConstraintBudget(t, &taggedCircuit{}, scs.NewBuilder, ecc.BN254, 10)
+}
+
+func TestConstraintSnapshot(t *testing.T) {
+	assert := NewAssert(t)
+	assert.CheckConstraintSnapshot("tagged", &taggedCircuit{}, WithCurves(ecc.BN254, ecc.BLS12_381))
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	stats := ConstraintStats{NbConstraints: 8, NbInternalWires: 7, NbHints: 4}
+	expected := map[string]constraintSnapshot{
+		"bn254/plonk": {stats, map[string]int64{"test.Tag": 4, "test.(*taggedCircuit).Define": 4}},
+	}
+	got := map[string]constraintSnapshot{
+		"bn254/plonk":   {stats, map[string]int64{"test.Tag": 5, "test.other": 3}},
+		"bn254/groth16": {stats, nil},
+	}
+	diff := diffSnapshots(expected, got)
+	expectedDiff := []string{
+		"bn254/groth16: missing in snapshot, got nbConstraints: 8, nbInternalWires: 7, nbHints: 4",
+		"bn254/plonk: test.(*taggedCircuit).Define: expected 4 constraints, got 0",
+		"bn254/plonk: test.Tag: expected 4 constraints, got 5",
+		"bn254/plonk: test.other: expected 0 constraints, got 3",
+	}
+	if !reflect.DeepEqual(diff, expectedDiff) {
+		t.Fatalf("unexpected diff:\n%s", strings.Join(diff, "\n"))
+	}
+}
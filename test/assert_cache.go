@@ -0,0 +1,130 @@
+package test
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+// WithFSCache is a testing option which enables an on-disk cache for the
+// artifacts generated during the prover checks: the unsafe KZG SRS (keyed by
+// curve and size) and the Groth16 and PLONK proving and verifying keys (keyed
+// by backend, curve and constraint system digest).
+//
+// The cache is stored in ~/.gnark/test and is shared between packages and test
+// runs. The keys are generated with unsafe randomness and must never be used
+// outside of tests.
+func WithFSCache() TestingOption {
+	return func(opt *testingConfig) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("get home directory: %w", err)
+		}
+		opt.cacheDir = filepath.Join(homeDir, ".gnark", "test")
+		return nil
+	}
+}
+
+// WithFSCacheDir is similar to [WithFSCache] but stores the cache in dir.
+func WithFSCacheDir(dir string) TestingOption {
+	return func(opt *testingConfig) error {
+		opt.cacheDir = dir
+		return nil
+	}
+}
+
+// setup runs the backend setup. If cacheDir is set, the keys are read from the
+// cache when present and written to it otherwise.
+func (assert *Assert) setup(tb tBackend, b backend.ID, ccs constraint.ConstraintSystem, curve ecc.ID, cacheDir string) (pk, vk any, err error) {
+	if cacheDir == "" {
+		return tb.setup(ccs, curve)
+	}
+	log := logger.Logger().With().Str("cacheDir", cacheDir).Logger()
+
+	digest, err := ccsDigest(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix := filepath.Join(cacheDir, fmt.Sprintf("%s-%s-%s", b, curve, digest))
+	pkPath, vkPath := prefix+".pk", prefix+".vk"
+
+	pk, vk = tb.newProvingKey(curve), tb.newVerifyingKey(curve)
+	if errPk, errVk := readCached(pkPath, pk), readCached(vkPath, vk); errPk == nil && errVk == nil {
+		log.Debug().Str("key", prefix).Msg("keys found in fs cache")
+		return pk, vk, nil
+	}
+
+	if err = os.MkdirAll(cacheDir, 0o700); err != nil {
+		return nil, nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	pk, vk, err = tb.setup(ccs, curve, unsafekzg.WithCacheDir(filepath.Join(cacheDir, "kzg")))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeCached(pkPath, pk); err != nil {
+		log.Warn().Err(err).Msg("could not write proving key to fs cache")
+	}
+	if err := writeCached(vkPath, vk); err != nil {
+		log.Warn().Err(err).Msg("could not write verifying key to fs cache")
+	}
+	return pk, vk, nil
+}
+
+// ccsDigest returns the hex encoded sha256 digest of the serialized constraint
+// system.
+func ccsDigest(ccs constraint.ConstraintSystem) (string, error) {
+	h := sha256.New()
+	if _, err := ccs.WriteTo(h); err != nil {
+		return "", fmt.Errorf("hash constraint system: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readCached(path string, into any) error {
+	r, ok := into.(gnarkio.UnsafeReaderFrom)
+	if !ok {
+		return fmt.Errorf("%T does not implement io.UnsafeReaderFrom", into)
+	}
+	f, err := os.Open(path) // #nosec G304 -- path built from cache directory and digest
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = r.UnsafeReadFrom(bufio.NewReaderSize(f, 1<<20))
+	return err
+}
+
+func writeCached(path string, from any) error {
+	w, ok := from.(gnarkio.WriterRawTo)
+	if !ok {
+		return fmt.Errorf("%T does not implement io.WriterRawTo", from)
+	}
+	// write to a temporary file first so that concurrent test binaries never
+	// observe a partially written key.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriterSize(f, 1<<20)
+	if _, err = w.WriteRawTo(bw); err == nil {
+		err = bw.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+func TestFSCacheSetup(t *testing.T) {
+	assert := NewAssert(t)
+	cacheDir := t.TempDir()
+
+	for _, b := range backend.Implemented() {
+		tb := _groth16
+		if b == backend.PLONK {
+			tb = _plonk
+		}
+		ccs, err := assert.compile(&taggedCircuit{}, ecc.BN254, b, nil)
+		assert.NoError(err)
+
+		// first setup populates the cache, second one reads from it.
+		_, _, err = assert.setup(tb, b, ccs, ecc.BN254, cacheDir)
+		assert.NoError(err)
+		pk, vk, err := assert.setup(tb, b, ccs, ecc.BN254, cacheDir)
+		assert.NoError(err)
+
+		w := assert.parseAssignment(&taggedCircuit{}, &taggedCircuit{X: 3, Y: 27}, ecc.BN254, false)
+		proof, err := tb.prove(ccs, pk, w.full)
+		assert.NoError(err)
+		assert.NoError(tb.verify(proof, vk, w.public))
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	assert.NoError(err)
+	var nbKeys int
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); ext == ".pk" || ext == ".vk" {
+			nbKeys++
+		}
+	}
+	assert.Equal(4, nbKeys)
+}
@@ -23,7 +23,10 @@ import (
 //
 // Depending on the above flags, the following checks are performed:
 //   - the circuit compiles
-//   - the circuit can be solved with the test engine
+//   - the circuit can be solved with the test engine, modelling the
+//     commitments of each backend (see [WithCommitmentBackend]) and checking
+//     that they are used (see [WithCommitmentUseCheck] and
+//     [NoCommitmentUseCheck])
 //   - the circuit can be solved with the constraint system solver
 //   - the circuit can be solved with the prover
 //   - the circuit can be verified with the verifier
@@ -48,8 +51,13 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 
 				// check that the assignment is valid with the test engine
 				if !opt.skipTestEngine {
-					err := IsSolved(circuit, w.assignment, curve.ScalarField())
-					assert.noError(err, &w)
+					for _, b := range opt.engineBackends() {
+						nbCommitments, err := isSolved(circuit, w.assignment, curve.ScalarField(), opt.engineOptions(b, true)...)
+						assert.noError(err, &w)
+						if nbCommitments == 0 {
+							break
+						}
+					}
 				}
 			}
 
@@ -59,8 +67,13 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 
 				// check that the assignment is invalid with the test engine
 				if !opt.skipTestEngine {
-					err := IsSolved(circuit, w.assignment, curve.ScalarField())
-					assert.error(err, &w)
+					for _, b := range opt.engineBackends() {
+						nbCommitments, err := isSolved(circuit, w.assignment, curve.ScalarField(), opt.engineOptions(b, true)...)
+						assert.error(err, &w)
+						if nbCommitments == 0 {
+							break
+						}
+					}
 				}
 			}
 
@@ -77,6 +90,22 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 
 					// TODO @gbotrel check serialization round trip with constraint system.
 
+					// compare the test engine with the constraint system solver
+					if opt.differential {
+						for _, w := range validWitnesses {
+							w := w
+							assert.Run(func(assert *Assert) {
+								assert.differentialCheck(circuit, ccs, w, opt.solverOpts, opt.engineOptions(b, false))
+							}, "differential", "valid_witness")
+						}
+						for _, w := range invalidWitnesses {
+							w := w
+							assert.Run(func(assert *Assert) {
+								assert.differentialCheck(circuit, ccs, w, opt.solverOpts, opt.engineOptions(b, false))
+							}, "differential", "invalid_witness")
+						}
+					}
+
 					// 2- if we are not running the full prover;
 					// we need to run the solver on the constraint system only
 					if !opt.checkProver {
@@ -114,8 +143,11 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 					}
 
 					// proof system setup.
-					pk, vk, pkBuilder, vkBuilder, proofBuilder, err := concreteBackend.setup(ccs, curve)
+					pk, vk, err := assert.setup(concreteBackend, b, ccs, curve, opt.cacheDir)
 					assert.noError(err, nil)
+					pkBuilder := func() any { return concreteBackend.newProvingKey(curve) }
+					vkBuilder := func() any { return concreteBackend.newVerifyingKey(curve) }
+					proofBuilder := func() any { return concreteBackend.newProof(curve) }
 
 					// for each valid witness, run the prover and verifier
 					for _, w := range validWitnesses {
@@ -229,28 +261,26 @@ func (assert *Assert) parseAssignment(circuit frontend.Circuit, assignment front
 	return _witness{full: full, public: public, assignment: assignment}
 }
 
-type fnSetup func(ccs constraint.ConstraintSystem, curve ecc.ID) (
-	pk, vk any,
-	pkBuilder, vkBuilder, proofBuilder func() any,
-	err error)
+type fnSetup func(ccs constraint.ConstraintSystem, curve ecc.ID, srsOpts ...unsafekzg.Option) (pk, vk any, err error)
 type fnProve func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error)
 type fnVerify func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error
+type fnBuilder func(curve ecc.ID) any
 
 // tBackend abstracts the backend implementation in the test package.
 type tBackend struct {
 	setup  fnSetup
 	prove  fnProve
 	verify fnVerify
+
+	newProvingKey   fnBuilder
+	newVerifyingKey fnBuilder
+	newProof        fnBuilder
 }
 
 var (
 	_groth16 = tBackend{
-		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (
-			pk, vk any,
-			pkBuilder, vkBuilder, proofBuilder func() any,
-			err error) {
-			pk, vk, err = groth16.Setup(ccs)
-			return pk, vk, func() any { return groth16.NewProvingKey(curve) }, func() any { return groth16.NewVerifyingKey(curve) }, func() any { return groth16.NewProof(curve) }, err
+		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID, srsOpts ...unsafekzg.Option) (pk, vk any, err error) {
+			return groth16.Setup(ccs)
 		},
 		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return groth16.Prove(ccs, pk.(groth16.ProvingKey), fullWitness, opts...)
@@ -258,19 +288,18 @@ var (
 		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
 			return groth16.Verify(proof.(groth16.Proof), vk.(groth16.VerifyingKey), publicWitness, opts...)
 		},
+		newProvingKey:   func(curve ecc.ID) any { return groth16.NewProvingKey(curve) },
+		newVerifyingKey: func(curve ecc.ID) any { return groth16.NewVerifyingKey(curve) },
+		newProof:        func(curve ecc.ID) any { return groth16.NewProof(curve) },
 	}
 
 	_plonk = tBackend{
-		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (
-			pk, vk any,
-			pkBuilder, vkBuilder, proofBuilder func() any,
-			err error) {
-			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID, srsOpts ...unsafekzg.Option) (pk, vk any, err error) {
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs, srsOpts...)
 			if err != nil {
-				return nil, nil, nil, nil, nil, err
+				return nil, nil, err
 			}
-			pk, vk, err = plonk.Setup(ccs, srs, srsLagrange)
-			return pk, vk, func() any { return plonk.NewProvingKey(curve) }, func() any { return plonk.NewVerifyingKey(curve) }, func() any { return plonk.NewProof(curve) }, err
+			return plonk.Setup(ccs, srs, srsLagrange)
 		},
 		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
 			return plonk.Prove(ccs, pk.(plonk.ProvingKey), fullWitness, opts...)
@@ -278,5 +307,8 @@ var (
 		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
 			return plonk.Verify(proof.(plonk.Proof), vk.(plonk.VerifyingKey), publicWitness, opts...)
 		},
+		newProvingKey:   func(curve ecc.ID) any { return plonk.NewProvingKey(curve) },
+		newVerifyingKey: func(curve ecc.ID) any { return plonk.NewVerifyingKey(curve) },
+		newProof:        func(curve ecc.ID) any { return plonk.NewProof(curve) },
 	}
 )
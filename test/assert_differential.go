@@ -0,0 +1,176 @@
+package test
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+func init() {
+	solver.RegisterHint(tagHint)
+}
+
+var (
+	tagNames  = make(map[uint64]string)
+	tagNamesM sync.RWMutex
+)
+
+type tagCounterKey struct{}
+
+// tagKey identifies a tagged wire: the hash of the tag name (reduced modulo
+// the field) and the occurrence of the tag during the circuit definition.
+type tagKey struct {
+	name       uint64
+	occurrence uint64
+}
+
+// format returns a human readable name of the tag. On small fields the name
+// hash is reduced, in which case the first matching name is returned.
+func (k tagKey) format(field *big.Int) string {
+	tagNamesM.RLock()
+	defer tagNamesM.RUnlock()
+	name, ok := tagNames[k.name]
+	if !ok {
+		var h big.Int
+		name = fmt.Sprintf("<unknown tag %d>", k.name)
+		for hash, n := range tagNames {
+			if h.SetUint64(hash).Mod(&h, field).Uint64() == k.name {
+				name = n
+				break
+			}
+		}
+	}
+	if k.occurrence == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s#%d", name, k.occurrence)
+}
+
+// Tag marks the intermediate value v with the given name. When the circuit is
+// checked with the [WithDifferentialTesting] option, the values of the tagged
+// wires computed by the test engine are compared with the values computed by
+// the constraint system solver.
+//
+// A name may be tagged several times (for example in a loop), the occurrences
+// are then distinguished by the order in which Tag is called in Define.
+//
+// Tagging adds a hint and a single constraint to the circuit, so it should be
+// used for testing purposes only.
+func Tag(api frontend.API, name string, v frontend.Variable) {
+	h := fnv.New64a()
+	h.Write([]byte(name)) // #nosec G104 -- does not err
+	nameHash := h.Sum64()
+
+	tagNamesM.Lock()
+	tagNames[nameHash] = name
+	tagNamesM.Unlock()
+
+	var occurrence uint64
+	if kv, ok := api.Compiler().(kvstore.Store); ok {
+		counters, _ := kv.GetKeyValue(tagCounterKey{}).(map[uint64]uint64)
+		if counters == nil {
+			counters = make(map[uint64]uint64)
+			kv.SetKeyValue(tagCounterKey{}, counters)
+		}
+		occurrence = counters[nameHash]
+		counters[nameHash]++
+	}
+
+	res, err := api.Compiler().NewHint(tagHint, 1, new(big.Int).SetUint64(nameHash), occurrence, v)
+	if err != nil {
+		panic(fmt.Sprintf("tag %s: %v", name, err))
+	}
+	api.AssertIsEqual(res[0], v)
+}
+
+// tagHint is the identity function on its last input. The first inputs encode
+// the tag identifier and are only used when recording the tagged values.
+func tagHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 3 || len(outputs) != 1 {
+		return fmt.Errorf("expected 3 inputs and 1 output")
+	}
+	outputs[0].Set(inputs[2])
+	return nil
+}
+
+// tagRecorder collects the values of the tagged wires during a single run of
+// the test engine or of the constraint system solver.
+type tagRecorder struct {
+	m      sync.Mutex
+	field  *big.Int
+	values map[tagKey]*big.Int
+}
+
+func newTagRecorder(field *big.Int) *tagRecorder {
+	return &tagRecorder{field: field, values: make(map[tagKey]*big.Int)}
+}
+
+func (r *tagRecorder) hint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if err := tagHint(field, inputs, outputs); err != nil {
+		return err
+	}
+	key := tagKey{name: inputs[0].Uint64(), occurrence: inputs[1].Uint64()}
+	r.m.Lock()
+	r.values[key] = new(big.Int).Mod(inputs[2], field)
+	r.m.Unlock()
+	return nil
+}
+
+// diff returns a description of the tagged values recorded by both r and
+// other which differ. Tags recorded by only one of the runs are ignored, as an
+// unsatisfied constraint may stop a run early.
+func (r *tagRecorder) diff(other *tagRecorder) []string {
+	var res []string
+	for k, v := range r.values {
+		if o, ok := other.values[k]; ok && o.Cmp(v) != 0 {
+			res = append(res, fmt.Sprintf("%s: engine=%s solver=%s", k.format(r.field), v.String(), o.String()))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// WithDifferentialTesting is a testing option which runs every assignment both
+// through the test engine and through the constraint system solver of every
+// checked curve and backend. The check fails if the two disagree on whether the
+// assignment is valid or on the value of any wire marked with [Tag].
+//
+// The option is meant to catch semantic mismatches between the test engine and
+// the solver, for example around hints or the [frontend.Committer] interface.
+func WithDifferentialTesting() TestingOption {
+	return func(opt *testingConfig) error {
+		opt.differential = true
+		return nil
+	}
+}
+
+// differentialCheck runs the given witness through the test engine and the
+// constraint system solver and compares the outcomes and the tagged values.
+func (assert *Assert) differentialCheck(circuit frontend.Circuit, ccs constraint.ConstraintSystem, w _witness, solverOpts []solver.Option, engineOpts []TestEngineOption) {
+	tagID := solver.GetHintID(tagHint)
+
+	engineTags := newTagRecorder(ccs.Field())
+	engineErr := IsSolved(circuit, w.assignment, ccs.Field(), append(engineOpts[:len(engineOpts):len(engineOpts)], withHintOverride(tagID, engineTags.hint))...)
+
+	solverTags := newTagRecorder(ccs.Field())
+	opts := make([]solver.Option, len(solverOpts), len(solverOpts)+1)
+	copy(opts, solverOpts)
+	opts = append(opts, solver.OverrideHint(tagID, solverTags.hint))
+	_, solverErr := ccs.Solve(w.full, opts...)
+
+	if (engineErr == nil) != (solverErr == nil) {
+		assert.FailNow(fmt.Sprintf("test engine and solver disagree on the assignment\nengine: %v\nsolver: %v", engineErr, solverErr))
+	}
+
+	if mismatches := engineTags.diff(solverTags); len(mismatches) > 0 {
+		assert.FailNow("test engine and solver disagree on tagged values:\n" + strings.Join(mismatches, "\n"))
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type taggedCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *taggedCircuit) Define(api frontend.API) error {
+	acc := frontend.Variable(1)
+	for i := 0; i < 3; i++ {
+		acc = api.Mul(acc, c.X)
+		Tag(api, "power", acc)
+	}
+	Tag(api, "inverse", api.Inverse(c.X))
+	api.AssertIsEqual(acc, c.Y)
+	return nil
+}
+
+func TestDifferentialTesting(t *testing.T) {
+	assert := NewAssert(t)
+	assert.CheckCircuit(&taggedCircuit{},
+		WithValidAssignment(&taggedCircuit{X: 3, Y: 27}),
+		WithInvalidAssignment(&taggedCircuit{X: 3, Y: 26}),
+		WithCurves(ecc.BN254), WithDifferentialTesting())
+	assert.CheckCircuit(&taggedCircuit{},
+		WithValidAssignment(&taggedCircuit{X: 3, Y: 27}),
+		WithInvalidAssignment(&taggedCircuit{X: 3, Y: 26}),
+		WithCurves(ecc.BN254), WithDifferentialTesting(), WithTestEngineOpts(WithNativeField()))
+}
+
+func TestTagRecorderDiff(t *testing.T) {
+	assert := NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	engineTags, solverTags := newTagRecorder(field), newTagRecorder(field)
+	outputs := []*big.Int{new(big.Int)}
+
+	assert.NoError(engineTags.hint(field, []*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(5)}, outputs))
+	assert.NoError(solverTags.hint(field, []*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(5)}, outputs))
+	assert.Empty(engineTags.diff(solverTags))
+
+	assert.NoError(engineTags.hint(field, []*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(5)}, outputs))
+	assert.NoError(solverTags.hint(field, []*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(6)}, outputs))
+	assert.Len(engineTags.diff(solverTags), 1)
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// WithSkippedMutations is a testing option which excludes the witness elements
+// with the given full names (for example "X" or "Limbs_2") from the mutations
+// of [Assert.CheckMutations]. It is useful for elements which are not
+// uniquely determined by the circuit.
+func WithSkippedMutations(fullNames ...string) TestingOption {
+	return func(opt *testingConfig) error {
+		opt.skippedMutations = append(opt.skippedMutations, fullNames...)
+		return nil
+	}
+}
+
+// mutation describes a perturbation of a valid assignment.
+type mutation struct {
+	desc   string
+	values map[int]*big.Int // index of the witness element -> mutated value
+}
+
+// CheckMutations checks that the circuit is sound with respect to small
+// perturbations of a valid assignment. For every witness element of
+// validAssignment it derives mutated assignments where the element is:
+//   - incremented or decremented by one (off-by-one);
+//   - modified by flipping its lowest and highest bits;
+//   - swapped with the next witness element, when their values differ.
+//
+// Each mutated assignment must be rejected by the test engine and by the
+// constraint system solver of every curve and backend of the testing
+// configuration. The check fails listing all the mutations which were
+// accepted. validAssignment is restored before returning.
+//
+// All the witness elements are expected to be uniquely determined by the
+// circuit, elements which are not can be excluded with [WithSkippedMutations].
+func (assert *Assert) CheckMutations(circuit, validAssignment frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+
+	var (
+		leaves []reflect.Value
+		names  []string
+	)
+	collectHandler := func(f schema.LeafInfo, tInput reflect.Value) error {
+		if tInput.IsNil() {
+			return fmt.Errorf("when parsing variable %s: missing assignment", f.FullName())
+		}
+		leaves = append(leaves, tInput)
+		names = append(names, f.FullName())
+		return nil
+	}
+	_, err := schema.Walk(validAssignment, tVariable, collectHandler)
+	assert.NoError(err, "parsing valid assignment")
+
+	original := make([]reflect.Value, len(leaves))
+	for i := range leaves {
+		original[i] = reflect.ValueOf(leaves[i].Interface())
+	}
+	restore := func() {
+		for i := range leaves {
+			leaves[i].Set(original[i])
+		}
+	}
+	defer restore()
+
+	skipped := make(map[string]struct{}, len(opt.skippedMutations))
+	for _, n := range opt.skippedMutations {
+		skipped[n] = struct{}{}
+	}
+
+	for _, curve := range opt.curves {
+		curve := curve
+		assert.Run(func(assert *Assert) {
+			field := curve.ScalarField()
+
+			values := make([]*big.Int, len(leaves))
+			for i := range leaves {
+				v := utils.FromInterface(original[i].Interface())
+				values[i] = v.Mod(&v, field)
+			}
+			mutations := mutations(values, names, skipped, field)
+
+			var ccss []constraint.ConstraintSystem
+			for _, b := range opt.backends {
+				ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				assert.NoError(err, "compiling %s", b)
+				ccss = append(ccss, ccs)
+			}
+
+			var accepted []string
+			for _, m := range mutations {
+				restore()
+				for i, v := range m.values {
+					leaves[i].Set(reflect.ValueOf(v))
+				}
+				if by := assert.acceptedBy(circuit, validAssignment, field, ccss, &opt); by != "" {
+					accepted = append(accepted, fmt.Sprintf("%s: accepted by %s", m.desc, by))
+				}
+			}
+			if len(accepted) > 0 {
+				assert.FailNow(fmt.Sprintf("%d/%d mutations of the valid assignment were accepted:\n%s", len(accepted), len(mutations), strings.Join(accepted, "\n")))
+			}
+		}, curve.String(), "mutations")
+	}
+}
+
+// acceptedBy returns a description of the first solver which accepted the
+// assignment, or the empty string if all solvers rejected it.
+func (assert *Assert) acceptedBy(circuit, assignment frontend.Circuit, field *big.Int, ccss []constraint.ConstraintSystem, opt *testingConfig) string {
+	if !opt.skipTestEngine {
+		if err := IsSolved(circuit, assignment, field); err == nil {
+			return "test engine"
+		}
+	}
+	if len(ccss) == 0 {
+		return ""
+	}
+	w, err := frontend.NewWitness(assignment, field)
+	assert.NoError(err, "can't parse mutated assignment into full witness")
+	for i, ccs := range ccss {
+		if err := ccs.IsSolved(w, opt.solverOpts...); err == nil {
+			return opt.backends[i].String() + " solver"
+		}
+	}
+	return ""
+}
+
+// mutations returns the list of perturbations of the given witness values.
+// Mutations which don't change the value are omitted.
+func mutations(values []*big.Int, names []string, skipped map[string]struct{}, field *big.Int) []mutation {
+	var res []mutation
+	one := big.NewInt(1)
+	for i, v := range values {
+		if _, ok := skipped[names[i]]; ok {
+			continue
+		}
+		seen := map[string]struct{}{v.String(): {}}
+		add := func(desc string, mv *big.Int) {
+			mv.Mod(mv, field)
+			if _, ok := seen[mv.String()]; ok {
+				return
+			}
+			seen[mv.String()] = struct{}{}
+			res = append(res, mutation{desc: fmt.Sprintf("%s %s", names[i], desc), values: map[int]*big.Int{i: mv}})
+		}
+		add("+1", new(big.Int).Add(v, one))
+		add("-1", new(big.Int).Sub(v, one))
+		add("flip bit 0", new(big.Int).SetBit(v, 0, v.Bit(0)^1))
+		if n := v.BitLen() - 1; n > 0 {
+			add(fmt.Sprintf("flip bit %d", n), new(big.Int).SetBit(v, n, 0))
+		}
+
+		if j := i + 1; j < len(values) && v.Cmp(values[j]) != 0 {
+			if _, ok := skipped[names[j]]; !ok {
+				res = append(res, mutation{
+					desc:   fmt.Sprintf("swap %s and %s", names[i], names[j]),
+					values: map[int]*big.Int{i: new(big.Int).Set(values[j]), j: new(big.Int).Set(v)},
+				})
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type mutationCircuit struct {
+	X      frontend.Variable
+	Y      frontend.Variable `gnark:",public"`
+	Unused frontend.Variable
+}
+
+func (c *mutationCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func TestCheckMutations(t *testing.T) {
+	assert := NewAssert(t)
+	assignment := &mutationCircuit{X: 3, Y: 27, Unused: 5}
+	assert.CheckMutations(&mutationCircuit{}, assignment, WithCurves(ecc.BN254), WithSkippedMutations("Unused"))
+	// the assignment is restored
+	assert.Equal(&mutationCircuit{X: 3, Y: 27, Unused: 5}, assignment)
+}
+
+func TestMutations(t *testing.T) {
+	assert := NewAssert(t)
+	field := big.NewInt(47)
+	values := []*big.Int{big.NewInt(4), big.NewInt(46), big.NewInt(46)}
+	names := []string{"A", "B", "C"}
+
+	var descs []string
+	for _, m := range mutations(values, names, map[string]struct{}{"C": {}}, field) {
+		descs = append(descs, m.desc)
+	}
+	assert.Equal([]string{
+		"A +1", "A -1", "A flip bit 2", "swap A and B", // flip bit 0 is the same as +1
+		"B +1", "B -1", "B flip bit 5", // B and C are equal, C is skipped
+	}, descs)
+}
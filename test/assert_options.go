@@ -20,9 +20,15 @@ type testingConfig struct {
 	proverOpts   []backend.ProverOption
 	verifierOpts []backend.VerifierOption
 	compileOpts  []frontend.CompileOption
+	engineOpts   []TestEngineOption
 
 	validAssignments   []frontend.Circuit
 	invalidAssignments []frontend.Circuit
+
+	differential     bool
+	noCommitmentUse  bool
+	cacheDir         string
+	skippedMutations []string
 }
 
 // default options
@@ -168,6 +174,47 @@ func WithCompileOpts(compileOpts ...frontend.CompileOption) TestingOption {
 	}
 }
 
+// WithTestEngineOpts is a testing option which uses the given engineOpts when
+// running the circuit in the test engine, for example [WithNativeField] for
+// large circuits.
+func WithTestEngineOpts(engineOpts ...TestEngineOption) TestingOption {
+	return func(opt *testingConfig) error {
+		opt.engineOpts = engineOpts
+		return nil
+	}
+}
+
+// NoCommitmentUseCheck is a testing option which disables the check by the
+// test engine that the commitments are used in the circuit, see
+// [WithCommitmentUseCheck]. It is enabled by default.
+func NoCommitmentUseCheck() TestingOption {
+	return func(opt *testingConfig) error {
+		opt.noCommitmentUse = true
+		return nil
+	}
+}
+
+// engineBackends returns the backends whose commitments are modelled by the
+// test engine checks: the backends of the configuration, or all of them when
+// the configuration has none.
+func (opt *testingConfig) engineBackends() []backend.ID {
+	if len(opt.backends) == 0 {
+		return []backend.ID{backend.GROTH16, backend.PLONK}
+	}
+	return opt.backends
+}
+
+// engineOptions returns the options of the test engine modelling the
+// commitments of b, with the commitment use check unless disabled. The user
+// options come last so that they take precedence.
+func (opt *testingConfig) engineOptions(b backend.ID, useCheck bool) []TestEngineOption {
+	res := []TestEngineOption{WithCommitmentBackend(b)}
+	if useCheck && !opt.noCommitmentUse {
+		res = append(res, WithCommitmentUseCheck())
+	}
+	return append(res, opt.engineOpts...)
+}
+
 // WithVerifierOpts is a testing option which uses the given verifierOpts when
 // calling backend.Verify method.
 func WithVerifierOpts(verifierOpts ...backend.VerifierOption) TestingOption {
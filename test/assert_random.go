@@ -0,0 +1,43 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// RandomAssignment fills all the [frontend.Variable] of the given assignment
+// with random elements of the field using the provided source of randomness.
+//
+// Slices in the assignment must be allocated with the expected lengths, as
+// when defining the circuit. The value of a witness element tagged with the
+// "boolean" or "bits=n" option (see [schema.TagOpt]) is sampled in [0, 2^n)
+// instead of the whole field:
+//
+//	type Circuit struct {
+//		Sel   frontend.Variable   `gnark:",boolean"`
+//		Limbs []frontend.Variable `gnark:",bits=64"`
+//		X     frontend.Variable   `gnark:",public"`
+//	}
+//
+// The tags only describe the expected domain of the values, the circuit must
+// still constrain them. Using a fixed seed for rng makes the assignment
+// reproducible.
+func RandomAssignment(assignment frontend.Circuit, field *big.Int, rng *rand.Rand) error {
+	setHandler := func(f schema.LeafInfo, tInput reflect.Value) error {
+		bound := field
+		if f.NbBits > 0 && f.NbBits < field.BitLen() {
+			bound = new(big.Int).Lsh(big.NewInt(1), uint(f.NbBits))
+		}
+		tInput.Set(reflect.ValueOf(new(big.Int).Rand(rng, bound)))
+		return nil
+	}
+	if _, err := schema.Walk(assignment, tVariable, setHandler); err != nil {
+		return fmt.Errorf("random assignment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type randomLimbs struct {
+	Limbs [4]frontend.Variable
+}
+
+type randomAssignmentCircuit struct {
+	Sel    frontend.Variable   `gnark:",boolean"`
+	Bytes  []frontend.Variable `gnark:",bits=8"`
+	Nested randomLimbs         `gnark:",bits=16"`
+	X      frontend.Variable   `gnark:",public"`
+}
+
+func (c *randomAssignmentCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(c.Sel)
+	for _, b := range c.Bytes {
+		api.ToBinary(b, 8)
+	}
+	for _, l := range c.Nested.Limbs {
+		api.ToBinary(l, 16)
+	}
+	api.AssertIsDifferent(c.X, 0)
+	return nil
+}
+
+func TestRandomAssignment(t *testing.T) {
+	assert := NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 weak rng is fine here
+
+	for i := 0; i < 10; i++ {
+		assignment := &randomAssignmentCircuit{Bytes: make([]frontend.Variable, 3)}
+		assert.NoError(RandomAssignment(assignment, field, rng))
+		assert.Less(assignment.Sel.(*big.Int).BitLen(), 2)
+		assert.Less(assignment.Bytes[2].(*big.Int).BitLen(), 9)
+		assert.Less(assignment.Nested.Limbs[3].(*big.Int).BitLen(), 17)
+		assert.Less(assignment.X.(*big.Int).Cmp(field), 0)
+
+		circuit := &randomAssignmentCircuit{Bytes: make([]frontend.Variable, 3)}
+		assert.NoError(IsSolved(circuit, assignment, field))
+	}
+}
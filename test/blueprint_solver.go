@@ -35,6 +35,10 @@ func (s *blueprintSolver) IsSolved(vID uint32) bool {
 	panic("not implemented in test.Engine")
 }
 
+func (s *blueprintSolver) ConstantTime() bool {
+	return false
+}
+
 // implements constraint.Field
 
 func (s *blueprintSolver) FromInterface(i interface{}) constraint.Element {
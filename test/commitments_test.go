@@ -2,6 +2,7 @@ package test
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/consensys/gnark/backend/witness"
 	cs "github.com/consensys/gnark/constraint/bn254"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/stretchr/testify/require"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -262,3 +264,73 @@ func comparePkSizes(t *testing.T, pk1, pk2 groth16.ProvingKey) {
 
 	require.Equal(t, len(pk1.CommitmentKeys), len(pk2.CommitmentKeys)) // TODO @Tabaie Compare the commitment keys
 }
+
+type unusedCommitmentCircuit struct {
+	X frontend.Variable
+}
+
+func (c *unusedCommitmentCircuit) Define(api frontend.API) error {
+	if _, err := api.(frontend.Committer).Commit(c.X); err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.X, 1)
+	return nil
+}
+
+func TestEngineCommitment(t *testing.T) {
+	// commitments to the same values at different depths are distinct
+	assert.NoError(t, IsSolved(&commitUniquenessCircuit{[]frontend.Variable{nil, nil}}, &commitUniquenessCircuit{[]frontend.Variable{0, 0}}, ecc.BN254.ScalarField()))
+
+	// an unused commitment is a misuse of the Committer interface, which the
+	// backends accept
+	assert.NoError(t, IsSolved(&unusedCommitmentCircuit{}, &unusedCommitmentCircuit{X: 1}, ecc.BN254.ScalarField()))
+	err := IsSolved(&unusedCommitmentCircuit{}, &unusedCommitmentCircuit{X: 1}, ecc.BN254.ScalarField(), WithCommitmentUseCheck())
+	assert.ErrorContains(t, err, "commitment 0 is not used")
+
+	for _, assignment := range commitmentTestCircuits {
+		assert.NoError(t, IsSolved(hollow(assignment), assignment, ecc.BN254.ScalarField()))
+	}
+}
+
+func TestEngineCommitmentBackend(t *testing.T) {
+	commit := func(b backend.ID, v ...frontend.Variable) *big.Int {
+		e := &engine{
+			curveID: ecc.BN254,
+			q:       ecc.BN254.ScalarField(),
+			Store:   kvstore.New(),
+		}
+		require.NoError(t, WithCommitmentBackend(b)(e))
+		res, err := e.Commit(v...)
+		require.NoError(t, err)
+		return res.(*big.Int)
+	}
+
+	// Groth16 commits to a set, PLONK to a list
+	assert.Equal(t, commit(backend.GROTH16, 1, 2), commit(backend.GROTH16, 2, 1, 2))
+	assert.NotEqual(t, commit(backend.PLONK, 1, 2), commit(backend.PLONK, 2, 1))
+	assert.NotEqual(t, commit(backend.PLONK, 1, 2), commit(backend.PLONK, 1, 2, 2))
+
+	assert.Error(t, IsSolved(&unusedCommitmentCircuit{}, &unusedCommitmentCircuit{X: 1}, ecc.BN254.ScalarField(), WithCommitmentBackend(backend.UNKNOWN)))
+}
+
+func TestCheckCircuitEngineOptions(t *testing.T) {
+	var opt testingConfig
+	assert.Equal(t, []backend.ID{backend.GROTH16, backend.PLONK}, opt.engineBackends())
+	require.NoError(t, WithBackends(backend.PLONK)(&opt))
+	assert.Equal(t, []backend.ID{backend.PLONK}, opt.engineBackends())
+
+	// CheckCircuit runs the commitment model of each backend. It rejects the
+	// unused commitments, except in the differential checks comparing the
+	// engine with the solver, or with NoCommitmentUseCheck.
+	solve := func(opts []TestEngineOption) (int, error) {
+		return isSolved(&unusedCommitmentCircuit{}, &unusedCommitmentCircuit{X: 1}, ecc.BN254.ScalarField(), opts...)
+	}
+	nbCommitments, err := solve(opt.engineOptions(backend.GROTH16, true))
+	assert.ErrorContains(t, err, "commitment 0 is not used")
+	assert.Equal(t, 1, nbCommitments)
+	_, err = solve(opt.engineOptions(backend.GROTH16, false))
+	assert.NoError(t, err)
+	require.NoError(t, NoCommitmentUseCheck()(&opt))
+	_, err = solve(opt.engineOptions(backend.GROTH16, true))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,332 @@
+// Package debugger provides an interactive debugger for the constraint system
+// solver.
+//
+// The debugger runs the solver of a compiled constraint system on a witness
+// and pauses it on breakpoints: when a given wire is assigned, or when an
+// instruction adds a constraint whose recorded call stack contains a given
+// function. While paused, the solved values can be inspected and linear
+// expressions of wires can be evaluated, programmatically or through a simple
+// command line interface (see [Debugger.REPL]).
+//
+// The call stacks are the ones recorded in the debug information of the
+// constraint system, that is for assertions and, when compiling with the
+// "debug" build tag, in more details.
+//
+// This package is meant for debugging circuits and is experimental.
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// ErrAborted is returned by the solver when the debugging session is closed
+// before solving completed.
+var ErrAborted = errors.New("debugging session aborted")
+
+// Stop describes why the solver paused.
+type Stop struct {
+	// Instruction is the index of the instruction which was just processed.
+	Instruction int
+	// Reason is a human readable reason of the stop.
+	Reason string
+	// Err is the error returned when processing the instruction, if any (for
+	// example an unsatisfied constraint). Solving fails when resumed.
+	Err error
+}
+
+func (s *Stop) String() string {
+	if s.Err != nil {
+		return fmt.Sprintf("instruction %d: %s: %v", s.Instruction, s.Reason, s.Err)
+	}
+	return fmt.Sprintf("instruction %d: %s", s.Instruction, s.Reason)
+}
+
+type event struct {
+	stop *Stop
+	err  error // solving error, when stop is nil
+}
+
+// Debugger is a debugging session of the constraint system solver. It is not
+// thread safe.
+type Debugger struct {
+	ccs      constraint.ConstraintSystem
+	witness  witness.Witness
+	opts     []solver.Option
+	nbInputs int
+
+	// breakpoints
+	wireBreaks     map[int]bool // wire id -> already assigned
+	functionBreaks []string
+	stepping       bool
+
+	// solver go routine
+	chEvents chan event
+	chResume chan bool
+	state    solver.State // set while paused
+	current  *Stop
+	finished bool
+	err      error
+
+	constraints []constraintInfo // lazily parsed constraints
+}
+
+// New returns a new debugging session solving ccs with the given full witness
+// and solver options. The solver is started by the first call to
+// [Debugger.Continue] or [Debugger.Step].
+func New(ccs constraint.ConstraintSystem, fullWitness witness.Witness, opts ...solver.Option) *Debugger {
+	return &Debugger{
+		ccs:        ccs,
+		witness:    fullWitness,
+		opts:       opts,
+		nbInputs:   ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables(),
+		wireBreaks: make(map[int]bool),
+	}
+}
+
+// BreakOnWire pauses the solver after the instruction which assigns the wire.
+// Public and secret inputs are assigned before solving starts and can't be
+// used as breakpoints.
+func (d *Debugger) BreakOnWire(wireID int) error {
+	if wireID < d.nbInputs {
+		return fmt.Errorf("wire %d is an input of the circuit", wireID)
+	}
+	if wireID >= d.nbInputs+d.ccs.GetNbInternalVariables() {
+		return fmt.Errorf("wire %d does not exist", wireID)
+	}
+	d.wireBreaks[wireID] = false
+	return nil
+}
+
+// BreakOnFunction pauses the solver after each instruction adding a
+// constraint whose recorded call stack contains a function with the given
+// name (for example "(*Circuit).Define" or "bits.ToBinary").
+func (d *Debugger) BreakOnFunction(name string) {
+	d.functionBreaks = append(d.functionBreaks, name)
+}
+
+// Continue resumes (or starts) solving until the next breakpoint or until an
+// instruction fails, in which case the returned [Stop] describes why the
+// solver paused. When solving completes, it returns a nil Stop and the
+// solving error, if any.
+func (d *Debugger) Continue() (*Stop, error) {
+	return d.resume(false)
+}
+
+// Step resumes (or starts) solving and pauses after the next instruction.
+func (d *Debugger) Step() (*Stop, error) {
+	return d.resume(true)
+}
+
+// Current returns the current stop, or nil if the solver is not paused.
+func (d *Debugger) Current() *Stop {
+	return d.current
+}
+
+// Finished returns true if solving completed (successfully or not).
+func (d *Debugger) Finished() bool {
+	return d.finished
+}
+
+// Close aborts the solver if it is paused. The debugger can't be resumed
+// afterwards.
+func (d *Debugger) Close() {
+	if d.finished || d.chEvents == nil {
+		d.finished = true
+		return
+	}
+	d.state, d.current = nil, nil
+	d.chResume <- false
+	ev := <-d.chEvents
+	d.finished, d.err = true, ev.err
+}
+
+func (d *Debugger) resume(step bool) (*Stop, error) {
+	if d.finished {
+		return nil, d.err
+	}
+	d.stepping = step
+	d.state, d.current = nil, nil
+	if d.chEvents == nil {
+		d.start()
+	} else {
+		d.chResume <- true
+	}
+	ev := <-d.chEvents
+	if ev.stop == nil {
+		d.finished, d.err = true, ev.err
+		return nil, ev.err
+	}
+	d.current = ev.stop
+	return ev.stop, nil
+}
+
+func (d *Debugger) start() {
+	d.chEvents = make(chan event)
+	d.chResume = make(chan bool)
+	opts := make([]solver.Option, len(d.opts), len(d.opts)+1)
+	copy(opts, d.opts)
+	opts = append(opts, solver.WithInstructionHook(d.hook))
+	go func() {
+		_, err := d.ccs.Solve(d.witness, opts...)
+		d.chEvents <- event{err: err}
+	}()
+}
+
+// hook is called by the solver go routine after each instruction. It blocks
+// while the solver is paused.
+func (d *Debugger) hook(instructionID int, state solver.State, err error) error {
+	reason := d.breakReason(instructionID, state, err)
+	if reason == "" {
+		return nil
+	}
+	d.state = state
+	d.chEvents <- event{stop: &Stop{Instruction: instructionID, Reason: reason, Err: err}}
+	if resume := <-d.chResume; !resume {
+		return ErrAborted
+	}
+	return nil
+}
+
+func (d *Debugger) breakReason(instructionID int, state solver.State, err error) string {
+	var reasons []string
+	if err != nil {
+		reasons = append(reasons, "instruction failed")
+	}
+	for wireID, assigned := range d.wireBreaks {
+		if assigned {
+			continue
+		}
+		if _, ok := state.Value(wireID); ok {
+			d.wireBreaks[wireID] = true
+			reasons = append(reasons, fmt.Sprintf("wire %s assigned", d.wireName(wireID)))
+		}
+	}
+	if len(d.functionBreaks) > 0 {
+		first, last := d.constraintRange(instructionID)
+	search:
+		for cID := first; cID < last; cID++ {
+			for _, frame := range d.ccs.GetCallStack(cID) {
+				for _, name := range d.functionBreaks {
+					if strings.Contains(frame.Function, name) {
+						reasons = append(reasons, fmt.Sprintf("constraint %d added by %s", cID, frame.Function))
+						break search
+					}
+				}
+			}
+		}
+	}
+	if len(reasons) == 0 && d.stepping {
+		reasons = append(reasons, "step")
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// constraintRange returns the range [first, last) of the constraints added by
+// the instruction.
+func (d *Debugger) constraintRange(instructionID int) (first, last int) {
+	first = int(d.ccs.GetInstruction(instructionID).ConstraintOffset)
+	last = d.ccs.GetNbConstraints()
+	if instructionID+1 < d.ccs.GetNbInstructions() {
+		last = int(d.ccs.GetInstruction(instructionID + 1).ConstraintOffset)
+	}
+	return
+}
+
+// Value returns the value of the wire and true if the solver is paused and
+// the wire is solved.
+func (d *Debugger) Value(wireID int) (*big.Int, bool) {
+	if d.state == nil {
+		return nil, false
+	}
+	return d.state.Value(wireID)
+}
+
+// Describe returns a description of the instruction: the constraints it
+// adds, the current values of their wires and the call stacks recorded with
+// them.
+func (d *Debugger) Describe(instructionID int) string {
+	if instructionID < 0 || instructionID >= d.ccs.GetNbInstructions() {
+		return fmt.Sprintf("instruction %d does not exist", instructionID)
+	}
+	var sb strings.Builder
+	first, last := d.constraintRange(instructionID)
+	fmt.Fprintf(&sb, "instruction %d: %d constraint(s)\n", instructionID, last-first)
+
+	constraints := d.parseConstraints()
+	for cID := first; cID < last && cID < len(constraints); cID++ {
+		fmt.Fprintf(&sb, "constraint %d: %s\n", cID, constraints[cID].str)
+		for _, wireID := range constraints[cID].wires {
+			if v, ok := d.Value(wireID); ok {
+				fmt.Fprintf(&sb, "\t%s = %s\n", d.wireName(wireID), v)
+			} else {
+				fmt.Fprintf(&sb, "\t%s = <unsolved>\n", d.wireName(wireID))
+			}
+		}
+		for _, frame := range d.ccs.GetCallStack(cID) {
+			fmt.Fprintf(&sb, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+	}
+	return sb.String()
+}
+
+// constraintInfo is the string representation of a constraint and the list of
+// its wires.
+type constraintInfo struct {
+	str   string
+	wires []int
+}
+
+func (d *Debugger) parseConstraints() []constraintInfo {
+	if d.constraints != nil {
+		return d.constraints
+	}
+	addWires := func(c *constraintInfo, wires ...int) {
+	next:
+		for _, w := range wires {
+			for _, w2 := range c.wires {
+				if w == w2 {
+					continue next
+				}
+			}
+			c.wires = append(c.wires, w)
+		}
+	}
+	switch ccs := d.ccs.(type) {
+	case constraint.R1CS:
+		for _, c := range ccs.GetR1Cs() {
+			info := constraintInfo{str: c.String(ccs)}
+			for _, l := range []constraint.LinearExpression{c.L, c.R, c.O} {
+				for _, t := range l {
+					if !t.IsConstant() {
+						addWires(&info, t.WireID())
+					}
+				}
+			}
+			d.constraints = append(d.constraints, info)
+		}
+	case constraint.SparseR1CS:
+		for _, c := range ccs.GetSparseR1Cs() {
+			info := constraintInfo{str: c.String(ccs)}
+			for _, t := range []constraint.Term{{CID: c.QL, VID: c.XA}, {CID: c.QR, VID: c.XB}, {CID: c.QO, VID: c.XC}} {
+				if ccs.CoeffToString(int(t.CID)) != "0" {
+					addWires(&info, t.WireID())
+				}
+			}
+			d.constraints = append(d.constraints, info)
+		}
+	}
+	return d.constraints
+}
+
+// wireName returns the name of the wire, as displayed in the debug
+// information of the constraint system.
+func (d *Debugger) wireName(wireID int) string {
+	return d.ccs.VariableToString(wireID)
+}
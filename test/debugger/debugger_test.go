@@ -0,0 +1,121 @@
+package debugger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(api frontend.API) error {
+	x2 := api.Mul(c.X, c.X)
+	api.AssertIsEqual(api.Mul(x2, c.X), c.Y)
+	return nil
+}
+
+func newDebugger(t *testing.T, newBuilder frontend.NewBuilder, assignment *cubeCircuit) *Debugger {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &cubeCircuit{})
+	require.NoError(t, err)
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	return New(ccs, w)
+}
+
+func TestBreakOnWire(t *testing.T) {
+	assert := require.New(t)
+	d := newDebugger(t, r1cs.NewBuilder, &cubeCircuit{X: 3, Y: 27})
+
+	// wires: ONE, Y, X, X*X
+	assert.Error(d.BreakOnWire(2))
+	assert.NoError(d.BreakOnWire(3))
+
+	stop, err := d.Continue()
+	assert.NoError(err)
+	assert.NotNil(stop)
+	assert.Contains(stop.Reason, "v0 assigned")
+
+	v, ok := d.Value(3)
+	assert.True(ok)
+	assert.Equal("9", v.String())
+
+	v, err = d.Eval("2*v0 - X + w1 - 1")
+	assert.NoError(err)
+	assert.Equal("41", v.String())
+	_, err = d.Eval("v0 * X")
+	assert.Error(err)
+
+	stop, err = d.Continue()
+	assert.NoError(err)
+	assert.Nil(stop)
+	assert.True(d.Finished())
+}
+
+func TestStepUntilFailure(t *testing.T) {
+	assert := require.New(t)
+	d := newDebugger(t, scs.NewBuilder, &cubeCircuit{X: 3, Y: 28})
+
+	var last *Stop
+	for {
+		stop, err := d.Step()
+		if stop == nil {
+			assert.Error(err)
+			break
+		}
+		last = stop
+	}
+	assert.NotNil(last)
+	assert.Error(last.Err)
+	assert.Contains(last.Reason, "instruction failed")
+}
+
+type divCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *divCircuit) Define(api frontend.API) error {
+	// division records debug information even without the debug build tag
+	api.AssertIsEqual(api.Div(c.X, c.Y), 3)
+	return nil
+}
+
+func TestBreakOnFunction(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &divCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&divCircuit{X: 12, Y: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	d := New(ccs, w)
+	d.BreakOnFunction("divCircuit).Define")
+
+	stop, err := d.Continue()
+	assert.NoError(err)
+	assert.NotNil(stop)
+	assert.Contains(stop.Reason, "divCircuit).Define")
+	assert.Contains(d.Describe(stop.Instruction), "debugger_test.go")
+	d.Close()
+
+	_, err = d.Continue()
+	assert.ErrorIs(err, ErrAborted)
+}
+
+func TestREPL(t *testing.T) {
+	assert := require.New(t)
+	d := newDebugger(t, r1cs.NewBuilder, &cubeCircuit{X: 3, Y: 27})
+
+	in := strings.NewReader("b w v0\nc\np v0 + 1\ni\nc\n")
+	var out bytes.Buffer
+	assert.NoError(d.REPL(in, &out))
+	assert.Contains(out.String(), "(gnark) 10\n")
+	assert.Contains(out.String(), "v0 = 9")
+	assert.Contains(out.String(), "solving succeeded")
+}
@@ -0,0 +1,136 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var errNotPaused = errors.New("solver is not paused")
+
+// Eval evaluates a linear expression of wires on the current solved values,
+// modulo the field. The expression is a sum of terms separated by '+' or '-',
+// where each term is either a constant, a wire or the product of a constant
+// and a wire:
+//
+//	2*w5 - X + 0x10*v3 + 1
+//
+// Wires are referenced by their absolute id ("w5"), by their name as in the
+// debug information of the constraint system ("v3" for the fourth internal
+// wire) or, for inputs, by their name in the witness ("X", "Y_0").
+func (d *Debugger) Eval(expr string) (*big.Int, error) {
+	if d.state == nil {
+		return nil, errNotPaused
+	}
+	field := d.ccs.Field()
+
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+	res := new(big.Int)
+	for _, t := range terms {
+		v, err := d.evalTerm(t.str)
+		if err != nil {
+			return nil, err
+		}
+		if t.neg {
+			res.Sub(res, v)
+		} else {
+			res.Add(res, v)
+		}
+	}
+	return res.Mod(res, field), nil
+}
+
+type term struct {
+	str string
+	neg bool
+}
+
+// splitTerms splits the expression on top level '+' and '-' signs.
+func splitTerms(expr string) ([]term, error) {
+	var res []term
+	neg := false
+	start := 0
+	flush := func(end int) error {
+		s := strings.TrimSpace(expr[start:end])
+		if s == "" {
+			return fmt.Errorf("invalid expression %q: empty term", expr)
+		}
+		res = append(res, term{str: s, neg: neg})
+		return nil
+	}
+	for i, c := range expr {
+		if c != '+' && c != '-' {
+			continue
+		}
+		if strings.TrimSpace(expr[start:i]) == "" && len(res) == 0 && c == '-' {
+			// leading minus sign
+			neg = !neg
+			start = i + 1
+			continue
+		}
+		if err := flush(i); err != nil {
+			return nil, err
+		}
+		neg = c == '-'
+		start = i + 1
+	}
+	if err := flush(len(expr)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (d *Debugger) evalTerm(s string) (*big.Int, error) {
+	factors := strings.Split(s, "*")
+	if len(factors) > 2 {
+		return nil, fmt.Errorf("invalid term %q: expression must be linear", s)
+	}
+	res := big.NewInt(1)
+	nbWires := 0
+	for _, f := range factors {
+		f = strings.TrimSpace(f)
+		if c, ok := new(big.Int).SetString(f, 0); ok {
+			res.Mul(res, c)
+			continue
+		}
+		wireID, err := d.resolveWire(f)
+		if err != nil {
+			return nil, err
+		}
+		if nbWires++; nbWires > 1 {
+			return nil, fmt.Errorf("invalid term %q: expression must be linear", s)
+		}
+		v, ok := d.state.Value(wireID)
+		if !ok {
+			return nil, fmt.Errorf("wire %s is not solved", f)
+		}
+		res.Mul(res, v)
+	}
+	return res, nil
+}
+
+// resolveWire returns the absolute id of the wire with the given name.
+func (d *Debugger) resolveWire(name string) (int, error) {
+	nbWires := d.nbInputs + d.ccs.GetNbInternalVariables()
+	if strings.HasPrefix(name, "w") {
+		if id, err := strconv.Atoi(name[1:]); err == nil && id >= 0 && id < nbWires {
+			return id, nil
+		}
+	}
+	if strings.HasPrefix(name, "v") {
+		if id, err := strconv.Atoi(name[1:]); err == nil && id >= 0 && d.nbInputs+id < nbWires {
+			return d.nbInputs + id, nil
+		}
+	}
+	for id := 0; id < d.nbInputs; id++ {
+		if d.wireName(id) == name {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown wire %q", name)
+}
@@ -0,0 +1,113 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const replHelp = `commands:
+  break wire <wire>     pause when the wire is assigned (b w)
+  break func <name>     pause on constraints added by the function (b f)
+  continue              resume solving until the next breakpoint (c)
+  step                  resume solving until the next instruction (s)
+  print <expr>          evaluate a linear expression of wires (p)
+  info [instruction]    describe the current or given instruction (i)
+  quit                  abort solving and exit (q)
+  help                  print this help (h)
+`
+
+// REPL runs a command line interface on the debugging session, reading
+// commands from in and writing results to out, until the input is exhausted,
+// solving completes or the "quit" command is entered. The session is closed
+// when REPL returns. It returns the solving error, if any.
+//
+// Wires are referenced as in [Debugger.Eval]. Type "help" for the list of
+// commands.
+func (d *Debugger) REPL(in io.Reader, out io.Writer) error {
+	defer d.Close()
+	scanner := bufio.NewScanner(in)
+	for {
+		if d.finished {
+			return d.err
+		}
+		fmt.Fprint(out, "(gnark) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		cmd, args := fields[0], fields[1:]
+		switch cmd {
+		case "break", "b":
+			if len(args) != 2 {
+				fmt.Fprintln(out, "usage: break wire <wire> | break func <name>")
+				continue
+			}
+			switch args[0] {
+			case "wire", "w":
+				wireID, err := d.resolveWire(args[1])
+				if err == nil {
+					err = d.BreakOnWire(wireID)
+				}
+				if err != nil {
+					fmt.Fprintln(out, err)
+				}
+			case "func", "f":
+				d.BreakOnFunction(args[1])
+			default:
+				fmt.Fprintln(out, "usage: break wire <wire> | break func <name>")
+			}
+		case "continue", "c", "step", "s":
+			var stop *Stop
+			var err error
+			if cmd == "step" || cmd == "s" {
+				stop, err = d.Step()
+			} else {
+				stop, err = d.Continue()
+			}
+			switch {
+			case stop != nil:
+				fmt.Fprintln(out, stop)
+			case err != nil:
+				fmt.Fprintln(out, "solving failed:", err)
+			default:
+				fmt.Fprintln(out, "solving succeeded")
+			}
+		case "print", "p":
+			v, err := d.Eval(strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			fmt.Fprintln(out, v)
+		case "info", "i":
+			instructionID := -1
+			if len(args) > 0 {
+				id, err := strconv.Atoi(args[0])
+				if err != nil {
+					fmt.Fprintln(out, "usage: info [instruction]")
+					continue
+				}
+				instructionID = id
+			} else if d.current != nil {
+				instructionID = d.current.Instruction
+			} else {
+				fmt.Fprintln(out, errNotPaused)
+				continue
+			}
+			fmt.Fprint(out, d.Describe(instructionID))
+		case "quit", "q":
+			return nil
+		case "help", "h":
+			fmt.Fprint(out, replHelp)
+		default:
+			fmt.Fprintf(out, "unknown command %q, type help for the list of commands\n", cmd)
+		}
+	}
+}
@@ -17,11 +17,13 @@ limitations under the License.
 package test
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -39,9 +41,11 @@ import (
 	"github.com/consensys/gnark-crypto/field/pool"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/internal/utils"
+	"github.com/rs/zerolog"
 )
 
 // engine implements frontend.API
@@ -59,6 +63,27 @@ type engine struct {
 	kvstore.Store
 	blueprints        []constraint.Blueprint
 	internalVariables []*big.Int
+
+	// hintOverrides replaces hint functions given to NewHint, keyed by the hint
+	// ID of the replaced function.
+	hintOverrides map[solver.HintID]solver.Hint
+
+	// commitments maps the values returned by Commit to the commitment depth,
+	// to record when a commitment is an operand of the API if
+	// checkCommitmentUse is set; see WithCommitmentUseCheck.
+	nbCommitments      int
+	commitments        map[*big.Int]int
+	commitmentUsed     []bool
+	checkCommitmentUse bool
+
+	// commitmentBackend is the backend whose commitments are modelled, see
+	// WithCommitmentBackend.
+	commitmentBackend backend.ID
+
+	// native is set by WithNativeField, the values are then stored in
+	// *constraint.Element allocated by elements.
+	native   constraint.Field
+	elements elementAllocator
 }
 
 // TestEngineOption defines an option for the test engine.
@@ -88,6 +113,59 @@ func WithBackendProverOptions(opts ...backend.ProverOption) TestEngineOption {
 	}
 }
 
+// WithCommitmentUseCheck is a test engine option which makes the engine
+// return an error if a commitment returned by [frontend.Committer.Commit] is
+// not an operand of the API. The backends don't check it, but a commitment
+// which is not used in the circuit is usually a misuse of the Committer
+// interface, the randomness it provides being never applied.
+func WithCommitmentUseCheck() TestEngineOption {
+	return func(e *engine) error {
+		e.checkCommitmentUse = true
+		return nil
+	}
+}
+
+// WithCommitmentBackend is a test engine option which makes the engine compute
+// the commitments as the builder of the given backend does:
+//
+//   - [backend.GROTH16] commits to the set of the committed wires, so that the
+//     commitment doesn't depend on the order nor on the repetitions of the
+//     committed variables. The engine, which has no wires, commits to the set
+//     of the committed values.
+//   - [backend.PLONK] adds a constraint per committed variable, so that the
+//     commitment depends on the order and on the repetitions of the committed
+//     variables.
+//
+// If not set, the commitments are computed as for PLONK. In both backends, the
+// commitment depends on the number of previous commitments.
+func WithCommitmentBackend(b backend.ID) TestEngineOption {
+	return func(e *engine) error {
+		if b != backend.GROTH16 && b != backend.PLONK {
+			return fmt.Errorf("no commitment for backend %s", b)
+		}
+		e.commitmentBackend = b
+		return nil
+	}
+}
+
+// WithResolver is a test engine option which gives the context and the
+// resolver to the resolver hints, as [solver.WithResolver] for the solver.
+func WithResolver(ctx context.Context, resolver solver.Resolver) TestEngineOption {
+	return withHintOverride(solver.GetHintID(solver.ResolveHint), solver.BindResolver(ctx, resolver))
+}
+
+// withHintOverride is a test engine option which forces the engine to use
+// provided hint function for given id.
+func withHintOverride(id solver.HintID, f solver.Hint) TestEngineOption {
+	return func(e *engine) error {
+		if e.hintOverrides == nil {
+			e.hintOverrides = make(map[solver.HintID]solver.Hint)
+		}
+		e.hintOverrides[id] = f
+		return nil
+	}
+}
+
 // IsSolved returns an error if the test execution engine failed to execute the given circuit
 // with provided witness as input.
 //
@@ -95,6 +173,12 @@ func WithBackendProverOptions(opts ...backend.ProverOption) TestEngineOption {
 //
 // This is an experimental feature.
 func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEngineOption) (err error) {
+	_, err = isSolved(circuit, witness, field, opts...)
+	return err
+}
+
+// isSolved is IsSolved, also returning the number of commitments computed.
+func isSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEngineOption) (nbCommitments int, err error) {
 	e := &engine{
 		curveID:   utils.FieldToCurve(field),
 		q:         new(big.Int).Set(field),
@@ -103,7 +187,7 @@ func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEng
 	}
 	for _, opt := range opts {
 		if err := opt(e); err != nil {
-			return fmt.Errorf("apply option: %w", err)
+			return 0, fmt.Errorf("apply option: %w", err)
 		}
 	}
 
@@ -123,6 +207,7 @@ func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEng
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v\n%s", r, string(debug.Stack()))
 		}
+		nbCommitments = e.nbCommitments
 	}()
 
 	log := logger.Logger()
@@ -137,10 +222,15 @@ func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEng
 	}
 
 	if err = c.Define(e); err != nil {
-		return fmt.Errorf("define: %w", err)
+		return 0, fmt.Errorf("define: %w", err)
 	}
 	if err = callDeferred(e); err != nil {
-		return fmt.Errorf("deferred: %w", err)
+		return 0, fmt.Errorf("deferred: %w", err)
+	}
+	for i, used := range e.commitmentUsed {
+		if !used {
+			return 0, fmt.Errorf("commitment %d is not used in the circuit", i)
+		}
 	}
 
 	log.Debug().Uint64("add", cptAdd).
@@ -166,6 +256,10 @@ var cptAdd, cptMul, cptSub, cptToBinary, cptFromBinary, cptAssertIsEqual uint64
 
 func (e *engine) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	atomic.AddUint64(&cptAdd, 1)
+	if e.native != nil {
+		atomic.AddUint64(&cptAdd, uint64(len(in)))
+		return e.nativeAdd(i1, i2, in)
+	}
 	res := new(big.Int)
 	res.Add(e.toBigInt(i1), e.toBigInt(i2))
 	for i := 0; i < len(in); i++ {
@@ -177,6 +271,9 @@ func (e *engine) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend
 }
 
 func (e *engine) MulAcc(a, b, c frontend.Variable) frontend.Variable {
+	if e.native != nil {
+		return e.nativeMulAcc(a, b, c)
+	}
 	bc := pool.BigInt.Get()
 	bc.Mul(e.toBigInt(b), e.toBigInt(c))
 
@@ -190,6 +287,10 @@ func (e *engine) MulAcc(a, b, c frontend.Variable) frontend.Variable {
 
 func (e *engine) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	atomic.AddUint64(&cptSub, 1)
+	if e.native != nil {
+		atomic.AddUint64(&cptSub, uint64(len(in)))
+		return e.nativeSub(i1, i2, in)
+	}
 	res := new(big.Int)
 	res.Sub(e.toBigInt(i1), e.toBigInt(i2))
 	for i := 0; i < len(in); i++ {
@@ -201,6 +302,9 @@ func (e *engine) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend
 }
 
 func (e *engine) Neg(i1 frontend.Variable) frontend.Variable {
+	if e.native != nil {
+		return e.nativeNeg(i1)
+	}
 	res := new(big.Int)
 	res.Neg(e.toBigInt(i1))
 	res.Mod(res, e.modulus())
@@ -209,6 +313,10 @@ func (e *engine) Neg(i1 frontend.Variable) frontend.Variable {
 
 func (e *engine) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	atomic.AddUint64(&cptMul, 1)
+	if e.native != nil {
+		atomic.AddUint64(&cptMul, uint64(len(in)))
+		return e.nativeMul(i1, i2, in)
+	}
 	b2 := e.toBigInt(i2)
 	if len(in) == 0 && b2.IsUint64() && b2.Uint64() <= 1 {
 		// special path to avoid useless allocations
@@ -230,6 +338,9 @@ func (e *engine) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend
 }
 
 func (e *engine) Div(i1, i2 frontend.Variable) frontend.Variable {
+	if e.native != nil {
+		return e.nativeDiv(i1, i2)
+	}
 	res := new(big.Int)
 	if res.ModInverse(e.toBigInt(i2), e.modulus()) == nil {
 		panic("no inverse")
@@ -254,6 +365,9 @@ func (e *engine) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
 }
 
 func (e *engine) Inverse(i1 frontend.Variable) frontend.Variable {
+	if e.native != nil {
+		return e.nativeInverse(i1)
+	}
 	res := new(big.Int)
 	if res.ModInverse(e.toBigInt(i1), e.modulus()) == nil {
 		panic("no inverse")
@@ -446,6 +560,10 @@ func (e *engine) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 
 func (e *engine) AssertIsEqual(i1, i2 frontend.Variable) {
 	atomic.AddUint64(&cptAssertIsEqual, 1)
+	if e.native != nil {
+		e.nativeAssertIsEqual(i1, i2)
+		return
+	}
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	if b1.Cmp(b2) != 0 {
 		panic(fmt.Sprintf("[assertIsEqual] %s == %s", b1.String(), b2.String()))
@@ -453,6 +571,10 @@ func (e *engine) AssertIsEqual(i1, i2 frontend.Variable) {
 }
 
 func (e *engine) AssertIsDifferent(i1, i2 frontend.Variable) {
+	if e.native != nil {
+		e.nativeAssertIsDifferent(i1, i2)
+		return
+	}
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	if b1.Cmp(b2) == 0 {
 		panic(fmt.Sprintf("[assertIsDifferent] %s != %s", b1.String(), b2.String()))
@@ -484,6 +606,38 @@ func (e *engine) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variabl
 	}
 }
 
+func (e *engine) AssertIsEqualWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	e.withMessage(func() { e.AssertIsEqual(i1, i2) }, format, args)
+}
+
+func (e *engine) AssertIsDifferentWithMessage(i1, i2 frontend.Variable, format string, args ...interface{}) {
+	e.withMessage(func() { e.AssertIsDifferent(i1, i2) }, format, args)
+}
+
+func (e *engine) AssertIsBooleanWithMessage(i1 frontend.Variable, format string, args ...interface{}) {
+	e.withMessage(func() { e.AssertIsBoolean(i1) }, format, args)
+}
+
+func (e *engine) AssertIsLessOrEqualWithMessage(v frontend.Variable, bound frontend.Variable, format string, args ...interface{}) {
+	e.withMessage(func() { e.AssertIsLessOrEqual(v, bound) }, format, args)
+}
+
+// CompilerAssert fails if condition is not 1. The engine doesn't track which
+// variables are constants, the condition is checked with its value.
+func (e *engine) CompilerAssert(condition frontend.Variable, format string, args ...interface{}) {
+	cs.CompilerAssert(e.toBigInt(condition), true, format, args)
+}
+
+// withMessage prefixes the failure of assert with the formatted message.
+func (e *engine) withMessage(assert func(), format string, args []interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Sprintf("%s: %v", fmt.Sprintf(format, args...), r))
+		}
+	}()
+	assert()
+}
+
 func (e *engine) Println(a ...frontend.Variable) {
 	var sbb strings.Builder
 	sbb.WriteString("(test.engine) ")
@@ -503,6 +657,46 @@ func (e *engine) Println(a ...frontend.Variable) {
 	fmt.Println(sbb.String())
 }
 
+func (e *engine) Log(keyvals ...frontend.Variable) {
+	e.log(zerolog.DebugLevel, keyvals)
+}
+
+func (e *engine) LogWithLevel(level zerolog.Level, keyvals ...frontend.Variable) {
+	e.log(level, keyvals)
+}
+
+func (e *engine) log(level zerolog.Level, keyvals []frontend.Variable) {
+	if len(keyvals)%2 != 0 {
+		panic("Log expects key-value pairs")
+	}
+	var sbb strings.Builder
+	sbb.WriteString("(test.engine) ")
+
+	// prefix log line with file.go:line of the caller of Log
+	if _, file, line, ok := runtime.Caller(2); ok {
+		sbb.WriteString(filepath.Base(file))
+		sbb.WriteByte(':')
+		sbb.WriteString(strconv.Itoa(line))
+		sbb.WriteByte(' ')
+	}
+	if level != zerolog.DebugLevel {
+		sbb.WriteString(level.String())
+		sbb.WriteByte(' ')
+	}
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("Log key %v is not a string", keyvals[i]))
+		}
+		sbb.WriteString(key)
+		sbb.WriteByte('=')
+		e.print(&sbb, keyvals[i+1])
+		sbb.WriteByte(' ')
+	}
+	fmt.Println(sbb.String())
+}
+
 func (e *engine) print(sbb *strings.Builder, x interface{}) {
 	switch v := x.(type) {
 	case string:
@@ -517,7 +711,7 @@ func (e *engine) print(sbb *strings.Builder, x interface{}) {
 		}
 		sbb.WriteRune(']')
 	default:
-		i := e.toBigInt(v)
+		i := e.value(v)
 		var iAsNeg big.Int
 		iAsNeg.Sub(i, e.q)
 		if iAsNeg.IsInt64() {
@@ -534,10 +728,16 @@ func (e *engine) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variab
 		return nil, fmt.Errorf("hint function must return at least one output")
 	}
 
+	if e.hintOverrides != nil {
+		if override, ok := e.hintOverrides[solver.GetHintID(f)]; ok {
+			f = override
+		}
+	}
+
 	in := make([]*big.Int, len(inputs))
 
 	for i := 0; i < len(inputs); i++ {
-		in[i] = e.toBigInt(inputs[i])
+		in[i] = e.value(inputs[i])
 	}
 	res := make([]*big.Int, nbOutputs)
 	for i := range res {
@@ -589,12 +789,28 @@ func (e *engine) MarkBoolean(v frontend.Variable) {
 	}
 }
 
+// toBigInt returns the value of i1 and records the use of commitments, see
+// WithCommitmentUseCheck.
 func (e *engine) toBigInt(i1 frontend.Variable) *big.Int {
+	if e.commitments != nil {
+		if v, ok := i1.(*big.Int); ok {
+			if depth, ok := e.commitments[v]; ok {
+				e.commitmentUsed[depth] = true
+			}
+		}
+	}
+	return e.value(i1)
+}
+
+// value returns the value of i1.
+func (e *engine) value(i1 frontend.Variable) *big.Int {
 	switch vv := i1.(type) {
 	case *big.Int:
 		return vv
 	case big.Int:
 		return &vv
+	case *constraint.Element:
+		return e.native.ToBigInt(*vv)
 	default:
 		b := utils.FromInterface(i1)
 		b.Mod(&b, e.modulus())
@@ -672,12 +888,31 @@ func (e *engine) Compiler() frontend.Compiler {
 	return e
 }
 
+// Commit implements [frontend.Committer]. As in the backends, the commitments
+// depend on their depth (the number of previous commitments), so committing
+// twice to the same values yields different commitments. See
+// WithCommitmentBackend for the differences between the backends and
+// WithCommitmentUseCheck to check that the commitments are used.
 func (e *engine) Commit(v ...frontend.Variable) (frontend.Variable, error) {
+	if len(v) == 0 {
+		return nil, fmt.Errorf("must commit to at least one variable")
+	}
+	committed := make([]*big.Int, len(v))
+	for i := range v {
+		committed[i] = e.toBigInt(v[i])
+	}
+	if e.commitmentBackend == backend.GROTH16 {
+		slices.SortFunc(committed, func(a, b *big.Int) int { return a.Cmp(b) })
+		committed = slices.CompactFunc(committed, func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+	}
+
+	depth := e.nbCommitments
+	e.nbCommitments++
 	nb := (e.FieldBitLen() + 7) / 8
 	buf := make([]byte, nb)
 	hasher := sha3.NewCShake128(nil, []byte("gnark test engine"))
-	for i := range v {
-		vs := e.toBigInt(v[i])
+	hasher.Write(big.NewInt(int64(depth)).FillBytes(buf))
+	for _, vs := range committed {
 		bs := vs.FillBytes(buf)
 		hasher.Write(bs)
 	}
@@ -689,6 +924,13 @@ func (e *engine) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 		// with tinyfield
 		res.SetUint64(1)
 	}
+	if e.checkCommitmentUse {
+		if e.commitments == nil {
+			e.commitments = make(map[*big.Int]int)
+		}
+		e.commitments[res] = depth
+		e.commitmentUsed = append(e.commitmentUsed, false)
+	}
 	return res, nil
 }
 
@@ -763,8 +1005,13 @@ func (e *engine) SetGkrInfo(info constraint.GkrInfo) error {
 func (e *engine) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.Int, aForDebug frontend.Variable) {
 	v := new(big.Int)
 	for i, b := range aBits {
-		bb, ok := b.(*big.Int)
-		if !ok {
+		var bb *big.Int
+		switch v := b.(type) {
+		case *big.Int:
+			bb = v
+		case *constraint.Element:
+			bb = e.value(v)
+		default:
 			panic("not big.Int bit")
 		}
 		if !bb.IsUint64() {
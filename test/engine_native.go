@@ -0,0 +1,157 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	cs_tinyfield "github.com/consensys/gnark/constraint/tinyfield"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/tinyfield"
+)
+
+// elementChunkSize is the number of values allocated at once by the engine in
+// native mode.
+const elementChunkSize = 1024
+
+// WithNativeField is a test engine option which makes the engine compute with
+// the field elements of the curve instead of big.Int, for testing large
+// circuits. The results of the arithmetic operations are field elements
+// allocated by chunks, and the assertions compare field elements, which
+// divides the memory and the time used by circuits of millions of
+// constraints. The other operations and the hints still use big.Int.
+//
+// The option returns an error if the field is not the scalar field of a
+// supported curve.
+func WithNativeField() TestEngineOption {
+	return func(e *engine) error {
+		e.native = nativeField(e.curveID, e.q)
+		if e.native == nil {
+			return fmt.Errorf("no native arithmetic for field %s", e.q)
+		}
+		return nil
+	}
+}
+
+// nativeField returns the arithmetic of the constraint systems for the field,
+// or nil if there is none.
+func nativeField(curveID ecc.ID, q *big.Int) constraint.Field {
+	switch curveID {
+	case ecc.BN254:
+		return cs_bn254.NewR1CS(0)
+	case ecc.BLS12_377:
+		return cs_bls12377.NewR1CS(0)
+	case ecc.BLS12_381:
+		return cs_bls12381.NewR1CS(0)
+	case ecc.BLS24_315:
+		return cs_bls24315.NewR1CS(0)
+	case ecc.BLS24_317:
+		return cs_bls24317.NewR1CS(0)
+	case ecc.BW6_633:
+		return cs_bw6633.NewR1CS(0)
+	case ecc.BW6_761:
+		return cs_bw6761.NewR1CS(0)
+	}
+	if q.Cmp(tinyfield.Modulus()) == 0 {
+		return cs_tinyfield.NewR1CS(0)
+	}
+	return nil
+}
+
+// elementAllocator allocates the values of the native mode by chunks. A chunk
+// is freed when all its values are unreachable.
+type elementAllocator struct {
+	chunk []constraint.Element
+}
+
+func (a *elementAllocator) new(v constraint.Element) *constraint.Element {
+	if len(a.chunk) == 0 {
+		a.chunk = make([]constraint.Element, elementChunkSize)
+	}
+	res := &a.chunk[0]
+	*res = v
+	a.chunk = a.chunk[1:]
+	return res
+}
+
+// toElement returns the value of i1 in native form and records the use of
+// commitments.
+func (e *engine) toElement(i1 frontend.Variable) constraint.Element {
+	switch vv := i1.(type) {
+	case *constraint.Element:
+		return *vv
+	case *big.Int:
+		return e.native.FromInterface(e.toBigInt(vv))
+	default:
+		return e.native.FromInterface(i1)
+	}
+}
+
+func (e *engine) nativeAdd(i1, i2 frontend.Variable, in []frontend.Variable) frontend.Variable {
+	res := e.native.Add(e.toElement(i1), e.toElement(i2))
+	for i := range in {
+		res = e.native.Add(res, e.toElement(in[i]))
+	}
+	return e.elements.new(res)
+}
+
+func (e *engine) nativeSub(i1, i2 frontend.Variable, in []frontend.Variable) frontend.Variable {
+	res := e.native.Sub(e.toElement(i1), e.toElement(i2))
+	for i := range in {
+		res = e.native.Sub(res, e.toElement(in[i]))
+	}
+	return e.elements.new(res)
+}
+
+func (e *engine) nativeMul(i1, i2 frontend.Variable, in []frontend.Variable) frontend.Variable {
+	res := e.native.Mul(e.toElement(i1), e.toElement(i2))
+	for i := range in {
+		res = e.native.Mul(res, e.toElement(in[i]))
+	}
+	return e.elements.new(res)
+}
+
+func (e *engine) nativeMulAcc(a, b, c frontend.Variable) frontend.Variable {
+	bc := e.native.Mul(e.toElement(b), e.toElement(c))
+	return e.elements.new(e.native.Add(e.toElement(a), bc))
+}
+
+func (e *engine) nativeNeg(i1 frontend.Variable) frontend.Variable {
+	return e.elements.new(e.native.Neg(e.toElement(i1)))
+}
+
+func (e *engine) nativeInverse(i1 frontend.Variable) frontend.Variable {
+	res, ok := e.native.Inverse(e.toElement(i1))
+	if !ok {
+		panic("no inverse")
+	}
+	return e.elements.new(res)
+}
+
+func (e *engine) nativeDiv(i1, i2 frontend.Variable) frontend.Variable {
+	inv, ok := e.native.Inverse(e.toElement(i2))
+	if !ok {
+		panic("no inverse")
+	}
+	return e.elements.new(e.native.Mul(e.toElement(i1), inv))
+}
+
+func (e *engine) nativeAssertIsEqual(i1, i2 frontend.Variable) {
+	if b1, b2 := e.toElement(i1), e.toElement(i2); b1 != b2 {
+		panic(fmt.Sprintf("[assertIsEqual] %s == %s", e.native.String(b1), e.native.String(b2)))
+	}
+}
+
+func (e *engine) nativeAssertIsDifferent(i1, i2 frontend.Variable) {
+	if b1, b2 := e.toElement(i1), e.toElement(i2); b1 == b2 {
+		panic(fmt.Sprintf("[assertIsDifferent] %s != %s", e.native.String(b1), e.native.String(b2)))
+	}
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/circuits"
 	"github.com/consensys/gnark/std/math/bits"
 )
 
@@ -101,3 +103,49 @@ func TestPreCompileHook(t *testing.T) {
 		t.Error("callback not called")
 	}
 }
+
+type messageCircuit struct {
+	A, B frontend.Variable
+}
+
+func (circuit *messageCircuit) Define(api frontend.API) error {
+	api.(frontend.MessageAsserter).AssertIsEqualWithMessage(circuit.A, circuit.B, "%d is not %s", circuit.A, "B")
+	return nil
+}
+
+func TestAssertionMessage(t *testing.T) {
+	err := IsSolved(&messageCircuit{}, &messageCircuit{A: 1, B: 1}, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = IsSolved(&messageCircuit{}, &messageCircuit{A: 2, B: 1}, ecc.BN254.ScalarField())
+	if err == nil || !strings.Contains(err.Error(), "2 is not B: [assertIsEqual] 2 == 1") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNativeField(t *testing.T) {
+	for name, tc := range circuits.Circuits {
+		curves := tc.Curves
+		if curves == nil {
+			curves = gnark.Curves()
+		}
+		for _, curve := range curves {
+			field := curve.ScalarField()
+			for _, w := range tc.ValidAssignments {
+				if err := IsSolved(tc.Circuit, w, field, WithNativeField()); err != nil {
+					t.Errorf("%s on %s: valid assignment rejected: %v", name, curve, err)
+				}
+			}
+			for _, w := range tc.InvalidAssignments {
+				if err := IsSolved(tc.Circuit, w, field, WithNativeField()); err == nil {
+					t.Errorf("%s on %s: invalid assignment accepted", name, curve)
+				}
+			}
+		}
+	}
+
+	if err := IsSolved(&hintCircuit{}, &hintCircuit{A: 0b1000, B: 0}, big.NewInt(101), WithNativeField()); err == nil {
+		t.Error("expected an error for a field without native arithmetic")
+	}
+}
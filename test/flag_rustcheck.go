@@ -0,0 +1,5 @@
+//go:build rustcheck
+
+package test
+
+const RustCheck = true
@@ -0,0 +1,5 @@
+//go:build !update_snapshots
+
+package test
+
+const updateSnapshotsFlag = false
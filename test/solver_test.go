@@ -119,7 +119,7 @@ func (p *permutter) permuteAndTest(index int) error {
 
 			// we have a unique permutation
 			var errorSystems [2]error
-			var errorEngines [2]error
+			var errorEngines [3]error
 
 			// 2 constraints systems
 			for k := 0; k < nbSystems; k++ {
@@ -134,15 +134,20 @@ func (p *permutter) permuteAndTest(index int) error {
 				copyWitnessFromVector(p.circuit, p.witness)
 				errorEngines[1] = isSolvedEngine(p.circuit, tinyfield.Modulus(), SetAllVariablesAsConstants())
 
+				copyWitnessFromVector(p.circuit, p.witness)
+				errorEngines[2] = isSolvedEngine(p.circuit, tinyfield.Modulus(), WithNativeField())
+
 			}
 			if (errorSystems[0] == nil) != (errorEngines[0] == nil) ||
 				(errorSystems[1] == nil) != (errorEngines[0] == nil) ||
-				(errorEngines[0] == nil) != (errorEngines[1] == nil) {
-				return fmt.Errorf("errSCS :%s\nerrR1CS :%s\nerrEngine(const=false): %s\nerrEngine(const=true): %s\nwitness: %s",
+				(errorEngines[0] == nil) != (errorEngines[1] == nil) ||
+				(errorEngines[0] == nil) != (errorEngines[2] == nil) {
+				return fmt.Errorf("errSCS :%s\nerrR1CS :%s\nerrEngine(const=false): %s\nerrEngine(const=true): %s\nerrEngine(native): %s\nwitness: %s",
 					formatError(errorSystems[0]),
 					formatError(errorSystems[1]),
 					formatError(errorEngines[0]),
 					formatError(errorEngines[1]),
+					formatError(errorEngines[2]),
 					formatWitness(p.witness))
 			}
 
@@ -9,7 +9,7 @@ import (
 
 type Option func(*config) error
 
-// WithCacheDir enables the filesystem cache and sets the cache directory
+// WithFSCache enables the filesystem cache and sets the cache directory
 // to ~/.gnark/kzg by default.
 func WithFSCache() Option {
 	return func(opt *config) error {
@@ -18,6 +18,16 @@ func WithFSCache() Option {
 	}
 }
 
+// WithCacheDir enables the filesystem cache and sets the cache directory to
+// dir. It allows sharing the cached SRS between packages and test runs.
+func WithCacheDir(dir string) Option {
+	return func(opt *config) error {
+		opt.fsCache = true
+		opt.cacheDir = dir
+		return nil
+	}
+}
+
 type config struct {
 	fsCache  bool
 	cacheDir string
@@ -0,0 +1,298 @@
+package unsafekzg
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/internal/parallel"
+
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	kzg_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+)
+
+// srsChunkSize is the number of points computed at once, which bounds the
+// memory used on top of the SRS.
+const srsChunkSize = 1 << 20
+
+// NewSRSParallel returns a kzg SRS in canonical and lagrange form for a domain
+// of the given size, which must be a power of two. The canonical SRS has size+3
+// points, as needed by PLONK, and the lagrange SRS size points.
+//
+// The toxic waste τ is derived from the seed, so that the same seed gives the
+// same SRS: anyone knowing the seed can forge proofs. It is INSECURE and meant
+// for benchmarks of large circuits, where [NewSRS] is too slow. The points are
+// computed in parallel, by chunks, and the lagrange form is computed directly
+// from τ instead of with an FFT of the canonical form. The SRS isn't cached.
+func NewSRSParallel(curveID ecc.ID, size uint64, seed []byte) (canonical kzg.SRS, lagrange kzg.SRS, err error) {
+	if size < 2 || size&(size-1) != 0 {
+		return nil, nil, fmt.Errorf("size must be a power of two larger than 1, got %d", size)
+	}
+	h := sha256.Sum256(append([]byte("gnark/unsafekzg/tau"), seed...))
+	tau := new(big.Int).SetBytes(h[:])
+	tau.Mod(tau, curveID.ScalarField())
+
+	switch curveID {
+	case ecc.BN254:
+		var t fr_bn254.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bn254.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bn254.Generators()
+		srs, lsrs := &kzg_bn254.SRS{}, &kzg_bn254.SRS{}
+		srs.Pk.G1 = make([]bn254.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bn254.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bn254.BatchInvert, func(scalars []fr_bn254.Element) []bn254.G1Affine {
+			return bn254.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bn254.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bn254.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BLS12_381:
+		var t fr_bls12381.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bls12381.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bls12381.Generators()
+		srs, lsrs := &kzg_bls12381.SRS{}, &kzg_bls12381.SRS{}
+		srs.Pk.G1 = make([]bls12381.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bls12381.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bls12381.BatchInvert, func(scalars []fr_bls12381.Element) []bls12381.G1Affine {
+			return bls12381.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bls12381.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bls12381.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BLS12_377:
+		var t fr_bls12377.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bls12377.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bls12377.Generators()
+		srs, lsrs := &kzg_bls12377.SRS{}, &kzg_bls12377.SRS{}
+		srs.Pk.G1 = make([]bls12377.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bls12377.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bls12377.BatchInvert, func(scalars []fr_bls12377.Element) []bls12377.G1Affine {
+			return bls12377.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bls12377.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bls12377.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BW6_761:
+		var t fr_bw6761.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bw6761.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bw6761.Generators()
+		srs, lsrs := &kzg_bw6761.SRS{}, &kzg_bw6761.SRS{}
+		srs.Pk.G1 = make([]bw6761.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bw6761.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bw6761.BatchInvert, func(scalars []fr_bw6761.Element) []bw6761.G1Affine {
+			return bw6761.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bw6761.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bw6761.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BLS24_317:
+		var t fr_bls24317.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bls24317.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bls24317.Generators()
+		srs, lsrs := &kzg_bls24317.SRS{}, &kzg_bls24317.SRS{}
+		srs.Pk.G1 = make([]bls24317.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bls24317.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bls24317.BatchInvert, func(scalars []fr_bls24317.Element) []bls24317.G1Affine {
+			return bls24317.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bls24317.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bls24317.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BLS24_315:
+		var t fr_bls24315.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bls24315.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bls24315.Generators()
+		srs, lsrs := &kzg_bls24315.SRS{}, &kzg_bls24315.SRS{}
+		srs.Pk.G1 = make([]bls24315.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bls24315.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bls24315.BatchInvert, func(scalars []fr_bls24315.Element) []bls24315.G1Affine {
+			return bls24315.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bls24315.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bls24315.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	case ecc.BW6_633:
+		var t fr_bw6633.Element
+		t.SetBigInt(tau)
+		omega, err := fr_bw6633.Generator(size)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, _, g1, g2 := bw6633.Generators()
+		srs, lsrs := &kzg_bw6633.SRS{}, &kzg_bw6633.SRS{}
+		srs.Pk.G1 = make([]bw6633.G1Affine, size+3)
+		lsrs.Pk.G1 = make([]bw6633.G1Affine, size)
+		err = fillSRS(srs.Pk.G1, lsrs.Pk.G1, &t, &omega, fr_bw6633.BatchInvert, func(scalars []fr_bw6633.Element) []bw6633.G1Affine {
+			return bw6633.BatchScalarMultiplicationG1(&g1, scalars)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		srs.Vk.G1 = g1
+		srs.Vk.G2[0] = g2
+		srs.Vk.G2[1].ScalarMultiplication(&g2, tau)
+		srs.Vk.Lines[0] = bw6633.PrecomputeLines(srs.Vk.G2[0])
+		srs.Vk.Lines[1] = bw6633.PrecomputeLines(srs.Vk.G2[1])
+		lsrs.Vk = srs.Vk
+		return srs, lsrs, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported curve %s", curveID)
+	}
+}
+
+// element is implemented by the pointers to the scalar field elements of all
+// the curves.
+type element[E any] interface {
+	*E
+	SetOne() *E
+	SetUint64(uint64) *E
+	Mul(x, y *E) *E
+	Sub(x, y *E) *E
+	Exp(x E, k *big.Int) *E
+	Inverse(x *E) *E
+	IsOne() bool
+}
+
+// fillSRS sets canonical[i] = [τⁱ]G₁ and lagrange[i] = [Lᵢ(τ)]G₁, where Lᵢ is
+// the i-th Lagrange polynomial of the domain generated by ω:
+//
+//	Lᵢ(τ) = ωⁱ(τⁿ-1) / (n(τ-ωⁱ))
+//
+// batchMul multiplies the generator of G₁ by the scalars.
+func fillSRS[E any, PE element[E], G any](canonical, lagrange []G, tau, omega *E, batchInvert func([]E) []E, batchMul func([]E) []G) error {
+	n := len(lagrange)
+
+	// c = (τⁿ-1)/n
+	var c, one, nInv E
+	PE(&c).Exp(*tau, big.NewInt(int64(n)))
+	if PE(&c).IsOne() {
+		return errors.New("τ is in the domain")
+	}
+	PE(&one).SetOne()
+	PE(&c).Sub(&c, &one)
+	PE(&nInv).SetUint64(uint64(n))
+	PE(&nInv).Inverse(&nInv)
+	PE(&c).Mul(&c, &nInv)
+
+	scalars := make([]E, min(srsChunkSize, len(canonical)))
+	for start := 0; start < len(canonical); start += len(scalars) {
+		chunk := scalars[:min(len(scalars), len(canonical)-start)]
+		parallel.Execute(len(chunk), func(s, e int) {
+			PE(&chunk[s]).Exp(*tau, big.NewInt(int64(start+s)))
+			for i := s + 1; i < e; i++ {
+				PE(&chunk[i]).Mul(&chunk[i-1], tau)
+			}
+		})
+		copy(canonical[start:], batchMul(chunk))
+	}
+
+	denominators := make([]E, len(scalars))
+	for start := 0; start < n; start += len(scalars) {
+		chunk := scalars[:min(len(scalars), n-start)]
+		parallel.Execute(len(chunk), func(s, e int) {
+			var w E
+			PE(&w).Exp(*omega, big.NewInt(int64(start+s)))
+			for i := s; i < e; i++ {
+				PE(&chunk[i]).Mul(&w, &c)
+				PE(&denominators[i]).Sub(tau, &w)
+				PE(&w).Mul(&w, omega)
+			}
+			inv := batchInvert(denominators[s:e])
+			for i := s; i < e; i++ {
+				PE(&chunk[i]).Mul(&chunk[i], &inv[i-s])
+			}
+		})
+		copy(lagrange[start:], batchMul(chunk))
+	}
+	return nil
+}